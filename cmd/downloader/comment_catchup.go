@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log/slog"
+	"os"
 	"strings"
 	"time"
 
@@ -20,6 +21,14 @@ const (
 	commentCatchupThrottle = 3 * time.Second
 )
 
+// refreshCommentsEnabled reports whether refresh jobs re-fetch comments
+// (REFRESH_COMMENTS). Off by default: comment extraction can add minutes to a
+// metadata refresh on popular videos.
+func refreshCommentsEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("REFRESH_COMMENTS"))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
 // commentCatchupLoop periodically backfills comments for older videos that have
 // none (e.g. downloaded before comment ingest existed), re-fetching them via
 // yt-dlp. It is deliberately gentle (small batches, throttled, newest-first) to
@@ -81,6 +90,7 @@ func fetchAndIngestComments(ctx context.Context, q *db.Queries, encMgr *encrypti
 
 	client := ytdlp.New()
 	client.Path = "/usr/local/bin/yt-dlp"
+	client.ExtraArgs = ytdlpFFmpegArgs()
 	// Best-effort cookies (needed for age-restricted/private; public works without).
 	if cookies, err := q.GetUserCookies(ctx, archivedBy); err == nil && len(cookies) > 0 {
 		if content := generateCookiesFile(encMgr, cookies); strings.TrimSpace(content) != "" {
@@ -90,10 +100,7 @@ func fetchAndIngestComments(ctx context.Context, q *db.Queries, encMgr *encrypti
 
 	fetchCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
 	defer cancel()
-	info, err := client.GetInfo(fetchCtx, src,
-		"--write-comments",
-		"--extractor-args", "youtube:max_comments=4000,all,all,8",
-	)
+	info, err := client.GetInfo(fetchCtx, src, ytdlp.CommentArgs...)
 	if err != nil {
 		slog.Warn("comment catchup fetch failed", "video_id", uuidString(videoID), "error", err)
 		return
@@ -106,7 +113,7 @@ func fetchAndIngestComments(ctx context.Context, q *db.Queries, encMgr *encrypti
 		source = canon
 	}
 
-	if err := comments.IngestFromInfoJSON(ctx, q, videoID, source, info.Raw); err != nil {
+	if err := comments.IngestFromInfoJSON(ctx, q, videoID, source, info.Raw, comments.Options{}); err != nil {
 		slog.Warn("comment catchup ingest failed", "video_id", uuidString(videoID), "error", err)
 	}
 }
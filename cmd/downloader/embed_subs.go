@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"thirdcoast.systems/rewind/pkg/ytdlp"
+)
+
+// embedSubtitleArgs returns the yt-dlp arguments that embed subtitles in
+// downloaded files (EMBED_SUBTITLES), or nil when disabled. Off by default.
+// EMBED_SUBTITLE_LANGS picks the languages, as a yt-dlp --sub-langs value.
+func embedSubtitleArgs() []string {
+	v := strings.TrimSpace(os.Getenv("EMBED_SUBTITLES"))
+	if v != "1" && !strings.EqualFold(v, "true") && !strings.EqualFold(v, "yes") {
+		return nil
+	}
+	return ytdlp.EmbedSubtitleArgs(os.Getenv("EMBED_SUBTITLE_LANGS"))
+}
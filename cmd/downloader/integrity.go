@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// integrityConfig controls the optional post-download decode check.
+type integrityConfig struct {
+	// mode is "" when the check is disabled.
+	mode        ffmpeg.IntegrityMode
+	tailSeconds float64
+	// maxAttempts is how many download attempts a job gets before a corrupt
+	// result fails it instead of re-queueing it.
+	maxAttempts int32
+}
+
+// errIntegrityExhausted marks a job whose downloads kept failing the
+// integrity check after maxAttempts attempts. It is not retried again.
+var errIntegrityExhausted = errors.New("download failed integrity check")
+
+// downloadIntegrity is set from the environment at startup.
+var downloadIntegrity integrityConfig
+
+// integrityConfigFromEnv reads DOWNLOAD_INTEGRITY_CHECK (off|tail|full),
+// DOWNLOAD_INTEGRITY_TAIL_SECONDS and DOWNLOAD_INTEGRITY_MAX_ATTEMPTS.
+func integrityConfigFromEnv() integrityConfig {
+	cfg := integrityConfig{
+		tailSeconds: 10,
+		maxAttempts: int32(envInt("DOWNLOAD_INTEGRITY_MAX_ATTEMPTS", 3)),
+	}
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("DOWNLOAD_INTEGRITY_CHECK"))) {
+	case "tail", "1", "true", "yes":
+		cfg.mode = ffmpeg.IntegrityTail
+	case "full":
+		cfg.mode = ffmpeg.IntegrityFull
+	}
+	if v, err := strconv.ParseFloat(strings.TrimSpace(os.Getenv("DOWNLOAD_INTEGRITY_TAIL_SECONDS")), 64); err == nil && v > 0 {
+		cfg.tailSeconds = v
+	}
+	if cfg.maxAttempts < 1 {
+		cfg.maxAttempts = 1
+	}
+	return cfg
+}
+
+func (c integrityConfig) enabled() bool {
+	return c.mode != ""
+}
+
+// mediaExtensions are the downloaded file types the integrity check decodes.
+var mediaExtensions = map[string]bool{
+	".mp4": true, ".m4v": true, ".mkv": true, ".webm": true, ".mov": true,
+	".avi": true, ".flv": true, ".ts": true,
+	".m4a": true, ".mp3": true, ".opus": true, ".ogg": true, ".flac": true,
+	".wav": true, ".aac": true,
+}
+
+// verifyDownloadedMedia decodes every media file yt-dlp left in destDir.
+// Returns an error wrapping ffmpeg.ErrCorrupt for the first damaged file.
+func verifyDownloadedMedia(ctx context.Context, destDir string, cfg integrityConfig) error {
+	entries, err := os.ReadDir(destDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !mediaExtensions[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		path := filepath.Join(destDir, e.Name())
+		if err := ffmpeg.VerifyIntegrity(ctx, path, &ffmpeg.IntegrityOptions{Mode: cfg.mode, TailSeconds: cfg.tailSeconds}); err != nil {
+			return fmt.Errorf("%s: %w", e.Name(), err)
+		}
+	}
+	return nil
+}
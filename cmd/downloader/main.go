@@ -21,6 +21,7 @@ import (
 	"thirdcoast.systems/rewind/internal/config"
 	"thirdcoast.systems/rewind/internal/db"
 	"thirdcoast.systems/rewind/pkg/encryption"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
 	"thirdcoast.systems/rewind/pkg/utils/crypto"
 	"thirdcoast.systems/rewind/pkg/ytdlp"
 )
@@ -89,8 +90,17 @@ func persistNetscapeCookies(ctx context.Context, q *db.Queries, encMgr *encrypti
 	normalizedContent = strings.ReplaceAll(normalizedContent, "\r", "\n")
 	lines := strings.Split(normalizedContent, "\n")
 
+	// Cookies yt-dlp refreshed go through the same domain filter as imports,
+	// so a blocked domain cannot slip back in via a download.
+	filter, err := db.LoadCookieDomainFilter(ctx, q, userID)
+	if err != nil {
+		slog.Warn("skipping cookie persist: failed to load cookie domain filter", "user_id", userID, "error", err)
+		return
+	}
+
 	validCount := 0
 	invalidCount := 0
+	droppedCount := 0
 
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
@@ -104,6 +114,10 @@ func persistNetscapeCookies(ctx context.Context, q *db.Queries, encMgr *encrypti
 			invalidCount++
 			continue
 		}
+		if !filter.Keep(parts[0]) {
+			droppedCount++
+			continue
+		}
 		expiration, err := strconv.ParseInt(parts[4], 10, 64)
 		if err != nil {
 			invalidCount++
@@ -132,8 +146,8 @@ func persistNetscapeCookies(ctx context.Context, q *db.Queries, encMgr *encrypti
 		validCount++
 	}
 
-	if validCount > 0 {
-		slog.Info("persisted updated cookies", "user_id", userID, "valid", validCount, "invalid", invalidCount)
+	if validCount > 0 || droppedCount > 0 {
+		slog.Info("persisted updated cookies", "user_id", userID, "valid", validCount, "invalid", invalidCount, "dropped_by_domain", droppedCount)
 	}
 }
 
@@ -161,6 +175,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// ffmpeg verifies finished downloads and yt-dlp uses it to merge formats.
+	if _, err := application.InitFFmpeg(ctx); err != nil {
+		slog.Error("ffmpeg unavailable", "error", err)
+		os.Exit(1)
+	}
+
 	ytdlpUpdateCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 	err = ytdlp.New().Update(ytdlpUpdateCtx)
@@ -192,16 +212,35 @@ func main() {
 	}
 	defer dbc.Close()
 
-	// Recover orphaned jobs stuck in "processing" from previous crashes/restarts
+	// Recover orphaned jobs stuck in "processing" from previous crashes/restarts.
+	// Jobs with attempts left are re-queued after the configured backoff; the
+	// rest are failed.
 	slog.Info("Recovering stuck download jobs from previous service instances")
-	if err := dbc.Queries(ctx).RecoverStuckDownloadJobs(ctx); err != nil {
+	policy := loadDownloadRetryPolicy(ctx, dbc.Queries(ctx))
+	if err := dbc.Queries(ctx).RecoverStuckDownloadJobs(ctx, &db.RecoverStuckDownloadJobsParams{
+		MaxAttempts:        policy.MaxAttempts,
+		BackoffExponential: policy.Exponential(),
+		BaseSeconds:        policy.BaseSeconds,
+		CapSeconds:         policy.CapSeconds,
+	}); err != nil {
 		slog.Error("failed to recover stuck download jobs", "error", err)
 		// Non-fatal - continue startup
 	}
+	if n, err := dbc.Queries(ctx).FailExcessiveRetryDownloadJobs(ctx, policy.MaxAttempts); err != nil {
+		slog.Error("failed to fail excessive retry download jobs", "error", err)
+	} else if n > 0 {
+		slog.Warn("permanently failed download jobs exceeding max retries", "count", n, "max_attempts", policy.MaxAttempts)
+	}
+
+	downloadIntegrity = integrityConfigFromEnv()
+	if downloadIntegrity.enabled() {
+		slog.Info("Download integrity check enabled", "mode", downloadIntegrity.mode, "tail_seconds", downloadIntegrity.tailSeconds, "max_attempts", downloadIntegrity.maxAttempts)
+	}
 
 	workers := envInt("DOWNLOAD_WORKERS", 2)
 	client := ytdlp.New()
 	client.Path = "/usr/local/bin/yt-dlp"
+	client.ExtraArgs = ytdlpFFmpegArgs()
 
 	wake := make(chan struct{}, 1)
 	go listenAndSignal(ctx, conf.DatabaseDSN, "download_jobs", wake)
@@ -218,8 +257,36 @@ func main() {
 	slog.Info("Downloader service stopping")
 }
 
+// dequeueDownloadJob claims the next download job. With a per-user cap the
+// claim runs in a transaction holding the dequeue lock, so concurrent workers
+// see each other's claims when counting a user's processing jobs.
+func dequeueDownloadJob(ctx context.Context, dbc *db.DatabaseConnection, q *db.Queries, maxPerUser int32) (*db.DownloadJob, error) {
+	if maxPerUser <= 0 {
+		return q.DequeueDownloadJob(ctx, maxPerUser)
+	}
+	txq, tx, err := dbc.NewWithTX(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	if err := txq.LockDownloadDequeue(ctx); err != nil {
+		return nil, err
+	}
+	job, err := txq.DequeueDownloadJob(ctx, maxPerUser)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
 func downloadWorker(ctx context.Context, dbc *db.DatabaseConnection, client *ytdlp.Client, spoolDir string, encMgr *encryption.Manager, wake <-chan struct{}) {
 	q := dbc.Queries(ctx)
+	// Cap on one user's concurrently processing jobs across all workers;
+	// DOWNLOAD_MAX_PER_USER unset or 0 means no cap.
+	maxPerUser := int32(envInt("DOWNLOAD_MAX_PER_USER", 0))
 	for {
 		if ctx.Err() != nil {
 			return
@@ -227,7 +294,7 @@ func downloadWorker(ctx context.Context, dbc *db.DatabaseConnection, client *ytd
 
 		// Drain as many jobs as we can
 		for {
-			job, err := q.DequeueDownloadJob(ctx)
+			job, err := dequeueDownloadJob(ctx, dbc, q, maxPerUser)
 			if err != nil {
 				if errors.Is(err, pgx.ErrNoRows) {
 					break
@@ -259,7 +326,17 @@ func downloadWorker(ctx context.Context, dbc *db.DatabaseConnection, client *ytd
 				}
 
 				errMsg := err.Error()
-				_ = q.MarkDownloadJobFailed(ctx, &db.MarkDownloadJobFailedParams{ID: job.ID, LastError: &errMsg})
+				var errorCode *string
+				if code := ytdlp.ClassifyError(err); code != "" {
+					errorCode = &code
+					slog.Info("download failure classified", "job_id", jobID, "error_code", code)
+				} else if !errors.Is(err, errIntegrityExhausted) && retryDownloadJob(ctx, q, job, errMsg) {
+					// Unclassified failures (network errors, throttling) may be
+					// transient; classified ones need the user to act first, and
+					// the integrity check has already used up its own attempts.
+					continue
+				}
+				_ = q.MarkDownloadJobFailed(ctx, &db.MarkDownloadJobFailedParams{ID: job.ID, LastError: &errMsg, ErrorCode: errorCode})
 				continue
 			}
 		}
@@ -348,7 +425,12 @@ func processDownloadJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 	if job.Refresh {
 		infoPath = filepath.Join(destDir, "refresh.info.json")
 		slog.Info("Refreshing metadata", "job_id", jobID, "url", job.URL)
-		if err := client.DumpInfoJSON(ctx, job.URL, infoPath, "--no-playlist"); err != nil {
+		refreshArgs := []string{"--no-playlist"}
+		if refreshCommentsEnabled() {
+			// Re-fetch comments so ingest can merge them into the archive.
+			refreshArgs = append(refreshArgs, ytdlp.CommentArgs...)
+		}
+		if err := client.DumpInfoJSON(ctx, job.URL, infoPath, refreshArgs...); err != nil {
 			return err
 		}
 
@@ -371,6 +453,29 @@ func processDownloadJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 		}
 
 		// Store PID after command starts
+		if client.LastPID > 0 {
+			lastPID := int64(client.LastPID)
+			_ = q.UpdateDownloadJobPID(ctx, &db.UpdateDownloadJobPIDParams{ID: job.ID, ProcessPid: &lastPID})
+		}
+	} else if job.MetadataOnly {
+		slog.Info("Saving metadata only", "job_id", jobID, "url", job.URL)
+		metaArgs := []string{"--no-playlist"}
+		if len(job.ExtraArgs) > 0 {
+			metaArgs = append(metaArgs, job.ExtraArgs...)
+		}
+		if err := client.WriteMetadata(ctx, job.URL, destDir, metaArgs...); err != nil {
+			return err
+		}
+
+		infoMatches, err := filepath.Glob(filepath.Join(destDir, "*.info.json"))
+		if err != nil {
+			return err
+		}
+		if len(infoMatches) == 0 {
+			return errors.New("yt-dlp did not produce .info.json")
+		}
+		infoPath = infoMatches[0]
+
 		if client.LastPID > 0 {
 			lastPID := int64(client.LastPID)
 			_ = q.UpdateDownloadJobPID(ctx, &db.UpdateDownloadJobPIDParams{ID: job.ID, ProcessPid: &lastPID})
@@ -378,10 +483,15 @@ func processDownloadJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 	} else {
 		slog.Info("Downloading", "job_id", jobID, "url", job.URL)
 		downloadArgs := []string{"--no-playlist"}
+		// Before ExtraArgs, so a job's own subtitle options win.
+		downloadArgs = append(downloadArgs, embedSubtitleArgs()...)
 		if len(job.ExtraArgs) > 0 {
 			downloadArgs = append(downloadArgs, job.ExtraArgs...)
 		}
 		if err := client.Download(ctx, job.URL, destDir, downloadArgs...); err != nil {
+			if sel := formatSelectorArg(job.ExtraArgs); sel != "" && ytdlp.IsFormatUnavailable(err) {
+				return fmt.Errorf("source has no format matching %q (e.g. the requested codec is not offered): %w", sel, err)
+			}
 			return err
 		}
 
@@ -399,6 +509,25 @@ func processDownloadJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 			lastPID := int64(client.LastPID)
 			_ = q.UpdateDownloadJobPID(ctx, &db.UpdateDownloadJobPIDParams{ID: job.ID, ProcessPid: &lastPID})
 		}
+
+		// Optional decode check: truncated files often probe fine but play broken.
+		if downloadIntegrity.enabled() {
+			if err := verifyDownloadedMedia(ctx, destDir, downloadIntegrity); err != nil {
+				if !errors.Is(err, ffmpeg.ErrCorrupt) {
+					slog.Warn("integrity check could not run", "job_id", jobID, "error", err)
+				} else {
+					_ = os.RemoveAll(destDir)
+					if job.Attempts < downloadIntegrity.maxAttempts {
+						delay := loadDownloadRetryPolicy(ctx, q).DelaySeconds(job.Attempts)
+						slog.Warn("download failed integrity check, re-queueing", "job_id", jobID, "attempt", job.Attempts, "max_attempts", downloadIntegrity.maxAttempts, "delay_seconds", delay, "error", err)
+						msg := err.Error()
+						_, err := q.ScheduleDownloadJobRetry(ctx, &db.ScheduleDownloadJobRetryParams{ID: job.ID, DelaySeconds: delay, LastError: &msg})
+						return err
+					}
+					return fmt.Errorf("%w after %d attempts: %w", errIntegrityExhausted, job.Attempts, err)
+				}
+			}
+		}
 	}
 
 	if err := q.MarkDownloadJobSucceeded(ctx, &db.MarkDownloadJobSucceededParams{ID: job.ID, SpoolDir: &destDir, InfoJsonPath: &infoPath}); err != nil {
@@ -409,6 +538,49 @@ func processDownloadJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 	return err
 }
 
+// loadDownloadRetryPolicy reads the download retry policy from instance
+// settings, falling back to the default when it cannot be read.
+func loadDownloadRetryPolicy(ctx context.Context, q *db.Queries) db.RetryPolicy {
+	policy, _, err := db.LoadRetryPolicies(ctx, q)
+	if err != nil {
+		slog.Warn("failed to load retry policy, using defaults", "error", err)
+	}
+	return policy
+}
+
+// retryDownloadJob re-queues a failed job after the policy's backoff when it
+// has attempts left, reporting whether it did.
+func retryDownloadJob(ctx context.Context, q *db.Queries, job *db.DownloadJob, errMsg string) bool {
+	if job.Kind == "playlist" {
+		return false
+	}
+	policy := loadDownloadRetryPolicy(ctx, q)
+	if !policy.CanRetry(job.Attempts) {
+		return false
+	}
+	delay := policy.DelaySeconds(job.Attempts)
+	n, err := q.ScheduleDownloadJobRetry(ctx, &db.ScheduleDownloadJobRetryParams{ID: job.ID, DelaySeconds: delay, LastError: &errMsg})
+	if err != nil {
+		slog.Warn("failed to schedule download retry", "job_id", uuidString(job.ID), "error", err)
+		return false
+	}
+	if n == 0 {
+		return false
+	}
+	slog.Info("download job re-queued", "job_id", uuidString(job.ID), "attempt", job.Attempts, "max_attempts", policy.MaxAttempts, "delay_seconds", delay)
+	return true
+}
+
+// formatSelectorArg returns the value passed to -f in args, or "".
+func formatSelectorArg(args []string) string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "-f" {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
 func listenAndSignal(ctx context.Context, dsn string, channel string, signalCh chan<- struct{}) {
 	for {
 		if ctx.Err() != nil {
@@ -478,6 +650,15 @@ func envInt(name string, def int) int {
 	return n
 }
 
+// ytdlpFFmpegArgs points yt-dlp at the configured ffmpeg when FFMPEG_PATH is
+// set; otherwise yt-dlp finds it on PATH itself.
+func ytdlpFFmpegArgs() []string {
+	if strings.TrimSpace(os.Getenv("FFMPEG_PATH")) == "" {
+		return nil
+	}
+	return []string{"--ffmpeg-location", ffmpeg.FFmpegPath()}
+}
+
 func uuidString(u pgtype.UUID) string {
 	if !u.Valid {
 		return ""
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -33,10 +34,19 @@ func processPlaylistJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 	jobID := uuidString(job.ID)
 	slog.Info("Expanding playlist/channel", "job_id", jobID, "url", job.URL)
 
-	entries, err := client.ListPlaylistEntries(ctx, job.URL, "--playlist-end", strconv.Itoa(maxPlaylistEntries))
+	// Per-job args (e.g. request headers) apply to enumeration as well.
+	// --yes-playlist makes a watch URL that also names a playlist (queued with
+	// expand_playlist) enumerate the playlist rather than the one video.
+	listArgs := append([]string{"--yes-playlist", "--playlist-end", strconv.Itoa(maxPlaylistEntries)}, job.ExtraArgs...)
+	entries, err := client.ListPlaylistEntries(ctx, job.URL, listArgs...)
 	if err != nil {
 		return fmt.Errorf("list playlist entries: %w", err)
 	}
+	// A bare channel URL lists the channel's tabs rather than its videos.
+	entries, err = expandChannelTabs(ctx, client, entries, job.ExtraArgs)
+	if err != nil {
+		return fmt.Errorf("list channel tab entries: %w", err)
+	}
 	if len(entries) >= maxPlaylistEntries {
 		slog.Warn("playlist capped", "job_id", jobID, "cap", maxPlaylistEntries, "url", job.URL)
 	}
@@ -49,11 +59,18 @@ func processPlaylistJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 	// playlist itself.
 	urlByID := make(map[string]string, len(entries))
 	candidates := make([]pgtype.UUID, 0, len(entries))
+	nested := 0
 	for _, e := range entries {
 		id := strings.TrimSpace(e.ID)
 		if id == "" {
 			continue
 		}
+		// A channel's tabs or a playlist of playlists list collections, not
+		// videos; a child job for one would fail, so they are skipped.
+		if e.Collection {
+			nested++
+			continue
+		}
 		childURL := childDownloadURL(canonicalDomain, e)
 		if childURL == "" {
 			continue
@@ -69,7 +86,10 @@ func processPlaylistJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 
 	// Drop entries whose video is already archived.
 	if len(candidates) > 0 {
-		existing, err := q.FilterExistingVideoIDs(ctx, candidates)
+		existing, err := q.FilterExistingVideoIDs(ctx, &db.FilterExistingVideoIDsParams{
+			Ids:          candidates,
+			MetadataOnly: job.MetadataOnly,
+		})
 		if err != nil {
 			return fmt.Errorf("filter existing videos: %w", err)
 		}
@@ -86,15 +106,18 @@ func processPlaylistJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 	slog.Info("Playlist expanded",
 		"job_id", jobID,
 		"entries", len(entries),
+		"nested_skipped", nested,
 		"new", len(urls),
 		"already_archived", len(candidates)-len(urls),
 	)
 
 	if len(urls) > 0 {
 		if _, err := q.EnqueueChildDownloadJobs(ctx, &db.EnqueueChildDownloadJobsParams{
-			ArchivedBy:  job.ArchivedBy,
-			ParentJobID: job.ID,
-			Urls:        urls,
+			ArchivedBy:   job.ArchivedBy,
+			ParentJobID:  job.ID,
+			Urls:         urls,
+			ExtraArgs:    nonNilArgs(job.ExtraArgs),
+			MetadataOnly: job.MetadataOnly,
 		}); err != nil {
 			return fmt.Errorf("enqueue child jobs: %w", err)
 		}
@@ -108,6 +131,57 @@ func processPlaylistJob(ctx context.Context, q *db.Queries, client *ytdlp.Client
 	})
 }
 
+// channelVideoTabs are the channel tabs whose entries are videos. Other tabs
+// (playlists, community, ...) list collections or posts and are not followed.
+var channelVideoTabs = []string{"/videos", "/shorts"}
+
+// isChannelVideoTab reports whether a collection entry is a channel's videos
+// or shorts tab.
+func isChannelVideoTab(e ytdlp.FlatEntry) bool {
+	if !e.Collection {
+		return false
+	}
+	u, err := url.Parse(strings.TrimSpace(e.URL))
+	if err != nil {
+		return false
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	for _, tab := range channelVideoTabs {
+		if strings.HasSuffix(path, tab) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandChannelTabs replaces each videos/shorts tab in entries with the tab's
+// own entries, so a bare channel URL archives the channel's uploads rather
+// than skipping its tabs as nested collections. Tabs are followed one level
+// deep and the combined list stays within maxPlaylistEntries.
+func expandChannelTabs(ctx context.Context, client *ytdlp.Client, entries []ytdlp.FlatEntry, extraArgs []string) ([]ytdlp.FlatEntry, error) {
+	out := make([]ytdlp.FlatEntry, 0, len(entries))
+	for _, e := range entries {
+		if !isChannelVideoTab(e) {
+			out = append(out, e)
+			continue
+		}
+		remaining := maxPlaylistEntries - len(out)
+		if remaining <= 0 {
+			break
+		}
+		tabArgs := append([]string{"--playlist-end", strconv.Itoa(remaining)}, extraArgs...)
+		tabEntries, err := client.ListPlaylistEntries(ctx, e.URL, tabArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.URL, err)
+		}
+		out = append(out, tabEntries...)
+	}
+	if len(out) > maxPlaylistEntries {
+		out = out[:maxPlaylistEntries]
+	}
+	return out, nil
+}
+
 // childDownloadURL picks the best URL to enqueue for a flat-playlist entry.
 // yt-dlp usually supplies a full URL; for YouTube we can always reconstruct a
 // canonical watch URL from the id as a fallback.
@@ -120,3 +194,12 @@ func childDownloadURL(domain string, e ytdlp.FlatEntry) string {
 	}
 	return strings.TrimSpace(e.URL)
 }
+
+// nonNilArgs returns args, or an empty slice in place of nil so the NOT NULL
+// extra_args column receives '{}' rather than NULL.
+func nonNilArgs(args []string) []string {
+	if args == nil {
+		return []string{}
+	}
+	return args
+}
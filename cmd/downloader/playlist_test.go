@@ -24,3 +24,22 @@ func TestChildDownloadURL(t *testing.T) {
 		}
 	}
 }
+
+func TestIsChannelVideoTab(t *testing.T) {
+	cases := []struct {
+		name  string
+		entry ytdlp.FlatEntry
+		want  bool
+	}{
+		{"videos tab", ytdlp.FlatEntry{URL: "https://www.youtube.com/@chan/videos", Collection: true}, true},
+		{"shorts tab with trailing slash", ytdlp.FlatEntry{URL: "https://www.youtube.com/channel/UC123/shorts/", Collection: true}, true},
+		{"playlists tab", ytdlp.FlatEntry{URL: "https://www.youtube.com/@chan/playlists", Collection: true}, false},
+		{"nested playlist", ytdlp.FlatEntry{URL: "https://www.youtube.com/playlist?list=PL9", Collection: true}, false},
+		{"video entry", ytdlp.FlatEntry{URL: "https://www.youtube.com/watch?v=videos"}, false},
+	}
+	for _, tc := range cases {
+		if got := isChannelVideoTab(tc.entry); got != tc.want {
+			t.Errorf("%s: isChannelVideoTab(%+v) = %v, want %v", tc.name, tc.entry, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// concatPart is one clip of a concat export: where renderExport writes it and
+// where its encode progress goes.
+type concatPart struct {
+	outputPath string
+	progress   func(pct int)
+}
+
+// processConcatExport renders a concat export. Each clip listed in the spec
+// goes through the single-export pipeline (seek, its own saved filter stack,
+// the export's format and quality) into a temporary part, then the parts are
+// joined with the concat demuxer. Parts that differ in size, frame rate or
+// codecs are re-encoded while joining; otherwise their streams are copied.
+// The output passes the same probe check as a single export.
+func processConcatExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir, workerID string, exportRow *db.FindAndLockPendingClipExportRow) error {
+	exportID := uuidString(exportRow.ID)
+
+	var spec ffmpeg.ExportSpec
+	if err := json.Unmarshal(exportRow.Spec, &spec); err != nil {
+		return fmt.Errorf("failed to parse export spec: %w", err)
+	}
+	if len(spec.Concat) < 2 {
+		return fmt.Errorf("concat export lists %d clips, need at least 2", len(spec.Concat))
+	}
+	slog.Info("processing concat export", "export_id", exportID, "clips", len(spec.Concat))
+
+	exportDir := filepath.Join(exportsDir, "clips", uuidString(exportRow.ClipID))
+	if err := os.MkdirAll(exportDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export dir: %w", err)
+	}
+	videoPreset, audioPreset, ext, _ := ffmpeg.ExportPresetForFormat(exportRow.Format, spec, exportHW)
+	outputPath := filepath.Join(exportDir, exportID+ext)
+	if err := q.UpdateClipExportFilePath(ctx, &db.UpdateClipExportFilePathParams{
+		ID:       exportRow.ID,
+		FilePath: outputPath,
+	}); err != nil {
+		slog.Warn("failed to update export file path", "error", err)
+	}
+
+	// Each part is an equal share of the progress, and joining them is
+	// one more share.
+	shares := len(spec.Concat) + 1
+	report := func(pct int) {
+		_ = q.UpdateClipExportProgress(ctx, &db.UpdateClipExportProgressParams{
+			ID:          exportRow.ID,
+			ProgressPct: int32(pct),
+		})
+	}
+
+	var parts []string
+	defer func() {
+		for _, p := range parts {
+			_ = os.Remove(p)
+		}
+	}()
+	for i, id := range spec.Concat {
+		var clipID pgtype.UUID
+		if err := clipID.Scan(id); err != nil {
+			return fmt.Errorf("invalid clip id %q: %w", id, err)
+		}
+		clipData, err := q.GetClipForExport(ctx, clipID)
+		if err != nil {
+			return fmt.Errorf("failed to get clip %s: %w", id, err)
+		}
+		var filters []ffmpeg.FilterSpec
+		if len(clipData.FilterStack) > 0 {
+			if err := json.Unmarshal(clipData.FilterStack, &filters); err != nil {
+				return fmt.Errorf("clip %s: failed to parse filter stack: %w", id, err)
+			}
+		}
+		partSpec, err := json.Marshal(ffmpeg.ExportSpec{
+			Format:   exportRow.Format,
+			Quality:  spec.Quality,
+			Filters:  filters,
+			Accurate: spec.Accurate,
+		})
+		if err != nil {
+			return err
+		}
+		partRow := *exportRow
+		partRow.ClipID, partRow.Variant, partRow.Spec = clipID, "full", partSpec
+
+		partPath := filepath.Join(exportDir, fmt.Sprintf("%s.part%d%s", exportID, i, ext))
+		parts = append(parts, partPath)
+		done := i * 100
+		if err := renderExport(ctx, q, exportsDir, downloadsDir, workerID, &partRow, &concatPart{
+			outputPath: partPath,
+			progress:   func(pct int) { report((done + pct) / shares) },
+		}); err != nil {
+			return fmt.Errorf("clip %d of %d (%s): %w", i+1, len(spec.Concat), id, err)
+		}
+		report((done + 100) / shares)
+	}
+
+	listPath := filepath.Join(exportDir, exportID+".concat.txt")
+	if err := ffmpeg.WriteConcatList(listPath, parts); err != nil {
+		return fmt.Errorf("failed to write concat list: %w", err)
+	}
+	defer os.Remove(listPath)
+
+	probes := make([]*ffmpeg.ProbeResult, len(parts))
+	for i, p := range parts {
+		probe, err := ffmpeg.Probe(ctx, p)
+		if err != nil {
+			return fmt.Errorf("failed to probe part %d: %w", i+1, err)
+		}
+		probes[i] = probe
+	}
+	var joinErr error
+	if ffmpeg.ConcatCompatible(probes) {
+		joinErr = ffmpeg.ConcatFiles(ctx, listPath, outputPath)
+	} else {
+		// Filters such as crops give parts different sizes, which a stream
+		// copy cannot join; they are conformed to the first part.
+		slog.Info("concat parts differ, re-encoding while joining", "export_id", exportID)
+		opts := ffmpeg.Flatten(videoPreset)
+		if audioPreset != nil {
+			opts = append(opts, ffmpeg.Flatten(audioPreset)...)
+		}
+		opts = append(opts, ffmpeg.ConcatInput(), ffmpeg.Filter(ffmpeg.ConformFilter(probes[0].Width, probes[0].Height, probes[0].FPS)))
+		joinErr = ffmpeg.RunCapture(ctx, listPath, outputPath, opts...).Err
+	}
+	if joinErr != nil {
+		_ = os.Remove(outputPath)
+		return fmt.Errorf("ffmpeg concat failed: %w", joinErr)
+	}
+
+	st, err := os.Stat(outputPath)
+	if err != nil {
+		return fmt.Errorf("output file missing: %w", err)
+	}
+	probe, err := ffmpeg.Probe(ctx, outputPath)
+	if err != nil {
+		_ = os.Remove(outputPath)
+		return fmt.Errorf("output validation failed (ffprobe): %w", err)
+	}
+	if probe.Duration < 0.5 {
+		_ = os.Remove(outputPath)
+		return fmt.Errorf("output validation failed: duration too short (%.2fs)", probe.Duration)
+	}
+
+	if err := q.FinishClipExportReady(ctx, &db.FinishClipExportReadyParams{
+		ID:        exportRow.ID,
+		FilePath:  outputPath,
+		SizeBytes: st.Size(),
+	}); err != nil {
+		return fmt.Errorf("failed to mark export ready: %w", err)
+	}
+	slog.Info("concat export complete", "export_id", exportID, "clips", len(spec.Concat), "size_bytes", st.Size())
+	return nil
+}
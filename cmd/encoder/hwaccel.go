@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// exportHW is the GPU encoder clip exports use, or ffmpeg.HardwareNone for
+// libx264. Set once at startup by initHardwareEncoder.
+var exportHW ffmpeg.HardwareEncoder
+
+// initHardwareEncoder reads ENCODER_HWACCEL and test-encodes with the chosen
+// encoder. An unknown value or a failed probe (missing driver, no device in
+// the container) logs a warning and falls back to software encoding.
+func initHardwareEncoder(ctx context.Context) ffmpeg.HardwareEncoder {
+	hw, err := ffmpeg.HardwareEncoderFromEnv()
+	if err != nil {
+		slog.Warn("invalid ENCODER_HWACCEL, using software encoding", "error", err)
+		return ffmpeg.HardwareNone
+	}
+	if hw == ffmpeg.HardwareNone {
+		return ffmpeg.HardwareNone
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := ffmpeg.ProbeHardwareEncoder(probeCtx, hw); err != nil {
+		slog.Warn("hardware encoder unavailable, using software encoding", "encoder", hw.Codec(), "error", err)
+		return ffmpeg.HardwareNone
+	}
+	slog.Info("hardware encoding enabled", "encoder", hw.Codec())
+	return hw
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/utils/crops"
+)
+
+// measureLoudness runs the first pass of a two-pass loudnorm: the clip's
+// audio, cut with seek, goes through the export's filters to a null output
+// while the normalize filter prints its statistics. Filters ahead of the
+// normalize filter change what it measures, so the whole stack is applied.
+func measureLoudness(ctx context.Context, inputPath string, seek ffmpeg.Option, specs []ffmpeg.FilterSpec, clipCrops crops.CropArray) (ffmpeg.LoudnormStats, error) {
+	filterOpts, _, err := ffmpeg.CompileFiltersForSource(ffmpeg.WithLoudnormMeasurement(specs), clipCrops, true)
+	if err != nil {
+		return ffmpeg.LoudnormStats{}, err
+	}
+	opts := append([]ffmpeg.Option{seek, ffmpeg.NoVideo, ffmpeg.ExtraArgs("-f", "null")}, filterOpts...)
+	res := ffmpeg.RunCapture(ctx, inputPath, "-", opts...)
+	if res.Err != nil {
+		return ffmpeg.LoudnormStats{}, fmt.Errorf("ffmpeg: %w", res.Err)
+	}
+	return ffmpeg.ParseLoudnormStats(res.Logs)
+}
@@ -40,6 +40,16 @@ func main() {
 		conf.DatabaseRetries = 10
 	}
 
+	// The filter compiler emits colortemperature, drawtext and loudnorm.
+	// The subtitles and vidstab filters are optional.
+	if _, err := application.InitFFmpeg(ctx, "colortemperature", "drawtext", "loudnorm"); err != nil {
+		slog.Error("ffmpeg unavailable", "error", err)
+		os.Exit(1)
+	}
+	exportHW = initHardwareEncoder(ctx)
+	exportLibass = initLibass(ctx)
+	exportVidstab = initVidstab(ctx)
+
 	exportsDir := strings.TrimSpace(os.Getenv("EXPORTS_DIR"))
 	if exportsDir == "" {
 		exportsDir = "/exports"
@@ -126,7 +136,11 @@ func encoderWorker(ctx context.Context, dbc *db.DatabaseConnection, exportsDir,
 				break
 			}
 
-			if err := processExport(ctx, q, exportsDir, downloadsDir, exportRow); err != nil {
+			process := processExport
+			if strings.HasPrefix(exportRow.Variant, "concat:") {
+				process = processConcatExport
+			}
+			if err := process(ctx, q, exportsDir, downloadsDir, workerID, exportRow); err != nil {
 				exportID := uuidString(exportRow.ID)
 				slog.Error("export failed", "export_id", exportID, "error", err)
 				errMsg := err.Error()
@@ -134,6 +148,11 @@ func encoderWorker(ctx context.Context, dbc *db.DatabaseConnection, exportsDir,
 					ID:        exportRow.ID,
 					LastError: &errMsg,
 				})
+				// Rungs of a ladder share the lead's encode and fail with it
+				_ = q.FinishClipExportRungsError(ctx, &db.FinishClipExportRungsErrorParams{
+					LadderParentID: exportRow.ID,
+					LastError:      &errMsg,
+				})
 				continue
 			}
 		}
@@ -149,7 +168,14 @@ func encoderWorker(ctx context.Context, dbc *db.DatabaseConnection, exportsDir,
 	}
 }
 
-func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir string, exportRow *db.FindAndLockPendingClipExportRow) error {
+func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir, workerID string, exportRow *db.FindAndLockPendingClipExportRow) error {
+	return renderExport(ctx, q, exportsDir, downloadsDir, workerID, exportRow, nil)
+}
+
+// renderExport encodes exportRow's clip. With part set it renders one clip
+// of a concat export to part.outputPath and reports progress to it, leaving
+// the export row's file and status to processConcatExport.
+func renderExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir, workerID string, exportRow *db.FindAndLockPendingClipExportRow, part *concatPart) error {
 	exportID := uuidString(exportRow.ID)
 	clipID := uuidString(exportRow.ClipID)
 
@@ -176,19 +202,45 @@ func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 	}
 
 	// Determine codec presets and file extension based on format
-	var specQuality string
+	var preset ffmpeg.ExportSpec
 	if len(exportRow.Spec) > 0 {
 		var specPeek struct {
-			Quality string `json:"quality"`
+			Quality       string `json:"quality"`
+			GOP           int    `json:"gop"`
+			TargetBitrate int    `json:"target_bitrate"`
+			FPS           int    `json:"fps"`
+			MaxWidth      int    `json:"max_width"`
+			Ladder        []int  `json:"ladder"`
+			Accurate      bool   `json:"accurate"`
 		}
 		_ = json.Unmarshal(exportRow.Spec, &specPeek)
-		specQuality = specPeek.Quality
+		preset.Quality = specPeek.Quality
+		preset.FPS = specPeek.FPS
+		preset.MaxWidth = specPeek.MaxWidth
+		preset.Accurate = specPeek.Accurate
+		if specPeek.GOP > 0 && specPeek.GOP <= ffmpeg.MaxExportGOP {
+			preset.GOP = specPeek.GOP
+		}
+		// A ladder encodes every rung from one decode, which two passes
+		// cannot share, so a ladder lead always encodes in one pass.
+		if specPeek.TargetBitrate >= ffmpeg.MinExportBitrate && specPeek.TargetBitrate <= ffmpeg.MaxExportBitrate && len(specPeek.Ladder) == 0 {
+			preset.TargetBitrate = specPeek.TargetBitrate
+		}
+	}
+	videoPreset, audioPreset, ext, twoPass := ffmpeg.ExportPresetForFormat(exportRow.Format, preset, exportHW)
+	isImage := ffmpeg.IsAnimatedImageFormat(exportRow.Format)
+	isAudio := ffmpeg.IsAudioFormat(exportRow.Format)
+	if isAudio {
+		if probe, err := ffmpeg.Probe(ctx, inputPath); err == nil && probe.AudioStreams == 0 {
+			return fmt.Errorf("source video has no audio to export")
+		}
 	}
-	videoPreset, audioPreset, ext := ffmpeg.ExportPresetForFormat(exportRow.Format, specQuality)
 	outputPath := filepath.Join(clipExportDir, exportID+ext)
 
 	// Update file path in DB
-	if err := q.UpdateClipExportFilePath(ctx, &db.UpdateClipExportFilePathParams{
+	if part != nil {
+		outputPath = part.outputPath
+	} else if err := q.UpdateClipExportFilePath(ctx, &db.UpdateClipExportFilePathParams{
 		ID:       exportRow.ID,
 		FilePath: outputPath,
 	}); err != nil {
@@ -198,6 +250,18 @@ func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 	start := time.Duration(clipData.StartTs * float64(time.Second))
 	end := time.Duration((clipData.StartTs + clipData.Duration) * float64(time.Second))
 
+	// Fast input seeking can start the cut on a nearby keyframe; an accurate
+	// export decodes from before the start and trims to the exact frame.
+	// The audio trim fails on a silent source, like audio filters.
+	seek := ffmpeg.SeekTo(start, end)
+	if preset.Accurate {
+		hasAudio := !isImage
+		if probe, err := ffmpeg.Probe(ctx, inputPath); err == nil && hasAudio {
+			hasAudio = probe.AudioStreams > 0
+		}
+		seek = ffmpeg.AccurateSeekTo(start, end, hasAudio)
+	}
+
 	// Build options using format-aware codec presets
 	opts := ffmpeg.Flatten(videoPreset)
 	if audioPreset != nil {
@@ -218,8 +282,9 @@ func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 		opts = append(opts, ffmpeg.Metadata("rewind_filter_stack", string(clipData.FilterStack)))
 	}
 
-	// Embed crop info as metadata when a crop variant is used
-	if strings.HasPrefix(exportRow.Variant, "crop:") {
+	// Embed crop info as metadata when a crop variant is used; audio-only
+	// exports have no picture to crop
+	if strings.HasPrefix(exportRow.Variant, "crop:") && !isAudio {
 		cropID := strings.TrimPrefix(exportRow.Variant, "crop:")
 		for _, cr := range clipData.Crops {
 			if cr.ID == cropID {
@@ -235,22 +300,103 @@ func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 
 	// Apply filters: spec-based pipeline takes precedence, otherwise fall back to legacy crop variant
 	var specApplied bool
+	var pip *ffmpeg.PiPSpec
+	loops := 1
+	height := 0
+	isLadderLead := false
 	if len(exportRow.Spec) > 0 {
 		var spec ffmpeg.ExportSpec
 		if err := json.Unmarshal(exportRow.Spec, &spec); err != nil {
 			slog.Warn("failed to parse export spec, falling back to variant", "error", err)
-		} else if len(spec.Filters) > 0 {
-			filterOpts, filterErr := ffmpeg.CompileFilters(spec.Filters, clipData.Crops)
-			if filterErr != nil {
-				slog.Warn("failed to compile filter spec, falling back to variant", "error", filterErr)
-			} else {
-				opts = append(opts, filterOpts...)
-				specApplied = true
+		} else {
+			if !isImage {
+				// GIF and WebP loop by themselves
+				loops = spec.LoopCount()
+			}
+			pip = spec.PiP
+			height = spec.Height
+			isLadderLead = len(spec.Ladder) > 0
+			if len(spec.Filters) > 0 {
+				// Audio filters fail on a silent source, so they are dropped
+				// when the probe finds no audio stream, and always for
+				// animated images, which have no audio.
+				hasAudio := !isImage
+				var sourceFPS float64
+				if probe, err := ffmpeg.Probe(ctx, inputPath); err == nil {
+					hasAudio = hasAudio && probe.AudioStreams > 0
+					sourceFPS = probe.FPS
+				}
+				// Overlay images and LUT files belong to the user who asked
+				// for the export; a missing one fails the export rather than
+				// silently dropping the effect. Params starting with "_" are
+				// only ever set here, never taken from the stored stack.
+				userID := uuidString(exportRow.CreatedBy)
+				specs, err := ffmpeg.ResolveOverlayImages(ffmpeg.StripInjectedParams(spec.Filters), ffmpeg.OverlayImageDir(exportsDir, userID))
+				if err != nil {
+					return err
+				}
+				if specs, err = ffmpeg.ResolveLUTFiles(specs, ffmpeg.LUTFileDir(exportsDir, userID)); err != nil {
+					return err
+				}
+				// Burned-in subtitles come from the clip's video; a video
+				// without the requested captions, or an ffmpeg without
+				// libass, fails the export instead of exporting without them.
+				if hasSubtitlesFilter(specs) && !exportLibass {
+					return fmt.Errorf("burning in subtitles needs an ffmpeg built with libass")
+				}
+				if specs, err = ffmpeg.ResolveSubtitleFiles(specs, func(lang string) (string, bool) {
+					path, _, ok := findCanonicalCaptionFilePath(videoDir, videoID, lang)
+					return path, ok
+				}); err != nil {
+					return err
+				}
+				if names := ffmpeg.LUTFileNames(specs); len(names) > 0 && !isAudio {
+					opts = append(opts, ffmpeg.Metadata("rewind_lut", strings.Join(names, ", ")))
+				}
+				specs = ffmpeg.WithClipStart(ffmpeg.WithClipDuration(specs, clipData.Duration), clipData.StartTs)
+				specs = ffmpeg.WithSourceFPS(specs, sourceFPS)
+				// A two-pass loudnorm measures the clip's audio first; if
+				// that fails it normalizes in one pass.
+				if hasAudio && ffmpeg.NeedsLoudnormMeasurement(specs) {
+					stats, err := measureLoudness(ctx, inputPath, seek, specs, clipData.Crops)
+					if err != nil {
+						slog.Warn("loudness measurement failed, normalizing in one pass", "export_id", exportID, "error", err)
+					} else {
+						slog.Info("measured loudness", "export_id", exportID, "input_i", stats.InputI, "input_tp", stats.InputTP, "input_lra", stats.InputLRA)
+						specs = ffmpeg.WithLoudnormStats(specs, stats)
+					}
+				}
+				// Stabilization detects the camera motion in a first pass,
+				// writing the transforms next to the export for the encode to
+				// apply. Without libvidstab the filter is skipped.
+				if ffmpeg.NeedsStabilizeDetection(specs) && !isAudio {
+					if !exportVidstab {
+						slog.Warn("skipping stabilize filter: ffmpeg lacks libvidstab", "export_id", exportID)
+					} else {
+						trfPath := filepath.Join(clipExportDir, exportID+".trf")
+						defer os.Remove(trfPath)
+						slog.Info("detecting camera motion", "export_id", exportID)
+						if err := detectMotion(ctx, inputPath, seek, specs, clipData.Crops, trfPath); err != nil {
+							return fmt.Errorf("stabilize motion detection failed: %w", err)
+						}
+						specs = ffmpeg.WithStabilizeTransforms(specs, trfPath)
+					}
+				}
+				filterOpts, skipped, filterErr := ffmpeg.CompileFiltersForSource(specs, clipData.Crops, hasAudio)
+				for _, f := range skipped {
+					slog.Warn("skipping audio filter: source has no audio", "export_id", exportID, "filter", f)
+				}
+				if filterErr != nil {
+					slog.Warn("failed to compile filter spec, falling back to variant", "error", filterErr)
+				} else {
+					opts = append(opts, filterOpts...)
+					specApplied = true
+				}
 			}
 		}
 	}
 
-	if !specApplied {
+	if !specApplied && !isAudio {
 		// Legacy crop variant handling
 		variant := exportRow.Variant
 		if strings.HasPrefix(variant, "crop:") {
@@ -263,57 +409,150 @@ func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 		}
 	}
 
-	// Progress channel
-	progressChan := make(chan ffmpeg.Progress, 100)
+	// Inset a second video (e.g. a signer) over the main one
+	if pip != nil {
+		pipOpt, err := pipOption(downloadsDir, *pip, clipData.StartTs, clipData.Duration)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, pipOpt, ffmpeg.Metadata("rewind_pip", pip.VideoID))
+	}
 
-	// Build command with seek + duration
-	allOpts := append([]ffmpeg.Option{ffmpeg.SeekTo(start, end)}, opts...)
-	cmd := ffmpeg.NewCommand(inputPath, outputPath, allOpts...)
+	// A ladder lead encodes its pending rungs in the same pass
+	var rungs []ladderRung
+	if isLadderLead {
+		rungs, err = claimLadderRungs(ctx, q, workerID, exportRow.ID, clipExportDir, ext)
+		if err != nil {
+			return err
+		}
+	}
+	if len(rungs) > 0 {
+		renditions := []ffmpeg.Rendition{{Height: height, Output: outputPath}}
+		for _, r := range rungs {
+			renditions = append(renditions, ffmpeg.Rendition{Height: r.height, Output: r.outputPath})
+		}
+		opts = append(opts, ffmpeg.RenditionLadder(renditions))
+		loops = 1
+		slog.Info("encoding rendition ladder", "export_id", exportID, "rungs", len(renditions))
+	} else if height > 0 {
+		opts = append(opts, ffmpeg.Filter(ffmpeg.RenditionScaleFilter(height)))
+	}
 
-	// Start with progress tracking
-	proc, err := cmd.StartWithProgress(ctx, progressChan)
-	if err != nil {
-		return fmt.Errorf("failed to start ffmpeg: %w", err)
+	// Looped exports cut and encode a single play to an intermediate file
+	// first, then repeat that file with a stream copy. Seeking the source
+	// only once keeps audio and video aligned across every repetition.
+	encodePath := outputPath
+	if loops > 1 {
+		encodePath = filepath.Join(clipExportDir, exportID+".cut"+ext)
+		defer os.Remove(encodePath)
 	}
 
-	// Store PID for potential cleanup if we crash
-	pid := int32(proc.PID())
-	if err := q.UpdateClipExportPID(ctx, &db.UpdateClipExportPIDParams{
-		ID:  exportRow.ID,
-		Pid: &pid,
-	}); err != nil {
-		slog.Warn("failed to store ffmpeg PID", "error", err, "pid", pid)
+	// A two-pass encode runs the same command twice: an analysis pass that
+	// writes rate-control statistics next to the export, then the real
+	// encode. The statistics are removed however the export ends.
+	passes := [][]ffmpeg.Option{nil}
+	if twoPass {
+		passLog := filepath.Join(clipExportDir, exportID+".passlog")
+		defer removePassLogs(passLog)
+		passes = [][]ffmpeg.Option{{ffmpeg.FirstPass(passLog)}, {ffmpeg.SecondPass(passLog)}}
+		slog.Info("encoding in two passes", "export_id", exportID, "target_kbps", preset.TargetBitrate)
+	}
+
+	var history *progressHistory
+	if progressHistoryEnabled() && part == nil {
+		history = newProgressHistory(time.Now())
 	}
 
-	// Process progress updates
 	lastPct := -1
 	lastUpdate := time.Time{}
-	for progress := range progressChan {
-		if clipData.Duration <= 0 {
-			continue
+	for i, passOpts := range passes {
+		passOutput := encodePath
+		if len(passes) > 1 && i == 0 {
+			passOutput = os.DevNull
 		}
-		pct := int((float64(progress.OutTimeMS()) / (clipData.Duration * 1000)) * 100)
-		if pct < 0 {
-			pct = 0
+
+		// Build command with seek + duration
+		allOpts := append([]ffmpeg.Option{seek}, opts...)
+		allOpts = append(allOpts, passOpts...)
+		cmd := ffmpeg.NewCommand(inputPath, passOutput, allOpts...)
+
+		// Progress channel
+		progressChan := make(chan ffmpeg.Progress, 100)
+
+		// Start with progress tracking
+		proc, err := cmd.StartWithProgress(ctx, progressChan)
+		if err != nil {
+			return fmt.Errorf("failed to start ffmpeg: %w", err)
+		}
+
+		// Store PID for potential cleanup if we crash
+		pid := int32(proc.PID())
+		if err := q.UpdateClipExportPID(ctx, &db.UpdateClipExportPIDParams{
+			ID:  exportRow.ID,
+			Pid: &pid,
+		}); err != nil {
+			slog.Warn("failed to store ffmpeg PID", "error", err, "pid", pid)
 		}
-		if pct > 99 {
-			pct = 99
+
+		// Process progress updates; each pass covers an equal share
+		for progress := range progressChan {
+			if clipData.Duration <= 0 {
+				continue
+			}
+			pct := int((float64(progress.OutTimeMS()) / (clipData.Duration * 1000)) * 100)
+			if pct < 0 {
+				pct = 0
+			}
+			if pct > 99 {
+				pct = 99
+			}
+			pct = (i*100 + pct) / len(passes)
+			now := time.Now()
+			if history != nil {
+				history.add(now, pct, progress)
+			}
+			if pct != lastPct && now.Sub(lastUpdate) > time.Second {
+				lastPct = pct
+				lastUpdate = now
+				if part != nil {
+					part.progress(pct)
+				} else if len(rungs) > 0 {
+					// One decode drives every rendition, so progress is shared
+					_ = q.UpdateClipExportLadderProgress(ctx, &db.UpdateClipExportLadderProgressParams{
+						ID:          exportRow.ID,
+						ProgressPct: int32(pct),
+					})
+				} else {
+					_ = q.UpdateClipExportProgress(ctx, &db.UpdateClipExportProgressParams{
+						ID:          exportRow.ID,
+						ProgressPct: int32(pct),
+					})
+				}
+			}
 		}
-		now := time.Now()
-		if pct != lastPct && now.Sub(lastUpdate) > time.Second {
-			lastPct = pct
-			lastUpdate = now
-			_ = q.UpdateClipExportProgress(ctx, &db.UpdateClipExportProgressParams{
-				ID:          exportRow.ID,
-				ProgressPct: int32(pct),
-			})
+
+		// Wait for completion
+		if err := proc.Wait(); err != nil {
+			_ = os.Remove(encodePath)
+			for _, r := range rungs {
+				_ = os.Remove(r.outputPath)
+			}
+			if len(passes) > 1 {
+				return fmt.Errorf("ffmpeg pass %d failed: %w", i+1, err)
+			}
+			return fmt.Errorf("ffmpeg failed: %w", err)
 		}
 	}
 
-	// Wait for completion
-	if err := proc.Wait(); err != nil {
-		_ = os.Remove(outputPath)
-		return fmt.Errorf("ffmpeg failed: %w", err)
+	for _, r := range rungs {
+		finishLadderRung(ctx, q, r)
+	}
+
+	if loops > 1 {
+		if err := ffmpeg.LoopFile(ctx, encodePath, outputPath, loops); err != nil {
+			_ = os.Remove(outputPath)
+			return fmt.Errorf("ffmpeg loop failed: %w", err)
+		}
 	}
 
 	// Verify output exists
@@ -322,15 +561,32 @@ func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 		return fmt.Errorf("output file missing: %w", err)
 	}
 
-	// Validate output is a playable media file
-	probe, probeErr := ffmpeg.Probe(ctx, outputPath)
-	if probeErr != nil {
-		_ = os.Remove(outputPath)
-		return fmt.Errorf("output validation failed (ffprobe): %w", probeErr)
+	// Validate output is a playable media file. Animated images have no
+	// duration ffprobe can rely on, so only their container is checked.
+	if isImage {
+		if err := ffmpeg.CheckAnimatedImage(outputPath); err != nil {
+			_ = os.Remove(outputPath)
+			return fmt.Errorf("output validation failed: %w", err)
+		}
+	} else {
+		probe, probeErr := ffmpeg.Probe(ctx, outputPath)
+		if probeErr != nil {
+			_ = os.Remove(outputPath)
+			return fmt.Errorf("output validation failed (ffprobe): %w", probeErr)
+		}
+		if probe.Duration < 0.5 {
+			_ = os.Remove(outputPath)
+			return fmt.Errorf("output validation failed: duration too short (%.2fs)", probe.Duration)
+		}
+		// Audio-only exports have no video stream to check, but must
+		// carry the audio
+		if isAudio && probe.AudioStreams == 0 {
+			_ = os.Remove(outputPath)
+			return fmt.Errorf("output validation failed: no audio stream")
+		}
 	}
-	if probe.Duration < 0.5 {
-		_ = os.Remove(outputPath)
-		return fmt.Errorf("output validation failed: duration too short (%.2fs)", probe.Duration)
+	if part != nil {
+		return nil
 	}
 
 	// Mark ready
@@ -342,10 +598,136 @@ func processExport(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 		return fmt.Errorf("failed to mark export ready: %w", err)
 	}
 
+	if history != nil {
+		history.finish(time.Now())
+		if b, err := history.marshal(); err != nil {
+			slog.Warn("failed to marshal export progress history", "export_id", exportID, "error", err)
+		} else if err := q.SetClipExportProgressHistory(ctx, &db.SetClipExportProgressHistoryParams{
+			ID:              exportRow.ID,
+			ProgressHistory: b,
+		}); err != nil {
+			slog.Warn("failed to store export progress history", "export_id", exportID, "error", err)
+		}
+	}
+
 	slog.Info("export complete", "export_id", exportID, "clip_id", clipID, "size_bytes", st.Size())
 	return nil
 }
 
+// ladderRung is a rendition ladder rung claimed by its lead's worker.
+type ladderRung struct {
+	id         pgtype.UUID
+	height     int
+	outputPath string
+}
+
+// claimLadderRungs marks a lead's pending rungs as processing and records
+// each one's output path next to the lead's.
+func claimLadderRungs(ctx context.Context, q *db.Queries, workerID string, leadID pgtype.UUID, dir, ext string) ([]ladderRung, error) {
+	rows, err := q.ClaimClipExportRungs(ctx, &db.ClaimClipExportRungsParams{
+		LockedBy:       &workerID,
+		LadderParentID: leadID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim ladder rungs: %w", err)
+	}
+	rungs := make([]ladderRung, 0, len(rows))
+	for _, row := range rows {
+		var spec ffmpeg.ExportSpec
+		if err := json.Unmarshal(row.Spec, &spec); err != nil || spec.Height <= 0 {
+			errMsg := "invalid ladder rung spec"
+			_ = q.FinishClipExportError(ctx, &db.FinishClipExportErrorParams{ID: row.ID, LastError: &errMsg})
+			continue
+		}
+		r := ladderRung{
+			id:         row.ID,
+			height:     spec.Height,
+			outputPath: filepath.Join(dir, uuidString(row.ID)+ext),
+		}
+		if err := q.UpdateClipExportFilePath(ctx, &db.UpdateClipExportFilePathParams{
+			ID:       r.id,
+			FilePath: r.outputPath,
+		}); err != nil {
+			slog.Warn("failed to update export file path", "error", err)
+		}
+		rungs = append(rungs, r)
+	}
+	return rungs, nil
+}
+
+// finishLadderRung validates a rung's output and marks it ready, or failed
+// on its own without affecting the rest of the ladder.
+func finishLadderRung(ctx context.Context, q *db.Queries, r ladderRung) {
+	fail := func(err error) {
+		_ = os.Remove(r.outputPath)
+		errMsg := err.Error()
+		slog.Error("ladder rung failed", "export_id", uuidString(r.id), "error", err)
+		_ = q.FinishClipExportError(ctx, &db.FinishClipExportErrorParams{ID: r.id, LastError: &errMsg})
+	}
+	st, err := os.Stat(r.outputPath)
+	if err != nil {
+		fail(fmt.Errorf("output file missing: %w", err))
+		return
+	}
+	probe, err := ffmpeg.Probe(ctx, r.outputPath)
+	if err != nil {
+		fail(fmt.Errorf("output validation failed (ffprobe): %w", err))
+		return
+	}
+	if probe.Duration < 0.5 {
+		fail(fmt.Errorf("output validation failed: duration too short (%.2fs)", probe.Duration))
+		return
+	}
+	if err := q.FinishClipExportReady(ctx, &db.FinishClipExportReadyParams{
+		ID:        r.id,
+		FilePath:  r.outputPath,
+		SizeBytes: st.Size(),
+	}); err != nil {
+		slog.Error("failed to mark ladder rung ready", "export_id", uuidString(r.id), "error", err)
+		return
+	}
+	slog.Info("export complete", "export_id", uuidString(r.id), "height", r.height, "size_bytes", st.Size())
+}
+
+// pipOption resolves the inset video of a PiP export and returns the option
+// that adds it. Without an explicit start the inset plays from the clip's own
+// start time, as for a signer recorded alongside the main video. Either way
+// only the clip's length of the inset is read, the same range the main video
+// is cut to.
+func pipOption(downloadsDir string, pip ffmpeg.PiPSpec, clipStart, clipDuration float64) (ffmpeg.Option, error) {
+	if err := pip.Validate(); err != nil {
+		return nil, err
+	}
+	var id pgtype.UUID
+	if err := id.Scan(pip.VideoID); err != nil {
+		return nil, fmt.Errorf("invalid pip video id %q", pip.VideoID)
+	}
+	pipID := uuidString(id)
+	pipDir := filepath.Join(downloadsDir, pipID)
+	pipPath := findVideoFile(pipDir, pipID)
+	if pipPath == "" {
+		return nil, fmt.Errorf("pip video file not found in %s", pipDir)
+	}
+	start := clipStart
+	if pip.Start != nil {
+		start = *pip.Start
+	}
+	return ffmpeg.PictureInPicture(pipPath,
+		time.Duration(start*float64(time.Second)),
+		time.Duration(clipDuration*float64(time.Second)),
+		pip), nil
+}
+
+// removePassLogs deletes the statistics files of a two-pass encode. Encoders
+// add their own suffixes to the -passlogfile prefix (x264 writes
+// <prefix>-0.log and <prefix>-0.log.mbtree), so every file under it goes.
+func removePassLogs(prefix string) {
+	matches, _ := filepath.Glob(prefix + "*")
+	for _, m := range matches {
+		_ = os.Remove(m)
+	}
+}
+
 var videoExtensions = []string{".webm", ".mp4", ".mkv", ".mov", ".avi"}
 
 func findVideoFile(dir, videoID string) string {
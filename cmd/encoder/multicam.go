@@ -73,7 +73,7 @@ func processMulticam(ctx context.Context, q *db.Queries, exportsDir, downloadsDi
 		return fmt.Errorf("failed to create export dir: %w", err)
 	}
 
-	videoPreset, audioPreset, ext := ffmpeg.ExportPresetForFormat(jobRow.Format, jobRow.Quality)
+	videoPreset, audioPreset, ext, _ := ffmpeg.ExportPresetForFormat(jobRow.Format, ffmpeg.ExportSpec{Quality: jobRow.Quality}, ffmpeg.HardwareNone)
 	outputPath := filepath.Join(stitchExportDir, jobID+ext)
 
 	codecOpts := ffmpeg.Flatten(videoPreset)
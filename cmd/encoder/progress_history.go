@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// maxProgressSamples bounds the stored timeline regardless of export length.
+const maxProgressSamples = 120
+
+// progressHistoryEnabled reports whether the encoder records a progress
+// timeline for each export. Off by default to keep export rows small.
+func progressHistoryEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("EXPORT_PROGRESS_HISTORY"))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// progressSample is one point of an export's encode timeline. Keys are kept
+// short because the timeline is stored as JSON on the export row.
+type progressSample struct {
+	T     float64 `json:"t"`     // seconds since the encode started
+	Pct   int     `json:"pct"`   // percent of the clip encoded
+	Speed float64 `json:"speed"` // ffmpeg speed multiplier (1.0 = realtime)
+}
+
+// progressHistory collects a downsampled timeline of ffmpeg progress updates.
+// Samples are taken at most once per interval; when the buffer fills, every
+// other sample is dropped and the interval doubles, so the timeline always
+// spans the whole encode in at most maxProgressSamples points.
+type progressHistory struct {
+	start    time.Time
+	interval time.Duration
+	last     time.Duration
+	samples  []progressSample
+}
+
+func newProgressHistory(start time.Time) *progressHistory {
+	return &progressHistory{
+		start:    start,
+		interval: time.Second,
+		last:     -time.Second,
+		samples:  make([]progressSample, 0, maxProgressSamples),
+	}
+}
+
+// add records p at time now unless a sample was taken within the current
+// interval.
+func (h *progressHistory) add(now time.Time, pct int, p ffmpeg.Progress) {
+	elapsed := now.Sub(h.start)
+	if elapsed-h.last < h.interval {
+		return
+	}
+	if len(h.samples) == maxProgressSamples {
+		h.compact()
+	}
+	h.last = elapsed
+	h.samples = append(h.samples, progressSample{
+		T:     math.Round(elapsed.Seconds()*10) / 10,
+		Pct:   pct,
+		Speed: math.Round(p.SpeedMultiplier()*100) / 100,
+	})
+}
+
+// finish appends a final 100% sample so the timeline ends at completion.
+func (h *progressHistory) finish(now time.Time) {
+	if len(h.samples) == maxProgressSamples {
+		h.compact()
+	}
+	speed := 0.0
+	if n := len(h.samples); n > 0 {
+		speed = h.samples[n-1].Speed
+	}
+	h.samples = append(h.samples, progressSample{
+		T:     math.Round(now.Sub(h.start).Seconds()*10) / 10,
+		Pct:   100,
+		Speed: speed,
+	})
+}
+
+func (h *progressHistory) compact() {
+	kept := h.samples[:0]
+	for i, s := range h.samples {
+		if i%2 == 0 {
+			kept = append(kept, s)
+		}
+	}
+	h.samples = kept
+	h.interval *= 2
+}
+
+func (h *progressHistory) marshal() ([]byte, error) {
+	return json.Marshal(h.samples)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+func TestProgressHistory_ThrottlesToInterval(t *testing.T) {
+	start := time.Unix(0, 0)
+	h := newProgressHistory(start)
+	for ms := 0; ms < 3000; ms += 100 {
+		h.add(start.Add(time.Duration(ms)*time.Millisecond), ms/30, ffmpeg.Progress{Speed: "2x"})
+	}
+	if got := len(h.samples); got != 3 {
+		t.Fatalf("samples = %d, want 3 (one per second)", got)
+	}
+	if h.samples[1].T != 1 || h.samples[1].Speed != 2 {
+		t.Errorf("sample[1] = %+v", h.samples[1])
+	}
+}
+
+func TestProgressHistory_StaysBoundedAndSpansEncode(t *testing.T) {
+	start := time.Unix(0, 0)
+	h := newProgressHistory(start)
+	const total = 2 * time.Hour
+	for d := time.Duration(0); d < total; d += 500 * time.Millisecond {
+		h.add(start.Add(d), int(d*100/total), ffmpeg.Progress{Speed: "1.0x"})
+	}
+	h.finish(start.Add(total))
+
+	if got := len(h.samples); got > maxProgressSamples+1 {
+		t.Fatalf("samples = %d, want <= %d", got, maxProgressSamples+1)
+	}
+	if h.samples[0].T != 0 {
+		t.Errorf("first sample at %v, want 0", h.samples[0].T)
+	}
+	last := h.samples[len(h.samples)-1]
+	if last.Pct != 100 || last.T != total.Seconds() {
+		t.Errorf("last sample = %+v, want 100%% at %v", last, total.Seconds())
+	}
+}
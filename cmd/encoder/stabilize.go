@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/utils/crops"
+)
+
+// exportVidstab reports whether ffmpeg was built with libvidstab, which the
+// stabilize filter needs. Set once at startup by initVidstab.
+var exportVidstab bool
+
+// initVidstab checks for the vidstab filters. Builds without them still run;
+// exports skip their stabilize filters.
+func initVidstab(ctx context.Context) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	detect, err := ffmpeg.HasFilter(probeCtx, "vidstabdetect")
+	if err != nil {
+		slog.Warn("failed to probe for libvidstab, stabilize filters will be skipped", "error", err)
+		return false
+	}
+	transform, err := ffmpeg.HasFilter(probeCtx, "vidstabtransform")
+	if err != nil || !detect || !transform {
+		slog.Warn("ffmpeg lacks libvidstab, stabilize filters will be skipped")
+		return false
+	}
+	slog.Info("video stabilization enabled")
+	return true
+}
+
+// detectMotion runs the detection pass of the stabilize filter: the clip's
+// video, cut with seek, goes through the export's filters to a null output
+// while vidstabdetect writes the camera motion to trfPath. Filters ahead of
+// the stabilize filter change the frames it analyses, so the whole stack is
+// applied.
+func detectMotion(ctx context.Context, inputPath string, seek ffmpeg.Option, specs []ffmpeg.FilterSpec, clipCrops crops.CropArray, trfPath string) error {
+	filterOpts, _, err := ffmpeg.CompileFiltersForSource(ffmpeg.WithStabilizeDetection(specs, trfPath), clipCrops, false)
+	if err != nil {
+		return err
+	}
+	opts := append([]ffmpeg.Option{seek, ffmpeg.NoAudio, ffmpeg.ExtraArgs("-f", "null")}, filterOpts...)
+	if res := ffmpeg.RunCapture(ctx, inputPath, "-", opts...); res.Err != nil {
+		return fmt.Errorf("ffmpeg: %w", res.Err)
+	}
+	return nil
+}
@@ -127,6 +127,36 @@ func processStitch(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 	}
 	for i := range rawSegs {
 		rawSegs[i].parseTransition()
+		rawSegs[i].Filters = ffmpeg.StripInjectedParams(rawSegs[i].Filters)
+	}
+
+	// Filters that cannot be rendered fail the job rather than leaving a
+	// segment unfiltered. LUT files belong to the user who asked for the
+	// stitch. Burned-in subtitles come from the segment's own video
+	// (videoDir), so only clip and video segments can carry them. The
+	// stitch graph has no motion detection pass for stabilize.
+	lutDir := ffmpeg.LUTFileDir(exportsDir, uuidString(jobRow.CreatedBy))
+	prepareFilters := func(specs []ffmpeg.FilterSpec, videoDir, videoID string) ([]ffmpeg.FilterSpec, error) {
+		if ffmpeg.NeedsStabilizeDetection(specs) {
+			return nil, fmt.Errorf("stabilize is not supported when stitching clips")
+		}
+		specs, err := ffmpeg.ResolveLUTFiles(specs, lutDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve lut files: %w", err)
+		}
+		if !hasSubtitlesFilter(specs) {
+			return specs, nil
+		}
+		if videoDir == "" {
+			return nil, fmt.Errorf("subtitles can only be burned into clip and video segments")
+		}
+		if !exportLibass {
+			return nil, fmt.Errorf("burning in subtitles needs an ffmpeg built with libass")
+		}
+		return ffmpeg.ResolveSubtitleFiles(specs, func(lang string) (string, bool) {
+			path, _, ok := findCanonicalCaptionFilePath(videoDir, videoID, lang)
+			return path, ok
+		})
 	}
 	if len(rawSegs) == 0 {
 		return fmt.Errorf("stitch job has no segments")
@@ -138,6 +168,7 @@ func processStitch(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 		if err := json.Unmarshal(jobRow.GlobalFilters, &globalFilterSpecs); err != nil {
 			slog.Warn("failed to parse global filters, ignoring", "error", err)
 		}
+		globalFilterSpecs = ffmpeg.StripInjectedParams(globalFilterSpecs)
 	}
 
 	// Collect clip IDs to bulk-load from DB
@@ -237,21 +268,22 @@ func processStitch(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 
 			// Compile per-segment filters
 			var videoFilters, audioFilters []string
-			if len(raw.Filters) > 0 {
-				var err error
-				videoFilters, audioFilters, err = ffmpeg.CompileFilterStrings(raw.Filters, clipData.Crops)
+			// Segment filters, falling back to the clip's saved filter stack
+			specs := raw.Filters
+			if len(specs) == 0 && len(clipData.FilterStack) > 0 && string(clipData.FilterStack) != "[]" && string(clipData.FilterStack) != "null" {
+				if err := json.Unmarshal(clipData.FilterStack, &specs); err != nil {
+					return fmt.Errorf("segment %d: invalid filter stack of clip %q: %w", i, raw.ClipID, err)
+				}
+				specs = ffmpeg.StripInjectedParams(specs)
+			}
+			if len(specs) > 0 {
+				specs, err := prepareFilters(specs, videoDir, videoID)
 				if err != nil {
-					slog.Warn("failed to compile segment filters, skipping", "clip_id", raw.ClipID, "error", err)
+					return fmt.Errorf("segment %d: %w", i, err)
 				}
-			} else if len(clipData.FilterStack) > 0 && string(clipData.FilterStack) != "[]" && string(clipData.FilterStack) != "null" {
-				// Fall back to clip's saved filter stack
-				var specs []ffmpeg.FilterSpec
-				if err := json.Unmarshal(clipData.FilterStack, &specs); err == nil && len(specs) > 0 {
-					var err error
-					videoFilters, audioFilters, err = ffmpeg.CompileFilterStrings(specs, clipData.Crops)
-					if err != nil {
-						slog.Warn("failed to compile clip filter stack, skipping", "clip_id", raw.ClipID, "error", err)
-					}
+				videoFilters, audioFilters, err = ffmpeg.CompileFilterStrings(ffmpeg.WithClipStart(ffmpeg.WithClipDuration(specs, dur.Seconds()), clipData.StartTs), clipData.Crops)
+				if err != nil {
+					return fmt.Errorf("segment %d: filters of clip %q: %w", i, raw.ClipID, err)
 				}
 			}
 
@@ -300,10 +332,13 @@ func processStitch(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 			// Compile per-segment filters (no crops for raw videos)
 			var videoFilters, audioFilters []string
 			if len(raw.Filters) > 0 {
-				var err error
-				videoFilters, audioFilters, err = ffmpeg.CompileFilterStrings(raw.Filters, nil)
+				specs, err := prepareFilters(raw.Filters, videoDir, raw.VideoID)
 				if err != nil {
-					slog.Warn("failed to compile segment filters, skipping", "video_id", raw.VideoID, "error", err)
+					return fmt.Errorf("segment %d: %w", i, err)
+				}
+				videoFilters, audioFilters, err = ffmpeg.CompileFilterStrings(ffmpeg.WithClipStart(ffmpeg.WithClipDuration(specs, dur.Seconds()), start.Seconds()), nil)
+				if err != nil {
+					return fmt.Errorf("segment %d: filters of video %q: %w", i, raw.VideoID, err)
 				}
 			}
 
@@ -337,10 +372,13 @@ func processStitch(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 			// Compile per-segment filters
 			var videoFilters, audioFilters []string
 			if len(raw.Filters) > 0 {
-				var compileErr error
-				videoFilters, audioFilters, compileErr = ffmpeg.CompileFilterStrings(raw.Filters, nil)
-				if compileErr != nil {
-					slog.Warn("failed to compile segment filters, skipping", "export_job_id", raw.ExportJobID, "error", compileErr)
+				specs, err := prepareFilters(raw.Filters, "", "")
+				if err != nil {
+					return fmt.Errorf("segment %d: %w", i, err)
+				}
+				videoFilters, audioFilters, err = ffmpeg.CompileFilterStrings(ffmpeg.WithClipDuration(specs, dur.Seconds()), nil)
+				if err != nil {
+					return fmt.Errorf("segment %d: filters of export %q: %w", i, raw.ExportJobID, err)
 				}
 			}
 
@@ -366,10 +404,13 @@ func processStitch(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 	// Compile global filters
 	var globalVideoFilters, globalAudioFilters []string
 	if len(globalFilterSpecs) > 0 {
-		var err error
-		globalVideoFilters, globalAudioFilters, err = ffmpeg.CompileFilterStrings(globalFilterSpecs, nil)
+		specs, err := prepareFilters(globalFilterSpecs, "", "")
+		if err != nil {
+			return fmt.Errorf("global filters: %w", err)
+		}
+		globalVideoFilters, globalAudioFilters, err = ffmpeg.CompileFilterStrings(ffmpeg.WithClipDuration(specs, totalDur.Seconds()), nil)
 		if err != nil {
-			slog.Warn("failed to compile global filters, ignoring", "error", err)
+			return fmt.Errorf("global filters: %w", err)
 		}
 	}
 
@@ -380,7 +421,7 @@ func processStitch(ctx context.Context, q *db.Queries, exportsDir, downloadsDir
 	}
 
 	// Determine codec presets and extension
-	videoPreset, audioPreset, ext := ffmpeg.ExportPresetForFormat(jobRow.Format, jobRow.Quality)
+	videoPreset, audioPreset, ext, _ := ffmpeg.ExportPresetForFormat(jobRow.Format, ffmpeg.ExportSpec{Quality: jobRow.Quality}, ffmpeg.HardwareNone)
 	outputPath := filepath.Join(stitchExportDir, jobID+ext)
 
 	// Build codec opts
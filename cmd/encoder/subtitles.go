@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// exportLibass reports whether ffmpeg has the subtitles filter, which needs
+// libass. Set once at startup by initLibass.
+var exportLibass bool
+
+// initLibass checks for the subtitles filter. Builds without it still run;
+// only exports that burn in subtitles fail.
+func initLibass(ctx context.Context) bool {
+	probeCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	ok, err := ffmpeg.HasFilter(probeCtx, "subtitles")
+	if err != nil {
+		slog.Warn("failed to probe for libass, subtitle burn-in will fail", "error", err)
+		return false
+	}
+	if !ok {
+		slog.Warn("ffmpeg lacks libass, subtitle burn-in will fail")
+		return false
+	}
+	return true
+}
+
+// hasSubtitlesFilter reports whether specs burn in subtitles.
+func hasSubtitlesFilter(specs []ffmpeg.FilterSpec) bool {
+	for _, spec := range specs {
+		if spec.Type == "subtitles" {
+			return true
+		}
+	}
+	return false
+}
+
+// findCanonicalCaptionFilePath finds a video's <id>.captions.<lang>.vtt in
+// dir, returning its path and language. With no lang it prefers English,
+// then und, then any language, the same order ingest uses; otherwise only
+// that language is accepted.
+func findCanonicalCaptionFilePath(dir, videoID, lang string) (string, string, bool) {
+	if strings.TrimSpace(dir) == "" || strings.TrimSpace(videoID) == "" {
+		return "", "", false
+	}
+	langs := []string{"en", "und"}
+	if lang != "" {
+		langs = []string{lang}
+	}
+	for _, l := range langs {
+		p := filepath.Join(dir, videoID+".captions."+l+".vtt")
+		if _, err := os.Stat(p); err == nil {
+			return p, l, true
+		}
+	}
+	if lang != "" {
+		return "", "", false
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, videoID+".captions.*.vtt"))
+	if len(matches) == 0 {
+		return "", "", false
+	}
+	name := strings.TrimSuffix(filepath.Base(matches[0]), ".vtt")
+	return matches[0], name[strings.LastIndex(name, ".")+1:], true
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// assetParallelism is how many of one video's asset generators run at once
+// (INGEST_ASSET_PARALLELISM, default 3). Each is an ffmpeg process or
+// similar, so this multiplies with INGEST_WORKERS.
+func assetParallelism() int {
+	return envInt("INGEST_ASSET_PARALLELISM", 3)
+}
+
+// assetRunner runs a video's asset generators concurrently, at most
+// assetParallelism at a time. Generators are best-effort: a failure is
+// logged and recorded under its asset name, and never stops the others.
+// Generators may read the source file concurrently but must each write only
+// their own outputs.
+type assetRunner struct {
+	videoID string
+	g       errgroup.Group
+
+	mu     sync.Mutex
+	errors map[string]string
+}
+
+func newAssetRunner(videoID string) *assetRunner {
+	r := &assetRunner{videoID: videoID, errors: map[string]string{}}
+	r.g.SetLimit(assetParallelism())
+	return r
+}
+
+// Go starts fn once a slot is free, recording its error under asset. A panic
+// in fn is recorded as its error: generators run outside the ingest worker's
+// recover, so it would otherwise take down the whole service.
+func (r *assetRunner) Go(asset string, fn func() error) {
+	r.g.Go(func() error {
+		start := time.Now()
+		if err := runAssetGenerator(fn); err != nil {
+			slog.Warn("asset generation failed", "video_id", r.videoID, "asset", asset, "error", err)
+			r.mu.Lock()
+			r.errors[asset] = err.Error()
+			r.mu.Unlock()
+			return nil
+		}
+		slog.Debug("asset generated", "video_id", r.videoID, "asset", asset, "elapsed", time.Since(start))
+		return nil
+	})
+}
+
+// runAssetGenerator calls fn, turning a panic into an error.
+func runAssetGenerator(fn func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return fn()
+}
+
+// Wait blocks until every generator has finished and returns the errors by
+// asset name. Values the generators stored are safe to read once it returns.
+func (r *assetRunner) Wait() map[string]string {
+	_ = r.g.Wait()
+	return r.errors
+}
+
+// recordAssetErrors adds error tracking to a verified assets status. A run
+// with errors bumps _error_count from prev, which backs off asset catchup
+// retries, and stores the errors by asset; a clean run clears both. It
+// returns the new error count.
+func recordAssetErrors(status map[string]any, prev db.AssetMap, assetErrors map[string]string) int {
+	if len(assetErrors) == 0 {
+		status["_error_count"] = 0
+		status["_errors"] = map[string]string{}
+		return 0
+	}
+	prevCount := 0
+	if v, ok := prev["_error_count"].(float64); ok {
+		prevCount = int(v)
+	}
+	status["_error_count"] = prevCount + 1
+	status["_last_error_at"] = time.Now().UTC().Format(time.RFC3339)
+	status["_errors"] = assetErrors
+	return prevCount + 1
+}
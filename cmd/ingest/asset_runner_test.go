@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+func TestAssetRunner_BoundedAndBestEffort(t *testing.T) {
+	t.Setenv("INGEST_ASSET_PARALLELISM", "2")
+
+	r := newAssetRunner("video")
+	var active, maxActive, ran atomic.Int32
+	for _, asset := range []string{"thumbnail", "preview", "seek", "waveform", "captions"} {
+		r.Go(asset, func() error {
+			n := active.Add(1)
+			for {
+				m := maxActive.Load()
+				if n <= m || maxActive.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			active.Add(-1)
+			ran.Add(1)
+			if asset == "preview" || asset == "captions" {
+				return errors.New(asset + " failed")
+			}
+			return nil
+		})
+	}
+	errs := r.Wait()
+
+	if ran.Load() != 5 {
+		t.Errorf("ran %d generators, want all 5 despite failures", ran.Load())
+	}
+	if maxActive.Load() > 2 {
+		t.Errorf("max concurrent generators = %d, want at most 2", maxActive.Load())
+	}
+	if len(errs) != 2 || errs["preview"] != "preview failed" || errs["captions"] != "captions failed" {
+		t.Errorf("errors = %v, want preview and captions", errs)
+	}
+}
+
+func TestRecordAssetErrors(t *testing.T) {
+	status := map[string]any{}
+	if n := recordAssetErrors(status, db.AssetMap{"_error_count": float64(2)}, map[string]string{"seek": "boom"}); n != 3 {
+		t.Errorf("error count = %d, want 3", n)
+	}
+	if status["_error_count"] != 3 || status["_last_error_at"] == nil {
+		t.Errorf("status = %v, want count 3 and a timestamp", status)
+	}
+
+	status = map[string]any{}
+	if n := recordAssetErrors(status, db.AssetMap{"_error_count": float64(2)}, map[string]string{}); n != 0 {
+		t.Errorf("clean run error count = %d, want 0", n)
+	}
+	if errs, _ := status["_errors"].(map[string]string); status["_error_count"] != 0 || errs == nil || len(errs) != 0 {
+		t.Errorf("clean run status = %v, want errors cleared", status)
+	}
+}
+
+func TestAssetRunner_RecoversPanics(t *testing.T) {
+	r := newAssetRunner("video")
+	r.Go("thumbnail", func() error { panic("boom") })
+	r.Go("waveform", func() error { return nil })
+	errs := r.Wait()
+
+	if got := errs["thumbnail"]; got != "panic: boom" {
+		t.Errorf("thumbnail error = %q, want the recovered panic", got)
+	}
+	if _, ok := errs["waveform"]; ok {
+		t.Errorf("waveform recorded an error after another generator panicked")
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+// assetNotApplicable is stored in assets_status in place of a boolean for
+// assets that cannot exist for a video, such as seek sprites for audio-only
+// content. Catch-up only retries keys that are false, so these are left alone.
+const assetNotApplicable = "n/a"
+
+// audioOnlySkipEnabled reports whether audio-only files skip video asset
+// generation. On by default; set AUDIO_ONLY_SKIP_VIDEO_ASSETS=false to attempt
+// every asset regardless.
+func audioOnlySkipEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("AUDIO_ONLY_SKIP_VIDEO_ASSETS"))
+	if v == "" {
+		return true
+	}
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// audioOnlyMedia is the result of probing a file for audio-only handling.
+type audioOnlyMedia struct {
+	AudioOnly   bool
+	HasCoverArt bool
+}
+
+// probeAudioOnly reports whether videoPath has no real video stream. It
+// always returns false when audio-only skipping is disabled or the probe
+// fails, so callers fall back to the normal video pipeline.
+func probeAudioOnly(ctx context.Context, videoPath string) audioOnlyMedia {
+	if !audioOnlySkipEnabled() || strings.TrimSpace(videoPath) == "" {
+		return audioOnlyMedia{}
+	}
+	result, err := ffmpeg.Probe(ctx, videoPath)
+	if err != nil {
+		return audioOnlyMedia{}
+	}
+	return audioOnlyFromProbe(result)
+}
+
+func audioOnlyFromProbe(result *ffmpeg.ProbeResult) audioOnlyMedia {
+	if !audioOnlySkipEnabled() || result == nil {
+		return audioOnlyMedia{}
+	}
+	pj, err := json.Marshal(result.RawJSON)
+	if err != nil {
+		return audioOnlyMedia{}
+	}
+	info := videoinfo.NewProbeInfo(pj)
+	if info == nil || !info.IsAudioOnly() {
+		return audioOnlyMedia{}
+	}
+	return audioOnlyMedia{AudioOnly: true, HasCoverArt: info.HasCoverArt()}
+}
+
+// generateAudioCoverThumbnail writes embedded cover art as the thumbnail
+// variants for an audio-only video. Returns nil without error when the file
+// has no cover art.
+func generateAudioCoverThumbnail(ctx context.Context, videoPath, videoID string, media audioOnlyMedia, forceRegenerate bool) (*string, error) {
+	if !media.HasCoverArt {
+		return nil, nil
+	}
+	p, err := writeStillThumbnails(ctx, videoPath, filepath.Dir(videoPath), videoID, 0, forceRegenerate)
+	if err == nil {
+		pruneSourceArt(filepath.Dir(videoPath), videoID)
+	}
+	return p, err
+}
+
+// coverArtFallbackThumbnail writes a video's embedded cover art as its
+// thumbnail, for when no frame could be extracted (e.g. the video stream does
+// not decode). Returns nil without error when the file has no cover art.
+func coverArtFallbackThumbnail(ctx context.Context, videoPath, videoID string) (*string, error) {
+	probe, err := ffmpeg.Probe(ctx, videoPath)
+	if err != nil || len(probe.AttachedPics) == 0 {
+		return nil, nil
+	}
+	// Overwrite any variants the failed frame extraction left behind.
+	return writeStillThumbnails(ctx, videoPath, filepath.Dir(videoPath), videoID, probe.AttachedPics[0], true)
+}
+
+// writeStillThumbnails scales a single picture (cover art or a downloaded
+// image) into every thumbnail variant in videoDir and returns the default
+// variant's path. stream is the video stream of input holding the picture.
+func writeStillThumbnails(ctx context.Context, input, videoDir, videoID string, stream int, forceRegenerate bool) (*string, error) {
+	legacy := filepath.Join(videoDir, videoID+".thumbnail.jpg")
+	if forceRegenerate {
+		_ = os.Remove(legacy)
+	}
+	for _, variant := range thumbnailVariants {
+		out := thumbnailVariantPath(videoDir, videoID, variant.Label)
+		if !forceRegenerate {
+			if _, err := os.Stat(out); err == nil {
+				continue
+			}
+		}
+		result := ffmpeg.ExtractAttachedPic(ctx, input, out, stream, variant.MaxWidth)
+		if result.Err != nil {
+			_ = os.Remove(out)
+			if result.Logs != "" {
+				slog.Info("ffmpeg cover art output", "output", out, "logs", result.Logs)
+			}
+			return nil, fmt.Errorf("ffmpeg cover art: %w", result.Err)
+		}
+	}
+	defaultPath := thumbnailVariantPath(videoDir, videoID, defaultThumbnailLabel)
+	ensureLegacyThumbnailCopy(videoDir, videoID, defaultPath)
+	return &defaultPath, nil
+}
+
+// markAudioOnlyAssets replaces the status of video-derived assets with
+// assetNotApplicable. The thumbnail keeps its real status when cover art was
+// extracted.
+func markAudioOnlyAssets(status map[string]any) {
+	for _, key := range []string{"preview", "preview_sprite", "seek", "chapter_posters"} {
+		status[key] = assetNotApplicable
+	}
+	if ok, _ := status["thumbnail"].(bool); !ok {
+		status["thumbnail"] = assetNotApplicable
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/utils/keywords"
+)
+
+// autoTagCandidates is how many of a transcript's most frequent words are
+// weighed against the archive; the rest rarely make the cut.
+const autoTagCandidates = 40
+
+// autoTagsEnabled reports whether transcript keywords are added as tags.
+// Off by default: suggested tags land in the shared tag list.
+func autoTagsEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("AUTO_TAGS_ENABLED"))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// autoTagsMax is the most tags added to one video (AUTO_TAGS_MAX, 1-20).
+func autoTagsMax() int {
+	return max(1, min(envInt("AUTO_TAGS_MAX", 5), 20))
+}
+
+// autoTagVideo replaces a video's automatic tags with the top keywords of its
+// transcripts. Videos without a transcript are left untouched and are not
+// marked, so they are picked up once one exists.
+func autoTagVideo(ctx context.Context, q *db.Queries, videoID pgtype.UUID) error {
+	texts, err := q.ListVideoTranscriptTexts(ctx, videoID)
+	if err != nil {
+		return fmt.Errorf("load transcripts: %w", err)
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+
+	terms := keywords.Candidates(strings.Join(texts, "\n"), autoTagCandidates)
+	var tags []string
+	if len(terms) > 0 {
+		words := make([]string, len(terms))
+		for i, t := range terms {
+			words[i] = t.Word
+		}
+		rows, err := q.CountTranscriptTermVideos(ctx, words)
+		if err != nil {
+			return fmt.Errorf("count term frequency: %w", err)
+		}
+		docFreq := make(map[string]int64, len(rows))
+		for _, r := range rows {
+			docFreq[r.Term] = r.Videos
+		}
+		total, err := q.CountTranscribedVideos(ctx)
+		if err != nil {
+			return fmt.Errorf("count transcripts: %w", err)
+		}
+		tags = keywords.Rank(terms, docFreq, total, autoTagsMax())
+	}
+
+	if err := q.DeleteVideoAutoTags(ctx, videoID); err != nil {
+		return fmt.Errorf("clear auto tags: %w", err)
+	}
+	for _, word := range tags {
+		tagID, err := q.UpsertAutoTag(ctx, &db.UpsertAutoTagParams{Name: word, Slug: word})
+		if err != nil {
+			return fmt.Errorf("upsert tag %q: %w", word, err)
+		}
+		if err := q.AddVideoAutoTag(ctx, &db.AddVideoAutoTagParams{VideoID: videoID, TagID: tagID}); err != nil {
+			return fmt.Errorf("add tag %q: %w", word, err)
+		}
+	}
+	if err := q.MarkVideoAutoTagged(ctx, videoID); err != nil {
+		return fmt.Errorf("mark auto-tagged: %w", err)
+	}
+	slog.Info("auto tags stored", "video_id", videoID.String(), "tags", tags)
+	return nil
+}
+
+// runAutoTagBackfill tags transcribed videos ingested before AUTO_TAGS_ENABLED
+// was turned on.
+func runAutoTagBackfill(ctx context.Context, dbc *db.DatabaseConnection) {
+	if !autoTagsEnabled() {
+		return
+	}
+	q := dbc.Queries(ctx)
+	ids, err := q.ListVideosNeedingAutoTags(ctx, 50)
+	if err != nil {
+		slog.Warn("auto tag backfill query failed", "error", err)
+		return
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := autoTagVideo(ctx, q, id); err != nil {
+			slog.Warn("auto tag backfill failed", "video_id", id.String(), "error", err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	xtlang "golang.org/x/text/language"
 	"thirdcoast.systems/rewind/internal/db"
 	rewindlang "thirdcoast.systems/rewind/pkg/utils/language"
+	"thirdcoast.systems/rewind/pkg/utils/srt"
 )
 
 func findCaptionFilePath(infoPath string, spoolDir string) (string, string, bool) {
@@ -125,11 +127,78 @@ func ingestTranscriptFile(ctx context.Context, q *db.Queries, videoID pgtype.UUI
 		parsedLang = xtlang.Und
 	}
 
-	return q.UpsertVideoTranscript(ctx, &db.UpsertVideoTranscriptParams{
+	if err := q.UpsertVideoTranscript(ctx, &db.UpsertVideoTranscriptParams{
 		VideoID: videoID,
 		Lang:    rewindlang.Tag(parsedLang),
 		Format:  "vtt",
 		Text:    text,
 		Raw:     string(rawBytes),
-	})
+	}); err != nil {
+		return err
+	}
+
+	// Word timings from Whisper (best-effort); other captions seek per cue.
+	if n, err := storeTranscriptWords(ctx, q, videoID, rewindlang.Tag(parsedLang), path, string(rawBytes)); err != nil {
+		slog.Warn("failed to store transcript word timings", "video_id", videoID.String(), "path", path, "error", err)
+	} else if n > 0 {
+		slog.Info("Transcript word timings stored", "video_id", videoID.String(), "words", n)
+	}
+
+	// Keyword tags follow the transcript (best-effort).
+	if autoTagsEnabled() {
+		if err := autoTagVideo(ctx, q, videoID); err != nil {
+			slog.Warn("failed to auto-tag video", "video_id", videoID.String(), "error", err)
+		}
+	}
+	return nil
+}
+
+// writeCaptionSRTs writes an SRT copy next to each of the video's canonical
+// caption files, <id>.captions.<lang>.srt, for players and editors that do
+// not read WebVTT. Copies newer than their VTT are left alone. It returns
+// the number of files written.
+func writeCaptionSRTs(dir string, videoID string) (int, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, videoID+".captions.*.vtt"))
+	if err != nil {
+		return 0, err
+	}
+	written := 0
+	for _, vttPath := range matches {
+		srtPath := strings.TrimSuffix(vttPath, ".vtt") + ".srt"
+		vttInfo, err := os.Stat(vttPath)
+		if err != nil {
+			return written, err
+		}
+		if srtInfo, err := os.Stat(srtPath); err == nil && !srtInfo.ModTime().Before(vttInfo.ModTime()) {
+			continue
+		}
+		raw, err := os.ReadFile(vttPath)
+		if err != nil {
+			return written, fmt.Errorf("read captions: %w", err)
+		}
+		out, cues := srt.FromVTT(raw)
+		if cues == 0 {
+			continue
+		}
+		tmp := srtPath + ".tmp"
+		if err := os.WriteFile(tmp, out, 0o644); err != nil {
+			return written, fmt.Errorf("write srt: %w", err)
+		}
+		if err := os.Rename(tmp, srtPath); err != nil {
+			_ = os.Remove(tmp)
+			return written, fmt.Errorf("rename srt: %w", err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// exportCaptionSRTs is writeCaptionSRTs for the asset pipelines, where the
+// SRT copies are best-effort.
+func exportCaptionSRTs(dir string, videoID string) {
+	if n, err := writeCaptionSRTs(dir, videoID); err != nil {
+		slog.Warn("failed to export srt captions", "video_id", videoID, "error", err)
+	} else if n > 0 {
+		slog.Info("SRT captions exported", "video_id", videoID, "files", n)
+	}
 }
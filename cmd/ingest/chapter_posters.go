@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+const chapterPostersManifestName = "posters.json"
+
+// chapterPostersManifest records which chapter posters exist and the chapter
+// start each was taken from, so unchanged posters are not re-extracted.
+type chapterPostersManifest struct {
+	Posters []chapterPoster `json:"posters"`
+}
+
+type chapterPoster struct {
+	Index     int     `json:"index"`
+	StartTime float64 `json:"start_time"`
+	Title     string  `json:"title,omitempty"`
+	File      string  `json:"file"`
+}
+
+func chapterPostersDirForVideoPath(videoPath string) (string, error) {
+	videoPath = strings.TrimSpace(videoPath)
+	if videoPath == "" {
+		return "", errors.New("missing video path")
+	}
+	return filepath.Join(filepath.Dir(videoPath), "chapters"), nil
+}
+
+// ensureChapterPosters extracts an original-resolution poster frame at the
+// start of each chapter into <video dir>/chapters/<index>.jpg. Posters whose
+// chapter start is unchanged since the last run are kept unless
+// forceRegenerate is set. A chapter whose frame cannot be extracted (e.g. a
+// start past the last decodable frame) is logged and left out of the
+// manifest, so the next run tries it again; the other posters are still
+// written. Returns the number of posters extracted, and an error naming the
+// chapters that failed.
+func ensureChapterPosters(ctx context.Context, videoPath string, chapters []videoinfo.Chapter, forceRegenerate bool) (int, error) {
+	dir, err := chapterPostersDirForVideoPath(videoPath)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("mkdir chapters dir: %w", err)
+	}
+
+	prev := map[int]chapterPoster{}
+	if !forceRegenerate {
+		if m, err := readChapterPostersManifest(dir); err == nil {
+			for _, p := range m.Posters {
+				prev[p.Index] = p
+			}
+		}
+	}
+
+	next := chapterPostersManifest{Posters: make([]chapterPoster, 0, len(chapters))}
+	generated := 0
+	var failed []string
+	for i, ch := range chapters {
+		if ctx.Err() != nil {
+			return generated, ctx.Err()
+		}
+		poster := chapterPoster{
+			Index:     i,
+			StartTime: ch.StartTime,
+			Title:     strings.TrimSpace(ch.Title),
+			File:      strconv.Itoa(i) + ".jpg",
+		}
+		out := filepath.Join(dir, poster.File)
+		if p, ok := prev[i]; ok && p.StartTime == ch.StartTime {
+			if _, err := os.Stat(out); err == nil {
+				next.Posters = append(next.Posters, poster)
+				continue
+			}
+		}
+
+		offset := time.Duration(ch.StartTime * float64(time.Second))
+		result := ffmpeg.ExtractFrame(ctx, videoPath, out, offset, 2)
+		if result.Err != nil {
+			_ = os.Remove(out)
+			if result.Logs != "" {
+				slog.Info("ffmpeg chapter poster output", "output", out, "logs", result.Logs)
+			}
+			slog.Warn("failed to extract chapter poster", "video_path", videoPath, "chapter", i, "start_time", ch.StartTime, "error", result.Err)
+			failed = append(failed, strconv.Itoa(i))
+			continue
+		}
+		generated++
+		next.Posters = append(next.Posters, poster)
+	}
+
+	// Drop posters for chapters that no longer exist.
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		name := e.Name()
+		idx, err := strconv.Atoi(strings.TrimSuffix(name, ".jpg"))
+		if err != nil || !strings.HasSuffix(name, ".jpg") || idx < len(chapters) {
+			continue
+		}
+		_ = os.Remove(filepath.Join(dir, name))
+	}
+
+	if err := writeChapterPostersManifest(dir, next); err != nil {
+		return generated, err
+	}
+	if len(failed) > 0 {
+		return generated, fmt.Errorf("%d of %d chapter posters failed (chapters %s)", len(failed), len(chapters), strings.Join(failed, ", "))
+	}
+	return generated, nil
+}
+
+// generateVideoChapterPosters wraps ensureChapterPosters, clearing existing
+// posters first when forceRegenerate is set.
+func generateVideoChapterPosters(ctx context.Context, videoPath, videoID string, chapters []videoinfo.Chapter, forceRegenerate bool) (int, error) {
+	if forceRegenerate {
+		if dir, err := chapterPostersDirForVideoPath(videoPath); err == nil {
+			if err := os.RemoveAll(dir); err != nil && !os.IsNotExist(err) {
+				slog.Warn("failed to delete existing chapter posters", "path", dir, "error", err)
+			} else if err == nil {
+				slog.Info("deleted existing chapter posters for regeneration", "video_id", videoID, "path", dir)
+			}
+		}
+	}
+	return ensureChapterPosters(ctx, videoPath, chapters, forceRegenerate)
+}
+
+// verifyChapterPosters reports whether the chapter poster manifest exists and
+// every poster it lists is on disk.
+func verifyChapterPosters(videoPath string) bool {
+	dir, err := chapterPostersDirForVideoPath(videoPath)
+	if err != nil {
+		return false
+	}
+	m, err := readChapterPostersManifest(dir)
+	if err != nil {
+		return false
+	}
+	for _, p := range m.Posters {
+		if _, err := os.Stat(filepath.Join(dir, p.File)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func readChapterPostersManifest(dir string) (chapterPostersManifest, error) {
+	var m chapterPostersManifest
+	b, err := os.ReadFile(filepath.Join(dir, chapterPostersManifestName))
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(b, &m)
+	return m, err
+}
+
+func writeChapterPostersManifest(dir string, m chapterPostersManifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal chapter posters manifest: %w", err)
+	}
+	path := filepath.Join(dir, chapterPostersManifestName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return fmt.Errorf("write chapter posters manifest: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("rename chapter posters manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+// Values of video_chapters.source.
+const (
+	chapterSourceInfo      = "info"
+	chapterSourceContainer = "container"
+)
+
+// resolveVideoChapters returns a video's sanitized chapters and their source.
+// The info.json chapters win; when it has none, the chapters muxed into the
+// media file are used instead. No chapters yields a nil slice.
+func resolveVideoChapters(ctx context.Context, videoPath string, info []videoinfo.Chapter, durationSeconds *int32) ([]videoinfo.Chapter, string) {
+	var duration float64
+	if durationSeconds != nil {
+		duration = float64(*durationSeconds)
+	}
+	if chapters := videoinfo.SanitizeChapters(info, duration); len(chapters) > 0 {
+		return chapters, chapterSourceInfo
+	}
+
+	if strings.TrimSpace(videoPath) == "" {
+		return nil, ""
+	}
+	probe, err := ffmpeg.Probe(ctx, videoPath)
+	if err != nil {
+		slog.Warn("failed to probe container chapters", "video_path", videoPath, "error", err)
+		return nil, ""
+	}
+	if len(probe.Chapters) == 0 {
+		return nil, ""
+	}
+	if probe.Duration > 0 {
+		duration = probe.Duration
+	}
+	fromFile := make([]videoinfo.Chapter, len(probe.Chapters))
+	for i, ch := range probe.Chapters {
+		fromFile[i] = videoinfo.Chapter{StartTime: ch.StartTime, EndTime: ch.EndTime, Title: ch.Title}
+	}
+	chapters := videoinfo.SanitizeChapters(fromFile, duration)
+	if len(chapters) == 0 {
+		return nil, ""
+	}
+	return chapters, chapterSourceContainer
+}
+
+// storeVideoChapters replaces a video's stored chapters with chapters. An
+// empty list clears them.
+func storeVideoChapters(ctx context.Context, dbc *db.DatabaseConnection, videoID pgtype.UUID, chapters []videoinfo.Chapter, source string) error {
+	tx, err := dbc.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	qtx := dbc.Queries(ctx).WithTx(tx)
+
+	if err := qtx.DeleteVideoChapters(ctx, videoID); err != nil {
+		return fmt.Errorf("delete previous chapters: %w", err)
+	}
+	if len(chapters) > 0 {
+		params := &db.InsertVideoChaptersParams{
+			VideoID:   videoID,
+			Source:    source,
+			Positions: make([]int32, len(chapters)),
+			Starts:    make([]float64, len(chapters)),
+			Ends:      make([]float64, len(chapters)),
+			Titles:    make([]string, len(chapters)),
+		}
+		for i, ch := range chapters {
+			params.Positions[i] = int32(i)
+			params.Starts[i] = ch.StartTime
+			params.Ends[i] = ch.EndTime
+			params.Titles[i] = ch.Title
+		}
+		if err := qtx.InsertVideoChapters(ctx, params); err != nil {
+			return fmt.Errorf("insert chapters: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
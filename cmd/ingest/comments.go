@@ -9,7 +9,11 @@ import (
 )
 
 // ingestCommentsFromInfoJSON delegates to the shared comments ingester (used by
-// both the initial ingest and the downloader's comment catch-up loop).
-func ingestCommentsFromInfoJSON(ctx context.Context, q *db.Queries, videoID pgtype.UUID, source string, rawInfoJSON []byte) error {
-	return comments.IngestFromInfoJSON(ctx, q, videoID, source, rawInfoJSON)
+// both the initial ingest and the downloader's comment catch-up loop). On a
+// refresh, comments missing from the new fetch are flagged deleted when
+// COMMENTS_MARK_DELETED is enabled.
+func ingestCommentsFromInfoJSON(ctx context.Context, q *db.Queries, videoID pgtype.UUID, source string, rawInfoJSON []byte, refresh bool) error {
+	return comments.IngestFromInfoJSON(ctx, q, videoID, source, rawInfoJSON, comments.Options{
+		MarkDeleted: refresh && comments.MarkDeletedEnabled(),
+	})
 }
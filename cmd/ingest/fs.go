@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"image"
 	"io"
 	"log/slog"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/phash"
 )
 
 // moveVideoToPermanentStorage moves video files from spool to /downloads/{videoID}/
@@ -35,6 +37,7 @@ func moveVideoToPermanentStorage(ctx context.Context, videoID, spoolDir string)
 
 	var videoPath *string
 	var thumbnailPath *string
+	var copiedVideo *hashedFile
 
 	// Move each file to permanent storage
 	for _, srcPath := range files {
@@ -71,8 +74,16 @@ func moveVideoToPermanentStorage(ctx context.Context, videoID, spoolDir string)
 
 		// Move file (rename is fastest)
 		if err := os.Rename(srcPath, destPath); err != nil {
-			// If rename fails (cross-device), copy and delete
-			if err := copyFile(srcPath, destPath); err != nil {
+			// If rename fails (cross-device), copy and delete. The video is
+			// hashed while it is copied so it needn't be read a second time.
+			if isVideo {
+				hf, err := copyFileHashed(srcPath, destPath)
+				if err != nil {
+					slog.Warn("failed to move file", "src", srcPath, "dest", destPath, "error", err)
+					continue
+				}
+				copiedVideo = hf
+			} else if err := copyFile(srcPath, destPath); err != nil {
 				slog.Warn("failed to move file", "src", srcPath, "dest", destPath, "error", err)
 				continue
 			}
@@ -103,15 +114,27 @@ func moveVideoToPermanentStorage(ctx context.Context, videoID, spoolDir string)
 		}
 	}
 
-	// Compute SHA256 hash and size of video file if present
+	// SHA256 hash and size of the video file if present. A hash taken during
+	// a cross-device copy is reused unless normalization rewrote the file.
 	var fileHash *string
 	var fileSize *int64
 	if videoPath != nil {
-		if h, s, err := computeFileHashAndSize(*videoPath); err == nil {
-			fileHash = &h
-			fileSize = &s
-		} else {
-			slog.Warn("failed to compute file hash", "path", *videoPath, "error", err)
+		switch {
+		case copiedVideo.validFor(*videoPath):
+			fileHash = &copiedVideo.hash
+			fileSize = &copiedVideo.size
+		case fileHashEnabled():
+			if h, s, err := computeFileHashAndSize(*videoPath); err == nil {
+				fileHash = &h
+				fileSize = &s
+			} else {
+				slog.Warn("failed to compute file hash", "path", *videoPath, "error", err)
+			}
+		default:
+			if fi, err := os.Stat(*videoPath); err == nil {
+				s := fi.Size()
+				fileSize = &s
+			}
 		}
 	}
 
@@ -537,6 +560,101 @@ func computeFileHashAndSize(path string) (string, int64, error) {
 	return hex.EncodeToString(h.Sum(nil)), info.Size(), nil
 }
 
+// computeThumbnailPHash returns the perceptual hash of a thumbnail image as
+// the BIGINT bit pattern stored in videos.thumbnail_phash. A missing
+// thumbnail yields nil without an error; not every video has one.
+func computeThumbnailPHash(path string) (*int64, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode thumbnail: %w", err)
+	}
+	h, err := phash.HashImage(img)
+	if err != nil {
+		return nil, err
+	}
+	stored := int64(h)
+	return &stored, nil
+}
+
+// fileHashEnabled reports whether ingest reads each video back to compute its
+// SHA256 (INGEST_FILE_HASH, default true). Hashes back exact-duplicate
+// detection and the archive manifest; turning them off saves a full read of
+// every file. Videos copied across devices are still hashed during the copy.
+func fileHashEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("INGEST_FILE_HASH"))
+	return !(v == "0" || strings.EqualFold(v, "false") || strings.EqualFold(v, "no"))
+}
+
+// hashedFile is a SHA256 and size taken while a file was written. It only
+// describes the file while it is unchanged; see validFor.
+type hashedFile struct {
+	hash string
+	size int64
+	info os.FileInfo
+}
+
+// validFor reports whether path is still the file that was hashed: the same
+// inode, size and modification time. Rewrites such as faststart replace the
+// file and so invalidate the hash.
+func (h *hashedFile) validFor(path string) bool {
+	if h == nil {
+		return false
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(h.info, fi) && fi.Size() == h.size && fi.ModTime().Equal(h.info.ModTime())
+}
+
+// copyFileHashed copies a file from src to dst like copyFile, computing the
+// SHA256 of the data as it is copied.
+func copyFileHashed(src, dst string) (*hashedFile, error) {
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer sourceFile.Close()
+
+	destFile, err := os.Create(dst)
+	if err != nil {
+		return nil, err
+	}
+	defer destFile.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(destFile, io.TeeReader(sourceFile, h))
+	if err != nil {
+		return nil, err
+	}
+
+	// Preserve permissions
+	srcInfo, err := sourceFile.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(dst, srcInfo.Mode()); err != nil {
+		return nil, err
+	}
+	info, err := destFile.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return &hashedFile{hash: hex.EncodeToString(h.Sum(nil)), size: n, info: info}, nil
+}
+
 // copyFile copies a file from src to dst
 func copyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
@@ -564,9 +682,10 @@ func copyFile(src, dst string) error {
 }
 
 // isReadableVideoFile reports whether ffprobe can read the file and finds at
-// least one video stream. Used to validate candidates before treating one as
-// the canonical video (and before deleting any "redundant" sibling), so a
-// broken/empty stub can never displace a real archive file.
+// least one video stream, or embedded cover art alongside audio. Used to
+// validate candidates before treating one as the canonical video (and before
+// deleting any "redundant" sibling), so a broken/empty stub can never displace
+// a real archive file.
 func isReadableVideoFile(ctx context.Context, path string) bool {
 	probeCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
@@ -574,6 +693,9 @@ func isReadableVideoFile(ctx context.Context, path string) bool {
 	if err != nil {
 		return false
 	}
+	if len(probe.AttachedPics) > 0 && probe.AudioStreams > 0 {
+		return true
+	}
 	return probe.VideoStreams >= 1 && strings.TrimSpace(probe.VideoCodec) != ""
 }
 
@@ -596,15 +718,27 @@ func verifyPlayableMP4(ctx context.Context, path string) bool {
 		return false
 	}
 	// Normalization should have produced browser-playable codecs.
-	return !ffmpeg.NeedsVideoTranscode(probe) && !ffmpeg.NeedsAudioTranscode(probe)
+	return !ffmpeg.NeedsVideoTranscode(probe) && !normalizeAudioPolicy().NeedsAudioTranscode(probe)
+}
+
+// normalizeAudioPolicy returns the audio handling for MP4 normalization. By
+// default only audio browsers can't play is transcoded; set
+// NORMALIZE_AUDIO_AAC=true to transcode every non-AAC track (Opus, Vorbis,
+// FLAC, …) so all archived files share one audio codec.
+func normalizeAudioPolicy() ffmpeg.AudioPolicy {
+	v := strings.TrimSpace(os.Getenv("NORMALIZE_AUDIO_AAC"))
+	if v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes") {
+		return ffmpeg.AudioForceAAC
+	}
+	return ffmpeg.AudioKeepPlayable
 }
 
 // ensureStreamableMP4 makes videoPath a browser-playable, faststart MP4 in place.
 //
 //   - An already-streamable .mp4 just gets faststart applied and is returned as-is.
 //   - Otherwise it is normalized in one pass to <base>.mp4 (video copied unless the
-//     codec is legacy/unplayable, audio transcoded to AAC only when unplayable,
-//     subtitles dropped). The output is ffprobe-verified BEFORE the now-redundant
+//     codec is legacy/unplayable, audio transcoded to AAC only when unplayable
+//     or when NORMALIZE_AUDIO_AAC is set, text subtitles kept as mov_text). The output is ffprobe-verified BEFORE the now-redundant
 //     source is deleted, so a failed conversion can never destroy the original.
 //
 // Returns the path to the streamable video (the new .mp4 on success, the original
@@ -618,8 +752,15 @@ func ensureStreamableMP4(ctx context.Context, videoPath string) (string, error)
 		return videoPath, fmt.Errorf("ensure streamable: probe %s: %w", videoPath, err)
 	}
 
+	// Both paths below keep only the first video stream, so pull any extra
+	// camera angles out while the source still has them.
+	if extractAngleStreams(ctx, videoPath, probe) > 0 {
+		writeStreamsManifest(ctx, videoPath)
+	}
+
+	audio := normalizeAudioPolicy()
 	ext := strings.ToLower(filepath.Ext(videoPath))
-	if ext == ".mp4" && ffmpeg.IsStreamableMP4(probe) {
+	if ext == ".mp4" && ffmpeg.IsStreamableMP4(probe, audio) {
 		if !mp4HasFaststart(videoPath) {
 			if err := ffmpeg.ApplyFaststart(ctx, videoPath); err != nil {
 				slog.Warn("faststart failed (video still usable)", "path", videoPath, "error", err)
@@ -636,9 +777,9 @@ func ensureStreamableMP4(ctx context.Context, videoPath string) (string, error)
 
 	slog.Info("normalizing to streamable mp4",
 		"src", videoPath, "video_codec", probe.VideoCodec, "audio_codec", probe.AudioCodec,
-		"reencode_video", ffmpeg.NeedsVideoTranscode(probe), "transcode_audio", ffmpeg.NeedsAudioTranscode(probe))
+		"reencode_video", ffmpeg.NeedsVideoTranscode(probe), "transcode_audio", audio.NeedsAudioTranscode(probe))
 
-	if err := ffmpeg.NormalizeToStreamableMP4(ctx, videoPath, tmpPath); err != nil {
+	if err := ffmpeg.NormalizeToStreamableMP4(ctx, videoPath, tmpPath, audio); err != nil {
 		_ = os.Remove(tmpPath)
 		return videoPath, fmt.Errorf("ensure streamable: normalize %s: %w", videoPath, err)
 	}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRandomFile(tb testing.TB, path string, size int) {
+	tb.Helper()
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+func TestCopyFileHashed_MatchesRehash(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.mp4")
+	dst := filepath.Join(dir, "dst.mp4")
+	writeRandomFile(t, src, 1<<20+17)
+
+	hf, err := copyFileHashed(src, dst)
+	if err != nil {
+		t.Fatalf("copyFileHashed: %v", err)
+	}
+	wantHash, wantSize, err := computeFileHashAndSize(dst)
+	if err != nil {
+		t.Fatalf("computeFileHashAndSize: %v", err)
+	}
+	if hf.hash != wantHash || hf.size != wantSize {
+		t.Fatalf("got %s/%d, want %s/%d", hf.hash, hf.size, wantHash, wantSize)
+	}
+	if !hf.validFor(dst) {
+		t.Fatal("hash should be valid for the untouched copy")
+	}
+	if hf.validFor(src) {
+		t.Fatal("hash should not be valid for a different file")
+	}
+
+	// A rewrite in place (faststart writes a temp file and renames it over
+	// the original) must invalidate the hash.
+	tmp := dst + ".faststart.tmp"
+	writeRandomFile(t, tmp, 1<<20+17)
+	if err := os.Rename(tmp, dst); err != nil {
+		t.Fatal(err)
+	}
+	if hf.validFor(dst) {
+		t.Fatal("hash should be invalid after the file was replaced")
+	}
+
+	var nilFile *hashedFile
+	if nilFile.validFor(dst) {
+		t.Fatal("nil hashedFile should never be valid")
+	}
+}
+
+func TestFileHashEnabled(t *testing.T) {
+	for v, want := range map[string]bool{"": true, "true": true, "1": true, "false": false, "0": false, "no": false} {
+		t.Setenv("INGEST_FILE_HASH", v)
+		if got := fileHashEnabled(); got != want {
+			t.Errorf("INGEST_FILE_HASH=%q: got %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestVerifyAllAssetStatus_FileHashDisabled(t *testing.T) {
+	t.Setenv("INGEST_FILE_HASH", "false")
+	status := verifyAllAssetStatus(filepath.Join(t.TempDir(), "v.mp4"), "v", nil, false)
+	if status["file_hash"] != assetNotApplicable {
+		t.Fatalf("file_hash = %v, want %q", status["file_hash"], assetNotApplicable)
+	}
+}
+
+// The cross-device move used to copy the video and then read it back to hash
+// it. Hashing during the copy reads the source once.
+const benchCopySize = 64 << 20
+
+func benchmarkCopy(b *testing.B, copyAndHash func(src, dst string) error) {
+	dir := b.TempDir()
+	src := filepath.Join(dir, "src.mp4")
+	writeRandomFile(b, src, benchCopySize)
+	b.SetBytes(benchCopySize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst := filepath.Join(dir, "dst.mp4")
+		if err := copyAndHash(src, dst); err != nil {
+			b.Fatal(err)
+		}
+		_ = os.Remove(dst)
+	}
+}
+
+func BenchmarkMoveCopyThenHash(b *testing.B) {
+	benchmarkCopy(b, func(src, dst string) error {
+		if err := copyFile(src, dst); err != nil {
+			return err
+		}
+		_, _, err := computeFileHashAndSize(dst)
+		return err
+	})
+	b.ReportMetric(2*benchCopySize, "read-B/op")
+}
+
+func BenchmarkMoveCopyHashed(b *testing.B) {
+	benchmarkCopy(b, func(src, dst string) error {
+		_, err := copyFileHashed(src, dst)
+		return err
+	})
+	b.ReportMetric(benchCopySize, "read-B/op")
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"hash/fnv"
 	_ "image/jpeg"
 	"io"
 	"log/slog"
@@ -41,6 +40,10 @@ func main() {
 		os.Exit(1)
 	}
 	logWhisperStartupInfo()
+	if _, err := application.InitFFmpeg(ctx, "tile"); err != nil {
+		slog.Error("ffmpeg unavailable", "error", err)
+		os.Exit(1)
+	}
 	if conf.DatabaseRetries <= 0 {
 		conf.DatabaseRetries = 10
 	}
@@ -59,19 +62,11 @@ func main() {
 	}
 	defer dbc.Close()
 
-	// Recover orphaned jobs stuck in "processing" from previous crashes/restarts
+	// Recover orphaned jobs stuck in "processing" from previous crashes/restarts,
+	// and fail jobs that have exceeded max retry attempts so they stop wasting workers.
+	// Non-fatal - continue startup on error.
 	slog.Info("Recovering stuck ingest jobs from previous service instances")
-	if err := dbc.Queries(ctx).RecoverStuckIngestJobs(ctx); err != nil {
-		slog.Error("failed to recover stuck ingest jobs", "error", err)
-		// Non-fatal - continue startup
-	}
-
-	// Fail jobs that have exceeded max retry attempts so they stop wasting workers
-	if n, err := dbc.Queries(ctx).FailExcessiveRetryIngestJobs(ctx); err != nil {
-		slog.Error("failed to fail excessive retry ingest jobs", "error", err)
-	} else if n > 0 {
-		slog.Warn("permanently failed ingest jobs exceeding max retries", "count", n)
-	}
+	recoverIngestJobs(ctx, dbc.Queries(ctx))
 
 	// Periodically recover stuck jobs and fail excessive retries (not just on startup).
 	// This prevents long-running ffmpeg operations from permanently blocking the queue.
@@ -83,14 +78,7 @@ func main() {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				if err := dbc.Queries(ctx).RecoverStuckIngestJobs(ctx); err != nil {
-					slog.Error("periodic: failed to recover stuck ingest jobs", "error", err)
-				}
-				if n, err := dbc.Queries(ctx).FailExcessiveRetryIngestJobs(ctx); err != nil {
-					slog.Error("periodic: failed to fail excessive retry ingest jobs", "error", err)
-				} else if n > 0 {
-					slog.Warn("periodic: permanently failed ingest jobs exceeding max retries", "count", n)
-				}
+				recoverIngestJobs(ctx, dbc.Queries(ctx))
 			}
 		}
 	}()
@@ -114,6 +102,9 @@ func main() {
 		defer ticker.Stop()
 		for {
 			runAssetCatchupUnit(ctx, dbc)
+			runPHashBackfill(ctx, dbc)
+			runThumbnailPHashBackfill(ctx, dbc)
+			runAutoTagBackfill(ctx, dbc)
 			select {
 			case <-ctx.Done():
 				return
@@ -298,21 +289,14 @@ func runAssetCatchupUnit(ctx context.Context, dbc *db.DatabaseConnection) {
 
 		slog.Info("asset catchup scan", "video_id", videoID, "video_path", videoPath, "thumb_path", derefString(thumbPath), "has_hash", fileHash != nil && strings.TrimSpace(*fileHash) != "", "duration_seconds", durationSeconds)
 
-		lockID := advisoryLockID("asset-catchup", videoID)
-		conn, err := dbc.Acquire(ctx)
-		if err != nil {
-			slog.Warn("asset catchup lock acquire failed", "video_id", videoID, "error", err)
-			continue
-		}
-		q := db.New(conn)
-		acquired, err := q.TryAdvisoryLock(ctx, lockID)
+		// Skip (rather than wait for) videos another worker is writing.
+		release, acquired, err := dbc.LockVideoAssets(ctx, videoID, false)
 		if err != nil || !acquired {
 			if err != nil {
 				slog.Warn("asset catchup lock error", "video_id", videoID, "error", err)
 			} else {
 				slog.Info("asset catchup lock busy", "video_id", videoID)
 			}
-			conn.Release()
 			continue
 		}
 
@@ -344,11 +328,13 @@ func runAssetCatchupUnit(ctx context.Context, dbc *db.DatabaseConnection) {
 
 		// Probe video file first - if ffprobe can't read it, all asset generation will fail.
 		// Also store probe data if not already present.
+		var media audioOnlyMedia
 		probeResult, probeErr := ffmpeg.Probe(ctx, videoPath)
 		if probeErr != nil {
 			slog.Warn("asset catchup video unreadable", "video_id", videoID, "error", probeErr)
 			assetErrors["video_file"] = probeErr.Error()
 		} else {
+			media = audioOnlyFromProbe(probeResult)
 			// Backfill probe_data if missing
 			if pj, marshalErr := json.Marshal(probeResult.RawJSON); marshalErr == nil {
 				_ = q.UpdateVideoProbeData(ctx, &db.UpdateVideoProbeDataParams{ID: idUUID, ProbeData: videoinfo.NewProbeInfo(pj)})
@@ -356,7 +342,7 @@ func runAssetCatchupUnit(ctx context.Context, dbc *db.DatabaseConnection) {
 		}
 
 		// File hash: compute if missing
-		if fileHash == nil || strings.TrimSpace(*fileHash) == "" {
+		if fileHashEnabled() && (fileHash == nil || strings.TrimSpace(*fileHash) == "") {
 			if h, s, err := computeFileHashAndSize(videoPath); err == nil {
 				slog.Info("asset catchup computed file hash", "video_id", videoID, "file_hash", h, "file_size", s)
 				_ = q.UpdateVideoFileHashAndSize(ctx, &db.UpdateVideoFileHashAndSizeParams{ID: idUUID, FileHash: &h, FileSize: &s})
@@ -379,24 +365,42 @@ func runAssetCatchupUnit(ctx context.Context, dbc *db.DatabaseConnection) {
 				}
 			}
 
-			// Thumbnail: find existing or generate
-			if p, err := generateVideoThumbnail(ctx, videoPath, videoID, false); err == nil {
-				_ = q.UpdateVideoThumbnailPath(ctx, &db.UpdateVideoThumbnailPathParams{ID: idUUID, ThumbnailPath: p})
+			if media.AudioOnly {
+				// Audio-only: no frames to sample, so use embedded cover art
+				// (if any) as the thumbnail and skip preview/seek.
+				if p, err := generateAudioCoverThumbnail(ctx, videoPath, videoID, media, false); err != nil {
+					slog.Warn("asset catchup cover art failed", "video_id", videoID, "error", err)
+					assetErrors["thumbnail"] = err.Error()
+				} else if p != nil {
+					_ = q.UpdateVideoThumbnailPath(ctx, &db.UpdateVideoThumbnailPathParams{ID: idUUID, ThumbnailPath: p})
+				}
 			} else {
-				slog.Warn("asset catchup thumbnail failed", "video_id", videoID, "error", err)
-				assetErrors["thumbnail"] = err.Error()
-			}
+				// Thumbnail: find existing or generate
+				if p, err := generateVideoThumbnail(ctx, videoPath, videoID, false); err == nil {
+					_ = q.UpdateVideoThumbnailPath(ctx, &db.UpdateVideoThumbnailPathParams{ID: idUUID, ThumbnailPath: p})
+					storeThumbnailPHash(ctx, q, idUUID, p)
+				} else {
+					slog.Warn("asset catchup thumbnail failed", "video_id", videoID, "error", err)
+					assetErrors["thumbnail"] = err.Error()
+				}
 
-			// Preview
-			if err := generateVideoPreview(ctx, videoPath, videoID, false); err != nil {
-				slog.Warn("asset catchup preview failed", "video_id", videoID, "error", err)
-				assetErrors["preview"] = err.Error()
-			}
+				// Preview
+				if err := generateVideoPreview(ctx, videoPath, videoID, false); err != nil {
+					slog.Warn("asset catchup preview failed", "video_id", videoID, "error", err)
+					assetErrors["preview"] = err.Error()
+				}
+				if previewSpriteEnabled() {
+					if err := generateVideoPreviewSprite(ctx, videoPath, videoID, durationSeconds, false); err != nil {
+						slog.Warn("asset catchup preview sprite failed", "video_id", videoID, "error", err)
+						assetErrors["preview_sprite"] = err.Error()
+					}
+				}
 
-			// Seek sprites
-			if _, err := generateVideoSeekAssets(ctx, videoPath, videoID, durationSeconds, false); err != nil {
-				slog.Warn("asset catchup seek assets failed", "video_id", videoID, "error", err)
-				assetErrors["seek"] = err.Error()
+				// Seek sprites
+				if _, err := generateVideoSeekAssets(ctx, videoPath, videoID, durationSeconds, false); err != nil {
+					slog.Warn("asset catchup seek assets failed", "video_id", videoID, "error", err)
+					assetErrors["seek"] = err.Error()
+				}
 			}
 
 			// Waveform
@@ -407,18 +411,20 @@ func runAssetCatchupUnit(ctx context.Context, dbc *db.DatabaseConnection) {
 
 			// Ensure the canonical video is a browser-playable, faststart MP4.
 			// (Replaces the old HLS demux/transcode pipeline — playback is now a
-			// direct stream of a normalized MP4.)
-			if normalized, nErr := ensureStreamableMP4(ctx, videoPath); nErr != nil {
-				slog.Warn("asset catchup normalize failed", "video_id", videoID, "error", nErr)
-				assetErrors["video_normalize"] = nErr.Error()
-			} else if normalized != videoPath {
-				videoPath = normalized
-				_ = q.UpdateVideoPath(ctx, &db.UpdateVideoPathParams{ID: idUUID, VideoPath: &videoPath})
+			// direct stream of a normalized MP4.) Audio-only files play as-is.
+			if !media.AudioOnly {
+				if normalized, nErr := ensureStreamableMP4(ctx, videoPath); nErr != nil {
+					slog.Warn("asset catchup normalize failed", "video_id", videoID, "error", nErr)
+					assetErrors["video_normalize"] = nErr.Error()
+				} else if normalized != videoPath {
+					videoPath = normalized
+					_ = q.UpdateVideoPath(ctx, &db.UpdateVideoPathParams{ID: idUUID, VideoPath: &videoPath})
+				}
 			}
 
 			// Captions: find existing or generate via Whisper
 			if _, _, ok := findCanonicalCaptionFilePath(filepath.Dir(videoPath), videoID); !ok && whisperEnabled() {
-				if p, l, wErr := generateCaptionsWithWhisper(ctx, videoPath, videoID, filepath.Dir(videoPath)); wErr != nil {
+				if p, l, wErr := generateCaptionsWithWhisperLimited(ctx, dbc, videoPath, videoID, filepath.Dir(videoPath), whisperOptions{}); wErr != nil {
 					slog.Warn("asset catchup whisper failed", "video_id", videoID, "error", wErr)
 					assetErrors["captions"] = wErr.Error()
 				} else if iErr := ingestTranscriptFile(ctx, q, idUUID, l, p); iErr != nil {
@@ -428,38 +434,22 @@ func runAssetCatchupUnit(ctx context.Context, dbc *db.DatabaseConnection) {
 					slog.Info("asset catchup generated captions via whisper", "video_id", videoID, "lang", l)
 				}
 			}
+			exportCaptionSRTs(filepath.Dir(videoPath), videoID)
 		}
 
 		// Build final status: disk verification + error tracking
-		status := verifyAllAssetStatus(videoPath, videoID, fileHash)
-
-		if len(assetErrors) > 0 {
-			// Increment error count, store errors and timestamp
-			prevCount := 0
-			if row.AssetsStatus != nil {
-				if v, ok := row.AssetsStatus["_error_count"]; ok {
-					if f, ok := v.(float64); ok {
-						prevCount = int(f)
-					}
-				}
-			}
-			status["_error_count"] = prevCount + 1
-			status["_last_error_at"] = time.Now().UTC().Format(time.RFC3339)
-			status["_errors"] = assetErrors
+		status := verifyAllAssetStatus(videoPath, videoID, fileHash, media.AudioOnly)
+
+		if n := recordAssetErrors(status, row.AssetsStatus, assetErrors); n > 0 {
 			slog.Warn("asset catchup completed with errors",
-				"video_id", videoID, "error_count", prevCount+1, "errors", assetErrors)
-		} else {
-			// All assets OK - clear any previous error tracking
-			status["_error_count"] = 0
-			status["_errors"] = map[string]string{}
+				"video_id", videoID, "error_count", n, "errors", assetErrors)
 		}
 
 		if err := updateVideoAssetsStatus(ctx, q, videoID, status); err != nil {
 			slog.Warn("asset catchup assets_status update failed", "video_id", videoID, "error", err)
 		}
 
-		_, _ = q.AdvisoryUnlock(ctx, lockID)
-		conn.Release()
+		release()
 
 		// Small throttle to keep CPU/disk sane.
 		select {
@@ -480,7 +470,7 @@ func verifyWaveformAssets(videoPath string) bool {
 	wfDir := filepath.Join(filepath.Dir(videoPath), "waveform")
 
 	// Check for no-audio marker (videos without audio are valid)
-	if _, err := os.Stat(filepath.Join(wfDir, ".no-audio")); err == nil {
+	if hasNoAudioMarker(videoPath) {
 		return true
 	}
 
@@ -514,6 +504,7 @@ func verifyWaveformAssets(videoPath string) bool {
 }
 
 // generateVideoThumbnail generates a thumbnail for a video, optionally deleting the existing one first.
+// Embedded cover art is used when no frame can be extracted.
 func generateVideoThumbnail(ctx context.Context, videoPath, videoID string, forceRegenerate bool) (*string, error) {
 	videoDir := filepath.Dir(videoPath)
 	thumbPath := filepath.Join(videoDir, videoID+".thumbnail.jpg")
@@ -536,8 +527,14 @@ func generateVideoThumbnail(ctx context.Context, videoPath, videoID string, forc
 
 	p, err := generateThumbnail(ctx, videoPath)
 	if err != nil {
+		if cover, coverErr := coverArtFallbackThumbnail(ctx, videoPath, videoID); coverErr == nil && cover != nil {
+			slog.Info("no usable video frame, using embedded cover art as thumbnail", "video_id", videoID, "error", err)
+			pruneSourceArt(videoDir, videoID)
+			return cover, nil
+		}
 		return nil, err
 	}
+	pruneSourceArt(videoDir, videoID)
 	return &p, nil
 }
 
@@ -587,14 +584,6 @@ func generateVideoWaveform(ctx context.Context, videoPath, videoID string, durat
 	return ensureWaveformAssets(ctx, videoPath, durationSeconds)
 }
 
-func advisoryLockID(scope, id string) int64 {
-	h := fnv.New64a()
-	_, _ = h.Write([]byte(scope))
-	_, _ = h.Write([]byte(":"))
-	_, _ = h.Write([]byte(id))
-	return int64(h.Sum64())
-}
-
 func derefString(v *string) string {
 	if v == nil {
 		return ""
@@ -623,7 +612,8 @@ func logDirContents(label string, dir string) {
 }
 
 // verifyAllAssetStatus checks which generated assets exist on disk for a video.
-func verifyAllAssetStatus(videoPath, videoID string, fileHash *string) map[string]any {
+// For audio-only content, video-derived assets are reported as not applicable.
+func verifyAllAssetStatus(videoPath, videoID string, fileHash *string, audioOnly bool) map[string]any {
 	status := map[string]any{}
 	dir := filepath.Dir(videoPath)
 
@@ -632,7 +622,14 @@ func verifyAllAssetStatus(videoPath, videoID string, fileHash *string) map[strin
 	status["video_file"] = err == nil
 
 	// File hash
-	status["file_hash"] = fileHash != nil && strings.TrimSpace(*fileHash) != ""
+	switch {
+	case fileHash != nil && strings.TrimSpace(*fileHash) != "":
+		status["file_hash"] = true
+	case !fileHashEnabled():
+		status["file_hash"] = assetNotApplicable
+	default:
+		status["file_hash"] = false
+	}
 
 	// Thumbnail
 	_, err = os.Stat(filepath.Join(dir, videoID+".thumbnail.jpg"))
@@ -642,6 +639,11 @@ func verifyAllAssetStatus(videoPath, videoID string, fileHash *string) map[strin
 	_, err = os.Stat(filepath.Join(dir, videoID+".preview.mp4"))
 	status["preview"] = err == nil
 
+	// Preview sprite strip (only tracked when enabled)
+	if previewSpriteEnabled() {
+		status["preview_sprite"] = verifyPreviewSprite(videoPath, videoID)
+	}
+
 	// Seek sprites
 	if levelStatus, sErr := verifySeekAssetsDetailed(videoPath); sErr == nil {
 		status["seek"] = levelStatus
@@ -650,12 +652,19 @@ func verifyAllAssetStatus(videoPath, videoID string, fileHash *string) map[strin
 	}
 
 	// Waveform
-	status["waveform"] = verifyWaveformAssets(videoPath)
+	if hasNoAudioMarker(videoPath) {
+		status["waveform"] = assetNotApplicable
+	} else {
+		status["waveform"] = verifyWaveformAssets(videoPath)
+	}
 
 	// Captions
 	_, _, capOK := findCanonicalCaptionFilePath(dir, videoID)
 	status["captions"] = capOK
 
+	// Chapter posters
+	status["chapter_posters"] = verifyChapterPosters(videoPath)
+
 	// Faststart: MP4 moov atom at front for instant browser seek.
 	// Non-MP4 formats (WebM, MKV) don't use this structure, mark as N/A (true).
 	if strings.ToLower(filepath.Ext(videoPath)) == ".mp4" {
@@ -664,6 +673,10 @@ func verifyAllAssetStatus(videoPath, videoID string, fileHash *string) map[strin
 		status["faststart"] = true
 	}
 
+	if audioOnly {
+		markAudioOnlyAssets(status)
+	}
+
 	return status
 }
 
@@ -755,6 +768,28 @@ func startHeartbeat(ctx context.Context, q *db.Queries, jobID pgtype.UUID) conte
 	return cancel
 }
 
+// recoverIngestJobs re-queues orphaned "processing" jobs that have attempts
+// left, after the configured backoff, and permanently fails the rest.
+func recoverIngestJobs(ctx context.Context, q *db.Queries) {
+	_, policy, err := db.LoadRetryPolicies(ctx, q)
+	if err != nil {
+		slog.Warn("failed to load retry policy, using defaults", "error", err)
+	}
+	if err := q.RecoverStuckIngestJobs(ctx, &db.RecoverStuckIngestJobsParams{
+		MaxAttempts:        policy.MaxAttempts,
+		BackoffExponential: policy.Exponential(),
+		BaseSeconds:        policy.BaseSeconds,
+		CapSeconds:         policy.CapSeconds,
+	}); err != nil {
+		slog.Error("failed to recover stuck ingest jobs", "error", err)
+	}
+	if n, err := q.FailExcessiveRetryIngestJobs(ctx, policy.MaxAttempts); err != nil {
+		slog.Error("failed to fail excessive retry ingest jobs", "error", err)
+	} else if n > 0 {
+		slog.Warn("permanently failed ingest jobs exceeding max retries", "count", n, "max_attempts", policy.MaxAttempts)
+	}
+}
+
 func ingestWorker(ctx context.Context, dbc *db.DatabaseConnection, wake <-chan struct{}) {
 	q := dbc.Queries(ctx)
 
@@ -763,8 +798,14 @@ func ingestWorker(ctx context.Context, dbc *db.DatabaseConnection, wake <-chan s
 			return
 		}
 
+		// Reload the policy each wake so settings changes apply without a restart.
+		_, policy, err := db.LoadRetryPolicies(ctx, q)
+		if err != nil {
+			slog.Warn("failed to load retry policy, using defaults", "error", err)
+		}
+
 		for {
-			job, err := q.DequeueIngestJob(ctx)
+			job, err := q.DequeueIngestJob(ctx, policy.MaxAttempts)
 			if err != nil {
 				if errors.Is(err, pgx.ErrNoRows) {
 					break
@@ -797,13 +838,13 @@ func ingestWorker(ctx context.Context, dbc *db.DatabaseConnection, wake <-chan s
 					(job.SpoolDir == nil || strings.TrimSpace(*job.SpoolDir) == "")
 
 				if isRegenerationJob {
-					if err := processAssetRegenerationJob(ctx, q, job); err != nil {
+					if err := processAssetRegenerationJob(ctx, dbc, q, job); err != nil {
 						slog.Error("asset regeneration job failed", "ingest_job_id", job.IngestJobID, "error", err)
 						errMsg := err.Error()
 						_ = q.MarkIngestJobFailed(ctx, &db.MarkIngestJobFailedParams{ID: job.IngestJobID, LastError: &errMsg})
 					}
 				} else {
-					if err := processIngestJob(ctx, q, job); err != nil {
+					if err := processIngestJob(ctx, dbc, q, job); err != nil {
 						slog.Error("ingest job failed", "ingest_job_id", job.IngestJobID, "error", err)
 						errMsg := err.Error()
 						_ = q.MarkIngestJobFailed(ctx, &db.MarkIngestJobFailedParams{ID: job.IngestJobID, LastError: &errMsg})
@@ -821,14 +862,22 @@ func ingestWorker(ctx context.Context, dbc *db.DatabaseConnection, wake <-chan s
 	}
 }
 
-// processAssetRegenerationJob handles regeneration of assets for an existing video
-func processAssetRegenerationJob(ctx context.Context, q *db.Queries, job *db.DequeueIngestJobRow) error {
+// processAssetRegenerationJob handles regeneration of assets for an existing
+// video, holding the video's asset lock for the duration.
+func processAssetRegenerationJob(ctx context.Context, dbc *db.DatabaseConnection, q *db.Queries, job *db.DequeueIngestJobRow) error {
 	slog.Info("processing asset regeneration job", "ingest_job_id", job.IngestJobID, "download_job_id", job.DownloadJobID, "video_id", job.VideoID)
 
 	// VideoID is now returned directly from DequeueIngestJob
 	if !job.VideoID.Valid {
 		return errors.New("asset regeneration job has no video_id")
 	}
+	return dbc.WithVideoAssetLock(ctx, job.VideoID.String(), func() error {
+		return regenerateJobAssets(ctx, dbc, q, job)
+	})
+}
+
+// regenerateJobAssets does the work of processAssetRegenerationJob.
+func regenerateJobAssets(ctx context.Context, dbc *db.DatabaseConnection, q *db.Queries, job *db.DequeueIngestJobRow) error {
 
 	// Get the existing video by ID
 	videoRow, err := q.GetVideoByID(ctx, job.VideoID)
@@ -838,6 +887,14 @@ func processAssetRegenerationJob(ctx context.Context, q *db.Queries, job *db.Deq
 
 	videoID := videoRow.ID.String()
 
+	// Metadata-only videos have no file; only the thumbnail can be rebuilt.
+	if videoRow.MetadataOnly && (videoRow.VideoPath == nil || strings.TrimSpace(*videoRow.VideoPath) == "") {
+		if job.AssetScope == nil || strings.TrimSpace(*job.AssetScope) == "" || strings.TrimSpace(*job.AssetScope) == "thumbnail" {
+			ingestMetadataOnlyAssets(ctx, q, videoRow, true)
+		}
+		return q.MarkIngestJobSucceeded(ctx, job.IngestJobID)
+	}
+
 	// Resolve video_path: use DB value, or discover on disk if NULL
 	var videoPath string
 	if videoRow.VideoPath != nil && strings.TrimSpace(*videoRow.VideoPath) != "" {
@@ -873,8 +930,28 @@ func processAssetRegenerationJob(ctx context.Context, q *db.Queries, job *db.Deq
 	}
 	slog.Info("asset regeneration scope", "video_id", videoID, "scope", scope)
 
-	// Regenerate thumbnail
-	if scope == "all" || scope == "thumbnail" {
+	media := probeAudioOnly(ctx, videoPath)
+	if media.AudioOnly {
+		slog.Info("audio-only video, skipping preview/seek/chapter posters", "video_id", videoID)
+	}
+
+	// Regenerate thumbnail (cover art for audio-only videos)
+	if media.AudioOnly && (scope == "all" || scope == "thumbnail") {
+		if p, genErr := generateAudioCoverThumbnail(ctx, videoPath, videoID, media, true); genErr != nil {
+			slog.Warn("failed to extract cover art", "video_id", videoID, "error", genErr)
+		} else if p != nil {
+			if err := q.UpdateVideoThumbnailPath(ctx, &db.UpdateVideoThumbnailPathParams{ID: videoRow.ID, ThumbnailPath: p}); err != nil {
+				slog.Warn("failed to update thumbnail path", "video_id", videoID, "error", err)
+			}
+		}
+	} else if scope == "all" || scope == "thumbnail" {
+		if job.ThumbnailAt != nil {
+			if err := setThumbnailPin(filepath.Dir(videoPath), videoID, *job.ThumbnailAt); err != nil {
+				slog.Warn("failed to update thumbnail pin", "video_id", videoID, "thumbnail_at", *job.ThumbnailAt, "error", err)
+			} else {
+				slog.Info("updated thumbnail pin", "video_id", videoID, "thumbnail_at", *job.ThumbnailAt)
+			}
+		}
 		if p, genErr := generateVideoThumbnail(ctx, videoPath, videoID, true); genErr != nil {
 			slog.Warn("failed to generate thumbnail", "video_id", videoID, "error", genErr)
 		} else {
@@ -882,20 +959,28 @@ func processAssetRegenerationJob(ctx context.Context, q *db.Queries, job *db.Deq
 			if err := q.UpdateVideoThumbnailPath(ctx, &db.UpdateVideoThumbnailPathParams{ID: videoRow.ID, ThumbnailPath: p}); err != nil {
 				slog.Warn("failed to update thumbnail path", "video_id", videoID, "error", err)
 			}
+			storeThumbnailPHash(ctx, q, videoRow.ID, p)
 		}
 	}
 
 	// Regenerate preview
-	if scope == "all" || scope == "preview" {
+	if !media.AudioOnly && (scope == "all" || scope == "preview") {
 		if genErr := generateVideoPreview(ctx, videoPath, videoID, true); genErr != nil {
 			slog.Warn("failed to generate preview", "video_id", videoID, "error", genErr)
 		} else {
 			slog.Info("regenerated preview", "video_id", videoID)
 		}
+		if previewSpriteEnabled() {
+			if genErr := generateVideoPreviewSprite(ctx, videoPath, videoID, norm.DurationSeconds, true); genErr != nil {
+				slog.Warn("failed to generate preview sprite", "video_id", videoID, "error", genErr)
+			} else {
+				slog.Info("regenerated preview sprite", "video_id", videoID)
+			}
+		}
 	}
 
 	// Regenerate seek sprites
-	if scope == "all" || scope == "seek" {
+	if !media.AudioOnly && (scope == "all" || scope == "seek") {
 		if ok, genErr := generateVideoSeekAssets(ctx, videoPath, videoID, norm.DurationSeconds, true); genErr != nil {
 			slog.Warn("failed to generate seek assets", "video_id", videoID, "error", genErr)
 		} else if ok {
@@ -912,11 +997,29 @@ func processAssetRegenerationJob(ctx context.Context, q *db.Queries, job *db.Deq
 		}
 	}
 
+	// Re-extract chapters and regenerate their posters
+	if scope == "all" || scope == "chapters" {
+		chapters, source := resolveVideoChapters(ctx, videoPath, videoRow.Info.Chapters, norm.DurationSeconds)
+		if err := storeVideoChapters(ctx, dbc, videoRow.ID, chapters, source); err != nil {
+			slog.Warn("failed to store chapters", "video_id", videoID, "error", err)
+		} else {
+			slog.Info("re-extracted chapters", "video_id", videoID, "count", len(chapters), "source", source)
+		}
+		if !media.AudioOnly {
+			if n, genErr := generateVideoChapterPosters(ctx, videoPath, videoID, chapters, true); genErr != nil {
+				slog.Warn("failed to generate chapter posters", "video_id", videoID, "error", genErr)
+			} else {
+				slog.Info("regenerated chapter posters", "video_id", videoID, "count", n)
+			}
+		}
+	}
+
 	// Regenerate captions via Whisper
 	if scope == "all" || scope == "captions" {
 		dir := filepath.Dir(videoPath)
 		if whisperEnabled() {
-			if p, l, err := generateCaptionsWithWhisper(ctx, videoPath, videoID, dir); err != nil {
+			opts := whisperOptions{Model: derefString(job.WhisperModel), Language: derefString(job.WhisperLanguage)}
+			if p, l, err := generateCaptionsWithWhisperLimited(ctx, dbc, videoPath, videoID, dir, opts); err != nil {
 				slog.Warn("whisper caption regeneration failed", "video_id", videoID, "error", err)
 			} else {
 				if err := ingestTranscriptFile(ctx, q, videoRow.ID, l, p); err != nil {
@@ -928,18 +1031,22 @@ func processAssetRegenerationJob(ctx context.Context, q *db.Queries, job *db.Deq
 		} else {
 			slog.Info("skipping caption regeneration: whisper not enabled", "video_id", videoID)
 		}
+		exportCaptionSRTs(dir, videoID)
 	}
 
 	// Refresh the alternate-quality streams manifest. (HLS has been removed —
 	// playback is a direct stream of the normalized MP4, and quality variants are
 	// offered as direct alternate <source> files.)
 	if scope == "all" || scope == "streams" {
+		if probe, err := ffmpeg.Probe(ctx, videoPath); err == nil {
+			extractAngleStreams(ctx, videoPath, probe)
+		}
 		writeStreamsManifest(ctx, videoPath)
 	}
 
 	slog.Info("asset regeneration complete", "video_id", videoID)
 
-	if err := updateVideoAssetsStatus(ctx, q, videoID, verifyAllAssetStatus(videoPath, videoID, videoRow.FileHash)); err != nil {
+	if err := updateVideoAssetsStatus(ctx, q, videoID, verifyAllAssetStatus(videoPath, videoID, videoRow.FileHash, media.AudioOnly)); err != nil {
 		slog.Warn("failed to update assets_status after regeneration", "video_id", videoID, "error", err)
 	}
 
@@ -951,7 +1058,7 @@ func processAssetRegenerationJob(ctx context.Context, q *db.Queries, job *db.Deq
 	return q.MarkIngestJobSucceeded(ctx, job.IngestJobID)
 }
 
-func processIngestJob(ctx context.Context, q *db.Queries, job *db.DequeueIngestJobRow) error {
+func processIngestJob(ctx context.Context, dbc *db.DatabaseConnection, q *db.Queries, job *db.DequeueIngestJobRow) error {
 	// This handles normal ingest from a download job with info.json
 	if job.InfoJsonPath == nil || strings.TrimSpace(*job.InfoJsonPath) == "" {
 		return errors.New("missing info_json_path on download job")
@@ -964,7 +1071,7 @@ func processIngestJob(ctx context.Context, q *db.Queries, job *db.DequeueIngestJ
 		if job.VideoID.Valid && os.IsNotExist(err) {
 			slog.Warn("spool cleaned up but video exists - converting to asset regeneration",
 				"ingest_job_id", job.IngestJobID, "video_id", job.VideoID)
-			return processAssetRegenerationJob(ctx, q, job)
+			return processAssetRegenerationJob(ctx, dbc, q, job)
 		}
 		return fmt.Errorf("read info json: %w", err)
 	}
@@ -1148,15 +1255,32 @@ func processIngestJob(ctx context.Context, q *db.Queries, job *db.DequeueIngestJ
 		}
 	}
 
+	// SponsorBlock segments as markers (best-effort, opt-in).
+	if sponsorBlockImportEnabled() {
+		if n, err := importSponsorBlockMarkers(ctx, dbc, video.ID, videoArchivedBy, info); err != nil {
+			slog.Warn("failed to import sponsorblock segments", "video_id", video.ID, "error", err)
+		} else if n > 0 {
+			slog.Info("SponsorBlock segments imported", "video_id", video.ID, "markers", n)
+		}
+	}
+
 	// Comment ingest (best-effort). Extract from info.json "comments" array.
 	commentSource := canonicalDomain
 	if commentSource == "" {
 		commentSource = "unknown"
 	}
-	if err := ingestCommentsFromInfoJSON(ctx, q, video.ID, commentSource, b); err != nil {
+	if err := ingestCommentsFromInfoJSON(ctx, q, video.ID, commentSource, b, job.Refresh); err != nil {
 		slog.Warn("failed to ingest comments", "video_id", video.ID, "error", err)
 	}
 
+	// Everything below writes into the video's directory; wait for any
+	// catch-up or regeneration already working on it.
+	releaseAssets, _, err := dbc.LockVideoAssets(ctx, video.ID.String(), true)
+	if err != nil {
+		return fmt.Errorf("lock video assets: %w", err)
+	}
+	defer releaseAssets()
+
 	// Asset generation/regeneration logic
 	var videoPath *string
 	var thumbPath *string
@@ -1205,64 +1329,127 @@ func processIngestJob(ctx context.Context, q *db.Queries, job *db.DequeueIngestJ
 		videoID := video.ID.String()
 		slog.Info("generating video assets", "video_id", videoID, "video_path", *videoPath)
 
-		// Always ensure we have a right-sized thumbnail (don't force regenerate on normal ingest).
-		if p, genErr := generateVideoThumbnail(ctx, *videoPath, videoID, false); genErr != nil {
-			slog.Warn("failed to generate thumbnail", "video_id", videoID, "error", genErr)
-		} else {
-			thumbPath = p
-		}
+		media := probeAudioOnly(ctx, *videoPath)
 
-		// Generate a lightweight hover preview (best-effort).
-		if genErr := generateVideoPreview(ctx, *videoPath, videoID, false); genErr != nil {
-			slog.Warn("failed to generate preview", "video_id", videoID, "error", genErr)
+		// Chapters come from info.json, else from the container. Posters
+		// are generated from the same list so their indexes match.
+		chapters, chapterSource := resolveVideoChapters(ctx, *videoPath, infoVI.Chapters, norm.DurationSeconds)
+		if err := storeVideoChapters(ctx, dbc, video.ID, chapters, chapterSource); err != nil {
+			slog.Warn("failed to store chapters", "video_id", videoID, "error", err)
 		}
 
-		// Generate seek thumbnails (sprite sheets) (best-effort).
-		if _, genErr := generateVideoSeekAssets(ctx, *videoPath, videoID, norm.DurationSeconds, false); genErr != nil {
-			slog.Warn("failed to generate seek assets", "video_id", videoID, "error", genErr)
-		}
+		// Independent generators run concurrently. thumbPath and probeInfo
+		// are written by one generator each and read after Wait.
+		assets := newAssetRunner(videoID)
+		var probeInfo *videoinfo.ProbeInfo
+		if media.AudioOnly {
+			// No video stream: use embedded cover art as the thumbnail and
+			// skip frame-based assets.
+			slog.Info("audio-only video, skipping preview/seek/chapter posters", "video_id", videoID, "cover_art", media.HasCoverArt)
+			assets.Go("thumbnail", func() error {
+				p, err := generateAudioCoverThumbnail(ctx, *videoPath, videoID, media, false)
+				if err != nil {
+					return fmt.Errorf("extract cover art: %w", err)
+				}
+				if p != nil {
+					thumbPath = p
+				}
+				return nil
+			})
+		} else {
+			// Always ensure we have a right-sized thumbnail (don't force regenerate on normal ingest).
+			assets.Go("thumbnail", func() error {
+				p, err := generateVideoThumbnail(ctx, *videoPath, videoID, false)
+				if err == nil {
+					thumbPath = p
+				}
+				return err
+			})
 
-		// Generate waveform peaks (best-effort).
-		if _, genErr := generateVideoWaveform(ctx, *videoPath, videoID, norm.DurationSeconds, false); genErr != nil {
-			slog.Warn("failed to generate waveform assets", "video_id", videoID, "error", genErr)
+			// Lightweight hover preview.
+			assets.Go("preview", func() error {
+				return generateVideoPreview(ctx, *videoPath, videoID, false)
+			})
+
+			// Sprite strip alternative for hover-scrub (optional).
+			if previewSpriteEnabled() {
+				assets.Go("preview_sprite", func() error {
+					return generateVideoPreviewSprite(ctx, *videoPath, videoID, norm.DurationSeconds, false)
+				})
+			}
+
+			// Seek thumbnails (sprite sheets).
+			assets.Go("seek", func() error {
+				_, err := generateVideoSeekAssets(ctx, *videoPath, videoID, norm.DurationSeconds, false)
+				return err
+			})
+
+			// Chapter poster frames.
+			assets.Go("chapter_posters", func() error {
+				_, err := generateVideoChapterPosters(ctx, *videoPath, videoID, chapters, false)
+				return err
+			})
+
+			// Perceptual hash for near-duplicate detection (optional).
+			if phashEnabled() {
+				assets.Go("phash", func() error {
+					return storeVideoPHash(ctx, q, video.ID, *videoPath, norm.DurationSeconds)
+				})
+			}
 		}
 
+		// Waveform peaks.
+		assets.Go("waveform", func() error {
+			_, err := generateVideoWaveform(ctx, *videoPath, videoID, norm.DurationSeconds, false)
+			return err
+		})
+
 		// Captions: if missing, optionally generate with Whisper and ingest transcript.
-		dir := filepath.Dir(*videoPath)
-		capPath, lang, ok := findCanonicalCaptionFilePath(dir, video.ID.String())
-		if ok {
-			if err := ingestTranscriptFile(ctx, q, video.ID, lang, capPath); err != nil {
-				slog.Warn("failed to ingest transcript", "video_id", video.ID, "path", capPath, "error", err)
-			} else {
+		assets.Go("captions", func() error {
+			dir := filepath.Dir(*videoPath)
+			if capPath, lang, ok := findCanonicalCaptionFilePath(dir, videoID); ok {
+				if err := ingestTranscriptFile(ctx, q, video.ID, lang, capPath); err != nil {
+					return fmt.Errorf("ingest transcript %s: %w", capPath, err)
+				}
 				slog.Info("Transcript ingested", "video_id", video.ID, "lang", lang)
+				exportCaptionSRTs(dir, videoID)
+				return nil
 			}
-		} else if whisperEnabled() {
-			if p, l, err := generateCaptionsWithWhisper(ctx, *videoPath, video.ID.String(), dir); err != nil {
-				slog.Warn("whisper caption generation failed", "video_id", video.ID, "error", err)
-			} else {
-				if err := ingestTranscriptFile(ctx, q, video.ID, l, p); err != nil {
-					slog.Warn("failed to ingest whisper transcript", "video_id", video.ID, "path", p, "error", err)
-				} else {
-					slog.Info("Whisper transcript ingested", "video_id", video.ID, "lang", l)
-				}
+			if !whisperEnabled() {
+				return nil
 			}
-		}
+			p, l, err := generateCaptionsWithWhisperLimited(ctx, dbc, *videoPath, videoID, dir, whisperOptions{})
+			if err != nil {
+				return fmt.Errorf("whisper: %w", err)
+			}
+			if err := ingestTranscriptFile(ctx, q, video.ID, l, p); err != nil {
+				return fmt.Errorf("ingest whisper transcript %s: %w", p, err)
+			}
+			slog.Info("Whisper transcript ingested", "video_id", video.ID, "lang", l)
+			exportCaptionSRTs(dir, videoID)
+			return nil
+		})
 
-		// Run ffprobe to capture real stream metadata (best-effort).
-		var probeInfo *videoinfo.ProbeInfo
-		if probeResult, probeErr := ffmpeg.Probe(ctx, *videoPath); probeErr != nil {
-			slog.Warn("failed to probe video", "video_id", videoID, "error", probeErr)
-		} else {
+		// ffprobe captures real stream metadata.
+		assets.Go("video_file", func() error {
+			probeResult, err := ffmpeg.Probe(ctx, *videoPath)
+			if err != nil {
+				return err
+			}
 			if pj, marshalErr := json.Marshal(probeResult.RawJSON); marshalErr == nil {
 				probeInfo = videoinfo.NewProbeInfo(pj)
 				slog.Info("ffprobe data captured",
 					"video_id", videoID,
 					"video_streams", probeResult.VideoStreams,
 					"audio_streams", probeResult.AudioStreams,
+					"embedded_subtitles", len(probeInfo.SubtitleStreams()),
 					"codec", probeResult.VideoCodec,
 				)
 			}
-		}
+			return nil
+		})
+
+		assetErrors := assets.Wait()
 
 		// Update video with paths (including regenerated assets)
 		video, err = q.InsertVideo(ctx, &db.InsertVideoParams{
@@ -1293,10 +1480,28 @@ func processIngestJob(ctx context.Context, q *db.Queries, job *db.DequeueIngestJ
 		if err != nil {
 			slog.Error("failed to update video with permanent paths", "video_id", video.ID, "error", err)
 		}
+		if !media.AudioOnly {
+			storeThumbnailPHash(ctx, q, video.ID, thumbPath)
+		}
 
-		if err := updateVideoAssetsStatus(ctx, q, video.ID.String(), verifyAllAssetStatus(*videoPath, video.ID.String(), fileHash)); err != nil {
+		// The one assets_status write, once every generator is done.
+		status := verifyAllAssetStatus(*videoPath, video.ID.String(), fileHash, media.AudioOnly)
+		if n := recordAssetErrors(status, video.AssetsStatus, assetErrors); n > 0 {
+			slog.Warn("asset generation completed with errors",
+				"video_id", video.ID, "error_count", n, "errors", assetErrors)
+		}
+		if err := updateVideoAssetsStatus(ctx, q, video.ID.String(), status); err != nil {
 			slog.Warn("failed to update assets_status after ingest", "video_id", video.ID, "error", err)
 		}
+
+		// A full download of a metadata-only video upgrades it.
+		if video.MetadataOnly {
+			if err := q.SetVideoMetadataOnly(ctx, &db.SetVideoMetadataOnlyParams{ID: video.ID, MetadataOnly: false}); err != nil {
+				slog.Warn("failed to clear metadata-only flag", "video_id", video.ID, "error", err)
+			}
+		}
+	} else if job.MetadataOnly || video.MetadataOnly {
+		ingestMetadataOnlyAssets(ctx, q, video, job.Refresh)
 	}
 
 	// Store a revision diff when refreshing an existing video.
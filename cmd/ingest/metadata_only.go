@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// sourceArtPath returns the artwork yt-dlp saved alongside the info JSON
+// (<id>.src_thumbnail.<ext>), or "" when there is none.
+func sourceArtPath(videoDir, videoID string) string {
+	matches, _ := filepath.Glob(filepath.Join(videoDir, videoID+".src_thumbnail.*"))
+	for _, m := range matches {
+		switch strings.ToLower(filepath.Ext(m)) {
+		case ".jpg", ".jpeg", ".png", ".webp":
+			return m
+		}
+	}
+	return ""
+}
+
+// generateSourceArtThumbnail builds the thumbnail variants for a
+// metadata-only video from its downloaded source artwork. Returns nil without
+// error when no artwork was saved.
+func generateSourceArtThumbnail(ctx context.Context, videoDir, videoID string, forceRegenerate bool) (*string, error) {
+	art := sourceArtPath(videoDir, videoID)
+	if art == "" {
+		return nil, nil
+	}
+	return writeStillThumbnails(ctx, art, videoDir, videoID, 0, forceRegenerate)
+}
+
+// metadataOnlyAssetStatus is the assets_status for a video archived without
+// its media file: the thumbnail is tracked and every file-derived asset is
+// assetNotApplicable.
+func metadataOnlyAssetStatus(videoDir, videoID string) map[string]any {
+	status := map[string]any{}
+	for _, key := range []string{"video_file", "file_hash", "preview", "preview_sprite", "seek", "waveform", "captions", "chapter_posters", "faststart"} {
+		status[key] = assetNotApplicable
+	}
+	_, err := os.Stat(filepath.Join(videoDir, videoID+".thumbnail.jpg"))
+	status["thumbnail"] = err == nil
+	return status
+}
+
+// ingestMetadataOnlyAssets finishes ingest for a video that has no media
+// file: it builds the thumbnail from the saved artwork, flags the video as
+// metadata-only and records which assets do not apply.
+func ingestMetadataOnlyAssets(ctx context.Context, q *db.Queries, video *db.Video, forceRegenerate bool) {
+	videoID := video.ID.String()
+	videoDir := filepath.Join("/downloads", videoID)
+	slog.Info("metadata-only video, skipping media assets", "video_id", videoID)
+
+	if p, err := generateSourceArtThumbnail(ctx, videoDir, videoID, forceRegenerate); err != nil {
+		slog.Warn("failed to generate thumbnail from source art", "video_id", videoID, "error", err)
+	} else if p != nil {
+		if err := q.UpdateVideoThumbnailPath(ctx, &db.UpdateVideoThumbnailPathParams{ID: video.ID, ThumbnailPath: p}); err != nil {
+			slog.Warn("failed to update thumbnail path", "video_id", videoID, "error", err)
+		}
+	}
+	if !video.MetadataOnly {
+		if err := q.SetVideoMetadataOnly(ctx, &db.SetVideoMetadataOnlyParams{ID: video.ID, MetadataOnly: true}); err != nil {
+			slog.Warn("failed to flag video as metadata-only", "video_id", videoID, "error", err)
+		}
+	}
+	if err := updateVideoAssetsStatus(ctx, q, videoID, metadataOnlyAssetStatus(videoDir, videoID)); err != nil {
+		slog.Warn("failed to update assets_status for metadata-only video", "video_id", videoID, "error", err)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSourceArtPath(t *testing.T) {
+	dir := t.TempDir()
+	id := "video-1"
+	if got := sourceArtPath(dir, id); got != "" {
+		t.Fatalf("empty dir: got %q, want none", got)
+	}
+
+	// Non-image leftovers are ignored.
+	if err := os.WriteFile(filepath.Join(dir, id+".src_thumbnail.part"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := sourceArtPath(dir, id); got != "" {
+		t.Fatalf("non-image: got %q, want none", got)
+	}
+
+	want := filepath.Join(dir, id+".src_thumbnail.webp")
+	if err := os.WriteFile(want, []byte("img"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := sourceArtPath(dir, id); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMetadataOnlyAssetStatus(t *testing.T) {
+	dir := t.TempDir()
+	id := "video-1"
+
+	status := metadataOnlyAssetStatus(dir, id)
+	if status["thumbnail"] != false {
+		t.Errorf("thumbnail without a file = %v, want false", status["thumbnail"])
+	}
+	for _, key := range []string{"video_file", "preview", "seek", "waveform", "faststart"} {
+		if status[key] != assetNotApplicable {
+			t.Errorf("%s = %v, want %q", key, status[key], assetNotApplicable)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, id+".thumbnail.jpg"), []byte("jpg"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if status := metadataOnlyAssetStatus(dir, id); status["thumbnail"] != true {
+		t.Errorf("thumbnail with a file = %v, want true", status["thumbnail"])
+	}
+}
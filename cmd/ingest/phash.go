@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/phash"
+)
+
+// phashEnabled reports whether perceptual hashing runs during ingest.
+// Off by default: decoding sample frames costs a pass over the whole file.
+func phashEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("PHASH_ENABLED"))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// computeVideoPHash samples PHASH_FRAMES (default 16) frames evenly across the
+// video and returns the combined hash plus the per-frame hashes.
+func computeVideoPHash(ctx context.Context, videoPath string, durationSeconds *int32) (uint64, []uint64, error) {
+	dur, err := resolveDurationSeconds(ctx, videoPath, durationSeconds)
+	if err != nil {
+		return 0, nil, err
+	}
+	frames, err := ffmpeg.SampleGrayFrames(ctx, videoPath, dur, envInt("PHASH_FRAMES", 16), phash.Size)
+	if err != nil {
+		return 0, nil, err
+	}
+	hashes := make([]uint64, 0, len(frames))
+	for _, f := range frames {
+		h, err := phash.Hash(f)
+		if err != nil {
+			return 0, nil, fmt.Errorf("phash frame: %w", err)
+		}
+		hashes = append(hashes, h)
+	}
+	return phash.Combine(hashes), hashes, nil
+}
+
+// storeVideoPHash computes and persists the perceptual hash for one video.
+func storeVideoPHash(ctx context.Context, q db.Querier, videoID pgtype.UUID, videoPath string, durationSeconds *int32) error {
+	combined, frames, err := computeVideoPHash(ctx, videoPath, durationSeconds)
+	if err != nil {
+		return err
+	}
+	// BIGINT columns hold the raw 64-bit patterns.
+	h := int64(combined)
+	stored := make([]int64, len(frames))
+	for i, f := range frames {
+		stored[i] = int64(f)
+	}
+	if err := q.UpdateVideoPHash(ctx, &db.UpdateVideoPHashParams{ID: videoID, Phash: &h, PhashFrames: stored}); err != nil {
+		return fmt.Errorf("store phash: %w", err)
+	}
+	// Pairs feed the near-duplicates page; a failure only hides this video
+	// there until it is hashed again.
+	if err := q.DeleteVideoPHashPairs(ctx, videoID); err != nil {
+		slog.Warn("failed to clear phash pairs", "video_id", videoID.String(), "error", err)
+	} else if err := q.InsertVideoPHashPairs(ctx, &db.InsertVideoPHashPairsParams{VideoID: videoID, MaxDistance: phash.MaxPairDistance}); err != nil {
+		slog.Warn("failed to store phash pairs", "video_id", videoID.String(), "error", err)
+	}
+	slog.Info("perceptual hash stored", "video_id", videoID.String(), "phash", fmt.Sprintf("%016x", combined), "frames", len(frames))
+	return nil
+}
+
+// storeThumbnailPHash hashes a video's thumbnail and persists it. Callers
+// pass only thumbnails taken from a frame of the video: cover art and
+// platform art of audio-only and metadata-only videos would suggest false
+// duplicates. Failures are logged only: the hash just feeds duplicate
+// suggestions. It reports whether a hash was stored.
+func storeThumbnailPHash(ctx context.Context, q db.Querier, videoID pgtype.UUID, thumbPath *string) bool {
+	if thumbPath == nil {
+		return false
+	}
+	h, err := computeThumbnailPHash(*thumbPath)
+	if err != nil {
+		slog.Warn("thumbnail phash failed", "video_id", videoID.String(), "path", *thumbPath, "error", err)
+		return false
+	}
+	if h == nil {
+		return false
+	}
+	if err := q.UpdateVideoThumbnailPHash(ctx, &db.UpdateVideoThumbnailPHashParams{ID: videoID, ThumbnailPhash: h}); err != nil {
+		slog.Warn("failed to store thumbnail phash", "video_id", videoID.String(), "error", err)
+		return false
+	}
+	return true
+}
+
+// runThumbnailPHashBackfill hashes the thumbnails of videos ingested before
+// thumbnail hashing existed. Unlike the video hash it is cheap and always on.
+// Thumbnails that are missing or cannot be hashed are recorded so they don't
+// crowd out older videos on the next tick.
+func runThumbnailPHashBackfill(ctx context.Context, dbc *db.DatabaseConnection) {
+	q := dbc.Queries(ctx)
+	rows, err := q.ListVideosNeedingThumbnailPHash(ctx, &db.ListVideosNeedingThumbnailPHashParams{
+		RetryBefore: pgtype.Timestamptz{Time: time.Now().Add(-phashRetryAfter), Valid: true},
+		MaxCount:    100,
+	})
+	if err != nil {
+		slog.Warn("thumbnail phash backfill query failed", "error", err)
+		return
+	}
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			return
+		}
+		if !storeThumbnailPHash(ctx, q, row.ID, row.ThumbnailPath) {
+			if err := q.MarkVideoThumbnailPHashAttempted(ctx, row.ID); err != nil {
+				slog.Warn("failed to record thumbnail phash attempt", "video_id", row.ID.String(), "error", err)
+			}
+		}
+	}
+}
+
+// phashRetryAfter is how long the backfill leaves a video alone after a
+// failed hash attempt.
+const phashRetryAfter = 24 * time.Hour
+
+// runPHashBackfill hashes videos ingested before PHASH_ENABLED was turned on.
+// Audio-only videos and failed attempts are recorded so they don't crowd out
+// older videos on the next tick.
+func runPHashBackfill(ctx context.Context, dbc *db.DatabaseConnection) {
+	if !phashEnabled() {
+		return
+	}
+	q := dbc.Queries(ctx)
+	rows, err := q.ListVideosNeedingPHash(ctx, &db.ListVideosNeedingPHashParams{
+		RetryBefore: pgtype.Timestamptz{Time: time.Now().Add(-phashRetryAfter), Valid: true},
+		MaxCount:    25,
+	})
+	if err != nil {
+		slog.Warn("phash backfill query failed", "error", err)
+		return
+	}
+	for _, row := range rows {
+		if ctx.Err() != nil {
+			return
+		}
+		videoPath := strings.TrimSpace(derefString(row.VideoPath))
+		if videoPath == "" {
+			continue
+		}
+		if probeAudioOnly(ctx, videoPath).AudioOnly {
+			markPHashAttempted(ctx, q, row.ID)
+			continue
+		}
+		if err := storeVideoPHash(ctx, q, row.ID, videoPath, row.DurationSeconds); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("phash backfill failed", "video_id", row.ID.String(), "error", err)
+			markPHashAttempted(ctx, q, row.ID)
+		}
+	}
+}
+
+// markPHashAttempted holds a video back from the backfill for phashRetryAfter.
+func markPHashAttempted(ctx context.Context, q db.Querier, videoID pgtype.UUID) {
+	if err := q.MarkVideoPHashAttempted(ctx, videoID); err != nil {
+		slog.Warn("failed to record phash attempt", "video_id", videoID.String(), "error", err)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+const previewSpriteFormatV1 = "rewind-preview-sprite-v1"
+
+// previewSpriteManifest describes the hover-scrub sprite strip: Frames tiles
+// of FrameWidth x FrameHeight laid out left to right in Image, one every
+// IntervalSeconds from the start of the video.
+type previewSpriteManifest struct {
+	Format          string  `json:"format"`
+	Image           string  `json:"image"`
+	Frames          int     `json:"frames"`
+	FrameWidth      int     `json:"frame_width"`
+	FrameHeight     int     `json:"frame_height"`
+	IntervalSeconds float64 `json:"interval_seconds"`
+}
+
+// previewSpriteEnabled reports whether ingest also builds the sprite strip
+// preview alongside the MP4 preview (PREVIEW_SPRITE_ENABLED, default off).
+func previewSpriteEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("PREVIEW_SPRITE_ENABLED"))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+func previewSpritePaths(videoPath, videoID string) (image, manifest string) {
+	dir := filepath.Dir(videoPath)
+	return filepath.Join(dir, videoID+".preview-sprite.jpg"), filepath.Join(dir, videoID+".preview-sprite.json")
+}
+
+// generateVideoPreviewSprite builds a single horizontal strip of
+// PREVIEW_SPRITE_FRAMES (default 10) frames sampled evenly across the video,
+// plus its manifest. Existing output is kept unless forceRegenerate is set.
+func generateVideoPreviewSprite(ctx context.Context, videoPath, videoID string, durationSeconds *int32, forceRegenerate bool) error {
+	image, manifestPath := previewSpritePaths(videoPath, videoID)
+	if forceRegenerate {
+		_ = os.Remove(image)
+		_ = os.Remove(manifestPath)
+	} else if verifyPreviewSprite(videoPath, videoID) {
+		return nil
+	}
+
+	dur, err := resolveDurationSeconds(ctx, videoPath, durationSeconds)
+	if err != nil {
+		return err
+	}
+	frames := envInt("PREVIEW_SPRITE_FRAMES", 10)
+	if frames < 2 {
+		frames = 2
+	}
+	if frames > 50 {
+		frames = 50
+	}
+
+	// One row of the seek-sheet tiling, with the interval chosen so the whole
+	// video fits in the strip.
+	lvl := seekLevelSpec{
+		Name:            "preview",
+		IntervalSeconds: dur / float64(frames),
+		ThumbWidth:      240,
+		ThumbHeight:     135,
+		Cols:            frames,
+		Rows:            1,
+	}
+	result := ffmpeg.RunCapture(ctx, videoPath, image,
+		ffmpeg.Filter(seekTileFilter(lvl)),
+		ffmpeg.Frames(1),
+		ffmpeg.Quality(4),
+	)
+	if result.Logs != "" {
+		slog.Info("ffmpeg preview sprite output", "video_id", videoID, "logs", result.Logs)
+	}
+	if result.Err != nil {
+		_ = os.Remove(image)
+		return fmt.Errorf("ffmpeg preview sprite: %w", result.Err)
+	}
+
+	m := previewSpriteManifest{
+		Format:          previewSpriteFormatV1,
+		Image:           "preview-sprite.jpg",
+		Frames:          frames,
+		FrameWidth:      lvl.ThumbWidth,
+		FrameHeight:     lvl.ThumbHeight,
+		IntervalSeconds: lvl.IntervalSeconds,
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(manifestPath, b, 0644); err != nil {
+		return fmt.Errorf("write preview sprite manifest: %w", err)
+	}
+	return nil
+}
+
+// verifyPreviewSprite reports whether the sprite strip and a readable
+// manifest for it exist.
+func verifyPreviewSprite(videoPath, videoID string) bool {
+	image, manifestPath := previewSpritePaths(videoPath, videoID)
+	if _, err := os.Stat(image); err != nil {
+		return false
+	}
+	b, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return false
+	}
+	var m previewSpriteManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return false
+	}
+	return m.Format == previewSpriteFormatV1 && m.Frames > 0
+}
@@ -188,24 +188,27 @@ func ensureSeekAssets(ctx context.Context, videoPath string, durationSeconds *in
 
 	// Check which levels currently exist
 	existingLevels := make(map[string]bool)
+	existingSpecs := make(map[string]seekLevelSpec)
 	if m, err := loadSeekManifest(manifestPath); err == nil && m.Format == seekFormatV1 {
 		// Manifest exists and is correct format - check individual levels
 		detailed, err := verifySeekAssetsDetailed(videoPath)
 		if err == nil {
 			existingLevels = detailed
 		}
+		for _, lvl := range m.Levels {
+			existingSpecs[lvl.Name] = lvl
+		}
 	}
 
-	// Determine which levels need to be generated
-	levelsToGenerate := []seekLevelSpec{}
+	// If all levels exist, we're done
+	missing := false
 	for _, lvl := range levels {
 		if !existingLevels[lvl.Name] {
-			levelsToGenerate = append(levelsToGenerate, lvl)
+			missing = true
+			break
 		}
 	}
-
-	// If all levels exist, we're done
-	if len(levelsToGenerate) == 0 {
+	if !missing {
 		return false, nil
 	}
 
@@ -214,6 +217,32 @@ func ensureSeekAssets(ctx context.Context, videoPath string, durationSeconds *in
 		return false, err
 	}
 
+	// Levels already on disk keep the spec they were generated with; missing
+	// ones are sized so the whole set fits the storage budget.
+	for i, lvl := range levels {
+		if old, ok := existingSpecs[lvl.Name]; ok && existingLevels[lvl.Name] {
+			levels[i] = old
+		}
+	}
+	budget := seekBudgetBytes()
+	fitted := fitSeekLevelsToBudget(levels, dur, budget, existingLevels)
+	for i := range fitted {
+		if fitted[i].IntervalSeconds != levels[i].IntervalSeconds {
+			slog.Info("seek level interval widened to fit storage budget",
+				"video", videoPath, "level", fitted[i].Name,
+				"interval_seconds", fitted[i].IntervalSeconds, "budget_bytes", budget)
+		}
+	}
+	levels = fitted
+
+	// Determine which levels need to be generated
+	levelsToGenerate := []seekLevelSpec{}
+	for _, lvl := range levels {
+		if !existingLevels[lvl.Name] {
+			levelsToGenerate = append(levelsToGenerate, lvl)
+		}
+	}
+
 	// Generate only missing levels (incremental approach)
 	for _, lvl := range levelsToGenerate {
 		if !reSeekLevelSafe.MatchString(lvl.Name) {
@@ -265,10 +294,11 @@ func ensureSeekAssets(ctx context.Context, videoPath string, durationSeconds *in
 	return true, nil
 }
 
-func runFFmpegSeekSheets(ctx context.Context, videoPath string, lvl seekLevelSpec, outPattern string) error {
-	// Build complex filter chain: fps → scale → crop → tile
-	// Note: Scale with force_original_aspect_ratio=increase + crop ensures exact dimensions
-	vf := fmt.Sprintf(
+// seekTileFilter builds the filter chain that samples one frame every
+// IntervalSeconds and tiles them into Cols x Rows sheets: fps → scale → crop → tile.
+// Scale with force_original_aspect_ratio=increase + crop ensures exact dimensions.
+func seekTileFilter(lvl seekLevelSpec) string {
+	return fmt.Sprintf(
 		"fps=1/%g,scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,tile=%dx%d",
 		lvl.IntervalSeconds,
 		lvl.ThumbWidth,
@@ -278,9 +308,11 @@ func runFFmpegSeekSheets(ctx context.Context, videoPath string, lvl seekLevelSpe
 		lvl.Cols,
 		lvl.Rows,
 	)
+}
 
+func runFFmpegSeekSheets(ctx context.Context, videoPath string, lvl seekLevelSpec, outPattern string) error {
 	result := ffmpeg.RunCapture(ctx, videoPath, outPattern,
-		ffmpeg.Filter(vf),
+		ffmpeg.Filter(seekTileFilter(lvl)),
 		ffmpeg.Quality(4),
 		ffmpeg.ExtraArgs("-start_number", "0"),
 	)
@@ -367,7 +399,7 @@ func resolveDurationSeconds(ctx context.Context, videoPath string, durationSecon
 		return float64(*durationSeconds), nil
 	}
 	// Fallback to ffprobe.
-	cmd := exec.CommandContext(ctx, "ffprobe",
+	cmd := exec.CommandContext(ctx, ffmpeg.FFprobePath(),
 		"-v", "error",
 		"-show_entries", "format=duration",
 		"-of", "default=noprint_wrappers=1:nokey=1",
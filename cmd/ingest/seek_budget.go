@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultSeekBudgetMB is the per-video seek sprite storage target when
+// SEEK_SPRITE_BUDGET_MB is unset.
+const defaultSeekBudgetMB = 100
+
+// seekBytesPerPixel is a rough size for a seek thumbnail once tiled into a
+// JPEG sheet at the quality runFFmpegSeekSheets uses. It only needs to be close
+// enough to stop long videos producing runaway sprite sets.
+const seekBytesPerPixel = 0.3
+
+// seekBudgetBytes returns the seek sprite storage budget per video from
+// SEEK_SPRITE_BUDGET_MB (default 100). 0 disables the budget.
+func seekBudgetBytes() int64 {
+	v := strings.TrimSpace(os.Getenv("SEEK_SPRITE_BUDGET_MB"))
+	mb := defaultSeekBudgetMB
+	if v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return int64(defaultSeekBudgetMB) << 20
+		}
+		mb = n
+	}
+	return int64(mb) << 20
+}
+
+// seekLevelFrames is the number of thumbnails a level samples over the video.
+func seekLevelFrames(lvl seekLevelSpec, durationSeconds float64) int {
+	if lvl.IntervalSeconds <= 0 || durationSeconds <= 0 {
+		return 0
+	}
+	return int(math.Ceil(durationSeconds / lvl.IntervalSeconds))
+}
+
+// estimateSeekLevelBytes approximates the on-disk size of a level's sheets.
+func estimateSeekLevelBytes(lvl seekLevelSpec, durationSeconds float64) float64 {
+	return float64(seekLevelFrames(lvl, durationSeconds)) * float64(lvl.ThumbWidth*lvl.ThumbHeight) * seekBytesPerPixel
+}
+
+func estimateSeekBytes(levels []seekLevelSpec, durationSeconds float64) float64 {
+	var total float64
+	for _, lvl := range levels {
+		total += estimateSeekLevelBytes(lvl, durationSeconds)
+	}
+	return total
+}
+
+// fitSeekLevelsToBudget widens level intervals until the estimated sprite
+// storage for the whole video fits in budgetBytes. The costliest level is
+// doubled first, so the finest levels give way before the coarse ones. A level
+// stops widening once it fits on a single sheet, and levels in pinned
+// (already on disk) keep their spec. The budget may still be exceeded when no
+// level can give way. The input slice is not modified.
+func fitSeekLevelsToBudget(levels []seekLevelSpec, durationSeconds float64, budgetBytes int64, pinned map[string]bool) []seekLevelSpec {
+	out := append([]seekLevelSpec(nil), levels...)
+	if budgetBytes <= 0 || durationSeconds <= 0 {
+		return out
+	}
+	for estimateSeekBytes(out, durationSeconds) > float64(budgetBytes) {
+		costliest := -1
+		var costliestBytes float64
+		for i, lvl := range out {
+			if pinned[lvl.Name] || seekLevelFrames(lvl, durationSeconds) <= lvl.Cols*lvl.Rows {
+				continue
+			}
+			if b := estimateSeekLevelBytes(lvl, durationSeconds); b > costliestBytes {
+				costliest, costliestBytes = i, b
+			}
+		}
+		if costliest < 0 {
+			break
+		}
+		out[costliest].IntervalSeconds *= 2
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package main
+
+import "testing"
+
+func TestFitSeekLevelsToBudget(t *testing.T) {
+	const tenHours = 10 * 3600.0
+	levels := append([]seekLevelSpec(nil), seekBaseLevels...)
+
+	t.Run("short video untouched", func(t *testing.T) {
+		got := fitSeekLevelsToBudget(levels, 600, 100<<20, nil)
+		for i := range got {
+			if got[i] != levels[i] {
+				t.Errorf("level %s changed: %+v", got[i].Name, got[i])
+			}
+		}
+	})
+
+	t.Run("long video fits budget", func(t *testing.T) {
+		budget := int64(20 << 20)
+		if estimateSeekBytes(levels, tenHours) <= float64(budget) {
+			t.Fatal("test setup: base levels already fit")
+		}
+		got := fitSeekLevelsToBudget(levels, tenHours, budget, nil)
+		if est := estimateSeekBytes(got, tenHours); est > float64(budget) {
+			t.Errorf("estimate %.0f bytes exceeds budget %d", est, budget)
+		}
+		fine := got[2]
+		if fine.IntervalSeconds <= levels[2].IntervalSeconds {
+			t.Errorf("fine interval = %g, want widened", fine.IntervalSeconds)
+		}
+		if got[0].IntervalSeconds != levels[0].IntervalSeconds {
+			t.Errorf("coarse interval = %g, want unchanged", got[0].IntervalSeconds)
+		}
+		if levels[2].IntervalSeconds != 1 {
+			t.Error("input levels were modified")
+		}
+	})
+
+	t.Run("pinned levels keep their spec", func(t *testing.T) {
+		got := fitSeekLevelsToBudget(levels, tenHours, 20<<20, map[string]bool{"fine": true})
+		if got[2].IntervalSeconds != 1 {
+			t.Errorf("pinned fine interval = %g, want 1", got[2].IntervalSeconds)
+		}
+	})
+
+	t.Run("disabled budget", func(t *testing.T) {
+		got := fitSeekLevelsToBudget(levels, tenHours, 0, nil)
+		if got[2].IntervalSeconds != 1 {
+			t.Errorf("fine interval = %g, want 1 with budget disabled", got[2].IntervalSeconds)
+		}
+	})
+
+	t.Run("never below one sheet", func(t *testing.T) {
+		got := fitSeekLevelsToBudget(levels, tenHours, 1, nil)
+		for _, lvl := range got {
+			if n := seekLevelFrames(lvl, tenHours); n < lvl.Cols*lvl.Rows/2 {
+				t.Errorf("level %s stretched to %d frames", lvl.Name, n)
+			}
+		}
+	})
+}
+
+func TestSeekBudgetBytes(t *testing.T) {
+	for _, tc := range []struct {
+		env  string
+		want int64
+	}{
+		{"", 100 << 20},
+		{"0", 0},
+		{"25", 25 << 20},
+		{"junk", 100 << 20},
+		{"-5", 100 << 20},
+	} {
+		t.Setenv("SEEK_SPRITE_BUDGET_MB", tc.env)
+		if got := seekBudgetBytes(); got != tc.want {
+			t.Errorf("SEEK_SPRITE_BUDGET_MB=%q: got %d, want %d", tc.env, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/internal/sponsorblock"
+)
+
+// sponsorBlockImportEnabled reports whether ingest stores SponsorBlock
+// segments as markers (SPONSORBLOCK_IMPORT). Off by default; the video page
+// still shows segments fetched live when no import exists.
+func sponsorBlockImportEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("SPONSORBLOCK_IMPORT"))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// sponsorBlockImportCategories returns the segment categories to import,
+// from the comma-separated SPONSORBLOCK_IMPORT_CATEGORIES, defaulting to
+// sponsorblock.ImportCategories.
+func sponsorBlockImportCategories() []string {
+	var out []string
+	for _, c := range strings.Split(os.Getenv("SPONSORBLOCK_IMPORT_CATEGORIES"), ",") {
+		if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+			out = append(out, c)
+		}
+	}
+	if len(out) == 0 {
+		return sponsorblock.ImportCategories
+	}
+	return out
+}
+
+// sponsorBlockVideoID returns the YouTube video id SponsorBlock knows the
+// video by, or "" when the source isn't YouTube.
+func sponsorBlockVideoID(info ytdlpInfo) string {
+	if !strings.EqualFold(info.ExtractorKey, "youtube") && !strings.EqualFold(info.Extractor, "youtube") {
+		return ""
+	}
+	return strings.TrimSpace(info.ID)
+}
+
+// importSponsorBlockMarkers replaces a video's SponsorBlock markers with the
+// segments SponsorBlock currently has for it, owned by createdBy. Markers
+// users placed are never touched, and on a fetch error the previous import
+// is kept. Returns the number of markers stored; non-YouTube sources store
+// none.
+func importSponsorBlockMarkers(ctx context.Context, dbc *db.DatabaseConnection, videoID, createdBy pgtype.UUID, info ytdlpInfo) (int, error) {
+	ytID := sponsorBlockVideoID(info)
+	if ytID == "" {
+		return 0, nil
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	segs, err := sponsorblock.NewClient(os.Getenv("SPONSORBLOCK_BASE_URL")).GetSkipSegments(fetchCtx, sponsorblock.SkipSegmentsParams{
+		VideoID:     ytID,
+		Categories:  sponsorBlockImportCategories(),
+		ActionTypes: []string{"skip", "mute"},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("fetch segments: %w", err)
+	}
+
+	tx, err := dbc.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+	qtx := dbc.Queries(ctx).WithTx(tx)
+
+	if err := qtx.DeleteMarkersByVideoSource(ctx, &db.DeleteMarkersByVideoSourceParams{
+		VideoID: videoID,
+		Source:  db.MarkerSourceSponsorblock,
+	}); err != nil {
+		return 0, fmt.Errorf("delete previous import: %w", err)
+	}
+	n := 0
+	for _, seg := range segs {
+		if len(seg.Segment) < 2 || seg.Segment[0] < 0 {
+			continue
+		}
+		if _, err := qtx.CreateMarker(ctx, sponsorblock.SegmentMarkerParams(videoID, createdBy, seg)); err != nil {
+			return 0, fmt.Errorf("create marker: %w", err)
+		}
+		n++
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"thirdcoast.systems/rewind/internal/sponsorblock"
+)
+
+func TestSponsorBlockVideoID(t *testing.T) {
+	cases := []struct {
+		info ytdlpInfo
+		want string
+	}{
+		{ytdlpInfo{ID: "dQw4w9WgXcQ", ExtractorKey: "Youtube", Extractor: "youtube"}, "dQw4w9WgXcQ"},
+		{ytdlpInfo{ID: "dQw4w9WgXcQ", Extractor: "youtube"}, "dQw4w9WgXcQ"},
+		{ytdlpInfo{ID: "123456", ExtractorKey: "Vimeo", Extractor: "vimeo"}, ""},
+		{ytdlpInfo{ID: "PL123", ExtractorKey: "YoutubeTab", Extractor: "youtube:tab"}, ""},
+		{ytdlpInfo{ID: "upload"}, ""},
+	}
+	for _, tc := range cases {
+		if got := sponsorBlockVideoID(tc.info); got != tc.want {
+			t.Errorf("sponsorBlockVideoID(%+v) = %q, want %q", tc.info, got, tc.want)
+		}
+	}
+}
+
+func TestSponsorBlockImportCategories(t *testing.T) {
+	t.Setenv("SPONSORBLOCK_IMPORT_CATEGORIES", "")
+	if got := sponsorBlockImportCategories(); !slices.Equal(got, sponsorblock.ImportCategories) {
+		t.Errorf("default categories = %v, want %v", got, sponsorblock.ImportCategories)
+	}
+	t.Setenv("SPONSORBLOCK_IMPORT_CATEGORIES", " Sponsor, ,outro ")
+	if got := sponsorBlockImportCategories(); !slices.Equal(got, []string{"sponsor", "outro"}) {
+		t.Errorf("categories = %v, want [sponsor outro]", got)
+	}
+}
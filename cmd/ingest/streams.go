@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
@@ -10,6 +9,7 @@ import (
 	"strings"
 
 	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
 )
 
 // isFormatSpecificDownload checks if a download used -f to request a specific format.
@@ -104,8 +104,9 @@ func normalizeStreamFileInPlace(ctx context.Context, path string) {
 		slog.Warn("merge format: probe stream file failed", "path", path, "error", err)
 		return
 	}
+	audio := normalizeAudioPolicy()
 	ext := strings.ToLower(filepath.Ext(path))
-	if ext == ".mp4" && ffmpeg.IsStreamableMP4(probe) {
+	if ext == ".mp4" && ffmpeg.IsStreamableMP4(probe, audio) {
 		if err := ffmpeg.ApplyFaststart(ctx, path); err != nil {
 			slog.Warn("merge format: faststart failed", "path", path, "error", err)
 		}
@@ -114,7 +115,7 @@ func normalizeStreamFileInPlace(ctx context.Context, path string) {
 
 	mp4Path := strings.TrimSuffix(path, filepath.Ext(path)) + ".mp4"
 	tmpPath := mp4Path + ".normalize.tmp.mp4"
-	if err := ffmpeg.NormalizeToStreamableMP4(ctx, path, tmpPath); err != nil {
+	if err := ffmpeg.NormalizeToStreamableMP4(ctx, path, tmpPath, audio); err != nil {
 		slog.Warn("merge format: normalize stream file failed (keeping original)", "path", path, "error", err)
 		_ = os.Remove(tmpPath)
 		return
@@ -132,64 +133,59 @@ func normalizeStreamFileInPlace(ctx context.Context, path string) {
 	}
 }
 
-// StreamsManifest describes additional downloaded stream files.
-// Written to streams/manifest.json by ingest, read by the web UI.
-type StreamsManifest struct {
-	Streams []StreamEntry `json:"streams"`
-}
-
-// StreamEntry describes one downloaded alternate-quality stream file.
-type StreamEntry struct {
-	Filename string `json:"filename"`
-	Width    int    `json:"width"`
-	Height   int    `json:"height"`
-	Codec    string `json:"codec"`
-}
-
-// writeStreamsManifest scans the streams/ directory for video files, probes each,
-// and writes a manifest.json with resolution info for the web UI.
-func writeStreamsManifest(ctx context.Context, mainVideoPath string) {
-	permanentDir := filepath.Dir(mainVideoPath)
-	streamsDir := filepath.Join(permanentDir, "streams")
-
-	entries, err := os.ReadDir(streamsDir)
-	if err != nil {
-		return // no streams dir
+// extractAngleStreams writes each video stream after the first of a
+// multi-angle file to streams/angle<N>.mp4, since normalization keeps only the
+// first. Angles already extracted are left alone. It returns how many angle
+// files exist afterwards.
+func extractAngleStreams(ctx context.Context, videoPath string, probe *ffmpeg.ProbeResult) int {
+	if probe == nil || len(probe.Angles) < 2 {
+		return 0
+	}
+	streamsDir := filepath.Join(filepath.Dir(videoPath), "streams")
+	if err := os.MkdirAll(streamsDir, 0o755); err != nil {
+		slog.Warn("angles: failed to create streams dir", "error", err)
+		return 0
 	}
 
-	var manifest StreamsManifest
-	for _, e := range entries {
-		if e.IsDir() || e.Name() == "manifest.json" {
+	n := 0
+	for i, angle := range probe.Angles[1:] {
+		destPath := filepath.Join(streamsDir, fmt.Sprintf("%s%d.mp4", videoinfo.AngleFilePrefix, i+2))
+		if _, err := os.Stat(destPath); err == nil {
+			n++
 			continue
 		}
-		ext := strings.ToLower(filepath.Ext(e.Name()))
-		if ext != ".mp4" && ext != ".mkv" && ext != ".webm" && ext != ".mov" {
+		tmpPath := destPath + ".tmp.mp4"
+		if err := ffmpeg.ExtractAngleToStreamableMP4(ctx, videoPath, tmpPath, probe, angle, normalizeAudioPolicy()); err != nil {
+			slog.Warn("angles: extract failed", "path", videoPath, "stream", angle.Index, "error", err)
+			_ = os.Remove(tmpPath)
 			continue
 		}
-		fullPath := filepath.Join(streamsDir, e.Name())
-		probe, err := ffmpeg.Probe(ctx, fullPath)
-		if err != nil {
-			slog.Warn("streams manifest: failed to probe", "file", e.Name(), "error", err)
+		if err := os.Rename(tmpPath, destPath); err != nil {
+			slog.Warn("angles: rename failed", "tmp", tmpPath, "dest", destPath, "error", err)
+			_ = os.Remove(tmpPath)
 			continue
 		}
-		manifest.Streams = append(manifest.Streams, StreamEntry{
-			Filename: e.Name(),
-			Width:    probe.Width,
-			Height:   probe.Height,
-			Codec:    probe.VideoCodec,
-		})
+		slog.Info("angles: extracted angle", "dest", destPath, "codec", angle.Codec, "width", angle.Width, "height", angle.Height)
+		n++
 	}
+	return n
+}
 
-	data, err := json.MarshalIndent(manifest, "", "  ")
+// writeStreamsManifest scans the streams/ directory for video files, probes each,
+// and writes a manifest.json with resolution info for the web UI.
+func writeStreamsManifest(ctx context.Context, mainVideoPath string) {
+	videoDir := filepath.Dir(mainVideoPath)
+	manifest, failed, err := videoinfo.ScanStreams(ctx, videoDir)
 	if err != nil {
-		slog.Warn("streams manifest: failed to marshal", "error", err)
+		slog.Warn("streams manifest: failed to scan", "dir", videoDir, "error", err)
 		return
 	}
-
-	manifestPath := filepath.Join(streamsDir, "manifest.json")
-	if err := os.WriteFile(manifestPath, data, 0o644); err != nil {
-		slog.Warn("streams manifest: failed to write", "path", manifestPath, "error", err)
+	for _, name := range failed {
+		slog.Warn("streams manifest: failed to probe", "file", name)
+	}
+	if err := manifest.Write(videoDir); err != nil {
+		slog.Warn("streams manifest: failed to write", "dir", videoDir, "error", err)
 	} else {
-		slog.Info("streams manifest: written", "path", manifestPath, "streams", len(manifest.Streams))
+		slog.Info("streams manifest: written", "dir", videoDir, "streams", len(manifest.Streams))
 	}
 }
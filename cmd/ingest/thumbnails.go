@@ -10,6 +10,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +19,14 @@ import (
 
 const (
 	defaultThumbnailLabel = "sm"
+
+	// defaultThumbnailOffset is the frame used when no better candidate can
+	// be scored (unknown duration, candidate selection disabled).
+	defaultThumbnailOffset = 5 * time.Second
+
+	// thumbnailSampleSize is the edge length of the grayscale frames scored
+	// when choosing among candidate timestamps.
+	thumbnailSampleSize = 32
 )
 
 type thumbnailVariant struct {
@@ -61,9 +70,9 @@ func generateThumbnail(ctx context.Context, videoPath string) (string, error) {
 	return "", fmt.Errorf("thumbnail missing after generation")
 }
 
-func generateThumbnailVariant(ctx context.Context, videoPath, out string, maxWidth int) error {
+func generateThumbnailVariant(ctx context.Context, videoPath, out string, maxWidth int, offset time.Duration) error {
 	result := ffmpeg.ExtractThumbnail(ctx, videoPath, out, &ffmpeg.ThumbnailOptions{
-		Offset:   5 * time.Second,
+		Offset:   offset,
 		MaxWidth: maxWidth,
 		Quality:  4,
 	})
@@ -82,22 +91,194 @@ func ensureThumbnailVariants(ctx context.Context, videoPath, videoID string) err
 		return errors.New("missing video id")
 	}
 	videoDir := filepath.Dir(videoPath)
+	defaultPath := thumbnailVariantPath(videoDir, videoID, defaultThumbnailLabel)
+
+	stale := false
 	for _, variant := range thumbnailVariants {
 		path := thumbnailVariantPath(videoDir, videoID, variant.Label)
-		if _, err := os.Stat(path); err == nil {
-			if ok := thumbnailIsAcceptable(path, variant.MaxWidth); ok {
-				continue
-			}
+		if !thumbnailIsAcceptable(path, variant.MaxWidth) {
+			stale = true
+			break
 		}
-		if err := generateThumbnailVariant(ctx, videoPath, path, variant.MaxWidth); err != nil {
+	}
+	if !stale {
+		ensureLegacyThumbnailCopy(videoDir, videoID, defaultPath)
+		return nil
+	}
+
+	// Rebuild every variant from the same frame so the sizes never show
+	// different moments of the video.
+	offset := pickThumbnailOffset(ctx, videoPath, videoID)
+	for _, variant := range thumbnailVariants {
+		path := thumbnailVariantPath(videoDir, videoID, variant.Label)
+		if err := generateThumbnailVariant(ctx, videoPath, path, variant.MaxWidth, offset); err != nil {
 			return err
 		}
 	}
-	defaultPath := thumbnailVariantPath(videoDir, videoID, defaultThumbnailLabel)
+
+	// The legacy copy may still point at the previous frame.
+	legacy := filepath.Join(videoDir, videoID+".thumbnail.jpg")
+	if err := os.Remove(legacy); err != nil && !os.IsNotExist(err) {
+		slog.Warn("failed to delete stale legacy thumbnail", "path", legacy, "error", err)
+	}
 	ensureLegacyThumbnailCopy(videoDir, videoID, defaultPath)
 	return nil
 }
 
+// thumbnailCandidateCount is how many frames are scored when choosing a
+// thumbnail (THUMBNAIL_CANDIDATES, default 8). 1 always uses the frame at
+// defaultThumbnailOffset.
+func thumbnailCandidateCount() int {
+	return envInt("THUMBNAIL_CANDIDATES", 8)
+}
+
+// pickThumbnailOffset returns the timestamp a video's thumbnail is taken
+// from: the pinned timestamp if one is set, otherwise the best-scoring of
+// several candidate frames spread across the video.
+func pickThumbnailOffset(ctx context.Context, videoPath, videoID string) time.Duration {
+	videoDir := filepath.Dir(videoPath)
+	duration, durErr := resolveDurationSeconds(ctx, videoPath, nil)
+
+	if pin, ok := readThumbnailPin(videoDir, videoID); ok {
+		if durErr == nil && pin.Seconds() >= duration {
+			slog.Warn("pinned thumbnail timestamp is past the end of the video, ignoring", "video_id", videoID, "pin", pin, "duration", duration)
+		} else {
+			return pin
+		}
+	}
+	if durErr != nil {
+		return defaultThumbnailOffset
+	}
+
+	candidates := thumbnailCandidateOffsets(duration, thumbnailCandidateCount())
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	best, bestScore := time.Duration(-1), -1.0
+	for _, offset := range candidates {
+		frame, err := ffmpeg.SampleGrayFrameAt(ctx, videoPath, offset, thumbnailSampleSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			slog.Debug("thumbnail candidate frame failed", "video_id", videoID, "offset", offset, "error", err)
+			continue
+		}
+		if score := scoreThumbnailFrame(frame); score > bestScore {
+			best, bestScore = offset, score
+		}
+	}
+	if best < 0 {
+		return candidates[0]
+	}
+	slog.Info("selected thumbnail frame", "video_id", videoID, "offset", best, "score", bestScore, "candidates", len(candidates))
+	return best
+}
+
+// thumbnailCandidateOffsets spreads n timestamps evenly over 5–95% of the
+// duration, skipping intros that fade in from black and end cards. n <= 1
+// returns the single default frame, moved to the midpoint for videos too
+// short to reach it.
+func thumbnailCandidateOffsets(durationSeconds float64, n int) []time.Duration {
+	if n <= 1 {
+		if durationSeconds > 0 && durationSeconds <= defaultThumbnailOffset.Seconds() {
+			return []time.Duration{time.Duration(durationSeconds / 2 * float64(time.Second))}
+		}
+		return []time.Duration{defaultThumbnailOffset}
+	}
+	offsets := make([]time.Duration, n)
+	for i := range offsets {
+		frac := 0.05 + 0.9*float64(i)/float64(n-1)
+		offsets[i] = time.Duration(durationSeconds * frac * float64(time.Second))
+	}
+	return offsets
+}
+
+// scoreThumbnailFrame rates how "interesting" a grayscale frame is: its
+// contrast (standard deviation of luma), scaled down the further its mean
+// brightness drifts from mid-gray. Black, white and flat frames score zero.
+func scoreThumbnailFrame(frame []byte) float64 {
+	if len(frame) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range frame {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(frame))
+	var variance float64
+	for _, v := range frame {
+		d := float64(v) - mean
+		variance += d * d
+	}
+	stddev := math.Sqrt(variance / float64(len(frame)))
+
+	drift := (mean - 127.5) / 127.5
+	return stddev * (1 - drift*drift)
+}
+
+// thumbnailPinPath is the sidecar holding a user-pinned thumbnail timestamp
+// (seconds) for a video.
+func thumbnailPinPath(videoDir, videoID string) string {
+	return filepath.Join(videoDir, videoID+".thumbnail_at")
+}
+
+func readThumbnailPin(videoDir, videoID string) (time.Duration, bool) {
+	raw, err := os.ReadFile(thumbnailPinPath(videoDir, videoID))
+	if err != nil {
+		return 0, false
+	}
+	secs, err := strconv.ParseFloat(strings.TrimSpace(string(raw)), 64)
+	if err != nil || secs < 0 || math.IsNaN(secs) || math.IsInf(secs, 0) {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
+
+// setThumbnailPin pins a video's thumbnail to seconds, or clears the pin
+// when seconds is negative.
+func setThumbnailPin(videoDir, videoID string, seconds float64) error {
+	path := thumbnailPinPath(videoDir, videoID)
+	if seconds < 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path, []byte(strconv.FormatFloat(seconds, 'f', 3, 64)+"\n"), 0o644)
+}
+
+// sourceArtPruneEnabled reports whether the downloaded source artwork
+// (<id>.src_thumbnail.*) is deleted once the generated thumbnails exist
+// (THUMBNAIL_DELETE_SOURCE_ART, default off so the archive keeps the original).
+func sourceArtPruneEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("THUMBNAIL_DELETE_SOURCE_ART"))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// pruneSourceArt deletes a video's source artwork when pruning is enabled and
+// every thumbnail variant is on disk. Metadata-only videos must not call it:
+// the artwork is the only input their thumbnails can be rebuilt from.
+func pruneSourceArt(videoDir, videoID string) {
+	if !sourceArtPruneEnabled() {
+		return
+	}
+	for _, variant := range thumbnailVariants {
+		if _, err := os.Stat(thumbnailVariantPath(videoDir, videoID, variant.Label)); err != nil {
+			return
+		}
+	}
+	matches, _ := filepath.Glob(filepath.Join(videoDir, videoID+".src_thumbnail.*"))
+	for _, p := range matches {
+		if err := os.Remove(p); err != nil {
+			slog.Warn("failed to delete source artwork", "path", p, "error", err)
+			continue
+		}
+		slog.Info("deleted source artwork after thumbnail generation", "path", p)
+	}
+}
+
 func thumbnailVariantPath(videoDir, videoID, label string) string {
 	return filepath.Join(videoDir, fmt.Sprintf("%s.thumbnail.%s.jpg", videoID, label))
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPruneSourceArt(t *testing.T) {
+	dir := t.TempDir()
+	id := "video-1"
+	art := filepath.Join(dir, id+".src_thumbnail.webp")
+	write := func(p string) {
+		t.Helper()
+		if err := os.WriteFile(p, []byte("img"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exists := func(p string) bool {
+		_, err := os.Stat(p)
+		return err == nil
+	}
+	write(art)
+
+	// Off by default: artwork is kept even with every variant present.
+	for _, v := range thumbnailVariants {
+		write(thumbnailVariantPath(dir, id, v.Label))
+	}
+	pruneSourceArt(dir, id)
+	if !exists(art) {
+		t.Fatal("artwork deleted with pruning disabled")
+	}
+
+	t.Setenv("THUMBNAIL_DELETE_SOURCE_ART", "true")
+
+	// A missing variant keeps the artwork so it can still be rebuilt.
+	missing := thumbnailVariantPath(dir, id, thumbnailVariants[0].Label)
+	if err := os.Remove(missing); err != nil {
+		t.Fatal(err)
+	}
+	pruneSourceArt(dir, id)
+	if !exists(art) {
+		t.Fatal("artwork deleted while a thumbnail variant is missing")
+	}
+
+	write(missing)
+	pruneSourceArt(dir, id)
+	if exists(art) {
+		t.Error("artwork kept after every thumbnail variant was generated")
+	}
+	if !exists(missing) {
+		t.Error("thumbnail variant removed")
+	}
+}
+
+func TestScoreThumbnailFrame(t *testing.T) {
+	const n = thumbnailSampleSize * thumbnailSampleSize
+	fill := func(f func(i int) byte) []byte {
+		frame := make([]byte, n)
+		for i := range frame {
+			frame[i] = f(i)
+		}
+		return frame
+	}
+	black := fill(func(int) byte { return 0 })
+	white := fill(func(int) byte { return 255 })
+	gray := fill(func(int) byte { return 128 })
+	// Checkerboard-ish texture around mid-gray.
+	textured := fill(func(i int) byte {
+		if (i/thumbnailSampleSize+i)%2 == 0 {
+			return 64
+		}
+		return 192
+	})
+	// Same contrast, but crushed into the shadows.
+	dark := fill(func(i int) byte {
+		if (i/thumbnailSampleSize+i)%2 == 0 {
+			return 0
+		}
+		return 64
+	})
+
+	for name, frame := range map[string][]byte{"black": black, "white": white, "gray": gray} {
+		if got := scoreThumbnailFrame(frame); got != 0 {
+			t.Errorf("%s frame scored %v, want 0", name, got)
+		}
+	}
+	if got := scoreThumbnailFrame(nil); got != 0 {
+		t.Errorf("empty frame scored %v, want 0", got)
+	}
+
+	tex, dk := scoreThumbnailFrame(textured), scoreThumbnailFrame(dark)
+	if tex <= 0 {
+		t.Fatalf("textured frame scored %v, want > 0", tex)
+	}
+	if dk >= tex {
+		t.Errorf("dark frame scored %v, want less than textured %v", dk, tex)
+	}
+}
+
+func TestThumbnailCandidateOffsets(t *testing.T) {
+	got := thumbnailCandidateOffsets(100, 3)
+	want := []time.Duration{5 * time.Second, 50 * time.Second, 95 * time.Second}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("offset %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := thumbnailCandidateOffsets(100, 1); len(got) != 1 || got[0] != defaultThumbnailOffset {
+		t.Errorf("single candidate = %v, want [%v]", got, defaultThumbnailOffset)
+	}
+	if got := thumbnailCandidateOffsets(4, 1); len(got) != 1 || got[0] != 2*time.Second {
+		t.Errorf("short video single candidate = %v, want [2s]", got)
+	}
+}
+
+func TestThumbnailPin(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok := readThumbnailPin(dir, "v"); ok {
+		t.Fatal("pin reported before one was set")
+	}
+	if err := setThumbnailPin(dir, "v", 12.5); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := readThumbnailPin(dir, "v"); !ok || got != 12500*time.Millisecond {
+		t.Fatalf("pin = %v, %v; want 12.5s", got, ok)
+	}
+	if err := setThumbnailPin(dir, "v", -1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := readThumbnailPin(dir, "v"); ok {
+		t.Fatal("pin still set after clearing")
+	}
+}
@@ -31,6 +31,13 @@ func waveformDirForVideoPath(videoPath string) (string, error) {
 	return filepath.Join(filepath.Dir(videoPath), "waveform"), nil
 }
 
+// hasNoAudioMarker reports whether waveform generation found that the video
+// has no audio track. Audio assets are then not applicable rather than missing.
+func hasNoAudioMarker(videoPath string) bool {
+	_, err := os.Stat(filepath.Join(filepath.Dir(videoPath), "waveform", ".no-audio"))
+	return err == nil
+}
+
 func ensureWaveformAssets(ctx context.Context, videoPath string, durationSeconds *int32) (bool, error) {
 	wfDir, err := waveformDirForVideoPath(videoPath)
 	if err != nil {
@@ -11,6 +11,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"thirdcoast.systems/rewind/internal/db"
 )
 
 func logWhisperStartupInfo() {
@@ -112,7 +114,50 @@ func findCanonicalCaptionFilePath(dir string, videoID string) (string, string, b
 	return matches[0], lang, true
 }
 
-func generateCaptionsWithWhisper(ctx context.Context, videoPath string, videoID string, outputDir string) (string, string, error) {
+// whisperOptions overrides the WHISPER_* settings for one run, as set on a
+// caption regeneration job. The zero value uses the environment.
+type whisperOptions struct {
+	Model    string // "" uses WHISPER_MODEL
+	Language string // "" uses WHISPER_LANGUAGE; "auto" forces detection
+}
+
+// replaces reports whether the run was asked for explicitly and should
+// replace captions left by an earlier one.
+func (o whisperOptions) replaces() bool {
+	return o.Model != "" || o.Language != ""
+}
+
+// whisperModelCost is roughly how many times longer model takes than the
+// small model, used to stretch WHISPER_TIMEOUT_SECONDS.
+func whisperModelCost(model string) float64 {
+	switch {
+	case strings.HasPrefix(model, "tiny"), strings.HasPrefix(model, "base"):
+		return 0.5
+	case strings.HasPrefix(model, "medium"), model == "turbo", model == "large-v3-turbo":
+		return 2
+	case strings.HasPrefix(model, "large"):
+		return 4
+	default:
+		return 1
+	}
+}
+
+// validateWhisperModel checks model before whisper is launched. Names must be
+// known; when WHISPER_MODEL_DIR is set, whisper cannot download, so the
+// weights must already be there.
+func validateWhisperModel(model string) error {
+	if !db.ValidWhisperModels[model] {
+		return fmt.Errorf("whisper: unknown model %q", model)
+	}
+	if dir := strings.TrimSpace(os.Getenv("WHISPER_MODEL_DIR")); dir != "" {
+		if _, err := os.Stat(filepath.Join(dir, model+".pt")); err != nil {
+			return fmt.Errorf("whisper: model %q not found in %s", model, dir)
+		}
+	}
+	return nil
+}
+
+func generateCaptionsWithWhisper(ctx context.Context, videoPath string, videoID string, outputDir string, opts whisperOptions) (string, string, error) {
 	if !whisperEnabled() {
 		return "", "", fmt.Errorf("whisper disabled")
 	}
@@ -136,11 +181,21 @@ func generateCaptionsWithWhisper(ctx context.Context, videoPath string, videoID
 	if model == "" {
 		model = "small"
 	}
+	defaultModel := model
+	if opts.Model != "" {
+		model = opts.Model
+		if err := validateWhisperModel(model); err != nil {
+			return "", "", err
+		}
+	}
 	device := strings.TrimSpace(os.Getenv("WHISPER_DEVICE"))
 	if device == "" {
 		device = "cpu"
 	}
 	lang := strings.TrimSpace(os.Getenv("WHISPER_LANGUAGE"))
+	if opts.Language != "" {
+		lang = opts.Language
+	}
 	langTag := "und"
 	useLang := false
 	if lang != "" && !strings.EqualFold(lang, "auto") {
@@ -153,29 +208,46 @@ func generateCaptionsWithWhisper(ctx context.Context, videoPath string, videoID
 		task = "transcribe"
 	}
 
+	// Word timings are only in the JSON output, and whisper writes a single
+	// format or all of them.
+	wordTimestamps := whisperWordTimestampsEnabled()
+	outputFormat := "vtt"
+	if wordTimestamps {
+		outputFormat = "all"
+	}
 	args := []string{
 		videoPath,
 		"--model", model,
-		"--output_format", "vtt",
+		"--output_format", outputFormat,
 		"--output_dir", outputDir,
 		"--device", device,
 		"--task", task,
 	}
+	if wordTimestamps {
+		args = append(args, "--word_timestamps", "True")
+	}
 	if useLang {
 		args = append(args, "--language", lang)
 	}
+	if dir := strings.TrimSpace(os.Getenv("WHISPER_MODEL_DIR")); dir != "" {
+		args = append(args, "--model_dir", dir)
+	}
 	if extra := strings.TrimSpace(os.Getenv("WHISPER_ARGS")); extra != "" {
 		args = append(args, strings.Fields(extra)...)
 	}
 
+	// The timeout is sized for the default model; scale it for an override.
 	ctxToUse := ctx
+	var timeoutDur time.Duration
 	if timeout := strings.TrimSpace(os.Getenv("WHISPER_TIMEOUT_SECONDS")); timeout != "" {
 		if n, err := strconv.Atoi(timeout); err == nil && n > 0 {
+			timeoutDur = time.Duration(float64(n) * float64(time.Second) * whisperModelCost(model) / whisperModelCost(defaultModel))
 			var cancel context.CancelFunc
-			ctxToUse, cancel = context.WithTimeout(ctx, time.Duration(n)*time.Second)
+			ctxToUse, cancel = context.WithTimeout(ctx, timeoutDur)
 			defer cancel()
 		}
 	}
+	slog.Info("whisper run", "video_id", videoID, "model", model, "language", lang, "override", opts.replaces(), "timeout", timeoutDur)
 
 	var buf bytes.Buffer
 	cmd := exec.CommandContext(ctxToUse, cmdPath, args...)
@@ -197,14 +269,67 @@ func generateCaptionsWithWhisper(ctx context.Context, videoPath string, videoID
 	}
 
 	dest := filepath.Join(outputDir, videoID+".captions."+langTag+".vtt")
-	if _, err := os.Stat(dest); err == nil {
+	if _, err := os.Stat(dest); err == nil && (!opts.replaces() || !whisperWrote(dest)) {
+		if wordTimestamps {
+			keepWhisperWords(strings.TrimSuffix(cand, ".vtt"), "")
+		}
+		if opts.replaces() {
+			// The platform's or uploader's captions are kept over a rerun.
+			_ = os.Remove(cand)
+			return "", "", fmt.Errorf("whisper: %s holds captions that did not come from whisper; not replacing them", filepath.Base(dest))
+		}
 		return dest, langTag, nil
 	}
+	if wordTimestamps {
+		keepWhisperWords(strings.TrimSuffix(cand, ".vtt"), transcriptWordsPath(dest))
+	}
 	if filepath.Clean(cand) != filepath.Clean(dest) {
 		if err := moveOrCopyFile(cand, dest); err != nil {
 			return "", "", fmt.Errorf("whisper move: %w", err)
 		}
 	}
+	if err := os.WriteFile(whisperMarkerPath(dest), nil, 0o644); err != nil {
+		slog.Warn("whisper: failed to mark captions", "path", dest, "error", err)
+	}
 
 	return dest, langTag, nil
 }
+
+// whisperMarkerPath is the empty file recording that whisper wrote the
+// captions at vttPath, so a rerun with overrides may replace them.
+func whisperMarkerPath(vttPath string) string {
+	return strings.TrimSuffix(vttPath, ".vtt") + ".whisper"
+}
+
+// whisperWrote reports whether the captions at vttPath came from whisper:
+// they carry a marker, or word timings that only whisper writes (captions
+// from before the marker).
+func whisperWrote(vttPath string) bool {
+	for _, p := range []string{whisperMarkerPath(vttPath), transcriptWordsPath(vttPath)} {
+		if _, err := os.Stat(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// keepWhisperWords moves whisper's JSON output for base to wordsPath and
+// removes the other formats written alongside it. An empty wordsPath
+// discards the JSON too.
+func keepWhisperWords(base, wordsPath string) {
+	for _, ext := range []string{".txt", ".srt", ".tsv"} {
+		_ = os.Remove(base + ext)
+	}
+	src := base + ".json"
+	if _, err := os.Stat(src); err != nil {
+		return
+	}
+	if wordsPath == "" {
+		_ = os.Remove(src)
+		return
+	}
+	if err := moveOrCopyFile(src, wordsPath); err != nil {
+		slog.Warn("whisper: failed to keep word timings", "path", src, "error", err)
+		_ = os.Remove(src)
+	}
+}
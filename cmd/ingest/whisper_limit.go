@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// whisperSlotPollInterval is how often a worker waiting for a Whisper slot
+// retries.
+var whisperSlotPollInterval = 2 * time.Second
+
+// whisperMaxConcurrent is how many Whisper runs may execute at once across
+// every ingest replica sharing the database (WHISPER_MAX_CONCURRENT, default 1).
+func whisperMaxConcurrent() int {
+	return envInt("WHISPER_MAX_CONCURRENT", 1)
+}
+
+// generateCaptionsWithWhisperLimited runs generateCaptionsWithWhisper once a
+// Whisper slot is free. Slots are advisory locks, so the limit holds across
+// replicas and a crashed worker's slot frees with its connection.
+func generateCaptionsWithWhisperLimited(ctx context.Context, dbc *db.DatabaseConnection, videoPath, videoID, outputDir string, opts whisperOptions) (string, string, error) {
+	release, err := acquireWhisperSlot(ctx, dbc.AcquireAdvisoryLockConn, whisperMaxConcurrent(), videoID)
+	if err != nil {
+		return "", "", err
+	}
+	defer release()
+	return generateCaptionsWithWhisper(ctx, videoPath, videoID, outputDir, opts)
+}
+
+// acquireWhisperSlot blocks until one of slots advisory locks is taken and
+// returns the func that frees it. It only gives up when ctx is done.
+func acquireWhisperSlot(ctx context.Context, acquire func(context.Context) (db.AdvisoryLockConn, error), slots int, videoID string) (func(), error) {
+	if slots < 1 {
+		slots = 1
+	}
+	waiting := false
+	for {
+		conn, err := acquire(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("whisper slot acquire conn: %w", err)
+		}
+		for i := 0; i < slots; i++ {
+			lockID := db.AdvisoryLockID("whisper-slot", strconv.Itoa(i))
+			ok, err := conn.TryAdvisoryLock(ctx, lockID)
+			if err != nil {
+				conn.Release()
+				return nil, fmt.Errorf("whisper slot lock: %w", err)
+			}
+			if ok {
+				if waiting {
+					slog.Info("whisper slot acquired", "video_id", videoID, "slot", i)
+				}
+				return func() {
+					if _, err := conn.AdvisoryUnlock(context.Background(), lockID); err != nil {
+						slog.Warn("whisper slot unlock failed", "slot", i, "error", err)
+					}
+					conn.Release()
+				}, nil
+			}
+		}
+		// Every slot is busy; don't pin a connection while waiting.
+		conn.Release()
+		if !waiting {
+			slog.Info("waiting for a whisper slot", "video_id", videoID, "slots", slots)
+			waiting = true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(whisperSlotPollInterval):
+		}
+	}
+}
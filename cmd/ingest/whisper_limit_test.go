@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+func TestAcquireWhisperSlot_LimitsConcurrency(t *testing.T) {
+	prev := whisperSlotPollInterval
+	whisperSlotPollInterval = time.Millisecond
+	defer func() { whisperSlotPollInterval = prev }()
+
+	table := newFakeLockTable()
+	ctx := context.Background()
+	const slots = 2
+
+	var active, maxActive, runs atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := acquireWhisperSlot(ctx, table.acquire, slots, "video")
+			if err != nil {
+				t.Errorf("acquire: %v", err)
+				return
+			}
+			defer release()
+			n := active.Add(1)
+			for {
+				m := maxActive.Load()
+				if n <= m || maxActive.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond) // "run" whisper
+			active.Add(-1)
+			runs.Add(1)
+		}()
+	}
+	wg.Wait()
+
+	if runs.Load() != 5 {
+		t.Fatalf("runs = %d, want all 5", runs.Load())
+	}
+	if got := maxActive.Load(); got > slots {
+		t.Errorf("max concurrent whisper runs = %d, want at most %d", got, slots)
+	}
+	if table.open.Load() != 0 {
+		t.Errorf("%d connections left pinned", table.open.Load())
+	}
+}
+
+func TestAcquireWhisperSlot_HonoursContext(t *testing.T) {
+	prev := whisperSlotPollInterval
+	whisperSlotPollInterval = time.Millisecond
+	defer func() { whisperSlotPollInterval = prev }()
+
+	table := newFakeLockTable()
+	release, err := acquireWhisperSlot(context.Background(), table.acquire, 1, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := acquireWhisperSlot(ctx, table.acquire, 1, "b"); err == nil {
+		t.Error("waiting on a full limiter past the deadline: want context error")
+	}
+}
+
+// fakeLockTable mimics PostgreSQL session-level advisory locks: a lock is
+// owned by the connection that took it and only that connection can free it.
+type fakeLockTable struct {
+	mu   sync.Mutex
+	held map[int64]*fakeLockConn
+	open atomic.Int32
+}
+
+type fakeLockConn struct{ t *fakeLockTable }
+
+func newFakeLockTable() *fakeLockTable {
+	return &fakeLockTable{held: map[int64]*fakeLockConn{}}
+}
+
+func (t *fakeLockTable) acquire(context.Context) (db.AdvisoryLockConn, error) {
+	t.open.Add(1)
+	return &fakeLockConn{t: t}, nil
+}
+
+func (c *fakeLockConn) TryAdvisoryLock(_ context.Context, id int64) (bool, error) {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+	if owner, ok := c.t.held[id]; ok && owner != c {
+		return false, nil
+	}
+	c.t.held[id] = c
+	return true, nil
+}
+
+func (c *fakeLockConn) AdvisoryUnlock(_ context.Context, id int64) (bool, error) {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+	if c.t.held[id] != c {
+		return false, nil
+	}
+	delete(c.t.held, id)
+	return true, nil
+}
+
+func (c *fakeLockConn) Release() { c.t.open.Add(-1) }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateWhisperModel(t *testing.T) {
+	t.Setenv("WHISPER_MODEL_DIR", "")
+	if err := validateWhisperModel("large-v3"); err != nil {
+		t.Errorf("large-v3: %v", err)
+	}
+	if err := validateWhisperModel("huge"); err == nil {
+		t.Error("unknown model accepted")
+	}
+
+	// With a model directory the weights must already be there.
+	dir := t.TempDir()
+	t.Setenv("WHISPER_MODEL_DIR", dir)
+	if err := validateWhisperModel("medium"); err == nil {
+		t.Error("missing weights accepted")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "medium.pt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateWhisperModel("medium"); err != nil {
+		t.Errorf("medium with weights: %v", err)
+	}
+}
+
+func TestWhisperModelCost(t *testing.T) {
+	if !(whisperModelCost("tiny.en") < whisperModelCost("small") &&
+		whisperModelCost("small") < whisperModelCost("medium") &&
+		whisperModelCost("medium") < whisperModelCost("large-v2")) {
+		t.Error("model costs out of order")
+	}
+	if whisperModelCost("turbo") >= whisperModelCost("large") {
+		t.Error("turbo should be cheaper than large")
+	}
+}
+
+func TestWhisperWrote(t *testing.T) {
+	dir := t.TempDir()
+	vtt := filepath.Join(dir, "v.captions.en.vtt")
+	if err := os.WriteFile(vtt, []byte("WEBVTT\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if whisperWrote(vtt) {
+		t.Error("platform captions reported as whisper's")
+	}
+	if err := os.WriteFile(transcriptWordsPath(vtt), []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !whisperWrote(vtt) {
+		t.Error("captions with word timings not reported as whisper's")
+	}
+	_ = os.Remove(transcriptWordsPath(vtt))
+	if err := os.WriteFile(whisperMarkerPath(vtt), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !whisperWrote(vtt) {
+		t.Error("marked captions not reported as whisper's")
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/utils/language"
+	"thirdcoast.systems/rewind/pkg/utils/vttshift"
+)
+
+// whisperWordTimestampsEnabled reports whether Whisper is asked for
+// word-level timestamps, which let the transcript seek to a clicked word.
+func whisperWordTimestampsEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("WHISPER_WORD_TIMESTAMPS"))
+	if v == "" {
+		return true
+	}
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// transcriptWordsPath is the sidecar holding Whisper's JSON output (with word
+// timings) for the captions at vttPath. Imported captions have none.
+func transcriptWordsPath(vttPath string) string {
+	return strings.TrimSuffix(vttPath, ".vtt") + ".words.json"
+}
+
+// whisperWord is one word of Whisper's JSON output, in absolute seconds.
+type whisperWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// parseWhisperWords reads the words of Whisper's JSON output (written with
+// --word_timestamps True) in order. Blank words and words with bad timings
+// are skipped; output without word timings yields no words.
+func parseWhisperWords(r io.Reader) ([]whisperWord, error) {
+	var out struct {
+		Segments []struct {
+			Words []whisperWord `json:"words"`
+		} `json:"segments"`
+	}
+	if err := json.NewDecoder(r).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode whisper json: %w", err)
+	}
+	var words []whisperWord
+	for _, seg := range out.Segments {
+		for _, w := range seg.Words {
+			w.Word = strings.TrimSpace(w.Word)
+			if w.Word == "" || math.IsNaN(w.Start) || math.IsInf(w.Start, 0) || w.Start < 0 {
+				continue
+			}
+			if math.IsNaN(w.End) || w.End < w.Start {
+				w.End = w.Start
+			}
+			words = append(words, w)
+		}
+	}
+	return words, nil
+}
+
+// cueSpan is the timing of one VTT cue.
+type cueSpan struct {
+	start, end float64
+}
+
+// parseVTTCueSpans returns the timings of the cues in a VTT file, in file
+// order. Cues without text are skipped, matching how the transcript panel
+// numbers cues.
+func parseVTTCueSpans(vtt string) []cueSpan {
+	lines := strings.Split(strings.ReplaceAll(vtt, "\r\n", "\n"), "\n")
+	var spans []cueSpan
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.Contains(line, "-->") {
+			continue
+		}
+		parts := strings.SplitN(line, "-->", 2)
+		startFields := strings.Fields(parts[0])
+		endFields := strings.Fields(parts[1])
+		if len(startFields) == 0 || len(endFields) == 0 {
+			continue
+		}
+		start, err1 := vttshift.Parse(startFields[0])
+		end, err2 := vttshift.Parse(endFields[0])
+		hasText := false
+		for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+			hasText = true
+			i++
+		}
+		if err1 != nil || err2 != nil || !hasText {
+			continue
+		}
+		spans = append(spans, cueSpan{start: start.Seconds(), end: end.Seconds()})
+	}
+	return spans
+}
+
+// cueWord is a word placed in a cue, timed relative to the cue's start.
+type cueWord struct {
+	cue, index             int
+	startOffset, endOffset float64
+	word                   string
+}
+
+// assignWordsToCues places each word in the cue its midpoint falls in. Both
+// lists must be in time order. Words outside every cue are dropped.
+func assignWordsToCues(cues []cueSpan, words []whisperWord) []cueWord {
+	var out []cueWord
+	c, index := 0, 0
+	for _, w := range words {
+		mid := (w.Start + w.End) / 2
+		prev := c
+		for c < len(cues) && cues[c].end <= mid {
+			c++
+		}
+		if c != prev {
+			index = 0
+		}
+		if c >= len(cues) {
+			break
+		}
+		if mid < cues[c].start {
+			continue
+		}
+		out = append(out, cueWord{
+			cue:         c,
+			index:       index,
+			startOffset: math.Max(0, w.Start-cues[c].start),
+			endOffset:   math.Max(0, w.End-cues[c].start),
+			word:        w.Word,
+		})
+		index++
+	}
+	return out
+}
+
+// storeTranscriptWords replaces the stored word timings of a transcript with
+// those in the Whisper sidecar next to vttPath. Captions without a sidecar
+// clear them, and the transcript falls back to cue-level seeking.
+func storeTranscriptWords(ctx context.Context, q *db.Queries, videoID pgtype.UUID, lang language.Tag, vttPath, vtt string) (int, error) {
+	if err := q.DeleteVideoTranscriptWords(ctx, &db.DeleteVideoTranscriptWordsParams{VideoID: videoID, Lang: lang}); err != nil {
+		return 0, fmt.Errorf("delete previous words: %w", err)
+	}
+
+	f, err := os.Open(transcriptWordsPath(vttPath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+	words, err := parseWhisperWords(f)
+	if err != nil {
+		return 0, err
+	}
+	placed := assignWordsToCues(parseVTTCueSpans(vtt), words)
+	if len(placed) == 0 {
+		return 0, nil
+	}
+
+	params := &db.InsertVideoTranscriptWordsParams{
+		VideoID:      videoID,
+		Lang:         lang,
+		CueIndexes:   make([]int32, len(placed)),
+		WordIndexes:  make([]int32, len(placed)),
+		StartOffsets: make([]float64, len(placed)),
+		EndOffsets:   make([]float64, len(placed)),
+		Words:        make([]string, len(placed)),
+	}
+	for i, w := range placed {
+		params.CueIndexes[i] = int32(w.cue)
+		params.WordIndexes[i] = int32(w.index)
+		params.StartOffsets[i] = w.startOffset
+		params.EndOffsets[i] = w.endOffset
+		params.Words[i] = w.word
+	}
+	if err := q.InsertVideoTranscriptWords(ctx, params); err != nil {
+		return 0, fmt.Errorf("insert words: %w", err)
+	}
+	return len(placed), nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const whisperWordsJSON = `{
+  "text": " Hello there. General Kenobi!",
+  "segments": [
+    {"id": 0, "start": 0.0, "end": 1.5, "text": " Hello there.",
+     "words": [
+       {"word": " Hello", "start": 0.0, "end": 0.6, "probability": 0.98},
+       {"word": " there.", "start": 0.6, "end": 1.4, "probability": 0.95}
+     ]},
+    {"id": 1, "start": 2.0, "end": 4.0, "text": " General Kenobi!",
+     "words": [
+       {"word": " General", "start": 2.1, "end": 2.7, "probability": 0.9},
+       {"word": " ", "start": 2.7, "end": 2.7, "probability": 0.1},
+       {"word": " Kenobi!", "start": 2.8, "end": 2.5, "probability": 0.9}
+     ]}
+  ],
+  "language": "en"
+}`
+
+func TestParseWhisperWords(t *testing.T) {
+	words, err := parseWhisperWords(strings.NewReader(whisperWordsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []whisperWord{
+		{Word: "Hello", Start: 0, End: 0.6},
+		{Word: "there.", Start: 0.6, End: 1.4},
+		{Word: "General", Start: 2.1, End: 2.7},
+		// The end before the start is clamped; the blank word is dropped.
+		{Word: "Kenobi!", Start: 2.8, End: 2.8},
+	}
+	if !reflect.DeepEqual(words, want) {
+		t.Errorf("got %+v\nwant %+v", words, want)
+	}
+
+	// Output written without --word_timestamps has no words.
+	words, err = parseWhisperWords(strings.NewReader(`{"segments":[{"start":0,"end":1,"text":"hi"}]}`))
+	if err != nil || len(words) != 0 {
+		t.Errorf("no word timings: got %v, %v", words, err)
+	}
+
+	if _, err := parseWhisperWords(strings.NewReader("not json")); err == nil {
+		t.Error("want error for bad JSON")
+	}
+}
+
+func TestAssignWordsToCues(t *testing.T) {
+	vtt := "WEBVTT\n\n" +
+		"00:00.000 --> 00:01.500\nHello there.\n\n" +
+		"00:01.600 --> 00:01.900\n\n" + // no text: not counted
+		"00:02.000 --> 00:04.000\nGeneral Kenobi!\n"
+	cues := parseVTTCueSpans(vtt)
+	if len(cues) != 2 || cues[1].start != 2 {
+		t.Fatalf("cues = %+v", cues)
+	}
+
+	words, err := parseWhisperWords(strings.NewReader(whisperWordsJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := assignWordsToCues(cues, words)
+	want := []cueWord{
+		{cue: 0, index: 0, startOffset: 0, endOffset: 0.6, word: "Hello"},
+		{cue: 0, index: 1, startOffset: 0.6, endOffset: 1.4, word: "there."},
+		{cue: 1, index: 0, startOffset: 0.1, endOffset: 0.7, word: "General"},
+		{cue: 1, index: 1, startOffset: 0.8, endOffset: 0.8, word: "Kenobi!"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.cue != w.cue || g.index != w.index || g.word != w.word ||
+			!approx(g.startOffset, w.startOffset) || !approx(g.endOffset, w.endOffset) {
+			t.Errorf("word %d = %+v, want %+v", i, g, w)
+		}
+	}
+
+	// Words past the last cue are dropped.
+	if got := assignWordsToCues(cues[:1], words); len(got) != 2 {
+		t.Errorf("got %d words in the first cue, want 2", len(got))
+	}
+}
+
+func approx(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
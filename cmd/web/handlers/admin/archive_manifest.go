@@ -0,0 +1,246 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+const (
+	// archiveManifestSchemaVersion is bumped whenever a field changes meaning
+	// or is removed; adding fields does not bump it.
+	archiveManifestSchemaVersion = 1
+	archiveManifestPageSize      = 200
+)
+
+// manifestVideo is one video in the archive manifest: its database record
+// plus what is actually on disk.
+type manifestVideo struct {
+	ID              string               `json:"id"`
+	Src             string               `json:"src"`
+	Title           string               `json:"title"`
+	Description     string               `json:"description,omitempty"`
+	Tags            []string             `json:"tags,omitempty"`
+	Uploader        string               `json:"uploader,omitempty"`
+	UploaderID      *string              `json:"uploader_id,omitempty"`
+	ChannelID       *string              `json:"channel_id,omitempty"`
+	UploadDate      string               `json:"upload_date,omitempty"`
+	DurationSeconds *int32               `json:"duration_seconds,omitempty"`
+	MetadataOnly    bool                 `json:"metadata_only,omitempty"`
+	ArchivedAt      time.Time            `json:"archived_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+	Dir             string               `json:"dir"`
+	VideoFile       string               `json:"video_file,omitempty"` // relative to Dir
+	FileSHA256      string               `json:"file_sha256,omitempty"`
+	FileSize        *int64               `json:"file_size,omitempty"`
+	Files           []manifestFile       `json:"files"`
+	Disk            manifestDiskCheck    `json:"disk"`
+	AssetsStatus    db.AssetMap          `json:"assets_status,omitempty"`
+	Info            videoinfo.VideoInfo  `json:"info"`
+	Probe           *videoinfo.ProbeInfo `json:"probe,omitempty"`
+}
+
+// manifestFile is a file found in a video's directory.
+type manifestFile struct {
+	Path       string    `json:"path"` // relative to the video's Dir
+	SizeBytes  int64     `json:"size_bytes"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// manifestDiskCheck compares the database record with the disk.
+type manifestDiskCheck struct {
+	DirPresent       bool  `json:"dir_present"`
+	VideoFilePresent bool  `json:"video_file_present"`
+	VideoSizeMatches *bool `json:"video_size_matches,omitempty"`
+}
+
+// manifestSummary closes the manifest with archive-wide totals.
+type manifestSummary struct {
+	Videos            int   `json:"videos"`
+	Files             int   `json:"files"`
+	TotalBytes        int64 `json:"total_bytes"`
+	MissingDirs       int   `json:"missing_dirs"`
+	MissingVideoFiles int   `json:"missing_video_files"`
+	SizeMismatches    int   `json:"size_mismatches"`
+}
+
+// HandleAdminArchiveManifest serves GET /admin/archive-manifest, streaming a
+// JSON manifest of every video for disaster recovery: the metadata needed to
+// rebuild the database, the SHA-256 of each media file, and every file in
+// each video's directory as found on disk. Videos are read in pages and
+// written as they go, so memory stays flat for large archives. Asset flags
+// are as last verified by ingest; file hashes are not recomputed.
+func HandleAdminArchiveManifest(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		total, err := q.CountVideos(ctx)
+		if err != nil {
+			slog.Error("failed to count videos for manifest", "error", err)
+			return c.String(500, "failed to count videos")
+		}
+
+		now := time.Now().UTC()
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="rewind-manifest-`+now.Format("20060102-150405")+`.json"`)
+		res.WriteHeader(200)
+
+		header, _ := json.Marshal(map[string]any{
+			"schema_version": archiveManifestSchemaVersion,
+			"generated_at":   now,
+			"video_count":    total,
+		})
+		// Splice the videos array into the header object.
+		if _, err := res.Write(append(header[:len(header)-1], []byte(`,"videos":[`)...)); err != nil {
+			return nil
+		}
+
+		var summary manifestSummary
+		after := pgtype.UUID{Valid: true}
+		for {
+			rows, err := q.ListVideosForManifest(ctx, &db.ListVideosForManifestParams{
+				AfterID:   after,
+				PageLimit: archiveManifestPageSize,
+			})
+			if err != nil {
+				// Headers are gone; leave the JSON truncated so the file is
+				// visibly invalid rather than silently incomplete.
+				slog.Error("failed to list videos for manifest", "error", err)
+				return nil
+			}
+			for _, row := range rows {
+				entry := newManifestVideo(ctx, row, &summary)
+				b, err := json.Marshal(entry)
+				if err != nil {
+					slog.Error("failed to encode manifest entry", "error", err, "video_id", entry.ID)
+					return nil
+				}
+				if summary.Videos > 1 {
+					b = append([]byte(",\n"), b...)
+				}
+				if _, err := res.Write(b); err != nil {
+					return nil
+				}
+			}
+			res.Flush()
+			if len(rows) < archiveManifestPageSize {
+				break
+			}
+			after = rows[len(rows)-1].ID
+		}
+
+		tail, _ := json.Marshal(summary)
+		_, _ = res.Write([]byte(`],"summary":` + string(tail) + "}\n"))
+		slog.Info("archive manifest exported", "videos", summary.Videos, "files", summary.Files, "missing_video_files", summary.MissingVideoFiles)
+		return nil
+	}
+}
+
+// newManifestVideo builds the manifest entry for row, checking it against
+// the disk and adding to summary.
+func newManifestVideo(ctx context.Context, row *db.ListVideosForManifestRow, summary *manifestSummary) manifestVideo {
+	id := row.ID.String()
+	entry := manifestVideo{
+		ID:              id,
+		Src:             row.Src,
+		Title:           row.Title,
+		Description:     row.Description,
+		Tags:            row.Tags,
+		Uploader:        row.Uploader,
+		UploaderID:      row.UploaderID,
+		ChannelID:       row.ChannelID,
+		DurationSeconds: row.DurationSeconds,
+		MetadataOnly:    row.MetadataOnly,
+		ArchivedAt:      row.CreatedAt.Time.UTC(),
+		UpdatedAt:       row.UpdatedAt.Time.UTC(),
+		FileSize:        row.FileSize,
+		Files:           []manifestFile{},
+		AssetsStatus:    row.AssetsStatus,
+		Info:            row.Info,
+		Probe:           row.ProbeData,
+	}
+	if row.UploadDate.Valid {
+		entry.UploadDate = row.UploadDate.Time.Format("2006-01-02")
+	}
+	if row.FileHash != nil {
+		entry.FileSHA256 = strings.TrimSpace(*row.FileHash)
+	}
+
+	videoPath := ""
+	if row.VideoPath != nil {
+		videoPath = strings.TrimSpace(*row.VideoPath)
+	}
+	if videoPath != "" {
+		entry.Dir = filepath.Dir(videoPath)
+		entry.VideoFile = filepath.Base(videoPath)
+	} else {
+		entry.Dir, _ = fileserver.GetVideoDirForID(ctx, id)
+	}
+
+	summary.Videos++
+	entry.Files, entry.Disk.DirPresent = listManifestFiles(entry.Dir)
+	if !entry.Disk.DirPresent {
+		summary.MissingDirs++
+	}
+	for _, f := range entry.Files {
+		summary.Files++
+		summary.TotalBytes += f.SizeBytes
+		if entry.VideoFile != "" && f.Path == entry.VideoFile {
+			entry.Disk.VideoFilePresent = true
+			if row.FileSize != nil {
+				match := *row.FileSize == f.SizeBytes
+				entry.Disk.VideoSizeMatches = &match
+				if !match {
+					summary.SizeMismatches++
+				}
+			}
+		}
+	}
+	if !entry.Disk.VideoFilePresent && !row.MetadataOnly {
+		summary.MissingVideoFiles++
+	}
+	return entry
+}
+
+// listManifestFiles returns every regular file under dir, with paths
+// relative to dir. ok is false when dir does not exist.
+func listManifestFiles(dir string) (files []manifestFile, ok bool) {
+	files = []manifestFile{}
+	if st, err := os.Stat(dir); err != nil || !st.IsDir() {
+		return files, false
+	}
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, manifestFile{
+			Path:       filepath.ToSlash(rel),
+			SizeBytes:  info.Size(),
+			ModifiedAt: info.ModTime().UTC(),
+		})
+		return nil
+	})
+	return files, true
+}
@@ -12,7 +12,8 @@ import (
 	"thirdcoast.systems/rewind/internal/db"
 )
 
-// HandleAdminAssetHealthPage serves GET /admin/asset-health, showing videos with asset generation errors.
+// HandleAdminAssetHealthPage serves GET /admin/asset-health, showing videos with asset generation errors
+// and how many videos are missing a preview.
 func HandleAdminAssetHealthPage(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		username, _ := c.Get("currentUsername").(string)
@@ -32,7 +33,12 @@ func HandleAdminAssetHealthPage(sm *auth.SessionManager, dbc *db.DatabaseConnect
 		count, err := q.CountVideosWithAssetErrors(ctx)
 		if err != nil {
 			slog.Error("failed to count videos with asset errors", "error", err)
-			return templates.AdminAssetHealth(username, 0, nil, "error", "Failed to load asset errors.").Render(ctx, c.Response().Writer)
+			return templates.AdminAssetHealth(username, 0, 0, nil, "error", "Failed to load asset errors.").Render(ctx, c.Response().Writer)
+		}
+
+		missingPreviews, err := q.CountVideosMissingPreview(ctx)
+		if err != nil {
+			slog.Error("failed to count videos missing previews", "error", err)
 		}
 
 		var rows []*templates.AdminAssetErrorRow
@@ -40,7 +46,7 @@ func HandleAdminAssetHealthPage(sm *auth.SessionManager, dbc *db.DatabaseConnect
 			dbRows, err := q.ListVideosWithAssetErrors(ctx, 200)
 			if err != nil {
 				slog.Error("failed to list videos with asset errors", "error", err)
-				return templates.AdminAssetHealth(username, count, nil, "error", "Failed to load error details.").Render(ctx, c.Response().Writer)
+				return templates.AdminAssetHealth(username, count, missingPreviews, nil, "error", "Failed to load error details.").Render(ctx, c.Response().Writer)
 			}
 			rows = make([]*templates.AdminAssetErrorRow, 0, len(dbRows))
 			for _, r := range dbRows {
@@ -56,7 +62,7 @@ func HandleAdminAssetHealthPage(sm *auth.SessionManager, dbc *db.DatabaseConnect
 			}
 		}
 
-		return templates.AdminAssetHealth(username, count, rows, alertType, alertMsg).Render(ctx, c.Response().Writer)
+		return templates.AdminAssetHealth(username, count, missingPreviews, rows, alertType, alertMsg).Render(ctx, c.Response().Writer)
 	}
 }
 
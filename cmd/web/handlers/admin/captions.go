@@ -0,0 +1,203 @@
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/templates"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+const (
+	maxCaptionAuditVideos    = 200
+	captionBackfillBatchSize = 200
+)
+
+// captionBackfillRetryAfter is how long the backfill leaves a video alone
+// after a captions job for it failed or finished without a transcript.
+const captionBackfillRetryAfter = 24 * time.Hour
+
+// HandleAdminCaptionsPage serves GET /admin/captions, auditing transcript
+// coverage across the archive. Filters: ?channel=<channel_id>,
+// ?presence=missing|present and ?lang=<language>. With ?since=<RFC3339> the
+// page also tracks a running caption backfill.
+func HandleAdminCaptionsPage(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		username, _ := c.Get("currentUsername").(string)
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		view := templates.AdminCaptionsView{
+			Channel:  strings.TrimSpace(c.QueryParam("channel")),
+			Presence: strings.TrimSpace(c.QueryParam("presence")),
+			Lang:     strings.TrimSpace(c.QueryParam("lang")),
+			Since:    strings.TrimSpace(c.QueryParam("since")),
+		}
+		if errMsg := c.QueryParam("err"); errMsg != "" {
+			view.AlertType, view.AlertMsg = "error", errMsg
+		} else if msg := c.QueryParam("msg"); msg != "" {
+			view.AlertType, view.AlertMsg = "success", msg
+		}
+		if view.Presence != "" && view.Presence != "missing" && view.Presence != "present" {
+			view.Presence = ""
+		}
+		if view.Since != "" {
+			if _, err := time.Parse(time.RFC3339, view.Since); err != nil {
+				view.Since = ""
+			}
+		}
+		render := func() error {
+			return templates.AdminCaptions(username, view).Render(ctx, c.Response().Writer)
+		}
+
+		channel := optionalString(view.Channel)
+		coverage, err := q.GetCaptionCoverage(ctx, channel)
+		if err != nil {
+			slog.Error("failed to load caption coverage", "error", err)
+			view.AlertType, view.AlertMsg = "error", "Failed to load caption coverage."
+			return render()
+		}
+		view.TotalCount = coverage.TotalCount
+		view.CaptionedCount = coverage.CaptionedCount
+
+		if langs, err := q.ListCaptionLanguageCounts(ctx, channel); err != nil {
+			slog.Warn("failed to list caption languages", "error", err)
+		} else {
+			for _, l := range langs {
+				view.Languages = append(view.Languages, templates.AdminCaptionLanguage{Lang: l.Lang, Count: l.VideoCount})
+			}
+		}
+
+		if channels, err := q.ListCaptionChannels(ctx); err != nil {
+			slog.Warn("failed to list caption channels", "error", err)
+		} else {
+			for _, ch := range channels {
+				view.Channels = append(view.Channels, templates.AdminCaptionChannel{
+					ID:           ch.ChannelID,
+					Name:         ch.Uploader,
+					TotalCount:   ch.TotalCount,
+					MissingCount: ch.MissingCount,
+				})
+			}
+		}
+
+		rows, err := q.ListCaptionAuditVideos(ctx, &db.ListCaptionAuditVideosParams{
+			ChannelID: channel,
+			Presence:  optionalString(view.Presence),
+			Lang:      optionalString(view.Lang),
+			PageLimit: maxCaptionAuditVideos,
+		})
+		if err != nil {
+			slog.Error("failed to list caption audit videos", "error", err)
+			view.AlertType, view.AlertMsg = "error", "Failed to load videos."
+			return render()
+		}
+		for _, r := range rows {
+			view.Videos = append(view.Videos, templates.AdminCaptionVideo{
+				ID:        r.ID.String(),
+				Title:     r.Title,
+				Uploader:  r.Uploader,
+				Langs:     r.Langs,
+				CreatedAt: r.CreatedAt.Time.Format("2006-01-02"),
+			})
+		}
+
+		return render()
+	}
+}
+
+// HandleAdminCaptionsBackfill serves POST /admin/captions/backfill, queueing a
+// Whisper captions regeneration job for every caption-less video (or only
+// those of form field channel_id). Videos whose last attempt failed or found
+// no speech within captionBackfillRetryAfter are left out. Redirects back to
+// the audit page tracking the run.
+func HandleAdminCaptionsBackfill(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+		channelID := strings.TrimSpace(c.FormValue("channel_id"))
+
+		// Subtract a second so jobs created in this request are never excluded
+		// by clock skew between the web server and Postgres.
+		since := time.Now().Add(-time.Second)
+
+		queued := 0
+		cursor := pgtype.UUID{Valid: true}
+		for {
+			ids, err := q.EnqueueCaptionBackfillBatch(ctx, &db.EnqueueCaptionBackfillBatchParams{
+				AfterID:     cursor,
+				ChannelID:   optionalString(channelID),
+				RetryBefore: pgtype.Timestamptz{Time: since.Add(-captionBackfillRetryAfter), Valid: true},
+				BatchSize:   captionBackfillBatchSize,
+			})
+			if err != nil {
+				slog.Error("failed to enqueue caption backfill batch", "channel_id", channelID, "queued", queued, "error", err)
+				if queued == 0 {
+					return c.Redirect(http.StatusFound, "/admin/captions?err="+url.QueryEscape("Failed to queue caption jobs"))
+				}
+				break
+			}
+			queued += len(ids)
+			if len(ids) < captionBackfillBatchSize {
+				break
+			}
+			cursor = ids[len(ids)-1]
+		}
+		slog.Info("caption backfill queued", "channel_id", channelID, "videos", queued)
+
+		params := url.Values{}
+		if channelID != "" {
+			params.Set("channel", channelID)
+		}
+		params.Set("presence", "missing")
+		if queued == 0 {
+			params.Set("msg", "No caption-less videos needed a new job.")
+		} else {
+			params.Set("msg", fmt.Sprintf("Queued Whisper captioning for %d videos.", queued))
+			params.Set("since", since.UTC().Format(time.RFC3339))
+		}
+		return c.Redirect(http.StatusFound, "/admin/captions?"+params.Encode())
+	}
+}
+
+// HandleAdminCaptionsProgress serves GET /admin/captions/progress?since=<RFC3339>,
+// returning status counts for caption regeneration jobs created since then.
+func HandleAdminCaptionsProgress(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		since, err := time.Parse(time.RFC3339, strings.TrimSpace(c.QueryParam("since")))
+		if err != nil {
+			return c.String(http.StatusBadRequest, "invalid since: expected RFC3339 timestamp")
+		}
+
+		ctx := c.Request().Context()
+		row, err := dbc.Queries(ctx).GetCaptionBackfillProgress(ctx, pgtype.Timestamptz{Time: since, Valid: true})
+		if err != nil {
+			slog.Error("failed to load caption backfill progress", "error", err)
+			return c.String(http.StatusInternalServerError, "failed to load progress")
+		}
+
+		done := row.SucceededCount + row.FailedCount
+		return c.JSON(http.StatusOK, map[string]any{
+			"total":      row.TotalCount,
+			"queued":     row.QueuedCount,
+			"processing": row.ProcessingCount,
+			"succeeded":  row.SucceededCount,
+			"failed":     row.FailedCount,
+			"complete":   row.TotalCount > 0 && done == row.TotalCount,
+		})
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
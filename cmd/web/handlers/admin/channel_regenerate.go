@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// channelRegenerateBatchSize bounds how many job pairs one INSERT creates.
+const channelRegenerateBatchSize = 200
+
+// HandleAdminChannelRegenerateAssets serves POST /admin/channels/:id/regenerate-assets,
+// queueing an asset regeneration job for every video of a channel.
+// Query param ?scope=thumbnail|preview|seek|waveform|captions|streams limits the
+// regeneration to one asset type; omitting it regenerates everything.
+//
+// The response includes a "since" timestamp; pass it to the matching GET
+// endpoint to track progress of this run.
+func HandleAdminChannelRegenerateAssets(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		channelID := strings.TrimSpace(c.Param("id"))
+		if channelID == "" {
+			return c.String(http.StatusBadRequest, "missing channel id")
+		}
+
+		var assetScope *string
+		if raw := strings.TrimSpace(c.QueryParam("scope")); raw != "" {
+			if !db.ValidAssetScopes[raw] {
+				return c.String(http.StatusBadRequest, "invalid scope")
+			}
+			assetScope = &raw
+		}
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		total, err := q.CountVideosByChannel(ctx, &channelID)
+		if err != nil {
+			slog.Error("failed to count channel videos", "channel_id", channelID, "error", err)
+			return c.String(http.StatusInternalServerError, "failed to load channel")
+		}
+		if total == 0 {
+			return c.String(http.StatusNotFound, "no videos for channel")
+		}
+
+		// Subtract a second so jobs created in this request are never excluded
+		// by clock skew between the web server and Postgres.
+		since := time.Now().Add(-time.Second)
+
+		queued := 0
+		cursor := pgtype.UUID{Valid: true}
+		for {
+			ids, err := q.EnqueueChannelAssetRegenerationBatch(ctx, &db.EnqueueChannelAssetRegenerationBatchParams{
+				ChannelID:  &channelID,
+				AfterID:    cursor,
+				BatchSize:  channelRegenerateBatchSize,
+				AssetScope: assetScope,
+			})
+			if err != nil {
+				slog.Error("failed to enqueue channel regeneration batch", "channel_id", channelID, "queued", queued, "error", err)
+				if queued == 0 {
+					return c.String(http.StatusInternalServerError, "failed to create regeneration jobs")
+				}
+				break
+			}
+			queued += len(ids)
+			slog.Info("channel regeneration batch queued", "channel_id", channelID, "batch", len(ids), "queued", queued, "total", total)
+			if len(ids) < channelRegenerateBatchSize {
+				break
+			}
+			cursor = ids[len(ids)-1]
+		}
+
+		scopeLabel := "all"
+		if assetScope != nil {
+			scopeLabel = *assetScope
+		}
+		slog.Info("channel asset regeneration queued", "channel_id", channelID, "videos", queued, "scope", scopeLabel)
+
+		return c.JSON(http.StatusOK, map[string]any{
+			"channel_id": channelID,
+			"scope":      scopeLabel,
+			"total":      total,
+			"queued":     queued,
+			"since":      since.UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// HandleAdminChannelRegenerateProgress serves GET /admin/channels/:id/regenerate-assets?since=<RFC3339>,
+// returning status counts for the channel's regeneration jobs created since then.
+func HandleAdminChannelRegenerateProgress(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		channelID := strings.TrimSpace(c.Param("id"))
+		if channelID == "" {
+			return c.String(http.StatusBadRequest, "missing channel id")
+		}
+
+		since, err := time.Parse(time.RFC3339, strings.TrimSpace(c.QueryParam("since")))
+		if err != nil {
+			return c.String(http.StatusBadRequest, "invalid since: expected RFC3339 timestamp")
+		}
+
+		ctx := c.Request().Context()
+		row, err := dbc.Queries(ctx).GetChannelAssetRegenerationProgress(ctx, &db.GetChannelAssetRegenerationProgressParams{
+			ChannelID: &channelID,
+			Since:     pgtype.Timestamptz{Time: since, Valid: true},
+		})
+		if err != nil {
+			slog.Error("failed to load channel regeneration progress", "channel_id", channelID, "error", err)
+			return c.String(http.StatusInternalServerError, "failed to load progress")
+		}
+
+		done := row.SucceededCount + row.FailedCount
+		return c.JSON(http.StatusOK, map[string]any{
+			"channel_id": channelID,
+			"total":      row.TotalCount,
+			"queued":     row.QueuedCount,
+			"processing": row.ProcessingCount,
+			"succeeded":  row.SucceededCount,
+			"failed":     row.FailedCount,
+			"complete":   row.TotalCount > 0 && done == row.TotalCount,
+		})
+	}
+}
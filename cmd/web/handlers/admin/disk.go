@@ -0,0 +1,16 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/internal/diskmonitor"
+)
+
+// HandleAdminDiskStatus serves GET /admin/disk, returning the latest
+// free-space check for every monitored volume as JSON.
+func HandleAdminDiskStatus(mon *diskmonitor.Monitor) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, mon.Status())
+	}
+}
@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/templates"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/phash"
+)
+
+const (
+	defaultDuplicateDistance = 8
+	maxDuplicateDistance     = phash.MaxPairDistance
+	maxDuplicatePairs        = 200
+)
+
+// HandleAdminDuplicatesPage serves GET /admin/duplicates, listing video pairs
+// whose perceptual hashes are within ?max_distance= bits of each other.
+func HandleAdminDuplicatesPage(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		username, _ := c.Get("currentUsername").(string)
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		maxDistance := defaultDuplicateDistance
+		if raw := strings.TrimSpace(c.QueryParam("max_distance")); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 || n > maxDuplicateDistance {
+				return templates.AdminDuplicates(username, 0, defaultDuplicateDistance, nil, "error", "Max distance must be between 0 and 20.").Render(ctx, c.Response().Writer)
+			}
+			maxDistance = n
+		}
+
+		hashed, err := q.CountVideosWithPHash(ctx)
+		if err != nil {
+			slog.Error("failed to count hashed videos", "error", err)
+			return templates.AdminDuplicates(username, 0, maxDistance, nil, "error", "Failed to load perceptual hashes.").Render(ctx, c.Response().Writer)
+		}
+
+		rows, err := q.ListNearDuplicateVideos(ctx, &db.ListNearDuplicateVideosParams{
+			MaxDistance: int32(maxDistance),
+			MaxCount:    maxDuplicatePairs,
+		})
+		if err != nil {
+			slog.Error("failed to list near-duplicate videos", "error", err)
+			return templates.AdminDuplicates(username, hashed, maxDistance, nil, "error", "Failed to compare videos.").Render(ctx, c.Response().Writer)
+		}
+
+		pairs := make([]templates.AdminDuplicatePair, 0, len(rows))
+		for _, r := range rows {
+			pairs = append(pairs, templates.AdminDuplicatePair{
+				VideoID:       r.VideoID.String(),
+				VideoTitle:    r.VideoTitle,
+				OtherID:       r.OtherID.String(),
+				OtherTitle:    r.OtherTitle,
+				Distance:      int(r.Distance),
+				FrameDistance: phash.SequenceDistance(frameHashes(r.VideoFrames), frameHashes(r.OtherFrames)),
+				ExactMatch:    r.VideoFileHash != nil && r.OtherFileHash != nil && *r.VideoFileHash != "" && *r.VideoFileHash == *r.OtherFileHash,
+			})
+		}
+
+		return templates.AdminDuplicates(username, hashed, maxDistance, pairs, "", "").Render(ctx, c.Response().Writer)
+	}
+}
+
+// frameHashes converts stored BIGINT bit patterns back to hashes.
+func frameHashes(stored []int64) []uint64 {
+	out := make([]uint64, len(stored))
+	for i, v := range stored {
+		out[i] = uint64(v)
+	}
+	return out
+}
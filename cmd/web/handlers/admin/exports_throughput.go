@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/starfederation/datastar-go/datastar"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/templates"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/utils/format"
+)
+
+const (
+	exportThroughputInterval = 2 * time.Second
+	maxInFlightExportsShown  = 25
+)
+
+// HandleAdminExportsThroughput serves GET /admin/exports/throughput, streaming
+// the encoder throughput panel via SSE until the client disconnects. Numbers
+// come from clip_exports, so they cover every encoder worker.
+func HandleAdminExportsThroughput(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+		sse := datastar.NewSSE(c.Response().Writer, c.Request())
+
+		push := func() error {
+			stats, err := q.GetClipExportThroughput(ctx)
+			if err != nil {
+				return err
+			}
+			inFlight, err := q.ListInFlightClipExports(ctx, maxInFlightExportsShown)
+			if err != nil {
+				return err
+			}
+			view := buildExportThroughput(stats, inFlight, time.Now())
+			return sse.PatchElementTempl(templates.AdminExportThroughputPanel(view), datastar.WithSelectorID("export-throughput"))
+		}
+
+		if err := push(); err != nil {
+			slog.Error("failed to stream export throughput", "error", err)
+			return nil
+		}
+
+		ticker := time.NewTicker(exportThroughputInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := push(); err != nil {
+					if ctx.Err() == nil {
+						slog.Error("failed to stream export throughput", "error", err)
+					}
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// buildExportThroughput turns the throughput queries into the panel view.
+// Per-export ETAs extrapolate linearly from progress so far; the backlog ETA
+// assumes the last hour's completion rate holds.
+func buildExportThroughput(stats *db.GetClipExportThroughputRow, inFlight []*db.ListInFlightClipExportsRow, now time.Time) templates.AdminExportThroughput {
+	view := templates.AdminExportThroughput{
+		CompletedLastHour: stats.CompletedLastHour,
+		FailedLastHour:    stats.FailedLastHour,
+		QueuedCount:       stats.QueuedCount,
+		ProcessingCount:   stats.ProcessingCount,
+		ActiveWorkers:     stats.ActiveWorkers,
+		InFlight:          make([]templates.AdminExportInFlight, 0, len(inFlight)),
+		UpdatedAt:         now.Format("15:04:05"),
+	}
+	if stats.AvgEncodeSeconds > 0 {
+		view.AvgEncode = format.Duration(stats.AvgEncodeSeconds)
+	}
+	if stats.QueuedCount > 0 && stats.CompletedLastHour > 0 {
+		drain := float64(stats.QueuedCount) / float64(stats.CompletedLastHour) * 3600
+		view.BacklogETA = format.DurationHuman(int64(drain))
+	}
+
+	for _, e := range inFlight {
+		row := templates.AdminExportInFlight{
+			ID:          e.ID.String(),
+			ClipLabel:   e.ClipLabel,
+			VideoTitle:  e.VideoTitle,
+			Format:      e.Format,
+			ProgressPct: e.ProgressPct,
+		}
+		if e.LockedBy != nil {
+			row.Worker = strings.TrimPrefix(*e.LockedBy, "encoder-")
+		}
+		if e.StartedAt.Valid {
+			elapsed := now.Sub(e.StartedAt.Time).Seconds()
+			if elapsed < 0 {
+				elapsed = 0
+			}
+			row.Elapsed = format.Duration(elapsed)
+			if e.ProgressPct > 0 && e.ProgressPct < 100 {
+				pct := float64(e.ProgressPct)
+				row.ETA = format.Duration(elapsed * (100 - pct) / pct)
+			}
+		}
+		view.InFlight = append(view.InFlight, row)
+	}
+	return view
+}
@@ -8,11 +8,13 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/auth"
 	"thirdcoast.systems/rewind/cmd/web/templates"
 	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/internal/diskmonitor"
 	"thirdcoast.systems/rewind/pkg/utils/format"
 )
 
 // HandleAdminHomePage serves GET /admin, rendering the admin dashboard with system metrics and charts.
-func HandleAdminHomePage(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+// A banner lists any storage volumes the disk monitor reports as low.
+func HandleAdminHomePage(sm *auth.SessionManager, dbc *db.DatabaseConnection, mon *diskmonitor.Monitor) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		username, _ := c.Get("currentUsername").(string)
 		alertType := ""
@@ -45,6 +47,18 @@ func HandleAdminHomePage(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 			metrics.DurationDisplay = formatDurationDays(overview.TotalDurationSeconds)
 		}
 
+		if mon != nil {
+			status := mon.Status()
+			for _, v := range status.Low() {
+				metrics.LowDiskVolumes = append(metrics.LowDiskVolumes, templates.LowDiskVolume{
+					Path:        v.Path,
+					FreeDisplay: format.Bytes(int64(v.FreeBytes)),
+					FreePercent: v.FreePercent,
+				})
+			}
+			metrics.DownloadsPaused = status.DownloadsPaused
+		}
+
 		// Fetch job statuses
 		var jobStatuses []templates.JobStatusRow
 		jobRows, err := q.GetJobStatusCounts(ctx)
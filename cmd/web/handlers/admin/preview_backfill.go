@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+const (
+	previewBackfillBatchSize = 200
+	// defaultPreviewBackfillRate and maxPreviewBackfillRate are in jobs per
+	// minute.
+	defaultPreviewBackfillRate = 30
+	maxPreviewBackfillRate     = 600
+	maxPreviewBackfillLimit    = 10000
+)
+
+// HandleAdminBackfillPreviews serves POST /admin/assets/backfill-previews,
+// queueing preview-only regeneration jobs for videos whose preview is missing
+// (assets_status.preview = false). Form value "rate" (jobs per minute, default
+// 30) spaces the jobs out so a large backlog does not tie up every ingest
+// worker; "limit" caps how many videos one run queues. Videos that already
+// have a pending preview job are skipped, so the action can be repeated; jobs
+// still waiting for their slot from an earlier run are rescheduled at the new
+// rate.
+func HandleAdminBackfillPreviews(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		fail := func(msg string) error {
+			return c.Redirect(302, "/admin/asset-health?err="+url.QueryEscape(msg))
+		}
+
+		rate := defaultPreviewBackfillRate
+		if raw := strings.TrimSpace(c.FormValue("rate")); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxPreviewBackfillRate {
+				return fail(fmt.Sprintf("Rate must be between 1 and %d jobs per minute", maxPreviewBackfillRate))
+			}
+			rate = n
+		}
+		limit := maxPreviewBackfillLimit
+		if raw := strings.TrimSpace(c.FormValue("limit")); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				return fail("Invalid limit")
+			}
+			limit = min(n, maxPreviewBackfillLimit)
+		}
+		interval := 60 / float64(rate)
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		// Drop the jobs an earlier run scheduled but has not started yet, so
+		// this run's rate replaces it rather than stacking on top of it.
+		replaced, err := q.ResetPreviewBackfillSchedule(ctx)
+		if err != nil {
+			slog.Error("failed to reset preview backfill schedule", "error", err)
+			return fail("Failed to reset the previous preview backfill")
+		}
+
+		queued := 0
+		cursor := pgtype.UUID{Valid: true}
+		for queued < limit {
+			batchSize := min(previewBackfillBatchSize, limit-queued)
+			ids, err := q.EnqueuePreviewBackfillBatch(ctx, &db.EnqueuePreviewBackfillBatchParams{
+				AfterID:         cursor,
+				BatchSize:       int32(batchSize),
+				FirstSlot:       int32(queued),
+				IntervalSeconds: interval,
+			})
+			if err != nil {
+				slog.Error("failed to enqueue preview backfill batch", "queued", queued, "error", err)
+				if queued == 0 {
+					return fail("Failed to queue preview jobs")
+				}
+				break
+			}
+			queued += len(ids)
+			if len(ids) < batchSize {
+				break
+			}
+			cursor = ids[len(ids)-1]
+		}
+
+		if queued == 0 {
+			return c.Redirect(302, "/admin/asset-health?msg="+url.QueryEscape("No videos need a preview backfill"))
+		}
+		spread := time.Duration(float64(queued-1) * interval * float64(time.Second)).Round(time.Second)
+		slog.Info("preview backfill queued", "videos", queued, "replaced", replaced, "rate_per_minute", rate, "spread", spread)
+		return c.Redirect(302, "/admin/asset-health?msg="+url.QueryEscape(
+			fmt.Sprintf("Queued preview generation for %d videos at %d per minute (last starts in %s)", queued, rate, spread)))
+	}
+}
@@ -1,17 +1,21 @@
 package admin
 
 import (
+	"fmt"
 	"log/slog"
+	"math"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/dustin/go-humanize"
 	"github.com/labstack/echo/v4"
 	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/internal/archival"
 	"thirdcoast.systems/rewind/internal/db"
 )
 
-// HandleAdminSettings serves POST /admin/settings, persisting registration, storage-limit, and email settings.
+// HandleAdminSettings serves POST /admin/settings, persisting registration, approval, storage-limit, email, download quality, and job retry settings.
 func HandleAdminSettings(sm *auth.SessionManager, dbc *db.DatabaseConnection, sc *db.SettingsCache) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		enabled := c.FormValue("registration_enabled") != ""
@@ -61,6 +65,77 @@ func HandleAdminSettings(sm *auth.SessionManager, dbc *db.DatabaseConnection, sc
 			}
 		}
 
+		requiresApproval := c.FormValue("registration_requires_approval") != ""
+		if err := q.UpsertRegistrationRequiresApproval(c.Request().Context(), requiresApproval); err != nil {
+			if !db.IsUndefinedColumnErr(err) {
+				slog.Error("failed to update registration_requires_approval", "error", err)
+				return c.Redirect(302, "/settings?err="+url.QueryEscape("Failed to update settings"))
+			}
+		}
+
+		// Per-role download resolution caps; absent on older forms.
+		if c.FormValue("user_download_max_height") != "" {
+			userCap, err := parseDownloadHeightCap(c.FormValue("user_download_max_height"))
+			if err != nil {
+				return c.Redirect(302, "/settings?err="+url.QueryEscape(err.Error()))
+			}
+			adminCap, err := parseDownloadHeightCap(c.FormValue("admin_download_max_height"))
+			if err != nil {
+				return c.Redirect(302, "/settings?err="+url.QueryEscape(err.Error()))
+			}
+			if err := q.UpsertDownloadHeightCaps(c.Request().Context(), &db.UpsertDownloadHeightCapsParams{
+				UserMaxHeight:  userCap,
+				AdminMaxHeight: adminCap,
+			}); err != nil {
+				if !db.IsUndefinedColumnErr(err) {
+					slog.Error("failed to update download height caps", "error", err)
+					return c.Redirect(302, "/settings?err="+url.QueryEscape("Failed to update settings"))
+				}
+			}
+		}
+
+		// Player quality menu cap; absent on older forms.
+		if raw := c.FormValue("playback_max_height"); raw != "" {
+			playbackCap, err := parseDownloadHeightCap(raw)
+			if err != nil {
+				return c.Redirect(302, "/settings?err="+url.QueryEscape(err.Error()))
+			}
+			if err := q.UpsertPlaybackMaxHeight(c.Request().Context(), playbackCap); err != nil {
+				if !db.IsUndefinedColumnErr(err) {
+					slog.Error("failed to update playback_max_height", "error", err)
+					return c.Redirect(302, "/settings?err="+url.QueryEscape("Failed to update settings"))
+				}
+			}
+		}
+
+		// Job retry policies; the form always posts both, so a missing field
+		// means an older form and leaves the policies unchanged.
+		if c.FormValue("download_retry_max_attempts") != "" {
+			download, err := parseRetryPolicy(c, "download")
+			if err != nil {
+				return c.Redirect(302, "/settings?err="+url.QueryEscape(err.Error()))
+			}
+			ingest, err := parseRetryPolicy(c, "ingest")
+			if err != nil {
+				return c.Redirect(302, "/settings?err="+url.QueryEscape(err.Error()))
+			}
+			if err := q.UpsertJobRetryPolicy(c.Request().Context(), &db.UpsertJobRetryPolicyParams{
+				DownloadMaxAttempts: download.MaxAttempts,
+				DownloadBackoff:     download.Backoff,
+				DownloadBaseSeconds: download.BaseSeconds,
+				DownloadCapSeconds:  download.CapSeconds,
+				IngestMaxAttempts:   ingest.MaxAttempts,
+				IngestBackoff:       ingest.Backoff,
+				IngestBaseSeconds:   ingest.BaseSeconds,
+				IngestCapSeconds:    ingest.CapSeconds,
+			}); err != nil {
+				if !db.IsUndefinedColumnErr(err) {
+					slog.Error("failed to update job retry policy", "error", err)
+					return c.Redirect(302, "/settings?err="+url.QueryEscape("Failed to update settings"))
+				}
+			}
+		}
+
 		// Update admin emails
 		if err := q.UpsertAdminEmails(c.Request().Context(), adminEmails); err != nil {
 			if !db.IsUndefinedColumnErr(err) {
@@ -78,3 +153,43 @@ func HandleAdminSettings(sm *auth.SessionManager, dbc *db.DatabaseConnection, sc
 }
 
 // HandleAdminUsersPage shows the user management page.
+
+// parseRetryPolicy reads the <prefix>_retry_* form fields into a retry policy.
+func parseRetryPolicy(c echo.Context, prefix string) (db.RetryPolicy, error) {
+	field := func(name string, lo, hi int) (int32, error) {
+		raw := strings.TrimSpace(c.FormValue(prefix + "_retry_" + name))
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < lo || n > hi {
+			return 0, fmt.Errorf("%s retry %s must be a whole number from %d to %d", prefix, strings.ReplaceAll(name, "_", " "), lo, hi)
+		}
+		return int32(n), nil
+	}
+	var p db.RetryPolicy
+	var err error
+	if p.MaxAttempts, err = field("max_attempts", 1, 20); err != nil {
+		return p, err
+	}
+	// A week is far longer than any sensible retry delay.
+	const maxDelay = 7 * 24 * 60 * 60
+	if p.BaseSeconds, err = field("base_seconds", 0, maxDelay); err != nil {
+		return p, err
+	}
+	if p.CapSeconds, err = field("cap_seconds", 0, maxDelay); err != nil {
+		return p, err
+	}
+	p.Backoff = c.FormValue(prefix + "_retry_backoff")
+	if p.Backoff != db.BackoffLinear && p.Backoff != db.BackoffExponential {
+		return p, fmt.Errorf("%s retry backoff must be linear or exponential", prefix)
+	}
+	return p, nil
+}
+
+// parseDownloadHeightCap parses a resolution cap form value for downloads or
+// playback (0 for best available, or one of archival.DownloadHeightCaps).
+func parseDownloadHeightCap(raw string) (int32, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || n < 0 || n > math.MaxInt32 || !archival.ValidDownloadHeightCap(int32(n)) {
+		return 0, fmt.Errorf("invalid resolution cap %q", raw)
+	}
+	return int32(n), nil
+}
@@ -0,0 +1,42 @@
+package admin
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// HandleAdminUserDownloadQuality serves POST /admin/users/:id/download-quality,
+// setting a user's download resolution cap. An empty value clears the
+// override so the user's role cap applies again.
+func HandleAdminUserDownloadQuality(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q := dbc.Queries(c.Request().Context())
+
+		targetUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return c.Redirect(302, "/admin/users?err="+url.QueryEscape("Invalid user id"))
+		}
+
+		var maxHeight *int32
+		if raw := strings.TrimSpace(c.FormValue("download_max_height")); raw != "" {
+			h, err := parseDownloadHeightCap(raw)
+			if err != nil {
+				return c.Redirect(302, "/admin/users?err="+url.QueryEscape(err.Error()))
+			}
+			maxHeight = &h
+		}
+
+		if err := q.SetUserDownloadMaxHeight(c.Request().Context(), &db.SetUserDownloadMaxHeightParams{ID: targetUUID, DownloadMaxHeight: maxHeight}); err != nil {
+			slog.Error("failed to update user download cap", "error", err)
+			return c.Redirect(302, "/admin/users?err="+url.QueryEscape("Failed to update download quality"))
+		}
+
+		return c.Redirect(302, "/admin/users?msg="+url.QueryEscape("User updated"))
+	}
+}
@@ -31,7 +31,10 @@ func HandleAdminUsersPage(sm *auth.SessionManager, dbc *db.DatabaseConnection) e
 				Email:    u.Email,
 				Role:     string(u.Role),
 				Enabled:  u.Enabled,
+				Pending:  u.PendingApproval,
 				IsSelf:   u.ID.String() == currentUserUUID.String(),
+
+				DownloadMaxHeight: u.DownloadMaxHeight,
 			})
 		}
 
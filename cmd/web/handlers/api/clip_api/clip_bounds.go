@@ -0,0 +1,73 @@
+package clip_api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// clipBoundsTolerance is how far (seconds) a clip may run past the end of the
+// video and still be accepted, clamped to the video length. It absorbs player
+// rounding when a clip is dragged to the very end.
+const clipBoundsTolerance = 0.5
+
+// VideoDurationSeconds returns the best known length of a video in seconds:
+// the probed container duration when available, otherwise the metadata
+// duration plus one second, since that value is truncated to whole seconds.
+// Returns 0 when the length is unknown.
+func VideoDurationSeconds(v *db.Video) float64 {
+	if v == nil {
+		return 0
+	}
+	if v.ProbeData != nil {
+		if d, err := strconv.ParseFloat(strings.TrimSpace(v.ProbeData.Format.Duration), 64); err == nil && d > 0 {
+			return d
+		}
+	}
+	if v.DurationSeconds != nil && *v.DurationSeconds > 0 {
+		return float64(*v.DurationSeconds) + 1
+	}
+	return 0
+}
+
+// ClampClipBounds validates a clip's start/end against a video of length
+// videoDuration (0 = unknown, only ordering is checked). An end that overruns
+// the video by at most clipBoundsTolerance is clamped to the video length;
+// anything further out is rejected with an error suitable for the client.
+func ClampClipBounds(start, end, videoDuration float64) (float64, float64, error) {
+	if start < 0 || end < 0 {
+		return start, end, fmt.Errorf("start_ts and end_ts must be >= 0")
+	}
+	if videoDuration > 0 {
+		if start >= videoDuration {
+			return start, end, fmt.Errorf("start_ts %.2fs is past the end of the video (%.2fs)", start, videoDuration)
+		}
+		if end > videoDuration {
+			if end-videoDuration > clipBoundsTolerance {
+				return start, end, fmt.Errorf("end_ts %.2fs is past the end of the video (%.2fs)", end, videoDuration)
+			}
+			end = videoDuration
+		}
+	}
+	if end <= start {
+		return start, end, fmt.Errorf("end_ts must be > start_ts")
+	}
+	return start, end, nil
+}
+
+// ClampClipUpdate is ClampClipBounds for an edit of a clip currently spanning
+// oldStart..oldEnd. Clips saved before bounds were checked can already run
+// past the end of the video; such a clip may still be saved as long as the
+// edit doesn't push it further out than it already is.
+func ClampClipUpdate(start, end, oldStart, oldEnd, videoDuration float64) (float64, float64, error) {
+	s, e, err := ClampClipBounds(start, end, videoDuration)
+	if err == nil || videoDuration <= 0 || start < 0 || end <= start {
+		return s, e, err
+	}
+	if end <= oldEnd && max(start, videoDuration) <= max(oldStart, videoDuration) {
+		return start, end, nil
+	}
+	return s, e, err
+}
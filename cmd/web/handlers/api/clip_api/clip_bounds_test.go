@@ -0,0 +1,84 @@
+package clip_api
+
+import (
+	"testing"
+
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+func TestClampClipBounds(t *testing.T) {
+	tests := []struct {
+		name               string
+		start, end, dur    float64
+		wantStart, wantEnd float64
+		wantErr            bool
+	}{
+		{"inside", 10, 20, 60, 10, 20, false},
+		{"whole video", 0, 60, 60, 0, 60, false},
+		{"overrun within tolerance is clamped", 50, 60.4, 60, 50, 60, false},
+		{"overrun beyond tolerance", 50, 61, 60, 0, 0, true},
+		{"start at end of video", 60, 61, 60, 0, 0, true},
+		{"start past end of video", 75, 80, 60, 0, 0, true},
+		{"negative start", -1, 5, 60, 0, 0, true},
+		{"negative end", 0, -5, 60, 0, 0, true},
+		{"end before start", 20, 10, 60, 0, 0, true},
+		{"zero length", 10, 10, 60, 0, 0, true},
+		{"clamp leaves zero length", 59.9, 60.3, 59.9, 0, 0, true},
+		{"unknown duration", 500, 900, 0, 500, 900, false},
+		{"unknown duration still ordered", 10, 5, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		start, end, err := ClampClipBounds(tt.start, tt.end, tt.dur)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (start != tt.wantStart || end != tt.wantEnd) {
+			t.Errorf("%s: got (%v, %v), want (%v, %v)", tt.name, start, end, tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func TestClampClipUpdate(t *testing.T) {
+	// The stored clip runs 50..70 on a 60s video, saved before bounds checks.
+	tests := []struct {
+		name       string
+		start, end float64
+		wantErr    bool
+	}{
+		{"unchanged legacy clip", 50, 70, false},
+		{"trimmed toward the video", 45, 65, false},
+		{"moved back inside", 40, 55, false},
+		{"end pushed further out", 50, 75, true},
+		{"start pushed past the end", 62, 70, true},
+		{"end before start", 70, 50, true},
+	}
+	for _, tt := range tests {
+		_, _, err := ClampClipUpdate(tt.start, tt.end, 50, 70, 60)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if _, _, err := ClampClipUpdate(50, 61, 10, 20, 60); err == nil {
+		t.Errorf("in-bounds clip allowed to overrun the video")
+	}
+}
+
+func TestVideoDurationSeconds(t *testing.T) {
+	secs := int32(90)
+	probe := videoinfo.NewProbeInfo([]byte(`{"streams":[{"codec_type":"video"}],"format":{"duration":"90.48"}}`))
+
+	if got := VideoDurationSeconds(&db.Video{DurationSeconds: &secs, ProbeData: probe}); got != 90.48 {
+		t.Errorf("probe duration: got %v, want 90.48", got)
+	}
+	if got := VideoDurationSeconds(&db.Video{DurationSeconds: &secs}); got != 91 {
+		t.Errorf("metadata duration: got %v, want 91 (truncation slack)", got)
+	}
+	if got := VideoDurationSeconds(&db.Video{}); got != 0 {
+		t.Errorf("unknown duration: got %v, want 0", got)
+	}
+	if got := VideoDurationSeconds(nil); got != 0 {
+		t.Errorf("nil video: got %v, want 0", got)
+	}
+}
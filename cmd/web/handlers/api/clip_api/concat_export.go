@@ -0,0 +1,185 @@
+package clip_api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// maxConcatClips caps how many clips one concat export may join.
+const maxConcatClips = 50
+
+// concatExportRequest is the body of POST /exports/concat.
+type concatExportRequest struct {
+	ClipIDs  []string `json:"clip_ids"` // In playback order; a clip may appear more than once
+	Format   string   `json:"format"`
+	Quality  string   `json:"quality"`
+	Accurate bool     `json:"accurate"` // Frame-accurate cuts instead of the fast default
+}
+
+type concatExportResponse struct {
+	ExportID    string       `json:"export_id"`
+	State       enqueueState `json:"state"`
+	StreamURL   string       `json:"stream_url"`
+	DownloadURL string       `json:"download_url"`
+}
+
+// concatVariant is the export variant of clips joined in order. It hashes
+// each clip's ID and last update, so an identical ready join is reused and
+// editing any of the clips makes a new one.
+func concatVariant(clips []*db.Clip) string {
+	h := sha256.New()
+	for _, clipRow := range clips {
+		fmt.Fprintf(h, "%s@%d\n", clipRow.ID.String(), clipRow.UpdatedAt.Time.UnixNano())
+	}
+	return "concat:" + hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// checkConcatClipFilters reports a user-facing error when a clip's saved
+// filter stack cannot be rendered in a concat export: an overlay image, LUT
+// or caption track is missing, or a filter does not compile for the clip.
+func checkConcatClipFilters(ctx context.Context, clipRow *db.Clip, userUUID pgtype.UUID, format string) error {
+	var specs []ffmpeg.FilterSpec
+	if len(clipRow.FilterStack) > 0 {
+		if err := json.Unmarshal(clipRow.FilterStack, &specs); err != nil {
+			return fmt.Errorf("invalid filter stack")
+		}
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+	plan := &clipExportPlan{Format: format, Filters: ffmpeg.StripInjectedParams(specs)}
+	resolved, err := checkSubtitles(ctx, plan, clipRow)
+	if err != nil {
+		return err
+	}
+	if resolved, err = resolveUserFilterFiles(resolved, userUUID); err != nil {
+		return err
+	}
+	_, err = ffmpeg.CompileFilters(ffmpeg.WithClipDuration(resolved, clipRow.Duration), clipRow.Crops)
+	return err
+}
+
+// HandleEnqueueConcatExport serves POST /exports/concat, rendering several of
+// the user's clips of one video back to back into a single file. The encoder
+// cuts and filters each clip as a single export would, with the clip's saved
+// filter stack, then joins the parts with the concat demuxer. The join is an
+// export of the first clip with a "concat:" variant, so it is reused, capped
+// and served like any other export.
+func HandleEnqueueConcatExport(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		var req concatExportRequest
+		if err := c.Bind(&req); err != nil {
+			return c.String(400, "invalid json")
+		}
+		if len(req.ClipIDs) < 2 {
+			return c.String(400, "clip_ids needs at least two clips")
+		}
+		if len(req.ClipIDs) > maxConcatClips {
+			return c.String(400, fmt.Sprintf("at most %d clips can be joined", maxConcatClips))
+		}
+		format := strings.TrimSpace(req.Format)
+		if format == "" {
+			format = "mp4"
+		}
+		if format != "mp4" && format != "webm" {
+			return c.String(400, "invalid format (mp4 or webm)")
+		}
+		quality := strings.TrimSpace(req.Quality)
+		if quality == "" {
+			quality = "high"
+		}
+		if quality != "high" && quality != "max" {
+			return c.String(400, "invalid quality (high or max)")
+		}
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		// Resolve every clip before queuing so a bad one queues nothing.
+		loaded := map[string]*db.Clip{}
+		clips := make([]*db.Clip, 0, len(req.ClipIDs))
+		ids := make([]string, 0, len(req.ClipIDs))
+		for _, raw := range req.ClipIDs {
+			var id pgtype.UUID
+			if err := id.Scan(strings.TrimSpace(raw)); err != nil {
+				return c.String(400, fmt.Sprintf("invalid clip id %q", raw))
+			}
+			clipRow, ok := loaded[id.String()]
+			if !ok {
+				clipRow, err = q.GetClip(ctx, id)
+				if errors.Is(err, pgx.ErrNoRows) {
+					return c.String(404, "clip not found: "+id.String())
+				}
+				if err != nil {
+					slog.Error("failed to load clip for concat export", "error", err, "clip_id", id.String())
+					return common.ErrInternal("failed to load clip")
+				}
+				if clipRow.CreatedBy != userUUID {
+					return c.String(403, "clip belongs to another user: "+id.String())
+				}
+				if err := checkConcatClipFilters(ctx, clipRow, userUUID, format); err != nil {
+					return c.String(400, fmt.Sprintf("filters invalid for clip %s: %v", id.String(), err))
+				}
+				loaded[id.String()] = clipRow
+			}
+			if len(clips) > 0 && clipRow.VideoID != clips[0].VideoID {
+				return c.String(400, "all clips must be from the same video")
+			}
+			clips = append(clips, clipRow)
+			ids = append(ids, clipRow.ID.String())
+		}
+
+		plan := &clipExportPlan{
+			Format:   format,
+			Quality:  quality,
+			Variant:  concatVariant(clips),
+			Accurate: req.Accurate,
+		}
+		plan.Spec, _ = json.Marshal(ffmpeg.ExportSpec{
+			Format:   format,
+			Quality:  quality,
+			Accurate: req.Accurate,
+			Concat:   ids,
+		})
+		exportID, state, err := EnqueueClipExport(ctx, dbc, clips[0], userUUID, plan)
+		if errors.Is(err, errExportCapReached) {
+			return c.String(http.StatusTooManyRequests, err.Error())
+		}
+		if err != nil {
+			slog.Error("failed to create concat export", "error", err)
+			return common.ErrInternal("failed to queue export")
+		}
+
+		slog.Info("concat export enqueued", "user_id", userUUID.String(), "export_id", exportID.String(), "clips", len(clips), "state", state)
+		status := http.StatusAccepted
+		if state == enqueueReady {
+			status = http.StatusOK
+		}
+		return c.JSON(status, concatExportResponse{
+			ExportID:    exportID.String(),
+			State:       state,
+			StreamURL:   "/api/clip-exports/" + exportID.String() + "/stream",
+			DownloadURL: "/api/clip-exports/" + exportID.String() + "/download",
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package clip_api
 
 import (
+	"path/filepath"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -39,6 +40,11 @@ func HandleDelete(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 		if err := dbc.Queries(ctx).DeleteClip(ctx, clipUUID); err != nil {
 			return c.String(500, "failed to delete clip")
 		}
+		if video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoID); err == nil {
+			if p := common.DerefString(video.VideoPath); p != "" {
+				removeClipThumbnails(filepath.Dir(p), videoID.String(), clipUUID.String())
+			}
+		}
 
 		// SSE response for DataStar
 		common.SetSSEHeaders(c)
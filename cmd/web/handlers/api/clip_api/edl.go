@@ -0,0 +1,108 @@
+package clip_api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/filters"
+	"thirdcoast.systems/rewind/pkg/utils/edl"
+	"thirdcoast.systems/rewind/pkg/utils/filename"
+)
+
+// HandleClipEDL serves GET /api/clips/:id/edl, describing the clip for
+// external editors. ?format= selects json (default), cmx3600 or fcpxml; the
+// latter two only carry the source and in/out points.
+func HandleClipEDL(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		clipUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+		switch format {
+		case "", "json", "cmx3600", "edl", "fcpxml":
+		default:
+			return common.ErrBadRequest("format must be json, cmx3600 or fcpxml")
+		}
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		clip, err := q.GetClip(ctx, clipUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("clip not found")
+			}
+			return common.ErrInternal("failed to load clip")
+		}
+		video, err := q.GetVideoByID(ctx, clip.VideoID)
+		if err != nil {
+			slog.Error("clip edl: failed to load source video", "clip_id", clip.ID.String(), "error", err)
+			return common.ErrInternal("failed to load source video")
+		}
+
+		var stack []filters.FilterStackEntry
+		if len(clip.FilterStack) > 0 {
+			if err := json.Unmarshal(clip.FilterStack, &stack); err != nil {
+				slog.Warn("clip edl: ignoring unreadable filter stack", "clip_id", clip.ID.String(), "error", err)
+				stack = nil
+			}
+		}
+
+		desc := edl.Clip{
+			ID:          clip.ID.String(),
+			Title:       clip.Title,
+			Description: clip.Description,
+			Source: edl.Source{
+				VideoID: video.ID.String(),
+				Title:   video.Title,
+				URL:     video.Src,
+			},
+			In:      clip.StartTs,
+			Out:     clip.EndTs,
+			FPS:     video.Info.GetFPS(),
+			Filters: stack,
+			Crops:   clip.Crops,
+		}
+		if video.VideoPath != nil {
+			desc.Source.Filename = filepath.Base(*video.VideoPath)
+		}
+		if video.DurationSeconds != nil {
+			desc.Source.Duration = float64(*video.DurationSeconds)
+		}
+		desc.Normalize()
+
+		base := filename.Sanitize(clip.Title, 0)
+		if base == "" {
+			base = "clip-" + desc.ID
+		}
+		switch format {
+		case "cmx3600", "edl":
+			c.Response().Header().Set("Content-Disposition", filename.ContentDisposition(base+".edl"))
+			return c.Blob(http.StatusOK, "text/plain; charset=utf-8", []byte(edl.CMX3600(desc)))
+		case "fcpxml":
+			out, err := edl.FCPXML(desc)
+			if err != nil {
+				return common.ErrInternal("failed to build fcpxml")
+			}
+			c.Response().Header().Set("Content-Disposition", filename.ContentDisposition(base+".fcpxml"))
+			return c.Blob(http.StatusOK, "application/xml", out)
+		default:
+			return c.JSON(http.StatusOK, desc)
+		}
+	}
+}
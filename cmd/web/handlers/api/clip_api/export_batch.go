@@ -0,0 +1,179 @@
+package clip_api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// batchExportRequest is the body of POST /exports/batch: one shared export
+// spec applied to every listed clip.
+type batchExportRequest struct {
+	ClipIDs []string `json:"clip_ids"`
+	exportRequest
+}
+
+type batchExportItem struct {
+	ClipID     string                 `json:"clip_id"`
+	ExportID   string                 `json:"export_id"`
+	State      enqueueState           `json:"state"`
+	Renditions []batchExportRendition `json:"renditions,omitempty"`
+}
+
+// batchExportRendition is one export of a rendition ladder.
+type batchExportRendition struct {
+	Height   int    `json:"height"`
+	ExportID string `json:"export_id"`
+}
+
+type batchExportResponse struct {
+	ExportIDs []string          `json:"export_ids"`
+	Exports   []batchExportItem `json:"exports"`
+}
+
+// HandleBatchExport serves POST /exports/batch, enqueuing one export per clip
+// with the same format, quality, loop, GOP and filter stack (e.g. a watermark
+// across a channel's clips). Identical existing exports are reused, as with a
+// single export. Crop variants are per clip, so only the full frame is allowed.
+func HandleBatchExport(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		var req batchExportRequest
+		if err := c.Bind(&req); err != nil {
+			return c.String(400, "invalid json")
+		}
+		if v := strings.TrimSpace(req.Variant); v != "" && v != "full" {
+			return c.String(400, "batch exports only support the full variant")
+		}
+
+		plan, err := newClipExportPlan(req.exportRequest)
+		if err != nil {
+			return c.String(400, err.Error())
+		}
+
+		// Parse and de-duplicate clip IDs, keeping request order.
+		seen := map[string]bool{}
+		var clipUUIDs []pgtype.UUID
+		for _, raw := range req.ClipIDs {
+			var id pgtype.UUID
+			if err := id.Scan(strings.TrimSpace(raw)); err != nil {
+				return c.String(400, fmt.Sprintf("invalid clip id %q", raw))
+			}
+			if seen[id.String()] {
+				continue
+			}
+			seen[id.String()] = true
+			clipUUIDs = append(clipUUIDs, id)
+		}
+		if len(clipUUIDs) == 0 {
+			return c.String(400, "clip_ids is required")
+		}
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+		if err := checkPiPVideo(ctx, q, plan); err != nil {
+			return c.String(400, err.Error())
+		}
+		if err := checkOverlayImages(plan, userUUID); err != nil {
+			return c.String(400, err.Error())
+		}
+		if err := checkLUTFiles(plan, userUUID); err != nil {
+			return c.String(400, err.Error())
+		}
+
+		// Refuse up front a batch that cannot fit under the cap, so it does
+		// not queue part of itself. EnqueueClipExport enforces the cap.
+		active, err := q.CountActiveClipExportsByUser(ctx, userUUID)
+		if err != nil {
+			slog.Error("failed to count active exports", "error", err)
+			return common.ErrInternal("failed to check export cap")
+		}
+		requested := len(clipUUIDs) * plan.exportsPerClip()
+		if int(active)+requested > maxActiveExportsPerUser {
+			return c.String(http.StatusTooManyRequests, fmt.Sprintf(
+				"export cap reached: %d active, %d requested, at most %d allowed", active, requested, maxActiveExportsPerUser))
+		}
+
+		// Resolve and validate every clip before enqueuing so a bad one queues nothing.
+		clips := make([]*db.Clip, 0, len(clipUUIDs))
+		for _, id := range clipUUIDs {
+			clipRow, err := q.GetClip(ctx, id)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return c.String(404, "clip not found: "+id.String())
+			}
+			if err != nil {
+				slog.Error("failed to load clip for batch export", "error", err, "clip_id", id.String())
+				return common.ErrInternal("failed to load clip")
+			}
+			if err := checkClipLength(plan, clipRow); err != nil {
+				return c.String(400, fmt.Sprintf("clip %s: %v", id.String(), err))
+			}
+			// Burned-in subtitles need the captions of the clip's video.
+			specs, err := checkSubtitles(ctx, plan, clipRow)
+			if err != nil {
+				return c.String(400, fmt.Sprintf("clip %s: %v", id.String(), err))
+			}
+			// The shared filter stack must compile for every clip (crop IDs
+			// and timeline ranges depend on the clip). Overlay images and
+			// LUTs are resolved first, as the encoder does.
+			if len(specs) > 0 {
+				if specs, err = resolveUserFilterFiles(specs, userUUID); err != nil {
+					return c.String(400, err.Error())
+				}
+				if _, err := ffmpeg.CompileFilters(ffmpeg.WithClipDuration(specs, clipRow.Duration), clipRow.Crops); err != nil {
+					return c.String(400, fmt.Sprintf("filters invalid for clip %s: %v", id.String(), err))
+				}
+			}
+			clips = append(clips, clipRow)
+		}
+
+		resp := batchExportResponse{
+			ExportIDs: make([]string, 0, len(clips)),
+			Exports:   make([]batchExportItem, 0, len(clips)),
+		}
+		for _, clipRow := range clips {
+			exportID, state, err := EnqueueClipExport(ctx, dbc, clipRow, userUUID, plan)
+			if errors.Is(err, errExportCapReached) {
+				return c.String(http.StatusTooManyRequests, err.Error())
+			}
+			if err != nil {
+				slog.Error("failed to create clip export", "error", err, "clip_id", clipRow.ID.String())
+				return common.ErrInternal("failed to queue export")
+			}
+			item := batchExportItem{
+				ClipID:   clipRow.ID.String(),
+				ExportID: exportID.String(),
+				State:    state,
+			}
+			if len(plan.Ladder) > 0 {
+				rungs, err := q.ListClipExportRungs(ctx, exportID)
+				if err != nil {
+					slog.Error("failed to list ladder rungs", "error", err, "export_id", exportID.String())
+					return common.ErrInternal("failed to queue export")
+				}
+				item.Renditions = append(item.Renditions, batchExportRendition{Height: plan.Ladder[0], ExportID: exportID.String()})
+				for _, r := range rungs {
+					item.Renditions = append(item.Renditions, batchExportRendition{Height: int(r.Height), ExportID: r.ID.String()})
+				}
+			}
+			resp.ExportIDs = append(resp.ExportIDs, exportID.String())
+			resp.Exports = append(resp.Exports, item)
+		}
+		slog.Info("batch export enqueued", "user_id", userUUID.String(), "clips", len(clips))
+		return c.JSON(http.StatusOK, resp)
+	}
+}
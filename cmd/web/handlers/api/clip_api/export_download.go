@@ -7,6 +7,7 @@ import (
 	"mime"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
@@ -18,7 +19,7 @@ import (
 // HandleDownloadExport serves GET /clip-exports/:id/download, streaming an encoded clip export file.
 func HandleDownloadExport(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		_, _, err := sm.GetSession(c.Request())
+		userUUID, _, err := common.RequireSessionUser(c, sm)
 		if err != nil {
 			return c.String(401, "unauthorized")
 		}
@@ -56,34 +57,64 @@ func HandleDownloadExport(sm *auth.SessionManager, dbc *db.DatabaseConnection) e
 
 		ext := "." + exportData.Format
 		ct := mime.TypeByExtension(ext)
+		if ct == "" {
+			ct = audioExportTypes[exportData.Format]
+		}
 		if ct != "" {
 			c.Response().Header().Set(echo.HeaderContentType, ct)
 		}
 
-		// Build a human-friendly download filename.
-		// Pattern: "{title}[-{cropName}]-{exportID}.ext"
-		// Falls back to "clip[-{cropName}]-{exportID}.ext" when clip has no title.
-		titlePart := filename.Sanitize(exportData.ClipTitle, 80)
-		if titlePart == "" {
-			titlePart = "clip"
+		// Build a human-friendly download filename from the user's template.
+		// The on-disk file keeps its UUID name; only Content-Disposition changes.
+		// Default: "{clip}-{variant}-{id}" (clip falls back to "clip").
+		clipTitle := strings.TrimSpace(exportData.ClipTitle)
+		if clipTitle == "" {
+			clipTitle = "clip"
 		}
 
 		// Resolve crop name from variant + clip crops
-		var cropSuffix string
+		var variantName string
 		if strings.HasPrefix(exportData.Variant, "crop:") {
 			cropID := strings.TrimPrefix(exportData.Variant, "crop:")
 			for _, cr := range exportData.Crops {
 				if cr.ID == cropID && cr.Name != "" {
-					cropSuffix = "-" + filename.Sanitize(cr.Name, 30)
+					variantName = filename.Sanitize(cr.Name, 30)
 					break
 				}
 			}
-			if cropSuffix == "" {
-				cropSuffix = "-cropped"
+			if variantName == "" {
+				variantName = "cropped"
 			}
+		} else if strings.HasPrefix(exportData.Variant, "concat:") {
+			variantName = "joined"
+		}
+
+		tmpl, err := q.GetUserExportFilenameTemplate(ctx, userUUID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("failed to load export filename template", "error", err)
+		}
+		base := filename.Render(tmpl, map[string]string{
+			"video":   exportData.VideoTitle,
+			"clip":    clipTitle,
+			"format":  exportData.Format,
+			"variant": variantName,
+			"id":      exportID,
+			"date":    time.Now().Format("2006-01-02"),
+		}, 120)
+		if base == "" {
+			base = exportID
 		}
 
-		downloadName := titlePart + cropSuffix + "-" + exportID + ext
-		return c.Attachment(exportData.FilePath, downloadName)
+		downloadName := base + ext
+		c.Response().Header().Set(echo.HeaderContentDisposition, filename.ContentDisposition(downloadName))
+		return c.File(exportData.FilePath)
 	}
 }
+
+// audioExportTypes covers the audio export formats, which Go's built-in MIME
+// table lacks and slim container images have no /etc/mime.types for.
+var audioExportTypes = map[string]string{
+	"mp3":  "audio/mpeg",
+	"m4a":  "audio/mp4",
+	"flac": "audio/flac",
+}
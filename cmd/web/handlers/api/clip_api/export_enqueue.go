@@ -1,9 +1,12 @@
 package clip_api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -24,11 +27,462 @@ type exportRequest struct {
 	Format  string              `json:"format"`
 	Quality string              `json:"quality"`
 	Filters []ffmpeg.FilterSpec `json:"filters"`
+	Loop    int                 `json:"loop"`    // Number of plays; 0 or 1 means no repeat
+	GOP     int                 `json:"gop"`     // Keyframe interval in frames; 0 means encoder default
+	PiP     *ffmpeg.PiPSpec     `json:"pip"`     // Second video inset in a corner; nil for none
+	Ladder  []int               `json:"ladder"`  // Rendition heights, one export each; empty for a single export
 	Variant string              `json:"variant"` // Legacy compat: "full", "crop:<id>"
+
+	TargetBitrate int `json:"target_bitrate"` // Two-pass average bitrate in kbps; 0 for constant quality
+	FPS           int `json:"fps"`            // GIF/WebP frame rate; 0 for the default
+	MaxWidth      int `json:"max_width"`      // GIF/WebP width limit in pixels; 0 for the default
+
+	Accurate bool `json:"accurate"` // Start exactly on the clip's first frame; slower than the default seek
+}
+
+// clipExportPlan is a validated export request, ready to be matched against
+// existing exports or stored as a new one.
+type clipExportPlan struct {
+	Format  string
+	Quality string
+	Variant string
+	Loop    int
+	GOP     int
+	Filters []ffmpeg.FilterSpec
+	PiP     *ffmpeg.PiPSpec
+	Ladder  []int  // Rendition heights, tallest first; nil for a single export
+	Spec    []byte // ExportSpec JSON; nil for a plain legacy export
+
+	TargetBitrate int // kbps; 0 for a single constant-quality pass
+	FPS           int // GIF/WebP frame rate; 0 for video formats
+	MaxWidth      int // GIF/WebP width limit; 0 for video formats
+
+	Accurate bool // Frame-accurate seek instead of the fast default
+}
+
+// newClipExportPlan validates req and builds the plan. Errors are user-facing.
+func newClipExportPlan(req exportRequest) (*clipExportPlan, error) {
+	// Determine variant string (for reuse matching and display)
+	variant := strings.TrimSpace(req.Variant)
+	if variant == "" {
+		variant = "full"
+	}
+	// Validate variant
+	if variant != "full" && variant != "cropped" && !strings.HasPrefix(variant, "crop:") {
+		return nil, fmt.Errorf("invalid variant")
+	}
+
+	// Determine format with default
+	format := strings.TrimSpace(req.Format)
+	if format == "" {
+		format = "mp4"
+	}
+	if format != "mp4" && format != "webm" && format != "gif" && format != "webp" && !ffmpeg.IsAudioFormat(format) {
+		return nil, fmt.Errorf("invalid format")
+	}
+	image := ffmpeg.IsAnimatedImageFormat(format)
+	audio := ffmpeg.IsAudioFormat(format)
+
+	// Audio-only exports have no picture to crop, so every variant is the
+	// same export; the mute filter would leave them empty.
+	if audio {
+		variant = "full"
+		for _, f := range req.Filters {
+			if f.Type == "mute" {
+				return nil, fmt.Errorf("mute filter cannot be used with %s exports", format)
+			}
+		}
+	}
+
+	// Validate loop count; a single play is stored as 0 so it matches
+	// exports queued before looping existed. GIF and WebP loop by
+	// themselves, so it is dropped there.
+	if req.Loop < 0 || req.Loop > ffmpeg.MaxExportLoop {
+		return nil, fmt.Errorf("loop must be between 1 and %d", ffmpeg.MaxExportLoop)
+	}
+	loop := req.Loop
+	if loop == 1 || image {
+		loop = 0
+	}
+
+	// Validate keyframe interval; GIF, WebP and audio have no GOP so it is
+	// dropped there.
+	if req.GOP < 0 || req.GOP > ffmpeg.MaxExportGOP {
+		return nil, fmt.Errorf("gop must be between 1 and %d frames, or 0 for the encoder default", ffmpeg.MaxExportGOP)
+	}
+	gop := req.GOP
+	if image || audio {
+		gop = 0
+	}
+
+	// Validate the animated image frame rate and width. The defaults are
+	// stored explicitly so reuse matching compares like with like; video
+	// formats ignore both.
+	if req.FPS < 0 || req.FPS > ffmpeg.MaxImageFPS {
+		return nil, fmt.Errorf("fps must be between 1 and %d", ffmpeg.MaxImageFPS)
+	}
+	if req.MaxWidth != 0 && (req.MaxWidth < ffmpeg.MinImageMaxWidth || req.MaxWidth > ffmpeg.MaxImageMaxWidth) {
+		return nil, fmt.Errorf("max_width must be between %d and %d", ffmpeg.MinImageMaxWidth, ffmpeg.MaxImageMaxWidth)
+	}
+	var fps, maxWidth int
+	if image {
+		imageSpec := ffmpeg.ExportSpec{FPS: req.FPS, MaxWidth: req.MaxWidth}
+		fps, maxWidth = imageSpec.ImageFPS(), imageSpec.ImageMaxWidth()
+	}
+
+	// Validate the PiP inset; animated images have no room for a second
+	// input, and audio exports no picture to put it in.
+	if req.PiP != nil {
+		if image || audio {
+			return nil, fmt.Errorf("pip is not supported for %s exports", format)
+		}
+		if err := req.PiP.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate the rendition ladder. Looping repeats an intermediate file,
+	// which a multi-output encode does not produce.
+	var ladder []int
+	if len(req.Ladder) > 0 {
+		if image || audio {
+			return nil, fmt.Errorf("ladder is not supported for %s exports", format)
+		}
+		if loop > 0 {
+			return nil, fmt.Errorf("ladder cannot be combined with loop")
+		}
+		var err error
+		if ladder, err = ffmpeg.NormalizeLadder(req.Ladder); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate the two-pass target bitrate. It is a video bitrate, which
+	// animated images and audio exports do not have, and a ladder's single
+	// decode cannot be split into two passes.
+	if req.TargetBitrate != 0 {
+		if image || audio {
+			return nil, fmt.Errorf("target_bitrate is not supported for %s exports", format)
+		}
+		if len(ladder) > 0 {
+			return nil, fmt.Errorf("ladder cannot be combined with target_bitrate")
+		}
+		if req.TargetBitrate < ffmpeg.MinExportBitrate || req.TargetBitrate > ffmpeg.MaxExportBitrate {
+			return nil, fmt.Errorf("target_bitrate must be between %d and %d kbps", ffmpeg.MinExportBitrate, ffmpeg.MaxExportBitrate)
+		}
+	}
+
+	// When variant is crop:<id>, inject a crop filter at the front of the
+	// filter list so the encoder always applies it (even when other filters
+	// are present and the spec-based pipeline takes precedence over legacy
+	// variant handling).
+	filters := ffmpeg.StripInjectedParams(req.Filters)
+	if strings.HasPrefix(variant, "crop:") {
+		cropID := strings.TrimPrefix(variant, "crop:")
+		cropFilter := ffmpeg.FilterSpec{
+			Type:   "crop",
+			Params: map[string]any{"crop_id": cropID},
+		}
+		// Prepend so crop is applied before other filters
+		filters = append([]ffmpeg.FilterSpec{cropFilter}, filters...)
+	}
+
+	plan := &clipExportPlan{
+		Format:  format,
+		Quality: req.Quality,
+		Variant: variant,
+		Loop:    loop,
+		GOP:     gop,
+		Filters: filters,
+		PiP:     req.PiP,
+		Ladder:  ladder,
+
+		TargetBitrate: req.TargetBitrate,
+		FPS:           fps,
+		MaxWidth:      maxWidth,
+
+		Accurate: req.Accurate,
+	}
+
+	// Build ExportSpec JSON for storage
+	if len(filters) > 0 || req.Format != "" || req.Quality != "" || loop > 0 || gop > 0 || req.PiP != nil || ladder != nil || req.TargetBitrate > 0 || req.Accurate || image {
+		spec := plan.exportSpec()
+		if ladder != nil {
+			spec.Ladder = ladder
+			spec.Height = ladder[0]
+		}
+		plan.Spec, _ = json.Marshal(spec)
+	}
+	return plan, nil
+}
+
+// exportSpec is the encoding recipe shared by every export of the plan.
+func (p *clipExportPlan) exportSpec() ffmpeg.ExportSpec {
+	return ffmpeg.ExportSpec{
+		Format:  p.Format,
+		Quality: p.Quality,
+		Filters: p.Filters,
+		Loop:    p.Loop,
+		GOP:     p.GOP,
+		PiP:     p.PiP,
+
+		TargetBitrate: p.TargetBitrate,
+		FPS:           p.FPS,
+		MaxWidth:      p.MaxWidth,
+
+		Accurate: p.Accurate,
+	}
+}
+
+// rungSpec is the ExportSpec JSON of a ladder rung below the lead.
+func (p *clipExportPlan) rungSpec(height int) []byte {
+	spec := p.exportSpec()
+	spec.Height = height
+	b, _ := json.Marshal(spec)
+	return b
+}
+
+// exportsPerClip is how many export rows the plan creates for one clip.
+func (p *clipExportPlan) exportsPerClip() int {
+	if len(p.Ladder) > 0 {
+		return len(p.Ladder)
+	}
+	return 1
+}
+
+// filtersJSON is the plan's filter list in the form reuse matching compares
+// against the stored spec.
+func (p *clipExportPlan) filtersJSON() []byte {
+	if len(p.Filters) == 0 {
+		return []byte("[]")
+	}
+	b, _ := json.Marshal(p.Filters)
+	return b
+}
+
+// pipJSON is the plan's PiP inset in the form reuse matching compares
+// against the stored spec.
+func (p *clipExportPlan) pipJSON() []byte {
+	b, _ := json.Marshal(p.PiP)
+	return b
+}
+
+// checkClipLength reports a user-facing error when the plan would turn a clip
+// longer than ffmpeg.MaxImageExportSeconds into a GIF or WebP, which would
+// run to hundreds of megabytes.
+func checkClipLength(plan *clipExportPlan, clipRow *db.Clip) error {
+	if ffmpeg.IsAnimatedImageFormat(plan.Format) && clipRow.Duration > ffmpeg.MaxImageExportSeconds {
+		return fmt.Errorf("clip is %.0fs long; %s exports are limited to %ds", clipRow.Duration, plan.Format, ffmpeg.MaxImageExportSeconds)
+	}
+	return nil
+}
+
+// checkPiPVideo reports a user-facing error when the plan's PiP inset refers
+// to a video that does not exist or has no media file.
+func checkPiPVideo(ctx context.Context, q *db.Queries, plan *clipExportPlan) error {
+	if plan.PiP == nil {
+		return nil
+	}
+	var id pgtype.UUID
+	if err := id.Scan(strings.TrimSpace(plan.PiP.VideoID)); err != nil {
+		return fmt.Errorf("invalid pip.video_id")
+	}
+	video, err := q.GetVideoByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("pip video not found")
+	}
+	if video.MetadataOnly {
+		return fmt.Errorf("pip video has no media file")
+	}
+	return nil
+}
+
+// maxActiveExportsPerUser caps how many of one user's exports may be queued or
+// processing at once. EnqueueClipExport refuses new work past it.
+const maxActiveExportsPerUser = 50
+
+// errExportCapReached is returned when queuing an export would take the user
+// past maxActiveExportsPerUser.
+var errExportCapReached = errors.New("export cap reached")
+
+// withExportCap runs fn in a transaction holding the user's export lock,
+// once it has checked that n more exports keep the user within
+// maxActiveExportsPerUser. Enqueues for one user wait on the lock, so two of
+// them cannot both pass the check before either inserts.
+func withExportCap(ctx context.Context, dbc *db.DatabaseConnection, userUUID pgtype.UUID, n int, fn func(qtx *db.Queries) error) error {
+	tx, err := dbc.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := dbc.Queries(ctx).WithTx(tx)
+	if err := qtx.LockClipExportsByUser(ctx, userUUID); err != nil {
+		return err
+	}
+	active, err := qtx.CountActiveClipExportsByUser(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if int(active)+n > maxActiveExportsPerUser {
+		return fmt.Errorf("%w: %d active, %d requested, at most %d allowed", errExportCapReached, active, n, maxActiveExportsPerUser)
+	}
+	if err := fn(qtx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// enqueueState says how EnqueueClipExport satisfied a request.
+type enqueueState string
+
+const (
+	enqueueReady    enqueueState = "ready"    // an identical finished export exists
+	enqueueRequeued enqueueState = "requeued" // an identical export's file was missing; queued again
+	enqueuePending  enqueueState = "pending"  // an identical export is already queued or running
+	enqueueQueued   enqueueState = "queued"   // a new export was created
+)
+
+// EnqueueClipExport reuses an identical ready or in-flight export of the clip
+// by the same user, or creates and announces a new queued export. New or
+// requeued work counts against the user's export cap; past it the error
+// wraps errExportCapReached.
+func EnqueueClipExport(ctx context.Context, dbc *db.DatabaseConnection, clipRow *db.Clip, userUUID pgtype.UUID, plan *clipExportPlan) (pgtype.UUID, enqueueState, error) {
+	if len(plan.Ladder) > 0 {
+		return enqueueClipExportLadder(ctx, dbc, clipRow, userUUID, plan)
+	}
+	q := dbc.Queries(ctx)
+
+	// Check for existing ready export
+	existingExport, reuseErr := q.FindReusableClipExport(ctx, &db.FindReusableClipExportParams{
+		ClipID:    clipRow.ID,
+		CreatedBy: userUUID,
+		Format:    plan.Format,
+		Variant:   plan.Variant,
+		LoopCount: int32(plan.Loop),
+		Gop:       int32(plan.GOP),
+		Quality:   plan.Quality,
+		Filters:   plan.filtersJSON(),
+		Pip:       plan.pipJSON(),
+
+		TargetBitrate: int32(plan.TargetBitrate),
+		Fps:           int32(plan.FPS),
+		MaxWidth:      int32(plan.MaxWidth),
+
+		Accurate: plan.Accurate,
+	})
+	if reuseErr == nil {
+		if _, err := os.Stat(existingExport.FilePath); err == nil {
+			_ = q.UpdateClipExportLastAccessed(ctx, existingExport.ID)
+			cleanupClipExportsLRU(ctx, dbc)
+			return existingExport.ID, enqueueReady, nil
+		}
+		// File is missing - requeue this export
+		slog.Warn("reusable export file missing, requeuing", "export_id", existingExport.ID.String(), "file_path", existingExport.FilePath)
+		requeueErr := withExportCap(ctx, dbc, userUUID, 1, func(qtx *db.Queries) error {
+			return qtx.RequeueClipExport(ctx, existingExport.ID)
+		})
+		if errors.Is(requeueErr, errExportCapReached) {
+			return pgtype.UUID{}, "", requeueErr
+		}
+		if requeueErr != nil {
+			slog.Error("failed to requeue missing export", "export_id", existingExport.ID.String(), "error", requeueErr)
+		}
+		_, _ = dbc.Exec(ctx, "SELECT pg_notify('clip_exports', $1)", existingExport.ID.String())
+		return existingExport.ID, enqueueRequeued, nil
+	}
+
+	// Check for existing queued/processing export
+	pendingExport, pendingErr := q.FindOrCreatePendingClipExport(ctx, &db.FindOrCreatePendingClipExportParams{
+		ClipID:    clipRow.ID,
+		CreatedBy: userUUID,
+		Format:    plan.Format,
+		Variant:   plan.Variant,
+		LoopCount: int32(plan.Loop),
+		Gop:       int32(plan.GOP),
+		Quality:   plan.Quality,
+		Filters:   plan.filtersJSON(),
+		Pip:       plan.pipJSON(),
+
+		TargetBitrate: int32(plan.TargetBitrate),
+		Fps:           int32(plan.FPS),
+		MaxWidth:      int32(plan.MaxWidth),
+
+		Accurate: plan.Accurate,
+	})
+	if pendingErr == nil {
+		return pendingExport.ID, enqueuePending, nil
+	}
+
+	// Create new queued export
+	var exportID pgtype.UUID
+	err := withExportCap(ctx, dbc, userUUID, 1, func(qtx *db.Queries) error {
+		var err error
+		exportID, err = qtx.CreateClipExport(ctx, &db.CreateClipExportParams{
+			ClipID:        clipRow.ID,
+			CreatedBy:     userUUID,
+			Format:        plan.Format,
+			Variant:       plan.Variant,
+			Spec:          plan.Spec,
+			ClipUpdatedAt: clipRow.UpdatedAt,
+		})
+		return err
+	})
+	if err != nil {
+		return pgtype.UUID{}, "", err
+	}
+
+	// Notify encoder workers via NOTIFY
+	_, _ = dbc.Exec(ctx, "SELECT pg_notify('clip_exports', $1)", exportID.String())
+	return exportID, enqueueQueued, nil
+}
+
+// enqueueClipExportLadder creates a rendition ladder: a lead export at the
+// tallest height and one linked rung per remaining height, all encoded by
+// one worker from a single decode. Ladders are always created fresh; their
+// rows are never reused. It returns the lead's ID.
+func enqueueClipExportLadder(ctx context.Context, dbc *db.DatabaseConnection, clipRow *db.Clip, userUUID pgtype.UUID, plan *clipExportPlan) (pgtype.UUID, enqueueState, error) {
+	var leadID pgtype.UUID
+	err := withExportCap(ctx, dbc, userUUID, plan.exportsPerClip(), func(qtx *db.Queries) error {
+		var err error
+		leadID, err = qtx.CreateClipExport(ctx, &db.CreateClipExportParams{
+			ClipID:        clipRow.ID,
+			CreatedBy:     userUUID,
+			Format:        plan.Format,
+			Variant:       plan.Variant,
+			Spec:          plan.Spec,
+			ClipUpdatedAt: clipRow.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		for _, height := range plan.Ladder[1:] {
+			if _, err := qtx.CreateClipExportRung(ctx, &db.CreateClipExportRungParams{
+				ClipID:         clipRow.ID,
+				CreatedBy:      userUUID,
+				Format:         plan.Format,
+				Variant:        plan.Variant,
+				Spec:           plan.rungSpec(height),
+				ClipUpdatedAt:  clipRow.UpdatedAt,
+				LadderParentID: leadID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return pgtype.UUID{}, "", err
+	}
+
+	_, _ = dbc.Exec(ctx, "SELECT pg_notify('clip_exports', $1)", leadID.String())
+	return leadID, enqueueQueued, nil
 }
 
 // HandleEnqueueExport enqueues a clip export job and streams status updates via SSE.
-func HandleEnqueueExport(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+// The stream counts against limiter; when it is full nothing is queued and the
+// request gets 503, so a retry can't queue the export twice.
+func HandleEnqueueExport(sm *auth.SessionManager, dbc *db.DatabaseConnection, limiter *common.SSELimiter) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		userUUID, _, err := common.RequireSessionUser(c, sm)
 		if err != nil {
@@ -55,108 +509,47 @@ func HandleEnqueueExport(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 		if c.Request().ContentLength > 0 {
 			_ = json.NewDecoder(c.Request().Body).Decode(&req)
 		}
+		if strings.TrimSpace(req.Variant) == "" {
+			req.Variant = c.QueryParam("variant")
+		}
 
-		// Determine variant string (for reuse matching and display)
-		variant := strings.TrimSpace(req.Variant)
-		if variant == "" {
-			variant = strings.TrimSpace(c.QueryParam("variant"))
+		plan, err := newClipExportPlan(req)
+		if err != nil {
+			return c.String(400, err.Error())
 		}
-		if variant == "" {
-			variant = "full"
+		if err := checkClipLength(plan, clipRow); err != nil {
+			return c.String(400, err.Error())
 		}
-		// Validate variant
-		if variant != "full" && variant != "cropped" && !strings.HasPrefix(variant, "crop:") {
-			return c.String(400, "invalid variant")
+		if err := checkPiPVideo(ctx, q, plan); err != nil {
+			return c.String(400, err.Error())
 		}
-
-		// Determine format with default
-		format := strings.TrimSpace(req.Format)
-		if format == "" {
-			format = "mp4"
+		if err := checkOverlayImages(plan, userUUID); err != nil {
+			return c.String(400, err.Error())
 		}
-		if format != "mp4" && format != "webm" && format != "gif" {
-			return c.String(400, "invalid format")
+		if err := checkLUTFiles(plan, userUUID); err != nil {
+			return c.String(400, err.Error())
 		}
-
-		// When variant is crop:<id>, inject a crop filter at the front of the
-		// filter list so the encoder always applies it (even when other filters
-		// are present and the spec-based pipeline takes precedence over legacy
-		// variant handling).
-		filters := req.Filters
-		if strings.HasPrefix(variant, "crop:") {
-			cropID := strings.TrimPrefix(variant, "crop:")
-			cropFilter := ffmpeg.FilterSpec{
-				Type:   "crop",
-				Params: map[string]any{"crop_id": cropID},
-			}
-			// Prepend so crop is applied before other filters
-			filters = append([]ffmpeg.FilterSpec{cropFilter}, filters...)
+		if _, err := checkSubtitles(ctx, plan, clipRow); err != nil {
+			return c.String(400, err.Error())
 		}
 
-		// Build ExportSpec JSON for storage
-		var specJSON []byte
-		if len(filters) > 0 || req.Format != "" || req.Quality != "" {
-			spec := ffmpeg.ExportSpec{
-				Format:  format,
-				Quality: req.Quality,
-				Filters: filters,
-			}
-			specJSON, _ = json.Marshal(spec)
+		release, ok := limiter.AcquireRequest(c, sm)
+		if !ok {
+			c.Response().Header().Set("Retry-After", "10")
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "too many open streams")
 		}
+		defer release()
 
 		// Start SSE response
 		sse := datastar.NewSSE(c.Response().Writer, c.Request())
 
-		// Check for existing ready export
-		existingExport, reuseErr := q.FindReusableClipExport(ctx, &db.FindReusableClipExportParams{
-			ClipID:    clipRow.ID,
-			CreatedBy: userUUID,
-			Format:    format,
-			Variant:   variant,
-		})
-		if reuseErr == nil {
-			if _, err := os.Stat(existingExport.FilePath); err == nil {
-				_ = q.UpdateClipExportLastAccessed(ctx, existingExport.ID)
-				cleanupClipExportsLRU(ctx, dbc)
-				downloadURL := "/api/clip-exports/" + existingExport.ID.String() + "/download"
-				if err := sse.PatchElementTempl(components.ClipExportStatus(clipIDStr, "Ready", "ready", downloadURL)); err != nil {
-					slog.Error("failed to patch export status", "error", err)
-				}
-				return nil
-			} else {
-				// File is missing - requeue this export
-				slog.Warn("reusable export file missing, requeuing", "export_id", existingExport.ID.String(), "file_path", existingExport.FilePath)
-				if requeueErr := q.RequeueClipExport(ctx, existingExport.ID); requeueErr != nil {
-					slog.Error("failed to requeue missing export", "export_id", existingExport.ID.String(), "error", requeueErr)
-				}
-				_, _ = dbc.Exec(ctx, "SELECT pg_notify('clip_exports', $1)", existingExport.ID.String())
-				if err := sse.PatchElementTempl(components.ClipExportStatus(clipIDStr, "Queued...", "queued", "")); err != nil {
-					slog.Error("failed to patch export status", "error", err)
-				}
-				return streamExportStatus(c, sse, dbc, existingExport.ID, clipIDStr)
+		exportID, state, err := EnqueueClipExport(ctx, dbc, clipRow, userUUID, plan)
+		if errors.Is(err, errExportCapReached) {
+			if patchErr := sse.PatchElementTempl(components.ClipExportStatus(clipIDStr, "Too many exports queued", "error", "")); patchErr != nil {
+				slog.Error("failed to patch export status", "error", patchErr)
 			}
+			return nil
 		}
-
-		// Check for existing queued/processing export - stream its status
-		pendingExport, pendingErr := q.FindOrCreatePendingClipExport(ctx, &db.FindOrCreatePendingClipExportParams{
-			ClipID:    clipRow.ID,
-			CreatedBy: userUUID,
-			Format:    format,
-			Variant:   variant,
-		})
-		if pendingErr == nil {
-			return streamExportStatus(c, sse, dbc, pendingExport.ID, clipIDStr)
-		}
-
-		// Create new queued export
-		exportID, err := q.CreateClipExport(ctx, &db.CreateClipExportParams{
-			ClipID:        clipRow.ID,
-			CreatedBy:     userUUID,
-			Format:        format,
-			Variant:       variant,
-			Spec:          specJSON,
-			ClipUpdatedAt: clipRow.UpdatedAt,
-		})
 		if err != nil {
 			slog.Error("failed to create clip export", "error", err, "clip_id", clipIDStr)
 			if patchErr := sse.PatchElementTempl(components.ClipExportStatus(clipIDStr, "Failed to queue", "error", "")); patchErr != nil {
@@ -165,13 +558,23 @@ func HandleEnqueueExport(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 			return nil
 		}
 
-		// Notify encoder workers via NOTIFY
-		_, _ = dbc.Exec(ctx, "SELECT pg_notify('clip_exports', $1)", exportID.String())
-
-		// Patch initial queued status
-		if err := sse.PatchElementTempl(components.ClipExportStatus(clipIDStr, "Queued...", "queued", "")); err != nil {
-			slog.Error("failed to patch export status", "error", err)
-			return err
+		switch state {
+		case enqueueReady:
+			downloadURL := "/api/clip-exports/" + exportID.String() + "/download"
+			if err := sse.PatchElementTempl(components.ClipExportStatus(clipIDStr, "Ready", "ready", downloadURL)); err != nil {
+				slog.Error("failed to patch export status", "error", err)
+			}
+			return nil
+		case enqueueRequeued:
+			if err := sse.PatchElementTempl(components.ClipExportStatus(clipIDStr, "Queued...", "queued", "")); err != nil {
+				slog.Error("failed to patch export status", "error", err)
+			}
+		case enqueueQueued:
+			// Patch initial queued status
+			if err := sse.PatchElementTempl(components.ClipExportStatus(clipIDStr, "Queued...", "queued", "")); err != nil {
+				slog.Error("failed to patch export status", "error", err)
+				return err
+			}
 		}
 
 		// Stream status updates
@@ -205,6 +608,9 @@ func streamExportStatus(c echo.Context, sse *datastar.ServerSentEventGenerator,
 		case <-ticker.C:
 			exportRow, err := q.GetClipExportStatus(ctx, exportID)
 			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
 				if patchErr := patch("Export not found", "error", ""); patchErr != nil {
 					return patchErr
 				}
@@ -0,0 +1,223 @@
+package clip_api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+func TestNewClipExportPlan(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.Format != "mp4" || plan.Variant != "full" || plan.Spec != nil {
+			t.Errorf("plan = %+v, want mp4/full with no spec", plan)
+		}
+		if string(plan.filtersJSON()) != "[]" {
+			t.Errorf("filtersJSON = %s, want []", plan.filtersJSON())
+		}
+		if string(plan.pipJSON()) != "null" {
+			t.Errorf("pipJSON = %s, want null", plan.pipJSON())
+		}
+	})
+
+	t.Run("single play and gif gop normalised", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{Format: "gif", Loop: 1, GOP: 60})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.Loop != 0 || plan.GOP != 0 {
+			t.Errorf("loop=%d gop=%d, want 0/0", plan.Loop, plan.GOP)
+		}
+	})
+
+	t.Run("webp drops loop and gop and stores image defaults", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{Format: "webp", Loop: 3, GOP: 60})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.Loop != 0 || plan.GOP != 0 {
+			t.Errorf("loop=%d gop=%d, want 0/0", plan.Loop, plan.GOP)
+		}
+		var spec ffmpeg.ExportSpec
+		if err := json.Unmarshal(plan.Spec, &spec); err != nil {
+			t.Fatal(err)
+		}
+		if spec.FPS != ffmpeg.DefaultImageFPS || spec.MaxWidth != ffmpeg.DefaultImageMaxWidth {
+			t.Errorf("stored fps=%d max_width=%d, want defaults", spec.FPS, spec.MaxWidth)
+		}
+	})
+
+	t.Run("image size ignored for video formats", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{Format: "mp4", FPS: 10, MaxWidth: 320})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.FPS != 0 || plan.MaxWidth != 0 {
+			t.Errorf("fps=%d max_width=%d, want 0/0", plan.FPS, plan.MaxWidth)
+		}
+	})
+
+	t.Run("audio drops crop variant and gop", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{
+			Format:  "mp3",
+			Variant: "crop:abc",
+			GOP:     60,
+			Loop:    2,
+			Filters: []ffmpeg.FilterSpec{{Type: "volume"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if plan.Variant != "full" || plan.GOP != 0 || plan.Loop != 2 {
+			t.Errorf("plan = %+v, want full variant, no gop, loop kept", plan)
+		}
+		if len(plan.Filters) != 1 || plan.Filters[0].Type != "volume" {
+			t.Errorf("filters = %+v, want volume only", plan.Filters)
+		}
+	})
+
+	t.Run("target bitrate stored in spec", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{Quality: "max", TargetBitrate: 8000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var spec ffmpeg.ExportSpec
+		if err := json.Unmarshal(plan.Spec, &spec); err != nil {
+			t.Fatal(err)
+		}
+		if spec.TargetBitrate != 8000 {
+			t.Errorf("stored target bitrate = %d, want 8000", spec.TargetBitrate)
+		}
+	})
+
+	t.Run("accurate seek stored in spec", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{Accurate: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var spec ffmpeg.ExportSpec
+		if err := json.Unmarshal(plan.Spec, &spec); err != nil {
+			t.Fatal(err)
+		}
+		if !spec.Accurate {
+			t.Errorf("stored spec = %s, want accurate", plan.Spec)
+		}
+	})
+
+	t.Run("crop variant prepends crop filter", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{
+			Variant: "crop:abc",
+			Filters: []ffmpeg.FilterSpec{{Type: "grayscale"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Filters) != 2 || plan.Filters[0].Type != "crop" || plan.Filters[0].Params["crop_id"] != "abc" {
+			t.Fatalf("filters = %+v, want crop first", plan.Filters)
+		}
+		var spec ffmpeg.ExportSpec
+		if err := json.Unmarshal(plan.Spec, &spec); err != nil {
+			t.Fatal(err)
+		}
+		if len(spec.Filters) != 2 {
+			t.Errorf("stored spec has %d filters, want 2", len(spec.Filters))
+		}
+	})
+
+	t.Run("pip stored in spec", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{PiP: &ffmpeg.PiPSpec{VideoID: "v", Position: "top_right"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var spec ffmpeg.ExportSpec
+		if err := json.Unmarshal(plan.Spec, &spec); err != nil {
+			t.Fatal(err)
+		}
+		if spec.PiP == nil || spec.PiP.VideoID != "v" || spec.PiP.Position != "top_right" {
+			t.Errorf("stored pip = %+v, want video v at top_right", spec.PiP)
+		}
+		if string(plan.pipJSON()) == "null" {
+			t.Error("pipJSON = null, want the inset")
+		}
+	})
+
+	t.Run("ladder sorted with lead height in spec", func(t *testing.T) {
+		plan, err := newClipExportPlan(exportRequest{Ladder: []int{480, 1080, 720}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Ladder) != 3 || plan.Ladder[0] != 1080 || plan.Ladder[2] != 480 {
+			t.Fatalf("ladder = %v, want [1080 720 480]", plan.Ladder)
+		}
+		if plan.exportsPerClip() != 3 {
+			t.Errorf("exportsPerClip = %d, want 3", plan.exportsPerClip())
+		}
+		var spec ffmpeg.ExportSpec
+		if err := json.Unmarshal(plan.Spec, &spec); err != nil {
+			t.Fatal(err)
+		}
+		if spec.Height != 1080 || len(spec.Ladder) != 3 {
+			t.Errorf("lead spec = %+v, want height 1080 with the ladder", spec)
+		}
+		var rung ffmpeg.ExportSpec
+		if err := json.Unmarshal(plan.rungSpec(720), &rung); err != nil {
+			t.Fatal(err)
+		}
+		if rung.Height != 720 || len(rung.Ladder) != 0 {
+			t.Errorf("rung spec = %+v, want height 720 without a ladder", rung)
+		}
+	})
+
+	for _, req := range []exportRequest{
+		{PiP: &ffmpeg.PiPSpec{}},
+		{Ladder: []int{720}},
+		{Ladder: []int{720, 720}},
+		{Format: "gif", Ladder: []int{720, 480}},
+		{Loop: 2, Ladder: []int{720, 480}},
+		{Format: "gif", PiP: &ffmpeg.PiPSpec{VideoID: "v"}},
+		{Variant: "weird"},
+		{Format: "avi"},
+		{Loop: -1},
+		{Loop: ffmpeg.MaxExportLoop + 1},
+		{GOP: -1},
+		{GOP: ffmpeg.MaxExportGOP + 1},
+		{TargetBitrate: -1},
+		{TargetBitrate: ffmpeg.MinExportBitrate - 1},
+		{TargetBitrate: ffmpeg.MaxExportBitrate + 1},
+		{Format: "gif", TargetBitrate: 2000},
+		{TargetBitrate: 2000, Ladder: []int{720, 480}},
+		{Format: "webp", TargetBitrate: 2000},
+		{Format: "webp", PiP: &ffmpeg.PiPSpec{VideoID: "v"}},
+		{Format: "m4a", PiP: &ffmpeg.PiPSpec{VideoID: "v"}},
+		{Format: "flac", Ladder: []int{720, 480}},
+		{Format: "mp3", TargetBitrate: 2000},
+		{Format: "mp3", Filters: []ffmpeg.FilterSpec{{Type: "mute"}}},
+		{Format: "wav"},
+		{Format: "gif", FPS: -1},
+		{Format: "gif", FPS: ffmpeg.MaxImageFPS + 1},
+		{Format: "gif", MaxWidth: ffmpeg.MinImageMaxWidth - 1},
+		{Format: "gif", MaxWidth: ffmpeg.MaxImageMaxWidth + 1},
+	} {
+		if _, err := newClipExportPlan(req); err == nil {
+			t.Errorf("newClipExportPlan(%+v) accepted, want error", req)
+		}
+	}
+}
+
+func TestCheckClipLength(t *testing.T) {
+	long := &db.Clip{Duration: ffmpeg.MaxImageExportSeconds + 1}
+	for format, wantErr := range map[string]bool{"gif": true, "webp": true, "mp4": false} {
+		err := checkClipLength(&clipExportPlan{Format: format}, long)
+		if (err != nil) != wantErr {
+			t.Errorf("%s: err = %v, want error %v", format, err, wantErr)
+		}
+	}
+	if err := checkClipLength(&clipExportPlan{Format: "gif"}, &db.Clip{Duration: 5}); err != nil {
+		t.Errorf("short gif: %v", err)
+	}
+}
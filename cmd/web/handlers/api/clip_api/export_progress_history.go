@@ -0,0 +1,68 @@
+package clip_api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// exportProgressHistoryResponse is the JSON body of the progress history
+// endpoint. Samples is null when the encoder did not record a timeline
+// (EXPORT_PROGRESS_HISTORY off, or the export has not finished).
+type exportProgressHistoryResponse struct {
+	ID         string          `json:"id"`
+	Status     string          `json:"status"`
+	StartedAt  *string         `json:"started_at"`
+	FinishedAt *string         `json:"finished_at"`
+	Samples    json.RawMessage `json:"samples"`
+}
+
+// HandleExportProgressHistory serves GET /api/clip-exports/:id/progress-history,
+// returning the downsampled encode timeline ({t, pct, speed} samples) the
+// encoder stored for a finished export.
+func HandleExportProgressHistory(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		exportUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		row, err := dbc.Queries(ctx).GetClipExportProgressHistory(ctx, exportUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("export not found")
+			}
+			return common.ErrInternal("failed to load export")
+		}
+
+		resp := exportProgressHistoryResponse{
+			ID:      row.ID.String(),
+			Status:  string(row.Status),
+			Samples: json.RawMessage("null"),
+		}
+		if len(row.ProgressHistory) > 0 {
+			resp.Samples = row.ProgressHistory
+		}
+		if row.StartedAt.Valid {
+			s := row.StartedAt.Time.UTC().Format(time.RFC3339)
+			resp.StartedAt = &s
+		}
+		if row.FinishedAt.Valid {
+			s := row.FinishedAt.Time.UTC().Format(time.RFC3339)
+			resp.FinishedAt = &s
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,194 @@
+package clip_api
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// maxLUTFileBytes caps .cube uploads; a 65-point LUT is about 8 MB.
+const maxLUTFileBytes = 16 << 20
+
+// lutFile describes one of a user's uploaded LUTs.
+type lutFile struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Size      int       `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// userLUTDir is the directory of the user's uploaded LUTs.
+func userLUTDir(userUUID pgtype.UUID) string {
+	return ffmpeg.LUTFileDir(exportsDir(), userUUID.String())
+}
+
+// checkLUTFiles reports a user-facing error when one of the plan's lut_file
+// filters names a LUT the user has not uploaded.
+func checkLUTFiles(plan *clipExportPlan, userUUID pgtype.UUID) error {
+	_, err := ffmpeg.ResolveLUTFiles(plan.Filters, userLUTDir(userUUID))
+	return err
+}
+
+// resolveUserFilterFiles points the overlay image and LUT filters in specs at
+// the user's files, as the encoder does, so that the specs can be compiled
+// for validation.
+func resolveUserFilterFiles(specs []ffmpeg.FilterSpec, userUUID pgtype.UUID) ([]ffmpeg.FilterSpec, error) {
+	specs, err := ffmpeg.ResolveOverlayImages(specs, userOverlayDir(userUUID))
+	if err != nil {
+		return nil, err
+	}
+	return ffmpeg.ResolveLUTFiles(specs, userLUTDir(userUUID))
+}
+
+// HandleUploadLUT serves POST /api/luts, storing a 3D LUT in the .cube format
+// (multipart field "file") for use by the lut_file filter. The file is parsed
+// before it is accepted. LUTs are private to the uploading user.
+func HandleUploadLUT(sm *auth.SessionManager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			return common.ErrBadRequest("file is required")
+		}
+		if !strings.EqualFold(filepath.Ext(file.Filename), ".cube") {
+			return common.ErrBadRequest("LUTs must be .cube files")
+		}
+		if file.Size > maxLUTFileBytes {
+			return common.ErrBadRequest("LUT files are limited to 16 MB")
+		}
+		src, err := file.Open()
+		if err != nil {
+			return common.ErrInternal("failed to open uploaded file")
+		}
+		defer src.Close()
+		data, err := io.ReadAll(io.LimitReader(src, maxLUTFileBytes+1))
+		if err != nil {
+			return common.ErrInternal("failed to read uploaded file")
+		}
+		if len(data) > maxLUTFileBytes {
+			return common.ErrBadRequest("LUT files are limited to 16 MB")
+		}
+
+		title, size, err := ffmpeg.ParseCubeLUT(data)
+		if err != nil {
+			return common.ErrBadRequest("invalid LUT: " + err.Error())
+		}
+		name := title
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(file.Filename), filepath.Ext(file.Filename))
+		}
+
+		dir := userLUTDir(userUUID)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			slog.Error("failed to create lut dir", "dir", dir, "error", err)
+			return common.ErrInternal("failed to store LUT")
+		}
+		id := uuid.New().String()
+		path, _ := ffmpeg.LUTFilePath(dir, id)
+		info := ffmpeg.LUTInfo{Name: name, Size: size}
+		// The info goes first: a .cube file without one is not listed
+		// and cannot be used.
+		if err := ffmpeg.WriteLUTInfo(path, info); err != nil {
+			slog.Error("failed to write lut info", "path", path, "error", err)
+			return common.ErrInternal("failed to store LUT")
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, path)
+		}
+		if err != nil {
+			_ = os.Remove(tmp)
+			_ = removeLUT(path)
+			slog.Error("failed to store lut", "path", path, "error", err)
+			return common.ErrInternal("failed to store LUT")
+		}
+
+		return c.JSON(http.StatusCreated, lutFile{ID: id, Name: name, Size: size, CreatedAt: time.Now()})
+	}
+}
+
+// HandleListLUTs serves GET /api/luts, the user's uploaded LUTs, newest
+// first.
+func HandleListLUTs(sm *auth.SessionManager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		dir := userLUTDir(userUUID)
+		entries, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			slog.Error("failed to list luts", "error", err)
+			return common.ErrInternal("failed to list LUTs")
+		}
+		luts := []lutFile{}
+		for _, e := range entries {
+			id, ok := strings.CutSuffix(e.Name(), ".cube")
+			if !ok || e.IsDir() {
+				continue
+			}
+			fi, err := e.Info()
+			if err != nil {
+				continue
+			}
+			info, err := ffmpeg.ReadLUTInfo(filepath.Join(dir, e.Name()))
+			if err != nil {
+				continue
+			}
+			luts = append(luts, lutFile{ID: id, Name: info.Name, Size: info.Size, CreatedAt: fi.ModTime()})
+		}
+		sort.Slice(luts, func(i, j int) bool { return luts[i].CreatedAt.After(luts[j].CreatedAt) })
+		return c.JSON(http.StatusOK, luts)
+	}
+}
+
+// HandleDeleteLUT serves DELETE /api/luts/:id. Exports already encoded keep
+// the look; queued ones that use the LUT will fail.
+func HandleDeleteLUT(sm *auth.SessionManager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+		path, err := ffmpeg.LUTFilePath(userLUTDir(userUUID), c.Param("id"))
+		if err != nil {
+			return common.ErrBadRequest(err.Error())
+		}
+		if err := removeLUT(path); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return common.ErrNotFound("LUT not found")
+			}
+			slog.Error("failed to delete lut", "path", path, "error", err)
+			return common.ErrInternal("failed to delete LUT")
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// removeLUT deletes a .cube file and its stored info.
+func removeLUT(path string) error {
+	err := os.Remove(path)
+	if infoErr := os.Remove(ffmpeg.LUTInfoPath(path)); err == nil && !errors.Is(infoErr, fs.ErrNotExist) {
+		err = infoErr
+	}
+	return err
+}
@@ -147,12 +147,16 @@ func streamMulticamStatus(c echo.Context, sse *datastar.ServerSentEventGenerator
 			}
 			switch row.Status {
 			case db.ExportStatusQueued:
-				_ = sse.PatchElementTempl(components.ExportStatus("multicam-export-status","Queued...", "queued", ""))
+				if err := sse.PatchElementTempl(components.ExportStatus("multicam-export-status", "Queued...", "queued", "")); err != nil {
+					return nil // client gone
+				}
 			case db.ExportStatusProcessing:
 				if row.ProgressPct != lastPct {
 					lastPct = row.ProgressPct
-					_ = sse.PatchElementTempl(components.ExportStatus("multicam-export-status",
-						fmt.Sprintf("Rendering %d%%…", row.ProgressPct), "processing", ""))
+					if err := sse.PatchElementTempl(components.ExportStatus("multicam-export-status",
+						fmt.Sprintf("Rendering %d%%…", row.ProgressPct), "processing", "")); err != nil {
+						return nil // client gone
+					}
 				}
 			case db.ExportStatusReady:
 				downloadURL := "/api/stitch/" + jobIDStr + "/download"
@@ -0,0 +1,190 @@
+package clip_api
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/png" // registers the PNG decoder for image.DecodeConfig
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// Overlay image upload limits.
+const (
+	maxOverlayImageBytes = 5 << 20
+	maxOverlayImageSide  = 4096
+)
+
+// overlayImage describes one of a user's uploaded overlay images.
+type overlayImage struct {
+	ID        string    `json:"id"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// exportsDir is the exports volume shared with the encoder, which also holds
+// overlay images.
+func exportsDir() string {
+	if dir := strings.TrimSpace(os.Getenv("EXPORTS_DIR")); dir != "" {
+		return dir
+	}
+	return "/exports"
+}
+
+// userOverlayDir is the directory of the user's overlay images.
+func userOverlayDir(userUUID pgtype.UUID) string {
+	return ffmpeg.OverlayImageDir(exportsDir(), userUUID.String())
+}
+
+// checkOverlayImages reports a user-facing error when one of the plan's
+// image_overlay filters names an image the user has not uploaded.
+func checkOverlayImages(plan *clipExportPlan, userUUID pgtype.UUID) error {
+	_, err := ffmpeg.ResolveOverlayImages(plan.Filters, userOverlayDir(userUUID))
+	return err
+}
+
+// HandleUploadOverlayImage serves POST /api/overlay-images, storing a PNG
+// (multipart field "file") for use by the image_overlay filter. Images are
+// private to the uploading user.
+func HandleUploadOverlayImage(sm *auth.SessionManager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			return common.ErrBadRequest("file is required")
+		}
+		if file.Size > maxOverlayImageBytes {
+			return common.ErrBadRequest("overlay images are limited to 5 MB")
+		}
+		src, err := file.Open()
+		if err != nil {
+			return common.ErrInternal("failed to open uploaded file")
+		}
+		defer src.Close()
+		data, err := io.ReadAll(io.LimitReader(src, maxOverlayImageBytes+1))
+		if err != nil {
+			return common.ErrInternal("failed to read uploaded file")
+		}
+		if len(data) > maxOverlayImageBytes {
+			return common.ErrBadRequest("overlay images are limited to 5 MB")
+		}
+
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil || format != "png" {
+			return common.ErrBadRequest("overlay images must be PNG files")
+		}
+		if cfg.Width > maxOverlayImageSide || cfg.Height > maxOverlayImageSide {
+			return common.ErrBadRequest("overlay images are limited to 4096×4096 pixels")
+		}
+
+		dir := userOverlayDir(userUUID)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			slog.Error("failed to create overlay image dir", "dir", dir, "error", err)
+			return common.ErrInternal("failed to store image")
+		}
+		id := uuid.New().String()
+		path, _ := ffmpeg.OverlayImagePath(dir, id)
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o644); err != nil {
+			slog.Error("failed to write overlay image", "path", tmp, "error", err)
+			return common.ErrInternal("failed to store image")
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			_ = os.Remove(tmp)
+			slog.Error("failed to store overlay image", "path", path, "error", err)
+			return common.ErrInternal("failed to store image")
+		}
+
+		return c.JSON(http.StatusCreated, overlayImage{ID: id, Size: int64(len(data)), CreatedAt: time.Now()})
+	}
+}
+
+// HandleListOverlayImages serves GET /api/overlay-images, the user's overlay
+// images, newest first.
+func HandleListOverlayImages(sm *auth.SessionManager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		entries, err := os.ReadDir(userOverlayDir(userUUID))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			slog.Error("failed to list overlay images", "error", err)
+			return common.ErrInternal("failed to list images")
+		}
+		images := []overlayImage{}
+		for _, e := range entries {
+			id, ok := strings.CutSuffix(e.Name(), ".png")
+			if !ok || e.IsDir() {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			images = append(images, overlayImage{ID: id, Size: info.Size(), CreatedAt: info.ModTime()})
+		}
+		sort.Slice(images, func(i, j int) bool { return images[i].CreatedAt.After(images[j].CreatedAt) })
+		return c.JSON(http.StatusOK, images)
+	}
+}
+
+// HandleGetOverlayImage serves GET /api/overlay-images/:id, the image itself.
+func HandleGetOverlayImage(sm *auth.SessionManager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+		path, err := ffmpeg.OverlayImagePath(userOverlayDir(userUUID), c.Param("id"))
+		if err != nil {
+			return common.ErrBadRequest(err.Error())
+		}
+		if _, err := os.Stat(path); err != nil {
+			return common.ErrNotFound("image not found")
+		}
+		c.Response().Header().Set("Cache-Control", "private, max-age=86400")
+		return c.File(path)
+	}
+}
+
+// HandleDeleteOverlayImage serves DELETE /api/overlay-images/:id. Exports
+// already encoded keep the image; queued ones that use it will fail.
+func HandleDeleteOverlayImage(sm *auth.SessionManager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+		path, err := ffmpeg.OverlayImagePath(userOverlayDir(userUUID), c.Param("id"))
+		if err != nil {
+			return common.ErrBadRequest(err.Error())
+		}
+		if err := os.Remove(path); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return common.ErrNotFound("image not found")
+			}
+			slog.Error("failed to delete overlay image", "path", path, "error", err)
+			return common.ErrInternal("failed to delete image")
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
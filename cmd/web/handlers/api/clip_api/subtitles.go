@@ -0,0 +1,37 @@
+package clip_api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// checkSubtitles reports a user-facing error when one of the plan's
+// subtitles filters asks for captions the clip's video does not have. It
+// returns the plan's filters with the caption files resolved, for compiling
+// checks; the encoder resolves them again when the export runs.
+func checkSubtitles(ctx context.Context, plan *clipExportPlan, clipRow *db.Clip) ([]ffmpeg.FilterSpec, error) {
+	videoID := clipRow.VideoID.String()
+	dir, err := fileserver.GetVideoDirForID(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return ffmpeg.ResolveSubtitleFiles(plan.Filters, func(lang string) (string, bool) {
+		if lang == "" {
+			matches, _ := filepath.Glob(filepath.Join(dir, videoID+".captions.*.vtt"))
+			if len(matches) == 0 {
+				return "", false
+			}
+			return matches[0], true
+		}
+		path := filepath.Join(dir, videoID+".captions."+lang+".vtt")
+		if _, err := os.Stat(path); err != nil {
+			return "", false
+		}
+		return path, true
+	})
+}
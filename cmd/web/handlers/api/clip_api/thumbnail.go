@@ -0,0 +1,151 @@
+package clip_api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+const (
+	// defaultClipThumbnailWidth fits the clip bank row; larger widths are
+	// for hover cards and the like.
+	defaultClipThumbnailWidth = 320
+	// clipThumbnailTimeout bounds one ffmpeg run.
+	clipThumbnailTimeout = 20 * time.Second
+)
+
+// clipThumbnailWidths are the widths GET /api/clips/:id/thumbnail.jpg accepts
+// for ?w=, kept to a few so the cache stays small.
+var clipThumbnailWidths = map[int]bool{160: true, 320: true, 640: true}
+
+// clipThumbnailSlots limits concurrent extractions; opening the cut page
+// requests every clip's thumbnail at once.
+var clipThumbnailSlots = make(chan struct{}, 2)
+
+// HandleClipThumbnail serves GET /api/clips/:id/thumbnail.jpg, the frame at
+// the clip's start, scaled to ?w= (160, 320 or 640; default 320). Other query
+// parameters are ignored; the clip bank adds the start time to bust caches. Frames are
+// extracted on first request and cached next to the video under a name that
+// includes the start time, so moving the clip's start renders a new one.
+func HandleClipThumbnail(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		clipUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		width := defaultClipThumbnailWidth
+		if raw := c.QueryParam("w"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || !clipThumbnailWidths[n] {
+				return common.ErrBadRequest("w must be 160, 320 or 640")
+			}
+			width = n
+		}
+
+		ctx := c.Request().Context()
+		clip, err := dbc.Queries(ctx).GetClip(ctx, clipUUID)
+		if err != nil || clip == nil {
+			return common.ErrNotFound("clip not found")
+		}
+		video, err := dbc.Queries(ctx).GetVideoByID(ctx, clip.VideoID)
+		if err != nil {
+			return common.ErrNotFound("video not found")
+		}
+		if video.ProbeData != nil && video.ProbeData.IsAudioOnly() {
+			return c.String(404, "thumbnail not available")
+		}
+		videoPath := common.DerefString(video.VideoPath)
+		if videoPath == "" {
+			return c.String(404, "thumbnail not available")
+		}
+		if _, err := os.Stat(videoPath); err != nil {
+			return c.String(404, "thumbnail not available")
+		}
+
+		clipID := clip.ID.String()
+		dir := filepath.Dir(videoPath)
+		startMs := int64(math.Round(clip.StartTs * 1000))
+		thumb := filepath.Join(dir, fmt.Sprintf("%s.clip-%s.%d.w%d.jpg", clip.VideoID.String(), clipID, startMs, width))
+		if _, err := os.Stat(thumb); err != nil {
+			if err := renderClipThumbnail(ctx, clipID, videoPath, thumb, time.Duration(startMs)*time.Millisecond, width); err != nil {
+				return common.ErrInternal("failed to render thumbnail")
+			}
+			removeStaleClipThumbnails(dir, clip.VideoID.String(), clipID, startMs)
+		}
+		return fs.ServeDiskFileWithCache(c, thumb, "image/jpeg", "private, max-age=86400, stale-while-revalidate=3600", fileserver.ETagWeakStat)
+	}
+}
+
+// renderClipThumbnail extracts the frame at offset to a temp file and moves
+// it into place, so a failed run never leaves a partial cache entry.
+func renderClipThumbnail(ctx context.Context, clipID, videoPath, thumb string, offset time.Duration, width int) error {
+	select {
+	case clipThumbnailSlots <- struct{}{}:
+		defer func() { <-clipThumbnailSlots }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	tmp := thumb + ".tmp.jpg"
+	defer os.Remove(tmp)
+	runCtx, cancel := context.WithTimeout(ctx, clipThumbnailTimeout)
+	defer cancel()
+	// ExtractThumbnail treats a zero offset as "use the default", so a clip
+	// starting at 0 asks for the first frame explicitly.
+	res := ffmpeg.ExtractThumbnail(runCtx, videoPath, tmp, &ffmpeg.ThumbnailOptions{
+		Offset:   max(offset, time.Nanosecond),
+		MaxWidth: width,
+	})
+	if res.Err != nil {
+		slog.Error("clip thumbnail render failed", "clip_id", clipID, "error", res.Err, "logs", res.Logs)
+		return res.Err
+	}
+	if info, err := os.Stat(tmp); err != nil || info.Size() == 0 {
+		// ffmpeg exits cleanly without a frame when seeking past the last one.
+		slog.Warn("clip thumbnail: no frame at start", "clip_id", clipID, "offset", offset)
+		return fmt.Errorf("no frame at %s", offset)
+	}
+	if err := os.Rename(tmp, thumb); err != nil {
+		slog.Error("failed to store clip thumbnail", "clip_id", clipID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// removeStaleClipThumbnails deletes a clip's cached thumbnails for start
+// times other than startMs.
+func removeStaleClipThumbnails(dir, videoID, clipID string, startMs int64) {
+	keep := fmt.Sprintf("%s.clip-%s.%d.", videoID, clipID, startMs)
+	matches, _ := filepath.Glob(filepath.Join(dir, videoID+".clip-"+clipID+".*.jpg"))
+	for _, p := range matches {
+		if !strings.HasPrefix(filepath.Base(p), keep) {
+			_ = os.Remove(p)
+		}
+	}
+}
+
+// removeClipThumbnails deletes every cached thumbnail of a clip.
+func removeClipThumbnails(dir, videoID, clipID string) {
+	matches, _ := filepath.Glob(filepath.Join(dir, videoID+".clip-"+clipID+".*.jpg"))
+	for _, p := range matches {
+		_ = os.Remove(p)
+	}
+}
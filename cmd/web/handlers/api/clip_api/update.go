@@ -99,20 +99,27 @@ func HandleUpdate(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 			endPtr = req.EndTs
 		}
 
-		// Update duration if timing changes are present
-		if req.StartTs != nil || req.EndTs != nil {
+		// Validate the resulting range against the video length and update
+		// duration if timing changes are present.
+		if startPtr != nil || endPtr != nil {
 			startVal := existing.StartTs
 			endVal := existing.EndTs
-			if req.StartTs != nil {
-				startVal = *req.StartTs
+			if startPtr != nil {
+				startVal = *startPtr
 			}
-			if req.EndTs != nil {
-				endVal = *req.EndTs
+			if endPtr != nil {
+				endVal = *endPtr
 			}
-			if endVal > startVal {
-				dur := endVal - startVal
-				durationPtr = &dur
+			video, err := dbc.Queries(ctx).GetVideoByID(ctx, existing.VideoID)
+			if err != nil {
+				return c.String(500, "failed to load video")
 			}
+			startVal, endVal, err = ClampClipUpdate(startVal, endVal, existing.StartTs, existing.EndTs, VideoDurationSeconds(video))
+			if err != nil {
+				return c.String(400, err.Error())
+			}
+			dur := endVal - startVal
+			startPtr, endPtr, durationPtr = &startVal, &endVal, &dur
 		}
 
 		// Update clip in database.
@@ -78,6 +78,7 @@ func HandleStream(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
 		c.Response().Header().Set(echo.HeaderCacheControl, "no-cache")
 		c.Response().Header().Set(echo.HeaderConnection, "keep-alive")
+		common.SetSSEHeaders(c)
 
 		last := map[string]string{}
 
@@ -102,9 +103,10 @@ func HandleStream(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 			return nil
 		}
 
+		canPrioritize := sm.GetAccessLevel(c.Request()) == auth.AccessAdmin
 		renderJobsCard := func(ctx context.Context, j *db.DownloadJob) (string, error) {
 			var buf bytes.Buffer
-			if err := templates.DownloadJobCard(j).Render(ctx, &buf); err != nil {
+			if err := templates.DownloadJobCard(j, canPrioritize).Render(ctx, &buf); err != nil {
 				return "", err
 			}
 			return buf.String(), nil
@@ -126,16 +128,26 @@ func HandleStream(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 		}
 
 		// Initial comment so proxies start streaming.
-		_, _ = fmt.Fprintf(w, ": connected\n\n")
-		flusher.Flush()
+		if err := common.WriteSSEComment(c, "connected"); err != nil {
+			return nil
+		}
 
 		ticker := time.NewTicker(1200 * time.Millisecond)
 		defer ticker.Stop()
 
+		// Keep-alives surface a dead client as a write error even when no
+		// job changes.
+		keepalive := time.NewTicker(15 * time.Second)
+		defer keepalive.Stop()
+
 		for {
 			select {
 			case <-c.Request().Context().Done():
 				return nil
+			case <-keepalive.C:
+				if err := common.WriteSSEComment(c, "keepalive"); err != nil {
+					return nil
+				}
 			case <-ticker.C:
 				ctx, cancel := context.WithTimeout(c.Request().Context(), 3*time.Second)
 				dbJobs, err := dbc.Queries(ctx).ListRecentDownloadJobs(ctx)
@@ -173,7 +185,9 @@ func HandleStream(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 					if err != nil {
 						continue
 					}
-					_ = send(id, string(j.Status), jobsHTML, homeHTML)
+					if err := send(id, string(j.Status), jobsHTML, homeHTML); err != nil {
+						return nil // client gone
+					}
 				}
 			}
 		}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"syscall"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
 	"thirdcoast.systems/rewind/cmd/web/auth"
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
@@ -31,17 +32,8 @@ func HandleCancel(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 
 		// Get the PID if process is running
 		pid, err := dbc.Queries(c.Request().Context()).GetDownloadJobPID(c.Request().Context(), jobUUID)
-		if err == nil && pid != nil && *pid > 0 {
-			process, findErr := os.FindProcess(int(*pid))
-			if findErr != nil {
-				slog.Warn("failed to find process", "job_id", jobUUID, "pid", *pid, "error", findErr)
-			} else {
-				if sigErr := process.Signal(syscall.SIGTERM); sigErr != nil {
-					slog.Warn("failed to signal process", "job_id", jobUUID, "pid", *pid, "error", sigErr)
-				} else {
-					slog.Info("signaled process to terminate", "job_id", jobUUID, "pid", *pid)
-				}
-			}
+		if err == nil {
+			signalJobProcess(jobUUID, pid)
 		}
 
 		if err := dbc.Queries(c.Request().Context()).CancelDownloadJob(c.Request().Context(), jobUUID); err != nil {
@@ -53,4 +45,21 @@ func HandleCancel(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 	}
 }
 
+// signalJobProcess sends SIGTERM to a download job's process, if it has one.
+func signalJobProcess(jobUUID pgtype.UUID, pid *int64) {
+	if pid == nil || *pid <= 0 {
+		return
+	}
+	process, findErr := os.FindProcess(int(*pid))
+	if findErr != nil {
+		slog.Warn("failed to find process", "job_id", jobUUID, "pid", *pid, "error", findErr)
+		return
+	}
+	if sigErr := process.Signal(syscall.SIGTERM); sigErr != nil {
+		slog.Warn("failed to signal process", "job_id", jobUUID, "pid", *pid, "error", sigErr)
+	} else {
+		slog.Info("signaled process to terminate", "job_id", jobUUID, "pid", *pid)
+	}
+}
+
 // HandleArchive archives a download job.
@@ -0,0 +1,66 @@
+package job_api
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// HandleCancelAll serves POST /jobs/cancel-all, cancelling every queued download
+// job submitted by the current user. With ?include_processing=true, the user's
+// running jobs are signalled and cancelled as well.
+func HandleCancelAll(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		includeProcessing := false
+		if raw := c.QueryParam("include_processing"); raw != "" {
+			includeProcessing, err = strconv.ParseBool(raw)
+			if err != nil {
+				return c.String(400, "invalid include_processing")
+			}
+		}
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		cancelled, err := q.CancelQueuedDownloadJobsForUser(ctx, userUUID)
+		if err != nil {
+			slog.Error("failed to cancel queued jobs", "user_id", userUUID, "error", err)
+			return c.String(500, "failed to cancel jobs")
+		}
+
+		var processing int64
+		if includeProcessing {
+			running, err := q.ListProcessingDownloadJobsForUser(ctx, userUUID)
+			if err != nil {
+				slog.Error("failed to list processing jobs", "user_id", userUUID, "error", err)
+				return c.String(500, "failed to cancel processing jobs")
+			}
+			for _, job := range running {
+				signalJobProcess(job.ID, job.ProcessPid)
+				if err := q.CancelDownloadJob(ctx, job.ID); err != nil {
+					slog.Error("failed to cancel job", "job_id", job.ID, "error", err)
+					continue
+				}
+				processing++
+			}
+		}
+
+		slog.Info("cancelled user download jobs", "user_id", userUUID, "queued", cancelled, "processing", processing)
+
+		return c.JSON(200, map[string]any{
+			"status":     "cancelled",
+			"cancelled":  cancelled + processing,
+			"queued":     cancelled,
+			"processing": processing,
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package job_api
 
 import (
+	"errors"
 	"log/slog"
 	"strings"
 
@@ -10,8 +11,14 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
 	"thirdcoast.systems/rewind/internal/archival"
 	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ytdlp"
 )
 // HandleCreateDownload serves POST /download-jobs, enqueuing a new URL for download.
+// Optional "headers" (name -> value) and "user_agent" are validated and passed
+// to yt-dlp for sources that require them. "metadata_only" saves the info JSON
+// and thumbnail without downloading the video file. "expand_playlist" archives
+// every video of the playlist or channel the URL names, even when the URL
+// isn't recognized as one.
 func HandleCreateDownload(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		archivedByUUID, _, err := common.RequireSessionUser(c, sm)
@@ -20,7 +27,11 @@ func HandleCreateDownload(sm *auth.SessionManager, dbc *db.DatabaseConnection) e
 		}
 
 		var req struct {
-			URL string `json:"url"`
+			URL            string            `json:"url"`
+			Headers        map[string]string `json:"headers"`
+			UserAgent      string            `json:"user_agent"`
+			MetadataOnly   bool              `json:"metadata_only"`
+			ExpandPlaylist bool              `json:"expand_playlist"`
 		}
 		if err := c.Bind(&req); err != nil {
 			return c.String(400, "invalid json")
@@ -30,17 +41,33 @@ func HandleCreateDownload(sm *auth.SessionManager, dbc *db.DatabaseConnection) e
 			return c.String(400, "url is required")
 		}
 
-		res, err := archival.EnqueueURL(c.Request().Context(), dbc.Queries(c.Request().Context()), req.URL, archivedByUUID)
+		extraArgs, err := ytdlp.RequestHeaderArgs(req.Headers, req.UserAgent)
+		if err != nil {
+			return c.String(400, err.Error())
+		}
+
+		res, err := archival.EnqueueURLWithOptions(c.Request().Context(), dbc.Queries(c.Request().Context()), req.URL, archivedByUUID, archival.EnqueueOptions{
+			ExtraArgs:      extraArgs,
+			MetadataOnly:   req.MetadataOnly,
+			ExpandPlaylist: req.ExpandPlaylist,
+		})
+		if errors.Is(err, archival.ErrDownloadsPaused) {
+			return c.String(503, err.Error())
+		}
+		if errors.Is(err, archival.ErrQualityCap) {
+			return c.String(400, err.Error())
+		}
 		if err != nil {
 			slog.Error("failed to enqueue download", "error", err)
 			return c.String(500, "failed to enqueue")
 		}
 
 		resp := map[string]any{
-			"id":       res.Job.ID.String(),
-			"status":   res.Job.Status,
-			"refresh":  res.Refresh,
-			"playlist": res.IsPlaylist,
+			"id":            res.Job.ID.String(),
+			"status":        res.Job.Status,
+			"refresh":       res.Refresh,
+			"playlist":      res.IsPlaylist,
+			"metadata_only": res.Job.MetadataOnly,
 		}
 		return c.JSON(200, resp)
 	}
@@ -30,7 +30,9 @@ func HandleIndex(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Handl
 
 		sse := datastar.NewSSE(c.Response().Writer, c.Request())
 
-		fragment := templates.JobsList(rows)
+		// Moving a job to the front of the shared queue is admin-only.
+		canPrioritize := sm.GetAccessLevel(c.Request()) == auth.AccessAdmin
+		fragment := templates.JobsList(rows, canPrioritize)
 		if err := sse.PatchElementTempl(fragment); err != nil {
 			slog.Error("failed to send jobs list SSE patch", "error", err)
 			return err
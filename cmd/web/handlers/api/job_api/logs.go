@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -27,12 +28,66 @@ type LogEntry struct {
 // LogsResponse is the response type for paginated job logs
 type LogsResponse struct {
 	Logs   []LogEntry `json:"logs"`
-	Total  int64      `json:"total"`
+	Total  int64      `json:"total"` // Matching lines (after filters)
 	Limit  int        `json:"limit"`
 	Offset int        `json:"offset"`
+	Stream string     `json:"stream,omitempty"`
+	Query  string     `json:"q,omitempty"`
 }
 
-// HandleLogs returns paginated logs for a specific job (JSON)
+// maxLogSearchLen bounds the ?q= substring filter.
+const maxLogSearchLen = 200
+
+// logFilter holds the optional ?stream= and ?q= filters shared by the JSON and
+// SSE log endpoints.
+type logFilter struct {
+	Stream string // "", "stdout", or "stderr"
+	Query  string // case-insensitive substring, "" for none
+}
+
+// parseLogFilter reads and validates ?stream= and ?q=.
+func parseLogFilter(c echo.Context) (logFilter, error) {
+	f := logFilter{
+		Stream: strings.ToLower(strings.TrimSpace(c.QueryParam("stream"))),
+		Query:  strings.TrimSpace(c.QueryParam("q")),
+	}
+	switch f.Stream {
+	case "", "all":
+		f.Stream = ""
+	case string(db.LogStreamStdout), string(db.LogStreamStderr):
+	default:
+		return f, common.ErrBadRequest("stream must be stdout or stderr")
+	}
+	if len(f.Query) > maxLogSearchLen {
+		return f, common.ErrBadRequest("q is too long")
+	}
+	return f, nil
+}
+
+func (f logFilter) streamArg() *string {
+	if f.Stream == "" {
+		return nil
+	}
+	return &f.Stream
+}
+
+func (f logFilter) searchArg() *string {
+	if f.Query == "" {
+		return nil
+	}
+	return &f.Query
+}
+
+// matches applies the filter in Go, for lines read by the SSE poller.
+func (f logFilter) matches(stream, message string) bool {
+	if f.Stream != "" && stream != f.Stream {
+		return false
+	}
+	return f.Query == "" || strings.Contains(strings.ToLower(message), strings.ToLower(f.Query))
+}
+
+// HandleLogs returns paginated logs for a specific job (JSON).
+// Query params: limit, offset, stream=stdout|stderr, q=<substring>.
 func HandleLogs(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		_, _, err := common.RequireSessionUser(c, sm)
@@ -45,6 +100,11 @@ func HandleLogs(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Handle
 			return err
 		}
 
+		filter, err := parseLogFilter(c)
+		if err != nil {
+			return err
+		}
+
 		// Fetch the job
 		_, err = dbc.Queries(c.Request().Context()).GetDownloadJobByID(c.Request().Context(), jobUUID)
 		if err != nil {
@@ -68,10 +128,18 @@ func HandleLogs(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Handle
 		if offsetStr := c.QueryParam("offset"); offsetStr != "" {
 			fmt.Sscanf(offsetStr, "%d", &offset)
 		}
+		if limit < 1 {
+			limit = 50
+		}
+		if offset < 0 {
+			offset = 0
+		}
 
 		// Fetch logs (DESC order, then reverse)
 		logs, err := dbc.Queries(c.Request().Context()).GetYtdlpLogsForJobPaginated(c.Request().Context(), &db.GetYtdlpLogsForJobPaginatedParams{
 			JobID:      jobUUID,
+			Stream:     filter.streamArg(),
+			Search:     filter.searchArg(),
 			PageLimit:  int32(limit),
 			PageOffset: int32(offset),
 		})
@@ -81,7 +149,11 @@ func HandleLogs(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Handle
 		}
 
 		// Get total count
-		totalCount, err := dbc.Queries(c.Request().Context()).CountYtdlpLogsForJob(c.Request().Context(), jobUUID)
+		totalCount, err := dbc.Queries(c.Request().Context()).CountYtdlpLogsForJob(c.Request().Context(), &db.CountYtdlpLogsForJobParams{
+			JobID:  jobUUID,
+			Stream: filter.streamArg(),
+			Search: filter.searchArg(),
+		})
 		if err != nil {
 			slog.Error("failed to count logs", "error", err)
 			totalCount = 0
@@ -103,11 +175,14 @@ func HandleLogs(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Handle
 			Total:  totalCount,
 			Limit:  limit,
 			Offset: offset,
+			Stream: filter.Stream,
+			Query:  filter.Query,
 		})
 	}
 }
 
-// HandleLogsStream streams logs via SSE as they're written
+// HandleLogsStream streams logs via SSE as they're written.
+// Accepts the same stream/q filters as HandleLogs.
 func HandleLogsStream(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		_, _, err := common.RequireSessionUser(c, sm)
@@ -120,6 +195,11 @@ func HandleLogsStream(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.
 			return err
 		}
 
+		filter, err := parseLogFilter(c)
+		if err != nil {
+			return err
+		}
+
 		// Fetch the job
 		_, err = dbc.Queries(c.Request().Context()).GetDownloadJobByID(c.Request().Context(), jobUUID)
 		if err != nil {
@@ -170,25 +250,27 @@ func HandleLogsStream(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.
 				}
 
 				for _, log := range logs {
+					// Advance past filtered-out lines too, so they aren't re-read.
+					lastTimestamp = log.CreatedAt
+					if !filter.matches(string(log.Stream), log.Message) {
+						continue
+					}
+
 					// Send each log as an SSE event
-					c.Response().Write([]byte("event: log\n"))
-					c.Response().Write([]byte("data: "))
-					enc := json.NewEncoder(w)
-					entry := LogEntry{
+					b, err := json.Marshal(LogEntry{
 						ID:        log.ID,
 						Stream:    string(log.Stream),
 						Message:   log.Message,
 						CreatedAt: log.CreatedAt.Time,
-					}
-					if err := enc.Encode(entry); err != nil {
+					})
+					if err != nil {
 						slog.Error("failed to encode log", "error", err)
 						continue
 					}
-					c.Response().Write([]byte("\n\n"))
+					if _, err := fmt.Fprintf(w, "event: log\ndata: %s\n\n", b); err != nil {
+						return nil // client gone
+					}
 					flusher.Flush()
-
-					// Update last timestamp
-					lastTimestamp = log.CreatedAt
 				}
 
 				// Check if job is finished
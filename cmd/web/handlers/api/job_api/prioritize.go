@@ -0,0 +1,66 @@
+package job_api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// HandlePrioritize serves POST /jobs/:id/prioritize, moving a queued download
+// job to the front of the queue. The queue is shared by every user, so only
+// admins may reorder it. Responds with the job's new priority and 1-based
+// queue position.
+func HandlePrioritize(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+		if sm.GetAccessLevel(c.Request()) != auth.AccessAdmin {
+			return echo.NewHTTPError(http.StatusForbidden, "only admins can prioritize jobs")
+		}
+
+		jobUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		jobRow, err := q.GetDownloadJobByID(ctx, jobUUID)
+		if err != nil || jobRow == nil {
+			return c.String(404, "job not found")
+		}
+		if jobRow.Status != db.JobStatusQueued {
+			return c.String(409, "only queued jobs can be prioritized")
+		}
+
+		priority, err := q.PrioritizeDownloadJob(ctx, jobUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Claimed by a worker between the read and the update.
+				return c.String(409, "only queued jobs can be prioritized")
+			}
+			slog.Error("failed to prioritize job", "job_id", jobUUID, "error", err)
+			return c.String(500, "failed to prioritize job")
+		}
+
+		position, err := q.GetDownloadJobQueuePosition(ctx, jobUUID)
+		if err != nil {
+			slog.Error("failed to compute queue position", "job_id", jobUUID, "error", err)
+			return c.String(500, "failed to compute queue position")
+		}
+
+		return c.JSON(200, map[string]any{
+			"status":         "queued",
+			"priority":       priority,
+			"queue_position": position,
+		})
+	}
+}
@@ -46,6 +46,9 @@ func HandleStatus(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Hand
 				// Fetch current job
 				job, err := dbc.Queries(ctx).GetDownloadJobByID(ctx, jobUUID)
 				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
 					slog.Error("failed to fetch job for SSE", "error", err, "job_id", jobUUID)
 					return err
 				}
@@ -0,0 +1,203 @@
+// package playlist_api provides continuous-playback API handlers for channels
+// and archived playlists.
+package playlist_api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/video_api"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/hls"
+)
+
+const (
+	// maxPlaylistVideos caps how many videos one playlist plays through.
+	maxPlaylistVideos = 500
+	// playlistFirstWait is how long a request waits for the first video's
+	// rendition before answering 503.
+	playlistFirstWait = 30 * time.Second
+	// playlistPrefetch is how many videos past the last ready one get their
+	// renditions started, so playback doesn't stall at each boundary.
+	playlistPrefetch = 2
+)
+
+// HandleHLSMaster serves GET /api/playlists/:id/hls/master.m3u8, a single HLS
+// media playlist that plays a playlist's videos back to back. :id is either a
+// playlist download job's ID, for the videos that job archived in playlist
+// order, or a channel ID, for the channel's videos oldest first.
+//
+// Each video's segments come from its own HLS rendition (see
+// video_api.HLSCache), separated by EXT-X-DISCONTINUITY. Renditions are
+// remuxed on demand, so the playlist stops at the first video that isn't
+// ready yet and is served as an EVENT playlist; players reload it and pick up
+// videos as they finish. Videos without a file or whose remux fails are
+// skipped. Once every video is ready the playlist is VOD. Encrypted
+// renditions keep their own key, so one playlist can mix keys.
+//
+// An EVENT playlist may only grow, so what a reload has already served is
+// kept (see servedPlaylists) and later reloads only append past it, and the
+// renditions it references are pinned against eviction.
+func HandleHLSMaster(sm *auth.SessionManager, dbc *db.DatabaseConnection, cache *video_api.HLSCache) echo.HandlerFunc {
+	served := newServedPlaylists()
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		if !cache.Enabled() {
+			return common.ErrNotFound("continuous playback is disabled")
+		}
+		id := strings.TrimSpace(c.Param("id"))
+		if id == "" {
+			return common.ErrBadRequest("missing playlist id")
+		}
+
+		ctx := c.Request().Context()
+		videoIDs, err := playlistVideoIDs(ctx, dbc.Queries(ctx), id)
+		if err != nil {
+			slog.Error("failed to list playlist videos", "playlist_id", id, "error", err)
+			return common.ErrInternal("failed to load playlist")
+		}
+		if len(videoIDs) == 0 {
+			return common.ErrNotFound("playlist not found")
+		}
+
+		sp := served.get(id)
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+
+		parts := sp.parts
+		ended := true
+		waited := false
+		for i := 0; i < len(videoIDs); i++ {
+			videoID := videoIDs[i].String()
+			if _, ok := sp.seen[videoID]; ok {
+				continue
+			}
+			p, done, err := cache.Video(ctx, videoID)
+			if errors.Is(err, video_api.ErrHLSUnavailable) {
+				sp.seen[videoID] = struct{}{}
+				continue
+			}
+			if err != nil {
+				slog.Error("failed to load video hls", "playlist_id", id, "video_id", videoID, "error", err)
+				return common.ErrInternal("failed to load playlist")
+			}
+			if p != nil {
+				sp.seen[videoID] = struct{}{}
+				parts = append(parts, hls.Part{Playlist: p, Base: "/api/videos/" + videoID + "/hls/"})
+				sp.videoIDs = append(sp.videoIDs, videoID)
+				continue
+			}
+
+			// Nothing to play yet: give the first remux a chance to finish.
+			if len(parts) == 0 && !waited {
+				waited = true
+				timer := time.NewTimer(playlistFirstWait)
+				select {
+				case <-done:
+					timer.Stop()
+					i--
+					continue
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				}
+			}
+
+			// The playlist only grows at the end, so it stops at the first
+			// video still remuxing. Start the next few meanwhile.
+			ended = false
+			for _, next := range videoIDs[i+1 : min(i+1+playlistPrefetch, len(videoIDs))] {
+				_, _, _ = cache.Video(ctx, next.String())
+			}
+			break
+		}
+		sp.parts = parts
+		cache.Pin(sp.videoIDs...)
+
+		if len(parts) == 0 {
+			if !ended {
+				c.Response().Header().Set("Retry-After", "10")
+				return c.String(http.StatusServiceUnavailable, "playlist is being prepared")
+			}
+			return common.ErrNotFound("no playable videos")
+		}
+
+		c.Response().Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		c.Response().Header().Set("Cache-Control", "private, no-cache")
+		c.Response().WriteHeader(http.StatusOK)
+		return hls.WriteConcat(c.Response(), parts, ended)
+	}
+}
+
+// servedPlaylist is what HandleHLSMaster has served for one playlist: the
+// parts in order, and every video already placed in the playlist or skipped.
+// Reloads start from it, so a rendition evicted or remuxed again later, or a
+// remux failure that expires, never takes a served video back out or puts a
+// skipped one in the middle.
+type servedPlaylist struct {
+	mu       sync.Mutex
+	parts    []hls.Part
+	videoIDs []string
+	seen     map[string]struct{}
+	used     time.Time
+}
+
+// servedPlaylists holds the servedPlaylist of each playlist reloaded within
+// video_api.HLSPinTTL, the time its renditions stay pinned.
+type servedPlaylists struct {
+	mu        sync.Mutex
+	playlists map[string]*servedPlaylist
+}
+
+func newServedPlaylists() *servedPlaylists {
+	return &servedPlaylists{playlists: map[string]*servedPlaylist{}}
+}
+
+// get returns id's servedPlaylist, starting an empty one if it has none or
+// it went unused for longer than video_api.HLSPinTTL.
+func (s *servedPlaylists) get(id string) *servedPlaylist {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for k, sp := range s.playlists {
+		if now.Sub(sp.used) >= video_api.HLSPinTTL {
+			delete(s.playlists, k)
+		}
+	}
+	sp, ok := s.playlists[id]
+	if !ok {
+		sp = &servedPlaylist{seen: map[string]struct{}{}}
+		s.playlists[id] = sp
+	}
+	sp.used = now
+	return sp
+}
+
+// playlistVideoIDs resolves :id to video IDs: a UUID names a playlist
+// download job, anything else a channel.
+func playlistVideoIDs(ctx context.Context, q *db.Queries, id string) ([]pgtype.UUID, error) {
+	var jobID pgtype.UUID
+	if err := jobID.Scan(id); err == nil {
+		return q.ListPlaylistJobVideos(ctx, &db.ListPlaylistJobVideosParams{
+			ParentJobID: jobID,
+			MaxVideos:   maxPlaylistVideos,
+		})
+	}
+	return q.ListChannelPlaylistVideos(ctx, &db.ListChannelPlaylistVideosParams{
+		ChannelID: &id,
+		MaxVideos: maxPlaylistVideos,
+	})
+}
@@ -0,0 +1,25 @@
+package playlist_api
+
+import (
+	"testing"
+	"time"
+
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/video_api"
+)
+
+func TestServedPlaylists(t *testing.T) {
+	s := newServedPlaylists()
+	sp := s.get("UCabc")
+	sp.seen["v1"] = struct{}{}
+	if got := s.get("UCabc"); got != sp {
+		t.Fatal("reload did not return the served playlist")
+	}
+	if got := s.get("UCdef"); got == sp || len(got.seen) != 0 {
+		t.Fatal("another playlist shares the served state")
+	}
+
+	sp.used = time.Now().Add(-video_api.HLSPinTTL)
+	if got := s.get("UCabc"); got == sp || len(got.seen) != 0 {
+		t.Fatal("expired playlist was not started over")
+	}
+}
@@ -0,0 +1,72 @@
+package settings_api
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+type playbackPositionResponse struct {
+	VideoID   string     `json:"video_id"`
+	Position  float64    `json:"position"`
+	Duration  *float64   `json:"duration"`
+	Saved     bool       `json:"saved"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Watched   bool       `json:"watched"`
+	WatchedAt *time.Time `json:"watched_at,omitempty"`
+}
+
+// HandleGetPlaybackPosition serves GET /videos/:id/position, returning the
+// current user's saved resume position so any device can pick up where the
+// last one stopped. Without a saved position it returns position 0 and
+// saved=false.
+func HandleGetPlaybackPosition(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		row, err := dbc.Queries(ctx).GetVideoPlaybackState(ctx, &db.GetVideoPlaybackStateParams{
+			UserID:  userUUID,
+			VideoID: videoUUID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			slog.Error("failed to load playback position", "error", err, "video_id", videoUUID)
+			return common.ErrInternal("failed to load playback position")
+		}
+
+		resp := playbackPositionResponse{VideoID: videoUUID.String()}
+		if row.DurationSeconds != nil {
+			d := float64(*row.DurationSeconds)
+			resp.Duration = &d
+		}
+		if row.PositionSeconds != nil {
+			resp.Position = *row.PositionSeconds
+			resp.Saved = true
+		}
+		if row.UpdatedAt.Valid {
+			resp.UpdatedAt = &row.UpdatedAt.Time
+		}
+		if row.WatchedAt.Valid {
+			resp.Watched = true
+			resp.WatchedAt = &row.WatchedAt.Time
+		}
+		return c.JSON(200, resp)
+	}
+}
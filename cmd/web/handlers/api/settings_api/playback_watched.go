@@ -0,0 +1,59 @@
+package settings_api
+
+import (
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// HandleSetWatched serves PUT /videos/:id/watched with {"watched": bool},
+// marking the video watched or unwatched for the current user. Both reset
+// the resume position to the start.
+func HandleSetWatched(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		var req struct {
+			Watched *bool `json:"watched"`
+		}
+		if err := c.Bind(&req); err != nil || req.Watched == nil {
+			return c.String(400, "watched must be true or false")
+		}
+
+		ctx := c.Request().Context()
+		if _, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+
+		err = dbc.Queries(ctx).SetPlaybackWatched(ctx, &db.SetPlaybackWatchedParams{
+			UserID:  userUUID,
+			VideoID: videoUUID,
+			Watched: *req.Watched,
+		})
+		if err != nil {
+			slog.Error("failed to set watched state", "error", err, "video_id", videoUUID)
+			return c.String(500, "failed to update watched state")
+		}
+
+		return c.JSON(200, map[string]any{
+			"success": true,
+			"watched": *req.Watched,
+		})
+	}
+}
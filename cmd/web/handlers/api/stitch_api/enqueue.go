@@ -35,6 +35,29 @@ type stitchSegmentForValidation struct {
 	RawTransition json.RawMessage `json:"transition"`
 }
 
+// stripSegmentFilters removes the encoder's "_" params from a segment's
+// filters, leaving the rest of the segment as sent.
+func stripSegmentFilters(raw json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	rawFilters, ok := fields["filters"]
+	if !ok {
+		return raw, nil
+	}
+	var specs []ffmpeg.FilterSpec
+	if err := json.Unmarshal(rawFilters, &specs); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(ffmpeg.StripInjectedParams(specs))
+	if err != nil {
+		return nil, err
+	}
+	fields["filters"] = b
+	return json.Marshal(fields)
+}
+
 // HandleStitchEnqueue validates and enqueues a stitch job, then streams status via SSE.
 func HandleStitchEnqueue(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -77,6 +100,14 @@ func HandleStitchEnqueue(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 			return sseError("Invalid quality (high or max)")
 		}
 
+		// Subtitles are burned in from one video's captions, which the
+		// sequence as a whole does not have.
+		for _, f := range req.GlobalFilters {
+			if f.Type == "subtitles" {
+				return sseError("Subtitles can only be burned into clip and video segments")
+			}
+		}
+
 		// Validate segment transition durations don't exceed segment durations.
 		for i, rawSeg := range req.Segments {
 			var seg stitchSegmentForValidation
@@ -84,6 +115,11 @@ func HandleStitchEnqueue(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 				slog.Error("stitch segment unmarshal failed", "index", i, "error", err)
 				return sseError(fmt.Sprintf("Invalid segment[%d]", i))
 			}
+			stripped, err := stripSegmentFilters(rawSeg)
+			if err != nil {
+				return sseError(fmt.Sprintf("Invalid segment[%d] filters", i))
+			}
+			req.Segments[i] = stripped
 			// Parse transition — RawTransition may be null, "", or a JSON object.
 			var trDuration float64
 			if len(seg.RawTransition) > 0 && seg.RawTransition[0] == '{' {
@@ -109,7 +145,7 @@ func HandleStitchEnqueue(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 		}
 		globalFiltersJSON := []byte("[]")
 		if len(req.GlobalFilters) > 0 {
-			globalFiltersJSON, _ = json.Marshal(req.GlobalFilters)
+			globalFiltersJSON, _ = json.Marshal(ffmpeg.StripInjectedParams(req.GlobalFilters))
 		}
 
 		ctx := c.Request().Context()
@@ -191,12 +227,16 @@ func streamStitchStatus(c echo.Context, sse *datastar.ServerSentEventGenerator,
 
 			switch row.Status {
 			case db.ExportStatusQueued:
-				_ = sse.PatchElementTempl(components.StitchExportStatus("Queued...", "queued", ""))
+				if err := sse.PatchElementTempl(components.StitchExportStatus("Queued...", "queued", "")); err != nil {
+					return nil // client gone
+				}
 			case db.ExportStatusProcessing:
 				if row.ProgressPct != lastPct {
 					lastPct = row.ProgressPct
-					_ = sse.PatchElementTempl(components.StitchExportStatus(
-						fmt.Sprintf("Stitching %d%%…", row.ProgressPct), "processing", ""))
+					if err := sse.PatchElementTempl(components.StitchExportStatus(
+						fmt.Sprintf("Stitching %d%%…", row.ProgressPct), "processing", "")); err != nil {
+						return nil // client gone
+					}
 				}
 			case db.ExportStatusReady:
 				downloadURL := "/api/stitch/" + jobIDStr + "/download"
@@ -0,0 +1,69 @@
+package tag_api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+const (
+	defaultScrapedTagLimit = 200
+	maxScrapedTagLimit     = 1000
+)
+
+// ScrapedTagCount is one entry in the scraped tag cloud response.
+type ScrapedTagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// HandleListScrapedTags serves GET /api/tags/scraped?min_count=N&limit=N,
+// returning the distinct tags scraped from source metadata across the archive
+// with how many videos carry each, most-used first. Tags on fewer than
+// min_count videos (default 1) are left out. Each tag can be passed to the
+// library's tags filter as-is.
+func HandleListScrapedTags(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		minCount := int64(1)
+		if raw := c.QueryParam("min_count"); raw != "" {
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || n < 1 {
+				return common.ErrBadRequest("invalid min_count")
+			}
+			minCount = n
+		}
+		limit := defaultScrapedTagLimit
+		if raw := c.QueryParam("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				return common.ErrBadRequest("invalid limit")
+			}
+			limit = min(n, maxScrapedTagLimit)
+		}
+
+		ctx := c.Request().Context()
+		rows, err := dbc.Queries(ctx).ListScrapedTagCounts(ctx, &db.ListScrapedTagCountsParams{
+			MinCount:  minCount,
+			PageLimit: int32(limit),
+		})
+		if err != nil {
+			slog.Error("failed to list scraped tags", "error", err)
+			return common.ErrInternal("failed to list tags")
+		}
+
+		out := make([]ScrapedTagCount, 0, len(rows))
+		for _, r := range rows {
+			out = append(out, ScrapedTagCount{Tag: r.Tag, Count: r.VideoCount})
+		}
+		return c.JSON(http.StatusOK, map[string]any{"tags": out})
+	}
+}
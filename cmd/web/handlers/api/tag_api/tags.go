@@ -180,7 +180,7 @@ func loadTagEditorData(ctx context.Context, q *db.Queries, videoID string, video
 			if r.Color != nil {
 				color = *r.Color
 			}
-			tags = append(tags, components.TagItem{ID: r.ID.String(), Name: r.Name, Color: color})
+			tags = append(tags, components.TagItem{ID: r.ID.String(), Name: r.Name, Color: color, Auto: r.Auto})
 		}
 	}
 	return components.TagEditorData{VideoID: videoID, Tags: tags}
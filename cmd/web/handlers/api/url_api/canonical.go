@@ -0,0 +1,83 @@
+// package url_api provides URL inspection API handlers.
+package url_api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/videoid"
+)
+
+// CanonicalResponse describes how the dedup pipeline sees a URL.
+type CanonicalResponse struct {
+	Input           string `json:"input"`
+	ExpandedURL     string `json:"expanded_url"`
+	ExpandedHost    string `json:"expanded_host"`
+	CanonicalDomain string `json:"canonical_domain"`
+	NormalizedURL   string `json:"normalized_url"`
+	SourceVideoID   string `json:"source_video_id,omitempty"`
+	VideoUUID       string `json:"video_uuid,omitempty"`
+}
+
+// HandleCanonical serves GET /api/url/canonical?u=<url>, returning the expanded
+// URL, canonical domain, normalized src, and the deterministic video UUID the
+// ingest pipeline would assign (when the source ID is derivable pre-download).
+//
+// Expansion may make outbound requests, so this is restricted to signed-in users.
+func HandleCanonical(sm *auth.SessionManager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		raw := strings.TrimSpace(c.QueryParam("u"))
+		if raw == "" {
+			return common.ErrBadRequest("missing u")
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+		defer cancel()
+
+		resp, err := Canonicalize(ctx, raw)
+		if err != nil {
+			return common.ErrBadRequest("invalid url")
+		}
+
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+// Canonicalize expands and normalizes raw exactly as ingest does, deriving the
+// deterministic video UUID when the source ID is available pre-download.
+func Canonicalize(ctx context.Context, raw string) (CanonicalResponse, error) {
+	resp := CanonicalResponse{Input: raw}
+
+	// Mirror the ingest order: expand first, then normalize the expanded URL.
+	expanded, err := videoid.ExpandAndCanonicalizeURL(ctx, raw)
+	if err != nil {
+		return resp, err
+	}
+	resp.ExpandedURL = expanded.ExpandedURL
+	resp.ExpandedHost = expanded.ExpandedHost
+	resp.CanonicalDomain = expanded.CanonicalDomain
+
+	normalized, canon, err := videoid.NormalizeSourceURL(expanded.ExpandedURL)
+	if err != nil {
+		return resp, err
+	}
+	resp.NormalizedURL = normalized
+	if canon != "" {
+		resp.CanonicalDomain = canon
+	}
+
+	if id := videoid.SourceVideoID(normalized, resp.CanonicalDomain); id != "" {
+		resp.SourceVideoID = id
+		resp.VideoUUID = videoid.VideoUUID(resp.CanonicalDomain, id).String()
+	}
+	return resp, nil
+}
@@ -0,0 +1,132 @@
+package url_api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/internal/videoid"
+)
+
+// ExistsResponse reports whether a URL is already archived.
+type ExistsResponse struct {
+	URL           string `json:"url"`
+	NormalizedURL string `json:"normalized_url,omitempty"`
+	// VideoUUID is the deterministic ID ingest would assign; empty when the
+	// source ID cannot be derived before download.
+	VideoUUID   string `json:"video_uuid,omitempty"`
+	IDDerivable bool   `json:"id_derivable"`
+	// Playlist is true for playlist/channel URLs, which are never "archived"
+	// as a single video.
+	Playlist bool   `json:"playlist"`
+	Archived bool   `json:"archived"`
+	VideoID  string `json:"video_id,omitempty"`
+	Title    string `json:"title,omitempty"`
+	// MatchedBy is "id" (deterministic UUID) or "src" (stored source URL).
+	MatchedBy string `json:"matched_by,omitempty"`
+}
+
+// HandleVideoExists serves GET /api/videos/exists?url=<url>, reporting whether
+// the URL is already archived without enqueueing anything.
+func HandleVideoExists(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		raw := strings.TrimSpace(c.QueryParam("url"))
+		if raw == "" {
+			return common.ErrBadRequest("missing url")
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+		defer cancel()
+
+		resp, err := LookupArchived(ctx, dbc.Queries(ctx), raw)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+// LookupArchived canonicalizes raw and looks for an existing video, first by
+// its deterministic UUID (when derivable) and then by stored source URL, which
+// covers sources whose ID is only known after download. Returned errors are
+// *echo.HTTPError values ready to return from a handler.
+func LookupArchived(ctx context.Context, q *db.Queries, raw string) (*ExistsResponse, error) {
+	resp := &ExistsResponse{URL: raw}
+	if videoid.IsPlaylistOrChannelURL(raw) {
+		resp.Playlist = true
+		return resp, nil
+	}
+
+	canon, err := Canonicalize(ctx, raw)
+	if err != nil {
+		return nil, common.ErrBadRequest("invalid url")
+	}
+	resp.NormalizedURL = canon.NormalizedURL
+	resp.VideoUUID = canon.VideoUUID
+	resp.IDDerivable = canon.VideoUUID != ""
+
+	if resp.IDDerivable {
+		var id pgtype.UUID
+		if err := id.Scan(canon.VideoUUID); err == nil {
+			video, err := q.GetVideoByID(ctx, id)
+			if err == nil {
+				resp.setMatch(video, "id")
+				return resp, nil
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return nil, common.ErrInternal("failed to look up video")
+			}
+		}
+	}
+
+	for _, src := range uniqueNonEmpty(canon.NormalizedURL, raw) {
+		video, err := q.SelectVideoBySrc(ctx, src)
+		if err == nil {
+			resp.setMatch(video, "src")
+			return resp, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrInternal("failed to look up video")
+		}
+	}
+	return resp, nil
+}
+
+func (r *ExistsResponse) setMatch(video *db.Video, matchedBy string) {
+	r.Archived = true
+	r.VideoID = video.ID.String()
+	r.Title = video.Title
+	r.MatchedBy = matchedBy
+}
+
+func uniqueNonEmpty(values ...string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		dup := false
+		for _, o := range out {
+			if o == v {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			out = append(out, v)
+		}
+	}
+	return out
+}
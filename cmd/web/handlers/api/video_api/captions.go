@@ -10,12 +10,16 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
 	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/utils/captionstyle"
 )
 
-// HandleCaptions serves the video captions.
+// HandleCaptions serves the video captions. The file is served as stored
+// unless ?styled=1 is set, in which case the user's caption display
+// preferences are applied; the player uses that form, downloads do not.
 func HandleCaptions(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		if _, _, err := sm.GetSession(c.Request()); err != nil {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
 			return c.String(401, "unauthorized")
 		}
 
@@ -29,21 +33,48 @@ func HandleCaptions(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fil
 			return err
 		}
 
-		// Prefer English, then und, then any captions.*.vtt.
-		candidates := []string{
-			filepath.Join(dir, videoID+".captions.en.vtt"),
-			filepath.Join(dir, videoID+".captions.und.vtt"),
+		path, ok := captionFilePath(dir, videoID, "")
+		if !ok {
+			return c.String(404, "captions not available")
 		}
-		for _, p := range candidates {
-			if _, err := os.Stat(p); err == nil {
-				return fs.ServeDiskFileWithCache(c, p, "text/vtt", "private, max-age=86400, stale-while-revalidate=3600", fileserver.ETagStrongSHA256)
+
+		if c.QueryParam("styled") == "1" {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return common.ErrInternal("failed to read captions")
 			}
+			style := common.LoadCaptionStyle(c.Request().Context(), dbc, userUUID)
+			// Preferences can change at any time, so styled output is not cached.
+			c.Response().Header().Set("Cache-Control", "private, no-cache")
+			return c.Blob(200, "text/vtt; charset=utf-8", captionstyle.Apply(raw, style))
 		}
-		glob := filepath.Join(dir, videoID+".captions.*.vtt")
-		matches, _ := filepath.Glob(glob)
-		if len(matches) == 0 {
-			return c.String(404, "captions not available")
+		return fs.ServeDiskFileWithCache(c, path, "text/vtt", "private, max-age=86400, stale-while-revalidate=3600", fileserver.ETagStrongSHA256)
+	}
+}
+
+// captionFilePath finds the video's captions.<lang>.vtt. With no lang it
+// prefers English, then und, then any captions.*.vtt.
+func captionFilePath(dir, videoID, lang string) (string, bool) {
+	var candidates []string
+	if lang != "" {
+		candidates = []string{filepath.Join(dir, videoID+".captions."+lang+".vtt")}
+	} else {
+		candidates = []string{
+			filepath.Join(dir, videoID+".captions.en.vtt"),
+			filepath.Join(dir, videoID+".captions.und.vtt"),
 		}
-		return fs.ServeDiskFileWithCache(c, matches[0], "text/vtt", "private, max-age=86400, stale-while-revalidate=3600", fileserver.ETagStrongSHA256)
 	}
+	for _, p := range candidates {
+		if _, err := os.Stat(p); err == nil {
+			return p, true
+		}
+	}
+	if lang != "" {
+		return "", false
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, videoID+".captions.*.vtt"))
+	if len(matches) == 0 {
+		return "", false
+	}
+	return matches[0], true
 }
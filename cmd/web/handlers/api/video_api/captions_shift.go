@@ -0,0 +1,216 @@
+package video_api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	xtlang "golang.org/x/text/language"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	rewindlang "thirdcoast.systems/rewind/pkg/utils/language"
+	"thirdcoast.systems/rewind/pkg/utils/vttshift"
+)
+
+// maxCaptionShift bounds a single correction; drift beyond this is a wrong
+// file, not a sync problem.
+const maxCaptionShift = time.Hour
+
+// captionBackupSuffix names the copy of the captions as they were before the
+// first shift. It does not end in .vtt, so caption lookups never pick it up.
+const captionBackupSuffix = ".orig"
+
+type captionShiftRequest struct {
+	// Offset is added to every cue time, in seconds. Negative moves
+	// captions earlier.
+	Offset float64 `json:"offset"`
+	// Restore puts back the captions as they were before the first shift.
+	Restore bool `json:"restore"`
+}
+
+// HandleCaptionsShift serves POST /api/videos/:id/captions/shift, moving every
+// cue in the video's captions by a fixed offset to correct drift. The first
+// shift keeps a backup of the original file; shifts stack on the current
+// file, and {"restore": true} returns to the original. The stored transcript
+// is updated to match. Only the user who archived the video or an admin may
+// shift its captions, and the request fails with 409 while ingest is writing
+// the video's assets.
+func HandleCaptionsShift(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		videoID := videoUUID.String()
+
+		ctx := c.Request().Context()
+		videoRow, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			slog.Error("failed to fetch video", "error", err, "video_id", videoID)
+			return common.ErrInternal("failed to fetch video")
+		}
+		// Only the archiver or an admin may rewrite a video's captions.
+		if fmt.Sprint(c.Get("accessLevel")) != "admin" && userID != videoRow.ArchivedBy {
+			return echo.NewHTTPError(http.StatusForbidden, "forbidden")
+		}
+
+		var req captionShiftRequest
+		if err := c.Bind(&req); err != nil {
+			return common.ErrBadRequest("invalid json")
+		}
+		if math.IsNaN(req.Offset) || math.Abs(req.Offset) > maxCaptionShift.Seconds() {
+			return common.ErrBadRequest("offset must be within ±3600 seconds")
+		}
+		offset := time.Duration(math.Round(req.Offset*1000)) * time.Millisecond
+		if !req.Restore && offset == 0 {
+			return common.ErrBadRequest("offset is required")
+		}
+
+		dir, err := fileserver.GetVideoDirForID(ctx, videoID)
+		if err != nil {
+			return err
+		}
+
+		// Hold the asset lock so a caption regeneration in ingest can't
+		// replace the file or transcript mid-shift.
+		release, acquired, err := dbc.LockVideoAssets(ctx, videoID, false)
+		defer release()
+		if err != nil {
+			slog.Error("failed to lock video assets", "error", err, "video_id", videoID)
+			return common.ErrInternal("failed to lock captions")
+		}
+		if !acquired {
+			return echo.NewHTTPError(http.StatusConflict, "video assets are being regenerated, try again later")
+		}
+
+		path := findVTTFile(dir, videoID)
+		if path == "" {
+			return common.ErrNotFound("captions not available")
+		}
+		out, cues, err := shiftCaptionFile(path, offset, req.Restore)
+		if err != nil {
+			return err
+		}
+		if req.Restore {
+			offset = 0
+		}
+
+		lang := captionFileLang(path, videoID)
+		updated, err := dbc.Queries(ctx).UpdateVideoTranscriptRaw(ctx, &db.UpdateVideoTranscriptRawParams{
+			Raw:     string(out),
+			VideoID: videoUUID,
+			Lang:    lang,
+		})
+		if err != nil {
+			// The file is the source of truth; a stale transcript row only
+			// affects search snippets until the next ingest.
+			slog.Error("failed to update transcript after caption shift", "error", err, "video_id", videoID)
+		}
+
+		slog.Info("captions shifted", "video_id", videoID, "offset_ms", offset.Milliseconds(), "cues", cues, "restore", req.Restore)
+		return c.JSON(200, map[string]any{
+			"video_id":           videoID,
+			"offset":             offset.Seconds(),
+			"cues":               cues,
+			"restored":           req.Restore,
+			"backup":             !req.Restore,
+			"transcript_updated": err == nil && updated > 0,
+		})
+	}
+}
+
+// shiftCaptionFile moves the cues in path by offset, backing up the original
+// on the first shift, or with restore puts the backup back. It returns the
+// new file contents and the number of cues shifted. Errors are HTTP errors.
+func shiftCaptionFile(path string, offset time.Duration, restore bool) ([]byte, int, error) {
+	backup := path + captionBackupSuffix
+	if restore {
+		out, err := os.ReadFile(backup)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, common.ErrNotFound("captions have not been shifted")
+		}
+		if err != nil {
+			slog.Error("failed to read caption backup", "error", err, "path", backup)
+			return nil, 0, common.ErrInternal("failed to read caption backup")
+		}
+		if err := writeFileAtomic(path, out); err != nil {
+			slog.Error("failed to write restored captions", "error", err, "path", path)
+			return nil, 0, common.ErrInternal("failed to write captions")
+		}
+		if err := os.Remove(backup); err != nil {
+			slog.Warn("failed to remove caption backup", "error", err, "path", backup)
+		}
+		return out, 0, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		slog.Error("failed to read captions", "error", err, "path", path)
+		return nil, 0, common.ErrInternal("failed to read captions")
+	}
+	out, cues, err := vttshift.Shift(raw, offset)
+	if err != nil {
+		return nil, 0, common.ErrBadRequest("captions could not be parsed: " + err.Error())
+	}
+	if _, err := os.Stat(backup); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(backup, raw, 0o644); err != nil {
+			slog.Error("failed to back up captions", "error", err, "path", backup)
+			return nil, 0, common.ErrInternal("failed to back up captions")
+		}
+	}
+	if err := writeFileAtomic(path, out); err != nil {
+		slog.Error("failed to write shifted captions", "error", err, "path", path)
+		return nil, 0, common.ErrInternal("failed to write captions")
+	}
+	return out, cues, nil
+}
+
+// captionFileLang reads the language from a "<id>.captions.<lang>.vtt" path,
+// falling back to und.
+func captionFileLang(path, videoID string) rewindlang.Tag {
+	name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), videoID+".captions."), ".vtt")
+	tag, err := xtlang.Parse(name)
+	if err != nil {
+		tag = xtlang.Und
+	}
+	return rewindlang.Tag(tag)
+}
+
+// writeFileAtomic replaces path with data via a temp file and rename, so the
+// player never reads a half-written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,70 @@
+package video_api
+
+import (
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	xtlang "golang.org/x/text/language"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/utils/srt"
+)
+
+// HandleCaptionsSRT serves the video captions as SRT, picking the same file
+// as HandleCaptions unless ?lang= names one. The .srt copy written by ingest
+// is served when it is current; otherwise (e.g. after a caption shift) the
+// VTT is converted on the fly.
+func HandleCaptionsSRT(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		lang := ""
+		if raw := strings.TrimSpace(c.QueryParam("lang")); raw != "" {
+			tag, err := xtlang.Parse(raw)
+			if err != nil {
+				return c.String(400, "invalid lang")
+			}
+			lang = strings.ToLower(tag.String())
+		}
+		videoID := videoUUID.String()
+		dir, err := fileserver.GetVideoDirForID(c.Request().Context(), videoID)
+		if err != nil {
+			return err
+		}
+
+		vttPath, ok := captionFilePath(dir, videoID, lang)
+		if !ok {
+			return c.String(404, "captions not available")
+		}
+		vttInfo, err := os.Stat(vttPath)
+		if err != nil {
+			return c.String(404, "captions not available")
+		}
+
+		const cacheControl = "private, max-age=86400, stale-while-revalidate=3600"
+		srtPath := strings.TrimSuffix(vttPath, ".vtt") + ".srt"
+		if srtInfo, err := os.Stat(srtPath); err == nil && !srtInfo.ModTime().Before(vttInfo.ModTime()) {
+			return fs.ServeDiskFileWithCache(c, srtPath, "application/x-subrip", cacheControl, fileserver.ETagStrongSHA256)
+		}
+
+		raw, err := os.ReadFile(vttPath)
+		if err != nil {
+			return common.ErrInternal("failed to read captions")
+		}
+		out, cues := srt.FromVTT(raw)
+		if cues == 0 {
+			return c.String(404, "captions not available")
+		}
+		c.Response().Header().Set("Cache-Control", "private, no-cache")
+		return c.Blob(200, "application/x-subrip; charset=utf-8", out)
+	}
+}
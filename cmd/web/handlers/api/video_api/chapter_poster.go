@@ -0,0 +1,42 @@
+package video_api
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// HandleChapterPoster serves GET /videos/:id/chapters/:index/poster.jpg, the
+// full-resolution frame ingest extracted at the start of chapter :index.
+func HandleChapterPoster(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		index, err := strconv.Atoi(c.Param("index"))
+		if err != nil || index < 0 {
+			return common.ErrBadRequest("invalid chapter index")
+		}
+
+		dir, err := fileserver.GetVideoDirForID(c.Request().Context(), videoUUID.String())
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, "chapters", strconv.Itoa(index)+".jpg")
+		if _, err := os.Stat(path); err != nil {
+			return c.String(404, "chapter poster not available")
+		}
+		return fs.ServeDiskFileWithCache(c, path, "image/jpeg", "private, max-age=86400, stale-while-revalidate=3600", fileserver.ETagStrongSHA256)
+	}
+}
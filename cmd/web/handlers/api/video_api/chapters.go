@@ -0,0 +1,82 @@
+package video_api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/starfederation/datastar-go/datastar"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/cmd/web/templates/components"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// HandleChapters serves GET /videos/:id/chapters, the video's chapters as JSON
+// for the player's timeline ticks.
+func HandleChapters(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		videoRow, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil || videoRow == nil {
+			return c.String(404, "video not found")
+		}
+
+		type ChapterResponse struct {
+			Index int     `json:"index"`
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+			Title string  `json:"title"`
+		}
+
+		chapters := common.VideoChapters(ctx, dbc, videoRow)
+		response := make([]ChapterResponse, len(chapters))
+		for i, ch := range chapters {
+			response[i] = ChapterResponse{Index: i, Start: ch.StartTime, End: ch.EndTime, Title: ch.Title}
+		}
+		return c.JSON(200, response)
+	}
+}
+
+// HandleChaptersRender returns an SSE-patched, server-rendered chapter list.
+func HandleChaptersRender(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return echo.NewHTTPError(401, "unauthorized")
+		}
+
+		sse := datastar.NewSSE(c.Response().Writer, c.Request())
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		videoID := c.Param("id")
+
+		ctx := c.Request().Context()
+		videoRow, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil || videoRow == nil {
+			return nil
+		}
+
+		chapters := common.VideoChapters(ctx, dbc, videoRow)
+		items := make([]components.ChapterItem, len(chapters))
+		for i, ch := range chapters {
+			items[i] = components.ChapterItem{Index: i, Start: ch.StartTime, End: ch.EndTime, Title: ch.Title}
+		}
+
+		sse.PatchElementTempl(
+			components.ChapterList(videoID, items),
+			datastar.WithSelector("[data-chapters-list]"),
+			datastar.WithModeInner(),
+		)
+
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package video_api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+// HandleChaptersVTT serves GET /videos/:id/chapters.vtt, the video's chapters
+// as a WebVTT chapters track for <track kind="chapters">. Videos without
+// chapters get a 404.
+func HandleChaptersVTT(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+
+		var duration float64
+		if video.DurationSeconds != nil {
+			duration = float64(*video.DurationSeconds)
+		}
+		vtt, ok := chaptersVTT(common.VideoChapters(ctx, dbc, video), duration)
+		if !ok {
+			return c.String(404, "chapters not available")
+		}
+		c.Response().Header().Set("Cache-Control", "private, no-cache")
+		return c.Blob(200, "text/vtt; charset=utf-8", []byte(vtt))
+	}
+}
+
+// chapterSpan is a chapter with its end time resolved and its title cleaned
+// for use in a WebVTT cue.
+type chapterSpan struct {
+	start, end float64
+	title      string
+}
+
+// chapterSpans resolves chapters into cue-ready spans. A chapter without an
+// end time runs to the next chapter's start, or to durationSeconds for the
+// last one. Chapters that still have no positive length are skipped.
+func chapterSpans(chapters []videoinfo.Chapter, durationSeconds float64) []chapterSpan {
+	var spans []chapterSpan
+	for i, ch := range chapters {
+		end := ch.EndTime
+		if end <= ch.StartTime {
+			if i+1 < len(chapters) {
+				end = chapters[i+1].StartTime
+			} else {
+				end = durationSeconds
+			}
+		}
+		if ch.StartTime < 0 || end <= ch.StartTime {
+			continue
+		}
+		title := strings.Join(strings.Fields(ch.Title), " ")
+		// "-->" would end the cue's timing line early in some parsers.
+		title = strings.ReplaceAll(title, "-->", "->")
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", i+1)
+		}
+		spans = append(spans, chapterSpan{start: ch.StartTime, end: end, title: title})
+	}
+	return spans
+}
+
+// chaptersVTT renders chapters as a WebVTT chapters track, one cue per
+// chapter span (see chapterSpans). ok is false when no cue is left.
+func chaptersVTT(chapters []videoinfo.Chapter, durationSeconds float64) (vtt string, ok bool) {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	spans := chapterSpans(chapters, durationSeconds)
+	for i, sp := range spans {
+		fmt.Fprintf(&b, "\n%d\n%s --> %s\n%s\n", i+1, vttTimestamp(sp.start), vttTimestamp(sp.end), sp.title)
+	}
+	return b.String(), len(spans) > 0
+}
+
+// vttTimestamp formats seconds as a WebVTT hh:mm:ss.mmm timestamp.
+func vttTimestamp(seconds float64) string {
+	ms := int64(math.Round(seconds * 1000))
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
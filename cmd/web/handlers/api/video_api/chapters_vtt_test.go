@@ -0,0 +1,60 @@
+package video_api
+
+import (
+	"testing"
+
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+func TestChaptersVTT(t *testing.T) {
+	chapters := []videoinfo.Chapter{
+		{StartTime: 0, EndTime: 65.5, Title: "Intro"},
+		{StartTime: 65.5, Title: "  Main\n part --> here "},
+		{StartTime: 3700, Title: ""},
+	}
+	got, ok := chaptersVTT(chapters, 3725)
+	if !ok {
+		t.Fatal("want cues")
+	}
+	want := "WEBVTT\n" +
+		"\n1\n00:00:00.000 --> 00:01:05.500\nIntro\n" +
+		"\n2\n00:01:05.500 --> 01:01:40.000\nMain part -> here\n" +
+		"\n3\n01:01:40.000 --> 01:02:05.000\nChapter 3\n"
+	if got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+
+	// The last chapter has no end and the duration is unknown: it is dropped.
+	if got, _ := chaptersVTT(chapters, 0); got == want {
+		t.Error("open-ended last chapter kept without a duration")
+	}
+
+	if _, ok := chaptersVTT(nil, 100); ok {
+		t.Error("no chapters: want ok=false")
+	}
+}
+
+func TestMergeSeekVTTChapters(t *testing.T) {
+	seek := "WEBVTT\n\nNOTE rewind-seek-v1 interval=10s size=160x90 grid=10x10\n\n" +
+		"00:00:00.000 --> 00:00:10.000\nseek-000.jpg#xywh=0,0,160,90\n\n" +
+		"00:00:10.000 --> 00:00:20.000\nseek-000.jpg#xywh=160,0,160,90\n\n" +
+		"00:00:20.000 --> 00:00:25.000\nseek-000.jpg#xywh=320,0,160,90\n\n"
+	spans := chapterSpans([]videoinfo.Chapter{
+		{StartTime: 0, EndTime: 10, Title: "Intro"},
+		{StartTime: 10, EndTime: 20, Title: "Main"},
+	}, 25)
+	want := "WEBVTT\n\nNOTE rewind-seek-v1 interval=10s size=160x90 grid=10x10\n\n" +
+		"00:00:00.000 --> 00:00:10.000\nseek-000.jpg#xywh=0,0,160,90\nIntro\n\n" +
+		"00:00:10.000 --> 00:00:20.000\nseek-000.jpg#xywh=160,0,160,90\nMain\n\n" +
+		"00:00:20.000 --> 00:00:25.000\nseek-000.jpg#xywh=320,0,160,90\n\n"
+	if got := mergeSeekVTTChapters(seek, spans); got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+
+	if got := parseVTTTimestamp("01:02:03.500"); got != 3723.5 {
+		t.Errorf("parseVTTTimestamp = %v, want 3723.5", got)
+	}
+	if got := parseVTTTimestamp("bogus"); got != -1 {
+		t.Errorf("parseVTTTimestamp(bogus) = %v, want -1", got)
+	}
+}
@@ -53,11 +53,9 @@ func HandleClipsCreate(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo
 			return c.String(400, "invalid json")
 		}
 
-		if req.StartTs < 0 || req.EndTs < 0 {
-			return c.String(400, "start_ts and end_ts must be >= 0")
-		}
-		if req.EndTs <= req.StartTs {
-			return c.String(400, "end_ts must be > start_ts")
+		req.StartTs, req.EndTs, err = clip_api.ClampClipBounds(req.StartTs, req.EndTs, clip_api.VideoDurationSeconds(videoRow))
+		if err != nil {
+			return c.String(400, err.Error())
 		}
 
 		color := strings.TrimSpace(req.Color)
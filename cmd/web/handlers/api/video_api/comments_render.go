@@ -180,6 +180,8 @@ func listCommentsToItems(videoID string, rows []*db.ListVideoCommentsRow) []comp
 			IsPinned:    r.IsPinned,
 			IsUploader:  r.AuthorIsUploader,
 			IsVerified:  r.AuthorIsVerified,
+			IsEdited:    r.IsEdited,
+			IsDeleted:   r.IsDeleted,
 		}
 	}
 	return items
@@ -204,6 +206,8 @@ func searchCommentsToItems(videoID string, rows []*db.SearchVideoCommentsRow) []
 			IsPinned:    r.IsPinned,
 			IsUploader:  r.AuthorIsUploader,
 			IsVerified:  r.AuthorIsVerified,
+			IsEdited:    r.IsEdited,
+			IsDeleted:   r.IsDeleted,
 		}
 	}
 	return items
@@ -226,6 +230,8 @@ func replyCommentsToItems(videoID string, rows []*db.ListVideoCommentRepliesRow)
 			IsPinned:    r.IsPinned,
 			IsUploader:  r.AuthorIsUploader,
 			IsVerified:  r.AuthorIsVerified,
+			IsEdited:    r.IsEdited,
+			IsDeleted:   r.IsDeleted,
 		}
 	}
 	return items
@@ -0,0 +1,53 @@
+package video_api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/utils/commentfmt"
+)
+
+type descriptionTimestampsResponse struct {
+	VideoID    string                            `json:"video_id"`
+	Timestamps []commentfmt.DescriptionTimestamp `json:"timestamps"`
+}
+
+// HandleDescriptionTimestamps serves GET /videos/:id/description/timestamps,
+// the timestamps parsed from the video description (e.g. a chapter list) so
+// the player can show them as markers. Returns an empty list when the
+// description has none.
+func HandleDescriptionTimestamps(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+
+		timestamps := commentfmt.ParseDescriptionTimestamps(video.Description)
+		if timestamps == nil {
+			timestamps = []commentfmt.DescriptionTimestamp{}
+		}
+		return c.JSON(http.StatusOK, descriptionTimestampsResponse{
+			VideoID:    videoUUID.String(),
+			Timestamps: timestamps,
+		})
+	}
+}
@@ -14,8 +14,10 @@ import (
 	"thirdcoast.systems/rewind/internal/db"
 )
 
-// HandleDownload serves the video file for download.
-func HandleDownload(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
+// HandleDownload serves the video file for download. With ?audio=<index> it
+// sends a remux with that audio track as the default, and with &only=true the
+// other audio tracks are dropped.
+func HandleDownload(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer, audioTracks *AudioTrackCache) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if _, _, err := sm.GetSession(c.Request()); err != nil {
 			return c.String(401, "unauthorized")
@@ -45,6 +47,13 @@ func HandleDownload(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fil
 			return c.String(404, "video file not available")
 		}
 
+		if c.QueryParam("audio") != "" {
+			videoPath, err = resolveAudioTrackDownload(c, dbc, audioTracks, videoUUID, videoPath)
+			if err != nil {
+				return err
+			}
+		}
+
 		safeTitle := strings.Map(func(r rune) rune {
 			switch r {
 			case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
@@ -0,0 +1,203 @@
+package video_api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+const (
+	// audioTrackRemuxSlots limits concurrent audio track remuxes.
+	audioTrackRemuxSlots = 2
+	// defaultAudioTrackCacheGB is the AUDIO_TRACK_CACHE_MAX_GB default.
+	defaultAudioTrackCacheGB = 10
+)
+
+// AudioTrackCache holds the remuxes made for downloads with a chosen audio
+// track. They live in AUDIO_TRACK_CACHE_DIR (default rewind-audio-tracks
+// under the system temp directory), outside the archive, and once they
+// together outgrow AUDIO_TRACK_CACHE_MAX_GB the least recently downloaded are
+// removed.
+type AudioTrackCache struct {
+	dir      string
+	maxBytes int64
+	slots    chan struct{}
+}
+
+// NewAudioTrackCache returns the remux cache configured from the
+// environment.
+func NewAudioTrackCache() *AudioTrackCache {
+	dir := strings.TrimSpace(os.Getenv("AUDIO_TRACK_CACHE_DIR"))
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "rewind-audio-tracks")
+	}
+	gb := int64(defaultAudioTrackCacheGB)
+	if v, err := strconv.ParseInt(strings.TrimSpace(os.Getenv("AUDIO_TRACK_CACHE_MAX_GB")), 10, 64); err == nil && v > 0 {
+		gb = v
+	}
+	return &AudioTrackCache{
+		dir:      dir,
+		maxBytes: gb << 30,
+		slots:    make(chan struct{}, audioTrackRemuxSlots),
+	}
+}
+
+// path is where the remux for one audio track choice is cached, e.g.
+// <id>.a1-only.mp4.
+func (c *AudioTrackCache) path(videoPath, videoID string, index int, only bool) string {
+	name := videoID + ".a" + strconv.Itoa(index)
+	if only {
+		name += "-only"
+	}
+	return filepath.Join(c.dir, name+filepath.Ext(videoPath))
+}
+
+// fresh reports whether cached exists and is not older than videoPath. A
+// fresh remux has its mtime bumped, which records the use for eviction.
+func fresh(cached, videoPath string) bool {
+	info, err := os.Stat(cached)
+	if err != nil {
+		return false
+	}
+	src, err := os.Stat(videoPath)
+	if err != nil || info.ModTime().Before(src.ModTime()) {
+		return false
+	}
+	now := time.Now()
+	_ = os.Chtimes(cached, now, now)
+	return true
+}
+
+// evict removes the least recently used remuxes until the cache fits in
+// maxBytes. keep, the remux just written, always stays. Temp files of
+// remuxes still being written are left alone.
+func (c *AudioTrackCache) evict(keep string) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	type cachedRemux struct {
+		path string
+		size int64
+		used time.Time
+	}
+	var remuxes []cachedRemux
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || strings.Contains(e.Name(), ".tmp") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		total += info.Size()
+		remuxes = append(remuxes, cachedRemux{path: filepath.Join(c.dir, e.Name()), size: info.Size(), used: info.ModTime()})
+	}
+	if total <= c.maxBytes {
+		return
+	}
+	sort.Slice(remuxes, func(i, j int) bool { return remuxes[i].used.Before(remuxes[j].used) })
+	for _, r := range remuxes {
+		if total <= c.maxBytes {
+			return
+		}
+		if r.path == keep {
+			continue
+		}
+		if err := os.Remove(r.path); err != nil {
+			slog.Warn("failed to evict audio track remux", "path", r.path, "error", err)
+			continue
+		}
+		total -= r.size
+	}
+}
+
+// resolveAudioTrackDownload returns the file to send for
+// GET /videos/:id/download?audio=<index>[&only=true]: a stream-copy remux
+// with the chosen track as the default (or the only audio track). The track
+// index is validated against the stored probe data. Remuxes are kept in the
+// AudioTrackCache and rebuilt when the source file is newer than the cache.
+// At most audioTrackRemuxSlots remuxes run at once; other requests wait.
+func resolveAudioTrackDownload(c echo.Context, dbc *db.DatabaseConnection, cache *AudioTrackCache, videoUUID pgtype.UUID, videoPath string) (string, error) {
+	index, err := strconv.Atoi(strings.TrimSpace(c.QueryParam("audio")))
+	if err != nil || index < 0 {
+		return "", common.ErrBadRequest("audio must be a track index")
+	}
+	only := c.QueryParam("only") == "true" || c.QueryParam("only") == "1"
+
+	ctx := c.Request().Context()
+	videoID := videoUUID.String()
+	video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", common.ErrNotFound("video not found")
+		}
+		return "", common.ErrInternal("failed to fetch video")
+	}
+	if video.ProbeData == nil {
+		return "", common.ErrBadRequest("audio tracks unknown: video has not been probed")
+	}
+	tracks := len(video.ProbeData.AudioStreams())
+	if index >= tracks {
+		return "", common.ErrBadRequest(fmt.Sprintf("audio track %d out of range (video has %d)", index, tracks))
+	}
+	if tracks == 1 {
+		// Nothing to choose between; the original already has just this track.
+		return videoPath, nil
+	}
+
+	cached := cache.path(videoPath, videoID, index, only)
+	if fresh(cached, videoPath) {
+		return cached, nil
+	}
+
+	select {
+	case cache.slots <- struct{}{}:
+		defer func() { <-cache.slots }()
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	// Another request may have made it while this one waited.
+	if fresh(cached, videoPath) {
+		return cached, nil
+	}
+	if err := os.MkdirAll(cache.dir, 0o755); err != nil {
+		slog.Error("failed to create audio track cache dir", "dir", cache.dir, "error", err)
+		return "", common.ErrInternal("failed to prepare download")
+	}
+
+	// Write to a unique temp file and rename, so concurrent requests for the
+	// same track never see a half-written file.
+	tmp, err := os.CreateTemp(filepath.Dir(cached), filepath.Base(cached)+".*.tmp"+filepath.Ext(cached))
+	if err != nil {
+		slog.Error("failed to create audio track remux file", "video_id", videoID, "error", err)
+		return "", common.ErrInternal("failed to prepare download")
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	if err := ffmpeg.SelectAudioTrack(ctx, videoPath, tmpPath, index, only); err != nil {
+		os.Remove(tmpPath)
+		slog.Error("audio track remux failed", "video_id", videoID, "audio", index, "only", only, "error", err)
+		return "", common.ErrInternal("failed to prepare download")
+	}
+	if err := os.Rename(tmpPath, cached); err != nil {
+		os.Remove(tmpPath)
+		return "", common.ErrInternal("failed to prepare download")
+	}
+	cache.evict(cached)
+	return cached, nil
+}
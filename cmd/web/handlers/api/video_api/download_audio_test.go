@@ -0,0 +1,34 @@
+package video_api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAudioTrackCacheEvict(t *testing.T) {
+	c := &AudioTrackCache{dir: t.TempDir(), maxBytes: 10}
+	write := func(name string, age time.Duration) string {
+		p := filepath.Join(c.dir, name)
+		if err := os.WriteFile(p, make([]byte, 4), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		ts := time.Now().Add(-age)
+		if err := os.Chtimes(p, ts, ts); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	oldest := write("a.a1.mp4", 3*time.Hour)
+	keep := write("b.a1.mp4", 4*time.Hour)
+	recent := write("c.a1.mp4", time.Hour)
+	tmp := write("d.a1.mp4.123.tmp.mp4", 5*time.Hour)
+
+	c.evict(keep)
+	for p, want := range map[string]bool{oldest: false, keep: true, recent: true, tmp: true} {
+		if _, err := os.Stat(p); (err == nil) != want {
+			t.Errorf("%s kept = %v, want %v", filepath.Base(p), err == nil, want)
+		}
+	}
+}
@@ -4,18 +4,28 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 	"thirdcoast.systems/rewind/cmd/web/auth"
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/archival"
 	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+	"thirdcoast.systems/rewind/pkg/ytdlp"
 )
 
-// HandleDownloadFormat creates a download job for a specific yt-dlp format ID.
+// HandleDownloadFormat creates a download job for specific yt-dlp format IDs or
+// for a codec preference.
 // POST /api/videos/:id/download-format
 // Body: {"format_ids": "303,251"} — comma-separated yt-dlp format IDs
+// Body: {"codec": "h264"} — best video+audio restricted to that codec family
+// (h264, hevc, vp9, av1); no fallback to other codecs. vp9 is paired with Opus
+// in WebM, the others with AAC in MP4.
+// The user's download quality cap is applied on top of either selector, and
+// the response's "selector" is the capped one.
 func HandleDownloadFormat(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		userUUID, _, err := common.RequireSessionUser(c, sm)
@@ -30,6 +40,7 @@ func HandleDownloadFormat(sm *auth.SessionManager, dbc *db.DatabaseConnection) e
 
 		type requestBody struct {
 			FormatIDs string `json:"format_ids"`
+			Codec     string `json:"codec"`
 		}
 		var req requestBody
 		if err := c.Bind(&req); err != nil {
@@ -37,14 +48,27 @@ func HandleDownloadFormat(sm *auth.SessionManager, dbc *db.DatabaseConnection) e
 		}
 
 		formatIDs := strings.TrimSpace(req.FormatIDs)
-		if formatIDs == "" {
-			return c.String(400, "format_ids is required")
+		codec := strings.TrimSpace(req.Codec)
+		if formatIDs == "" && codec == "" {
+			return c.String(400, "format_ids or codec is required")
+		}
+		if formatIDs != "" && codec != "" {
+			return c.String(400, "specify either format_ids or codec, not both")
 		}
 
-		// Validate format IDs look reasonable (alphanumeric + commas + plus)
-		for _, ch := range formatIDs {
-			if !((ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == ',' || ch == '+' || ch == '-' || ch == '_') {
-				return c.String(400, "invalid format_ids characters")
+		var preset ytdlp.CodecPreset
+		if codec != "" {
+			var ok bool
+			preset, ok = ytdlp.LookupCodecPreset(codec)
+			if !ok {
+				return c.String(400, "unsupported codec; expected one of: "+strings.Join(ytdlp.CodecPresetNames(), ", "))
+			}
+		} else {
+			// Validate format IDs look reasonable (alphanumeric + commas + plus)
+			for _, ch := range formatIDs {
+				if !((ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == ',' || ch == '+' || ch == '-' || ch == '_') {
+					return c.String(400, "invalid format_ids characters")
+				}
 			}
 		}
 
@@ -57,15 +81,41 @@ func HandleDownloadFormat(sm *auth.SessionManager, dbc *db.DatabaseConnection) e
 			return c.String(500, "failed to fetch video")
 		}
 
-		// Build the yt-dlp format selector: "formatID1+formatID2/best"
-		// This tells yt-dlp to download the specific format(s) requested.
-		formatSelector := fmt.Sprintf("%s/best", formatIDs)
+		var extraArgs []string
+		if codec != "" {
+			// Fail fast when the archived format list proves the source can't
+			// satisfy the preference. Videos without a format list fall through
+			// and the downloader reports yt-dlp's "format not available".
+			if msg := missingCodecMessage(videoRow.Info.Formats, preset); msg != "" {
+				return c.String(422, msg)
+			}
+			extraArgs = preset.DownloadArgs()
+		} else {
+			// Build the yt-dlp format selector: "formatID1+formatID2/best"
+			// This tells yt-dlp to download the specific format(s) requested.
+			extraArgs = []string{"-f", fmt.Sprintf("%s/best", formatIDs)}
+		}
+		if err := ytdlp.ValidateFormatSelector(extraArgs[1]); err != nil {
+			return c.String(400, err.Error())
+		}
+		extraArgs, err = archival.CapDownloadArgs(c.Request().Context(), dbc.Queries(c.Request().Context()), userUUID, extraArgs)
+		if err != nil {
+			if errors.Is(err, archival.ErrQualityCap) {
+				return c.String(400, err.Error())
+			}
+			slog.Error("failed to apply download quality cap", "error", err)
+			return c.String(500, "failed to create download job")
+		}
+
+		if err := archival.CheckDownloadsPaused(); err != nil {
+			return c.String(503, err.Error())
+		}
 
 		job, err := dbc.Queries(c.Request().Context()).EnqueueDownloadJob(c.Request().Context(), &db.EnqueueDownloadJobParams{
 			URL:        videoRow.Src,
 			ArchivedBy: userUUID,
 			Refresh:    false,
-			ExtraArgs:  []string{"-f", formatSelector},
+			ExtraArgs:  extraArgs,
 		})
 		if err != nil {
 			slog.Error("failed to create format download job", "error", err)
@@ -74,12 +124,48 @@ func HandleDownloadFormat(sm *auth.SessionManager, dbc *db.DatabaseConnection) e
 
 		slog.Info("created format download job",
 			"job_id", job.ID, "video_id", videoUUID,
-			"url", videoRow.Src, "format_ids", formatIDs)
+			"url", videoRow.Src, "format_ids", formatIDs, "codec", preset.Name)
 
 		return c.JSON(200, map[string]any{
 			"job_id":     job.ID.String(),
 			"status":     job.Status,
 			"format_ids": formatIDs,
+			"codec":      preset.Name,
+			"selector":   extraArgs[1],
 		})
 	}
 }
+
+// missingCodecMessage returns a user-facing error when formats is non-empty
+// and offers no video or no audio stream matching preset, or "" otherwise.
+func missingCodecMessage(formats []videoinfo.FormatInfo, preset ytdlp.CodecPreset) string {
+	if len(formats) == 0 {
+		return ""
+	}
+	var hasVideo, hasAudio bool
+	offered := map[string]bool{}
+	for _, f := range formats {
+		if preset.MatchesVCodec(f.VCodec) {
+			hasVideo = true
+		}
+		if preset.MatchesACodec(f.ACodec) {
+			hasAudio = true
+		}
+		if v := strings.ToLower(f.VCodec); v != "" && v != "none" {
+			family, _, _ := strings.Cut(v, ".")
+			offered[family] = true
+		}
+	}
+	if hasVideo && hasAudio {
+		return ""
+	}
+	families := make([]string, 0, len(offered))
+	for family := range offered {
+		families = append(families, family)
+	}
+	sort.Strings(families)
+	if !hasVideo {
+		return fmt.Sprintf("source offers no %s video (available: %s)", preset.Name, strings.Join(families, ", "))
+	}
+	return fmt.Sprintf("source offers no audio compatible with %s (%s)", preset.Name, strings.Join(preset.ACodecPrefix, "/"))
+}
@@ -0,0 +1,54 @@
+package video_api
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/starfederation/datastar-go/datastar"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/cmd/web/templates"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+const (
+	// possibleDuplicateDistance is the most bits two perceptual hashes may
+	// differ by for the detail page to suggest the videos are duplicates.
+	// Kept tighter than the admin page's default since it is unprompted.
+	possibleDuplicateDistance = 6
+	maxPossibleDuplicates     = 10
+)
+
+// HandleDuplicatesRender serves GET /api/videos/:id/duplicates/render,
+// patching the "possible duplicates" grid on the detail page via SSE.
+func HandleDuplicatesRender(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		videos, err := dbc.Queries(ctx).FindSimilarVideosByPHash(ctx, &db.FindSimilarVideosByPHashParams{
+			VideoID:     videoUUID,
+			MaxDistance: possibleDuplicateDistance,
+			MaxCount:    maxPossibleDuplicates,
+		})
+		if err != nil {
+			slog.Error("failed to find similar videos", "error", err, "video_id", videoUUID)
+			return common.ErrInternal("failed to find similar videos")
+		}
+
+		common.SetSSEHeaders(c)
+		sse := datastar.NewSSE(c.Response().Writer, c.Request())
+		return sse.PatchElementTempl(
+			templates.PossibleDuplicatesGrid(videos),
+			datastar.WithSelectorID("video-duplicates"),
+			datastar.WithModeReplace(),
+		)
+	}
+}
@@ -0,0 +1,145 @@
+package video_api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+const (
+	// filterPreviewMaxWidth caps preview stills; the filter runs at full
+	// resolution first, so spatial filters preview faithfully.
+	filterPreviewMaxWidth = 1280
+	// filterPreviewTimeout bounds one ffmpeg run.
+	filterPreviewTimeout = 30 * time.Second
+)
+
+// filterPreviewSlots limits concurrent preview renders so slider drags cannot
+// start an unbounded number of ffmpeg processes.
+var filterPreviewSlots = make(chan struct{}, 2)
+
+type filterPreviewRequest struct {
+	Filter ffmpeg.FilterSpec `json:"filter"`
+	// Time is the frame position in seconds from the start of the video.
+	Time float64 `json:"time"`
+	// Format is "jpeg" (default) or "png".
+	Format string `json:"format"`
+}
+
+// HandleFilterPreview serves POST /api/videos/:id/filter-preview, rendering
+// the frame at a timestamp with a single video filter applied and returning
+// it as a still image. Audio filters and filters that only act over time are
+// rejected.
+func HandleFilterPreview(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		videoID := videoUUID.String()
+
+		var req filterPreviewRequest
+		if err := c.Bind(&req); err != nil {
+			return common.ErrBadRequest("invalid json")
+		}
+		if req.Filter.Type == "" {
+			return common.ErrBadRequest("filter.type is required")
+		}
+		if math.IsNaN(req.Time) || math.IsInf(req.Time, 0) || req.Time < 0 {
+			return common.ErrBadRequest("time must be a non-negative number of seconds")
+		}
+		ext, contentType := ".jpg", "image/jpeg"
+		switch req.Format {
+		case "", "jpeg", "jpg":
+		case "png":
+			ext, contentType = ".png", "image/png"
+		default:
+			return common.ErrBadRequest("format must be jpeg or png")
+		}
+
+		chain, err := ffmpeg.CompileStillFilter(ffmpeg.StripInjectedParams([]ffmpeg.FilterSpec{req.Filter})[0])
+		if err != nil {
+			return common.ErrBadRequest(err.Error())
+		}
+
+		ctx := c.Request().Context()
+		video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+		if video.ProbeData != nil && video.ProbeData.IsAudioOnly() {
+			return common.ErrBadRequest("video has no picture to preview")
+		}
+		if video.DurationSeconds != nil && *video.DurationSeconds > 0 && req.Time >= float64(*video.DurationSeconds) {
+			return common.ErrBadRequest("time is past the end of the video")
+		}
+
+		dir, err := fileserver.GetVideoDirForID(ctx, videoID)
+		if err != nil {
+			return err
+		}
+		var videoPath string
+		for _, e := range VideoExtensions {
+			p := filepath.Join(dir, videoID+".video"+e)
+			if _, err := os.Stat(p); err == nil {
+				videoPath = p
+				break
+			}
+		}
+		if videoPath == "" {
+			return common.ErrNotFound("video file not available")
+		}
+
+		select {
+		case filterPreviewSlots <- struct{}{}:
+			defer func() { <-filterPreviewSlots }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		tmp, err := os.CreateTemp("", "rewind-filter-preview-*"+ext)
+		if err != nil {
+			return common.ErrInternal("failed to create preview")
+		}
+		out := tmp.Name()
+		tmp.Close()
+		defer os.Remove(out)
+
+		runCtx, cancel := context.WithTimeout(ctx, filterPreviewTimeout)
+		defer cancel()
+		offset := time.Duration(req.Time * float64(time.Second))
+		if res := ffmpeg.ExtractFilteredFrame(runCtx, videoPath, out, offset, chain, filterPreviewMaxWidth); res.Err != nil {
+			slog.Error("filter preview failed", "video_id", videoID, "filter", req.Filter.Type, "error", res.Err, "logs", res.Logs)
+			return common.ErrInternal("failed to render preview")
+		}
+		data, err := os.ReadFile(out)
+		if err != nil || len(data) == 0 {
+			// ffmpeg exits cleanly without a frame when seeking past the
+			// last one.
+			return common.ErrBadRequest("no frame at that time")
+		}
+
+		c.Response().Header().Set("Cache-Control", "no-store")
+		return c.Blob(http.StatusOK, contentType, data)
+	}
+}
@@ -0,0 +1,118 @@
+package video_api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+// sourceFormat is one row of the source format table. Zero-valued numbers
+// mean the source did not report them.
+type sourceFormat struct {
+	FormatID       string  `json:"format_id"`
+	Note           string  `json:"note,omitempty"`
+	Ext            string  `json:"ext"`
+	Kind           string  `json:"kind"`
+	VCodec         string  `json:"vcodec,omitempty"`
+	ACodec         string  `json:"acodec,omitempty"`
+	Width          int     `json:"width,omitempty"`
+	Height         int     `json:"height,omitempty"`
+	Resolution     string  `json:"resolution,omitempty"`
+	FPS            float64 `json:"fps,omitempty"`
+	DynamicRange   string  `json:"dynamic_range,omitempty"`
+	TBR            float64 `json:"tbr,omitempty"`
+	VBR            float64 `json:"vbr,omitempty"`
+	ABR            float64 `json:"abr,omitempty"`
+	AudioChannels  int     `json:"audio_channels,omitempty"`
+	SampleRate     int     `json:"sample_rate,omitempty"`
+	Language       string  `json:"language,omitempty"`
+	Protocol       string  `json:"protocol,omitempty"`
+	Filesize       int64   `json:"filesize,omitempty"`
+	FilesizeApprox bool    `json:"filesize_approx,omitempty"`
+}
+
+type sourceFormatsResponse struct {
+	VideoID string         `json:"video_id"`
+	Formats []sourceFormat `json:"formats"`
+}
+
+// newSourceFormat maps a yt-dlp format entry to its API row, dropping the
+// "none" codec placeholders.
+func newSourceFormat(f videoinfo.FormatInfo) sourceFormat {
+	codec := func(s string) string {
+		if s = strings.TrimSpace(s); s == "none" {
+			return ""
+		}
+		return s
+	}
+	row := sourceFormat{
+		FormatID:      f.FormatID,
+		Note:          strings.TrimSpace(f.FormatNote),
+		Ext:           f.Ext,
+		Kind:          f.Kind(),
+		VCodec:        codec(f.VCodec),
+		ACodec:        codec(f.ACodec),
+		Width:         int(f.Width),
+		Height:        int(f.Height),
+		Resolution:    strings.TrimSpace(f.Resolution),
+		FPS:           f.FPS,
+		DynamicRange:  strings.TrimSpace(f.DynamicRange),
+		TBR:           f.TBR,
+		VBR:           f.VBR,
+		ABR:           f.ABR,
+		AudioChannels: int(f.AudioCh),
+		SampleRate:    int(f.ASR),
+		Language:      strings.TrimSpace(f.Language),
+		Protocol:      f.Protocol,
+		Filesize:      int64(f.Filesize),
+	}
+	if row.Filesize == 0 && f.FilesizeApprox > 0 {
+		row.Filesize = int64(f.FilesizeApprox)
+		row.FilesizeApprox = true
+	}
+	return row
+}
+
+// HandleFormats serves GET /videos/:id/formats, the full table of formats
+// the source offered at archive time. Format IDs can be passed to
+// POST /videos/:id/download-format. Videos whose metadata has no formats
+// (uploads, some extractors) return an empty list.
+func HandleFormats(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			slog.Error("failed to fetch video for formats", "error", err, "video_id", videoUUID)
+			return common.ErrInternal("failed to fetch video")
+		}
+
+		resp := sourceFormatsResponse{
+			VideoID: videoUUID.String(),
+			Formats: make([]sourceFormat, 0, len(video.Info.Formats)),
+		}
+		for _, f := range video.Info.Formats {
+			resp.Formats = append(resp.Formats, newSourceFormat(f))
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,382 @@
+package video_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/hls"
+)
+
+const (
+	// hlsSegmentSeconds is the target segment length of a rendition.
+	hlsSegmentSeconds = 6
+	// hlsRemuxTimeout bounds one remux; a stream copy of a long video is
+	// still bound by disk speed.
+	hlsRemuxTimeout = 30 * time.Minute
+	// hlsFailureTTL is how long a failed remux is remembered before the next
+	// request tries again.
+	hlsFailureTTL = time.Hour
+	// hlsRemuxSlots limits concurrent remuxes; a playlist request can start
+	// several.
+	hlsRemuxSlots = 2
+	// defaultHLSCacheGB is the HLS_CACHE_MAX_GB default.
+	defaultHLSCacheGB = 20
+	// HLSPinTTL is how long a rendition stays pinned after its last Pin.
+	HLSPinTTL = time.Hour
+)
+
+var (
+	// ErrHLSUnavailable is returned by HLSCache.Video for videos with no file
+	// on disk or whose remux recently failed.
+	ErrHLSUnavailable = errors.New("hls rendition unavailable")
+	// ErrHLSDisabled is returned by HLSCache.Video when HLS_CACHE_MAX_GB is 0.
+	ErrHLSDisabled = errors.New("hls renditions disabled")
+)
+
+// hlsFileRe matches the files SegmentHLS writes.
+var hlsFileRe = regexp.MustCompile(`^(index\.m3u8|init\.mp4|seg_\d{5,}\.m4s)$`)
+
+// hlsDir is the directory holding a video's HLS rendition.
+func hlsDir(dir, videoID string) string {
+	return filepath.Join(dir, videoID+".hls")
+}
+
+// HLSCache remuxes videos into HLS renditions on first use and keeps them
+// next to the video under <id>.hls/. Once the renditions together outgrow
+// HLS_CACHE_MAX_GB (default 20), the least recently played are removed,
+// except those pinned by a playlist that players may still be reading.
+type HLSCache struct {
+	dbc      *db.DatabaseConnection
+	keys     *HLSKeys
+	maxBytes int64
+	slots    chan struct{}
+
+	mu      sync.Mutex
+	running map[string]chan struct{}
+	failed  map[string]time.Time
+	pins    map[string]time.Time
+}
+
+// NewHLSCache returns the rendition cache. HLS_CACHE_MAX_GB=0 turns
+// renditions off.
+func NewHLSCache(dbc *db.DatabaseConnection, keys *HLSKeys) *HLSCache {
+	gb := int64(defaultHLSCacheGB)
+	if v, err := strconv.ParseInt(strings.TrimSpace(os.Getenv("HLS_CACHE_MAX_GB")), 10, 64); err == nil && v >= 0 {
+		gb = v
+	}
+	return &HLSCache{
+		dbc:      dbc,
+		keys:     keys,
+		maxBytes: gb << 30,
+		slots:    make(chan struct{}, hlsRemuxSlots),
+		running:  map[string]chan struct{}{},
+		failed:   map[string]time.Time{},
+		pins:     map[string]time.Time{},
+	}
+}
+
+// Enabled reports whether videos are remuxed into renditions.
+func (c *HLSCache) Enabled() bool {
+	return c != nil && c.maxBytes > 0
+}
+
+// Video returns the media playlist of a video's HLS rendition, AES-128
+// encrypted when the cache's keys are enabled. When there is no rendition,
+// the video file is newer than it, or its encryption does not match, Video
+// starts a remux in the background and returns a nil playlist with a channel
+// that closes when the remux ends.
+func (c *HLSCache) Video(ctx context.Context, videoID string) (*hls.MediaPlaylist, <-chan struct{}, error) {
+	if !c.Enabled() {
+		return nil, nil, ErrHLSDisabled
+	}
+	dir, err := fileserver.GetVideoDirForID(ctx, videoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var src string
+	var srcInfo os.FileInfo
+	for _, ext := range VideoExtensions {
+		p := filepath.Join(dir, videoID+".video"+ext)
+		if info, err := os.Stat(p); err == nil {
+			src, srcInfo = p, info
+			break
+		}
+	}
+	if src == "" {
+		return nil, nil, ErrHLSUnavailable
+	}
+
+	out := hlsDir(dir, videoID)
+	index := filepath.Join(out, ffmpeg.HLSIndex)
+	if info, err := os.Stat(index); err == nil && !info.ModTime().Before(srcInfo.ModTime()) {
+		if p, err := readHLSIndex(index); err == nil && (p.Key != nil) == c.keys.Enabled() {
+			// The directory's mtime records the last use for eviction.
+			now := time.Now()
+			_ = os.Chtimes(out, now, now)
+			return p, nil, nil
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if done, ok := c.running[videoID]; ok {
+		return nil, done, nil
+	}
+	if t, ok := c.failed[videoID]; ok {
+		if time.Since(t) < hlsFailureTTL {
+			return nil, nil, ErrHLSUnavailable
+		}
+		delete(c.failed, videoID)
+	}
+	done := make(chan struct{})
+	c.running[videoID] = done
+	go func() {
+		err := c.remux(videoID, src, dir)
+		c.mu.Lock()
+		delete(c.running, videoID)
+		if err != nil {
+			c.forgetExpiredFailures()
+			c.failed[videoID] = time.Now()
+		}
+		c.mu.Unlock()
+		close(done)
+	}()
+	return nil, done, nil
+}
+
+// forgetExpiredFailures drops failures older than hlsFailureTTL, so the map
+// only holds the last hour's. c.mu must be held.
+func (c *HLSCache) forgetExpiredFailures() {
+	for id, t := range c.failed {
+		if time.Since(t) >= hlsFailureTTL {
+			delete(c.failed, id)
+		}
+	}
+}
+
+// Pin keeps the videos' renditions out of eviction for HLSPinTTL, for
+// playlists that reference their segments.
+func (c *HLSCache) Pin(videoIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for id, t := range c.pins {
+		if now.Sub(t) >= HLSPinTTL {
+			delete(c.pins, id)
+		}
+	}
+	for _, id := range videoIDs {
+		c.pins[id] = now
+	}
+}
+
+// pinned reports whether a Pin for videoID is still in effect.
+func (c *HLSCache) pinned(videoID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.pins[videoID]
+	return ok && time.Since(t) < HLSPinTTL
+}
+
+// readHLSIndex parses a rendition's playlist, rejecting unfinished ones.
+func readHLSIndex(path string) (*hls.MediaPlaylist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := hls.ParseMedia(f)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Ended || len(p.Segments) == 0 {
+		return nil, fmt.Errorf("incomplete rendition %s", path)
+	}
+	return p, nil
+}
+
+// remux writes the rendition to a temp directory and swaps it into place, so
+// readers never see a partial one. It holds the video's asset lock while
+// writing, as ingest does, then evicts old renditions if the cache is full.
+func (c *HLSCache) remux(videoID, src, dir string) error {
+	c.slots <- struct{}{}
+	defer func() { <-c.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), hlsRemuxTimeout)
+	defer cancel()
+	err := c.dbc.WithVideoAssetLock(ctx, videoID, func() error {
+		return c.writeRendition(ctx, videoID, src, dir)
+	})
+	if err != nil {
+		return err
+	}
+	c.evict(ctx, filepath.Dir(dir), videoID)
+	return nil
+}
+
+func (c *HLSCache) writeRendition(ctx context.Context, videoID, src, dir string) error {
+	out := hlsDir(dir, videoID)
+	tmp := out + ".tmp"
+	_ = os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		slog.Error("failed to create hls dir", "video_id", videoID, "error", err)
+		return err
+	}
+
+	var keyInfo string
+	if c.keys.Enabled() {
+		var err error
+		if keyInfo, err = c.keys.writeKeyInfo(ctx, videoID); err != nil {
+			slog.Error("failed to prepare hls key", "video_id", videoID, "error", err)
+			_ = os.RemoveAll(tmp)
+			return err
+		}
+		defer os.RemoveAll(filepath.Dir(keyInfo))
+	}
+
+	started := time.Now()
+	if res := ffmpeg.SegmentHLS(ctx, src, tmp, hlsSegmentSeconds, keyInfo); res.Err != nil {
+		slog.Error("hls remux failed", "video_id", videoID, "error", res.Err, "logs", res.Logs)
+		_ = os.RemoveAll(tmp)
+		return res.Err
+	}
+	if _, err := readHLSIndex(filepath.Join(tmp, ffmpeg.HLSIndex)); err != nil {
+		slog.Error("hls remux produced no playable rendition", "video_id", videoID, "error", err)
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	_ = os.RemoveAll(out)
+	if err := os.Rename(tmp, out); err != nil {
+		slog.Error("failed to store hls rendition", "video_id", videoID, "error", err)
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	slog.Info("hls rendition ready", "video_id", videoID, "elapsed", time.Since(started))
+	return nil
+}
+
+// hlsRendition is one cached rendition found by evict.
+type hlsRendition struct {
+	videoID string
+	path    string
+	size    int64
+	used    time.Time
+}
+
+// evict removes the least recently played renditions under root until the
+// cache fits in maxBytes. keep, the rendition just written, and pinned
+// renditions always stay. Renditions whose video is locked by another writer
+// are left for next time.
+func (c *HLSCache) evict(ctx context.Context, root, keep string) {
+	paths, err := filepath.Glob(filepath.Join(root, "*", "*.hls"))
+	if err != nil {
+		return
+	}
+	var renditions []hlsRendition
+	var total int64
+	for _, p := range paths {
+		videoID := filepath.Base(filepath.Dir(p))
+		if filepath.Base(p) != videoID+".hls" {
+			continue
+		}
+		info, err := os.Stat(p)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		size := dirSize(p)
+		total += size
+		renditions = append(renditions, hlsRendition{videoID: videoID, path: p, size: size, used: info.ModTime()})
+	}
+	if total <= c.maxBytes {
+		return
+	}
+	sort.Slice(renditions, func(i, j int) bool { return renditions[i].used.Before(renditions[j].used) })
+	for _, r := range renditions {
+		if total <= c.maxBytes {
+			return
+		}
+		if r.videoID == keep || c.pinned(r.videoID) {
+			continue
+		}
+		release, ok, err := c.dbc.LockVideoAssets(ctx, r.videoID, false)
+		if err != nil || !ok {
+			release()
+			continue
+		}
+		err = os.RemoveAll(r.path)
+		release()
+		if err != nil {
+			slog.Warn("failed to evict hls rendition", "video_id", r.videoID, "error", err)
+			continue
+		}
+		total -= r.size
+		slog.Info("evicted hls rendition", "video_id", r.videoID, "bytes", r.size)
+	}
+}
+
+// dirSize sums the sizes of the files under dir.
+func dirSize(dir string) int64 {
+	var n int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			n += info.Size()
+		}
+		return nil
+	})
+	return n
+}
+
+// HandleHLSFile serves GET /api/videos/:id/hls/:file, a file of the video's
+// HLS rendition: index.m3u8, init.mp4 or a seg_NNNNN.m4s segment. Renditions
+// are created by HLSCache.Video; this handler only serves them.
+func HandleHLSFile(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		name := c.Param("file")
+		if !hlsFileRe.MatchString(name) {
+			return common.ErrNotFound("file not found")
+		}
+		videoID := videoUUID.String()
+		dir, err := fileserver.GetVideoDirForID(c.Request().Context(), videoID)
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(hlsDir(dir, videoID), name)
+		switch {
+		case name == ffmpeg.HLSIndex:
+			return fs.ServeDiskFileWithCache(c, path, "application/vnd.apple.mpegurl", "private, no-cache", fileserver.ETagWeakStat)
+		case strings.HasSuffix(name, ".m4s"):
+			return fs.ServeDiskFileWithCache(c, path, "video/iso.segment", "private, max-age=86400", fileserver.ETagWeakStat)
+		default:
+			return fs.ServeDiskFileWithCache(c, path, "video/mp4", "private, max-age=86400", fileserver.ETagWeakStat)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package video_api
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/encryption"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// hlsKeySize is the length of an AES-128 key and IV.
+const hlsKeySize = 16
+
+// HLSKeys issues and looks up the per-video keys of AES-128 encrypted HLS
+// renditions. Keys are stored in video_hls_keys, encrypted with the instance
+// key, and handed to players only through HandleHLSKey.
+type HLSKeys struct {
+	dbc     *db.DatabaseConnection
+	encMgr  *encryption.Manager
+	encrypt bool
+}
+
+// NewHLSKeys returns the key store. New renditions are encrypted when
+// HLS_ENCRYPTION is set; keys already issued stay servable either way, so
+// cached encrypted renditions keep playing until they are remuxed.
+func NewHLSKeys(dbc *db.DatabaseConnection, encMgr *encryption.Manager) *HLSKeys {
+	v := strings.TrimSpace(os.Getenv("HLS_ENCRYPTION"))
+	return &HLSKeys{
+		dbc:     dbc,
+		encMgr:  encMgr,
+		encrypt: v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes"),
+	}
+}
+
+// Enabled reports whether new renditions are encrypted. A nil *HLSKeys
+// never encrypts.
+func (k *HLSKeys) Enabled() bool {
+	return k != nil && k.encrypt
+}
+
+// hlsKeyURI is the key URI written into a video's playlist.
+func hlsKeyURI(videoID string) string {
+	return "/api/videos/" + videoID + "/hls/key"
+}
+
+// writeKeyInfo writes the video's key, issuing one on first use, and an
+// ffmpeg key info file with a fresh IV to a new temp directory, away from
+// the served video directory. It returns the key info path; the caller
+// removes its directory once the remux ends.
+func (k *HLSKeys) writeKeyInfo(ctx context.Context, videoID string) (string, error) {
+	var id pgtype.UUID
+	if err := id.Scan(videoID); err != nil {
+		return "", fmt.Errorf("invalid video id: %w", err)
+	}
+
+	key := make([]byte, hlsKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generate hls key: %w", err)
+	}
+	encKey, err := encryption.Encrypt(k.encMgr, key)
+	if err != nil {
+		return "", fmt.Errorf("encrypt hls key: %w", err)
+	}
+	stored, err := k.dbc.Queries(ctx).EnsureVideoHLSKey(ctx, &db.EnsureVideoHLSKeyParams{
+		VideoID: id,
+		Key:     encKey,
+	})
+	if err != nil {
+		return "", fmt.Errorf("store hls key: %w", err)
+	}
+	// Another remux may have issued the key first; use the stored one.
+	key, err = encryption.DecryptValue(k.encMgr, &stored)
+	if err != nil {
+		return "", fmt.Errorf("decrypt hls key: %w", err)
+	}
+
+	iv := make([]byte, hlsKeySize)
+	if _, err := rand.Read(iv); err != nil {
+		return "", fmt.Errorf("generate hls iv: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp("", "rewind-hls-key-")
+	if err != nil {
+		return "", err
+	}
+	keyFile := filepath.Join(tmp, "key.bin")
+	keyInfo := filepath.Join(tmp, "key.info")
+	if err := os.WriteFile(keyFile, key, 0o600); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+	if err := ffmpeg.WriteHLSKeyInfo(keyInfo, hlsKeyURI(videoID), keyFile, iv); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+	return keyInfo, nil
+}
+
+// HandleHLSKey serves GET /api/videos/:id/hls/key, the AES-128 key of the
+// video's encrypted HLS rendition. Like the segments it unlocks, it needs a
+// logged-in session, and it is never cached.
+func HandleHLSKey(sm *auth.SessionManager, keys *HLSKeys) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		stored, err := keys.dbc.Queries(ctx).GetVideoHLSKey(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("key not found")
+			}
+			slog.Error("failed to load hls key", "video_id", videoUUID.String(), "error", err)
+			return common.ErrInternal("failed to load key")
+		}
+		key, err := encryption.DecryptValue(keys.encMgr, &stored)
+		if err != nil {
+			slog.Error("failed to decrypt hls key", "video_id", videoUUID.String(), "error", err)
+			return common.ErrInternal("failed to load key")
+		}
+
+		c.Response().Header().Set("Cache-Control", "private, no-store")
+		return c.Blob(200, "application/octet-stream", key)
+	}
+}
@@ -0,0 +1,299 @@
+package video_api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/clip_api"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+	"thirdcoast.systems/rewind/pkg/filters"
+	"thirdcoast.systems/rewind/pkg/utils/crops"
+	"thirdcoast.systems/rewind/pkg/utils/edl"
+)
+
+const (
+	// maxImportEditsBytes bounds the request body of an edits import.
+	maxImportEditsBytes = 4 << 20
+	maxImportClips      = 500
+	maxImportMarkers    = 2000
+	// defaultMarkerColor matches the color HandleMarkersUpdate assigns.
+	defaultMarkerColor = "#3b82f6"
+)
+
+// importedClip is a clip from an edits document, checked against the target
+// video and with its crops given new IDs.
+type importedClip struct {
+	Start       float64
+	End         float64
+	Title       string
+	Description string
+	Crops       crops.CropArray
+	// FilterStack is the JSON filter stack, nil when the clip has none.
+	FilterStack []byte
+}
+
+// HandleImportEdits serves POST /api/videos/:id/import-edits, creating the
+// clips (with their crops and filter stacks) and markers of an edits document
+// on the video in one transaction. The body is an edl.Edits document or a
+// single clip as served by GET /api/clips/:id/edl. Everything is validated
+// before anything is written, so a bad entry imports nothing.
+func HandleImportEdits(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxImportEditsBytes+1))
+		if err != nil {
+			return common.ErrBadRequest("failed to read body")
+		}
+		if len(body) > maxImportEditsBytes {
+			return common.ErrBadRequest(fmt.Sprintf("edits must be at most %d MB", maxImportEditsBytes>>20))
+		}
+		edits, err := edl.ParseEdits(body)
+		if err != nil {
+			return common.ErrBadRequest(err.Error())
+		}
+		if len(edits.Clips) == 0 && len(edits.Markers) == 0 {
+			return common.ErrBadRequest("no clips or markers to import")
+		}
+		if len(edits.Clips) > maxImportClips {
+			return common.ErrBadRequest(fmt.Sprintf("at most %d clips can be imported at once", maxImportClips))
+		}
+		if len(edits.Markers) > maxImportMarkers {
+			return common.ErrBadRequest(fmt.Sprintf("at most %d markers can be imported at once", maxImportMarkers))
+		}
+
+		ctx := c.Request().Context()
+		videoRow, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil || videoRow == nil {
+			return common.ErrNotFound("video not found")
+		}
+		videoDuration := clip_api.VideoDurationSeconds(videoRow)
+
+		clips := make([]importedClip, 0, len(edits.Clips))
+		for i, ec := range edits.Clips {
+			ic, err := prepareImportedClip(ec, videoDuration)
+			if err != nil {
+				return common.ErrBadRequest(fmt.Sprintf("clips[%d]: %v", i, err))
+			}
+			clips = append(clips, ic)
+		}
+		markers := make([]db.CreateMarkerParams, 0, len(edits.Markers))
+		for i, em := range edits.Markers {
+			m, err := prepareImportedMarker(em, videoDuration)
+			if err != nil {
+				return common.ErrBadRequest(fmt.Sprintf("markers[%d]: %v", i, err))
+			}
+			m.VideoID = videoUUID
+			m.CreatedBy = userUUID
+			markers = append(markers, m)
+		}
+
+		tx, err := dbc.Begin(ctx)
+		if err != nil {
+			slog.Error("import edits: failed to begin transaction", "error", err)
+			return common.ErrInternal("failed to import edits")
+		}
+		defer tx.Rollback(ctx)
+		qtx := dbc.Queries(ctx).WithTx(tx)
+
+		clipIDs := make([]string, 0, len(clips))
+		for _, ic := range clips {
+			created, err := qtx.CreateClip(ctx, &db.CreateClipParams{
+				VideoID:     videoUUID,
+				StartTs:     ic.Start,
+				EndTs:       ic.End,
+				Duration:    ic.End - ic.Start,
+				Title:       ic.Title,
+				Description: ic.Description,
+				Color:       randomClipColor(),
+				Tags:        []byte("[]"),
+				CreatedBy:   userUUID,
+			})
+			if err != nil {
+				slog.Error("import edits: failed to create clip", "video_id", videoUUID.String(), "error", err)
+				return common.ErrInternal("failed to import edits")
+			}
+			if len(ic.Crops) > 0 {
+				if err := qtx.UpdateClipCrops(ctx, &db.UpdateClipCropsParams{ID: created.ID, Crops: ic.Crops}); err != nil {
+					slog.Error("import edits: failed to set clip crops", "clip_id", created.ID.String(), "error", err)
+					return common.ErrInternal("failed to import edits")
+				}
+			}
+			if ic.FilterStack != nil {
+				if err := qtx.UpdateClipFilterStack(ctx, &db.UpdateClipFilterStackParams{ID: created.ID, FilterStack: ic.FilterStack}); err != nil {
+					slog.Error("import edits: failed to set clip filters", "clip_id", created.ID.String(), "error", err)
+					return common.ErrInternal("failed to import edits")
+				}
+			}
+			clipIDs = append(clipIDs, created.ID.String())
+		}
+
+		markerIDs := make([]string, 0, len(markers))
+		for i := range markers {
+			created, err := qtx.CreateMarker(ctx, &markers[i])
+			if err != nil {
+				slog.Error("import edits: failed to create marker", "video_id", videoUUID.String(), "error", err)
+				return common.ErrInternal("failed to import edits")
+			}
+			markerIDs = append(markerIDs, created.ID.String())
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			slog.Error("import edits: failed to commit", "video_id", videoUUID.String(), "error", err)
+			return common.ErrInternal("failed to import edits")
+		}
+
+		return c.JSON(http.StatusOK, map[string]any{"clips": clipIDs, "markers": markerIDs})
+	}
+}
+
+// prepareImportedClip validates an imported clip against a video of length
+// videoDuration (0 = unknown). Crops get new IDs, crop filters are pointed at
+// them, and the filter stack must compile, so unknown filter types and bad
+// params are rejected up front rather than at export time.
+func prepareImportedClip(c edl.Clip, videoDuration float64) (importedClip, error) {
+	start, end, err := clip_api.ClampClipBounds(c.In, c.Out, videoDuration)
+	if err != nil {
+		return importedClip{}, err
+	}
+	ic := importedClip{
+		Start:       start,
+		End:         end,
+		Title:       strings.TrimSpace(c.Title),
+		Description: c.Description,
+	}
+
+	cropIDs := make(map[string]string, len(c.Crops))
+	for i, cr := range c.Crops {
+		if err := validateImportedCrop(cr); err != nil {
+			return importedClip{}, fmt.Errorf("crops[%d]: %w", i, err)
+		}
+		if cr.ID != "" {
+			if _, dup := cropIDs[cr.ID]; dup {
+				return importedClip{}, fmt.Errorf("crops[%d]: duplicate id %q", i, cr.ID)
+			}
+		}
+		newID := uuid.New().String()
+		if cr.ID != "" {
+			cropIDs[cr.ID] = newID
+		}
+		cr.ID = newID
+		ic.Crops = append(ic.Crops, cr)
+	}
+
+	if len(c.Filters) == 0 {
+		return ic, nil
+	}
+	stack := make([]filters.FilterStackEntry, len(c.Filters))
+	specs := make([]ffmpeg.FilterSpec, len(c.Filters))
+	for i, f := range c.Filters {
+		params := make(map[string]any, len(f.Params))
+		for k, v := range f.Params {
+			// "_" params are set by the encoder, never imported.
+			if !strings.HasPrefix(k, "_") {
+				params[k] = v
+			}
+		}
+		if f.Type == "crop" {
+			oldID, _ := params["crop_id"].(string)
+			newID, ok := cropIDs[oldID]
+			if !ok {
+				return importedClip{}, fmt.Errorf("filter[%d] (crop): crop_id %q is not one of the clip's crops", i, oldID)
+			}
+			params["crop_id"] = newID
+		}
+		stack[i] = filters.FilterStackEntry{Type: f.Type, Params: params}
+		specs[i] = ffmpeg.FilterSpec{Type: f.Type, Params: params}
+	}
+	if _, err := ffmpeg.CompileFilters(ffmpeg.WithClipDuration(specs, end-start), ic.Crops); err != nil {
+		return importedClip{}, err
+	}
+	ic.FilterStack, err = json.Marshal(stack)
+	if err != nil {
+		return importedClip{}, err
+	}
+	return ic, nil
+}
+
+// cropEdgeTolerance absorbs rounding in crops whose edge sits on the frame
+// edge, e.g. a center of 0.84 with a width of 0.32.
+const cropEdgeTolerance = 1e-6
+
+// validateImportedCrop checks that a crop lies within the frame. Coordinates
+// are fractions of the source frame; X and Y are the crop's center.
+func validateImportedCrop(cr crops.Crop) error {
+	for _, v := range []float64{cr.X, cr.Y, cr.Width, cr.Height} {
+		if math.IsNaN(v) || v < 0 || v > 1 {
+			return fmt.Errorf("x, y, width and height must be between 0 and 1")
+		}
+	}
+	if cr.Width == 0 || cr.Height == 0 {
+		return fmt.Errorf("width and height must be greater than 0")
+	}
+	if cr.X-cr.Width/2 < -cropEdgeTolerance || cr.X+cr.Width/2 > 1+cropEdgeTolerance {
+		return fmt.Errorf("crop extends past the left or right edge of the frame")
+	}
+	if cr.Y-cr.Height/2 < -cropEdgeTolerance || cr.Y+cr.Height/2 > 1+cropEdgeTolerance {
+		return fmt.Errorf("crop extends past the top or bottom edge of the frame")
+	}
+	return nil
+}
+
+// prepareImportedMarker validates an imported marker against a video of
+// length videoDuration (0 = unknown) and fills in the default color and type.
+// VideoID and CreatedBy are left for the caller.
+func prepareImportedMarker(m edl.Marker, videoDuration float64) (db.CreateMarkerParams, error) {
+	if math.IsNaN(m.Timestamp) || m.Timestamp < 0 {
+		return db.CreateMarkerParams{}, fmt.Errorf("timestamp must be >= 0")
+	}
+	if videoDuration > 0 && m.Timestamp > videoDuration {
+		return db.CreateMarkerParams{}, fmt.Errorf("timestamp %.2fs is past the end of the video (%.2fs)", m.Timestamp, videoDuration)
+	}
+	if m.Duration != nil {
+		if math.IsNaN(*m.Duration) || *m.Duration < 0 {
+			return db.CreateMarkerParams{}, fmt.Errorf("duration must be >= 0")
+		}
+		if videoDuration > 0 && m.Timestamp+*m.Duration > videoDuration {
+			return db.CreateMarkerParams{}, fmt.Errorf("marker runs past the end of the video (%.2fs)", videoDuration)
+		}
+	}
+
+	markerType := strings.TrimSpace(m.MarkerType)
+	if markerType == "" {
+		markerType = "point"
+	}
+	if markerType != "point" && markerType != "chapter" {
+		return db.CreateMarkerParams{}, fmt.Errorf("invalid marker_type %q", m.MarkerType)
+	}
+	color := strings.TrimSpace(m.Color)
+	if color == "" {
+		color = defaultMarkerColor
+	}
+	return db.CreateMarkerParams{
+		Timestamp:   m.Timestamp,
+		Title:       strings.TrimSpace(m.Title),
+		Description: m.Description,
+		Color:       color,
+		MarkerType:  db.MarkerType(markerType),
+		Duration:    m.Duration,
+		Source:      db.MarkerSourceUser,
+	}, nil
+}
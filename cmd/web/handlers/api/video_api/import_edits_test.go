@@ -0,0 +1,86 @@
+package video_api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"thirdcoast.systems/rewind/pkg/filters"
+	"thirdcoast.systems/rewind/pkg/utils/crops"
+	"thirdcoast.systems/rewind/pkg/utils/edl"
+)
+
+func TestPrepareImportedClip(t *testing.T) {
+	src := edl.Clip{
+		Title: " Intro ",
+		In:    2,
+		Out:   60.3,
+		Crops: []crops.Crop{{ID: "old", Name: "Vertical", AspectRatio: "9:16", X: 0.3, Y: 0.5, Width: 0.32, Height: 1}},
+		Filters: []filters.FilterStackEntry{
+			{Type: "crop", Params: map[string]any{"crop_id": "old"}},
+			{Type: "blur", Params: map[string]any{"radius": 8.0, "start": 1.0, "end": 3.0}},
+		},
+	}
+	ic, err := prepareImportedClip(src, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ic.Start != 2 || ic.End != 60 || ic.Title != "Intro" {
+		t.Errorf("bounds/title = %v-%v %q, want 2-60 \"Intro\"", ic.Start, ic.End, ic.Title)
+	}
+	if len(ic.Crops) != 1 || ic.Crops[0].ID == "old" || ic.Crops[0].ID == "" {
+		t.Fatalf("crop not given a new id: %+v", ic.Crops)
+	}
+	var stack []filters.FilterStackEntry
+	if err := json.Unmarshal(ic.FilterStack, &stack); err != nil {
+		t.Fatal(err)
+	}
+	if got := stack[0].Params["crop_id"]; got != ic.Crops[0].ID {
+		t.Errorf("crop filter points at %v, want %s", got, ic.Crops[0].ID)
+	}
+	if src.Filters[0].Params["crop_id"] != "old" {
+		t.Error("source filter params were modified")
+	}
+
+	bad := []struct {
+		name string
+		clip edl.Clip
+		want string
+	}{
+		{"past end", edl.Clip{In: 70, Out: 80}, "past the end"},
+		{"unknown filter", edl.Clip{In: 1, Out: 2, Filters: []filters.FilterStackEntry{{Type: "wobble"}}}, "unknown filter type"},
+		{"missing crop", edl.Clip{In: 1, Out: 2, Filters: []filters.FilterStackEntry{{Type: "crop", Params: map[string]any{"crop_id": "x"}}}}, "not one of the clip's crops"},
+		{"crop outside frame", edl.Clip{In: 1, Out: 2, Crops: []crops.Crop{{X: 0.5, Width: 1.5, Height: 1}}}, "between 0 and 1"},
+		{"duplicate crop", edl.Clip{In: 1, Out: 2, Crops: []crops.Crop{{ID: "a", X: 0.5, Y: 0.5, Width: 1, Height: 1}, {ID: "a", X: 0.5, Y: 0.5, Width: 1, Height: 1}}}, "duplicate id"},
+		{"crop off right edge", edl.Clip{In: 1, Out: 2, Crops: []crops.Crop{{X: 0.9, Y: 0.5, Width: 0.32, Height: 1}}}, "left or right edge"},
+		{"crop off top edge", edl.Clip{In: 1, Out: 2, Crops: []crops.Crop{{X: 0.5, Y: 0.1, Width: 0.5, Height: 0.5}}}, "top or bottom edge"},
+	}
+	for _, tc := range bad {
+		_, err := prepareImportedClip(tc.clip, 60)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want containing %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestPrepareImportedMarker(t *testing.T) {
+	m, err := prepareImportedMarker(edl.Marker{Timestamp: 12, Title: " Goal "}, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MarkerType != "point" || m.Color != defaultMarkerColor || m.Title != "Goal" {
+		t.Errorf("defaults not applied: %+v", m)
+	}
+
+	dur := 20.0
+	for _, bad := range []edl.Marker{
+		{Timestamp: -1},
+		{Timestamp: 61},
+		{Timestamp: 50, Duration: &dur},
+		{Timestamp: 5, MarkerType: "range"},
+	} {
+		if _, err := prepareImportedMarker(bad, 60); err == nil {
+			t.Errorf("prepareImportedMarker(%+v) succeeded, want error", bad)
+		}
+	}
+}
@@ -42,8 +42,9 @@ func HandleMarkers(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Han
 		baseMarkers := markers
 		sbMarkers := []*db.Marker{}
 
-		// Add sponsorblock segments if this is a YouTube video
-		if videoRow.Src != "" {
+		// Add sponsorblock segments if this is a YouTube video and ingest
+		// didn't already import them.
+		if videoRow.Src != "" && !hasSponsorBlockMarkers(markers) {
 			if ytID, err := videoid.ExtractYouTubeVideoID(videoRow.Src); err == nil && ytID != "" {
 				sb := sponsorblock.NewClient(os.Getenv("SPONSORBLOCK_BASE_URL"))
 				ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
@@ -81,14 +82,15 @@ func HandleMarkers(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Han
 
 		// Convert to JSON-friendly format
 		type MarkerResponse struct {
-			ID          string        `json:"id"`
-			VideoID     string        `json:"video_id"`
-			Timestamp   float64       `json:"timestamp"`
-			Duration    *float64      `json:"duration,omitempty"`
-			Title       string        `json:"title"`
-			Description string        `json:"description"`
-			Color       string        `json:"color"`
-			MarkerType  db.MarkerType `json:"marker_type"`
+			ID          string          `json:"id"`
+			VideoID     string          `json:"video_id"`
+			Timestamp   float64         `json:"timestamp"`
+			Duration    *float64        `json:"duration,omitempty"`
+			Title       string          `json:"title"`
+			Description string          `json:"description"`
+			Color       string          `json:"color"`
+			MarkerType  db.MarkerType   `json:"marker_type"`
+			Source      db.MarkerSource `json:"source"`
 		}
 
 		response := make([]MarkerResponse, len(all))
@@ -102,9 +104,21 @@ func HandleMarkers(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.Han
 				Description: m.Description,
 				Color:       m.Color,
 				MarkerType:  m.MarkerType,
+				Source:      m.Source,
 			}
 		}
 
 		return c.JSON(200, response)
 	}
 }
+
+// hasSponsorBlockMarkers reports whether markers include segments ingest
+// imported from SponsorBlock, in which case they aren't fetched again live.
+func hasSponsorBlockMarkers(markers []*db.Marker) bool {
+	for _, m := range markers {
+		if m.Source == db.MarkerSourceSponsorblock {
+			return true
+		}
+	}
+	return false
+}
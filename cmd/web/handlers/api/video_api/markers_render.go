@@ -45,9 +45,10 @@ func HandleMarkersRender(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 			markers = []*db.Marker{}
 		}
 
-		// Fetch SponsorBlock segments for YouTube videos
+		// Fetch SponsorBlock segments for YouTube videos, unless ingest
+		// already imported them.
 		sbMarkers := []*db.Marker{}
-		if videoRow.Src != "" {
+		if videoRow.Src != "" && !hasSponsorBlockMarkers(markers) {
 			if ytID, err := videoid.ExtractYouTubeVideoID(videoRow.Src); err == nil && ytID != "" {
 				sb := sponsorblock.NewClient(os.Getenv("SPONSORBLOCK_BASE_URL"))
 				ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
@@ -80,7 +81,6 @@ func HandleMarkersRender(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 		})
 
 		// Convert to templ-friendly items.
-		// DB markers are indices 0..len(markers)-1, SB markers are len(markers)..len(all)-1.
 		items := make([]components.MarkerItem, len(all))
 		for i, m := range all {
 			dur := 0.0
@@ -93,7 +93,7 @@ func HandleMarkersRender(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 				Duration:       dur,
 				Title:          m.Title,
 				Description:    m.Description,
-				IsSponsorBlock: i >= len(markers),
+				IsSponsorBlock: m.Source == db.MarkerSourceSponsorblock,
 			}
 		}
 
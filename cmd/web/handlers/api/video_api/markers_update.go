@@ -62,6 +62,7 @@ func HandleMarkersUpdate(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 			Color:       color,
 			MarkerType:  markerTypeEnum,
 			CreatedBy:   userUUID,
+			Source:      db.MarkerSourceUser,
 		})
 		if err != nil {
 			return c.String(500, "failed to create marker")
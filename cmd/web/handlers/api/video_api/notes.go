@@ -0,0 +1,129 @@
+package video_api
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/utils/markdown"
+)
+
+// maxVideoNoteBytes bounds one note's markdown source.
+const maxVideoNoteBytes = 64 << 10
+
+// videoNoteResponse is a user's note on a video: the markdown source and its
+// sanitized HTML rendering. UpdatedAt is nil when there is no note.
+type videoNoteResponse struct {
+	Markdown  string        `json:"markdown"`
+	HTML      template.HTML `json:"html"`
+	UpdatedAt *time.Time    `json:"updated_at"`
+}
+
+// HandleGetNotes serves GET /api/videos/:id/notes, returning the current
+// user's private note on the video. A video without a note returns an empty
+// one.
+func HandleGetNotes(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		if _, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+
+		row, err := dbc.Queries(ctx).GetVideoNote(ctx, &db.GetVideoNoteParams{UserID: userUUID, VideoID: videoUUID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.JSON(http.StatusOK, videoNoteResponse{})
+		}
+		if err != nil {
+			slog.Error("failed to fetch video note", "video_id", videoUUID.String(), "error", err)
+			return common.ErrInternal("failed to fetch note")
+		}
+		updated := row.UpdatedAt.Time
+		return c.JSON(http.StatusOK, videoNoteResponse{
+			Markdown:  row.Body.Source,
+			HTML:      row.Body.Render(),
+			UpdatedAt: &updated,
+		})
+	}
+}
+
+// HandlePutNotes serves PUT /api/videos/:id/notes with {"markdown": "..."},
+// replacing the current user's note on the video. Notes are private to the
+// user who wrote them. Saving blank text deletes the note.
+func HandlePutNotes(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, _, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		var req struct {
+			Markdown *string `json:"markdown"`
+		}
+		if err := c.Bind(&req); err != nil || req.Markdown == nil {
+			return common.ErrBadRequest("markdown is required")
+		}
+		if len(*req.Markdown) > maxVideoNoteBytes {
+			return common.ErrBadRequest(fmt.Sprintf("notes must be at most %d KB", maxVideoNoteBytes>>10))
+		}
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+		if _, err := q.GetVideoByID(ctx, videoUUID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+
+		if strings.TrimSpace(*req.Markdown) == "" {
+			if err := q.DeleteVideoNote(ctx, &db.DeleteVideoNoteParams{UserID: userUUID, VideoID: videoUUID}); err != nil {
+				slog.Error("failed to delete video note", "video_id", videoUUID.String(), "error", err)
+				return common.ErrInternal("failed to save note")
+			}
+			return c.JSON(http.StatusOK, videoNoteResponse{})
+		}
+
+		row, err := q.UpsertVideoNote(ctx, &db.UpsertVideoNoteParams{
+			UserID:  userUUID,
+			VideoID: videoUUID,
+			Body:    markdown.Markdown{Source: *req.Markdown},
+		})
+		if err != nil {
+			slog.Error("failed to save video note", "video_id", videoUUID.String(), "error", err)
+			return common.ErrInternal("failed to save note")
+		}
+		updated := row.UpdatedAt.Time
+		return c.JSON(http.StatusOK, videoNoteResponse{
+			Markdown:  row.Body.Source,
+			HTML:      row.Body.Render(),
+			UpdatedAt: &updated,
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package video_api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+const (
+	// ogImageBrand is drawn in the corner of every poster.
+	ogImageBrand = "Rewind"
+	// ogImageTimeout bounds one ffmpeg run.
+	ogImageTimeout = 20 * time.Second
+)
+
+// ogImageSlots limits concurrent poster renders; a shared link can be
+// unfurled by several clients at once.
+var ogImageSlots = make(chan struct{}, 2)
+
+// HandleOGImage serves GET /videos/:id/og-image.jpg, a 1200x630 poster of the
+// video's thumbnail with its title burned in, for Open Graph and Twitter card
+// link previews. Posters are cached next to the video, keyed by title, and
+// re-rendered when the title changes or the thumbnail is regenerated.
+func HandleOGImage(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		videoID := videoUUID.String()
+
+		ctx := c.Request().Context()
+		video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+
+		dir, err := fileserver.GetVideoDirForID(ctx, videoID)
+		if err != nil {
+			return err
+		}
+		thumb := resolveThumbnailPath(dir, videoID, "xl")
+		thumbInfo, err := os.Stat(thumb)
+		if err != nil {
+			return c.String(404, "thumbnail not available")
+		}
+
+		sum := sha256.Sum256([]byte(video.Title))
+		poster := filepath.Join(dir, videoID+".og."+hex.EncodeToString(sum[:4])+".jpg")
+		if info, err := os.Stat(poster); err != nil || info.ModTime().Before(thumbInfo.ModTime()) {
+			if err := renderOGImage(ctx, dir, videoID, thumb, poster, video.Title); err != nil {
+				return common.ErrInternal("failed to render poster")
+			}
+		}
+		return fs.ServeDiskFileWithCache(c, poster, "image/jpeg", "private, max-age=86400, stale-while-revalidate=3600", fileserver.ETagWeakStat)
+	}
+}
+
+// renderOGImage renders the poster to a temp file, moves it into place and
+// removes posters cached for earlier titles.
+func renderOGImage(ctx context.Context, dir, videoID, thumb, poster, title string) error {
+	select {
+	case ogImageSlots <- struct{}{}:
+		defer func() { <-ogImageSlots }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// A unique temp file per render, so concurrent renders of the same poster
+	// never write to one file. The name must not match the stale-poster glob
+	// below, or a concurrent render could delete it.
+	f, err := os.CreateTemp(dir, videoID+".og-*.tmp.jpg")
+	if err != nil {
+		slog.Error("failed to create og image temp file", "video_id", videoID, "error", err)
+		return err
+	}
+	tmp := f.Name()
+	f.Close()
+	defer os.Remove(tmp)
+	runCtx, cancel := context.WithTimeout(ctx, ogImageTimeout)
+	defer cancel()
+	if res := ffmpeg.RenderPoster(runCtx, thumb, tmp, title, ogImageBrand); res.Err != nil {
+		slog.Error("og image render failed", "video_id", videoID, "error", res.Err, "logs", res.Logs)
+		return res.Err
+	}
+	if err := os.Rename(tmp, poster); err != nil {
+		slog.Error("failed to store og image", "video_id", videoID, "error", err)
+		return err
+	}
+
+	stale, _ := filepath.Glob(filepath.Join(dir, videoID+".og.*.jpg"))
+	for _, p := range stale {
+		if p != poster {
+			_ = os.Remove(p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package video_api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+// playbackResponse is the playback compatibility report plus any extra camera
+// angles the ingest service extracted from a multi-angle source.
+type playbackResponse struct {
+	videoinfo.PlaybackCompat
+	// Angles are served from /api/videos/:id/streams/<filename>.
+	Angles []videoinfo.StreamFile `json:"angles,omitempty"`
+}
+
+// HandlePlayback serves GET /api/videos/:id/playback, returning whether the
+// requesting browser can play the original file directly or should fall back
+// to an HLS/transcoded rendition, and which alternate angles exist.
+func HandlePlayback(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+
+		resp := playbackResponse{PlaybackCompat: videoinfo.PlaybackCompatibility(video.ProbeData, c.Request().UserAgent())}
+		if path := common.DerefString(video.VideoPath); path != "" {
+			m, err := videoinfo.ReadStreamsManifest(filepath.Dir(path))
+			if err != nil {
+				slog.Warn("failed to read streams manifest", "video_id", videoUUID.String(), "error", err)
+			} else {
+				resp.Angles = m.Angles()
+			}
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,96 @@
+package video_api
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// HandlePreviewSpriteManifest serves GET /videos/:id/preview-sprite.json, the
+// manifest for the hover-scrub sprite strip (frame count, frame size and
+// sampling interval). Its image is served at preview-sprite.jpg.
+func HandlePreviewSpriteManifest(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
+	return servePreviewSpriteFile(sm, fs, ".preview-sprite.json", "application/json", fileserver.ETagStrongSHA256)
+}
+
+// HandlePreviewSpriteImage serves GET /videos/:id/preview-sprite.jpg, the
+// horizontal strip of hover-scrub frames.
+func HandlePreviewSpriteImage(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
+	return servePreviewSpriteFile(sm, fs, ".preview-sprite.jpg", "image/jpeg", fileserver.ETagWeakStat)
+}
+
+func servePreviewSpriteFile(sm *auth.SessionManager, fs *fileserver.FileServer, suffix, contentType string, etag fileserver.ETagMode) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		videoID := videoUUID.String()
+		dir, err := fileserver.GetVideoDirForID(c.Request().Context(), videoID)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, videoID+suffix)
+		if _, err := os.Stat(path); err != nil {
+			return c.String(404, "preview sprite not available")
+		}
+		return fs.ServeDiskFileWithCache(c, path, contentType, "private, max-age=86400, stale-while-revalidate=3600", etag)
+	}
+}
+
+// videoAssetManifest lists the optional per-video assets that exist on disk.
+// A nil URL means the asset has not been generated.
+type videoAssetManifest struct {
+	VideoID  string             `json:"video_id"`
+	Previews videoPreviewAssets `json:"previews"`
+}
+
+// videoPreviewAssets are the hover preview variants. Clients that cannot or
+// prefer not to decode video use the sprite strip when it is present.
+type videoPreviewAssets struct {
+	MP4    *string `json:"mp4"`
+	Sprite *string `json:"sprite"`
+}
+
+// HandleAssetManifest serves GET /videos/:id/assets.json, telling clients
+// which preview variants are available so they can pick one.
+func HandleAssetManifest(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := sm.GetSession(c.Request()); err != nil {
+			return c.String(401, "unauthorized")
+		}
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+		videoID := videoUUID.String()
+		dir, err := fileserver.GetVideoDirForID(c.Request().Context(), videoID)
+		if err != nil {
+			return err
+		}
+
+		base := "/api/videos/" + videoID
+		existsURL := func(name, url string) *string {
+			if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+				return nil
+			}
+			return &url
+		}
+		return c.JSON(http.StatusOK, videoAssetManifest{
+			VideoID: videoID,
+			Previews: videoPreviewAssets{
+				MP4:    existsURL(videoID+".preview.mp4", base+"/preview.mp4"),
+				Sprite: existsURL(videoID+".preview-sprite.json", base+"/preview-sprite.json"),
+			},
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"thirdcoast.systems/rewind/cmd/web/auth"
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/archival"
 	"thirdcoast.systems/rewind/internal/db"
 )
 
@@ -34,11 +35,21 @@ func HandleRedownload(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.
 			return c.String(500, "failed to fetch video")
 		}
 
+		if err := archival.CheckDownloadsPaused(); err != nil {
+			return c.String(503, err.Error())
+		}
+
+		extraArgs, err := archival.CapDownloadArgs(c.Request().Context(), dbc.Queries(c.Request().Context()), userUUID, []string{})
+		if err != nil {
+			slog.Error("failed to apply download quality cap", "error", err)
+			return c.String(500, "failed to create download job")
+		}
+
 		job, err := dbc.Queries(c.Request().Context()).EnqueueDownloadJob(c.Request().Context(), &db.EnqueueDownloadJobParams{
 			URL:        videoRow.Src,
 			ArchivedBy: userUUID,
 			Refresh:    false,
-			ExtraArgs:  []string{},
+			ExtraArgs:  extraArgs,
 		})
 		if err != nil {
 			slog.Error("failed to create redownload job", "error", err)
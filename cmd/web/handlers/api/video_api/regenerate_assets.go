@@ -3,28 +3,27 @@ package video_api
 import (
 	"errors"
 	"log/slog"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
+	xtlang "golang.org/x/text/language"
 	"thirdcoast.systems/rewind/cmd/web/auth"
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
 	"thirdcoast.systems/rewind/internal/db"
 )
 
-// validAssetScopes are the individual asset types that can be regenerated.
-var validAssetScopes = map[string]bool{
-	"thumbnail": true,
-	"preview":   true,
-	"seek":      true,
-	"waveform":  true,
-	"captions":  true,
-	"streams":   true,
-}
-
 // HandleRegenerateAssets triggers regeneration of video assets.
-// Query param ?scope=thumbnail|preview|seek|waveform limits to a single asset.
+// Query param ?scope=thumbnail|preview|seek|waveform|chapters limits to a single asset.
 // Omitting scope regenerates all assets.
+// Query param ?at=<seconds> pins the thumbnail to that timestamp and ?at=auto
+// clears the pin; either needs scope thumbnail or no scope.
+// Query params ?model=<whisper model> and ?language=<code>|auto override the
+// Whisper settings for the captions run; they need scope captions or no scope,
+// and only admins may set them, since a larger model ties up the shared
+// Whisper slots for much longer.
 func HandleRegenerateAssets(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		_, _, err := common.RequireSessionUser(c, sm)
@@ -40,14 +39,60 @@ func HandleRegenerateAssets(sm *auth.SessionManager, dbc *db.DatabaseConnection)
 		// Parse optional asset scope
 		var assetScope *string
 		if raw := strings.TrimSpace(c.QueryParam("scope")); raw != "" {
-			if !validAssetScopes[raw] {
-				return c.String(400, "invalid scope: must be thumbnail, preview, seek, or waveform")
+			if !db.ValidAssetScopes[raw] {
+				return c.String(400, "invalid scope: must be thumbnail, preview, seek, waveform, or chapters")
 			}
 			assetScope = &raw
 		}
 
+		// Parse optional thumbnail timestamp (-1 clears a pin)
+		var thumbnailAt *float64
+		if raw := strings.TrimSpace(c.QueryParam("at")); raw != "" {
+			if assetScope != nil && *assetScope != "thumbnail" {
+				return c.String(400, "at requires scope thumbnail")
+			}
+			at := -1.0
+			if !strings.EqualFold(raw, "auto") {
+				v, err := strconv.ParseFloat(raw, 64)
+				if err != nil || v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+					return c.String(400, "invalid at: must be a non-negative number of seconds or auto")
+				}
+				at = v
+			}
+			thumbnailAt = &at
+		}
+
+		// Parse optional Whisper overrides
+		var whisperModel, whisperLanguage *string
+		if raw := strings.TrimSpace(c.QueryParam("model")); raw != "" {
+			if !db.ValidWhisperModels[raw] {
+				return c.String(400, "invalid model: must be tiny, base, small, medium, large, or a variant such as large-v3")
+			}
+			whisperModel = &raw
+		}
+		if raw := strings.TrimSpace(c.QueryParam("language")); raw != "" {
+			code := "auto"
+			if !strings.EqualFold(raw, "auto") {
+				tag, err := xtlang.Parse(raw)
+				if err != nil || tag == xtlang.Und {
+					return c.String(400, "invalid language: must be a language code or auto")
+				}
+				base, _ := tag.Base()
+				code = base.String()
+			}
+			whisperLanguage = &code
+		}
+		if whisperModel != nil || whisperLanguage != nil {
+			if sm.GetAccessLevel(c.Request()) != auth.AccessAdmin {
+				return c.String(403, "only admins can override the Whisper model or language")
+			}
+			if assetScope != nil && *assetScope != "captions" {
+				return c.String(400, "model and language require scope captions")
+			}
+		}
+
 		// Verify the video exists
-		_, err = dbc.Queries(c.Request().Context()).GetVideoByID(c.Request().Context(), videoUUID)
+		video, err := dbc.Queries(c.Request().Context()).GetVideoByID(c.Request().Context(), videoUUID)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				return c.String(404, "video not found")
@@ -55,12 +100,18 @@ func HandleRegenerateAssets(sm *auth.SessionManager, dbc *db.DatabaseConnection)
 			slog.Error("failed to fetch video for asset regeneration", "error", err, "video_id", videoUUID)
 			return c.String(500, "failed to fetch video")
 		}
+		if thumbnailAt != nil && video.DurationSeconds != nil && *thumbnailAt >= float64(*video.DurationSeconds) {
+			return c.String(400, "invalid at: past the end of the video")
+		}
 
 		// Create a special ingest job that will regenerate assets.
 		// The ingest worker will discover the video file on disk even if video_path is NULL.
 		job, err := dbc.Queries(c.Request().Context()).EnqueueAssetRegenerationJob(c.Request().Context(), &db.EnqueueAssetRegenerationJobParams{
-			VideoID:    videoUUID,
-			AssetScope: assetScope,
+			VideoID:         videoUUID,
+			AssetScope:      assetScope,
+			ThumbnailAt:     thumbnailAt,
+			WhisperModel:    whisperModel,
+			WhisperLanguage: whisperLanguage,
 		})
 		if err != nil {
 			slog.Error("failed to create asset regeneration job", "error", err, "video_id", videoUUID, "scope", assetScope)
@@ -71,13 +122,23 @@ func HandleRegenerateAssets(sm *auth.SessionManager, dbc *db.DatabaseConnection)
 		if assetScope != nil {
 			scopeLabel = *assetScope
 		}
-		slog.Info("created asset regeneration job", "ingest_job_id", job.IngestJobID, "download_job_id", job.DownloadJobID, "video_id", videoUUID, "scope", scopeLabel)
+		slog.Info("created asset regeneration job", "ingest_job_id", job.IngestJobID, "download_job_id", job.DownloadJobID, "video_id", videoUUID, "scope", scopeLabel, "thumbnail_at", thumbnailAt, "whisper_model", whisperModel, "whisper_language", whisperLanguage)
 
-		return c.JSON(200, map[string]any{
+		resp := map[string]any{
 			"ingest_job_id":   job.IngestJobID.String(),
 			"download_job_id": job.DownloadJobID.String(),
 			"video_id":        job.VideoID.String(),
 			"scope":           scopeLabel,
-		})
+		}
+		if thumbnailAt != nil {
+			resp["thumbnail_at"] = *thumbnailAt
+		}
+		if whisperModel != nil {
+			resp["whisper_model"] = *whisperModel
+		}
+		if whisperLanguage != nil {
+			resp["whisper_language"] = *whisperLanguage
+		}
+		return c.JSON(200, resp)
 	}
 }
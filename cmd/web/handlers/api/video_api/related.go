@@ -0,0 +1,135 @@
+package video_api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/starfederation/datastar-go/datastar"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/cmd/web/templates"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+const (
+	defaultRelatedLimit = 10
+	maxRelatedLimit     = 50
+)
+
+// RelatedVideo is one entry in the related-videos response.
+type RelatedVideo struct {
+	ID              string `json:"id"`
+	Title           string `json:"title"`
+	Uploader        string `json:"uploader"`
+	ChannelID       string `json:"channel_id,omitempty"`
+	UploadDate      string `json:"upload_date,omitempty"`
+	DurationSeconds *int32 `json:"duration_seconds,omitempty"`
+	ThumbnailURL    string `json:"thumbnail_url"`
+	CreatedAt       string `json:"created_at"`
+}
+
+// HandleRelated serves GET /api/videos/:id/related?limit=N, returning other
+// videos from the same channel (or uploader when the channel is unknown),
+// newest first. Videos with neither yield an empty list.
+func HandleRelated(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		limit := defaultRelatedLimit
+		if raw := c.QueryParam("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				return common.ErrBadRequest("invalid limit")
+			}
+			limit = min(n, maxRelatedLimit)
+		}
+
+		videos, err := relatedVideos(c, dbc, limit)
+		if err != nil {
+			return err
+		}
+
+		out := make([]RelatedVideo, 0, len(videos))
+		for _, v := range videos {
+			rv := RelatedVideo{
+				ID:              v.ID.String(),
+				Title:           v.Title,
+				Uploader:        v.Uploader,
+				ChannelID:       common.DerefString(v.ChannelID),
+				DurationSeconds: v.DurationSeconds,
+				ThumbnailURL:    "/api/videos/" + v.ID.String() + "/thumbnail",
+				CreatedAt:       v.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+			}
+			if v.UploadDate.Valid {
+				rv.UploadDate = v.UploadDate.Time.Format("2006-01-02")
+			}
+			out = append(out, rv)
+		}
+		return c.JSON(http.StatusOK, map[string]any{"videos": out})
+	}
+}
+
+// HandleRelatedRender serves GET /api/videos/:id/related/render, patching the
+// "more from this uploader" grid on the detail page via SSE.
+func HandleRelatedRender(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		videos, err := relatedVideos(c, dbc, defaultRelatedLimit)
+		if err != nil {
+			return err
+		}
+
+		common.SetSSEHeaders(c)
+		sse := datastar.NewSSE(c.Response().Writer, c.Request())
+		return sse.PatchElementTempl(
+			templates.RelatedVideosGrid(videos),
+			datastar.WithSelectorID("video-related"),
+			datastar.WithModeReplace(),
+		)
+	}
+}
+
+// relatedVideos loads the :id video and returns up to limit others sharing
+// its channel or uploader.
+func relatedVideos(c echo.Context, dbc *db.DatabaseConnection, limit int) ([]*db.Video, error) {
+	videoUUID, err := common.RequireUUIDParam(c, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := c.Request().Context()
+	q := dbc.Queries(ctx)
+	video, err := q.GetVideoByID(ctx, videoUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, common.ErrNotFound("video not found")
+		}
+		return nil, common.ErrInternal("failed to fetch video")
+	}
+
+	params := &db.ListRelatedVideosParams{VideoID: videoUUID, PageLimit: int32(limit)}
+	if video.ChannelID != nil && strings.TrimSpace(*video.ChannelID) != "" {
+		params.ChannelID = video.ChannelID
+	} else if strings.TrimSpace(video.Uploader) != "" {
+		params.Uploader = &video.Uploader
+	} else {
+		return []*db.Video{}, nil
+	}
+
+	videos, err := q.ListRelatedVideos(ctx, params)
+	if err != nil {
+		slog.Error("failed to list related videos", "error", err, "video_id", videoUUID)
+		return nil, common.ErrInternal("failed to list related videos")
+	}
+	return videos, nil
+}
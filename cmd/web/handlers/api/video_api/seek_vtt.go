@@ -2,10 +2,13 @@
 package video_api
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 	"thirdcoast.systems/rewind/cmd/web/auth"
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
@@ -13,6 +16,8 @@ import (
 	"thirdcoast.systems/rewind/internal/db"
 )
 // HandleSeekVTT serves GET /videos/:id/seek/levels/:level/seek.vtt, returning the WebVTT cue file for seek thumbnails.
+// With ?chapters=1, each cue gets a second text line naming the chapter it
+// starts in (see mergeSeekVTTChapters). Videos without chapters get the plain file.
 func HandleSeekVTT(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *fileserver.FileServer) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if _, _, err := sm.GetSession(c.Request()); err != nil {
@@ -34,8 +39,88 @@ func HandleSeekVTT(sm *auth.SessionManager, dbc *db.DatabaseConnection, fs *file
 		if _, err := os.Stat(path); err != nil {
 			return c.String(404, "seek thumbnails not available")
 		}
+		if withChapters(c.QueryParam("chapters")) {
+			ctx := c.Request().Context()
+			video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return common.ErrNotFound("video not found")
+				}
+				return common.ErrInternal("failed to fetch video")
+			}
+			var duration float64
+			if video.DurationSeconds != nil {
+				duration = float64(*video.DurationSeconds)
+			}
+			if spans := chapterSpans(common.VideoChapters(ctx, dbc, video), duration); len(spans) > 0 {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return common.ErrInternal("failed to read seek thumbnails")
+				}
+				// Chapters change on a metadata refresh, so this is not cached
+				// like the plain file.
+				c.Response().Header().Set("Cache-Control", "private, no-cache")
+				return c.Blob(200, "text/vtt; charset=utf-8", []byte(mergeSeekVTTChapters(string(data), spans)))
+			}
+		}
 		return fs.ServeDiskFileWithCache(c, path, "text/vtt", "private, max-age=86400, stale-while-revalidate=3600", fileserver.ETagStrongSHA256)
 	}
 }
 
+// withChapters reports whether a ?chapters= flag asks for chapter labels.
+func withChapters(v string) bool {
+	v = strings.TrimSpace(v)
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// mergeSeekVTTChapters adds the title of the chapter each cue starts in as a
+// second cue text line, after the sheet#xywh line. Cues outside every chapter
+// are left as they are. Clients that read only the first text line are
+// unaffected.
+func mergeSeekVTTChapters(vtt string, spans []chapterSpan) string {
+	lines := strings.Split(vtt, "\n")
+	out := make([]string, 0, len(lines)+len(lines)/3)
+	title := ""
+	for _, line := range lines {
+		out = append(out, line)
+		if start, _, ok := strings.Cut(line, "-->"); ok {
+			title = chapterTitleAt(spans, parseVTTTimestamp(strings.TrimSpace(start)))
+			continue
+		}
+		if title != "" && strings.TrimSpace(line) != "" {
+			out = append(out, title)
+			title = ""
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// chapterTitleAt returns the title of the span containing t, or "".
+func chapterTitleAt(spans []chapterSpan, t float64) string {
+	for _, sp := range spans {
+		if t >= sp.start && t < sp.end {
+			return sp.title
+		}
+	}
+	return ""
+}
+
+// parseVTTTimestamp parses a WebVTT hh:mm:ss.mmm (or mm:ss.mmm) timestamp,
+// returning -1 when it is malformed.
+func parseVTTTimestamp(ts string) float64 {
+	parts := strings.Split(ts, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return -1
+	}
+	var total float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v < 0 {
+			return -1
+		}
+		total = total*60 + v
+	}
+	return total
+}
+
 // HandleSeekSheet serves a seek thumbnail sheet.
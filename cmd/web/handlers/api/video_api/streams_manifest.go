@@ -0,0 +1,86 @@
+package video_api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+// streamsManifestResponse is the rebuilt streams manifest, split the way the
+// video page uses it, plus any stream files that could not be probed.
+type streamsManifestResponse struct {
+	Streams   []videoinfo.StreamFile `json:"streams"`
+	Qualities []videoinfo.StreamFile `json:"qualities"`
+	Angles    []videoinfo.StreamFile `json:"angles"`
+	Failed    []string               `json:"failed"`
+}
+
+// HandleRegenerateStreamsManifest serves POST
+// /api/videos/:id/regenerate-streams-manifest, re-probing every file in the
+// video's streams/ directory and rewriting streams/manifest.json. Use it after
+// stream files were added or removed by hand; the quality chips, the player's
+// quality picker and the angle list all read the manifest on the next load.
+func HandleRegenerateStreamsManifest(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		videoUUID, err := common.RequireUUIDParam(c, "id")
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		video, err := dbc.Queries(ctx).GetVideoByID(ctx, videoUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return common.ErrNotFound("video not found")
+			}
+			return common.ErrInternal("failed to fetch video")
+		}
+		path := strings.TrimSpace(common.DerefString(video.VideoPath))
+		if path == "" {
+			return common.ErrBadRequest("video has no file yet")
+		}
+
+		videoDir := filepath.Dir(path)
+		m, failed, err := videoinfo.ScanStreams(ctx, videoDir)
+		if err != nil {
+			slog.Error("failed to scan streams", "video_id", videoUUID.String(), "error", err)
+			return common.ErrInternal("failed to scan streams")
+		}
+		for _, name := range failed {
+			slog.Warn("streams manifest: failed to probe", "video_id", videoUUID.String(), "file", name)
+		}
+		if err := m.Write(videoDir); err != nil {
+			slog.Error("failed to write streams manifest", "video_id", videoUUID.String(), "error", err)
+			return common.ErrInternal("failed to write streams manifest")
+		}
+		slog.Info("streams manifest regenerated", "video_id", videoUUID.String(), "streams", len(m.Streams), "failed", len(failed))
+
+		return c.JSON(http.StatusOK, streamsManifestResponse{
+			Streams:   nonNil(m.Streams),
+			Qualities: nonNil(m.Qualities(0)),
+			Angles:    nonNil(m.Angles()),
+			Failed:    nonNil(failed),
+		})
+	}
+}
+
+// nonNil returns s, or an empty slice when s is nil, so it encodes as [].
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
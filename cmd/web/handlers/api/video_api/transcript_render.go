@@ -1,6 +1,7 @@
 package video_api
 
 import (
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -12,12 +13,14 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/fileserver"
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
 	"thirdcoast.systems/rewind/cmd/web/templates/components"
+	"thirdcoast.systems/rewind/internal/db"
 )
 
 // HandleTranscriptRender returns an SSE-patched, server-rendered transcript list.
 // This replaces the former client-side TranscriptManager.render() which built
-// HTML via createElement/innerHTML.
-func HandleTranscriptRender(sm *auth.SessionManager) echo.HandlerFunc {
+// HTML via createElement/innerHTML. Cues with stored word timings render each
+// word as its own seek target.
+func HandleTranscriptRender(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if _, _, err := sm.GetSession(c.Request()); err != nil {
 			return echo.NewHTTPError(401, "unauthorized")
@@ -49,6 +52,16 @@ func HandleTranscriptRender(sm *auth.SessionManager) echo.HandlerFunc {
 
 		cues := parseVTT(string(data))
 
+		ctx := c.Request().Context()
+		words, err := dbc.Queries(ctx).ListVideoTranscriptWords(ctx, &db.ListVideoTranscriptWordsParams{
+			VideoID: videoUUID,
+			Lang:    captionFileLang(vttPath, videoID),
+		})
+		if err != nil {
+			slog.Warn("transcript render: failed to list word timings", "video_id", videoID, "error", err)
+		}
+		attachTranscriptWords(cues, words)
+
 		sse := datastar.NewSSE(c.Response().Writer, c.Request())
 		sse.PatchElementTempl(components.TranscriptList(cues), datastar.WithSelectorID("transcript-list-inner"))
 		return nil
@@ -75,6 +88,21 @@ func findVTTFile(dir, videoID string) string {
 	return ""
 }
 
+// attachTranscriptWords adds stored word timings to the cues they belong to.
+// A word's cue index counts cues as parseVTT returns them.
+func attachTranscriptWords(cues []components.TranscriptCue, words []*db.VideoTranscriptWord) {
+	for _, w := range words {
+		i := int(w.CueIndex)
+		if i < 0 || i >= len(cues) {
+			continue
+		}
+		cues[i].Words = append(cues[i].Words, components.TranscriptWord{
+			Start: cues[i].Start + w.StartOffset,
+			Text:  w.Word,
+		})
+	}
+}
+
 // parseVTT parses a WebVTT file into TranscriptCue slices.
 func parseVTT(text string) []components.TranscriptCue {
 	lines := strings.Split(text, "\n")
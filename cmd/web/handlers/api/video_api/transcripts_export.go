@@ -0,0 +1,190 @@
+package video_api
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+)
+
+// transcriptExportPageSize bounds how many transcripts are held in memory at once.
+const transcriptExportPageSize = 100
+
+// transcriptExportSegment is one timestamped cue in an exported transcript.
+type transcriptExportSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// transcriptExportRecord is one line of the JSONL export (or one file in the ZIP).
+type transcriptExportRecord struct {
+	VideoID    string                    `json:"video_id"`
+	Title      string                    `json:"title"`
+	Uploader   string                    `json:"uploader,omitempty"`
+	ChannelID  string                    `json:"channel_id,omitempty"`
+	UploadDate string                    `json:"upload_date,omitempty"`
+	ArchivedAt string                    `json:"archived_at,omitempty"`
+	Lang       string                    `json:"lang"`
+	Format     string                    `json:"format"`
+	Text       string                    `json:"text"`
+	Segments   []transcriptExportSegment `json:"segments"`
+}
+
+// HandleTranscriptsExport serves GET /api/transcripts/export, streaming every
+// stored transcript as JSONL (default) or a ZIP of per-transcript JSON files.
+//
+// Query params: format=jsonl|zip, channelId, dateType=archived|published,
+// dateFrom/dateTo (YYYY-MM-DD). A malformed filter is a 400. Rows are read in keyset pages and written as
+// they arrive, so memory stays flat regardless of archive size.
+func HandleTranscriptsExport(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, _, err := common.RequireSessionUser(c, sm); err != nil {
+			return err
+		}
+
+		format := strings.ToLower(strings.TrimSpace(c.QueryParam("format")))
+		if format == "" {
+			format = "jsonl"
+		}
+		if format != "jsonl" && format != "zip" {
+			return common.ErrBadRequest("format must be jsonl or zip")
+		}
+
+		// A filter that doesn't parse is refused rather than dropped, which
+		// would silently export the whole archive.
+		dateType := strings.TrimSpace(c.QueryParam("dateType"))
+		if dateType != "" && dateType != "archived" && dateType != "published" {
+			return common.ErrBadRequest("dateType must be archived or published")
+		}
+		dateFrom, err := parseExportDate("dateFrom", c.QueryParam("dateFrom"))
+		if err != nil {
+			return err
+		}
+		dateTo, err := parseExportDate("dateTo", c.QueryParam("dateTo"))
+		if err != nil {
+			return err
+		}
+		if dateFrom.Valid && dateTo.Valid && dateTo.Time.Before(dateFrom.Time) {
+			return common.ErrBadRequest("dateTo must not be before dateFrom")
+		}
+
+		params := &db.ListTranscriptsForExportParams{
+			AfterVideoID: pgtype.UUID{Valid: true},
+			ChannelID:    nullableString(strings.TrimSpace(c.QueryParam("channelId"))),
+			DateType:     nullableString(dateType),
+			DateFrom:     dateFrom,
+			DateTo:       dateTo,
+			PageLimit:    transcriptExportPageSize,
+		}
+
+		stamp := time.Now().UTC().Format("20060102-150405")
+		res := c.Response()
+		res.Header().Set("X-Accel-Buffering", "no")
+		if format == "zip" {
+			res.Header().Set(echo.HeaderContentType, "application/zip")
+			res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="transcripts-`+stamp+`.zip"`)
+		} else {
+			res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+			res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="transcripts-`+stamp+`.jsonl"`)
+		}
+		res.WriteHeader(http.StatusOK)
+
+		var zw *zip.Writer
+		if format == "zip" {
+			zw = zip.NewWriter(res)
+			defer zw.Close()
+		}
+
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+		written := 0
+		for {
+			rows, err := q.ListTranscriptsForExport(ctx, params)
+			if err != nil {
+				// Headers are already sent; all we can do is stop and log.
+				slog.Error("transcript export: query failed", "error", err, "written", written)
+				return nil
+			}
+
+			for _, row := range rows {
+				rec := transcriptExportRecordFromRow(row)
+
+				var w io.Writer = res
+				if zw != nil {
+					w, err = zw.Create(rec.VideoID + "." + rec.Lang + ".json")
+					if err != nil {
+						slog.Error("transcript export: zip entry failed", "error", err)
+						return nil
+					}
+				}
+				if err := json.NewEncoder(w).Encode(rec); err != nil {
+					// Client went away.
+					return nil
+				}
+				written++
+			}
+			res.Flush()
+
+			if len(rows) < transcriptExportPageSize {
+				break
+			}
+			last := rows[len(rows)-1]
+			params.AfterVideoID = last.VideoID
+			params.AfterLang = last.Lang
+		}
+
+		slog.Info("transcript export complete", "format", format, "count", written)
+		return nil
+	}
+}
+
+// parseExportDate parses the YYYY-MM-DD query param name. An empty value is
+// no filter; anything else that doesn't parse is a 400.
+func parseExportDate(name, value string) (pgtype.Date, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return pgtype.Date{}, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return pgtype.Date{}, common.ErrBadRequest(name + " must be a YYYY-MM-DD date")
+	}
+	return pgtype.Date{Time: t, Valid: true}, nil
+}
+
+func transcriptExportRecordFromRow(row *db.ListTranscriptsForExportRow) transcriptExportRecord {
+	rec := transcriptExportRecord{
+		VideoID:  row.VideoID.String(),
+		Title:    row.Title,
+		Uploader: row.Uploader,
+		Lang:     row.Lang,
+		Format:   row.Format,
+		Text:     row.Text,
+		Segments: []transcriptExportSegment{},
+	}
+	if row.ChannelID != nil {
+		rec.ChannelID = *row.ChannelID
+	}
+	if row.UploadDate.Valid {
+		rec.UploadDate = row.UploadDate.Time.Format("2006-01-02")
+	}
+	if row.ArchivedAt.Valid {
+		rec.ArchivedAt = row.ArchivedAt.Time.UTC().Format(time.RFC3339)
+	}
+	if strings.EqualFold(row.Format, "vtt") || strings.HasPrefix(strings.TrimSpace(row.Raw), "WEBVTT") {
+		for _, cue := range parseVTT(row.Raw) {
+			rec.Segments = append(rec.Segments, transcriptExportSegment{Start: cue.Start, End: cue.End, Text: cue.Text})
+		}
+	}
+	return rec
+}
@@ -0,0 +1,18 @@
+package video_api
+
+import "testing"
+
+func TestParseExportDate(t *testing.T) {
+	d, err := parseExportDate("dateFrom", " 2024-03-01 ")
+	if err != nil || !d.Valid || d.Time.Format("2006-01-02") != "2024-03-01" {
+		t.Fatalf("parseExportDate(2024-03-01) = %+v, %v", d, err)
+	}
+	if d, err := parseExportDate("dateFrom", ""); err != nil || d.Valid {
+		t.Fatalf("empty value = %+v, %v; want no filter", d, err)
+	}
+	for _, bad := range []string{"2024-13-01", "03/01/2024", "yesterday"} {
+		if _, err := parseExportDate("dateTo", bad); err == nil {
+			t.Errorf("parseExportDate(%q) accepted a malformed date", bad)
+		}
+	}
+}
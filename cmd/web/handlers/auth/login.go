@@ -39,6 +39,9 @@ func HandleLogin(sm *webauth.SessionManager, dbc *db.DatabaseConnection) echo.Ha
 
 		// Check if user is enabled
 		if !user.Enabled {
+			if user.PendingApproval {
+				return templates.Login("Account is awaiting admin approval").Render(c.Request().Context(), c.Response())
+			}
 			return templates.Login("Account is disabled").Render(c.Request().Context(), c.Response())
 		}
 
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// pendingRegistration is the JSON payload posted to the registration webhook.
+type pendingRegistration struct {
+	Event     string    `json:"event"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// notifyPendingRegistration tells admins that a new account is awaiting
+// approval. It always logs; when webhookURL is set it also POSTs a JSON
+// payload in the background so a slow endpoint never delays the sign-up.
+func notifyPendingRegistration(webhookURL, userID, username, email string) {
+	slog.Info("registration pending admin approval", "user_id", userID, "username", username, "email", email)
+	if webhookURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(pendingRegistration{
+		Event:     "registration.pending",
+		UserID:    userID,
+		Username:  username,
+		Email:     email,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		slog.Error("failed to marshal registration webhook payload", "error", err)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+		if err != nil {
+			slog.Error("failed to build registration webhook request", "error", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			slog.Warn("registration webhook failed", "error", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			slog.Warn("registration webhook returned non-success status", "status", resp.StatusCode)
+		}
+	}()
+}
@@ -11,7 +11,11 @@ import (
 )
 
 // HandleRegister serves POST /register, creating a new user account and logging them in.
-func HandleRegister(sm *webauth.SessionManager, dbc *db.DatabaseConnection, sc *db.SettingsCache) echo.HandlerFunc {
+// When the instance requires approval, the account is created disabled instead,
+// admins are notified (log, plus webhookURL if set) and no session is started.
+// Addresses listed in the instance's admin emails register as admins and are
+// never held for approval.
+func HandleRegister(sm *webauth.SessionManager, dbc *db.DatabaseConnection, sc *db.SettingsCache, webhookURL string) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		username := strings.TrimSpace(c.FormValue("username"))
 		email := strings.TrimSpace(c.FormValue("email"))
@@ -34,6 +38,7 @@ func HandleRegister(sm *webauth.SessionManager, dbc *db.DatabaseConnection, sc *
 		}
 
 		role := "user"
+		pendingApproval := false
 		if userCount == 0 {
 			role = "admin"
 		} else {
@@ -41,6 +46,12 @@ func HandleRegister(sm *webauth.SessionManager, dbc *db.DatabaseConnection, sc *
 			if settings != nil && !settings.RegistrationEnabled {
 				return templates.Register("Registration is disabled on this instance").Render(c.Request().Context(), c.Response())
 			}
+			if settings != nil && isAdminEmail(settings.AdminEmails, email) {
+				// Configured admin addresses are promoted and skip approval.
+				role = "admin"
+			} else {
+				pendingApproval = settings != nil && settings.RegistrationRequiresApproval
+			}
 		}
 
 		// Check if username is taken
@@ -65,16 +76,22 @@ func HandleRegister(sm *webauth.SessionManager, dbc *db.DatabaseConnection, sc *
 
 		// Create user with hashed password
 		user, err := q.NewUser(c.Request().Context(), db.NewUserParams{
-			Username: username,
-			Email:    email,
-			Password: password,
-			Role:     role,
+			Username:        username,
+			Email:           email,
+			Password:        password,
+			Role:            role,
+			PendingApproval: pendingApproval,
 		})
 		if err != nil {
 			slog.Error("failed to create user", "error", err)
 			return templates.Register("Password does not meet requirements (minimum 8 characters) or an error occurred").Render(c.Request().Context(), c.Response())
 		}
 
+		if pendingApproval {
+			notifyPendingRegistration(webhookURL, user.ID.String(), user.UserName, user.Email)
+			return templates.RegisterPending().Render(c.Request().Context(), c.Response())
+		}
+
 		// Determine access level from role
 		accessLevel := webauth.AccessUser
 		if role == "admin" {
@@ -90,3 +107,14 @@ func HandleRegister(sm *webauth.SessionManager, dbc *db.DatabaseConnection, sc *
 		return c.Redirect(302, "/")
 	}
 }
+
+// isAdminEmail reports whether email is one of the configured admin emails.
+// Addresses compare case-insensitively.
+func isAdminEmail(adminEmails []string, email string) bool {
+	for _, a := range adminEmails {
+		if strings.EqualFold(strings.TrimSpace(a), email) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/utils/captionstyle"
+)
+
+// LoadCaptionStyle returns the user's caption display preferences, or the
+// default style if none are saved or they cannot be loaded.
+func LoadCaptionStyle(ctx context.Context, dbc *db.DatabaseConnection, userUUID pgtype.UUID) captionstyle.Style {
+	row, err := dbc.Queries(ctx).GetUserCaptionStyle(ctx, userUUID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("failed to load caption style", "error", err)
+		}
+		return captionstyle.Style{}
+	}
+	return captionstyle.Normalize(captionstyle.Style{
+		FontSize:   row.CaptionFontSize,
+		Color:      row.CaptionColor,
+		Background: row.CaptionBackground,
+		Position:   row.CaptionPosition,
+	})
+}
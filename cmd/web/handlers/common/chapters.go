@@ -0,0 +1,32 @@
+package common
+
+import (
+	"context"
+	"log/slog"
+
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
+)
+
+// VideoChapters returns a video's chapters in playback order. Ingest stores
+// them in video_chapters; videos ingested before that fall back to the
+// chapters in their info.json, sanitized the same way.
+func VideoChapters(ctx context.Context, dbc *db.DatabaseConnection, video *db.Video) []videoinfo.Chapter {
+	rows, err := dbc.Queries(ctx).ListVideoChapters(ctx, video.ID)
+	if err != nil {
+		slog.Warn("failed to list video chapters", "video_id", video.ID, "error", err)
+	}
+	if len(rows) > 0 {
+		chapters := make([]videoinfo.Chapter, len(rows))
+		for i, r := range rows {
+			chapters[i] = videoinfo.Chapter{StartTime: r.StartSeconds, EndTime: r.EndSeconds, Title: r.Title}
+		}
+		return chapters
+	}
+
+	var duration float64
+	if video.DurationSeconds != nil {
+		duration = float64(*video.DurationSeconds)
+	}
+	return videoinfo.SanitizeChapters(video.Info.Chapters, duration)
+}
@@ -1,6 +1,14 @@
 package common
 
-import "github.com/labstack/echo/v4"
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+)
 
 // SetSSEHeaders sets headers needed for SSE that datastar.NewSSE() does NOT set.
 // datastar already sets Content-Type, Cache-Control, and Connection.
@@ -8,3 +16,99 @@ import "github.com/labstack/echo/v4"
 func SetSSEHeaders(c echo.Context) {
 	c.Response().Header().Set("X-Accel-Buffering", "no")
 }
+
+// WriteSSEComment writes an SSE comment line (e.g. a keep-alive) and flushes.
+// A write error means the client has gone; stream loops should return on it
+// rather than wait for the next context check.
+func WriteSSEComment(c echo.Context, text string) error {
+	if _, err := fmt.Fprintf(c.Response(), ": %s\n\n", text); err != nil {
+		return err
+	}
+	c.Response().Flush()
+	return nil
+}
+
+// SSELimiter caps the number of concurrently open SSE streams, both across
+// the server and per user. A zero limit means unlimited.
+type SSELimiter struct {
+	global  int
+	perUser int
+
+	mu     sync.Mutex
+	total  int
+	byUser map[string]int
+}
+
+// NewSSELimiter returns a limiter allowing at most global streams in total
+// and perUser streams per user.
+func NewSSELimiter(global, perUser int) *SSELimiter {
+	return &SSELimiter{global: global, perUser: perUser, byUser: map[string]int{}}
+}
+
+// Acquire reserves a stream slot for key. It returns a release func, which
+// must be called exactly once when the stream ends, or ok=false when a limit
+// has been reached.
+func (l *SSELimiter) Acquire(key string) (release func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.global > 0 && l.total >= l.global {
+		return nil, false
+	}
+	if l.perUser > 0 && l.byUser[key] >= l.perUser {
+		return nil, false
+	}
+	l.total++
+	l.byUser[key]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.total--
+			if l.byUser[key]--; l.byUser[key] <= 0 {
+				delete(l.byUser, key)
+			}
+		})
+	}, true
+}
+
+// Open returns the number of streams currently open.
+func (l *SSELimiter) Open() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total
+}
+
+// AcquireRequest reserves a stream slot for the request's client, as the
+// Middleware does. Handlers that only stream after other work (e.g. enqueueing
+// an export) use it to count the stream without wrapping the whole route.
+// Requests are counted against the session user, or the client IP when there
+// is no session (e.g. extension bearer tokens).
+func (l *SSELimiter) AcquireRequest(c echo.Context, sm *auth.SessionManager) (release func(), ok bool) {
+	key := "ip:" + c.RealIP()
+	if userID, _, err := sm.GetSession(c.Request()); err == nil && userID != "" {
+		key = "user:" + userID
+	}
+	release, ok = l.Acquire(key)
+	if !ok {
+		slog.Warn("SSE stream limit reached", "path", c.Path(), "key", key, "open", l.Open())
+	}
+	return release, ok
+}
+
+// Middleware holds a stream slot for the lifetime of each request, returning
+// 503 with Retry-After when the limit is reached.
+func (l *SSELimiter) Middleware(sm *auth.SessionManager) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			release, ok := l.AcquireRequest(c, sm)
+			if !ok {
+				c.Response().Header().Set("Retry-After", "10")
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "too many open streams")
+			}
+			defer release()
+			return next(c)
+		}
+	}
+}
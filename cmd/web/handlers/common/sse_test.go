@@ -0,0 +1,42 @@
+package common
+
+import "testing"
+
+func TestSSELimiter_PerUserAndGlobal(t *testing.T) {
+	l := NewSSELimiter(3, 2)
+
+	a1, ok := l.Acquire("a")
+	if !ok {
+		t.Fatal("first stream for a refused")
+	}
+	if _, ok := l.Acquire("a"); !ok {
+		t.Fatal("second stream for a refused")
+	}
+	if _, ok := l.Acquire("a"); ok {
+		t.Fatal("third stream for a allowed past the per-user limit")
+	}
+	if _, ok := l.Acquire("b"); !ok {
+		t.Fatal("first stream for b refused")
+	}
+	if _, ok := l.Acquire("c"); ok {
+		t.Fatal("stream allowed past the global limit")
+	}
+
+	a1()
+	a1() // release is idempotent
+	if got := l.Open(); got != 2 {
+		t.Fatalf("Open() = %d after release, want 2", got)
+	}
+	if _, ok := l.Acquire("c"); !ok {
+		t.Fatal("stream refused after a slot was released")
+	}
+}
+
+func TestSSELimiter_ZeroIsUnlimited(t *testing.T) {
+	l := NewSSELimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		if _, ok := l.Acquire("a"); !ok {
+			t.Fatalf("stream %d refused with no limits", i)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package content
 
 import (
+	"errors"
 	"log/slog"
 	"strings"
 
@@ -27,7 +28,12 @@ func HandleArchiveSubmit(sm *auth.SessionManager, dbc *db.DatabaseConnection) ec
 			return c.Redirect(302, "/")
 		}
 
-		res, err := archival.EnqueueURL(c.Request().Context(), dbc.Queries(c.Request().Context()), url, archivedByUUID)
+		opts := archival.EnqueueOptions{MetadataOnly: c.FormValue("metadata_only") != ""}
+		res, err := archival.EnqueueURLWithOptions(c.Request().Context(), dbc.Queries(c.Request().Context()), url, archivedByUUID, opts)
+		if errors.Is(err, archival.ErrDownloadsPaused) {
+			slog.Warn("download refused from home form: downloads paused", "url", url)
+			return c.Redirect(302, "/jobs")
+		}
 		if err != nil {
 			slog.Error("failed to enqueue from home form", "error", err, "url", url)
 			return c.Redirect(302, "/jobs")
@@ -1,6 +1,7 @@
 package content
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -27,6 +28,9 @@ func HandleBookmarklet(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo
 		}
 
 		res, err := archival.EnqueueURL(c.Request().Context(), dbc.Queries(c.Request().Context()), url, archivedByUUID)
+		if errors.Is(err, archival.ErrDownloadsPaused) {
+			return c.String(503, err.Error())
+		}
 		if err != nil {
 			slog.Error("failed to enqueue job from bookmarklet", "error", err, "url", url)
 			return c.String(500, "Failed to create download job")
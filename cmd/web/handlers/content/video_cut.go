@@ -9,6 +9,7 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/templates"
 	"thirdcoast.systems/rewind/internal/db"
 )
+
 // HandleVideoCutPage serves GET /videos/:id/cut, rendering the clip editor for a video.
 func HandleVideoCutPage(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -34,12 +35,13 @@ func HandleVideoCutPage(sm *auth.SessionManager, dbc *db.DatabaseConnection) ech
 		}
 
 		video := templates.VideoDetail{
-			ID:        videoData.VideoID.String(),
-			Src:       videoData.Src,
-			Title:     videoData.Title,
-			Info:      videoData.Info,
-			SpoolDir:  "",
-			CreatedAt: videoData.VideoCreatedAt.Time.Format("January 2, 2006 at 3:04 PM"),
+			ID:           videoData.VideoID.String(),
+			Src:          videoData.Src,
+			Title:        videoData.Title,
+			Info:         videoData.Info,
+			SpoolDir:     "",
+			CreatedAt:    videoData.VideoCreatedAt.Time.Format("January 2, 2006 at 3:04 PM"),
+			CaptionStyle: common.LoadCaptionStyle(c.Request().Context(), dbc, userUUID),
 		}
 
 		if videoData.SpoolDir != nil {
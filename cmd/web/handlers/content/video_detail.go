@@ -1,35 +1,29 @@
 package content
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 	"thirdcoast.systems/rewind/cmd/web/auth"
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
 	"thirdcoast.systems/rewind/cmd/web/templates"
 	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/videoinfo"
 )
 
-// streamsManifest mirrors the ingest service's StreamsManifest type.
-type streamsManifest struct {
-	Streams []struct {
-		Filename string `json:"filename"`
-		Width    int    `json:"width"`
-		Height   int    `json:"height"`
-		Codec    string `json:"codec"`
-	} `json:"streams"`
-}
-
 // readStreamsManifest reads the streams/manifest.json next to the video and
 // returns stream heights (for quality chips) and stream qualities (for the
-// player quality picker).
-func readStreamsManifest(videoPath *string) ([]int, []templates.StreamQuality) {
+// player quality picker). Extra camera angles are listed in the picker after
+// the qualities but are not quality chips. The picker leaves out streams taller
+// than maxPlaybackHeight (0 = no cap); they still count as downloaded chips.
+// Each entry records whether the browser sending userAgent can play it.
+func readStreamsManifest(videoPath *string, maxPlaybackHeight int, userAgent string) ([]int, []templates.StreamQuality) {
 	if videoPath == nil {
 		return nil, nil
 	}
@@ -37,37 +31,52 @@ func readStreamsManifest(videoPath *string) ([]int, []templates.StreamQuality) {
 	if p == "" {
 		return nil, nil
 	}
-	manifestPath := filepath.Join(filepath.Dir(p), "streams", "manifest.json")
-	data, err := os.ReadFile(manifestPath)
+	m, err := videoinfo.ReadStreamsManifest(filepath.Dir(p))
 	if err != nil {
-		return nil, nil
-	}
-	var m streamsManifest
-	if err := json.Unmarshal(data, &m); err != nil {
-		slog.Warn("failed to parse streams manifest", "path", manifestPath, "error", err)
+		slog.Warn("failed to parse streams manifest", "video_path", p, "error", err)
 		return nil, nil
 	}
 	var heights []int
-	var qualities []templates.StreamQuality
+	var qualities, angles []templates.StreamQuality
 	for _, s := range m.Streams {
-		if s.Height > 0 {
-			heights = append(heights, s.Height)
-			qualities = append(qualities, templates.StreamQuality{
-				Label:    fmt.Sprintf("%dp", s.Height),
+		if s.Angle > 0 {
+			label := fmt.Sprintf("Angle %d", s.Angle)
+			if s.Height > 0 {
+				label += fmt.Sprintf(" (%dp)", s.Height)
+			}
+			angles = append(angles, templates.StreamQuality{
+				Label:    label,
 				Filename: s.Filename,
 				Height:   s.Height,
+				Angle:    s.Angle,
+				Playable: videoinfo.StreamPlayable(s, userAgent),
 			})
+			continue
 		}
+		if s.Height > 0 {
+			heights = append(heights, s.Height)
+		}
+	}
+	for _, s := range m.Qualities(maxPlaybackHeight) {
+		qualities = append(qualities, templates.StreamQuality{
+			Label:    fmt.Sprintf("%dp", s.Height),
+			Filename: s.Filename,
+			Height:   s.Height,
+			Playable: videoinfo.StreamPlayable(s, userAgent),
+		})
 	}
-	// Sort qualities by height descending (highest first)
+	// Sort qualities by height descending (highest first), angles by number
 	sort.Slice(qualities, func(i, j int) bool {
 		return qualities[i].Height > qualities[j].Height
 	})
-	return heights, qualities
+	sort.Slice(angles, func(i, j int) bool {
+		return angles[i].Angle < angles[j].Angle
+	})
+	return heights, append(qualities, angles...)
 }
 
 // HandleVideoDetailPage serves GET /videos/:id, rendering the video player and metadata page.
-func HandleVideoDetailPage(sm *auth.SessionManager, dbc *db.DatabaseConnection) echo.HandlerFunc {
+func HandleVideoDetailPage(sm *auth.SessionManager, dbc *db.DatabaseConnection, sc *db.SettingsCache) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		userUUID, username, err := common.RequireSessionUser(c, sm)
 		if err != nil {
@@ -91,12 +100,14 @@ func HandleVideoDetailPage(sm *auth.SessionManager, dbc *db.DatabaseConnection)
 		}
 
 		var savedPosition float64
+		var watched bool
 		positionRow, err := dbc.Queries(c.Request().Context()).GetPlaybackPosition(c.Request().Context(), &db.GetPlaybackPositionParams{
 			UserID:  userUUID,
 			VideoID: videoUUID,
 		})
 		if err == nil && positionRow != nil {
 			savedPosition = positionRow.PositionSeconds
+			watched = positionRow.WatchedAt.Valid
 		}
 
 		// Check for active (queued/processing) asset regeneration or ingest jobs
@@ -114,7 +125,7 @@ func HandleVideoDetailPage(sm *auth.SessionManager, dbc *db.DatabaseConnection)
 			}
 		}
 
-		streamHeights, streamQualities := readStreamsManifest(videoRow.VideoPath)
+		streamHeights, streamQualities := readStreamsManifest(videoRow.VideoPath, int(sc.Get().PlaybackMaxHeight), c.Request().UserAgent())
 
 		video := templates.VideoDetail{
 			ID:                videoData.VideoID.String(),
@@ -126,11 +137,26 @@ func HandleVideoDetailPage(sm *auth.SessionManager, dbc *db.DatabaseConnection)
 			SpoolDir:          "",
 			CreatedAt:         videoData.VideoCreatedAt.Time.Format("January 2, 2006 at 3:04 PM"),
 			SavedPosition:     savedPosition,
+			Watched:           watched,
 			FileSize:          videoRow.FileSize,
 			ActiveRegenScopes: activeRegenScopes,
 			VideoPath:         common.DerefString(videoRow.VideoPath),
 			StreamHeights:     streamHeights,
 			StreamQualities:   streamQualities,
+			Playback:          videoinfo.PlaybackCompatibility(videoRow.ProbeData, c.Request().UserAgent()),
+			CaptionStyle:      common.LoadCaptionStyle(c.Request().Context(), dbc, userUUID),
+			MetadataOnly:      videoRow.MetadataOnly,
+			BaseURL:           c.Scheme() + "://" + c.Request().Host,
+			HasChapters:       len(common.VideoChapters(c.Request().Context(), dbc, videoRow)) > 0,
+		}
+
+		if note, err := dbc.Queries(c.Request().Context()).GetVideoNote(c.Request().Context(), &db.GetVideoNoteParams{
+			UserID:  userUUID,
+			VideoID: videoUUID,
+		}); err == nil {
+			video.Notes = note.Body
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			slog.Warn("failed to fetch video note", "error", err, "video_id", videoUUID)
 		}
 
 		// Count comments for this video
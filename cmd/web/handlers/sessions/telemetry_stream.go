@@ -207,8 +207,9 @@ func HandleProducerStream(sm *auth.SessionManager, dbc *db.DatabaseConnection, h
 				// Detect remotes leaving by pruning stale telemetry.
 				_ = hub.PruneStale(code, time.Now())
 			case <-ticker.C:
-				_, _ = fmt.Fprintf(resp, ": keepalive\n\n")
-				flusher.Flush()
+				if err := common.WriteSSEComment(c, "keepalive"); err != nil {
+					return nil // client gone
+				}
 			}
 		}
 	}
@@ -301,8 +302,9 @@ func HandlePlayerStream(sm *auth.SessionManager, dbc *db.DatabaseConnection, hub
 				flusher.Flush()
 			case <-ticker.C:
 				_ = hub.TouchRemote(code, remoteKey, time.Now())
-				_, _ = fmt.Fprintf(resp, ": keepalive\n\n")
-				flusher.Flush()
+				if err := common.WriteSSEComment(c, "keepalive"); err != nil {
+					return nil // client gone
+				}
 			}
 		}
 	}
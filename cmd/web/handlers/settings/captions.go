@@ -0,0 +1,54 @@
+package settings_api
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/encryption"
+	"thirdcoast.systems/rewind/pkg/utils/captionstyle"
+)
+
+// HandleSettingsCaptions serves POST /settings/captions, saving the user's caption display preferences.
+func HandleSettingsCaptions(sm *auth.SessionManager, dbc *db.DatabaseConnection, encMgr *encryption.Manager, sc *db.SettingsCache) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, username, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return c.Redirect(302, "/login")
+		}
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		cookies, err := q.GetUserCookies(ctx, userUUID)
+		if err != nil {
+			slog.Error("failed to fetch cookies", "error", err)
+		}
+		cookiesValue := generateCookiesFile(encMgr, cookies)
+
+		style := captionstyle.Style{
+			FontSize:   strings.TrimSpace(c.FormValue("caption_font_size")),
+			Color:      strings.TrimSpace(c.FormValue("caption_color")),
+			Background: strings.TrimSpace(c.FormValue("caption_background")),
+			Position:   strings.TrimSpace(c.FormValue("caption_position")),
+		}
+		if !captionstyle.Valid(style) {
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Invalid caption setting")
+		}
+
+		if err := q.UpsertUserCaptionStyle(ctx, &db.UpsertUserCaptionStyleParams{
+			UserID:            userUUID,
+			CaptionFontSize:   style.FontSize,
+			CaptionColor:      style.Color,
+			CaptionBackground: style.Background,
+			CaptionPosition:   style.Position,
+		}); err != nil {
+			slog.Error("failed to save caption style", "error", err)
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Failed to save caption settings")
+		}
+
+		return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Caption settings saved")
+	}
+}
@@ -0,0 +1,53 @@
+package settings_api
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/encryption"
+	"thirdcoast.systems/rewind/pkg/utils/cookiedomains"
+)
+
+// HandleSettingsCookieDomains serves POST /settings/cookie-domains, saving the
+// user's cookie domain allowlist and blocklist. The filter applies to later
+// imports; cookies already stored are left alone.
+func HandleSettingsCookieDomains(sm *auth.SessionManager, dbc *db.DatabaseConnection, encMgr *encryption.Manager, sc *db.SettingsCache) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, username, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return c.Redirect(302, "/login")
+		}
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		cookies, err := q.GetUserCookies(ctx, userUUID)
+		if err != nil {
+			slog.Error("failed to fetch cookies", "error", err)
+		}
+		cookiesValue := generateCookiesFile(encMgr, cookies)
+
+		allow, err := cookiedomains.ParseList(c.FormValue("cookie_domain_allowlist"))
+		if err != nil {
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, fmt.Sprintf("Invalid keep list: %v", err))
+		}
+		block, err := cookiedomains.ParseList(c.FormValue("cookie_domain_blocklist"))
+		if err != nil {
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, fmt.Sprintf("Invalid block list: %v", err))
+		}
+
+		if err := q.UpsertUserCookieDomainFilter(ctx, &db.UpsertUserCookieDomainFilterParams{
+			UserID:                userUUID,
+			CookieDomainAllowlist: allow,
+			CookieDomainBlocklist: block,
+		}); err != nil {
+			slog.Error("failed to save cookie domain filter", "error", err)
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Failed to save cookie domain filter")
+		}
+
+		return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Cookie domain filter saved successfully")
+	}
+}
@@ -11,8 +11,17 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/handlers/common"
 	"thirdcoast.systems/rewind/internal/db"
 	"thirdcoast.systems/rewind/pkg/encryption"
+	"thirdcoast.systems/rewind/pkg/ytdlp"
 )
 
+// cookieErrorCodes are the download error codes that uploading cookies can fix.
+var cookieErrorCodes = []string{
+	ytdlp.ErrorCodeAgeRestricted,
+	ytdlp.ErrorCodeLoginRequired,
+	ytdlp.ErrorCodeMembersOnly,
+	ytdlp.ErrorCodePrivate,
+}
+
 // HandleSettingsCookies serves POST /settings/cookies, importing Netscape-format cookies for authenticated downloads.
 func HandleSettingsCookies(sm *auth.SessionManager, dbc *db.DatabaseConnection, encMgr *encryption.Manager, sc *db.SettingsCache) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -26,12 +35,19 @@ func HandleSettingsCookies(sm *auth.SessionManager, dbc *db.DatabaseConnection,
 			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, "", "No cookies content provided")
 		}
 
+		filter, err := db.LoadCookieDomainFilter(c.Request().Context(), dbc.Queries(c.Request().Context()), userUUID)
+		if err != nil {
+			slog.Error("failed to load cookie domain filter", "error", err)
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, "", "Failed to load cookie domain filter")
+		}
+
 		normalizedContent := strings.ReplaceAll(cookiesContent, "\r\n", "\n")
 		normalizedContent = strings.ReplaceAll(normalizedContent, "\r", "\n")
 		lines := strings.Split(normalizedContent, "\n")
 
 		validCount := 0
 		invalidCount := 0
+		droppedCount := 0
 		var firstInvalidLine string
 
 		for _, line := range lines {
@@ -42,6 +58,10 @@ func HandleSettingsCookies(sm *auth.SessionManager, dbc *db.DatabaseConnection,
 
 			parts := strings.Split(trimmed, "\t")
 			if len(parts) >= 7 {
+				if !filter.Keep(parts[0]) {
+					droppedCount++
+					continue
+				}
 				expiration, err := strconv.ParseInt(parts[4], 10, 64)
 				if err != nil {
 					invalidCount++
@@ -88,6 +108,10 @@ func HandleSettingsCookies(sm *auth.SessionManager, dbc *db.DatabaseConnection,
 			}
 		}
 
+		if validCount == 0 && droppedCount > 0 && invalidCount == 0 {
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, "",
+				fmt.Sprintf("No cookies saved: all %d were for domains your cookie domain filter drops", droppedCount))
+		}
 		if validCount == 0 {
 			slog.Warn("invalid cookies format", "valid_lines", validCount, "invalid_lines", invalidCount, "first_invalid", firstInvalidLine, "user", username)
 			errMsg := fmt.Sprintf("Invalid format. Found %d invalid cookie lines. ", invalidCount)
@@ -97,7 +121,7 @@ func HandleSettingsCookies(sm *auth.SessionManager, dbc *db.DatabaseConnection,
 			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, "", errMsg)
 		}
 
-		slog.Info("cookies saved successfully", "user", username, "original_lines", len(lines), "valid_cookies", validCount, "invalid_lines", invalidCount)
+		slog.Info("cookies saved successfully", "user", username, "original_lines", len(lines), "valid_cookies", validCount, "invalid_lines", invalidCount, "dropped_by_domain", droppedCount)
 
 		cookies, err := dbc.Queries(c.Request().Context()).GetUserCookies(c.Request().Context(), userUUID)
 		if err != nil {
@@ -106,6 +130,20 @@ func HandleSettingsCookies(sm *auth.SessionManager, dbc *db.DatabaseConnection,
 		cookiesDisplay := generateCookiesFile(encMgr, cookies)
 
 		successMsg := fmt.Sprintf("Cookies saved successfully (%d valid cookies from %d total lines)", validCount, len(lines))
+		if droppedCount > 0 {
+			successMsg += fmt.Sprintf(". %d cookie(s) dropped by your cookie domain filter", droppedCount)
+		}
+
+		// Point the user at downloads that failed for lack of cookies.
+		blocked, err := dbc.Queries(c.Request().Context()).CountCookieBlockedDownloadJobs(c.Request().Context(), &db.CountCookieBlockedDownloadJobsParams{
+			ArchivedBy: userUUID,
+			ErrorCodes: cookieErrorCodes,
+		})
+		if err != nil {
+			slog.Warn("failed to count cookie-blocked jobs", "error", err)
+		} else if blocked > 0 {
+			successMsg += fmt.Sprintf(". %d failed download(s) needed login cookies; retry them from the Jobs page", blocked)
+		}
 		return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesDisplay, successMsg)
 	}
 }
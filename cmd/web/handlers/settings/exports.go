@@ -0,0 +1,49 @@
+package settings_api
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/encryption"
+	"thirdcoast.systems/rewind/pkg/utils/filename"
+)
+
+// HandleSettingsExports serves POST /settings/exports, saving the user's clip export download filename template.
+func HandleSettingsExports(sm *auth.SessionManager, dbc *db.DatabaseConnection, encMgr *encryption.Manager, sc *db.SettingsCache) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userUUID, username, err := common.RequireSessionUser(c, sm)
+		if err != nil {
+			return c.Redirect(302, "/login")
+		}
+		ctx := c.Request().Context()
+		q := dbc.Queries(ctx)
+
+		cookies, err := q.GetUserCookies(ctx, userUUID)
+		if err != nil {
+			slog.Error("failed to fetch cookies", "error", err)
+		}
+		cookiesValue := generateCookiesFile(encMgr, cookies)
+
+		tmpl := strings.TrimSpace(c.FormValue("export_filename_template"))
+		if len(tmpl) > 200 {
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Filename template is too long (max 200 characters)")
+		}
+		if !filename.ValidateTemplate(tmpl) {
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Filename template contains an unknown placeholder")
+		}
+
+		if err := q.UpsertUserExportFilenameTemplate(ctx, &db.UpsertUserExportFilenameTemplateParams{
+			UserID:                 userUUID,
+			ExportFilenameTemplate: tmpl,
+		}); err != nil {
+			slog.Error("failed to save export filename template", "error", err)
+			return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Failed to save export settings")
+		}
+
+		return renderSettingsPage(c, sm, dbc, encMgr, sc, userUUID, username, cookiesValue, "Export settings saved")
+	}
+}
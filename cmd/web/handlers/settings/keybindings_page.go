@@ -18,6 +18,7 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/templates"
 	"thirdcoast.systems/rewind/internal/db"
 	"thirdcoast.systems/rewind/pkg/encryption"
+	"thirdcoast.systems/rewind/pkg/utils/captionstyle"
 	"thirdcoast.systems/rewind/pkg/utils/crypto"
 )
 
@@ -75,7 +76,29 @@ func renderSettingsPage(c echo.Context, sm *auth.SessionManager, dbc *db.Databas
 		adminSettings = settings
 	}
 
-	return templates.Settings(cookiesValue, message, true, username, adminSettings).Render(ctx, c.Response())
+	exportTemplate, err := dbc.Queries(ctx).GetUserExportFilenameTemplate(ctx, userUUID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		slog.Error("failed to load export filename template", "error", err)
+	}
+
+	var captionStyle captionstyle.Style
+	if row, err := dbc.Queries(ctx).GetUserCaptionStyle(ctx, userUUID); err == nil {
+		captionStyle = captionstyle.Normalize(captionstyle.Style{
+			FontSize:   row.CaptionFontSize,
+			Color:      row.CaptionColor,
+			Background: row.CaptionBackground,
+			Position:   row.CaptionPosition,
+		})
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		slog.Error("failed to load caption style", "error", err)
+	}
+
+	cookieFilter, err := db.LoadCookieDomainFilter(ctx, dbc.Queries(ctx), userUUID)
+	if err != nil {
+		slog.Error("failed to load cookie domain filter", "error", err)
+	}
+
+	return templates.Settings(cookiesValue, message, true, username, exportTemplate, captionStyle, cookieFilter, adminSettings).Render(ctx, c.Response())
 }
 
 func generateCookiesFile(encMgr *encryption.Manager, cookies []*db.GetUserCookiesRow) string {
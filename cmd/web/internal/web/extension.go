@@ -18,9 +18,11 @@ import (
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/url_api"
 	"thirdcoast.systems/rewind/internal/archival"
 	"thirdcoast.systems/rewind/internal/db"
 	"thirdcoast.systems/rewind/pkg/encryption"
+	"thirdcoast.systems/rewind/pkg/utils/netaddr"
 )
 
 type extensionArchiveRequest struct {
@@ -252,6 +254,32 @@ func (s *Webserver) HandleAPIExtensionStatus(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// HandleAPIExtensionExists serves GET /api/extension/exists?url=<url>, letting the
+// extension show "already archived" instead of offering to archive again.
+func (s *Webserver) HandleAPIExtensionExists(c echo.Context) error {
+	if _, _, err := s.requireExtensionBearerToken(c); err != nil {
+		return err
+	}
+
+	raw := strings.TrimSpace(c.QueryParam("url"))
+	if raw == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "url is required"})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := url_api.LookupArchived(ctx, s.dbc.Queries(ctx), raw)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return c.JSON(httpErr.Code, map[string]any{"error": httpErr.Message})
+		}
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // HandleAPIExtensionCookies serves POST /api/extension/cookies, importing Netscape-format cookies uploaded by the browser extension.
 func (s *Webserver) HandleAPIExtensionCookies(c echo.Context) error {
 	user, _, err := s.requireExtensionBearerToken(c)
@@ -274,7 +302,7 @@ func (s *Webserver) HandleAPIExtensionCookies(c echo.Context) error {
 	}
 
 	// Parse and store cookies
-	validCount, invalidCount, err := s.parseCookiesAndStore(c.Request().Context(), user.ID, req.CookiesContent)
+	validCount, invalidCount, droppedCount, err := s.parseCookiesAndStore(c.Request().Context(), user.ID, req.CookiesContent)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": fmt.Sprintf("failed to store cookies: %v", err),
@@ -282,8 +310,9 @@ func (s *Webserver) HandleAPIExtensionCookies(c echo.Context) error {
 	}
 
 	if validCount == 0 {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": fmt.Sprintf("no valid cookies found (%d invalid lines)", invalidCount),
+		return c.JSON(http.StatusBadRequest, map[string]any{
+			"error":         fmt.Sprintf("no valid cookies found (%d invalid lines, %d dropped by domain filter)", invalidCount, droppedCount),
+			"dropped_count": droppedCount,
 		})
 	}
 
@@ -291,11 +320,19 @@ func (s *Webserver) HandleAPIExtensionCookies(c echo.Context) error {
 		"status":        "ok",
 		"valid_count":   validCount,
 		"invalid_count": invalidCount,
+		"dropped_count": droppedCount,
 	})
 }
 
-// parseCookiesAndStore parses Netscape cookie format and stores in database
-func (s *Webserver) parseCookiesAndStore(ctx context.Context, userUUID pgtype.UUID, cookiesContent string) (validCount, invalidCount int, err error) {
+// parseCookiesAndStore parses Netscape cookie format and stores in database.
+// Cookies for domains the user's cookie domain filter drops are counted in
+// droppedCount and never stored.
+func (s *Webserver) parseCookiesAndStore(ctx context.Context, userUUID pgtype.UUID, cookiesContent string) (validCount, invalidCount, droppedCount int, err error) {
+	filter, err := db.LoadCookieDomainFilter(ctx, s.dbc.Queries(ctx), userUUID)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("load cookie domain filter: %w", err)
+	}
+
 	// Normalize line endings (handle Windows CRLF, Unix LF, old Mac CR)
 	normalizedContent := strings.ReplaceAll(cookiesContent, "\r\n", "\n")
 	normalizedContent = strings.ReplaceAll(normalizedContent, "\r", "\n")
@@ -312,6 +349,11 @@ func (s *Webserver) parseCookiesAndStore(ctx context.Context, userUUID pgtype.UU
 		// Netscape format: domain	flag	path	secure	expiration	name	value
 		parts := strings.Split(trimmed, "\t")
 		if len(parts) >= 7 {
+			if !filter.Keep(parts[0]) {
+				droppedCount++
+				continue
+			}
+
 			// Parse expiration to int64
 			expiration, err := strconv.ParseInt(parts[4], 10, 64)
 			if err != nil {
@@ -349,7 +391,7 @@ func (s *Webserver) parseCookiesAndStore(ctx context.Context, userUUID pgtype.UU
 		}
 	}
 
-	return validCount, invalidCount, nil
+	return validCount, invalidCount, droppedCount, nil
 }
 
 // HandleAPIExtensionStatusStream returns an SSE stream of status updates for the extension
@@ -464,6 +506,9 @@ func (s *Webserver) HandleAPIExtensionArchive(c echo.Context) error {
 	}
 
 	res, err := archival.EnqueueURL(c.Request().Context(), s.dbc.Queries(c.Request().Context()), req.URL, user.ID)
+	if errors.Is(err, archival.ErrDownloadsPaused) {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+	}
 	if err != nil {
 		slog.Error("failed to enqueue job from extension", "error", err, "url", req.URL)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to enqueue job"})
@@ -556,42 +601,7 @@ func isLocalOrPrivateRequestHost(c echo.Context) bool {
 	if h, _, err := net.SplitHostPort(hostHeader); err == nil {
 		host = h
 	}
-	host = strings.ToLower(strings.TrimSpace(host))
-
-	if host == "localhost" {
-		return true
-	}
-	if ip := net.ParseIP(host); ip != nil {
-		if ip.IsLoopback() {
-			return true
-		}
-		if ip4 := ip.To4(); ip4 != nil {
-			// RFC1918 + link-local
-			switch {
-			case ip4[0] == 10:
-				return true
-			case ip4[0] == 172 && ip4[1] >= 16 && ip4[1] <= 31:
-				return true
-			case ip4[0] == 192 && ip4[1] == 168:
-				return true
-			case ip4[0] == 169 && ip4[1] == 254:
-				return true
-			default:
-				return false
-			}
-		}
-		// IPv6: accept loopback already handled; treat ULA (fc00::/7) and link-local (fe80::/10) as local.
-		if len(ip) == net.IPv6len {
-			if ip[0]&0xfe == 0xfc { // fc00::/7
-				return true
-			}
-			if ip[0] == 0xfe && (ip[1]&0xc0) == 0x80 { // fe80::/10
-				return true
-			}
-		}
-	}
-
-	return false
+	return netaddr.IsLocalOrPrivateHost(host)
 }
 
 // createExtensionToken generates a random token, persists it in the DB, and returns the token string.
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -14,6 +15,7 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/ctxkeys"
 	"thirdcoast.systems/rewind/cmd/web/handlers/admin"
 	authhandlers "thirdcoast.systems/rewind/cmd/web/handlers/auth"
+	"thirdcoast.systems/rewind/cmd/web/handlers/common"
 	"thirdcoast.systems/rewind/cmd/web/handlers/content"
 	"thirdcoast.systems/rewind/cmd/web/handlers/sessions"
 	settingspage "thirdcoast.systems/rewind/cmd/web/handlers/settings"
@@ -23,16 +25,20 @@ import (
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/home_api"
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/job_api"
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/marker_api"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/playlist_api"
 	settingsapi "thirdcoast.systems/rewind/cmd/web/handlers/api/settings_api"
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/stitch_api"
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/tag_api"
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/upload_api"
+	"thirdcoast.systems/rewind/cmd/web/handlers/api/url_api"
 	"thirdcoast.systems/rewind/cmd/web/handlers/api/video_api"
 
 	"thirdcoast.systems/rewind/cmd/web/internal/producer"
 	"thirdcoast.systems/rewind/cmd/web/internal/telemetry"
 	staticpkg "thirdcoast.systems/rewind/cmd/web/internal/web/utils/static"
+	"thirdcoast.systems/rewind/internal/archival"
 	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/internal/diskmonitor"
 	"thirdcoast.systems/rewind/pkg/encryption"
 )
 
@@ -41,6 +47,9 @@ type Webserver struct {
 	*echo.Echo
 	sessionManager      *auth.SessionManager
 	encryptionManager   *encryption.Manager
+	hlsKeys             *video_api.HLSKeys
+	hlsCache            *video_api.HLSCache
+	audioTracks         *video_api.AudioTrackCache
 	dbc                 *db.DatabaseConnection
 	staticCache         *staticpkg.StaticCache
 	fileServer          *fileserver.FileServer
@@ -48,6 +57,11 @@ type Webserver struct {
 	telemetryHub        *telemetry.Hub
 	sceneHub            *producer.SceneHub
 	allowedExtensionIDs map[string]struct{}
+	diskMonitor         *diskmonitor.Monitor
+	// sseLimiter caps concurrently open SSE streams per user and in total.
+	sseLimiter *common.SSELimiter
+	// registrationWebhookURL receives a POST for each sign-up awaiting approval.
+	registrationWebhookURL string
 }
 
 // NewWebserver initializes the Echo server, registers all routes and middleware, and returns a ready-to-start Webserver.
@@ -66,10 +80,14 @@ func NewWebserver(ctx context.Context, dbc *db.DatabaseConnection, encryptionMan
 		return nil, err
 	}
 
+	hlsKeys := video_api.NewHLSKeys(dbc, encryptionManager)
 	webserver := &Webserver{
 		Echo:                e,
 		sessionManager:      sessionManager,
 		encryptionManager:   encryptionManager,
+		hlsKeys:             hlsKeys,
+		hlsCache:            video_api.NewHLSCache(dbc, hlsKeys),
+		audioTracks:         video_api.NewAudioTrackCache(),
 		dbc:                 dbc,
 		staticCache:         staticCache,
 		fileServer:          fileserver.NewFileServer(),
@@ -77,12 +95,21 @@ func NewWebserver(ctx context.Context, dbc *db.DatabaseConnection, encryptionMan
 		telemetryHub:        telemetry.NewHub(),
 		sceneHub:            producer.NewSceneHub(),
 		allowedExtensionIDs: parseCommaSeparatedSet(os.Getenv("EXTENSION_ALLOWED_CLIENT_IDS")),
+		diskMonitor:         diskmonitor.New(diskmonitor.ConfigFromEnv()),
+		sseLimiter:          common.NewSSELimiter(envInt("SSE_MAX_STREAMS", 1000), envInt("SSE_MAX_STREAMS_PER_USER", 16)),
+
+		registrationWebhookURL: strings.TrimSpace(os.Getenv("REGISTRATION_WEBHOOK_URL")),
 	}
 
 	if len(webserver.allowedExtensionIDs) == 0 {
 		slog.Info("EXTENSION_ALLOWED_CLIENT_IDS not set; extension CORS will be allowed only on localhost/private IP")
 	}
 
+	// Check storage before serving so a full disk pauses downloads from the first request.
+	webserver.diskMonitor.Check()
+	go webserver.diskMonitor.Run(ctx)
+	archival.DownloadsPaused = webserver.diskMonitor.DownloadsPaused
+
 	if err = webserver.registerRoutes(); err != nil {
 		return nil, err
 	}
@@ -106,6 +133,16 @@ func parseCommaSeparatedSet(raw string) map[string]struct{} {
 	return set
 }
 
+// envInt reads a non-negative integer from the environment, falling back to
+// def when unset or invalid.
+func envInt(name string, def int) int {
+	v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil || v < 0 {
+		return def
+	}
+	return v
+}
+
 // securityHeaders adds standard security and privacy headers to every response.
 func securityHeaders(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -225,6 +262,9 @@ func (s *Webserver) setupMiddleware() error {
 }
 
 func (s *Webserver) registerRoutes() error {
+	// sseLimit wraps long-lived SSE GET routes with the stream limiter.
+	sseLimit := s.sseLimiter.Middleware(s.sessionManager)
+
 	adminGroup := s.Group("/admin")
 	adminGroup.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -249,20 +289,31 @@ func (s *Webserver) registerRoutes() error {
 		}
 	})
 
-	adminGroup.GET("", admin.HandleAdminHomePage(s.sessionManager, s.dbc))
+	adminGroup.GET("", admin.HandleAdminHomePage(s.sessionManager, s.dbc, s.diskMonitor))
+	adminGroup.GET("/disk", admin.HandleAdminDiskStatus(s.diskMonitor))
 	adminGroup.GET("/settings", admin.HandleAdminSettingsPage())
 	adminGroup.POST("/settings", admin.HandleAdminSettings(s.sessionManager, s.dbc, s.settingsCache))
 	adminGroup.GET("/users", admin.HandleAdminUsersPage(s.sessionManager, s.dbc))
 	adminGroup.POST("/users/:id/enable", admin.HandleAdminUserEnable(s.sessionManager, s.dbc))
 	adminGroup.POST("/users/:id/role", admin.HandleAdminUserRole(s.sessionManager, s.dbc))
+	adminGroup.POST("/users/:id/download-quality", admin.HandleAdminUserDownloadQuality(s.sessionManager, s.dbc))
 	adminGroup.POST("/refresh-assets", admin.HandleAdminRefreshAssets(s.sessionManager, s.dbc))
+	adminGroup.POST("/channels/:id/regenerate-assets", admin.HandleAdminChannelRegenerateAssets(s.sessionManager, s.dbc))
+	adminGroup.GET("/channels/:id/regenerate-assets", admin.HandleAdminChannelRegenerateProgress(s.sessionManager, s.dbc))
 	// Asset health
 	adminGroup.GET("/asset-health", admin.HandleAdminAssetHealthPage(s.sessionManager, s.dbc))
+	adminGroup.GET("/archive-manifest", admin.HandleAdminArchiveManifest(s.sessionManager, s.dbc))
 	adminGroup.POST("/asset-health/:id/retry", admin.HandleAdminAssetHealthRetry(s.sessionManager, s.dbc))
 	adminGroup.POST("/asset-health/retry-all", admin.HandleAdminAssetHealthRetryAll(s.sessionManager, s.dbc))
+	adminGroup.POST("/assets/backfill-previews", admin.HandleAdminBackfillPreviews(s.sessionManager, s.dbc))
+	adminGroup.GET("/duplicates", admin.HandleAdminDuplicatesPage(s.sessionManager, s.dbc))
+	adminGroup.GET("/captions", admin.HandleAdminCaptionsPage(s.sessionManager, s.dbc))
+	adminGroup.GET("/captions/progress", admin.HandleAdminCaptionsProgress(s.sessionManager, s.dbc))
+	adminGroup.POST("/captions/backfill", admin.HandleAdminCaptionsBackfill(s.sessionManager, s.dbc))
 	// Exports management
 	adminGroup.GET("/exports", admin.HandleAdminExportsPage(s.sessionManager, s.dbc))
 	adminGroup.GET("/exports/index", admin.HandleAdminExportsIndex(s.sessionManager, s.dbc))
+	adminGroup.GET("/exports/throughput", admin.HandleAdminExportsThroughput(s.sessionManager, s.dbc), sseLimit)
 	adminGroup.POST("/exports/delete-all", admin.HandleAdminExportsDeleteAll(s.sessionManager, s.dbc))
 	adminGroup.POST("/exports/delete/:status", admin.HandleAdminExportsDeleteByStatus(s.sessionManager, s.dbc))
 	adminGroup.POST("/exports/requeue-errors", admin.HandleAdminExportsRequeueErrors(s.sessionManager, s.dbc))
@@ -275,35 +326,63 @@ func (s *Webserver) registerRoutes() error {
 	apiGroup.GET("/home/recent-clips", home_api.HandleRecentClips(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/index", video_api.HandleIndex(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/recent", video_api.HandleRecent(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/exists", url_api.HandleVideoExists(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/:id/stream", video_api.HandleStream(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/playback", video_api.HandlePlayback(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/:id/streams/:filename", video_api.HandleStreamFile(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/hls/key", video_api.HandleHLSKey(s.sessionManager, s.hlsKeys))
+	apiGroup.GET("/videos/:id/hls/:file", video_api.HandleHLSFile(s.sessionManager, s.dbc, s.fileServer))
+	apiGroup.GET("/playlists/:id/hls/master.m3u8", playlist_api.HandleHLSMaster(s.sessionManager, s.dbc, s.hlsCache))
 	apiGroup.GET("/videos/:id/thumbnail", video_api.HandleThumbnail(s.sessionManager, s.dbc, s.fileServer))
 	apiGroup.GET("/videos/:id/preview.mp4", video_api.HandlePreview(s.sessionManager, s.dbc, s.fileServer))
+	apiGroup.GET("/videos/:id/preview-sprite.json", video_api.HandlePreviewSpriteManifest(s.sessionManager, s.dbc, s.fileServer))
+	apiGroup.GET("/videos/:id/preview-sprite.jpg", video_api.HandlePreviewSpriteImage(s.sessionManager, s.dbc, s.fileServer))
+	apiGroup.GET("/videos/:id/assets.json", video_api.HandleAssetManifest(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/:id/seek/seek.json", video_api.HandleSeekManifest(s.sessionManager, s.dbc, s.fileServer))
 	apiGroup.GET("/videos/:id/seek/levels/:level/seek.vtt", video_api.HandleSeekVTT(s.sessionManager, s.dbc, s.fileServer))
 	apiGroup.GET("/videos/:id/seek/levels/:level/:sheet", video_api.HandleSeekSheet(s.sessionManager, s.dbc, s.fileServer))
 	apiGroup.GET("/videos/:id/waveform/waveform.json", video_api.HandleWaveformManifest(s.sessionManager, s.dbc, s.fileServer))
 	apiGroup.GET("/videos/:id/waveform/peaks.i16", video_api.HandleWaveformPeaks(s.sessionManager, s.dbc, s.fileServer))
 	apiGroup.GET("/videos/:id/captions.vtt", video_api.HandleCaptions(s.sessionManager, s.dbc, s.fileServer))
-	apiGroup.GET("/videos/:id/download", video_api.HandleDownload(s.sessionManager, s.dbc, s.fileServer))
+	apiGroup.GET("/videos/:id/captions.srt", video_api.HandleCaptionsSRT(s.sessionManager, s.dbc, s.fileServer))
+	apiGroup.POST("/videos/:id/captions/shift", video_api.HandleCaptionsShift(s.sessionManager, s.dbc))
+	apiGroup.POST("/videos/:id/filter-preview", video_api.HandleFilterPreview(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/description/timestamps", video_api.HandleDescriptionTimestamps(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/chapters.vtt", video_api.HandleChaptersVTT(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/chapters", video_api.HandleChapters(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/chapters/render", video_api.HandleChaptersRender(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/chapters/:index/poster.jpg", video_api.HandleChapterPoster(s.sessionManager, s.dbc, s.fileServer))
+	apiGroup.GET("/videos/:id/download", video_api.HandleDownload(s.sessionManager, s.dbc, s.fileServer, s.audioTracks))
 	apiGroup.GET("/videos/:id/markers", video_api.HandleMarkers(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/:id/markers/render", video_api.HandleMarkersRender(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/:id/comments/render", video_api.HandleCommentsRender(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/:id/tags/render", tag_api.HandleTagsRender(s.sessionManager, s.dbc))
 	apiGroup.POST("/videos/:id/tags", tag_api.HandleAddTag(s.sessionManager, s.dbc))
 	apiGroup.DELETE("/videos/:id/tags/:tagId", tag_api.HandleRemoveTag(s.sessionManager, s.dbc))
+	apiGroup.GET("/transcripts/export", video_api.HandleTranscriptsExport(s.sessionManager, s.dbc))
 	apiGroup.GET("/tags", tag_api.HandleListTags(s.sessionManager, s.dbc))
+	apiGroup.GET("/tags/scraped", tag_api.HandleListScrapedTags(s.sessionManager, s.dbc))
 	apiGroup.POST("/videos/bulk-tag", tag_api.HandleBulkTag(s.sessionManager, s.dbc))
-	apiGroup.GET("/videos/:id/transcript/render", video_api.HandleTranscriptRender(s.sessionManager))
+	apiGroup.GET("/videos/:id/transcript/render", video_api.HandleTranscriptRender(s.sessionManager, s.dbc))
 	apiGroup.POST("/videos/:id/markers", video_api.HandleMarkersUpdate(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/:id/clips", video_api.HandleClips(s.sessionManager, s.dbc))
 	apiGroup.POST("/videos/:id/clips", video_api.HandleClipsCreate(s.sessionManager, s.dbc))
+	apiGroup.POST("/videos/:id/import-edits", video_api.HandleImportEdits(s.sessionManager, s.dbc))
 	apiGroup.POST("/videos/:id/redownload", video_api.HandleRedownload(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/formats", video_api.HandleFormats(s.sessionManager, s.dbc))
 	apiGroup.POST("/videos/:id/download-format", video_api.HandleDownloadFormat(s.sessionManager, s.dbc))
 	apiGroup.POST("/videos/:id/regenerate-assets", video_api.HandleRegenerateAssets(s.sessionManager, s.dbc))
+	apiGroup.POST("/videos/:id/regenerate-streams-manifest", video_api.HandleRegenerateStreamsManifest(s.sessionManager, s.dbc))
 	apiGroup.DELETE("/videos/:id", video_api.HandleDelete(s.sessionManager, s.dbc))
 	apiGroup.GET("/videos/:id/jobs", video_api.HandleJobs(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/related", video_api.HandleRelated(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/related/render", video_api.HandleRelatedRender(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/duplicates/render", video_api.HandleDuplicatesRender(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/position", settingsapi.HandleGetPlaybackPosition(s.sessionManager, s.dbc))
 	apiGroup.POST("/videos/:id/position", settingsapi.HandleSavePlaybackPosition(s.sessionManager, s.dbc))
+	apiGroup.PUT("/videos/:id/watched", settingsapi.HandleSetWatched(s.sessionManager, s.dbc))
+	apiGroup.GET("/videos/:id/notes", video_api.HandleGetNotes(s.sessionManager, s.dbc))
+	apiGroup.PUT("/videos/:id/notes", video_api.HandlePutNotes(s.sessionManager, s.dbc))
 
 	apiGroup.PUT("/markers/:id", marker_api.HandleCreateOrUpdate(s.sessionManager, s.dbc))
 	apiGroup.DELETE("/markers/:id", marker_api.HandleDelete(s.sessionManager, s.dbc))
@@ -317,34 +396,49 @@ func (s *Webserver) registerRoutes() error {
 	apiGroup.PUT("/clips/:clipId/crops/:cropId", clip_api.HandleCropUpdate(s.sessionManager, s.dbc))
 	apiGroup.DELETE("/clips/:clipId/crops/:cropId", clip_api.HandleCropDelete(s.sessionManager, s.dbc))
 	apiGroup.PUT("/clips/:clipId/shot-list", clip_api.HandleShotListUpdate(s.sessionManager, s.dbc))
+	apiGroup.GET("/clips/:id/edl", clip_api.HandleClipEDL(s.sessionManager, s.dbc))
+	apiGroup.GET("/clips/:id/thumbnail.jpg", clip_api.HandleClipThumbnail(s.sessionManager, s.dbc, s.fileServer))
 	apiGroup.POST("/clips/:clipId/multicam-export", clip_api.HandleMulticamExport(s.sessionManager, s.dbc))
-	apiGroup.POST("/clips/:id/exports", clip_api.HandleEnqueueExport(s.sessionManager, s.dbc))
-	apiGroup.GET("/clip-exports/:id/stream", clip_api.HandleExportStatusStream(s.sessionManager, s.dbc))
+	apiGroup.POST("/clips/:id/exports", clip_api.HandleEnqueueExport(s.sessionManager, s.dbc, s.sseLimiter))
+	apiGroup.POST("/exports/batch", clip_api.HandleBatchExport(s.sessionManager, s.dbc))
+	apiGroup.POST("/exports/concat", clip_api.HandleEnqueueConcatExport(s.sessionManager, s.dbc))
+	apiGroup.GET("/clip-exports/:id/stream", clip_api.HandleExportStatusStream(s.sessionManager, s.dbc), sseLimit)
 	apiGroup.GET("/clip-exports/:id/download", clip_api.HandleDownloadExport(s.sessionManager, s.dbc))
+	apiGroup.GET("/clip-exports/:id/progress-history", clip_api.HandleExportProgressHistory(s.sessionManager, s.dbc))
+	apiGroup.POST("/overlay-images", clip_api.HandleUploadOverlayImage(s.sessionManager), middleware.BodyLimit("6M"))
+	apiGroup.GET("/overlay-images", clip_api.HandleListOverlayImages(s.sessionManager))
+	apiGroup.GET("/overlay-images/:id", clip_api.HandleGetOverlayImage(s.sessionManager))
+	apiGroup.DELETE("/overlay-images/:id", clip_api.HandleDeleteOverlayImage(s.sessionManager))
+	apiGroup.POST("/luts", clip_api.HandleUploadLUT(s.sessionManager), middleware.BodyLimit("17M"))
+	apiGroup.GET("/luts", clip_api.HandleListLUTs(s.sessionManager))
+	apiGroup.DELETE("/luts/:id", clip_api.HandleDeleteLUT(s.sessionManager))
 	apiGroup.GET("/videos/:videoId/clips/export-status", clip_api.HandleBankExportStatus(s.sessionManager, s.dbc))
 
 	// Cut page SSE endpoints
 	apiGroup.POST("/videos/:id/cut/filter-cards", video_api.HandleFilterCards())
 
 	apiGroup.POST("/upload", upload_api.HandleUpload(s.sessionManager, s.dbc), middleware.BodyLimit("10G"))
+	apiGroup.GET("/url/canonical", url_api.HandleCanonical(s.sessionManager))
 	apiGroup.POST("/download-jobs", job_api.HandleCreateDownload(s.sessionManager, s.dbc))
 	apiGroup.POST("/jobs/:id/retry", job_api.HandleRetry(s.sessionManager, s.dbc))
+	apiGroup.POST("/jobs/cancel-all", job_api.HandleCancelAll(s.sessionManager, s.dbc))
 	apiGroup.POST("/jobs/:id/cancel", job_api.HandleCancel(s.sessionManager, s.dbc))
+	apiGroup.POST("/jobs/:id/prioritize", job_api.HandlePrioritize(s.sessionManager, s.dbc))
 	apiGroup.POST("/jobs/:id/archive", job_api.HandleArchive(s.sessionManager, s.dbc))
 	apiGroup.POST("/jobs/:id/unarchive", job_api.HandleUnarchive(s.sessionManager, s.dbc))
 	apiGroup.POST("/jobs/archive", job_api.HandleArchiveBatch(s.sessionManager, s.dbc))
 	apiGroup.GET("/jobs/index", job_api.HandleIndex(s.sessionManager, s.dbc))
-	apiGroup.GET("/jobs/stream", job_api.HandleStream(s.sessionManager, s.dbc))
-	apiGroup.GET("/jobs/:id/status", job_api.HandleStatus(s.sessionManager, s.dbc))
+	apiGroup.GET("/jobs/stream", job_api.HandleStream(s.sessionManager, s.dbc), sseLimit)
+	apiGroup.GET("/jobs/:id/status", job_api.HandleStatus(s.sessionManager, s.dbc), sseLimit)
 	apiGroup.GET("/jobs/:id/logs", job_api.HandleLogs(s.sessionManager, s.dbc))
-	apiGroup.GET("/jobs/:id/logs/stream", job_api.HandleLogsStream(s.sessionManager, s.dbc))
+	apiGroup.GET("/jobs/:id/logs/stream", job_api.HandleLogsStream(s.sessionManager, s.dbc), sseLimit)
 
 	apiGroup.POST("/settings/keybindings", settingsapi.HandleKeybindingUpdate(s.sessionManager, s.dbc))
 	apiGroup.DELETE("/settings/keybindings/:action", settingsapi.HandleKeybindingDelete(s.sessionManager, s.dbc))
 	apiGroup.POST("/settings/keybindings/reset", settingsapi.HandleKeybindingReset(s.sessionManager, s.dbc))
 
-	apiGroup.GET("/player-sessions/:code/producer/stream", sessions.HandleProducerStream(s.sessionManager, s.dbc, s.telemetryHub))
-	apiGroup.GET("/player-sessions/:code/player/stream", sessions.HandlePlayerStream(s.sessionManager, s.dbc, s.telemetryHub, s.sceneHub))
+	apiGroup.GET("/player-sessions/:code/producer/stream", sessions.HandleProducerStream(s.sessionManager, s.dbc, s.telemetryHub), sseLimit)
+	apiGroup.GET("/player-sessions/:code/player/stream", sessions.HandlePlayerStream(s.sessionManager, s.dbc, s.telemetryHub, s.sceneHub), sseLimit)
 	apiGroup.POST("/player-sessions/:code/player/telemetry", sessions.HandlePlayerTelemetry(s.telemetryHub))
 
 	apiGroup.DELETE("/player-sessions/:id", sessions.HandleDeletePlayerSession(s.sessionManager, s.dbc))
@@ -355,7 +449,8 @@ func (s *Webserver) registerRoutes() error {
 	extensionAPIGroup.GET("/auth/start", s.HandleAPIExtensionAuthStart)
 	extensionAPIGroup.GET("/auth/finish", s.HandleAPIExtensionAuthFinish)
 	extensionAPIGroup.GET("/status", s.HandleAPIExtensionStatus)
-	extensionAPIGroup.GET("/status/stream", s.HandleAPIExtensionStatusStream)
+	extensionAPIGroup.GET("/status/stream", s.HandleAPIExtensionStatusStream, sseLimit)
+	extensionAPIGroup.GET("/exists", s.HandleAPIExtensionExists)
 	extensionAPIGroup.POST("/archive", s.HandleAPIExtensionArchive)
 	extensionAPIGroup.POST("/cookies", s.HandleAPIExtensionCookies)
 	extensionAPIGroup.POST("/logout", s.HandleAPIExtensionLogout)
@@ -367,6 +462,9 @@ func (s *Webserver) registerRoutes() error {
 	settingsGroup.GET("/cookies/download", settingspage.HandleSettingsDownloadCookies(s.sessionManager, s.dbc, s.encryptionManager))
 	settingsGroup.POST("/cookies/delete", settingspage.HandleSettingsDeleteCookies(s.sessionManager, s.dbc, s.encryptionManager, s.settingsCache))
 	settingsGroup.POST("/interface", settingspage.HandleSettingsInterface(s.sessionManager, s.dbc, s.encryptionManager, s.settingsCache))
+	settingsGroup.POST("/exports", settingspage.HandleSettingsExports(s.sessionManager, s.dbc, s.encryptionManager, s.settingsCache))
+	settingsGroup.POST("/captions", settingspage.HandleSettingsCaptions(s.sessionManager, s.dbc, s.encryptionManager, s.settingsCache))
+	settingsGroup.POST("/cookie-domains", settingspage.HandleSettingsCookieDomains(s.sessionManager, s.dbc, s.encryptionManager, s.settingsCache))
 	settingsGroup.GET("/keybindings", settingspage.HandleSettingsKeybindingsPage(s.sessionManager, s.dbc))
 
 	producerGroup := s.Group("/producer")
@@ -414,14 +512,14 @@ func (s *Webserver) registerRoutes() error {
 	s.GET("/login", authhandlers.HandleLoginPage(s.sessionManager, s.dbc))
 	s.POST("/login", authhandlers.HandleLogin(s.sessionManager, s.dbc))
 	s.GET("/register", authhandlers.HandleRegisterPage(s.sessionManager, s.dbc, s.settingsCache))
-	s.POST("/register", authhandlers.HandleRegister(s.sessionManager, s.dbc, s.settingsCache))
+	s.POST("/register", authhandlers.HandleRegister(s.sessionManager, s.dbc, s.settingsCache, s.registrationWebhookURL))
 	s.GET("/logout", authhandlers.HandleLogout(s.sessionManager))
 
 	// Stitch routes
 	apiGroup.GET("/stitch/sources", stitch_api.HandleStitchSourceBrowser(s.sessionManager, s.dbc))
 	apiGroup.POST("/stitch/enqueue", stitch_api.HandleStitchEnqueue(s.sessionManager, s.dbc))
 	apiGroup.GET("/stitch/:id/download", stitch_api.HandleStitchDownload(s.sessionManager, s.dbc))
-	apiGroup.GET("/stitch/:id/stream", stitch_api.HandleStitchStream(s.sessionManager, s.dbc))
+	apiGroup.GET("/stitch/:id/stream", stitch_api.HandleStitchStream(s.sessionManager, s.dbc), sseLimit)
 	apiGroup.POST("/stitch/projects", stitch_api.HandleCreateProject(s.sessionManager, s.dbc))
 	apiGroup.PUT("/stitch/projects/:id", stitch_api.HandleSaveProject(s.sessionManager, s.dbc))
 	apiGroup.DELETE("/stitch/projects/:id", stitch_api.HandleDeleteProject(s.sessionManager, s.dbc))
@@ -437,7 +535,8 @@ func (s *Webserver) registerRoutes() error {
 	s.GET("/jobs/:id", content.HandleJobDetailPage(s.sessionManager, s.dbc))
 	s.GET("/videos", content.HandleVideosPage(s.sessionManager, s.dbc))
 	s.GET("/videos/:id/cut", content.HandleVideoCutPage(s.sessionManager, s.dbc))
-	s.GET("/videos/:id", content.HandleVideoDetailPage(s.sessionManager, s.dbc))
+	s.GET("/videos/:id", content.HandleVideoDetailPage(s.sessionManager, s.dbc, s.settingsCache))
+	s.GET("/videos/:id/og-image.jpg", video_api.HandleOGImage(s.sessionManager, s.dbc, s.fileServer))
 	s.GET("/upload", content.HandleUploadPage(s.sessionManager))
 	s.GET("/bookmarklet", content.HandleBookmarklet(s.sessionManager, s.dbc))
 	s.GET("/", content.HandleHomePage(s.sessionManager))
@@ -32,6 +32,12 @@ func main() {
 		conf.DatabaseRetries = 10
 	}
 
+	// Filter previews use the filter compiler; link previews use drawtext.
+	if _, err := application.InitFFmpeg(ctx, "colortemperature", "drawtext"); err != nil {
+		slog.Error("ffmpeg unavailable", "error", err)
+		os.Exit(1)
+	}
+
 	pool, err := application.OpenDBPoolWithRetry(ctx, *conf)
 	if err != nil {
 		slog.Error("failed to connect to database", "error", err)
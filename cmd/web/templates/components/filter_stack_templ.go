@@ -55,6 +55,7 @@ func FilterStack(cfg filters.FilterConfig) templ.Component {
 			{Type: "hflip", Label: "Flip Horizontal", Icon: "arrows-left-right"},
 			{Type: "vflip", Label: "Flip Vertical", Icon: "arrows-up-down"},
 			{Type: "pad", Label: "Pad / Letterbox", Icon: "border-all"},
+			{Type: "chromakey", Label: "Chroma Key (green screen)", Icon: "user-slash"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -70,11 +71,13 @@ func FilterStack(cfg filters.FilterConfig) templ.Component {
 			{Type: "color_balance", Label: "Color Balance", Icon: "swatchbook"},
 			{Type: "curves", Label: "Curves Preset", Icon: "bezier-curve"},
 			{Type: "lut", Label: "LUT Preset", Icon: "film"},
+			{Type: "lut_file", Label: "LUT File (.cube)", Icon: "file-import"},
 			{Type: "grayscale", Label: "Grayscale", Icon: "droplet-slash"},
 			{Type: "sepia", Label: "Sepia", Icon: "image"},
 			{Type: "sharpen", Label: "Sharpen", Icon: "diamond"},
 			{Type: "denoise", Label: "Denoise", Icon: "wand-magic-sparkles"},
 			{Type: "vignette", Label: "Vignette", Icon: "bullseye"},
+			{Type: "blur", Label: "Blur", Icon: "droplet"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -84,6 +87,8 @@ func FilterStack(cfg filters.FilterConfig) templ.Component {
 			{Type: "fade_in", Label: "Fade In", Icon: "right-long"},
 			{Type: "fade_out", Label: "Fade Out", Icon: "left-long"},
 			{Type: "reverse", Label: "Reverse", Icon: "backward"},
+			{Type: "ken_burns", Label: "Ken Burns (zoom/pan)", Icon: "magnifying-glass-plus"},
+			{Type: "stabilize", Label: "Stabilize", Icon: "hand"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -107,6 +112,9 @@ func FilterStack(cfg filters.FilterConfig) templ.Component {
 		}
 		templ_7745c5c3_Err = FilterCategoryMenu(cfg, "Overlay", []FilterMenuItem{
 			{Type: "text", Label: "Text / Watermark", Icon: "font"},
+			{Type: "timecode", Label: "Timecode Burn-in", Icon: "clock"},
+			{Type: "image_overlay", Label: "Image Overlay", Icon: "image"},
+			{Type: "subtitles", Label: "Burn-in Subtitles", Icon: "closed-captioning"},
 		}).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
@@ -148,7 +156,7 @@ func FilterCategoryMenu(cfg filters.FilterConfig, category string, items []Filte
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(category)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 86, Col: 13}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 94, Col: 13}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -166,7 +174,7 @@ func FilterCategoryMenu(cfg filters.FilterConfig, category string, items []Filte
 			var templ_7745c5c3_Var4 string
 			templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.ResolveAttributeValue(filters.FilterAddExpr(item.Type, cfg))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 92, Col: 57}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 100, Col: 57}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var4)
 			if templ_7745c5c3_Err != nil {
@@ -201,7 +209,7 @@ func FilterCategoryMenu(cfg filters.FilterConfig, category string, items []Filte
 			var templ_7745c5c3_Var7 string
 			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(item.Label)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 95, Col: 16}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 103, Col: 16}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 			if templ_7745c5c3_Err != nil {
@@ -308,7 +316,7 @@ func FilterCard(filter filters.FilterStackEntry, index int, total int, cfg filte
 		var templ_7745c5c3_Var12 string
 		templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.ResolveAttributeValue(fmt.Sprintf("filter-card-%d", index))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 113, Col: 143}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 121, Col: 143}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var12)
 		if templ_7745c5c3_Err != nil {
@@ -343,7 +351,7 @@ func FilterCard(filter filters.FilterStackEntry, index int, total int, cfg filte
 		var templ_7745c5c3_Var15 string
 		templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs(filters.LabelForFilterType(filter.Type))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 116, Col: 120}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 124, Col: 120}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
 		if templ_7745c5c3_Err != nil {
@@ -361,7 +369,7 @@ func FilterCard(filter filters.FilterStackEntry, index int, total int, cfg filte
 			var templ_7745c5c3_Var16 string
 			templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.ResolveAttributeValue(filters.FilterMoveExpr(index, -1, cfg))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 121, Col: 59}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 129, Col: 59}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var16)
 			if templ_7745c5c3_Err != nil {
@@ -380,7 +388,7 @@ func FilterCard(filter filters.FilterStackEntry, index int, total int, cfg filte
 			var templ_7745c5c3_Var17 string
 			templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.ResolveAttributeValue(filters.FilterMoveExpr(index, 1, cfg))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 131, Col: 58}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 139, Col: 58}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var17)
 			if templ_7745c5c3_Err != nil {
@@ -398,7 +406,7 @@ func FilterCard(filter filters.FilterStackEntry, index int, total int, cfg filte
 		var templ_7745c5c3_Var18 string
 		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.ResolveAttributeValue(filters.FilterRemoveExpr(index, cfg))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 140, Col: 56}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 148, Col: 56}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var18)
 		if templ_7745c5c3_Err != nil {
@@ -486,7 +494,7 @@ func FilterCardParams(filter filters.FilterStackEntry, index int, cfg filters.Fi
 				var templ_7745c5c3_Var20 string
 				templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.JoinStringErrs(p.Label)
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 171, Col: 86}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/components/filter_stack.templ`, Line: 179, Col: 86}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var20))
 				if templ_7745c5c3_Err != nil {
@@ -63,7 +63,7 @@ func JobDetail(job *db.DownloadJob, username string) templ.Component {
 				}
 				templ_7745c5c3_Var3, templ_7745c5c3_Err := templruntime.ScriptContentInsideStringLiteral(job.ID.String())
 				if templ_7745c5c3_Err != nil {
-					return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 16, Col: 69}
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 16, Col: 69}
 				}
 				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var3)
 				if templ_7745c5c3_Err != nil {
@@ -186,7 +186,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 					var templ_7745c5c3_Var9 string
 					templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(job.ID.String())
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 54, Col: 67}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 54, Col: 67}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 					if templ_7745c5c3_Err != nil {
@@ -234,7 +234,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 					var templ_7745c5c3_Var12 string
 					templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var11).String())
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 1, Col: 0}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 1, Col: 0}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var12)
 					if templ_7745c5c3_Err != nil {
@@ -256,7 +256,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 					var templ_7745c5c3_Var14 string
 					templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var13).String())
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 1, Col: 0}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 1, Col: 0}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var14)
 					if templ_7745c5c3_Err != nil {
@@ -269,7 +269,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 					var templ_7745c5c3_Var15 templ.SafeURL
 					templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL(job.URL))
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 62, Col: 39}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 62, Col: 39}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
 					if templ_7745c5c3_Err != nil {
@@ -282,7 +282,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 					var templ_7745c5c3_Var16 string
 					templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(job.URL)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 63, Col: 17}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 63, Col: 17}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
 					if templ_7745c5c3_Err != nil {
@@ -309,7 +309,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						var templ_7745c5c3_Var18 string
 						templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var17).String())
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 1, Col: 0}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 1, Col: 0}
 						}
 						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var18)
 						if templ_7745c5c3_Err != nil {
@@ -331,7 +331,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						var templ_7745c5c3_Var20 string
 						templ_7745c5c3_Var20, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var19).String())
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 1, Col: 0}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 1, Col: 0}
 						}
 						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var20)
 						if templ_7745c5c3_Err != nil {
@@ -344,7 +344,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						var templ_7745c5c3_Var21 templ.SafeURL
 						templ_7745c5c3_Var21, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL("/videos/" + job.VideoID.String()))
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 72, Col: 66}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 72, Col: 66}
 						}
 						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var21))
 						if templ_7745c5c3_Err != nil {
@@ -371,7 +371,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 					var templ_7745c5c3_Var23 string
 					templ_7745c5c3_Var23, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var22).String())
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 1, Col: 0}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 1, Col: 0}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var23)
 					if templ_7745c5c3_Err != nil {
@@ -446,7 +446,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 								var templ_7745c5c3_Var28 string
 								templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(job.CreatedAt.Time.Format("January 2, 2006 at 3:04 PM"))
 								if templ_7745c5c3_Err != nil {
-									return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 87, Col: 66}
+									return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 87, Col: 66}
 								}
 								_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
 								if templ_7745c5c3_Err != nil {
@@ -522,7 +522,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 									var templ_7745c5c3_Var32 string
 									templ_7745c5c3_Var32, templ_7745c5c3_Err = templ.JoinStringErrs(job.StartedAt.Time.Format("January 2, 2006 at 3:04 PM"))
 									if templ_7745c5c3_Err != nil {
-										return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 96, Col: 67}
+										return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 96, Col: 67}
 									}
 									_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var32))
 									if templ_7745c5c3_Err != nil {
@@ -545,7 +545,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						if job.FinishedAt.Valid {
+						if job.Status == "queued" && job.NextRetryAt.Valid {
 							templ_7745c5c3_Var33 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 								templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 								templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
@@ -570,7 +570,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 										}()
 									}
 									ctx = templ.InitializeContext(ctx)
-									templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "Finished ")
+									templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "Next Retry ")
 									if templ_7745c5c3_Err != nil {
 										return templ_7745c5c3_Err
 									}
@@ -597,9 +597,9 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 									}
 									ctx = templ.InitializeContext(ctx)
 									var templ_7745c5c3_Var36 string
-									templ_7745c5c3_Var36, templ_7745c5c3_Err = templ.JoinStringErrs(job.FinishedAt.Time.Format("January 2, 2006 at 3:04 PM"))
+									templ_7745c5c3_Var36, templ_7745c5c3_Err = templ.JoinStringErrs(job.NextRetryAt.Time.Format("January 2, 2006 at 3:04 PM"))
 									if templ_7745c5c3_Err != nil {
-										return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 106, Col: 68}
+										return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 106, Col: 69}
 									}
 									_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var36))
 									if templ_7745c5c3_Err != nil {
@@ -617,12 +617,89 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, " ")
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, " ")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						if job.FinishedAt.Valid {
+							templ_7745c5c3_Var37 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+								templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+								templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+								if !templ_7745c5c3_IsBuffer {
+									defer func() {
+										templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+										if templ_7745c5c3_Err == nil {
+											templ_7745c5c3_Err = templ_7745c5c3_BufErr
+										}
+									}()
+								}
+								ctx = templ.InitializeContext(ctx)
+								templ_7745c5c3_Var38 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+									templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+									templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+									if !templ_7745c5c3_IsBuffer {
+										defer func() {
+											templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+											if templ_7745c5c3_Err == nil {
+												templ_7745c5c3_Err = templ_7745c5c3_BufErr
+											}
+										}()
+									}
+									ctx = templ.InitializeContext(ctx)
+									templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "Finished ")
+									if templ_7745c5c3_Err != nil {
+										return templ_7745c5c3_Err
+									}
+									return nil
+								})
+								templ_7745c5c3_Err = components.TableCell(true).Render(templ.WithChildren(ctx, templ_7745c5c3_Var38), templ_7745c5c3_Buffer)
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, " ")
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								templ_7745c5c3_Var39 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+									templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+									templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+									if !templ_7745c5c3_IsBuffer {
+										defer func() {
+											templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+											if templ_7745c5c3_Err == nil {
+												templ_7745c5c3_Err = templ_7745c5c3_BufErr
+											}
+										}()
+									}
+									ctx = templ.InitializeContext(ctx)
+									var templ_7745c5c3_Var40 string
+									templ_7745c5c3_Var40, templ_7745c5c3_Err = templ.JoinStringErrs(job.FinishedAt.Time.Format("January 2, 2006 at 3:04 PM"))
+									if templ_7745c5c3_Err != nil {
+										return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 116, Col: 68}
+									}
+									_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var40))
+									if templ_7745c5c3_Err != nil {
+										return templ_7745c5c3_Err
+									}
+									return nil
+								})
+								templ_7745c5c3_Err = components.TableCell(false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var39), templ_7745c5c3_Buffer)
+								if templ_7745c5c3_Err != nil {
+									return templ_7745c5c3_Err
+								}
+								return nil
+							})
+							templ_7745c5c3_Err = components.TableRow(false, "").Render(templ.WithChildren(ctx, templ_7745c5c3_Var37), templ_7745c5c3_Buffer)
+							if templ_7745c5c3_Err != nil {
+								return templ_7745c5c3_Err
+							}
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, " ")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 							if job.StartedAt.Valid {
-								templ_7745c5c3_Var37 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+								templ_7745c5c3_Var41 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 									templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 									templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 									if !templ_7745c5c3_IsBuffer {
@@ -634,7 +711,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 										}()
 									}
 									ctx = templ.InitializeContext(ctx)
-									templ_7745c5c3_Var38 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+									templ_7745c5c3_Var42 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 										templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 										templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 										if !templ_7745c5c3_IsBuffer {
@@ -646,21 +723,21 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 											}()
 										}
 										ctx = templ.InitializeContext(ctx)
-										templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "Duration ")
+										templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "Duration ")
 										if templ_7745c5c3_Err != nil {
 											return templ_7745c5c3_Err
 										}
 										return nil
 									})
-									templ_7745c5c3_Err = components.TableCell(true).Render(templ.WithChildren(ctx, templ_7745c5c3_Var38), templ_7745c5c3_Buffer)
+									templ_7745c5c3_Err = components.TableCell(true).Render(templ.WithChildren(ctx, templ_7745c5c3_Var42), templ_7745c5c3_Buffer)
 									if templ_7745c5c3_Err != nil {
 										return templ_7745c5c3_Err
 									}
-									templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, " ")
+									templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, " ")
 									if templ_7745c5c3_Err != nil {
 										return templ_7745c5c3_Err
 									}
-									templ_7745c5c3_Var39 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+									templ_7745c5c3_Var43 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 										templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 										templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 										if !templ_7745c5c3_IsBuffer {
@@ -672,24 +749,24 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 											}()
 										}
 										ctx = templ.InitializeContext(ctx)
-										var templ_7745c5c3_Var40 string
-										templ_7745c5c3_Var40, templ_7745c5c3_Err = templ.JoinStringErrs(format.JobDuration(job.FinishedAt.Time.Sub(job.StartedAt.Time)))
+										var templ_7745c5c3_Var44 string
+										templ_7745c5c3_Var44, templ_7745c5c3_Err = templ.JoinStringErrs(format.JobDuration(job.FinishedAt.Time.Sub(job.StartedAt.Time)))
 										if templ_7745c5c3_Err != nil {
-											return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 115, Col: 76}
+											return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 125, Col: 76}
 										}
-										_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var40))
+										_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var44))
 										if templ_7745c5c3_Err != nil {
 											return templ_7745c5c3_Err
 										}
 										return nil
 									})
-									templ_7745c5c3_Err = components.TableCell(false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var39), templ_7745c5c3_Buffer)
+									templ_7745c5c3_Err = components.TableCell(false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var43), templ_7745c5c3_Buffer)
 									if templ_7745c5c3_Err != nil {
 										return templ_7745c5c3_Err
 									}
 									return nil
 								})
-								templ_7745c5c3_Err = components.TableRow(false, "").Render(templ.WithChildren(ctx, templ_7745c5c3_Var37), templ_7745c5c3_Buffer)
+								templ_7745c5c3_Err = components.TableRow(false, "").Render(templ.WithChildren(ctx, templ_7745c5c3_Var41), templ_7745c5c3_Buffer)
 								if templ_7745c5c3_Err != nil {
 									return templ_7745c5c3_Err
 								}
@@ -701,92 +778,121 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "</div><div class=\"mb-6\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "</div><div class=\"mb-6\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					var templ_7745c5c3_Var41 = []any{"section-label mb-2"}
-					templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var41...)
+					var templ_7745c5c3_Var45 = []any{"section-label mb-2"}
+					templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var45...)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<h3 class=\"")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "<h3 class=\"")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					var templ_7745c5c3_Var42 string
-					templ_7745c5c3_Var42, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var41).String())
+					var templ_7745c5c3_Var46 string
+					templ_7745c5c3_Var46, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var45).String())
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 1, Col: 0}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 1, Col: 0}
 					}
-					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var42)
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var46)
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "\">Statistics</h3><div class=\"grid grid-cols-2 gap-4\"><div class=\"info-box\"><p class=\"section-label mb-1\">ATTEMPTS</p><p class=\"text-2xl font-mono font-bold text-white\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "\">Statistics</h3><div class=\"grid grid-cols-2 gap-4\"><div class=\"info-box\"><p class=\"section-label mb-1\">ATTEMPTS</p><p class=\"text-2xl font-mono font-bold text-white\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					var templ_7745c5c3_Var43 string
-					templ_7745c5c3_Var43, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", job.Attempts))
+					var templ_7745c5c3_Var47 string
+					templ_7745c5c3_Var47, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", job.Attempts))
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 127, Col: 92}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 137, Col: 92}
 					}
-					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var43))
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var47))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "</p></div><div class=\"info-box\"><p class=\"section-label mb-1\">OPTIONS</p><div class=\"flex flex-wrap gap-2 mt-2\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "</p></div><div class=\"info-box\"><p class=\"section-label mb-1\">OPTIONS</p><div class=\"flex flex-wrap gap-2 mt-2\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 					if job.Refresh {
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "<span class=\"inline-flex items-center px-2 py-1 text-xs font-mono bg-white/10 text-white border-2 border-white/20\"><i class=\"fa-sharp fa-solid fa-rotate mr-1\" aria-hidden=\"true\"></i> REFRESH</span>")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "<span class=\"inline-flex items-center px-2 py-1 text-xs font-mono bg-white/10 text-white border-2 border-white/20\"><i class=\"fa-sharp fa-solid fa-rotate mr-1\" aria-hidden=\"true\"></i> REFRESH</span> ")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+					}
+					if job.MetadataOnly {
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "<span class=\"inline-flex items-center px-2 py-1 text-xs font-mono bg-white/10 text-white border-2 border-white/20\"><i class=\"fa-sharp fa-solid fa-file-lines mr-1\" aria-hidden=\"true\"></i> METADATA ONLY</span>")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "</div></div></div></div>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					if jobNeedsCookies(job) {
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, "<div class=\"mb-6 bg-black/40 border-2 border-yellow-500/50 p-4\"><p class=\"text-sm font-mono text-yellow-400 mb-2\"><i class=\"fa-sharp fa-solid fa-cookie-bite mr-1\" aria-hidden=\"true\"></i> ")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var48 string
+						templ_7745c5c3_Var48, templ_7745c5c3_Err = templ.JoinStringErrs(cookiePromptMessage(*job.ErrorCode))
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 162, Col: 45}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var48))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "</p><p class=\"text-xs font-mono text-white/60\">Upload cookies from a browser where you are signed in on <a href=\"/settings\" class=\"underline text-white\">the settings page</a>, then retry this job.</p></div>")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "</div></div></div></div>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, " ")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 					if job.LastError != nil && *job.LastError != "" {
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, "<div class=\"mb-6\">")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "<div class=\"mb-6\">")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						var templ_7745c5c3_Var44 = []any{"section-label mb-2"}
-						templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var44...)
+						var templ_7745c5c3_Var49 = []any{"section-label mb-2"}
+						templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var49...)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "<h3 class=\"")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "<h3 class=\"")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						var templ_7745c5c3_Var45 string
-						templ_7745c5c3_Var45, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var44).String())
+						var templ_7745c5c3_Var50 string
+						templ_7745c5c3_Var50, templ_7745c5c3_Err = templ.ResolveAttributeValue(templ.CSSClasses(templ_7745c5c3_Var49).String())
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 1, Col: 0}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 1, Col: 0}
 						}
-						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var45)
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var50)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "\">Error Details</h3><div class=\"bg-black/40 border-2 border-red-500/50 p-4\"><pre class=\"text-xs font-mono text-red-400 whitespace-pre-wrap break-words\">")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, "\">Error Details</h3><div class=\"bg-black/40 border-2 border-red-500/50 p-4\"><pre class=\"text-xs font-mono text-red-400 whitespace-pre-wrap break-words\">")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						var templ_7745c5c3_Var46 string
-						templ_7745c5c3_Var46, templ_7745c5c3_Err = templ.JoinStringErrs(*job.LastError)
+						var templ_7745c5c3_Var51 string
+						templ_7745c5c3_Var51, templ_7745c5c3_Err = templ.JoinStringErrs(*job.LastError)
 						if templ_7745c5c3_Err != nil {
-							return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 146, Col: 100}
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 175, Col: 100}
 						}
-						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var46))
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var51))
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "</pre></div></div>")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, "</pre></div></div>")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
@@ -797,11 +903,11 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 48, " ")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 55, " ")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Var47 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+				templ_7745c5c3_Var52 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 					templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 					templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 					if !templ_7745c5c3_IsBuffer {
@@ -813,7 +919,7 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						}()
 					}
 					ctx = templ.InitializeContext(ctx)
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 49, "<div class=\"flex items-center gap-3\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 56, "<div class=\"flex items-center gap-3\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
@@ -822,20 +928,20 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 50, "<div onclick=\"")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 57, "<div onclick=\"")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						var templ_7745c5c3_Var48 templ.ComponentScript = templ.JSFuncCall("retryJob", job.ID.String())
-						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var48.Call)
+						var templ_7745c5c3_Var53 templ.ComponentScript = templ.JSFuncCall("retryJob", job.ID.String())
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var53.Call)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 51, "\">")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 58, "\">")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Var49 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+						templ_7745c5c3_Var54 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 							templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 							templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 							if !templ_7745c5c3_IsBuffer {
@@ -847,17 +953,17 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 								}()
 							}
 							ctx = templ.InitializeContext(ctx)
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 52, "Retry Job")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 59, "Retry Job")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 							return nil
 						})
-						templ_7745c5c3_Err = components.Button("primary", "md", "rotate", false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var49), templ_7745c5c3_Buffer)
+						templ_7745c5c3_Err = components.Button("primary", "md", "rotate", false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var54), templ_7745c5c3_Buffer)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 53, "</div>")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 60, "</div>")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
@@ -867,20 +973,20 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 54, "<div onclick=\"")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 61, "<div onclick=\"")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						var templ_7745c5c3_Var50 templ.ComponentScript = templ.JSFuncCall("cancelJob", job.ID.String())
-						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var50.Call)
+						var templ_7745c5c3_Var55 templ.ComponentScript = templ.JSFuncCall("cancelJob", job.ID.String())
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var55.Call)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 55, "\">")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 62, "\">")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Var51 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+						templ_7745c5c3_Var56 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 							templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 							templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 							if !templ_7745c5c3_IsBuffer {
@@ -892,17 +998,17 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 								}()
 							}
 							ctx = templ.InitializeContext(ctx)
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 56, "Cancel Job")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 63, "Cancel Job")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 							return nil
 						})
-						templ_7745c5c3_Err = components.Button("danger", "md", "xmark", false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var51), templ_7745c5c3_Buffer)
+						templ_7745c5c3_Err = components.Button("danger", "md", "xmark", false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var56), templ_7745c5c3_Buffer)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 57, "</div>")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 64, "</div>")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
@@ -912,20 +1018,20 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 58, "<div onclick=\"")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 65, "<div onclick=\"")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						var templ_7745c5c3_Var52 templ.ComponentScript = templ.JSFuncCall("unarchiveJob", job.ID.String())
-						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var52.Call)
+						var templ_7745c5c3_Var57 templ.ComponentScript = templ.JSFuncCall("unarchiveJob", job.ID.String())
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var57.Call)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 59, "\">")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 66, "\">")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Var53 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+						templ_7745c5c3_Var58 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 							templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 							templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 							if !templ_7745c5c3_IsBuffer {
@@ -937,17 +1043,17 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 								}()
 							}
 							ctx = templ.InitializeContext(ctx)
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 60, "Unarchive Job")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 67, "Unarchive Job")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 							return nil
 						})
-						templ_7745c5c3_Err = components.Button("secondary", "md", "box-archive", false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var53), templ_7745c5c3_Buffer)
+						templ_7745c5c3_Err = components.Button("secondary", "md", "box-archive", false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var58), templ_7745c5c3_Buffer)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 61, "</div>")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 68, "</div>")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
@@ -956,20 +1062,20 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 62, "<div onclick=\"")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 69, "<div onclick=\"")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						var templ_7745c5c3_Var54 templ.ComponentScript = templ.JSFuncCall("archiveJob", job.ID.String())
-						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var54.Call)
+						var templ_7745c5c3_Var59 templ.ComponentScript = templ.JSFuncCall("archiveJob", job.ID.String())
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var59.Call)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 63, "\">")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 70, "\">")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Var55 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+						templ_7745c5c3_Var60 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 							templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 							templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 							if !templ_7745c5c3_IsBuffer {
@@ -981,28 +1087,28 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 								}()
 							}
 							ctx = templ.InitializeContext(ctx)
-							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 64, "Archive Job")
+							templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 71, "Archive Job")
 							if templ_7745c5c3_Err != nil {
 								return templ_7745c5c3_Err
 							}
 							return nil
 						})
-						templ_7745c5c3_Err = components.Button("secondary", "md", "box-archive", false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var55), templ_7745c5c3_Buffer)
+						templ_7745c5c3_Err = components.Button("secondary", "md", "box-archive", false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var60), templ_7745c5c3_Buffer)
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
-						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 65, "</div>")
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 72, "</div>")
 						if templ_7745c5c3_Err != nil {
 							return templ_7745c5c3_Err
 						}
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 66, "</div>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 73, "</div>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 					return nil
 				})
-				templ_7745c5c3_Err = components.CardFooter().Render(templ.WithChildren(ctx, templ_7745c5c3_Var47), templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = components.CardFooter().Render(templ.WithChildren(ctx, templ_7745c5c3_Var52), templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -1012,11 +1118,11 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 67, "</div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 74, "</div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Var56 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+			templ_7745c5c3_Var61 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 				templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 				templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 				if !templ_7745c5c3_IsBuffer {
@@ -1032,11 +1138,11 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 68, " ")
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 75, " ")
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
-				templ_7745c5c3_Var57 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+				templ_7745c5c3_Var62 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 					templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 					templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
 					if !templ_7745c5c3_IsBuffer {
@@ -1048,19 +1154,19 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 						}()
 					}
 					ctx = templ.InitializeContext(ctx)
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 69, "<div id=\"logs-container\" class=\"info-box font-mono text-xs max-h-96 overflow-y-auto\"><div class=\"text-white/40\">Loading logs...</div></div>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 76, "<div class=\"flex flex-col sm:flex-row gap-2 mb-3\"><select id=\"logs-stream-filter\" class=\"bg-black border-2 border-white/20 text-sm font-mono px-2 py-1.5 focus:border-white/40 outline-none cursor-pointer\"><option value=\"\">ALL STREAMS</option> <option value=\"stdout\">STDOUT</option> <option value=\"stderr\">STDERR</option></select> <input id=\"logs-search\" type=\"search\" placeholder=\"Filter lines...\" autocomplete=\"off\" class=\"form-input flex-1 text-sm\"> <span id=\"logs-count\" class=\"self-center text-xs text-white/40 font-mono\"></span></div><div id=\"logs-container\" class=\"info-box font-mono text-xs max-h-96 overflow-y-auto\"><div class=\"text-white/40\">Loading logs...</div></div>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 					return nil
 				})
-				templ_7745c5c3_Err = components.CardBody(true).Render(templ.WithChildren(ctx, templ_7745c5c3_Var57), templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = components.CardBody(true).Render(templ.WithChildren(ctx, templ_7745c5c3_Var62), templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
 				return nil
 			})
-			templ_7745c5c3_Err = components.Card(false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var56), templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = components.Card(false).Render(templ.WithChildren(ctx, templ_7745c5c3_Var61), templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -1070,31 +1176,31 @@ func JobDetailContent(job *db.DownloadJob) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 70, "<script>\n\t\tconst jobId = \"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 77, "<script>\n\t\tconst jobId = \"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var58, templ_7745c5c3_Err := templruntime.ScriptContentInsideStringLiteral(job.ID.String())
+		templ_7745c5c3_Var63, templ_7745c5c3_Err := templruntime.ScriptContentInsideStringLiteral(job.ID.String())
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 194, Col: 35}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 241, Col: 35}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var58)
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var63)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 71, "\";\n\t\tconst isProcessing = ")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 78, "\";\n\t\tconst isProcessing = ")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Var59, templ_7745c5c3_Err := templruntime.ScriptContentOutsideStringLiteral(job.Status == "processing")
+		templ_7745c5c3_Var64, templ_7745c5c3_Err := templruntime.ScriptContentOutsideStringLiteral(job.Status == "processing")
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/job_detail.templ`, Line: 195, Col: 52}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `job_detail.templ`, Line: 242, Col: 52}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var59)
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var64)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 72, ";\n\t\t\n\t\tasync function postJobAction(jobId, action) {\n\t\t\tconst response = await fetch(`/api/jobs/${jobId}/${action}`, {\n\t\t\t\tmethod: 'POST',\n\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t});\n\t\t\tif (!response.ok) {\n\t\t\t\tconst text = await response.text();\n\t\t\t\tthrow new Error(text || `Failed to ${action} job`);\n\t\t\t}\n\t\t}\n\n\t\tasync function retryJob(jobId) {\n\t\t\tif (!confirm('Retry this job?')) return;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'retry');\n\t\t\t} catch (error) {\n\t\t\t\talert('Failed to retry job: ' + error.message);\n\t\t\t}\n\t\t}\n\n\t\tasync function cancelJob(jobId) {\n\t\t\tif (!confirm('Cancel this job?')) return;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'cancel');\n\t\t\t} catch (error) {\n\t\t\t\talert('Failed to cancel job: ' + error.message);\n\t\t\t}\n\t\t}\n\n\t\tasync function archiveJob(jobId) {\n\t\t\tif (!confirm('Archive this job? This will hide it from the jobs list.')) return;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'archive');\n\t\t\t\twindow.location.href = '/jobs';\n\t\t\t} catch (error) {\n\t\t\t\talert('Failed to archive job: ' + error.message);\n\t\t\t}\n\t\t}\n\n\t\tasync function unarchiveJob(jobId) {\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'unarchive');\n\t\t\t\twindow.location.href = '/jobs';\n\t\t\t} catch (error) {\n\t\t\t\talert('Failed to unarchive job: ' + error.message);\n\t\t\t}\n\t\t}\n\t\t\n\t\t// Paginated log viewer\n\t\tlet currentOffset = 0;\n\t\tlet totalLogs = 0;\n\t\tlet isLoading = false;\n\t\tconst LOGS_PER_PAGE = 50;\n\t\t\n\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\tloadInitialLogs();\n\t\t\t\n\t\t\t// Stream new logs if job is processing\n\t\t\tif (isProcessing) {\n\t\t\t\tstreamLogs();\n\t\t\t}\n\t\t\t\n\t\t\t// Infinite scroll for loading older logs\n\t\t\tconst container = document.getElementById('logs-container');\n\t\t\tcontainer.addEventListener('scroll', () => {\n\t\t\t\t// Load more when scrolled to top (to get older logs)\n\t\t\t\tif (container.scrollTop < 100 && !isLoading && currentOffset < totalLogs) {\n\t\t\t\t\tloadMoreLogs();\n\t\t\t\t}\n\t\t\t});\n\t\t});\n\t\t\n\t\tasync function loadInitialLogs() {\n\t\t\ttry {\n\t\t\t\tconst response = await fetch(`/api/jobs/${jobId}/logs?limit=${LOGS_PER_PAGE}&offset=0`);\n\t\t\t\tif (!response.ok) {\n\t\t\t\t\tdocument.getElementById('logs-container').innerHTML = '<div class=\"text-red-500\">Failed to load logs</div>';\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tconst data = await response.json();\n\t\t\t\ttotalLogs = data.total || 0;\n\t\t\t\tcurrentOffset = data.logs.length;\n\t\t\t\t\n\t\t\t\tdisplayLogs(data.logs, false);\n\t\t\t\t\n\t\t\t\t// If there are more logs, show indicator\n\t\t\t\tif (currentOffset < totalLogs) {\n\t\t\t\t\tprependLoadMoreIndicator();\n\t\t\t\t}\n\t\t\t} catch (error) {\n\t\t\t\tconsole.error('Failed to load logs:', error);\n\t\t\t\tdocument.getElementById('logs-container').innerHTML = '<div class=\"text-red-500\">Error loading logs</div>';\n\t\t\t}\n\t\t}\n\t\t\n\t\tasync function loadMoreLogs() {\n\t\t\tif (isLoading) return;\n\t\t\tisLoading = true;\n\t\t\t\n\t\t\ttry {\n\t\t\t\tconst response = await fetch(`/api/jobs/${jobId}/logs?limit=${LOGS_PER_PAGE}&offset=${currentOffset}`);\n\t\t\t\tif (!response.ok) {\n\t\t\t\t\tconsole.error('Failed to load more logs');\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tconst data = await response.json();\n\t\t\t\tcurrentOffset += data.logs.length;\n\t\t\t\t\n\t\t\t\t// Save scroll position\n\t\t\t\tconst container = document.getElementById('logs-container');\n\t\t\t\tconst oldScrollHeight = container.scrollHeight;\n\t\t\t\t\n\t\t\t\tdisplayLogs(data.logs, true);\n\t\t\t\t\n\t\t\t\t// Restore scroll position (compensate for new content at top)\n\t\t\t\tconst newScrollHeight = container.scrollHeight;\n\t\t\t\tcontainer.scrollTop = newScrollHeight - oldScrollHeight + container.scrollTop;\n\t\t\t\t\n\t\t\t\t// Remove load more indicator if we've loaded everything\n\t\t\t\tif (currentOffset >= totalLogs) {\n\t\t\t\t\tremoveLoadMoreIndicator();\n\t\t\t\t}\n\t\t\t} catch (error) {\n\t\t\t\tconsole.error('Failed to load more logs:', error);\n\t\t\t} finally {\n\t\t\t\tisLoading = false;\n\t\t\t}\n\t\t}\n\t\t\n\t\tfunction displayLogs(logs, prepend = false) {\n\t\t\tconst container = document.getElementById('logs-container');\n\t\t\t\n\t\t\tif (logs.length === 0 && !prepend) {\n\t\t\t\tcontainer.innerHTML = '<div class=\"text-white/40\">No output yet</div>';\n\t\t\t\treturn;\n\t\t\t}\n\t\t\t\n\t\t\t// Clear placeholder if exists\n\t\t\tconst placeholder = container.querySelector('.text-white\\\\/40');\n\t\t\tif (placeholder) {\n\t\t\t\tplaceholder.remove();\n\t\t\t}\n\t\t\t\n\t\t\tconst fragment = document.createDocumentFragment();\n\t\t\tlogs.forEach(log => {\n\t\t\t\tconst line = document.createElement('div');\n\t\t\t\tline.className = log.stream === 'stderr' ? 'text-red-400' : 'text-green-400';\n\t\t\t\tline.textContent = log.message;\n\t\t\t\tfragment.appendChild(line);\n\t\t\t});\n\t\t\t\n\t\t\tif (prepend) {\n\t\t\t\tremoveLoadMoreIndicator();\n\t\t\t\tcontainer.insertBefore(fragment, container.firstChild);\n\t\t\t\tif (currentOffset < totalLogs) {\n\t\t\t\t\tprependLoadMoreIndicator();\n\t\t\t\t}\n\t\t\t} else {\n\t\t\t\tcontainer.appendChild(fragment);\n\t\t\t\t// Auto-scroll to bottom on initial load\n\t\t\t\tcontainer.scrollTop = container.scrollHeight;\n\t\t\t}\n\t\t}\n\t\t\n\t\tfunction prependLoadMoreIndicator() {\n\t\t\tconst container = document.getElementById('logs-container');\n\t\t\tconst indicator = document.createElement('div');\n\t\t\tindicator.className = 'text-white/60 text-center py-2 cursor-pointer hover:text-white load-more-indicator';\n\t\t\tindicator.textContent = `↑ Load more (${totalLogs - currentOffset} older lines) ↑`;\n\t\t\tindicator.onclick = loadMoreLogs;\n\t\t\tcontainer.insertBefore(indicator, container.firstChild);\n\t\t}\n\t\t\n\t\tfunction removeLoadMoreIndicator() {\n\t\t\tconst indicator = document.querySelector('.load-more-indicator');\n\t\t\tif (indicator) indicator.remove();\n\t\t}\n\t\t\n\t\tfunction streamLogs() {\n\t\t\ttry {\n\t\t\t\tconst logStream = new EventSource(`/api/jobs/${jobId}/logs/stream`);\n\t\t\t\t\n\t\t\t\tlogStream.addEventListener('log', (evt) => {\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst log = JSON.parse(evt.data);\n\t\t\t\t\t\tconst container = document.getElementById('logs-container');\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Remove \"No output\" message if present\n\t\t\t\t\t\tif (container.querySelector('.text-white\\\\/40')) {\n\t\t\t\t\t\t\tcontainer.innerHTML = '';\n\t\t\t\t\t\t}\n\t\t\t\t\t\t\n\t\t\t\t\t\tconst line = document.createElement('div');\n\t\t\t\t\t\tline.className = log.stream === 'stderr' ? 'text-red-400' : 'text-green-400';\n\t\t\t\t\t\tline.textContent = log.message;\n\t\t\t\t\t\tcontainer.appendChild(line);\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Auto-scroll to bottom if user is near bottom\n\t\t\t\t\t\tconst isNearBottom = container.scrollHeight - container.scrollTop - container.clientHeight < 100;\n\t\t\t\t\t\tif (isNearBottom) {\n\t\t\t\t\t\t\tcontainer.scrollTop = container.scrollHeight;\n\t\t\t\t\t\t}\n\t\t\t\t\t\t\n\t\t\t\t\t\ttotalLogs++;\n\t\t\t\t\t} catch (e) {\n\t\t\t\t\t\tconsole.warn('bad log event', e);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t\t\n\t\t\t\tlogStream.addEventListener('complete', (evt) => {\n\t\t\t\t\tlogStream.close();\n\t\t\t\t\tconsole.log('Log stream complete');\n\t\t\t\t});\n\t\t\t\t\n\t\t\t\tlogStream.onerror = (err) => {\n\t\t\t\t\tconsole.error('Log stream error:', err);\n\t\t\t\t\tlogStream.close();\n\t\t\t\t};\n\t\t\t\t\n\t\t\t\twindow.addEventListener('beforeunload', () => {\n\t\t\t\t\tlogStream.close();\n\t\t\t\t});\n\t\t\t} catch (e) {\n\t\t\t\tconsole.warn('Log streaming unavailable', e);\n\t\t\t}\n\t\t}\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 79, ";\n\t\t\n\t\tasync function postJobAction(jobId, action) {\n\t\t\tconst response = await fetch(`/api/jobs/${jobId}/${action}`, {\n\t\t\t\tmethod: 'POST',\n\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t});\n\t\t\tif (!response.ok) {\n\t\t\t\tconst text = await response.text();\n\t\t\t\tthrow new Error(text || `Failed to ${action} job`);\n\t\t\t}\n\t\t}\n\n\t\tasync function retryJob(jobId) {\n\t\t\tif (!confirm('Retry this job?')) return;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'retry');\n\t\t\t} catch (error) {\n\t\t\t\talert('Failed to retry job: ' + error.message);\n\t\t\t}\n\t\t}\n\n\t\tasync function cancelJob(jobId) {\n\t\t\tif (!confirm('Cancel this job?')) return;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'cancel');\n\t\t\t} catch (error) {\n\t\t\t\talert('Failed to cancel job: ' + error.message);\n\t\t\t}\n\t\t}\n\n\t\tasync function archiveJob(jobId) {\n\t\t\tif (!confirm('Archive this job? This will hide it from the jobs list.')) return;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'archive');\n\t\t\t\twindow.location.href = '/jobs';\n\t\t\t} catch (error) {\n\t\t\t\talert('Failed to archive job: ' + error.message);\n\t\t\t}\n\t\t}\n\n\t\tasync function unarchiveJob(jobId) {\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'unarchive');\n\t\t\t\twindow.location.href = '/jobs';\n\t\t\t} catch (error) {\n\t\t\t\talert('Failed to unarchive job: ' + error.message);\n\t\t\t}\n\t\t}\n\t\t\n\t\t// Paginated log viewer\n\t\tlet currentOffset = 0;\n\t\tlet totalLogs = 0;\n\t\tlet isLoading = false;\n\t\tconst LOGS_PER_PAGE = 50;\n\t\tlet logStream = null;\n\t\tlet searchTimer = null;\n\n\t\t// Current ?stream=&q= filters from the toolbar, shared by fetch and SSE.\n\t\tfunction logFilterParams() {\n\t\t\tconst params = new URLSearchParams();\n\t\t\tconst stream = document.getElementById('logs-stream-filter').value;\n\t\t\tconst q = document.getElementById('logs-search').value.trim();\n\t\t\tif (stream) params.set('stream', stream);\n\t\t\tif (q) params.set('q', q);\n\t\t\treturn params;\n\t\t}\n\n\t\tfunction logsURL(offset) {\n\t\t\tconst params = logFilterParams();\n\t\t\tparams.set('limit', LOGS_PER_PAGE);\n\t\t\tparams.set('offset', offset);\n\t\t\treturn `/api/jobs/${jobId}/logs?${params}`;\n\t\t}\n\n\t\tfunction updateLogsCount() {\n\t\t\tconst el = document.getElementById('logs-count');\n\t\t\tconst filtered = logFilterParams().toString() !== '';\n\t\t\tel.textContent = filtered ? `${totalLogs} matching lines` : `${totalLogs} lines`;\n\t\t}\n\n\t\tfunction applyLogFilters() {\n\t\t\tcurrentOffset = 0;\n\t\t\ttotalLogs = 0;\n\t\t\tdocument.getElementById('logs-container').innerHTML = '<div class=\"text-white/40\">Loading logs...</div>';\n\t\t\tloadInitialLogs();\n\t\t\tif (logStream) {\n\t\t\t\tlogStream.close();\n\t\t\t\tstreamLogs();\n\t\t\t}\n\t\t}\n\t\t\n\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\tloadInitialLogs();\n\n\t\t\tdocument.getElementById('logs-stream-filter').addEventListener('change', applyLogFilters);\n\t\t\tdocument.getElementById('logs-search').addEventListener('input', () => {\n\t\t\t\tclearTimeout(searchTimer);\n\t\t\t\tsearchTimer = setTimeout(applyLogFilters, 300);\n\t\t\t});\n\t\t\t\n\t\t\t// Stream new logs if job is processing\n\t\t\tif (isProcessing) {\n\t\t\t\tstreamLogs();\n\t\t\t}\n\t\t\t\n\t\t\t// Infinite scroll for loading older logs\n\t\t\tconst container = document.getElementById('logs-container');\n\t\t\tcontainer.addEventListener('scroll', () => {\n\t\t\t\t// Load more when scrolled to top (to get older logs)\n\t\t\t\tif (container.scrollTop < 100 && !isLoading && currentOffset < totalLogs) {\n\t\t\t\t\tloadMoreLogs();\n\t\t\t\t}\n\t\t\t});\n\t\t});\n\t\t\n\t\tasync function loadInitialLogs() {\n\t\t\ttry {\n\t\t\t\tconst response = await fetch(logsURL(0));\n\t\t\t\tif (!response.ok) {\n\t\t\t\t\tdocument.getElementById('logs-container').innerHTML = '<div class=\"text-red-500\">Failed to load logs</div>';\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tconst data = await response.json();\n\t\t\t\ttotalLogs = data.total || 0;\n\t\t\t\tcurrentOffset = data.logs.length;\n\t\t\t\tupdateLogsCount();\n\t\t\t\t\n\t\t\t\tdisplayLogs(data.logs, false);\n\t\t\t\t\n\t\t\t\t// If there are more logs, show indicator\n\t\t\t\tif (currentOffset < totalLogs) {\n\t\t\t\t\tprependLoadMoreIndicator();\n\t\t\t\t}\n\t\t\t} catch (error) {\n\t\t\t\tconsole.error('Failed to load logs:', error);\n\t\t\t\tdocument.getElementById('logs-container').innerHTML = '<div class=\"text-red-500\">Error loading logs</div>';\n\t\t\t}\n\t\t}\n\t\t\n\t\tasync function loadMoreLogs() {\n\t\t\tif (isLoading) return;\n\t\t\tisLoading = true;\n\t\t\t\n\t\t\ttry {\n\t\t\t\tconst response = await fetch(logsURL(currentOffset));\n\t\t\t\tif (!response.ok) {\n\t\t\t\t\tconsole.error('Failed to load more logs');\n\t\t\t\t\treturn;\n\t\t\t\t}\n\t\t\t\tconst data = await response.json();\n\t\t\t\tcurrentOffset += data.logs.length;\n\t\t\t\t\n\t\t\t\t// Save scroll position\n\t\t\t\tconst container = document.getElementById('logs-container');\n\t\t\t\tconst oldScrollHeight = container.scrollHeight;\n\t\t\t\t\n\t\t\t\tdisplayLogs(data.logs, true);\n\t\t\t\t\n\t\t\t\t// Restore scroll position (compensate for new content at top)\n\t\t\t\tconst newScrollHeight = container.scrollHeight;\n\t\t\t\tcontainer.scrollTop = newScrollHeight - oldScrollHeight + container.scrollTop;\n\t\t\t\t\n\t\t\t\t// Remove load more indicator if we've loaded everything\n\t\t\t\tif (currentOffset >= totalLogs) {\n\t\t\t\t\tremoveLoadMoreIndicator();\n\t\t\t\t}\n\t\t\t} catch (error) {\n\t\t\t\tconsole.error('Failed to load more logs:', error);\n\t\t\t} finally {\n\t\t\t\tisLoading = false;\n\t\t\t}\n\t\t}\n\t\t\n\t\tfunction displayLogs(logs, prepend = false) {\n\t\t\tconst container = document.getElementById('logs-container');\n\t\t\t\n\t\t\tif (logs.length === 0 && !prepend) {\n\t\t\t\tconst filtered = logFilterParams().toString() !== '';\n\t\t\t\tcontainer.innerHTML = filtered\n\t\t\t\t\t? '<div class=\"text-white/40\">No matching lines</div>'\n\t\t\t\t\t: '<div class=\"text-white/40\">No output yet</div>';\n\t\t\t\treturn;\n\t\t\t}\n\t\t\t\n\t\t\t// Clear placeholder if exists\n\t\t\tconst placeholder = container.querySelector('.text-white\\\\/40');\n\t\t\tif (placeholder) {\n\t\t\t\tplaceholder.remove();\n\t\t\t}\n\t\t\t\n\t\t\tconst fragment = document.createDocumentFragment();\n\t\t\tlogs.forEach(log => {\n\t\t\t\tconst line = document.createElement('div');\n\t\t\t\tline.className = log.stream === 'stderr' ? 'text-red-400' : 'text-green-400';\n\t\t\t\tline.textContent = log.message;\n\t\t\t\tfragment.appendChild(line);\n\t\t\t});\n\t\t\t\n\t\t\tif (prepend) {\n\t\t\t\tremoveLoadMoreIndicator();\n\t\t\t\tcontainer.insertBefore(fragment, container.firstChild);\n\t\t\t\tif (currentOffset < totalLogs) {\n\t\t\t\t\tprependLoadMoreIndicator();\n\t\t\t\t}\n\t\t\t} else {\n\t\t\t\tcontainer.appendChild(fragment);\n\t\t\t\t// Auto-scroll to bottom on initial load\n\t\t\t\tcontainer.scrollTop = container.scrollHeight;\n\t\t\t}\n\t\t}\n\t\t\n\t\tfunction prependLoadMoreIndicator() {\n\t\t\tconst container = document.getElementById('logs-container');\n\t\t\tconst indicator = document.createElement('div');\n\t\t\tindicator.className = 'text-white/60 text-center py-2 cursor-pointer hover:text-white load-more-indicator';\n\t\t\tindicator.textContent = `↑ Load more (${totalLogs - currentOffset} older lines) ↑`;\n\t\t\tindicator.onclick = loadMoreLogs;\n\t\t\tcontainer.insertBefore(indicator, container.firstChild);\n\t\t}\n\t\t\n\t\tfunction removeLoadMoreIndicator() {\n\t\t\tconst indicator = document.querySelector('.load-more-indicator');\n\t\t\tif (indicator) indicator.remove();\n\t\t}\n\t\t\n\t\tfunction streamLogs() {\n\t\t\ttry {\n\t\t\t\tlogStream = new EventSource(`/api/jobs/${jobId}/logs/stream?${logFilterParams()}`);\n\t\t\t\t\n\t\t\t\tlogStream.addEventListener('log', (evt) => {\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst log = JSON.parse(evt.data);\n\t\t\t\t\t\tconst container = document.getElementById('logs-container');\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Remove \"No output\" message if present\n\t\t\t\t\t\tif (container.querySelector('.text-white\\\\/40')) {\n\t\t\t\t\t\t\tcontainer.innerHTML = '';\n\t\t\t\t\t\t}\n\t\t\t\t\t\t\n\t\t\t\t\t\tconst line = document.createElement('div');\n\t\t\t\t\t\tline.className = log.stream === 'stderr' ? 'text-red-400' : 'text-green-400';\n\t\t\t\t\t\tline.textContent = log.message;\n\t\t\t\t\t\tcontainer.appendChild(line);\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Auto-scroll to bottom if user is near bottom\n\t\t\t\t\t\tconst isNearBottom = container.scrollHeight - container.scrollTop - container.clientHeight < 100;\n\t\t\t\t\t\tif (isNearBottom) {\n\t\t\t\t\t\t\tcontainer.scrollTop = container.scrollHeight;\n\t\t\t\t\t\t}\n\t\t\t\t\t\t\n\t\t\t\t\t\ttotalLogs++;\n\t\t\t\t\t\tupdateLogsCount();\n\t\t\t\t\t} catch (e) {\n\t\t\t\t\t\tconsole.warn('bad log event', e);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t\t\n\t\t\t\tlogStream.addEventListener('complete', (evt) => {\n\t\t\t\t\tlogStream.close();\n\t\t\t\t\tlogStream = null;\n\t\t\t\t\tconsole.log('Log stream complete');\n\t\t\t\t});\n\t\t\t\t\n\t\t\t\tlogStream.onerror = (err) => {\n\t\t\t\t\tconsole.error('Log stream error:', err);\n\t\t\t\t\tlogStream.close();\n\t\t\t\t};\n\t\t\t\t\n\t\t\t\twindow.addEventListener('beforeunload', () => {\n\t\t\t\t\tlogStream.close();\n\t\t\t\t});\n\t\t\t} catch (e) {\n\t\t\t\tconsole.warn('Log streaming unavailable', e);\n\t\t\t}\n\t\t}\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -11,6 +11,7 @@ import templruntime "github.com/a-h/templ/runtime"
 import (
 	"fmt"
 	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ytdlp"
 )
 
 func Jobs(jobs []*db.DownloadJob, username string) templ.Component {
@@ -154,7 +155,7 @@ func JobsContent(jobs []*db.DownloadJob) templ.Component {
 					return templ_7745c5c3_Err
 				}
 			} else {
-				templ_7745c5c3_Err = JobsList(jobs).Render(ctx, templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = JobsList(jobs, false).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -165,7 +166,7 @@ func JobsContent(jobs []*db.DownloadJob) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<script>\n\t\t// Multi-select state\n\t\tconst selectedJobs = new Set();\n\t\tlet checkboxesVisible = false;\n\t\tlet lastCheckedIndex = null;\n\n\t\tfunction toggleCheckboxes() {\n\t\t\tcheckboxesVisible = !checkboxesVisible;\n\t\t\tconst checkboxes = document.querySelectorAll('.job-checkbox');\n\t\t\tconst btn = document.getElementById('toggle-checkboxes-btn');\n\t\t\t\n\t\t\tcheckboxes.forEach(checkbox => {\n\t\t\t\tcheckbox.style.display = checkboxesVisible ? 'block' : 'none';\n\t\t\t});\n\t\t\t\n\t\t\tif (checkboxesVisible) {\n\t\t\t\tbtn.classList.remove('text-white/60', 'border-white/40');\n\t\t\t\tbtn.classList.add('text-white', 'border-white');\n\t\t\t} else {\n\t\t\t\tbtn.classList.remove('text-white', 'border-white');\n\t\t\t\tbtn.classList.add('text-white/60', 'border-white/40');\n\t\t\t\t// Clear selections when hiding\n\t\t\t\tselectedJobs.clear();\n\t\t\t\tcheckboxes.forEach(checkbox => checkbox.checked = false);\n\t\t\t\tupdateSelectionUI();\n\t\t\t}\n\t\t}\n\n\t\tfunction updateSelectionUI() {\n\t\t\tconst count = selectedJobs.size;\n\t\t\tconst bar = document.getElementById('bulk-actions-bar');\n\t\t\tconst countText = document.getElementById('selection-count');\n\t\t\t\n\t\t\tif (count > 0) {\n\t\t\t\tbar.classList.remove('hidden');\n\t\t\t\tcountText.textContent = `${count} selected`;\n\t\t\t} else {\n\t\t\t\tbar.classList.add('hidden');\n\t\t\t}\n\n\t\t\t// Update select-all checkbox state\n\t\t\tconst allCheckboxes = document.querySelectorAll('.job-checkbox:not(#select-all-checkbox)');\n\t\t\tconst selectAllCheckbox = document.getElementById('select-all-checkbox');\n\t\t\tif (selectAllCheckbox) {\n\t\t\t\tselectAllCheckbox.checked = allCheckboxes.length > 0 && allCheckboxes.length === selectedJobs.size;\n\t\t\t\tselectAllCheckbox.indeterminate = selectedJobs.size > 0 && selectedJobs.size < allCheckboxes.length;\n\t\t\t}\n\t\t}\n\n\t\tfunction toggleJobSelection(event, jobId) {\n\t\t\tevent.stopPropagation();\n\t\t\t\n\t\t\tconst allCheckboxes = Array.from(document.querySelectorAll('.job-checkbox:not(#select-all-checkbox)'));\n\t\t\tconst currentIndex = allCheckboxes.findIndex(cb => cb.dataset.jobId === jobId);\n\t\t\t\n\t\t\t// Shift-click range selection\n\t\t\tif (event.shiftKey && lastCheckedIndex !== null && currentIndex !== -1) {\n\t\t\t\tconst start = Math.min(lastCheckedIndex, currentIndex);\n\t\t\t\tconst end = Math.max(lastCheckedIndex, currentIndex);\n\t\t\t\tconst shouldSelect = event.target.checked;\n\t\t\t\t\n\t\t\t\tfor (let i = start; i <= end; i++) {\n\t\t\t\t\tconst checkbox = allCheckboxes[i];\n\t\t\t\t\tcheckbox.checked = shouldSelect;\n\t\t\t\t\tif (shouldSelect) {\n\t\t\t\t\t\tselectedJobs.add(checkbox.dataset.jobId);\n\t\t\t\t\t} else {\n\t\t\t\t\t\tselectedJobs.delete(checkbox.dataset.jobId);\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t} else {\n\t\t\t\t// Normal click\n\t\t\t\tif (event.target.checked) {\n\t\t\t\t\tselectedJobs.add(jobId);\n\t\t\t\t} else {\n\t\t\t\t\tselectedJobs.delete(jobId);\n\t\t\t\t}\n\t\t\t}\n\t\t\t\n\t\t\tif (currentIndex !== -1) {\n\t\t\t\tlastCheckedIndex = currentIndex;\n\t\t\t}\n\t\t\t\n\t\t\tupdateSelectionUI();\n\t\t}\n\n\t\tfunction toggleSelectAll(event) {\n\t\t\tconst allCheckboxes = document.querySelectorAll('.job-checkbox:not(#select-all-checkbox)');\n\t\t\tconst shouldSelect = event.target.checked;\n\t\t\t\n\t\t\tselectedJobs.clear();\n\t\t\tallCheckboxes.forEach(checkbox => {\n\t\t\t\tcheckbox.checked = shouldSelect;\n\t\t\t\tif (shouldSelect) {\n\t\t\t\t\tselectedJobs.add(checkbox.dataset.jobId);\n\t\t\t\t}\n\t\t\t});\n\t\t\tupdateSelectionUI();\n\t\t}\n\n\t\tasync function bulkArchiveJobs() {\n\t\t\tif (selectedJobs.size === 0) return;\n\t\t\t\n\t\t\tconst count = selectedJobs.size;\n\t\t\tif (!confirm(`Archive ${count} job${count > 1 ? 's' : ''}?`)) return;\n\n\t\t\tconst btn = document.getElementById('bulk-archive-btn');\n\t\t\tbtn.disabled = true;\n\t\t\tbtn.textContent = 'Archiving...';\n\n\t\t\ttry {\n\t\t\t\tconst response = await fetch('/api/jobs/archive', {\n\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t\tbody: JSON.stringify({ job_ids: Array.from(selectedJobs) })\n\t\t\t\t});\n\n\t\t\t\tif (response.ok) {\n\t\t\t\t\t// Remove archived jobs from view\n\t\t\t\t\tselectedJobs.forEach(jobId => {\n\t\t\t\t\t\tconst card = document.getElementById(`job-card-${jobId}`);\n\t\t\t\t\t\tif (card) card.remove();\n\t\t\t\t\t});\n\t\t\t\t\tselectedJobs.clear();\n\t\t\t\t\tupdateSelectionUI();\n\t\t\t\t} else {\n\t\t\t\t\tconst text = await response.text();\n\t\t\t\t\talert('Failed to archive jobs: ' + text);\n\t\t\t\t}\n\t\t\t} catch (error) {\n\t\t\t\talert('Error: ' + error.message);\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t\tbtn.textContent = 'Archive Selected';\n\t\t\t}\n\t\t}\n\n\t\tasync function archiveJob(event, jobId) {\n\t\t\tevent.preventDefault();\n\t\t\tevent.stopPropagation();\n\t\t\tif (!confirm('Archive this job?')) return;\n\n\t\t\tconst btn = event.target;\n\t\t\tbtn.disabled = true;\n\n\t\t\ttry {\n\t\t\t\tconst response = await fetch(`/api/jobs/${jobId}/archive`, {\n\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t});\n\n\t\t\t\tif (response.ok) {\n\t\t\t\t\tconst card = document.getElementById(`job-card-${jobId}`);\n\t\t\t\t\tif (card) card.remove();\n\t\t\t\t} else {\n\t\t\t\t\tconst text = await response.text();\n\t\t\t\t\talert('Failed to archive job: ' + text);\n\t\t\t\t}\n\t\t\t} catch (error) {\n\t\t\t\talert('Error: ' + error.message);\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t}\n\t\t}\n\n\t\tfunction applyJobsFilter(filterStatus) {\n\t\t\tdocument.querySelectorAll('.job-card').forEach(card => {\n\t\t\t\tconst cardStatus = (card.getAttribute('data-status') || '').toLowerCase();\n\t\t\t\tif (filterStatus === 'all' || cardStatus === filterStatus) {\n\t\t\t\t\tcard.style.display = 'block';\n\t\t\t\t} else {\n\t\t\t\t\tcard.style.display = 'none';\n\t\t\t\t}\n\t\t\t});\n\t\t}\n\n\t\tfunction setActiveTab(activeButton) {\n\t\t\tconst tabButtons = document.querySelectorAll('.tab-button');\n\t\t\ttabButtons.forEach(btn => {\n\t\t\t\tif (btn === activeButton) {\n\t\t\t\t\tbtn.classList.remove('tab-btn-inactive');\n\t\t\t\t\tbtn.classList.add('tab-btn-active');\n\t\t\t\t} else {\n\t\t\t\t\tbtn.classList.remove('tab-btn-active');\n\t\t\t\t\tbtn.classList.add('tab-btn-inactive');\n\t\t\t\t}\n\t\t\t});\n\t\t}\n\n\t\tasync function postJobAction(jobId, action) {\n\t\t\tconst response = await fetch(`/api/jobs/${jobId}/${action}`, {\n\t\t\t\tmethod: 'POST',\n\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t});\n\t\t\tif (!response.ok) {\n\t\t\t\tconst text = await response.text();\n\t\t\t\tthrow new Error(text || `Failed to ${action} job`);\n\t\t\t}\n\t\t\treturn response.json().catch(() => ({}));\n\t\t}\n\n\t\t// Called by onclick handlers from templ.JSFuncCall\n\t\tasync function retryJob(event, jobId) {\n\t\t\tevent.preventDefault();\n\t\t\tevent.stopPropagation();\n\t\t\tconst btn = event.target;\n\t\t\tbtn.disabled = true;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'retry');\n\t\t\t\t// No reload; SSE will refresh the job card\n\t\t\t} catch (err) {\n\t\t\t\tconsole.error(err);\n\t\t\t\talert(err.message || 'Failed to retry job');\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t}\n\t\t}\n\n\t\tasync function cancelJob(event, jobId) {\n\t\t\tevent.preventDefault();\n\t\t\tevent.stopPropagation();\n\t\t\tif (!confirm('Are you sure you want to cancel this job?')) {\n\t\t\t\treturn;\n\t\t\t}\n\t\t\tconst btn = event.target;\n\t\t\tbtn.disabled = true;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'cancel');\n\t\t\t\t// No reload; SSE will refresh the job card\n\t\t\t} catch (err) {\n\t\t\t\tconsole.error(err);\n\t\t\t\talert(err.message || 'Failed to cancel job');\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t}\n\t\t}\n\n\t\tfunction upsertJobCardHTML(html) {\n\t\t\tif (!html) return;\n\t\t\tconst parser = new DOMParser();\n\t\t\tconst doc = parser.parseFromString(html, 'text/html');\n\t\t\tconst node = doc.body.firstElementChild;\n\t\t\tif (!node) return;\n\t\t\tconst id = node.getAttribute('id');\n\t\t\tif (!id) return;\n\t\t\t\n\t\t\t// Preserve checkbox visibility when updating cards\n\t\t\tconst checkbox = node.querySelector('.job-checkbox');\n\t\t\tif (checkbox && checkboxesVisible) {\n\t\t\t\tcheckbox.style.display = 'block';\n\t\t\t}\n\t\t\t\n\t\t\tconst existing = document.getElementById(id);\n\t\t\tif (existing) {\n\t\t\t\texisting.replaceWith(node);\n\t\t\t} else {\n\t\t\t\tconst list = document.getElementById('jobs-list');\n\t\t\t\tif (list) list.prepend(node);\n\t\t\t}\n\t\t}\n\n\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\t// Tabs\n\t\t\tdocument.querySelectorAll('.tab-button').forEach(button => {\n\t\t\t\tbutton.addEventListener('click', function() {\n\t\t\t\t\tconst filterStatus = this.getAttribute('data-status');\n\t\t\t\t\tsetActiveTab(this);\n\t\t\t\t\tapplyJobsFilter(filterStatus || 'all');\n\t\t\t\t});\n\t\t\t});\n\n\t\t\t// Live updates via SSE\n\t\t\ttry {\n\t\t\t\tconst es = new EventSource('/api/jobs/stream');\n\t\t\t\tes.addEventListener('download_job', (evt) => {\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst payload = JSON.parse(evt.data);\n\t\t\t\t\t\tupsertJobCardHTML(payload.jobs_card_html);\n\t\t\t\t\t\tconst active = document.querySelector('.tab-button.tab-btn-active');\n\t\t\t\t\t\tconst status = active ? active.getAttribute('data-status') : 'all';\n\t\t\t\t\t\tapplyJobsFilter(status || 'all');\n\t\t\t\t\t} catch (e) {\n\t\t\t\t\t\tconsole.warn('bad SSE payload', e);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t} catch (e) {\n\t\t\t\tconsole.warn('SSE unavailable', e);\n\t\t\t}\n\t\t});\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<script>\n\t\t// Multi-select state\n\t\tconst selectedJobs = new Set();\n\t\tlet checkboxesVisible = false;\n\t\tlet lastCheckedIndex = null;\n\n\t\tfunction toggleCheckboxes() {\n\t\t\tcheckboxesVisible = !checkboxesVisible;\n\t\t\tconst checkboxes = document.querySelectorAll('.job-checkbox');\n\t\t\tconst btn = document.getElementById('toggle-checkboxes-btn');\n\t\t\t\n\t\t\tcheckboxes.forEach(checkbox => {\n\t\t\t\tcheckbox.style.display = checkboxesVisible ? 'block' : 'none';\n\t\t\t});\n\t\t\t\n\t\t\tif (checkboxesVisible) {\n\t\t\t\tbtn.classList.remove('text-white/60', 'border-white/40');\n\t\t\t\tbtn.classList.add('text-white', 'border-white');\n\t\t\t} else {\n\t\t\t\tbtn.classList.remove('text-white', 'border-white');\n\t\t\t\tbtn.classList.add('text-white/60', 'border-white/40');\n\t\t\t\t// Clear selections when hiding\n\t\t\t\tselectedJobs.clear();\n\t\t\t\tcheckboxes.forEach(checkbox => checkbox.checked = false);\n\t\t\t\tupdateSelectionUI();\n\t\t\t}\n\t\t}\n\n\t\tfunction updateSelectionUI() {\n\t\t\tconst count = selectedJobs.size;\n\t\t\tconst bar = document.getElementById('bulk-actions-bar');\n\t\t\tconst countText = document.getElementById('selection-count');\n\t\t\t\n\t\t\tif (count > 0) {\n\t\t\t\tbar.classList.remove('hidden');\n\t\t\t\tcountText.textContent = `${count} selected`;\n\t\t\t} else {\n\t\t\t\tbar.classList.add('hidden');\n\t\t\t}\n\n\t\t\t// Update select-all checkbox state\n\t\t\tconst allCheckboxes = document.querySelectorAll('.job-checkbox:not(#select-all-checkbox)');\n\t\t\tconst selectAllCheckbox = document.getElementById('select-all-checkbox');\n\t\t\tif (selectAllCheckbox) {\n\t\t\t\tselectAllCheckbox.checked = allCheckboxes.length > 0 && allCheckboxes.length === selectedJobs.size;\n\t\t\t\tselectAllCheckbox.indeterminate = selectedJobs.size > 0 && selectedJobs.size < allCheckboxes.length;\n\t\t\t}\n\t\t}\n\n\t\tfunction toggleJobSelection(event, jobId) {\n\t\t\tevent.stopPropagation();\n\t\t\t\n\t\t\tconst allCheckboxes = Array.from(document.querySelectorAll('.job-checkbox:not(#select-all-checkbox)'));\n\t\t\tconst currentIndex = allCheckboxes.findIndex(cb => cb.dataset.jobId === jobId);\n\t\t\t\n\t\t\t// Shift-click range selection\n\t\t\tif (event.shiftKey && lastCheckedIndex !== null && currentIndex !== -1) {\n\t\t\t\tconst start = Math.min(lastCheckedIndex, currentIndex);\n\t\t\t\tconst end = Math.max(lastCheckedIndex, currentIndex);\n\t\t\t\tconst shouldSelect = event.target.checked;\n\t\t\t\t\n\t\t\t\tfor (let i = start; i <= end; i++) {\n\t\t\t\t\tconst checkbox = allCheckboxes[i];\n\t\t\t\t\tcheckbox.checked = shouldSelect;\n\t\t\t\t\tif (shouldSelect) {\n\t\t\t\t\t\tselectedJobs.add(checkbox.dataset.jobId);\n\t\t\t\t\t} else {\n\t\t\t\t\t\tselectedJobs.delete(checkbox.dataset.jobId);\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t} else {\n\t\t\t\t// Normal click\n\t\t\t\tif (event.target.checked) {\n\t\t\t\t\tselectedJobs.add(jobId);\n\t\t\t\t} else {\n\t\t\t\t\tselectedJobs.delete(jobId);\n\t\t\t\t}\n\t\t\t}\n\t\t\t\n\t\t\tif (currentIndex !== -1) {\n\t\t\t\tlastCheckedIndex = currentIndex;\n\t\t\t}\n\t\t\t\n\t\t\tupdateSelectionUI();\n\t\t}\n\n\t\tfunction toggleSelectAll(event) {\n\t\t\tconst allCheckboxes = document.querySelectorAll('.job-checkbox:not(#select-all-checkbox)');\n\t\t\tconst shouldSelect = event.target.checked;\n\t\t\t\n\t\t\tselectedJobs.clear();\n\t\t\tallCheckboxes.forEach(checkbox => {\n\t\t\t\tcheckbox.checked = shouldSelect;\n\t\t\t\tif (shouldSelect) {\n\t\t\t\t\tselectedJobs.add(checkbox.dataset.jobId);\n\t\t\t\t}\n\t\t\t});\n\t\t\tupdateSelectionUI();\n\t\t}\n\n\t\tasync function bulkArchiveJobs() {\n\t\t\tif (selectedJobs.size === 0) return;\n\t\t\t\n\t\t\tconst count = selectedJobs.size;\n\t\t\tif (!confirm(`Archive ${count} job${count > 1 ? 's' : ''}?`)) return;\n\n\t\t\tconst btn = document.getElementById('bulk-archive-btn');\n\t\t\tbtn.disabled = true;\n\t\t\tbtn.textContent = 'Archiving...';\n\n\t\t\ttry {\n\t\t\t\tconst response = await fetch('/api/jobs/archive', {\n\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t\tbody: JSON.stringify({ job_ids: Array.from(selectedJobs) })\n\t\t\t\t});\n\n\t\t\t\tif (response.ok) {\n\t\t\t\t\t// Remove archived jobs from view\n\t\t\t\t\tselectedJobs.forEach(jobId => {\n\t\t\t\t\t\tconst card = document.getElementById(`job-card-${jobId}`);\n\t\t\t\t\t\tif (card) card.remove();\n\t\t\t\t\t});\n\t\t\t\t\tselectedJobs.clear();\n\t\t\t\t\tupdateSelectionUI();\n\t\t\t\t} else {\n\t\t\t\t\tconst text = await response.text();\n\t\t\t\t\talert('Failed to archive jobs: ' + text);\n\t\t\t\t}\n\t\t\t} catch (error) {\n\t\t\t\talert('Error: ' + error.message);\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t\tbtn.textContent = 'Archive Selected';\n\t\t\t}\n\t\t}\n\n\t\tasync function archiveJob(event, jobId) {\n\t\t\tevent.preventDefault();\n\t\t\tevent.stopPropagation();\n\t\t\tif (!confirm('Archive this job?')) return;\n\n\t\t\tconst btn = event.target;\n\t\t\tbtn.disabled = true;\n\n\t\t\ttry {\n\t\t\t\tconst response = await fetch(`/api/jobs/${jobId}/archive`, {\n\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t\t});\n\n\t\t\t\tif (response.ok) {\n\t\t\t\t\tconst card = document.getElementById(`job-card-${jobId}`);\n\t\t\t\t\tif (card) card.remove();\n\t\t\t\t} else {\n\t\t\t\t\tconst text = await response.text();\n\t\t\t\t\talert('Failed to archive job: ' + text);\n\t\t\t\t}\n\t\t\t} catch (error) {\n\t\t\t\talert('Error: ' + error.message);\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t}\n\t\t}\n\n\t\tfunction applyJobsFilter(filterStatus) {\n\t\t\tdocument.querySelectorAll('.job-card').forEach(card => {\n\t\t\t\tconst cardStatus = (card.getAttribute('data-status') || '').toLowerCase();\n\t\t\t\tif (filterStatus === 'all' || cardStatus === filterStatus) {\n\t\t\t\t\tcard.style.display = 'block';\n\t\t\t\t} else {\n\t\t\t\t\tcard.style.display = 'none';\n\t\t\t\t}\n\t\t\t});\n\t\t}\n\n\t\tfunction setActiveTab(activeButton) {\n\t\t\tconst tabButtons = document.querySelectorAll('.tab-button');\n\t\t\ttabButtons.forEach(btn => {\n\t\t\t\tif (btn === activeButton) {\n\t\t\t\t\tbtn.classList.remove('tab-btn-inactive');\n\t\t\t\t\tbtn.classList.add('tab-btn-active');\n\t\t\t\t} else {\n\t\t\t\t\tbtn.classList.remove('tab-btn-active');\n\t\t\t\t\tbtn.classList.add('tab-btn-inactive');\n\t\t\t\t}\n\t\t\t});\n\t\t}\n\n\t\tasync function postJobAction(jobId, action) {\n\t\t\tconst response = await fetch(`/api/jobs/${jobId}/${action}`, {\n\t\t\t\tmethod: 'POST',\n\t\t\t\theaders: { 'Content-Type': 'application/json' },\n\t\t\t});\n\t\t\tif (!response.ok) {\n\t\t\t\tconst text = await response.text();\n\t\t\t\tthrow new Error(text || `Failed to ${action} job`);\n\t\t\t}\n\t\t\treturn response.json().catch(() => ({}));\n\t\t}\n\n\t\t// Called by onclick handlers from templ.JSFuncCall\n\t\tasync function retryJob(event, jobId) {\n\t\t\tevent.preventDefault();\n\t\t\tevent.stopPropagation();\n\t\t\tconst btn = event.target;\n\t\t\tbtn.disabled = true;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'retry');\n\t\t\t\t// No reload; SSE will refresh the job card\n\t\t\t} catch (err) {\n\t\t\t\tconsole.error(err);\n\t\t\t\talert(err.message || 'Failed to retry job');\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t}\n\t\t}\n\n\t\tasync function prioritizeJob(event, jobId) {\n\t\t\tevent.preventDefault();\n\t\t\tevent.stopPropagation();\n\t\t\tconst btn = event.target;\n\t\t\tbtn.disabled = true;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'prioritize');\n\t\t\t} catch (err) {\n\t\t\t\tconsole.error(err);\n\t\t\t\talert(err.message || 'Failed to prioritize job');\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t}\n\t\t}\n\n\t\tasync function cancelJob(event, jobId) {\n\t\t\tevent.preventDefault();\n\t\t\tevent.stopPropagation();\n\t\t\tif (!confirm('Are you sure you want to cancel this job?')) {\n\t\t\t\treturn;\n\t\t\t}\n\t\t\tconst btn = event.target;\n\t\t\tbtn.disabled = true;\n\t\t\ttry {\n\t\t\t\tawait postJobAction(jobId, 'cancel');\n\t\t\t\t// No reload; SSE will refresh the job card\n\t\t\t} catch (err) {\n\t\t\t\tconsole.error(err);\n\t\t\t\talert(err.message || 'Failed to cancel job');\n\t\t\t} finally {\n\t\t\t\tbtn.disabled = false;\n\t\t\t}\n\t\t}\n\n\t\tfunction upsertJobCardHTML(html) {\n\t\t\tif (!html) return;\n\t\t\tconst parser = new DOMParser();\n\t\t\tconst doc = parser.parseFromString(html, 'text/html');\n\t\t\tconst node = doc.body.firstElementChild;\n\t\t\tif (!node) return;\n\t\t\tconst id = node.getAttribute('id');\n\t\t\tif (!id) return;\n\t\t\t\n\t\t\t// Preserve checkbox visibility when updating cards\n\t\t\tconst checkbox = node.querySelector('.job-checkbox');\n\t\t\tif (checkbox && checkboxesVisible) {\n\t\t\t\tcheckbox.style.display = 'block';\n\t\t\t}\n\t\t\t\n\t\t\tconst existing = document.getElementById(id);\n\t\t\tif (existing) {\n\t\t\t\texisting.replaceWith(node);\n\t\t\t} else {\n\t\t\t\tconst list = document.getElementById('jobs-list');\n\t\t\t\tif (list) list.prepend(node);\n\t\t\t}\n\t\t}\n\n\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\t// Tabs\n\t\t\tdocument.querySelectorAll('.tab-button').forEach(button => {\n\t\t\t\tbutton.addEventListener('click', function() {\n\t\t\t\t\tconst filterStatus = this.getAttribute('data-status');\n\t\t\t\t\tsetActiveTab(this);\n\t\t\t\t\tapplyJobsFilter(filterStatus || 'all');\n\t\t\t\t});\n\t\t\t});\n\n\t\t\t// Live updates via SSE\n\t\t\ttry {\n\t\t\t\tconst es = new EventSource('/api/jobs/stream');\n\t\t\t\tes.addEventListener('download_job', (evt) => {\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconst payload = JSON.parse(evt.data);\n\t\t\t\t\t\tupsertJobCardHTML(payload.jobs_card_html);\n\t\t\t\t\t\tconst active = document.querySelector('.tab-button.tab-btn-active');\n\t\t\t\t\t\tconst status = active ? active.getAttribute('data-status') : 'all';\n\t\t\t\t\t\tapplyJobsFilter(status || 'all');\n\t\t\t\t\t} catch (e) {\n\t\t\t\t\t\tconsole.warn('bad SSE payload', e);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t} catch (e) {\n\t\t\t\tconsole.warn('SSE unavailable', e);\n\t\t\t}\n\t\t});\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -173,7 +174,7 @@ func JobsContent(jobs []*db.DownloadJob) templ.Component {
 	})
 }
 
-func JobsList(jobs []*db.DownloadJob) templ.Component {
+func JobsList(jobs []*db.DownloadJob, canPrioritize bool) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -200,7 +201,7 @@ func JobsList(jobs []*db.DownloadJob) templ.Component {
 		}
 		if len(jobs) > 0 {
 			for _, job := range jobs {
-				templ_7745c5c3_Err = DownloadJobCard(job).Render(ctx, templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = DownloadJobCard(job, canPrioritize).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
@@ -227,7 +228,7 @@ func JobsList(jobs []*db.DownloadJob) templ.Component {
 	})
 }
 
-func DownloadJobCard(job *db.DownloadJob) templ.Component {
+func DownloadJobCard(job *db.DownloadJob, canPrioritize bool) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -255,7 +256,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		var templ_7745c5c3_Var10 templ.SafeURL
 		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL("/jobs/" + job.ID.String()))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 403, Col: 50}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 419, Col: 50}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
 		if templ_7745c5c3_Err != nil {
@@ -268,7 +269,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		var templ_7745c5c3_Var11 string
 		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.ResolveAttributeValue("job-card-" + job.ID.String())
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 404, Col: 36}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 420, Col: 36}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var11)
 		if templ_7745c5c3_Err != nil {
@@ -281,7 +282,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		var templ_7745c5c3_Var12 string
 		templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.ResolveAttributeValue(job.Status)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 406, Col: 26}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 422, Col: 26}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var12)
 		if templ_7745c5c3_Err != nil {
@@ -319,7 +320,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		var templ_7745c5c3_Var14 string
 		templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.ResolveAttributeValue(job.ID.String())
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 414, Col: 34}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 430, Col: 34}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var14)
 		if templ_7745c5c3_Err != nil {
@@ -349,7 +350,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		var templ_7745c5c3_Var16 string
 		templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs(job.URL)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 422, Col: 62}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 438, Col: 62}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
 		if templ_7745c5c3_Err != nil {
@@ -362,7 +363,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		var templ_7745c5c3_Var17 string
 		templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs(job.CreatedAt.Time.Format("Jan 2 15:04"))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 425, Col: 52}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 441, Col: 52}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
 		if templ_7745c5c3_Err != nil {
@@ -375,7 +376,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		var templ_7745c5c3_Var18 string
 		templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs(fmt.Sprintf("%d", job.Attempts))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 426, Col: 45}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 442, Col: 45}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
 		if templ_7745c5c3_Err != nil {
@@ -421,7 +422,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		if job.Status == "processing" {
+		if job.Status == "queued" && canPrioritize {
 			templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, templ.ComponentScript{Call: "event.preventDefault(); event.stopPropagation();"})
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -439,7 +440,7 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, templ.JSFuncCall("cancelJob", templ.JSExpression("event"), job.ID.String()))
+			templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, templ.JSFuncCall("prioritizeJob", templ.JSExpression("event"), job.ID.String()))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -447,12 +448,48 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var22 templ.ComponentScript = templ.JSFuncCall("cancelJob", templ.JSExpression("event"), job.ID.String())
+			var templ_7745c5c3_Var22 templ.ComponentScript = templ.JSFuncCall("prioritizeJob", templ.JSExpression("event"), job.ID.String())
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var22.Call)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "\" class=\"btn-primary btn-sm\">CANCEL</button></div>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "\" class=\"btn-secondary btn-sm\" title=\"Move to the front of the queue\">RUN NEXT</button></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		if job.Status == "processing" {
+			templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, templ.ComponentScript{Call: "event.preventDefault(); event.stopPropagation();"})
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "<div onclick=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var23 templ.ComponentScript = templ.ComponentScript{Call: "event.preventDefault(); event.stopPropagation();"}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var23.Call)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templ.RenderScriptItems(ctx, templ_7745c5c3_Buffer, templ.JSFuncCall("cancelJob", templ.JSExpression("event"), job.ID.String()))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "<button type=\"button\" onclick=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var24 templ.ComponentScript = templ.JSFuncCall("cancelJob", templ.JSExpression("event"), job.ID.String())
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var24.Call)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "\" class=\"btn-primary btn-sm\">CANCEL</button></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -461,16 +498,16 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "<div onclick=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<div onclick=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var23 templ.ComponentScript = templ.ComponentScript{Call: "event.preventDefault(); event.stopPropagation();"}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var23.Call)
+		var templ_7745c5c3_Var25 templ.ComponentScript = templ.ComponentScript{Call: "event.preventDefault(); event.stopPropagation();"}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var25.Call)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -478,39 +515,57 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "<button type=\"button\" onclick=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "<button type=\"button\" onclick=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var24 templ.ComponentScript = templ.JSFuncCall("archiveJob", templ.JSExpression("event"), job.ID.String())
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var24.Call)
+		var templ_7745c5c3_Var26 templ.ComponentScript = templ.JSFuncCall("archiveJob", templ.JSExpression("event"), job.ID.String())
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ_7745c5c3_Var26.Call)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "\" class=\"btn-ghost btn-sm\" title=\"Archive job (soft delete)\">ARCHIVE</button></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 42, "\" class=\"btn-ghost btn-sm\" title=\"Archive job (soft delete)\">ARCHIVE</button></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if job.LastError != nil && *job.LastError != "" {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "<div class=\"mt-1 ml-8 text-xs font-mono text-white/60 truncate\">ERROR: ")
+		if jobNeedsCookies(job) {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 43, "<div class=\"mt-1 ml-8 text-xs font-mono text-yellow-400 truncate\"><i class=\"fa-sharp fa-solid fa-cookie-bite mr-1\" aria-hidden=\"true\"></i> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var27 string
+			templ_7745c5c3_Var27, templ_7745c5c3_Err = templ.JoinStringErrs(cookiePromptMessage(*job.ErrorCode))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 494, Col: 41}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var27))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var25 string
-			templ_7745c5c3_Var25, templ_7745c5c3_Err = templ.JoinStringErrs(*job.LastError)
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 44, " Upload your login cookies in Settings, then retry.</div>")
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 465, Col: 27}
+				return templ_7745c5c3_Err
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var25))
+		} else if job.LastError != nil && *job.LastError != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 45, "<div class=\"mt-1 ml-8 text-xs font-mono text-white/60 truncate\">ERROR: ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 40, "</div>")
+			var templ_7745c5c3_Var28 string
+			templ_7745c5c3_Var28, templ_7745c5c3_Err = templ.JoinStringErrs(*job.LastError)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `cmd/web/templates/jobs.templ`, Line: 498, Col: 27}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var28))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 46, "</div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 41, "</a>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 47, "</a>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -518,4 +573,23 @@ func DownloadJobCard(job *db.DownloadJob) templ.Component {
 	})
 }
 
+// jobNeedsCookies reports whether a failed job's error code suggests that
+// uploading login cookies and retrying would fix it.
+func jobNeedsCookies(job *db.DownloadJob) bool {
+	return job.Status == db.JobStatusFailed && job.ErrorCode != nil && ytdlp.RequiresCookies(*job.ErrorCode)
+}
+
+// cookiePromptMessage explains a cookie-fixable download failure.
+func cookiePromptMessage(code string) string {
+	switch code {
+	case ytdlp.ErrorCodeAgeRestricted:
+		return "This video is age-restricted and requires cookies from a signed-in account."
+	case ytdlp.ErrorCodeMembersOnly:
+		return "This video is members-only and requires cookies from an account with access."
+	case ytdlp.ErrorCodePrivate:
+		return "This video is private and requires cookies from an account with access."
+	}
+	return "This video requires cookies from a signed-in account."
+}
+
 var _ = templruntime.GeneratedTemplate
@@ -0,0 +1,28 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// InitFFmpeg applies FFMPEG_PATH, FFPROBE_PATH and FFMPEG_MIN_VERSION, then
+// checks that both binaries run, that ffmpeg is new enough and that it has the
+// given filters. The detected versions are logged. Services exit on error so a
+// broken install shows up at startup rather than on the first job.
+func InitFFmpeg(ctx context.Context, filters ...string) (*ffmpeg.BinaryInfo, error) {
+	if err := ffmpeg.ConfigureFromEnv(); err != nil {
+		return nil, err
+	}
+	info, err := ffmpeg.CheckBinaries(ctx, filters...)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg check: %w", err)
+	}
+	slog.Info("ffmpeg detected",
+		"ffmpeg", info.FFmpegPath, "ffmpeg_version", info.FFmpegVersion.String(),
+		"ffprobe", info.FFprobePath, "ffprobe_version", info.FFprobeVersion.String(),
+		"min_version", fmt.Sprintf("%d.%d", ffmpeg.MinVersion.Major, ffmpeg.MinVersion.Minor))
+	return info, nil
+}
@@ -14,6 +14,23 @@ import (
 	"thirdcoast.systems/rewind/internal/videoid"
 )
 
+// ErrDownloadsPaused is returned when new downloads are refused, e.g. because
+// a storage volume is low on free space.
+var ErrDownloadsPaused = errors.New("downloads are paused: storage is low on free space")
+
+// DownloadsPaused, when set, is consulted before any download job is enqueued.
+// The web server wires it to its disk monitor.
+var DownloadsPaused func() bool
+
+// CheckDownloadsPaused returns ErrDownloadsPaused while downloads are paused.
+// Call it before enqueueing download jobs outside EnqueueURL.
+func CheckDownloadsPaused() error {
+	if DownloadsPaused != nil && DownloadsPaused() {
+		return ErrDownloadsPaused
+	}
+	return nil
+}
+
 // EnqueueResult reports what EnqueueURL created.
 type EnqueueResult struct {
 	Job        *db.DownloadJob
@@ -25,15 +42,59 @@ type EnqueueResult struct {
 // become a "playlist" job; any other URL becomes a single-video job, with
 // refresh=true when that exact source URL is already archived.
 func EnqueueURL(ctx context.Context, q *db.Queries, rawURL string, archivedBy pgtype.UUID) (*EnqueueResult, error) {
+	return EnqueueURLWithArgs(ctx, q, rawURL, archivedBy, nil)
+}
+
+// EnqueueURLWithArgs is EnqueueURL with extra yt-dlp arguments stored on the
+// job (and, for playlists, on every child job). Callers must build extraArgs
+// from validated input, e.g. ytdlp.RequestHeaderArgs.
+func EnqueueURLWithArgs(ctx context.Context, q *db.Queries, rawURL string, archivedBy pgtype.UUID, extraArgs []string) (*EnqueueResult, error) {
+	return EnqueueURLWithOptions(ctx, q, rawURL, archivedBy, EnqueueOptions{ExtraArgs: extraArgs})
+}
+
+// EnqueueOptions are the per-job settings accepted by EnqueueURLWithOptions.
+type EnqueueOptions struct {
+	// ExtraArgs are extra yt-dlp arguments; see EnqueueURLWithArgs.
+	ExtraArgs []string
+	// MetadataOnly saves the info JSON and thumbnail without the media file.
+	MetadataOnly bool
+	// ExpandPlaylist queues a playlist job even when the URL isn't recognized
+	// as a playlist or channel, e.g. a watch URL that also carries list=, or a
+	// channel on a site IsPlaylistOrChannelURL doesn't know.
+	ExpandPlaylist bool
+}
+
+// EnqueueURLWithOptions is EnqueueURL with per-job options. Submitting a full
+// archive for a source that was saved metadata-only downloads the file rather
+// than refreshing metadata, which is how such videos are upgraded. The
+// submitting user's download quality cap is applied to the job's arguments.
+func EnqueueURLWithOptions(ctx context.Context, q *db.Queries, rawURL string, archivedBy pgtype.UUID, opts EnqueueOptions) (*EnqueueResult, error) {
+	extraArgs := opts.ExtraArgs
+	if extraArgs == nil {
+		extraArgs = []string{}
+	}
 	rawURL = strings.TrimSpace(rawURL)
 	if rawURL == "" {
 		return nil, errors.New("url is required")
 	}
+	if err := CheckDownloadsPaused(); err != nil {
+		return nil, err
+	}
+	// Metadata-only jobs download no media, so there is nothing to cap.
+	if !opts.MetadataOnly {
+		capped, err := CapDownloadArgs(ctx, q, archivedBy, extraArgs)
+		if err != nil {
+			return nil, err
+		}
+		extraArgs = capped
+	}
 
-	if videoid.IsPlaylistOrChannelURL(rawURL) {
+	if opts.ExpandPlaylist || videoid.IsPlaylistOrChannelURL(rawURL) {
 		job, err := q.EnqueuePlaylistJob(ctx, &db.EnqueuePlaylistJobParams{
-			URL:        rawURL,
-			ArchivedBy: archivedBy,
+			URL:          rawURL,
+			ArchivedBy:   archivedBy,
+			ExtraArgs:    extraArgs,
+			MetadataOnly: opts.MetadataOnly,
 		})
 		if err != nil {
 			return nil, err
@@ -43,16 +104,17 @@ func EnqueueURL(ctx context.Context, q *db.Queries, rawURL string, archivedBy pg
 
 	refresh := false
 	if existing, err := q.SelectVideoBySrc(ctx, rawURL); err == nil && existing != nil {
-		refresh = true
+		refresh = !existing.MetadataOnly || opts.MetadataOnly
 	} else if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		return nil, err
 	}
 
 	job, err := q.EnqueueDownloadJob(ctx, &db.EnqueueDownloadJobParams{
-		URL:        rawURL,
-		ArchivedBy: archivedBy,
-		Refresh:    refresh,
-		ExtraArgs:  []string{},
+		URL:          rawURL,
+		ArchivedBy:   archivedBy,
+		Refresh:      refresh,
+		ExtraArgs:    extraArgs,
+		MetadataOnly: opts.MetadataOnly,
 	})
 	if err != nil {
 		return nil, err
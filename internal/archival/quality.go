@@ -0,0 +1,67 @@
+package archival
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ytdlp"
+)
+
+// ErrQualityCap is returned when a user's download resolution cap cannot be
+// applied to the requested format selector.
+var ErrQualityCap = errors.New("cannot apply download quality cap")
+
+// DownloadHeightCaps are the resolutions offered for download caps, tallest
+// first. 0 (best available) is always allowed as well.
+var DownloadHeightCaps = []int32{2160, 1440, 1080, 720, 480, 360}
+
+// ValidDownloadHeightCap reports whether h is 0 or one of DownloadHeightCaps.
+func ValidDownloadHeightCap(h int32) bool {
+	if h == 0 {
+		return true
+	}
+	for _, c := range DownloadHeightCaps {
+		if c == h {
+			return true
+		}
+	}
+	return false
+}
+
+// DownloadHeightCap returns the maximum video height a user may archive, or
+// 0 for no cap: the user's own override when set, else the cap for their role.
+func DownloadHeightCap(ctx context.Context, q *db.Queries, userID pgtype.UUID) (int32, error) {
+	row, err := q.GetUserDownloadCap(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || db.IsUndefinedColumnErr(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if row.DownloadMaxHeight != nil {
+		return *row.DownloadMaxHeight, nil
+	}
+	if row.Role == db.UserRoleAdmin {
+		return row.AdminMaxHeight, nil
+	}
+	return row.UserMaxHeight, nil
+}
+
+// CapDownloadArgs applies the user's download resolution cap to yt-dlp args
+// (see ytdlp.CapDownloadArgs). A selector that would be invalid once capped
+// returns an error wrapping ErrQualityCap.
+func CapDownloadArgs(ctx context.Context, q *db.Queries, userID pgtype.UUID, args []string) ([]string, error) {
+	h, err := DownloadHeightCap(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	capped, err := ytdlp.CapDownloadArgs(args, int(h))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQualityCap, err)
+	}
+	return capped, nil
+}
@@ -8,15 +8,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"os"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"thirdcoast.systems/rewind/internal/db"
+	"thirdcoast.systems/rewind/pkg/ytdlp"
 )
 
+// Options controls how a fetch is reconciled with the stored archive.
+type Options struct {
+	// MarkDeleted flags stored comments that are missing from this fetch as
+	// deleted. Only safe when the fetch returned the complete comment set;
+	// yt-dlp comment limits can otherwise make live comments look deleted.
+	MarkDeleted bool
+}
+
+// MarkDeletedEnabled reports whether COMMENTS_MARK_DELETED is set, opting in
+// to deletion tracking on refresh.
+func MarkDeletedEnabled() bool {
+	v := strings.TrimSpace(os.Getenv("COMMENTS_MARK_DELETED"))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
 // IngestFromInfoJSON extracts the "comments" array from a raw info.json byte
-// slice and upserts them into video_comments. Best-effort: missing or empty
+// slice and merges it into video_comments: new comments are added, known ones
+// are updated in place (by comment id) with their previous text archived in
+// video_comment_edits when it changed, and, with opts.MarkDeleted, comments
+// no longer returned are flagged deleted. Best-effort: missing or empty
 // comments are silently skipped.
-func IngestFromInfoJSON(ctx context.Context, q *db.Queries, videoID pgtype.UUID, source string, rawInfoJSON []byte) error {
+func IngestFromInfoJSON(ctx context.Context, q *db.Queries, videoID pgtype.UUID, source string, rawInfoJSON []byte, opts Options) error {
 	// Quick check: does the JSON even contain a comments key?
 	var envelope struct {
 		Comments json.RawMessage `json:"comments"`
@@ -41,6 +62,7 @@ func IngestFromInfoJSON(ctx context.Context, q *db.Queries, videoID pgtype.UUID,
 
 	// Batch in chunks of 500 to avoid massive single queries
 	const batchSize = 500
+	var editedTotal int64
 	for i := 0; i < len(arr); i += batchSize {
 		end := i + batchSize
 		if end > len(arr) {
@@ -53,6 +75,16 @@ func IngestFromInfoJSON(ctx context.Context, q *db.Queries, videoID pgtype.UUID,
 			return fmt.Errorf("marshal comment chunk: %w", err)
 		}
 
+		edited, err := q.ArchiveEditedVideoComments(ctx, &db.ArchiveEditedVideoCommentsParams{
+			VideoID:      videoID,
+			Source:       source,
+			CommentsJson: chunkJSON,
+		})
+		if err != nil {
+			return fmt.Errorf("archive edited comments batch %d-%d: %w", i, end, err)
+		}
+		editedTotal += edited
+
 		if err := q.UpsertVideoCommentsFromJSON(ctx, &db.UpsertVideoCommentsFromJSONParams{
 			VideoID:      videoID,
 			Source:       source,
@@ -62,6 +94,40 @@ func IngestFromInfoJSON(ctx context.Context, q *db.Queries, videoID pgtype.UUID,
 		}
 	}
 
-	slog.Info("comments ingested successfully", "video_id", videoID, "total", len(arr))
+	var deleted int64
+	if opts.MarkDeleted {
+		ids := commentIDs(arr)
+		if len(ids) >= ytdlp.MaxComments {
+			// The fetch hit yt-dlp's comment cap, so absent comments may
+			// simply not have been fetched.
+			slog.Info("skipping deleted-comment detection for capped fetch", "video_id", videoID, "count", len(ids))
+		} else if len(ids) > 0 {
+			n, err := q.MarkMissingVideoCommentsDeleted(ctx, &db.MarkMissingVideoCommentsDeletedParams{
+				VideoID: videoID,
+				Source:  source,
+				SeenIds: ids,
+			})
+			if err != nil {
+				return fmt.Errorf("mark deleted comments: %w", err)
+			}
+			deleted = n
+		}
+	}
+
+	slog.Info("comments ingested successfully", "video_id", videoID, "total", len(arr), "edited", editedTotal, "marked_deleted", deleted)
 	return nil
 }
+
+// commentIDs returns the non-empty "id" of each comment object.
+func commentIDs(arr []json.RawMessage) []string {
+	ids := make([]string, 0, len(arr))
+	for _, raw := range arr {
+		var c struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(raw, &c) == nil && c.ID != "" {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AssetLockPollInterval is how often a waiting caller retries the per-video
+// asset lock.
+var AssetLockPollInterval = 500 * time.Millisecond
+
+// AdvisoryLockConn is a pinned database connection that can hold a
+// session-level advisory lock. Unlock must happen on the connection that took
+// the lock, so the lock and its connection are released together.
+type AdvisoryLockConn interface {
+	TryAdvisoryLock(ctx context.Context, lockID int64) (bool, error)
+	AdvisoryUnlock(ctx context.Context, lockID int64) (bool, error)
+	Release()
+}
+
+type pooledAdvisoryLockConn struct {
+	*Queries
+	conn *pgxpool.Conn
+}
+
+func (c pooledAdvisoryLockConn) Release() { c.conn.Release() }
+
+// AcquireAdvisoryLockConn pins a pool connection for advisory locking.
+func (db *DatabaseConnection) AcquireAdvisoryLockConn(ctx context.Context) (AdvisoryLockConn, error) {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pooledAdvisoryLockConn{Queries: New(conn), conn: conn}, nil
+}
+
+// AdvisoryLockID derives a bigint advisory lock key from a scope and an id.
+func AdvisoryLockID(scope, id string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(scope))
+	_, _ = h.Write([]byte(":"))
+	_, _ = h.Write([]byte(id))
+	return int64(h.Sum64())
+}
+
+// WithVideoAssetLock runs fn while holding the per-video asset lock, waiting
+// for any other writer of the same video's directory (ingest catch-up,
+// regeneration or the web service) to finish first.
+func (db *DatabaseConnection) WithVideoAssetLock(ctx context.Context, videoID string, fn func() error) error {
+	release, _, err := db.LockVideoAssets(ctx, videoID, true)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+// LockVideoAssets takes the per-video asset lock shared by every path that
+// writes into a video's directory. With wait false it returns acquired=false
+// immediately when another worker holds the lock. The returned release func
+// is always safe to call.
+func (db *DatabaseConnection) LockVideoAssets(ctx context.Context, videoID string, wait bool) (release func(), acquired bool, err error) {
+	return LockVideoAssetsWith(ctx, db.AcquireAdvisoryLockConn, videoID, wait)
+}
+
+// LockVideoAssetsWith is LockVideoAssets with the connection source supplied
+// by the caller.
+func LockVideoAssetsWith(ctx context.Context, acquire func(context.Context) (AdvisoryLockConn, error), videoID string, wait bool) (func(), bool, error) {
+	noop := func() {}
+	lockID := AdvisoryLockID("video-assets", videoID)
+	for {
+		conn, err := acquire(ctx)
+		if err != nil {
+			return noop, false, fmt.Errorf("asset lock acquire conn: %w", err)
+		}
+		ok, err := conn.TryAdvisoryLock(ctx, lockID)
+		if err != nil {
+			conn.Release()
+			return noop, false, fmt.Errorf("asset lock: %w", err)
+		}
+		if ok {
+			return func() {
+				// Unlock with a fresh context so a cancelled job still frees
+				// the lock before the connection goes back to the pool.
+				if _, err := conn.AdvisoryUnlock(context.Background(), lockID); err != nil {
+					slog.Warn("asset lock unlock failed", "video_id", videoID, "error", err)
+				}
+				conn.Release()
+			}, true, nil
+		}
+		// Don't pin a connection while waiting.
+		conn.Release()
+		if !wait {
+			return noop, false, nil
+		}
+		select {
+		case <-ctx.Done():
+			return noop, false, ctx.Err()
+		case <-time.After(AssetLockPollInterval):
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeLockTable mimics PostgreSQL session-level advisory locks: a lock is
+// owned by the connection that took it and only that connection can free it.
+type fakeLockTable struct {
+	mu   sync.Mutex
+	held map[int64]*fakeLockConn
+	open atomic.Int32
+}
+
+type fakeLockConn struct{ t *fakeLockTable }
+
+func newFakeLockTable() *fakeLockTable {
+	return &fakeLockTable{held: map[int64]*fakeLockConn{}}
+}
+
+func (t *fakeLockTable) acquire(context.Context) (AdvisoryLockConn, error) {
+	t.open.Add(1)
+	return &fakeLockConn{t: t}, nil
+}
+
+func (c *fakeLockConn) TryAdvisoryLock(_ context.Context, id int64) (bool, error) {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+	if owner, ok := c.t.held[id]; ok && owner != c {
+		return false, nil
+	}
+	c.t.held[id] = c
+	return true, nil
+}
+
+func (c *fakeLockConn) AdvisoryUnlock(_ context.Context, id int64) (bool, error) {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+	if c.t.held[id] != c {
+		return false, nil
+	}
+	delete(c.t.held, id)
+	return true, nil
+}
+
+func (c *fakeLockConn) Release() { c.t.open.Add(-1) }
+
+func TestLockVideoAssets_TwoWorkersNeverOverlap(t *testing.T) {
+	prev := AssetLockPollInterval
+	AssetLockPollInterval = time.Millisecond
+	defer func() { AssetLockPollInterval = prev }()
+
+	table := newFakeLockTable()
+	ctx := context.Background()
+
+	var active, maxActive, runs atomic.Int32
+	worker := func() {
+		release, ok, err := LockVideoAssetsWith(ctx, table.acquire, "video-1", true)
+		if err != nil || !ok {
+			t.Errorf("lock: ok=%v err=%v", ok, err)
+			return
+		}
+		defer release()
+		n := active.Add(1)
+		for {
+			m := maxActive.Load()
+			if n <= m || maxActive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond) // "write" the video's assets
+		active.Add(-1)
+		runs.Add(1)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker()
+		}()
+	}
+	wg.Wait()
+
+	if runs.Load() != 2 {
+		t.Fatalf("runs = %d, want both workers to finish", runs.Load())
+	}
+	if maxActive.Load() != 1 {
+		t.Errorf("max concurrent holders = %d, want 1", maxActive.Load())
+	}
+	if table.open.Load() != 0 {
+		t.Errorf("%d connections left pinned", table.open.Load())
+	}
+}
+
+func TestLockVideoAssets_TrySkipsWhenBusy(t *testing.T) {
+	table := newFakeLockTable()
+	ctx := context.Background()
+
+	release, ok, err := LockVideoAssetsWith(ctx, table.acquire, "video-1", false)
+	if err != nil || !ok {
+		t.Fatalf("first lock: ok=%v err=%v", ok, err)
+	}
+
+	skip, ok, err := LockVideoAssetsWith(ctx, table.acquire, "video-1", false)
+	if err != nil || ok {
+		t.Fatalf("second lock while held: ok=%v err=%v, want busy", ok, err)
+	}
+	skip() // no-op release must be safe
+
+	// A different video is independent.
+	other, ok, _ := LockVideoAssetsWith(ctx, table.acquire, "video-2", false)
+	if !ok {
+		t.Fatal("lock on another video should not be blocked")
+	}
+	other()
+
+	release()
+	again, ok, _ := LockVideoAssetsWith(ctx, table.acquire, "video-1", false)
+	if !ok {
+		t.Fatal("lock should be free after release")
+	}
+	again()
+
+	if table.open.Load() != 0 {
+		t.Errorf("%d connections left pinned", table.open.Load())
+	}
+}
+
+func TestLockVideoAssets_WaitHonoursContext(t *testing.T) {
+	prev := AssetLockPollInterval
+	AssetLockPollInterval = time.Millisecond
+	defer func() { AssetLockPollInterval = prev }()
+
+	table := newFakeLockTable()
+	release, _, _ := LockVideoAssetsWith(context.Background(), table.acquire, "video-1", false)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, ok, err := LockVideoAssetsWith(ctx, table.acquire, "video-1", true); ok || err == nil {
+		t.Errorf("waiting on a held lock past the deadline: ok=%v err=%v, want context error", ok, err)
+	}
+}
@@ -54,3 +54,34 @@ func (a AssetMap) TextValue() (pgtype.Text, error) {
 	}
 	return pgtype.Text{String: string(b), Valid: true}, nil
 }
+
+// ValidAssetScopes are the individual asset types an asset regeneration job
+// (ingest_jobs.asset_scope) may be limited to. A NULL scope means "all".
+var ValidAssetScopes = map[string]bool{
+	"thumbnail": true,
+	"preview":   true,
+	"seek":      true,
+	"waveform":  true,
+	"captions":  true,
+	"streams":   true,
+	"chapters":  true,
+}
+
+// ValidWhisperModels are the Whisper model names a caption regeneration job
+// (ingest_jobs.whisper_model) may ask for.
+var ValidWhisperModels = map[string]bool{
+	"tiny":           true,
+	"tiny.en":        true,
+	"base":           true,
+	"base.en":        true,
+	"small":          true,
+	"small.en":       true,
+	"medium":         true,
+	"medium.en":      true,
+	"large":          true,
+	"large-v1":       true,
+	"large-v2":       true,
+	"large-v3":       true,
+	"large-v3-turbo": true,
+	"turbo":          true,
+}
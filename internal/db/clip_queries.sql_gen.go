@@ -12,6 +12,61 @@ import (
 	"thirdcoast.systems/rewind/pkg/utils/crops"
 )
 
+const claimClipExportRungs = `-- name: ClaimClipExportRungs :many
+UPDATE clip_exports
+SET status = 'processing',
+    locked_at = NOW(),
+    locked_by = $1,
+    started_at = NOW(),
+    attempts = attempts + 1,
+    updated_at = NOW()
+WHERE ladder_parent_id = $2
+  AND status IN ('queued', 'processing')
+RETURNING id, spec
+`
+
+type ClaimClipExportRungsParams struct {
+	LockedBy       *string     `db:"locked_by" json:"LockedBy"`
+	LadderParentID pgtype.UUID `db:"ladder_parent_id" json:"LadderParentID"`
+}
+
+type ClaimClipExportRungsRow struct {
+	ID   pgtype.UUID `db:"id" json:"ID"`
+	Spec []byte      `db:"spec" json:"Spec"`
+}
+
+// Mark a ladder lead's pending rungs as processing by the same worker
+//
+//	UPDATE clip_exports
+//	SET status = 'processing',
+//	    locked_at = NOW(),
+//	    locked_by = $1,
+//	    started_at = NOW(),
+//	    attempts = attempts + 1,
+//	    updated_at = NOW()
+//	WHERE ladder_parent_id = $2
+//	  AND status IN ('queued', 'processing')
+//	RETURNING id, spec
+func (q *Queries) ClaimClipExportRungs(ctx context.Context, arg *ClaimClipExportRungsParams) ([]*ClaimClipExportRungsRow, error) {
+	rows, err := q.db.Query(ctx, claimClipExportRungs, arg.LockedBy, arg.LadderParentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ClaimClipExportRungsRow
+	for rows.Next() {
+		var i ClaimClipExportRungsRow
+		if err := rows.Scan(&i.ID, &i.Spec); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const clearClipExportPID = `-- name: ClearClipExportPID :exec
 UPDATE clip_exports
 SET pid = NULL,
@@ -30,6 +85,25 @@ func (q *Queries) ClearClipExportPID(ctx context.Context, id pgtype.UUID) error
 	return err
 }
 
+const countActiveClipExportsByUser = `-- name: CountActiveClipExportsByUser :one
+SELECT COUNT(*) FROM clip_exports
+WHERE created_by = $1
+  AND status IN ('queued', 'processing')
+`
+
+// CountActiveClipExportsByUser returns how many of the user's exports are
+// queued or processing, for the per-user export cap.
+//
+//	SELECT COUNT(*) FROM clip_exports
+//	WHERE created_by = $1
+//	  AND status IN ('queued', 'processing')
+func (q *Queries) CountActiveClipExportsByUser(ctx context.Context, createdBy pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveClipExportsByUser, createdBy)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countClipExports = `-- name: CountClipExports :one
 SELECT COUNT(*) FROM clip_exports
 `
@@ -170,6 +244,43 @@ func (q *Queries) CreateClipExport(ctx context.Context, arg *CreateClipExportPar
 	return id, err
 }
 
+const createClipExportRung = `-- name: CreateClipExportRung :one
+INSERT INTO clip_exports (clip_id, created_by, format, variant, spec, clip_updated_at, ladder_parent_id, file_path, status, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, '', 'queued', NOW(), NOW())
+RETURNING id
+`
+
+type CreateClipExportRungParams struct {
+	ClipID         pgtype.UUID        `db:"clip_id" json:"ClipID"`
+	CreatedBy      pgtype.UUID        `db:"created_by" json:"CreatedBy"`
+	Format         string             `db:"format" json:"Format"`
+	Variant        string             `db:"variant" json:"Variant"`
+	Spec           []byte             `db:"spec" json:"Spec"`
+	ClipUpdatedAt  pgtype.Timestamptz `db:"clip_updated_at" json:"ClipUpdatedAt"`
+	LadderParentID pgtype.UUID        `db:"ladder_parent_id" json:"LadderParentID"`
+}
+
+// CreateClipExportRung adds a queued rung to a rendition ladder. Rungs are
+// encoded by the lead's worker, so no NOTIFY is needed.
+//
+//	INSERT INTO clip_exports (clip_id, created_by, format, variant, spec, clip_updated_at, ladder_parent_id, file_path, status, created_at, updated_at)
+//	VALUES ($1, $2, $3, $4, $5, $6, $7, '', 'queued', NOW(), NOW())
+//	RETURNING id
+func (q *Queries) CreateClipExportRung(ctx context.Context, arg *CreateClipExportRungParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, createClipExportRung,
+		arg.ClipID,
+		arg.CreatedBy,
+		arg.Format,
+		arg.Variant,
+		arg.Spec,
+		arg.ClipUpdatedAt,
+		arg.LadderParentID,
+	)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const deleteAllClipExports = `-- name: DeleteAllClipExports :exec
 DELETE FROM clip_exports
 `
@@ -246,6 +357,7 @@ SET status = 'processing',
 WHERE id = (
     SELECT id FROM clip_exports
     WHERE status = 'queued'
+      AND ladder_parent_id IS NULL
       AND (locked_at IS NULL OR locked_at < NOW() - INTERVAL '10 minutes')
     ORDER BY created_at ASC
     LIMIT 1
@@ -280,6 +392,7 @@ type FindAndLockPendingClipExportRow struct {
 //	WHERE id = (
 //	    SELECT id FROM clip_exports
 //	    WHERE status = 'queued'
+//	      AND ladder_parent_id IS NULL
 //	      AND (locked_at IS NULL OR locked_at < NOW() - INTERVAL '10 minutes')
 //	    ORDER BY created_at ASC
 //	    LIMIT 1
@@ -308,6 +421,16 @@ WHERE clip_id = $1
   AND created_by = $2
   AND format = $3
   AND variant = $4
+  AND COALESCE((spec->>'loop')::int, 0) = $5::int
+  AND COALESCE((spec->>'gop')::int, 0) = $6::int
+  AND COALESCE((spec->>'target_bitrate')::int, 0) = $7::int
+  AND COALESCE((spec->>'fps')::int, 0) = $8::int
+  AND COALESCE((spec->>'max_width')::int, 0) = $9::int
+  AND COALESCE(spec->>'quality', '') = $10::text
+  AND COALESCE(spec->'filters', '[]'::jsonb) = $11::jsonb
+  AND COALESCE(spec->'pip', 'null'::jsonb) = $12::jsonb
+  AND COALESCE((spec->>'accurate')::boolean, false) = $13::boolean
+  AND COALESCE((spec->>'height')::int, 0) = 0
   AND status IN ('queued', 'processing')
   AND updated_at > NOW() - INTERVAL '5 minutes'
 ORDER BY created_at DESC
@@ -315,10 +438,19 @@ LIMIT 1
 `
 
 type FindOrCreatePendingClipExportParams struct {
-	ClipID    pgtype.UUID `db:"clip_id" json:"ClipID"`
-	CreatedBy pgtype.UUID `db:"created_by" json:"CreatedBy"`
-	Format    string      `db:"format" json:"Format"`
-	Variant   string      `db:"variant" json:"Variant"`
+	ClipID        pgtype.UUID `db:"clip_id" json:"ClipID"`
+	CreatedBy     pgtype.UUID `db:"created_by" json:"CreatedBy"`
+	Format        string      `db:"format" json:"Format"`
+	Variant       string      `db:"variant" json:"Variant"`
+	LoopCount     int32       `db:"loop_count" json:"LoopCount"`
+	Gop           int32       `db:"gop" json:"Gop"`
+	TargetBitrate int32       `db:"target_bitrate" json:"TargetBitrate"`
+	Fps           int32       `db:"fps" json:"Fps"`
+	MaxWidth      int32       `db:"max_width" json:"MaxWidth"`
+	Quality       string      `db:"quality" json:"Quality"`
+	Filters       []byte      `db:"filters" json:"Filters"`
+	Pip           []byte      `db:"pip" json:"Pip"`
+	Accurate      bool        `db:"accurate" json:"Accurate"`
 }
 
 type FindOrCreatePendingClipExportRow struct {
@@ -336,6 +468,16 @@ type FindOrCreatePendingClipExportRow struct {
 //	  AND created_by = $2
 //	  AND format = $3
 //	  AND variant = $4
+//	  AND COALESCE((spec->>'loop')::int, 0) = $5::int
+//	  AND COALESCE((spec->>'gop')::int, 0) = $6::int
+//	  AND COALESCE((spec->>'target_bitrate')::int, 0) = $7::int
+//	  AND COALESCE((spec->>'fps')::int, 0) = $8::int
+//	  AND COALESCE((spec->>'max_width')::int, 0) = $9::int
+//	  AND COALESCE(spec->>'quality', '') = $10::text
+//	  AND COALESCE(spec->'filters', '[]'::jsonb) = $11::jsonb
+//	  AND COALESCE(spec->'pip', 'null'::jsonb) = $12::jsonb
+//	  AND COALESCE((spec->>'accurate')::boolean, false) = $13::boolean
+//	  AND COALESCE((spec->>'height')::int, 0) = 0
 //	  AND status IN ('queued', 'processing')
 //	  AND updated_at > NOW() - INTERVAL '5 minutes'
 //	ORDER BY created_at DESC
@@ -346,6 +488,15 @@ func (q *Queries) FindOrCreatePendingClipExport(ctx context.Context, arg *FindOr
 		arg.CreatedBy,
 		arg.Format,
 		arg.Variant,
+		arg.LoopCount,
+		arg.Gop,
+		arg.TargetBitrate,
+		arg.Fps,
+		arg.MaxWidth,
+		arg.Quality,
+		arg.Filters,
+		arg.Pip,
+		arg.Accurate,
 	)
 	var i FindOrCreatePendingClipExportRow
 	err := row.Scan(
@@ -448,6 +599,16 @@ WHERE clip_exports.clip_id = $1
   AND clip_exports.created_by = $2
   AND clip_exports.format = $3
   AND clip_exports.variant = $4
+  AND COALESCE((clip_exports.spec->>'loop')::int, 0) = $5::int
+  AND COALESCE((clip_exports.spec->>'gop')::int, 0) = $6::int
+  AND COALESCE((clip_exports.spec->>'target_bitrate')::int, 0) = $7::int
+  AND COALESCE((clip_exports.spec->>'fps')::int, 0) = $8::int
+  AND COALESCE((clip_exports.spec->>'max_width')::int, 0) = $9::int
+  AND COALESCE(clip_exports.spec->>'quality', '') = $10::text
+  AND COALESCE(clip_exports.spec->'filters', '[]'::jsonb) = $11::jsonb
+  AND COALESCE(clip_exports.spec->'pip', 'null'::jsonb) = $12::jsonb
+  AND COALESCE((clip_exports.spec->>'accurate')::boolean, false) = $13::boolean
+  AND COALESCE((clip_exports.spec->>'height')::int, 0) = 0
   AND clip_exports.status = 'ready'
   AND clip_exports.clip_updated_at >= (SELECT clips.updated_at FROM clips WHERE clips.id = $1)
 ORDER BY clip_exports.created_at DESC
@@ -455,10 +616,19 @@ LIMIT 1
 `
 
 type FindReusableClipExportParams struct {
-	ClipID    pgtype.UUID `db:"clip_id" json:"ClipID"`
-	CreatedBy pgtype.UUID `db:"created_by" json:"CreatedBy"`
-	Format    string      `db:"format" json:"Format"`
-	Variant   string      `db:"variant" json:"Variant"`
+	ClipID        pgtype.UUID `db:"clip_id" json:"ClipID"`
+	CreatedBy     pgtype.UUID `db:"created_by" json:"CreatedBy"`
+	Format        string      `db:"format" json:"Format"`
+	Variant       string      `db:"variant" json:"Variant"`
+	LoopCount     int32       `db:"loop_count" json:"LoopCount"`
+	Gop           int32       `db:"gop" json:"Gop"`
+	TargetBitrate int32       `db:"target_bitrate" json:"TargetBitrate"`
+	Fps           int32       `db:"fps" json:"Fps"`
+	MaxWidth      int32       `db:"max_width" json:"MaxWidth"`
+	Quality       string      `db:"quality" json:"Quality"`
+	Filters       []byte      `db:"filters" json:"Filters"`
+	Pip           []byte      `db:"pip" json:"Pip"`
+	Accurate      bool        `db:"accurate" json:"Accurate"`
 }
 
 type FindReusableClipExportRow struct {
@@ -466,7 +636,10 @@ type FindReusableClipExportRow struct {
 	FilePath string      `db:"file_path" json:"FilePath"`
 }
 
-// FindReusableClipExport
+// FindReusableClipExport returns the user's newest ready export of the clip
+// with an identical spec (format, variant, loop, GOP, quality, target bitrate,
+// GIF/WebP frame rate and width, filters, PiP inset and seek mode).
+// Ladder renditions are scaled, so they never stand in for a full-size export.
 //
 //	SELECT id, file_path
 //	FROM clip_exports
@@ -474,6 +647,16 @@ type FindReusableClipExportRow struct {
 //	  AND clip_exports.created_by = $2
 //	  AND clip_exports.format = $3
 //	  AND clip_exports.variant = $4
+//	  AND COALESCE((clip_exports.spec->>'loop')::int, 0) = $5::int
+//	  AND COALESCE((clip_exports.spec->>'gop')::int, 0) = $6::int
+//	  AND COALESCE((clip_exports.spec->>'target_bitrate')::int, 0) = $7::int
+//	  AND COALESCE((clip_exports.spec->>'fps')::int, 0) = $8::int
+//	  AND COALESCE((clip_exports.spec->>'max_width')::int, 0) = $9::int
+//	  AND COALESCE(clip_exports.spec->>'quality', '') = $10::text
+//	  AND COALESCE(clip_exports.spec->'filters', '[]'::jsonb) = $11::jsonb
+//	  AND COALESCE(clip_exports.spec->'pip', 'null'::jsonb) = $12::jsonb
+//	  AND COALESCE((clip_exports.spec->>'accurate')::boolean, false) = $13::boolean
+//	  AND COALESCE((clip_exports.spec->>'height')::int, 0) = 0
 //	  AND clip_exports.status = 'ready'
 //	  AND clip_exports.clip_updated_at >= (SELECT clips.updated_at FROM clips WHERE clips.id = $1)
 //	ORDER BY clip_exports.created_at DESC
@@ -484,6 +667,15 @@ func (q *Queries) FindReusableClipExport(ctx context.Context, arg *FindReusableC
 		arg.CreatedBy,
 		arg.Format,
 		arg.Variant,
+		arg.LoopCount,
+		arg.Gop,
+		arg.TargetBitrate,
+		arg.Fps,
+		arg.MaxWidth,
+		arg.Quality,
+		arg.Filters,
+		arg.Pip,
+		arg.Accurate,
 	)
 	var i FindReusableClipExportRow
 	err := row.Scan(&i.ID, &i.FilePath)
@@ -563,6 +755,41 @@ func (q *Queries) FinishClipExportReady(ctx context.Context, arg *FinishClipExpo
 	return err
 }
 
+const finishClipExportRungsError = `-- name: FinishClipExportRungsError :exec
+UPDATE clip_exports
+SET status = 'error',
+    last_error = $1,
+    finished_at = NOW(),
+    locked_at = NULL,
+    locked_by = NULL,
+    pid = NULL,
+    updated_at = NOW()
+WHERE ladder_parent_id = $2
+  AND status = 'processing'
+`
+
+type FinishClipExportRungsErrorParams struct {
+	LastError      *string     `db:"last_error" json:"LastError"`
+	LadderParentID pgtype.UUID `db:"ladder_parent_id" json:"LadderParentID"`
+}
+
+// Fail a ladder lead's in-flight rungs along with the lead
+//
+//	UPDATE clip_exports
+//	SET status = 'error',
+//	    last_error = $1,
+//	    finished_at = NOW(),
+//	    locked_at = NULL,
+//	    locked_by = NULL,
+//	    pid = NULL,
+//	    updated_at = NOW()
+//	WHERE ladder_parent_id = $2
+//	  AND status = 'processing'
+func (q *Queries) FinishClipExportRungsError(ctx context.Context, arg *FinishClipExportRungsErrorParams) error {
+	_, err := q.db.Exec(ctx, finishClipExportRungsError, arg.LastError, arg.LadderParentID)
+	return err
+}
+
 const getClip = `-- name: GetClip :one
 SELECT id, video_id, start_ts, end_ts, duration, created_at, updated_at, created_by, title, description, color, tags, crops, filter_stack, shot_list FROM clips
 WHERE id = $1
@@ -628,29 +855,34 @@ func (q *Queries) GetClipExportByID(ctx context.Context, id pgtype.UUID) (*GetCl
 const getClipExportForDownload = `-- name: GetClipExportForDownload :one
 SELECT ce.file_path, ce.format, ce.status, ce.clip_id, ce.variant,
        COALESCE(c.title, '') AS clip_title,
-       c.crops
+       c.crops,
+       v.title AS video_title
 FROM clip_exports ce
 JOIN clips c ON c.id = ce.clip_id
+JOIN videos v ON v.id = c.video_id
 WHERE ce.id = $1
 `
 
 type GetClipExportForDownloadRow struct {
-	FilePath  string          `db:"file_path" json:"FilePath"`
-	Format    string          `db:"format" json:"Format"`
-	Status    ExportStatus    `db:"status" json:"Status"`
-	ClipID    pgtype.UUID     `db:"clip_id" json:"ClipID"`
-	Variant   string          `db:"variant" json:"Variant"`
-	ClipTitle string          `db:"clip_title" json:"ClipTitle"`
-	Crops     crops.CropArray `db:"crops" json:"Crops"`
+	FilePath   string          `db:"file_path" json:"FilePath"`
+	Format     string          `db:"format" json:"Format"`
+	Status     ExportStatus    `db:"status" json:"Status"`
+	ClipID     pgtype.UUID     `db:"clip_id" json:"ClipID"`
+	Variant    string          `db:"variant" json:"Variant"`
+	ClipTitle  string          `db:"clip_title" json:"ClipTitle"`
+	Crops      crops.CropArray `db:"crops" json:"Crops"`
+	VideoTitle string          `db:"video_title" json:"VideoTitle"`
 }
 
 // GetClipExportForDownload
 //
 //	SELECT ce.file_path, ce.format, ce.status, ce.clip_id, ce.variant,
 //	       COALESCE(c.title, '') AS clip_title,
-//	       c.crops
+//	       c.crops,
+//	       v.title AS video_title
 //	FROM clip_exports ce
 //	JOIN clips c ON c.id = ce.clip_id
+//	JOIN videos v ON v.id = c.video_id
 //	WHERE ce.id = $1
 func (q *Queries) GetClipExportForDownload(ctx context.Context, id pgtype.UUID) (*GetClipExportForDownloadRow, error) {
 	row := q.db.QueryRow(ctx, getClipExportForDownload, id)
@@ -663,6 +895,39 @@ func (q *Queries) GetClipExportForDownload(ctx context.Context, id pgtype.UUID)
 		&i.Variant,
 		&i.ClipTitle,
 		&i.Crops,
+		&i.VideoTitle,
+	)
+	return &i, err
+}
+
+const getClipExportProgressHistory = `-- name: GetClipExportProgressHistory :one
+SELECT id, status, started_at, finished_at, progress_history
+FROM clip_exports
+WHERE id = $1
+`
+
+type GetClipExportProgressHistoryRow struct {
+	ID              pgtype.UUID        `db:"id" json:"ID"`
+	Status          ExportStatus       `db:"status" json:"Status"`
+	StartedAt       pgtype.Timestamptz `db:"started_at" json:"StartedAt"`
+	FinishedAt      pgtype.Timestamptz `db:"finished_at" json:"FinishedAt"`
+	ProgressHistory []byte             `db:"progress_history" json:"ProgressHistory"`
+}
+
+// GetClipExportProgressHistory
+//
+//	SELECT id, status, started_at, finished_at, progress_history
+//	FROM clip_exports
+//	WHERE id = $1
+func (q *Queries) GetClipExportProgressHistory(ctx context.Context, id pgtype.UUID) (*GetClipExportProgressHistoryRow, error) {
+	row := q.db.QueryRow(ctx, getClipExportProgressHistory, id)
+	var i GetClipExportProgressHistoryRow
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.ProgressHistory,
 	)
 	return &i, err
 }
@@ -755,6 +1020,56 @@ func (q *Queries) GetClipExportStorageLimit(ctx context.Context) (int64, error)
 	return clip_export_storage_limit_bytes, err
 }
 
+const getClipExportThroughput = `-- name: GetClipExportThroughput :one
+SELECT
+    COUNT(*) FILTER (WHERE status = 'ready' AND finished_at > NOW() - INTERVAL '1 hour')::bigint AS completed_last_hour,
+    COUNT(*) FILTER (WHERE status = 'error' AND finished_at > NOW() - INTERVAL '1 hour')::bigint AS failed_last_hour,
+    COALESCE(AVG(EXTRACT(EPOCH FROM finished_at - started_at)) FILTER (
+        WHERE status = 'ready' AND started_at IS NOT NULL AND finished_at > NOW() - INTERVAL '24 hours'
+    ), 0)::float8 AS avg_encode_seconds,
+    COUNT(*) FILTER (WHERE status = 'queued')::bigint AS queued_count,
+    COUNT(*) FILTER (WHERE status = 'processing')::bigint AS processing_count,
+    COUNT(DISTINCT locked_by) FILTER (WHERE status = 'processing')::bigint AS active_workers
+FROM clip_exports
+`
+
+type GetClipExportThroughputRow struct {
+	CompletedLastHour int64   `db:"completed_last_hour" json:"CompletedLastHour"`
+	FailedLastHour    int64   `db:"failed_last_hour" json:"FailedLastHour"`
+	AvgEncodeSeconds  float64 `db:"avg_encode_seconds" json:"AvgEncodeSeconds"`
+	QueuedCount       int64   `db:"queued_count" json:"QueuedCount"`
+	ProcessingCount   int64   `db:"processing_count" json:"ProcessingCount"`
+	ActiveWorkers     int64   `db:"active_workers" json:"ActiveWorkers"`
+}
+
+// Fleet-wide encoder throughput for the admin exports dashboard: exports
+// finished in the last hour, mean encode time over the last 24 hours, the
+// current backlog and how many encoder hosts are busy.
+//
+//	SELECT
+//	    COUNT(*) FILTER (WHERE status = 'ready' AND finished_at > NOW() - INTERVAL '1 hour')::bigint AS completed_last_hour,
+//	    COUNT(*) FILTER (WHERE status = 'error' AND finished_at > NOW() - INTERVAL '1 hour')::bigint AS failed_last_hour,
+//	    COALESCE(AVG(EXTRACT(EPOCH FROM finished_at - started_at)) FILTER (
+//	        WHERE status = 'ready' AND started_at IS NOT NULL AND finished_at > NOW() - INTERVAL '24 hours'
+//	    ), 0)::float8 AS avg_encode_seconds,
+//	    COUNT(*) FILTER (WHERE status = 'queued')::bigint AS queued_count,
+//	    COUNT(*) FILTER (WHERE status = 'processing')::bigint AS processing_count,
+//	    COUNT(DISTINCT locked_by) FILTER (WHERE status = 'processing')::bigint AS active_workers
+//	FROM clip_exports
+func (q *Queries) GetClipExportThroughput(ctx context.Context) (*GetClipExportThroughputRow, error) {
+	row := q.db.QueryRow(ctx, getClipExportThroughput)
+	var i GetClipExportThroughputRow
+	err := row.Scan(
+		&i.CompletedLastHour,
+		&i.FailedLastHour,
+		&i.AvgEncodeSeconds,
+		&i.QueuedCount,
+		&i.ProcessingCount,
+		&i.ActiveWorkers,
+	)
+	return &i, err
+}
+
 const getClipForExport = `-- name: GetClipForExport :one
 SELECT c.id, c.video_id, c.start_ts, c.end_ts, c.duration, c.crops, c.filter_stack,
        c.title AS clip_title, v.video_path
@@ -903,6 +1218,45 @@ func (q *Queries) ListClipExportFilesByStatus(ctx context.Context, status Export
 	return items, nil
 }
 
+const listClipExportRungs = `-- name: ListClipExportRungs :many
+SELECT id, status, COALESCE((spec->>'height')::int, 0)::int AS height
+FROM clip_exports
+WHERE ladder_parent_id = $1
+ORDER BY height DESC
+`
+
+type ListClipExportRungsRow struct {
+	ID     pgtype.UUID  `db:"id" json:"ID"`
+	Status ExportStatus `db:"status" json:"Status"`
+	Height int32        `db:"height" json:"Height"`
+}
+
+// ListClipExportRungs returns the rungs of a rendition ladder, tallest first.
+//
+//	SELECT id, status, COALESCE((spec->>'height')::int, 0)::int AS height
+//	FROM clip_exports
+//	WHERE ladder_parent_id = $1
+//	ORDER BY height DESC
+func (q *Queries) ListClipExportRungs(ctx context.Context, ladderParentID pgtype.UUID) ([]*ListClipExportRungsRow, error) {
+	rows, err := q.db.Query(ctx, listClipExportRungs, ladderParentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListClipExportRungsRow
+	for rows.Next() {
+		var i ListClipExportRungsRow
+		if err := rows.Scan(&i.ID, &i.Status, &i.Height); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listClipExportsForAdmin = `-- name: ListClipExportsForAdmin :many
 SELECT 
     ce.id,
@@ -1056,6 +1410,77 @@ func (q *Queries) ListClipsByVideo(ctx context.Context, videoID pgtype.UUID) ([]
 	return items, nil
 }
 
+const listInFlightClipExports = `-- name: ListInFlightClipExports :many
+SELECT
+    ce.id,
+    ce.format,
+    ce.progress_pct,
+    ce.started_at,
+    ce.locked_by,
+    c.title AS clip_label,
+    v.title AS video_title
+FROM clip_exports ce
+JOIN clips c ON c.id = ce.clip_id
+JOIN videos v ON v.id = c.video_id
+WHERE ce.status = 'processing'
+ORDER BY ce.started_at ASC NULLS LAST
+LIMIT $1
+`
+
+type ListInFlightClipExportsRow struct {
+	ID          pgtype.UUID        `db:"id" json:"ID"`
+	Format      string             `db:"format" json:"Format"`
+	ProgressPct int32              `db:"progress_pct" json:"ProgressPct"`
+	StartedAt   pgtype.Timestamptz `db:"started_at" json:"StartedAt"`
+	LockedBy    *string            `db:"locked_by" json:"LockedBy"`
+	ClipLabel   string             `db:"clip_label" json:"ClipLabel"`
+	VideoTitle  string             `db:"video_title" json:"VideoTitle"`
+}
+
+// Exports currently being encoded, oldest first, for the throughput dashboard
+//
+//	SELECT
+//	    ce.id,
+//	    ce.format,
+//	    ce.progress_pct,
+//	    ce.started_at,
+//	    ce.locked_by,
+//	    c.title AS clip_label,
+//	    v.title AS video_title
+//	FROM clip_exports ce
+//	JOIN clips c ON c.id = ce.clip_id
+//	JOIN videos v ON v.id = c.video_id
+//	WHERE ce.status = 'processing'
+//	ORDER BY ce.started_at ASC NULLS LAST
+//	LIMIT $1
+func (q *Queries) ListInFlightClipExports(ctx context.Context, lim int32) ([]*ListInFlightClipExportsRow, error) {
+	rows, err := q.db.Query(ctx, listInFlightClipExports, lim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListInFlightClipExportsRow
+	for rows.Next() {
+		var i ListInFlightClipExportsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Format,
+			&i.ProgressPct,
+			&i.StartedAt,
+			&i.LockedBy,
+			&i.ClipLabel,
+			&i.VideoTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listOldestClipExportsForCleanup = `-- name: ListOldestClipExportsForCleanup :many
 SELECT id, file_path, size_bytes FROM clip_exports
 WHERE status = 'ready'
@@ -1093,6 +1518,20 @@ func (q *Queries) ListOldestClipExportsForCleanup(ctx context.Context) ([]*ListO
 	return items, nil
 }
 
+const lockClipExportsByUser = `-- name: LockClipExportsByUser :exec
+SELECT pg_advisory_xact_lock(hashtext('clip-exports'), hashtext($1::uuid::text))
+`
+
+// LockClipExportsByUser serializes the user's export enqueues until the
+// calling transaction ends, so concurrent requests cannot all count the user
+// below the export cap before any of them inserts.
+//
+//	SELECT pg_advisory_xact_lock(hashtext('clip-exports'), hashtext($1::uuid::text))
+func (q *Queries) LockClipExportsByUser(ctx context.Context, createdBy pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, lockClipExportsByUser, createdBy)
+	return err
+}
+
 const requeueAllErrorExports = `-- name: RequeueAllErrorExports :exec
 UPDATE clip_exports
 SET status = 'queued',
@@ -1102,6 +1541,10 @@ SET status = 'queued',
     progress_pct = 0,
     attempts = 0,
     last_error = NULL,
+    -- Rungs stay with their lead only if it is being requeued too.
+    ladder_parent_id = CASE
+        WHEN ladder_parent_id IN (SELECT id FROM clip_exports WHERE status = 'error') THEN ladder_parent_id
+    END,
     updated_at = NOW()
 WHERE status = 'error'
 `
@@ -1116,6 +1559,10 @@ WHERE status = 'error'
 //	    progress_pct = 0,
 //	    attempts = 0,
 //	    last_error = NULL,
+//	    -- Rungs stay with their lead only if it is being requeued too.
+//	    ladder_parent_id = CASE
+//	        WHEN ladder_parent_id IN (SELECT id FROM clip_exports WHERE status = 'error') THEN ladder_parent_id
+//	    END,
 //	    updated_at = NOW()
 //	WHERE status = 'error'
 func (q *Queries) RequeueAllErrorExports(ctx context.Context) error {
@@ -1134,6 +1581,8 @@ SET status = 'queued',
     started_at = NULL,
     finished_at = NULL,
     last_error = 'Requeued: output file was missing',
+    -- A requeued ladder rung is encoded on its own.
+    ladder_parent_id = NULL,
     updated_at = NOW()
 WHERE id = $1
 `
@@ -1150,6 +1599,8 @@ WHERE id = $1
 //	    started_at = NULL,
 //	    finished_at = NULL,
 //	    last_error = 'Requeued: output file was missing',
+//	    -- A requeued ladder rung is encoded on its own.
+//	    ladder_parent_id = NULL,
 //	    updated_at = NOW()
 //	WHERE id = $1
 func (q *Queries) RequeueClipExport(ctx context.Context, id pgtype.UUID) error {
@@ -1183,6 +1634,27 @@ func (q *Queries) ResetStuckExports(ctx context.Context) error {
 	return err
 }
 
+const setClipExportProgressHistory = `-- name: SetClipExportProgressHistory :exec
+UPDATE clip_exports
+SET progress_history = $1
+WHERE id = $2
+`
+
+type SetClipExportProgressHistoryParams struct {
+	ProgressHistory []byte      `db:"progress_history" json:"ProgressHistory"`
+	ID              pgtype.UUID `db:"id" json:"ID"`
+}
+
+// Store the encoder's downsampled progress timeline for a finished export
+//
+//	UPDATE clip_exports
+//	SET progress_history = $1
+//	WHERE id = $2
+func (q *Queries) SetClipExportProgressHistory(ctx context.Context, arg *SetClipExportProgressHistoryParams) error {
+	_, err := q.db.Exec(ctx, setClipExportProgressHistory, arg.ProgressHistory, arg.ID)
+	return err
+}
+
 const unlockClipExport = `-- name: UnlockClipExport :exec
 UPDATE clip_exports
 SET locked_at = NULL,
@@ -1325,6 +1797,31 @@ func (q *Queries) UpdateClipExportFilePath(ctx context.Context, arg *UpdateClipE
 	return err
 }
 
+const updateClipExportLadderProgress = `-- name: UpdateClipExportLadderProgress :exec
+UPDATE clip_exports
+SET progress_pct = $1,
+    updated_at = NOW()
+WHERE (id = $2 OR ladder_parent_id = $2)
+  AND status = 'processing'
+`
+
+type UpdateClipExportLadderProgressParams struct {
+	ProgressPct int32       `db:"progress_pct" json:"ProgressPct"`
+	ID          pgtype.UUID `db:"id" json:"ID"`
+}
+
+// Update progress on a ladder lead and its in-flight rungs together
+//
+//	UPDATE clip_exports
+//	SET progress_pct = $1,
+//	    updated_at = NOW()
+//	WHERE (id = $2 OR ladder_parent_id = $2)
+//	  AND status = 'processing'
+func (q *Queries) UpdateClipExportLadderProgress(ctx context.Context, arg *UpdateClipExportLadderProgressParams) error {
+	_, err := q.db.Exec(ctx, updateClipExportLadderProgress, arg.ProgressPct, arg.ID)
+	return err
+}
+
 const updateClipExportLastAccessed = `-- name: UpdateClipExportLastAccessed :exec
 UPDATE clip_exports 
 SET last_accessed_at = NOW(), updated_at = NOW() 
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/pkg/utils/cookiedomains"
+)
+
+// LoadCookieDomainFilter reads a user's cookie domain filter. Users without a
+// preferences row, or a database where the filter columns are not migrated
+// yet, get the zero filter, which keeps every cookie.
+func LoadCookieDomainFilter(ctx context.Context, q *Queries, userID pgtype.UUID) (cookiedomains.Filter, error) {
+	row, err := q.GetUserCookieDomainFilter(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || IsUndefinedColumnErr(err) {
+			return cookiedomains.Filter{}, nil
+		}
+		return cookiedomains.Filter{}, err
+	}
+	return cookiedomains.Filter{Allow: row.CookieDomainAllowlist, Block: row.CookieDomainBlocklist}, nil
+}
@@ -10,12 +10,12 @@ import (
 )
 
 const getInstanceSettings = `-- name: GetInstanceSettings :one
-SELECT id, registration_enabled, clip_export_storage_limit_bytes, admin_emails, updated_at FROM instance_settings WHERE id = 1
+SELECT id, registration_enabled, clip_export_storage_limit_bytes, admin_emails, updated_at, registration_requires_approval, download_retry_max_attempts, download_retry_backoff, download_retry_base_seconds, download_retry_cap_seconds, ingest_retry_max_attempts, ingest_retry_backoff, ingest_retry_base_seconds, ingest_retry_cap_seconds, user_download_max_height, admin_download_max_height, playback_max_height FROM instance_settings WHERE id = 1
 `
 
 // GetInstanceSettings fetches the single instance settings row
 //
-//	SELECT id, registration_enabled, clip_export_storage_limit_bytes, admin_emails, updated_at FROM instance_settings WHERE id = 1
+//	SELECT id, registration_enabled, clip_export_storage_limit_bytes, admin_emails, updated_at, registration_requires_approval, download_retry_max_attempts, download_retry_backoff, download_retry_base_seconds, download_retry_cap_seconds, ingest_retry_max_attempts, ingest_retry_backoff, ingest_retry_base_seconds, ingest_retry_cap_seconds, user_download_max_height, admin_download_max_height, playback_max_height FROM instance_settings WHERE id = 1
 func (q *Queries) GetInstanceSettings(ctx context.Context) (*InstanceSetting, error) {
 	row := q.db.QueryRow(ctx, getInstanceSettings)
 	var i InstanceSetting
@@ -25,6 +25,18 @@ func (q *Queries) GetInstanceSettings(ctx context.Context) (*InstanceSetting, er
 		&i.ClipExportStorageLimitBytes,
 		&i.AdminEmails,
 		&i.UpdatedAt,
+		&i.RegistrationRequiresApproval,
+		&i.DownloadRetryMaxAttempts,
+		&i.DownloadRetryBackoff,
+		&i.DownloadRetryBaseSeconds,
+		&i.DownloadRetryCapSeconds,
+		&i.IngestRetryMaxAttempts,
+		&i.IngestRetryBackoff,
+		&i.IngestRetryBaseSeconds,
+		&i.IngestRetryCapSeconds,
+		&i.UserDownloadMaxHeight,
+		&i.AdminDownloadMaxHeight,
+		&i.PlaybackMaxHeight,
 	)
 	return &i, err
 }
@@ -69,6 +81,127 @@ func (q *Queries) UpsertClipExportStorageLimit(ctx context.Context, limitBytes i
 	return err
 }
 
+const upsertDownloadHeightCaps = `-- name: UpsertDownloadHeightCaps :exec
+INSERT INTO instance_settings (id, registration_enabled, admin_emails, user_download_max_height, admin_download_max_height, updated_at)
+VALUES (1, TRUE, ARRAY[]::text[], $1, $2, NOW())
+ON CONFLICT (id) DO UPDATE
+SET user_download_max_height = EXCLUDED.user_download_max_height,
+    admin_download_max_height = EXCLUDED.admin_download_max_height,
+    updated_at = NOW()
+`
+
+type UpsertDownloadHeightCapsParams struct {
+	UserMaxHeight  int32 `db:"user_max_height" json:"UserMaxHeight"`
+	AdminMaxHeight int32 `db:"admin_max_height" json:"AdminMaxHeight"`
+}
+
+// UpsertDownloadHeightCaps sets the per-role download resolution caps (creates row if missing)
+//
+//	INSERT INTO instance_settings (id, registration_enabled, admin_emails, user_download_max_height, admin_download_max_height, updated_at)
+//	VALUES (1, TRUE, ARRAY[]::text[], $1, $2, NOW())
+//	ON CONFLICT (id) DO UPDATE
+//	SET user_download_max_height = EXCLUDED.user_download_max_height,
+//	    admin_download_max_height = EXCLUDED.admin_download_max_height,
+//	    updated_at = NOW()
+func (q *Queries) UpsertDownloadHeightCaps(ctx context.Context, arg *UpsertDownloadHeightCapsParams) error {
+	_, err := q.db.Exec(ctx, upsertDownloadHeightCaps, arg.UserMaxHeight, arg.AdminMaxHeight)
+	return err
+}
+
+const upsertJobRetryPolicy = `-- name: UpsertJobRetryPolicy :exec
+INSERT INTO instance_settings (
+    id, registration_enabled, admin_emails,
+    download_retry_max_attempts, download_retry_backoff, download_retry_base_seconds, download_retry_cap_seconds,
+    ingest_retry_max_attempts, ingest_retry_backoff, ingest_retry_base_seconds, ingest_retry_cap_seconds,
+    updated_at
+)
+VALUES (
+    1, TRUE, ARRAY[]::text[],
+    $1, $2, $3, $4,
+    $5, $6, $7, $8,
+    NOW()
+)
+ON CONFLICT (id) DO UPDATE
+SET download_retry_max_attempts = EXCLUDED.download_retry_max_attempts,
+    download_retry_backoff = EXCLUDED.download_retry_backoff,
+    download_retry_base_seconds = EXCLUDED.download_retry_base_seconds,
+    download_retry_cap_seconds = EXCLUDED.download_retry_cap_seconds,
+    ingest_retry_max_attempts = EXCLUDED.ingest_retry_max_attempts,
+    ingest_retry_backoff = EXCLUDED.ingest_retry_backoff,
+    ingest_retry_base_seconds = EXCLUDED.ingest_retry_base_seconds,
+    ingest_retry_cap_seconds = EXCLUDED.ingest_retry_cap_seconds,
+    updated_at = NOW()
+`
+
+type UpsertJobRetryPolicyParams struct {
+	DownloadMaxAttempts int32  `db:"download_max_attempts" json:"DownloadMaxAttempts"`
+	DownloadBackoff     string `db:"download_backoff" json:"DownloadBackoff"`
+	DownloadBaseSeconds int32  `db:"download_base_seconds" json:"DownloadBaseSeconds"`
+	DownloadCapSeconds  int32  `db:"download_cap_seconds" json:"DownloadCapSeconds"`
+	IngestMaxAttempts   int32  `db:"ingest_max_attempts" json:"IngestMaxAttempts"`
+	IngestBackoff       string `db:"ingest_backoff" json:"IngestBackoff"`
+	IngestBaseSeconds   int32  `db:"ingest_base_seconds" json:"IngestBaseSeconds"`
+	IngestCapSeconds    int32  `db:"ingest_cap_seconds" json:"IngestCapSeconds"`
+}
+
+// UpsertJobRetryPolicy sets the download and ingest retry policies (creates row if missing)
+//
+//	INSERT INTO instance_settings (
+//	    id, registration_enabled, admin_emails,
+//	    download_retry_max_attempts, download_retry_backoff, download_retry_base_seconds, download_retry_cap_seconds,
+//	    ingest_retry_max_attempts, ingest_retry_backoff, ingest_retry_base_seconds, ingest_retry_cap_seconds,
+//	    updated_at
+//	)
+//	VALUES (
+//	    1, TRUE, ARRAY[]::text[],
+//	    $1, $2, $3, $4,
+//	    $5, $6, $7, $8,
+//	    NOW()
+//	)
+//	ON CONFLICT (id) DO UPDATE
+//	SET download_retry_max_attempts = EXCLUDED.download_retry_max_attempts,
+//	    download_retry_backoff = EXCLUDED.download_retry_backoff,
+//	    download_retry_base_seconds = EXCLUDED.download_retry_base_seconds,
+//	    download_retry_cap_seconds = EXCLUDED.download_retry_cap_seconds,
+//	    ingest_retry_max_attempts = EXCLUDED.ingest_retry_max_attempts,
+//	    ingest_retry_backoff = EXCLUDED.ingest_retry_backoff,
+//	    ingest_retry_base_seconds = EXCLUDED.ingest_retry_base_seconds,
+//	    ingest_retry_cap_seconds = EXCLUDED.ingest_retry_cap_seconds,
+//	    updated_at = NOW()
+func (q *Queries) UpsertJobRetryPolicy(ctx context.Context, arg *UpsertJobRetryPolicyParams) error {
+	_, err := q.db.Exec(ctx, upsertJobRetryPolicy,
+		arg.DownloadMaxAttempts,
+		arg.DownloadBackoff,
+		arg.DownloadBaseSeconds,
+		arg.DownloadCapSeconds,
+		arg.IngestMaxAttempts,
+		arg.IngestBackoff,
+		arg.IngestBaseSeconds,
+		arg.IngestCapSeconds,
+	)
+	return err
+}
+
+const upsertPlaybackMaxHeight = `-- name: UpsertPlaybackMaxHeight :exec
+INSERT INTO instance_settings (id, registration_enabled, admin_emails, playback_max_height, updated_at)
+VALUES (1, TRUE, ARRAY[]::text[], $1, NOW())
+ON CONFLICT (id) DO UPDATE
+SET playback_max_height = EXCLUDED.playback_max_height,
+    updated_at = NOW()
+`
+
+// UpsertPlaybackMaxHeight sets the player quality menu resolution cap (creates row if missing)
+//
+//	INSERT INTO instance_settings (id, registration_enabled, admin_emails, playback_max_height, updated_at)
+//	VALUES (1, TRUE, ARRAY[]::text[], $1, NOW())
+//	ON CONFLICT (id) DO UPDATE
+//	SET playback_max_height = EXCLUDED.playback_max_height,
+//	    updated_at = NOW()
+func (q *Queries) UpsertPlaybackMaxHeight(ctx context.Context, playbackMaxHeight int32) error {
+	_, err := q.db.Exec(ctx, upsertPlaybackMaxHeight, playbackMaxHeight)
+	return err
+}
+
 const upsertRegistrationEnabled = `-- name: UpsertRegistrationEnabled :exec
 INSERT INTO instance_settings (id, registration_enabled, admin_emails, updated_at)
 VALUES (1, $1, $2, NOW())
@@ -95,3 +228,23 @@ func (q *Queries) UpsertRegistrationEnabled(ctx context.Context, arg *UpsertRegi
 	_, err := q.db.Exec(ctx, upsertRegistrationEnabled, arg.RegistrationEnabled, arg.AdminEmails)
 	return err
 }
+
+const upsertRegistrationRequiresApproval = `-- name: UpsertRegistrationRequiresApproval :exec
+INSERT INTO instance_settings (id, registration_enabled, admin_emails, registration_requires_approval, updated_at)
+VALUES (1, TRUE, ARRAY[]::text[], $1, NOW())
+ON CONFLICT (id) DO UPDATE
+SET registration_requires_approval = EXCLUDED.registration_requires_approval,
+    updated_at = NOW()
+`
+
+// UpsertRegistrationRequiresApproval sets whether new registrations need admin approval (creates row if missing)
+//
+//	INSERT INTO instance_settings (id, registration_enabled, admin_emails, registration_requires_approval, updated_at)
+//	VALUES (1, TRUE, ARRAY[]::text[], $1, NOW())
+//	ON CONFLICT (id) DO UPDATE
+//	SET registration_requires_approval = EXCLUDED.registration_requires_approval,
+//	    updated_at = NOW()
+func (q *Queries) UpsertRegistrationRequiresApproval(ctx context.Context, requiresApproval bool) error {
+	_, err := q.db.Exec(ctx, upsertRegistrationRequiresApproval, requiresApproval)
+	return err
+}
@@ -71,6 +71,36 @@ func (q *Queries) CancelDownloadJob(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const cancelQueuedDownloadJobsForUser = `-- name: CancelQueuedDownloadJobsForUser :execrows
+UPDATE download_jobs
+SET status = 'failed',
+    last_error = 'Cancelled by user',
+    finished_at = NOW(),
+    process_pid = NULL,
+    updated_at = NOW()
+WHERE archived_by = $1
+  AND status = 'queued'
+`
+
+// CancelQueuedDownloadJobsForUser cancels every queued (not yet claimed)
+// download job submitted by a user, returning the number of jobs cancelled.
+//
+//	UPDATE download_jobs
+//	SET status = 'failed',
+//	    last_error = 'Cancelled by user',
+//	    finished_at = NOW(),
+//	    process_pid = NULL,
+//	    updated_at = NOW()
+//	WHERE archived_by = $1
+//	  AND status = 'queued'
+func (q *Queries) CancelQueuedDownloadJobsForUser(ctx context.Context, archivedBy pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, cancelQueuedDownloadJobsForUser, archivedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const completePlaylistJob = `-- name: CompletePlaylistJob :exec
 UPDATE download_jobs
 SET status = 'succeeded',
@@ -104,43 +134,107 @@ func (q *Queries) CompletePlaylistJob(ctx context.Context, arg *CompletePlaylist
 	return err
 }
 
+const countCookieBlockedDownloadJobs = `-- name: CountCookieBlockedDownloadJobs :one
+SELECT COUNT(*)::bigint
+FROM download_jobs
+WHERE archived_by = $1
+  AND status = 'failed'
+  AND archived = FALSE
+  AND error_code = ANY($2::text[])
+`
+
+type CountCookieBlockedDownloadJobsParams struct {
+	ArchivedBy pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
+	ErrorCodes []string    `db:"error_codes" json:"ErrorCodes"`
+}
+
+// CountCookieBlockedDownloadJobs counts a user's failed, unarchived download
+// jobs whose error_code is one of the given cookie-fixable codes.
+//
+//	SELECT COUNT(*)::bigint
+//	FROM download_jobs
+//	WHERE archived_by = $1
+//	  AND status = 'failed'
+//	  AND archived = FALSE
+//	  AND error_code = ANY($2::text[])
+func (q *Queries) CountCookieBlockedDownloadJobs(ctx context.Context, arg *CountCookieBlockedDownloadJobsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countCookieBlockedDownloadJobs, arg.ArchivedBy, arg.ErrorCodes)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const countVideosByChannel = `-- name: CountVideosByChannel :one
+SELECT COUNT(*) FROM videos WHERE channel_id = $1
+`
+
+// CountVideosByChannel returns how many archived videos belong to a channel.
+//
+//	SELECT COUNT(*) FROM videos WHERE channel_id = $1
+func (q *Queries) CountVideosByChannel(ctx context.Context, channelID *string) (int64, error) {
+	row := q.db.QueryRow(ctx, countVideosByChannel, channelID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const dequeueDownloadJob = `-- name: DequeueDownloadJob :one
 WITH cte AS (
-    SELECT id
-    FROM download_jobs
-    WHERE status = 'queued'
-    ORDER BY created_at
+    SELECT j.id
+    FROM download_jobs j
+    WHERE j.status = 'queued'
+      AND (j.next_retry_at IS NULL OR j.next_retry_at <= NOW())
+      AND ($1::int <= 0 OR (
+          SELECT COUNT(*)
+          FROM download_jobs p
+          WHERE p.archived_by = j.archived_by
+            AND p.status = 'processing'
+      ) < $1::int)
+    ORDER BY j.priority DESC, j.created_at
     LIMIT 1
     FOR UPDATE SKIP LOCKED
 )
 UPDATE download_jobs
 SET status = 'processing',
     attempts = attempts + 1,
+    next_retry_at = NULL,
     started_at = COALESCE(started_at, NOW()),
     updated_at = NOW()
 WHERE id IN (SELECT id FROM cte)
-RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 `
 
-// DequeueDownloadJob claims one queued download job.
+// DequeueDownloadJob claims one queued download job, highest priority first.
+// Jobs waiting out a retry backoff (next_retry_at in the future) are skipped,
+// as are jobs of users who already have max_per_user jobs processing (0 means
+// no limit), so one user's playlist can't occupy every worker.
+// Callers enforcing the cap hold LockDownloadDequeue in the same transaction.
 //
 //	WITH cte AS (
-//	    SELECT id
-//	    FROM download_jobs
-//	    WHERE status = 'queued'
-//	    ORDER BY created_at
+//	    SELECT j.id
+//	    FROM download_jobs j
+//	    WHERE j.status = 'queued'
+//	      AND (j.next_retry_at IS NULL OR j.next_retry_at <= NOW())
+//	      AND ($1::int <= 0 OR (
+//	          SELECT COUNT(*)
+//	          FROM download_jobs p
+//	          WHERE p.archived_by = j.archived_by
+//	            AND p.status = 'processing'
+//	      ) < $1::int)
+//	    ORDER BY j.priority DESC, j.created_at
 //	    LIMIT 1
 //	    FOR UPDATE SKIP LOCKED
 //	)
 //	UPDATE download_jobs
 //	SET status = 'processing',
 //	    attempts = attempts + 1,
+//	    next_retry_at = NULL,
 //	    started_at = COALESCE(started_at, NOW()),
 //	    updated_at = NOW()
 //	WHERE id IN (SELECT id FROM cte)
-//	RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
-func (q *Queries) DequeueDownloadJob(ctx context.Context) (*DownloadJob, error) {
-	row := q.db.QueryRow(ctx, dequeueDownloadJob)
+//	RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
+func (q *Queries) DequeueDownloadJob(ctx context.Context, maxPerUser int32) (*DownloadJob, error) {
+	row := q.db.QueryRow(ctx, dequeueDownloadJob, maxPerUser)
 	var i DownloadJob
 	err := row.Scan(
 		&i.ID,
@@ -164,6 +258,10 @@ func (q *Queries) DequeueDownloadJob(ctx context.Context) (*DownloadJob, error)
 		&i.ParentJobID,
 		&i.BatchLabel,
 		&i.BatchTotal,
+		&i.ErrorCode,
+		&i.Priority,
+		&i.MetadataOnly,
+		&i.NextRetryAt,
 	)
 	return &i, err
 }
@@ -174,7 +272,8 @@ WITH cte AS (
     FROM ingest_jobs ij
     JOIN download_jobs dj ON dj.id = ij.download_job_id
     WHERE ij.status = 'queued'
-      AND ij.attempts < 5
+      AND ij.attempts < $1::int
+      AND (ij.next_retry_at IS NULL OR ij.next_retry_at <= NOW())
     ORDER BY
       -- Real ingests (fresh downloads with a spool/info.json) take priority over
       -- background asset-regeneration jobs, so a large regen batch never starves
@@ -189,6 +288,7 @@ WITH cte AS (
 UPDATE ingest_jobs AS ij
 SET status = 'processing',
     attempts = ij.attempts + 1,
+    next_retry_at = NULL,
     started_at = COALESCE(ij.started_at, NOW()),
     updated_at = NOW()
 FROM download_jobs AS dj
@@ -204,32 +304,42 @@ RETURNING
     dj.info_json_path AS info_json_path,
     dj.video_id AS video_id,
     ij.asset_scope AS asset_scope,
-    dj.extra_args AS extra_args
+    ij.thumbnail_at AS thumbnail_at,
+    ij.whisper_model AS whisper_model,
+    ij.whisper_language AS whisper_language,
+    dj.extra_args AS extra_args,
+    dj.metadata_only AS metadata_only
 `
 
 type DequeueIngestJobRow struct {
-	IngestJobID   pgtype.UUID `db:"ingest_job_id" json:"IngestJobID"`
-	DownloadJobID pgtype.UUID `db:"download_job_id" json:"DownloadJobID"`
-	URL           string      `db:"url" json:"Url"`
-	ArchivedBy    pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
-	Refresh       bool        `db:"refresh" json:"Refresh"`
-	SpoolDir      *string     `db:"spool_dir" json:"SpoolDir"`
-	InfoJsonPath  *string     `db:"info_json_path" json:"InfoJsonPath"`
-	VideoID       pgtype.UUID `db:"video_id" json:"VideoID"`
-	AssetScope    *string     `db:"asset_scope" json:"AssetScope"`
-	ExtraArgs     []string    `db:"extra_args" json:"ExtraArgs"`
+	IngestJobID     pgtype.UUID `db:"ingest_job_id" json:"IngestJobID"`
+	DownloadJobID   pgtype.UUID `db:"download_job_id" json:"DownloadJobID"`
+	URL             string      `db:"url" json:"Url"`
+	ArchivedBy      pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
+	Refresh         bool        `db:"refresh" json:"Refresh"`
+	SpoolDir        *string     `db:"spool_dir" json:"SpoolDir"`
+	InfoJsonPath    *string     `db:"info_json_path" json:"InfoJsonPath"`
+	VideoID         pgtype.UUID `db:"video_id" json:"VideoID"`
+	AssetScope      *string     `db:"asset_scope" json:"AssetScope"`
+	ThumbnailAt     *float64    `db:"thumbnail_at" json:"ThumbnailAt"`
+	WhisperModel    *string     `db:"whisper_model" json:"WhisperModel"`
+	WhisperLanguage *string     `db:"whisper_language" json:"WhisperLanguage"`
+	ExtraArgs       []string    `db:"extra_args" json:"ExtraArgs"`
+	MetadataOnly    bool        `db:"metadata_only" json:"MetadataOnly"`
 }
 
 // DequeueIngestJob claims one queued ingest job and returns needed info.
 // Returns video_id for asset regeneration jobs (NULL for normal ingest).
-// Skips jobs that have already been retried too many times.
+// Skips jobs that have already been retried max_attempts times and jobs still
+// waiting out a retry backoff.
 //
 //	WITH cte AS (
 //	    SELECT ij.id
 //	    FROM ingest_jobs ij
 //	    JOIN download_jobs dj ON dj.id = ij.download_job_id
 //	    WHERE ij.status = 'queued'
-//	      AND ij.attempts < 5
+//	      AND ij.attempts < $1::int
+//	      AND (ij.next_retry_at IS NULL OR ij.next_retry_at <= NOW())
 //	    ORDER BY
 //	      -- Real ingests (fresh downloads with a spool/info.json) take priority over
 //	      -- background asset-regeneration jobs, so a large regen batch never starves
@@ -244,6 +354,7 @@ type DequeueIngestJobRow struct {
 //	UPDATE ingest_jobs AS ij
 //	SET status = 'processing',
 //	    attempts = ij.attempts + 1,
+//	    next_retry_at = NULL,
 //	    started_at = COALESCE(ij.started_at, NOW()),
 //	    updated_at = NOW()
 //	FROM download_jobs AS dj
@@ -259,9 +370,13 @@ type DequeueIngestJobRow struct {
 //	    dj.info_json_path AS info_json_path,
 //	    dj.video_id AS video_id,
 //	    ij.asset_scope AS asset_scope,
-//	    dj.extra_args AS extra_args
-func (q *Queries) DequeueIngestJob(ctx context.Context) (*DequeueIngestJobRow, error) {
-	row := q.db.QueryRow(ctx, dequeueIngestJob)
+//	    ij.thumbnail_at AS thumbnail_at,
+//	    ij.whisper_model AS whisper_model,
+//	    ij.whisper_language AS whisper_language,
+//	    dj.extra_args AS extra_args,
+//	    dj.metadata_only AS metadata_only
+func (q *Queries) DequeueIngestJob(ctx context.Context, maxAttempts int32) (*DequeueIngestJobRow, error) {
+	row := q.db.QueryRow(ctx, dequeueIngestJob, maxAttempts)
 	var i DequeueIngestJobRow
 	err := row.Scan(
 		&i.IngestJobID,
@@ -273,7 +388,11 @@ func (q *Queries) DequeueIngestJob(ctx context.Context) (*DequeueIngestJobRow, e
 		&i.InfoJsonPath,
 		&i.VideoID,
 		&i.AssetScope,
+		&i.ThumbnailAt,
+		&i.WhisperModel,
+		&i.WhisperLanguage,
 		&i.ExtraArgs,
+		&i.MetadataOnly,
 	)
 	return &i, err
 }
@@ -295,20 +414,26 @@ WITH new_download_job AS (
         v.id
     FROM videos v
     WHERE v.id = $1
-    RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+    RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 ),
 new_ingest_job AS (
     INSERT INTO ingest_jobs (
         download_job_id,
         status,
-        asset_scope
+        asset_scope,
+        thumbnail_at,
+        whisper_model,
+        whisper_language
     )
     SELECT
         new_download_job.id,
         'queued',
-        $2::text
+        $2::text,
+        $3::double precision,
+        $4::text,
+        $5::text
     FROM new_download_job
-    RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+    RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 )
 SELECT
     new_ingest_job.id AS ingest_job_id,
@@ -318,8 +443,11 @@ FROM new_ingest_job, new_download_job
 `
 
 type EnqueueAssetRegenerationJobParams struct {
-	VideoID    pgtype.UUID `db:"video_id" json:"VideoID"`
-	AssetScope *string     `db:"asset_scope" json:"AssetScope"`
+	VideoID         pgtype.UUID `db:"video_id" json:"VideoID"`
+	AssetScope      *string     `db:"asset_scope" json:"AssetScope"`
+	ThumbnailAt     *float64    `db:"thumbnail_at" json:"ThumbnailAt"`
+	WhisperModel    *string     `db:"whisper_model" json:"WhisperModel"`
+	WhisperLanguage *string     `db:"whisper_language" json:"WhisperLanguage"`
 }
 
 type EnqueueAssetRegenerationJobRow struct {
@@ -330,6 +458,8 @@ type EnqueueAssetRegenerationJobRow struct {
 
 // EnqueueAssetRegenerationJob creates a download + ingest job pair for regenerating assets.
 // asset_scope: NULL = all assets, or one of 'thumbnail', 'preview', 'seek', 'waveform'.
+// thumbnail_at pins the thumbnail frame (seconds); negative clears the pin, NULL keeps it.
+// whisper_model and whisper_language override the Whisper settings for this run; NULL uses the defaults.
 //
 //	WITH new_download_job AS (
 //	    INSERT INTO download_jobs (
@@ -347,20 +477,26 @@ type EnqueueAssetRegenerationJobRow struct {
 //	        v.id
 //	    FROM videos v
 //	    WHERE v.id = $1
-//	    RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+//	    RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 //	),
 //	new_ingest_job AS (
 //	    INSERT INTO ingest_jobs (
 //	        download_job_id,
 //	        status,
-//	        asset_scope
+//	        asset_scope,
+//	        thumbnail_at,
+//	        whisper_model,
+//	        whisper_language
 //	    )
 //	    SELECT
 //	        new_download_job.id,
 //	        'queued',
-//	        $2::text
+//	        $2::text,
+//	        $3::double precision,
+//	        $4::text,
+//	        $5::text
 //	    FROM new_download_job
-//	    RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+//	    RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 //	)
 //	SELECT
 //	    new_ingest_job.id AS ingest_job_id,
@@ -368,33 +504,288 @@ type EnqueueAssetRegenerationJobRow struct {
 //	    new_download_job.video_id AS video_id
 //	FROM new_ingest_job, new_download_job
 func (q *Queries) EnqueueAssetRegenerationJob(ctx context.Context, arg *EnqueueAssetRegenerationJobParams) (*EnqueueAssetRegenerationJobRow, error) {
-	row := q.db.QueryRow(ctx, enqueueAssetRegenerationJob, arg.VideoID, arg.AssetScope)
+	row := q.db.QueryRow(ctx, enqueueAssetRegenerationJob,
+		arg.VideoID,
+		arg.AssetScope,
+		arg.ThumbnailAt,
+		arg.WhisperModel,
+		arg.WhisperLanguage,
+	)
 	var i EnqueueAssetRegenerationJobRow
 	err := row.Scan(&i.IngestJobID, &i.DownloadJobID, &i.VideoID)
 	return &i, err
 }
 
+const enqueueCaptionBackfillBatch = `-- name: EnqueueCaptionBackfillBatch :many
+WITH batch AS (
+    SELECT v.id, v.src, v.archived_by
+    FROM videos v
+    WHERE v.id > $1::uuid
+      AND ($2::text IS NULL OR v.channel_id = $2)
+      AND NOT EXISTS (SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id)
+      AND NOT EXISTS (
+          SELECT 1
+          FROM ingest_jobs ij
+          JOIN download_jobs dj ON dj.id = ij.download_job_id
+          WHERE dj.video_id = v.id
+            AND ij.asset_scope = 'captions'
+            AND (
+                ij.status IN ('queued', 'processing')
+                OR COALESCE(ij.finished_at, ij.updated_at) >= $3
+            )
+      )
+    ORDER BY v.id
+    LIMIT $4
+),
+new_download_jobs AS (
+    INSERT INTO download_jobs (
+        url,
+        archived_by,
+        refresh,
+        status,
+        video_id
+    )
+    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+    FROM batch
+    RETURNING id, video_id
+),
+new_ingest_jobs AS (
+    INSERT INTO ingest_jobs (
+        download_job_id,
+        status,
+        asset_scope
+    )
+    SELECT new_download_jobs.id, 'queued', 'captions'
+    FROM new_download_jobs
+    RETURNING download_job_id
+)
+SELECT new_download_jobs.video_id::uuid AS video_id
+FROM new_ingest_jobs
+JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+ORDER BY new_download_jobs.video_id
+`
+
+type EnqueueCaptionBackfillBatchParams struct {
+	AfterID     pgtype.UUID        `db:"after_id" json:"AfterID"`
+	ChannelID   *string            `db:"channel_id" json:"ChannelID"`
+	RetryBefore pgtype.Timestamptz `db:"retry_before" json:"RetryBefore"`
+	BatchSize   int32              `db:"batch_size" json:"BatchSize"`
+}
+
+// EnqueueCaptionBackfillBatch queues a "captions" asset regeneration job for
+// up to batch_size caption-less videos with id > after_id, optionally limited
+// to one channel. Videos that already have a captions job queued or running
+// are skipped, as are videos whose last captions job failed or produced no
+// transcript after retry_before. Returns the queued video IDs in order.
+//
+//	WITH batch AS (
+//	    SELECT v.id, v.src, v.archived_by
+//	    FROM videos v
+//	    WHERE v.id > $1::uuid
+//	      AND ($2::text IS NULL OR v.channel_id = $2)
+//	      AND NOT EXISTS (SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id)
+//	      AND NOT EXISTS (
+//	          SELECT 1
+//	          FROM ingest_jobs ij
+//	          JOIN download_jobs dj ON dj.id = ij.download_job_id
+//	          WHERE dj.video_id = v.id
+//	            AND ij.asset_scope = 'captions'
+//	            AND (
+//	                ij.status IN ('queued', 'processing')
+//	                OR COALESCE(ij.finished_at, ij.updated_at) >= $3
+//	            )
+//	      )
+//	    ORDER BY v.id
+//	    LIMIT $4
+//	),
+//	new_download_jobs AS (
+//	    INSERT INTO download_jobs (
+//	        url,
+//	        archived_by,
+//	        refresh,
+//	        status,
+//	        video_id
+//	    )
+//	    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+//	    FROM batch
+//	    RETURNING id, video_id
+//	),
+//	new_ingest_jobs AS (
+//	    INSERT INTO ingest_jobs (
+//	        download_job_id,
+//	        status,
+//	        asset_scope
+//	    )
+//	    SELECT new_download_jobs.id, 'queued', 'captions'
+//	    FROM new_download_jobs
+//	    RETURNING download_job_id
+//	)
+//	SELECT new_download_jobs.video_id::uuid AS video_id
+//	FROM new_ingest_jobs
+//	JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+//	ORDER BY new_download_jobs.video_id
+func (q *Queries) EnqueueCaptionBackfillBatch(ctx context.Context, arg *EnqueueCaptionBackfillBatchParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, enqueueCaptionBackfillBatch,
+		arg.AfterID,
+		arg.ChannelID,
+		arg.RetryBefore,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var video_id pgtype.UUID
+		if err := rows.Scan(&video_id); err != nil {
+			return nil, err
+		}
+		items = append(items, video_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const enqueueChannelAssetRegenerationBatch = `-- name: EnqueueChannelAssetRegenerationBatch :many
+WITH batch AS (
+    SELECT v.id, v.src, v.archived_by
+    FROM videos v
+    WHERE v.channel_id = $1
+      AND v.id > $2::uuid
+    ORDER BY v.id
+    LIMIT $3
+),
+new_download_jobs AS (
+    INSERT INTO download_jobs (
+        url,
+        archived_by,
+        refresh,
+        status,
+        video_id
+    )
+    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+    FROM batch
+    RETURNING id, video_id
+),
+new_ingest_jobs AS (
+    INSERT INTO ingest_jobs (
+        download_job_id,
+        status,
+        asset_scope
+    )
+    SELECT new_download_jobs.id, 'queued', $4::text
+    FROM new_download_jobs
+    RETURNING download_job_id
+)
+SELECT new_download_jobs.video_id::uuid AS video_id
+FROM new_ingest_jobs
+JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+ORDER BY new_download_jobs.video_id
+`
+
+type EnqueueChannelAssetRegenerationBatchParams struct {
+	ChannelID  *string     `db:"channel_id" json:"ChannelID"`
+	AfterID    pgtype.UUID `db:"after_id" json:"AfterID"`
+	BatchSize  int32       `db:"batch_size" json:"BatchSize"`
+	AssetScope *string     `db:"asset_scope" json:"AssetScope"`
+}
+
+// EnqueueChannelAssetRegenerationBatch creates download + ingest job pairs (the
+// same shape as EnqueueAssetRegenerationJob) for the next batch of a channel's
+// videos, keyset-ordered by id. Returns the video IDs queued; pass the last one
+// as after_id for the next batch (zero UUID for the first).
+//
+//	WITH batch AS (
+//	    SELECT v.id, v.src, v.archived_by
+//	    FROM videos v
+//	    WHERE v.channel_id = $1
+//	      AND v.id > $2::uuid
+//	    ORDER BY v.id
+//	    LIMIT $3
+//	),
+//	new_download_jobs AS (
+//	    INSERT INTO download_jobs (
+//	        url,
+//	        archived_by,
+//	        refresh,
+//	        status,
+//	        video_id
+//	    )
+//	    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+//	    FROM batch
+//	    RETURNING id, video_id
+//	),
+//	new_ingest_jobs AS (
+//	    INSERT INTO ingest_jobs (
+//	        download_job_id,
+//	        status,
+//	        asset_scope
+//	    )
+//	    SELECT new_download_jobs.id, 'queued', $4::text
+//	    FROM new_download_jobs
+//	    RETURNING download_job_id
+//	)
+//	SELECT new_download_jobs.video_id::uuid AS video_id
+//	FROM new_ingest_jobs
+//	JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+//	ORDER BY new_download_jobs.video_id
+func (q *Queries) EnqueueChannelAssetRegenerationBatch(ctx context.Context, arg *EnqueueChannelAssetRegenerationBatchParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, enqueueChannelAssetRegenerationBatch,
+		arg.ChannelID,
+		arg.AfterID,
+		arg.BatchSize,
+		arg.AssetScope,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var video_id pgtype.UUID
+		if err := rows.Scan(&video_id); err != nil {
+			return nil, err
+		}
+		items = append(items, video_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const enqueueChildDownloadJobs = `-- name: EnqueueChildDownloadJobs :execrows
-INSERT INTO download_jobs (url, archived_by, status, kind, parent_job_id)
-SELECT u, $1, 'queued', 'video', $2
-FROM unnest($3::text[]) AS u
+INSERT INTO download_jobs (url, archived_by, status, kind, parent_job_id, extra_args, metadata_only)
+SELECT u, $1, 'queued', 'video', $2, $3::text[], $4
+FROM unnest($5::text[]) AS u
 `
 
 type EnqueueChildDownloadJobsParams struct {
-	ArchivedBy  pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
-	ParentJobID pgtype.UUID `db:"parent_job_id" json:"ParentJobID"`
-	Urls        []string    `db:"urls" json:"Urls"`
+	ArchivedBy   pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
+	ParentJobID  pgtype.UUID `db:"parent_job_id" json:"ParentJobID"`
+	ExtraArgs    []string    `db:"extra_args" json:"ExtraArgs"`
+	MetadataOnly bool        `db:"metadata_only" json:"MetadataOnly"`
+	Urls         []string    `db:"urls" json:"Urls"`
 }
 
 // EnqueueChildDownloadJobs bulk-inserts one normal video download job per URL,
 // all linked to a parent playlist job. Each insert fires the download_jobs
 // NOTIFY trigger, so existing downloader workers pick them up unchanged.
 //
-//	INSERT INTO download_jobs (url, archived_by, status, kind, parent_job_id)
-//	SELECT u, $1, 'queued', 'video', $2
-//	FROM unnest($3::text[]) AS u
+//	INSERT INTO download_jobs (url, archived_by, status, kind, parent_job_id, extra_args, metadata_only)
+//	SELECT u, $1, 'queued', 'video', $2, $3::text[], $4
+//	FROM unnest($5::text[]) AS u
 func (q *Queries) EnqueueChildDownloadJobs(ctx context.Context, arg *EnqueueChildDownloadJobsParams) (int64, error) {
-	result, err := q.db.Exec(ctx, enqueueChildDownloadJobs, arg.ArchivedBy, arg.ParentJobID, arg.Urls)
+	result, err := q.db.Exec(ctx, enqueueChildDownloadJobs,
+		arg.ArchivedBy,
+		arg.ParentJobID,
+		arg.ExtraArgs,
+		arg.MetadataOnly,
+		arg.Urls,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -407,48 +798,55 @@ INSERT INTO download_jobs (
     archived_by,
     status,
     refresh,
-    extra_args
+    extra_args,
+    metadata_only
 )
 VALUES (
     $1,
     $2,
     'queued',
     $3,
-    $4
+    $4,
+    $5
 )
-RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 `
 
 type EnqueueDownloadJobParams struct {
-	URL        string      `db:"url" json:"Url"`
-	ArchivedBy pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
-	Refresh    bool        `db:"refresh" json:"Refresh"`
-	ExtraArgs  []string    `db:"extra_args" json:"ExtraArgs"`
+	URL          string      `db:"url" json:"Url"`
+	ArchivedBy   pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
+	Refresh      bool        `db:"refresh" json:"Refresh"`
+	ExtraArgs    []string    `db:"extra_args" json:"ExtraArgs"`
+	MetadataOnly bool        `db:"metadata_only" json:"MetadataOnly"`
 }
 
-// EnqueueDownloadJob inserts a new download job.
+// EnqueueDownloadJob inserts a new download job. metadata_only jobs fetch
+// just the info JSON and thumbnail, never the media file.
 //
 //	INSERT INTO download_jobs (
 //	    url,
 //	    archived_by,
 //	    status,
 //	    refresh,
-//	    extra_args
+//	    extra_args,
+//	    metadata_only
 //	)
 //	VALUES (
 //	    $1,
 //	    $2,
 //	    'queued',
 //	    $3,
-//	    $4
+//	    $4,
+//	    $5
 //	)
-//	RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+//	RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 func (q *Queries) EnqueueDownloadJob(ctx context.Context, arg *EnqueueDownloadJobParams) (*DownloadJob, error) {
 	row := q.db.QueryRow(ctx, enqueueDownloadJob,
 		arg.URL,
 		arg.ArchivedBy,
 		arg.Refresh,
 		arg.ExtraArgs,
+		arg.MetadataOnly,
 	)
 	var i DownloadJob
 	err := row.Scan(
@@ -473,6 +871,10 @@ func (q *Queries) EnqueueDownloadJob(ctx context.Context, arg *EnqueueDownloadJo
 		&i.ParentJobID,
 		&i.BatchLabel,
 		&i.BatchTotal,
+		&i.ErrorCode,
+		&i.Priority,
+		&i.MetadataOnly,
+		&i.NextRetryAt,
 	)
 	return &i, err
 }
@@ -486,7 +888,7 @@ VALUES (
     $1,
     'queued'
 )
-RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 `
 
 // EnqueueIngestJob inserts a new ingest job from a download job.
@@ -499,7 +901,7 @@ RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_er
 //	    $1,
 //	    'queued'
 //	)
-//	RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+//	RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 func (q *Queries) EnqueueIngestJob(ctx context.Context, downloadJobID pgtype.UUID) (*IngestJob, error) {
 	row := q.db.QueryRow(ctx, enqueueIngestJob, downloadJobID)
 	var i IngestJob
@@ -514,6 +916,10 @@ func (q *Queries) EnqueueIngestJob(ctx context.Context, downloadJobID pgtype.UUI
 		&i.StartedAt,
 		&i.FinishedAt,
 		&i.AssetScope,
+		&i.NextRetryAt,
+		&i.ThumbnailAt,
+		&i.WhisperModel,
+		&i.WhisperLanguage,
 	)
 	return &i, err
 }
@@ -523,40 +929,56 @@ INSERT INTO download_jobs (
     url,
     archived_by,
     status,
-    kind
+    kind,
+    extra_args,
+    metadata_only
 )
 VALUES (
     $1,
     $2,
     'queued',
-    'playlist'
+    'playlist',
+    $3::text[],
+    $4
 )
-RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 `
 
 type EnqueuePlaylistJobParams struct {
-	URL        string      `db:"url" json:"Url"`
-	ArchivedBy pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
+	URL          string      `db:"url" json:"Url"`
+	ArchivedBy   pgtype.UUID `db:"archived_by" json:"ArchivedBy"`
+	ExtraArgs    []string    `db:"extra_args" json:"ExtraArgs"`
+	MetadataOnly bool        `db:"metadata_only" json:"MetadataOnly"`
 }
 
 // EnqueuePlaylistJob inserts a parent "playlist" job. The downloader expands it
 // into child video jobs (see EnqueueChildDownloadJobs) rather than downloading.
+// extra_args are used for enumeration and copied to every child job, as is metadata_only.
 //
 //	INSERT INTO download_jobs (
 //	    url,
 //	    archived_by,
 //	    status,
-//	    kind
+//	    kind,
+//	    extra_args,
+//	    metadata_only
 //	)
 //	VALUES (
 //	    $1,
 //	    $2,
 //	    'queued',
-//	    'playlist'
+//	    'playlist',
+//	    $3::text[],
+//	    $4
 //	)
-//	RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+//	RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 func (q *Queries) EnqueuePlaylistJob(ctx context.Context, arg *EnqueuePlaylistJobParams) (*DownloadJob, error) {
-	row := q.db.QueryRow(ctx, enqueuePlaylistJob, arg.URL, arg.ArchivedBy)
+	row := q.db.QueryRow(ctx, enqueuePlaylistJob,
+		arg.URL,
+		arg.ArchivedBy,
+		arg.ExtraArgs,
+		arg.MetadataOnly,
+	)
 	var i DownloadJob
 	err := row.Scan(
 		&i.ID,
@@ -580,10 +1002,163 @@ func (q *Queries) EnqueuePlaylistJob(ctx context.Context, arg *EnqueuePlaylistJo
 		&i.ParentJobID,
 		&i.BatchLabel,
 		&i.BatchTotal,
+		&i.ErrorCode,
+		&i.Priority,
+		&i.MetadataOnly,
+		&i.NextRetryAt,
 	)
 	return &i, err
 }
 
+const enqueuePreviewBackfillBatch = `-- name: EnqueuePreviewBackfillBatch :many
+WITH batch AS (
+    SELECT
+        v.id,
+        v.src,
+        v.archived_by,
+        ROW_NUMBER() OVER (ORDER BY v.id) AS slot
+    FROM videos v
+    WHERE v.id > $1::uuid
+      AND v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+      AND v.assets_status @> '{"preview": false}'::jsonb
+      AND NOT EXISTS (
+          SELECT 1
+          FROM ingest_jobs ij
+          JOIN download_jobs dj ON dj.id = ij.download_job_id
+          WHERE dj.video_id = v.id
+            AND ij.status IN ('queued', 'processing')
+            AND (ij.asset_scope IS NULL OR ij.asset_scope = 'preview')
+      )
+    ORDER BY v.id
+    LIMIT $2
+),
+new_download_jobs AS (
+    INSERT INTO download_jobs (
+        url,
+        archived_by,
+        refresh,
+        status,
+        video_id
+    )
+    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+    FROM batch
+    RETURNING id, video_id
+),
+new_ingest_jobs AS (
+    INSERT INTO ingest_jobs (
+        download_job_id,
+        status,
+        asset_scope,
+        next_retry_at
+    )
+    SELECT
+        new_download_jobs.id,
+        'queued',
+        'preview',
+        NOW() + ($3::int + batch.slot - 1) * $4::float8 * INTERVAL '1 second'
+    FROM new_download_jobs
+    JOIN batch ON batch.id = new_download_jobs.video_id
+    RETURNING download_job_id
+)
+SELECT new_download_jobs.video_id::uuid AS video_id
+FROM new_ingest_jobs
+JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+ORDER BY new_download_jobs.video_id
+`
+
+type EnqueuePreviewBackfillBatchParams struct {
+	AfterID         pgtype.UUID `db:"after_id" json:"AfterID"`
+	BatchSize       int32       `db:"batch_size" json:"BatchSize"`
+	FirstSlot       int32       `db:"first_slot" json:"FirstSlot"`
+	IntervalSeconds float64     `db:"interval_seconds" json:"IntervalSeconds"`
+}
+
+// EnqueuePreviewBackfillBatch creates preview-scoped regeneration job pairs
+// for the next batch of videos missing a preview (assets_status.preview =
+// false), keyset-ordered by id. Videos that already have a pending preview or
+// full regeneration job are skipped. Jobs are spread out by setting
+// next_retry_at, which ingest workers wait for: the nth job of the batch
+// becomes claimable (first_slot + n - 1) * interval_seconds from now. Returns
+// the video IDs queued; pass the last one as after_id for the next batch.
+//
+//	WITH batch AS (
+//	    SELECT
+//	        v.id,
+//	        v.src,
+//	        v.archived_by,
+//	        ROW_NUMBER() OVER (ORDER BY v.id) AS slot
+//	    FROM videos v
+//	    WHERE v.id > $1::uuid
+//	      AND v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+//	      AND v.assets_status @> '{"preview": false}'::jsonb
+//	      AND NOT EXISTS (
+//	          SELECT 1
+//	          FROM ingest_jobs ij
+//	          JOIN download_jobs dj ON dj.id = ij.download_job_id
+//	          WHERE dj.video_id = v.id
+//	            AND ij.status IN ('queued', 'processing')
+//	            AND (ij.asset_scope IS NULL OR ij.asset_scope = 'preview')
+//	      )
+//	    ORDER BY v.id
+//	    LIMIT $2
+//	),
+//	new_download_jobs AS (
+//	    INSERT INTO download_jobs (
+//	        url,
+//	        archived_by,
+//	        refresh,
+//	        status,
+//	        video_id
+//	    )
+//	    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+//	    FROM batch
+//	    RETURNING id, video_id
+//	),
+//	new_ingest_jobs AS (
+//	    INSERT INTO ingest_jobs (
+//	        download_job_id,
+//	        status,
+//	        asset_scope,
+//	        next_retry_at
+//	    )
+//	    SELECT
+//	        new_download_jobs.id,
+//	        'queued',
+//	        'preview',
+//	        NOW() + ($3::int + batch.slot - 1) * $4::float8 * INTERVAL '1 second'
+//	    FROM new_download_jobs
+//	    JOIN batch ON batch.id = new_download_jobs.video_id
+//	    RETURNING download_job_id
+//	)
+//	SELECT new_download_jobs.video_id::uuid AS video_id
+//	FROM new_ingest_jobs
+//	JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+//	ORDER BY new_download_jobs.video_id
+func (q *Queries) EnqueuePreviewBackfillBatch(ctx context.Context, arg *EnqueuePreviewBackfillBatchParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, enqueuePreviewBackfillBatch,
+		arg.AfterID,
+		arg.BatchSize,
+		arg.FirstSlot,
+		arg.IntervalSeconds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var video_id pgtype.UUID
+		if err := rows.Scan(&video_id); err != nil {
+			return nil, err
+		}
+		items = append(items, video_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const enqueueUploadIngestJob = `-- name: EnqueueUploadIngestJob :one
 WITH new_download_job AS (
     INSERT INTO download_jobs (
@@ -604,7 +1179,7 @@ WITH new_download_job AS (
         $4,
         NOW()
     )
-    RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+    RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 ),
 new_ingest_job AS (
     INSERT INTO ingest_jobs (
@@ -613,7 +1188,7 @@ new_ingest_job AS (
     )
     SELECT new_download_job.id, 'queued'
     FROM new_download_job
-    RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+    RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 )
 SELECT
     new_ingest_job.id AS ingest_job_id,
@@ -655,7 +1230,7 @@ type EnqueueUploadIngestJobRow struct {
 //	        $4,
 //	        NOW()
 //	    )
-//	    RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+//	    RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 //	),
 //	new_ingest_job AS (
 //	    INSERT INTO ingest_jobs (
@@ -664,7 +1239,7 @@ type EnqueueUploadIngestJobRow struct {
 //	    )
 //	    SELECT new_download_job.id, 'queued'
 //	    FROM new_download_job
-//	    RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+//	    RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 //	)
 //	SELECT
 //	    new_ingest_job.id AS ingest_job_id,
@@ -682,28 +1257,65 @@ func (q *Queries) EnqueueUploadIngestJob(ctx context.Context, arg *EnqueueUpload
 	return &i, err
 }
 
+const failExcessiveRetryDownloadJobs = `-- name: FailExcessiveRetryDownloadJobs :execrows
+UPDATE download_jobs
+SET status = 'failed',
+    last_error = 'exceeded maximum retry attempts',
+    next_retry_at = NULL,
+    finished_at = NOW(),
+    process_pid = NULL,
+    updated_at = NOW()
+WHERE status = 'processing'
+  AND updated_at < NOW() - INTERVAL '5 minutes'
+  AND attempts >= $1::int
+`
+
+// FailExcessiveRetryDownloadJobs permanently fails orphaned "processing"
+// download jobs that have used up their max_attempts.
+//
+//	UPDATE download_jobs
+//	SET status = 'failed',
+//	    last_error = 'exceeded maximum retry attempts',
+//	    next_retry_at = NULL,
+//	    finished_at = NOW(),
+//	    process_pid = NULL,
+//	    updated_at = NOW()
+//	WHERE status = 'processing'
+//	  AND updated_at < NOW() - INTERVAL '5 minutes'
+//	  AND attempts >= $1::int
+func (q *Queries) FailExcessiveRetryDownloadJobs(ctx context.Context, maxAttempts int32) (int64, error) {
+	result, err := q.db.Exec(ctx, failExcessiveRetryDownloadJobs, maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const failExcessiveRetryIngestJobs = `-- name: FailExcessiveRetryIngestJobs :execrows
 UPDATE ingest_jobs
 SET status = 'failed',
     last_error = 'exceeded maximum retry attempts',
+    next_retry_at = NULL,
     finished_at = NOW(),
     updated_at = NOW()
-WHERE status = 'queued'
-  AND attempts >= 5
+WHERE (status = 'queued' OR (status = 'processing' AND updated_at < NOW() - INTERVAL '5 minutes'))
+  AND attempts >= $1::int
 `
 
-// FailExcessiveRetryIngestJobs permanently fails jobs that have been retried too many times.
+// FailExcessiveRetryIngestJobs permanently fails jobs that have used up their
+// max_attempts, whether queued or orphaned in "processing".
 // This prevents zombie jobs from wasting workers indefinitely.
 //
 //	UPDATE ingest_jobs
 //	SET status = 'failed',
 //	    last_error = 'exceeded maximum retry attempts',
+//	    next_retry_at = NULL,
 //	    finished_at = NOW(),
 //	    updated_at = NOW()
-//	WHERE status = 'queued'
-//	  AND attempts >= 5
-func (q *Queries) FailExcessiveRetryIngestJobs(ctx context.Context) (int64, error) {
-	result, err := q.db.Exec(ctx, failExcessiveRetryIngestJobs)
+//	WHERE (status = 'queued' OR (status = 'processing' AND updated_at < NOW() - INTERVAL '5 minutes'))
+//	  AND attempts >= $1::int
+func (q *Queries) FailExcessiveRetryIngestJobs(ctx context.Context, maxAttempts int32) (int64, error) {
+	result, err := q.db.Exec(ctx, failExcessiveRetryIngestJobs, maxAttempts)
 	if err != nil {
 		return 0, err
 	}
@@ -757,6 +1369,116 @@ func (q *Queries) GetActiveAssetJobsForVideo(ctx context.Context, videoID pgtype
 	return items, nil
 }
 
+const getCaptionBackfillProgress = `-- name: GetCaptionBackfillProgress :one
+SELECT
+    COUNT(*) AS total_count,
+    COUNT(*) FILTER (WHERE ij.status = 'queued') AS queued_count,
+    COUNT(*) FILTER (WHERE ij.status = 'processing') AS processing_count,
+    COUNT(*) FILTER (WHERE ij.status = 'succeeded') AS succeeded_count,
+    COUNT(*) FILTER (WHERE ij.status = 'failed') AS failed_count
+FROM ingest_jobs ij
+JOIN download_jobs dj ON dj.id = ij.download_job_id
+WHERE ij.asset_scope = 'captions'
+  AND dj.refresh = true
+  AND dj.spool_dir IS NULL
+  AND ij.created_at >= $1
+`
+
+type GetCaptionBackfillProgressRow struct {
+	TotalCount      int64 `db:"total_count" json:"TotalCount"`
+	QueuedCount     int64 `db:"queued_count" json:"QueuedCount"`
+	ProcessingCount int64 `db:"processing_count" json:"ProcessingCount"`
+	SucceededCount  int64 `db:"succeeded_count" json:"SucceededCount"`
+	FailedCount     int64 `db:"failed_count" json:"FailedCount"`
+}
+
+// GetCaptionBackfillProgress counts "captions" regeneration jobs created at or
+// after since, grouped by status.
+//
+//	SELECT
+//	    COUNT(*) AS total_count,
+//	    COUNT(*) FILTER (WHERE ij.status = 'queued') AS queued_count,
+//	    COUNT(*) FILTER (WHERE ij.status = 'processing') AS processing_count,
+//	    COUNT(*) FILTER (WHERE ij.status = 'succeeded') AS succeeded_count,
+//	    COUNT(*) FILTER (WHERE ij.status = 'failed') AS failed_count
+//	FROM ingest_jobs ij
+//	JOIN download_jobs dj ON dj.id = ij.download_job_id
+//	WHERE ij.asset_scope = 'captions'
+//	  AND dj.refresh = true
+//	  AND dj.spool_dir IS NULL
+//	  AND ij.created_at >= $1
+func (q *Queries) GetCaptionBackfillProgress(ctx context.Context, since pgtype.Timestamptz) (*GetCaptionBackfillProgressRow, error) {
+	row := q.db.QueryRow(ctx, getCaptionBackfillProgress, since)
+	var i GetCaptionBackfillProgressRow
+	err := row.Scan(
+		&i.TotalCount,
+		&i.QueuedCount,
+		&i.ProcessingCount,
+		&i.SucceededCount,
+		&i.FailedCount,
+	)
+	return &i, err
+}
+
+const getChannelAssetRegenerationProgress = `-- name: GetChannelAssetRegenerationProgress :one
+SELECT
+    COUNT(*) AS total_count,
+    COUNT(*) FILTER (WHERE ij.status = 'queued') AS queued_count,
+    COUNT(*) FILTER (WHERE ij.status = 'processing') AS processing_count,
+    COUNT(*) FILTER (WHERE ij.status = 'succeeded') AS succeeded_count,
+    COUNT(*) FILTER (WHERE ij.status = 'failed') AS failed_count
+FROM ingest_jobs ij
+JOIN download_jobs dj ON dj.id = ij.download_job_id
+JOIN videos v ON v.id = dj.video_id
+WHERE v.channel_id = $1
+  AND dj.refresh = true
+  AND dj.spool_dir IS NULL
+  AND ij.created_at >= $2
+`
+
+type GetChannelAssetRegenerationProgressParams struct {
+	ChannelID *string            `db:"channel_id" json:"ChannelID"`
+	Since     pgtype.Timestamptz `db:"since" json:"Since"`
+}
+
+type GetChannelAssetRegenerationProgressRow struct {
+	TotalCount      int64 `db:"total_count" json:"TotalCount"`
+	QueuedCount     int64 `db:"queued_count" json:"QueuedCount"`
+	ProcessingCount int64 `db:"processing_count" json:"ProcessingCount"`
+	SucceededCount  int64 `db:"succeeded_count" json:"SucceededCount"`
+	FailedCount     int64 `db:"failed_count" json:"FailedCount"`
+}
+
+// GetChannelAssetRegenerationProgress counts asset regeneration ingest jobs for
+// a channel's videos created at or after since, grouped by status.
+// Regeneration jobs are refresh download jobs that never had a spool dir.
+//
+//	SELECT
+//	    COUNT(*) AS total_count,
+//	    COUNT(*) FILTER (WHERE ij.status = 'queued') AS queued_count,
+//	    COUNT(*) FILTER (WHERE ij.status = 'processing') AS processing_count,
+//	    COUNT(*) FILTER (WHERE ij.status = 'succeeded') AS succeeded_count,
+//	    COUNT(*) FILTER (WHERE ij.status = 'failed') AS failed_count
+//	FROM ingest_jobs ij
+//	JOIN download_jobs dj ON dj.id = ij.download_job_id
+//	JOIN videos v ON v.id = dj.video_id
+//	WHERE v.channel_id = $1
+//	  AND dj.refresh = true
+//	  AND dj.spool_dir IS NULL
+//	  AND ij.created_at >= $2
+func (q *Queries) GetChannelAssetRegenerationProgress(ctx context.Context, arg *GetChannelAssetRegenerationProgressParams) (*GetChannelAssetRegenerationProgressRow, error) {
+	row := q.db.QueryRow(ctx, getChannelAssetRegenerationProgress, arg.ChannelID, arg.Since)
+	var i GetChannelAssetRegenerationProgressRow
+	err := row.Scan(
+		&i.TotalCount,
+		&i.QueuedCount,
+		&i.ProcessingCount,
+		&i.SucceededCount,
+		&i.FailedCount,
+	)
+	return &i, err
+}
+
 const getDownloadJobPID = `-- name: GetDownloadJobPID :one
 SELECT process_pid
 FROM download_jobs
@@ -775,6 +1497,35 @@ func (q *Queries) GetDownloadJobPID(ctx context.Context, id pgtype.UUID) (*int64
 	return process_pid, err
 }
 
+const getDownloadJobQueuePosition = `-- name: GetDownloadJobQueuePosition :one
+SELECT (COUNT(q.id) + 1)::bigint AS position
+FROM download_jobs t
+LEFT JOIN download_jobs q
+    ON q.status = 'queued'
+   AND q.id <> t.id
+   AND (q.priority > t.priority OR (q.priority = t.priority AND q.created_at < t.created_at))
+WHERE t.id = $1
+GROUP BY t.id
+`
+
+// GetDownloadJobQueuePosition returns the 1-based position of a queued job in
+// dequeue order (priority DESC, created_at).
+//
+//	SELECT (COUNT(q.id) + 1)::bigint AS position
+//	FROM download_jobs t
+//	LEFT JOIN download_jobs q
+//	    ON q.status = 'queued'
+//	   AND q.id <> t.id
+//	   AND (q.priority > t.priority OR (q.priority = t.priority AND q.created_at < t.created_at))
+//	WHERE t.id = $1
+//	GROUP BY t.id
+func (q *Queries) GetDownloadJobQueuePosition(ctx context.Context, id pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getDownloadJobQueuePosition, id)
+	var position int64
+	err := row.Scan(&position)
+	return position, err
+}
+
 const heartbeatIngestJob = `-- name: HeartbeatIngestJob :exec
 UPDATE ingest_jobs
 SET updated_at = NOW()
@@ -817,30 +1568,87 @@ func (q *Queries) LinkDownloadJobVideo(ctx context.Context, arg *LinkDownloadJob
 	return err
 }
 
+const listProcessingDownloadJobsForUser = `-- name: ListProcessingDownloadJobsForUser :many
+SELECT id, process_pid
+FROM download_jobs
+WHERE archived_by = $1
+  AND status = 'processing'
+`
+
+type ListProcessingDownloadJobsForUserRow struct {
+	ID         pgtype.UUID `db:"id" json:"ID"`
+	ProcessPid *int64      `db:"process_pid" json:"ProcessPid"`
+}
+
+// ListProcessingDownloadJobsForUser returns a user's running download jobs
+// with their process IDs, so they can be signalled before being cancelled.
+//
+//	SELECT id, process_pid
+//	FROM download_jobs
+//	WHERE archived_by = $1
+//	  AND status = 'processing'
+func (q *Queries) ListProcessingDownloadJobsForUser(ctx context.Context, archivedBy pgtype.UUID) ([]*ListProcessingDownloadJobsForUserRow, error) {
+	rows, err := q.db.Query(ctx, listProcessingDownloadJobsForUser, archivedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListProcessingDownloadJobsForUserRow
+	for rows.Next() {
+		var i ListProcessingDownloadJobsForUserRow
+		if err := rows.Scan(&i.ID, &i.ProcessPid); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockDownloadDequeue = `-- name: LockDownloadDequeue :exec
+SELECT pg_advisory_xact_lock(hashtext('download-dequeue'))
+`
+
+// LockDownloadDequeue serializes the DequeueDownloadJob calls that enforce
+// max_per_user until the calling transaction ends. Without it two workers can
+// both count a user below the cap and each claim one of their jobs.
+//
+//	SELECT pg_advisory_xact_lock(hashtext('download-dequeue'))
+func (q *Queries) LockDownloadDequeue(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockDownloadDequeue)
+	return err
+}
+
 const markDownloadJobFailed = `-- name: MarkDownloadJobFailed :exec
 UPDATE download_jobs
 SET status = 'failed',
     finished_at = NOW(),
     updated_at = NOW(),
-    last_error = $1
-WHERE id = $2
+    last_error = $1,
+    error_code = $2::text
+WHERE id = $3
 `
 
 type MarkDownloadJobFailedParams struct {
 	LastError *string     `db:"last_error" json:"LastError"`
+	ErrorCode *string     `db:"error_code" json:"ErrorCode"`
 	ID        pgtype.UUID `db:"id" json:"ID"`
 }
 
 // MarkDownloadJobFailed stores error and marks job failed.
+// error_code is a classified failure reason (see ytdlp.ClassifyError), or NULL.
 //
 //	UPDATE download_jobs
 //	SET status = 'failed',
 //	    finished_at = NOW(),
 //	    updated_at = NOW(),
-//	    last_error = $1
-//	WHERE id = $2
+//	    last_error = $1,
+//	    error_code = $2::text
+//	WHERE id = $3
 func (q *Queries) MarkDownloadJobFailed(ctx context.Context, arg *MarkDownloadJobFailedParams) error {
-	_, err := q.db.Exec(ctx, markDownloadJobFailed, arg.LastError, arg.ID)
+	_, err := q.db.Exec(ctx, markDownloadJobFailed, arg.LastError, arg.ErrorCode, arg.ID)
 	return err
 }
 
@@ -851,7 +1659,8 @@ SET status = 'succeeded',
     updated_at = NOW(),
     spool_dir = $1,
     info_json_path = $2,
-    last_error = NULL
+    last_error = NULL,
+    error_code = NULL
 WHERE id = $3
 `
 
@@ -869,7 +1678,8 @@ type MarkDownloadJobSucceededParams struct {
 //	    updated_at = NOW(),
 //	    spool_dir = $1,
 //	    info_json_path = $2,
-//	    last_error = NULL
+//	    last_error = NULL,
+//	    error_code = NULL
 //	WHERE id = $3
 func (q *Queries) MarkDownloadJobSucceeded(ctx context.Context, arg *MarkDownloadJobSucceededParams) error {
 	_, err := q.db.Exec(ctx, markDownloadJobSucceeded, arg.SpoolDir, arg.InfoJsonPath, arg.ID)
@@ -925,52 +1735,190 @@ func (q *Queries) MarkIngestJobSucceeded(ctx context.Context, id pgtype.UUID) er
 	return err
 }
 
+const prioritizeDownloadJob = `-- name: PrioritizeDownloadJob :one
+UPDATE download_jobs
+SET priority = (
+        SELECT COALESCE(MAX(q.priority), 0) + 1
+        FROM download_jobs q
+        WHERE q.status = 'queued' AND q.id <> $1
+    ),
+    updated_at = NOW()
+WHERE id = $1
+  AND status = 'queued'
+RETURNING priority
+`
+
+// PrioritizeDownloadJob moves a queued job to the front of the queue by
+// raising its priority above every other queued job, whoever queued it, so
+// callers must restrict it to admins. Returns no rows when the job is not
+// queued.
+//
+//	UPDATE download_jobs
+//	SET priority = (
+//	        SELECT COALESCE(MAX(q.priority), 0) + 1
+//	        FROM download_jobs q
+//	        WHERE q.status = 'queued' AND q.id <> $1
+//	    ),
+//	    updated_at = NOW()
+//	WHERE id = $1
+//	  AND status = 'queued'
+//	RETURNING priority
+func (q *Queries) PrioritizeDownloadJob(ctx context.Context, id pgtype.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, prioritizeDownloadJob, id)
+	var priority int32
+	err := row.Scan(&priority)
+	return priority, err
+}
+
 const recoverStuckDownloadJobs = `-- name: RecoverStuckDownloadJobs :exec
 UPDATE download_jobs
 SET status = 'queued',
+    next_retry_at = NOW() + LEAST(
+        $1::int,
+        CASE WHEN $2::bool
+             THEN $3::int * power(2, LEAST(GREATEST(attempts - 1, 0), 30))
+             ELSE $3::int * GREATEST(attempts, 1)
+        END
+    ) * INTERVAL '1 second',
+    process_pid = NULL,
     updated_at = NOW()
 WHERE status = 'processing'
   AND updated_at < NOW() - INTERVAL '5 minutes'
+  AND attempts < $4::int
 `
 
+type RecoverStuckDownloadJobsParams struct {
+	CapSeconds         int32 `db:"cap_seconds" json:"CapSeconds"`
+	BackoffExponential bool  `db:"backoff_exponential" json:"BackoffExponential"`
+	BaseSeconds        int32 `db:"base_seconds" json:"BaseSeconds"`
+	MaxAttempts        int32 `db:"max_attempts" json:"MaxAttempts"`
+}
+
 // RecoverStuckDownloadJobs resets orphaned "processing" jobs back to "queued" on service startup.
 // Jobs stuck in "processing" for more than the timeout are assumed to have been orphaned by a crash or restart.
+// Backoff and max_attempts work as in RecoverStuckIngestJobs.
 //
 //	UPDATE download_jobs
 //	SET status = 'queued',
+//	    next_retry_at = NOW() + LEAST(
+//	        $1::int,
+//	        CASE WHEN $2::bool
+//	             THEN $3::int * power(2, LEAST(GREATEST(attempts - 1, 0), 30))
+//	             ELSE $3::int * GREATEST(attempts, 1)
+//	        END
+//	    ) * INTERVAL '1 second',
+//	    process_pid = NULL,
 //	    updated_at = NOW()
 //	WHERE status = 'processing'
 //	  AND updated_at < NOW() - INTERVAL '5 minutes'
-func (q *Queries) RecoverStuckDownloadJobs(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, recoverStuckDownloadJobs)
+//	  AND attempts < $4::int
+func (q *Queries) RecoverStuckDownloadJobs(ctx context.Context, arg *RecoverStuckDownloadJobsParams) error {
+	_, err := q.db.Exec(ctx, recoverStuckDownloadJobs,
+		arg.CapSeconds,
+		arg.BackoffExponential,
+		arg.BaseSeconds,
+		arg.MaxAttempts,
+	)
 	return err
 }
 
 const recoverStuckIngestJobs = `-- name: RecoverStuckIngestJobs :exec
 UPDATE ingest_jobs
 SET status = 'queued',
+    next_retry_at = NOW() + LEAST(
+        $1::int,
+        CASE WHEN $2::bool
+             THEN $3::int * power(2, LEAST(GREATEST(attempts - 1, 0), 30))
+             ELSE $3::int * GREATEST(attempts, 1)
+        END
+    ) * INTERVAL '1 second',
     updated_at = NOW()
 WHERE status = 'processing'
-  AND updated_at < NOW() - INTERVAL '5 minutes'
+  AND updated_at < NOW() - CASE
+      WHEN whisper_model LIKE 'medium%' OR whisper_model LIKE 'large%' THEN INTERVAL '20 minutes'
+      ELSE INTERVAL '5 minutes'
+  END
+  AND attempts < $4::int
 `
 
+type RecoverStuckIngestJobsParams struct {
+	CapSeconds         int32 `db:"cap_seconds" json:"CapSeconds"`
+	BackoffExponential bool  `db:"backoff_exponential" json:"BackoffExponential"`
+	BaseSeconds        int32 `db:"base_seconds" json:"BaseSeconds"`
+	MaxAttempts        int32 `db:"max_attempts" json:"MaxAttempts"`
+}
+
 // RecoverStuckIngestJobs resets orphaned "processing" jobs back to "queued" on service startup.
 // Jobs stuck in "processing" for more than the timeout are assumed to have been orphaned by a crash.
+// Only jobs with attempts left under max_attempts are re-queued; each waits out
+// its backoff (see db.RetryPolicy.Delay) before it can be dequeued again.
+// Jobs running a medium or large Whisper model get a longer timeout, since a
+// busy host can delay their heartbeat.
 //
 //	UPDATE ingest_jobs
 //	SET status = 'queued',
+//	    next_retry_at = NOW() + LEAST(
+//	        $1::int,
+//	        CASE WHEN $2::bool
+//	             THEN $3::int * power(2, LEAST(GREATEST(attempts - 1, 0), 30))
+//	             ELSE $3::int * GREATEST(attempts, 1)
+//	        END
+//	    ) * INTERVAL '1 second',
 //	    updated_at = NOW()
 //	WHERE status = 'processing'
-//	  AND updated_at < NOW() - INTERVAL '5 minutes'
-func (q *Queries) RecoverStuckIngestJobs(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, recoverStuckIngestJobs)
+//	  AND updated_at < NOW() - CASE
+//	      WHEN whisper_model LIKE 'medium%' OR whisper_model LIKE 'large%' THEN INTERVAL '20 minutes'
+//	      ELSE INTERVAL '5 minutes'
+//	  END
+//	  AND attempts < $4::int
+func (q *Queries) RecoverStuckIngestJobs(ctx context.Context, arg *RecoverStuckIngestJobsParams) error {
+	_, err := q.db.Exec(ctx, recoverStuckIngestJobs,
+		arg.CapSeconds,
+		arg.BackoffExponential,
+		arg.BaseSeconds,
+		arg.MaxAttempts,
+	)
 	return err
 }
 
+const resetPreviewBackfillSchedule = `-- name: ResetPreviewBackfillSchedule :execrows
+DELETE FROM download_jobs dj
+USING ingest_jobs ij
+WHERE ij.download_job_id = dj.id
+  AND ij.status = 'queued'
+  AND ij.attempts = 0
+  AND ij.asset_scope = 'preview'
+  AND ij.next_retry_at > NOW()
+`
+
+// ResetPreviewBackfillSchedule deletes the preview backfill jobs still waiting
+// for their scheduled slot (queued, never attempted, next_retry_at in the
+// future) together with their placeholder download jobs, so a new backfill run
+// replaces the previous schedule instead of adding its rate on top of it.
+// Returns the number of jobs removed.
+//
+//	DELETE FROM download_jobs dj
+//	USING ingest_jobs ij
+//	WHERE ij.download_job_id = dj.id
+//	  AND ij.status = 'queued'
+//	  AND ij.attempts = 0
+//	  AND ij.asset_scope = 'preview'
+//	  AND ij.next_retry_at > NOW()
+func (q *Queries) ResetPreviewBackfillSchedule(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, resetPreviewBackfillSchedule)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const retryDownloadJob = `-- name: RetryDownloadJob :exec
 UPDATE download_jobs
 SET status = 'queued',
+    attempts = 0,
+    next_retry_at = NULL,
     last_error = NULL,
+    error_code = NULL,
     started_at = NULL,
     finished_at = NULL,
     process_pid = NULL,
@@ -978,11 +1926,15 @@ SET status = 'queued',
 WHERE id = $1
 `
 
-// RetryDownloadJob resets a job to queued status for retry.
+// RetryDownloadJob resets a job to queued status for a manual retry. The
+// attempt count starts over and the job can be dequeued immediately.
 //
 //	UPDATE download_jobs
 //	SET status = 'queued',
+//	    attempts = 0,
+//	    next_retry_at = NULL,
 //	    last_error = NULL,
+//	    error_code = NULL,
 //	    started_at = NULL,
 //	    finished_at = NULL,
 //	    process_pid = NULL,
@@ -993,6 +1945,45 @@ func (q *Queries) RetryDownloadJob(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const scheduleDownloadJobRetry = `-- name: ScheduleDownloadJobRetry :execrows
+UPDATE download_jobs
+SET status = 'queued',
+    next_retry_at = NOW() + $1::int * INTERVAL '1 second',
+    last_error = $2,
+    error_code = NULL,
+    process_pid = NULL,
+    updated_at = NOW()
+WHERE id = $3
+  AND status = 'processing'
+`
+
+type ScheduleDownloadJobRetryParams struct {
+	DelaySeconds int32       `db:"delay_seconds" json:"DelaySeconds"`
+	LastError    *string     `db:"last_error" json:"LastError"`
+	ID           pgtype.UUID `db:"id" json:"ID"`
+}
+
+// ScheduleDownloadJobRetry re-queues a failed download after delay_seconds,
+// keeping the error so the job list shows why it is waiting. Only a job still
+// "processing" is re-queued, so a job cancelled mid-download stays cancelled.
+//
+//	UPDATE download_jobs
+//	SET status = 'queued',
+//	    next_retry_at = NOW() + $1::int * INTERVAL '1 second',
+//	    last_error = $2,
+//	    error_code = NULL,
+//	    process_pid = NULL,
+//	    updated_at = NOW()
+//	WHERE id = $3
+//	  AND status = 'processing'
+func (q *Queries) ScheduleDownloadJobRetry(ctx context.Context, arg *ScheduleDownloadJobRetryParams) (int64, error) {
+	result, err := q.db.Exec(ctx, scheduleDownloadJobRetry, arg.DelaySeconds, arg.LastError, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const unarchiveJob = `-- name: UnarchiveJob :exec
 UPDATE download_jobs
 SET archived = FALSE,
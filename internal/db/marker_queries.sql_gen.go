@@ -20,7 +20,8 @@ INSERT INTO markers (
     color,
     marker_type,
     duration,
-    created_by
+    created_by,
+    source
 ) VALUES (
     $1,
     $2,
@@ -29,19 +30,21 @@ INSERT INTO markers (
     $5,
     $6,
     $7,
-    $8
-) RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by
+    $8,
+    $9
+) RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source
 `
 
 type CreateMarkerParams struct {
-	VideoID     pgtype.UUID `db:"video_id" json:"VideoID"`
-	Timestamp   float64     `db:"timestamp" json:"Timestamp"`
-	Title       string      `db:"title" json:"Title"`
-	Description string      `db:"description" json:"Description"`
-	Color       string      `db:"color" json:"Color"`
-	MarkerType  MarkerType  `db:"marker_type" json:"MarkerType"`
-	Duration    *float64    `db:"duration" json:"Duration"`
-	CreatedBy   pgtype.UUID `db:"created_by" json:"CreatedBy"`
+	VideoID     pgtype.UUID  `db:"video_id" json:"VideoID"`
+	Timestamp   float64      `db:"timestamp" json:"Timestamp"`
+	Title       string       `db:"title" json:"Title"`
+	Description string       `db:"description" json:"Description"`
+	Color       string       `db:"color" json:"Color"`
+	MarkerType  MarkerType   `db:"marker_type" json:"MarkerType"`
+	Duration    *float64     `db:"duration" json:"Duration"`
+	CreatedBy   pgtype.UUID  `db:"created_by" json:"CreatedBy"`
+	Source      MarkerSource `db:"source" json:"Source"`
 }
 
 // CreateMarker
@@ -54,7 +57,8 @@ type CreateMarkerParams struct {
 //	    color,
 //	    marker_type,
 //	    duration,
-//	    created_by
+//	    created_by,
+//	    source
 //	) VALUES (
 //	    $1,
 //	    $2,
@@ -63,8 +67,9 @@ type CreateMarkerParams struct {
 //	    $5,
 //	    $6,
 //	    $7,
-//	    $8
-//	) RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by
+//	    $8,
+//	    $9
+//	) RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source
 func (q *Queries) CreateMarker(ctx context.Context, arg *CreateMarkerParams) (*Marker, error) {
 	row := q.db.QueryRow(ctx, createMarker,
 		arg.VideoID,
@@ -75,6 +80,7 @@ func (q *Queries) CreateMarker(ctx context.Context, arg *CreateMarkerParams) (*M
 		arg.MarkerType,
 		arg.Duration,
 		arg.CreatedBy,
+		arg.Source,
 	)
 	var i Marker
 	err := row.Scan(
@@ -88,6 +94,7 @@ func (q *Queries) CreateMarker(ctx context.Context, arg *CreateMarkerParams) (*M
 		&i.Duration,
 		&i.CreatedAt,
 		&i.CreatedBy,
+		&i.Source,
 	)
 	return &i, err
 }
@@ -120,14 +127,36 @@ func (q *Queries) DeleteMarkersByVideo(ctx context.Context, videoID pgtype.UUID)
 	return err
 }
 
+const deleteMarkersByVideoSource = `-- name: DeleteMarkersByVideoSource :exec
+DELETE FROM markers
+WHERE video_id = $1
+  AND source = $2
+`
+
+type DeleteMarkersByVideoSourceParams struct {
+	VideoID pgtype.UUID  `db:"video_id" json:"VideoID"`
+	Source  MarkerSource `db:"source" json:"Source"`
+}
+
+// DeleteMarkersByVideoSource deletes a video's markers from one source, e.g.
+// its SponsorBlock markers before they are re-imported.
+//
+//	DELETE FROM markers
+//	WHERE video_id = $1
+//	  AND source = $2
+func (q *Queries) DeleteMarkersByVideoSource(ctx context.Context, arg *DeleteMarkersByVideoSourceParams) error {
+	_, err := q.db.Exec(ctx, deleteMarkersByVideoSource, arg.VideoID, arg.Source)
+	return err
+}
+
 const getMarker = `-- name: GetMarker :one
-SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by FROM markers
+SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source FROM markers
 WHERE id = $1
 `
 
 // GetMarker
 //
-//	SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by FROM markers
+//	SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source FROM markers
 //	WHERE id = $1
 func (q *Queries) GetMarker(ctx context.Context, id pgtype.UUID) (*Marker, error) {
 	row := q.db.QueryRow(ctx, getMarker, id)
@@ -143,19 +172,20 @@ func (q *Queries) GetMarker(ctx context.Context, id pgtype.UUID) (*Marker, error
 		&i.Duration,
 		&i.CreatedAt,
 		&i.CreatedBy,
+		&i.Source,
 	)
 	return &i, err
 }
 
 const listMarkersByVideo = `-- name: ListMarkersByVideo :many
-SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by FROM markers
+SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source FROM markers
 WHERE video_id = $1
 ORDER BY timestamp ASC
 `
 
 // ListMarkersByVideo
 //
-//	SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by FROM markers
+//	SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source FROM markers
 //	WHERE video_id = $1
 //	ORDER BY timestamp ASC
 func (q *Queries) ListMarkersByVideo(ctx context.Context, videoID pgtype.UUID) ([]*Marker, error) {
@@ -178,6 +208,7 @@ func (q *Queries) ListMarkersByVideo(ctx context.Context, videoID pgtype.UUID) (
 			&i.Duration,
 			&i.CreatedAt,
 			&i.CreatedBy,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -199,7 +230,7 @@ SET
     marker_type = COALESCE($5, marker_type),
     duration = COALESCE($6, duration)
 WHERE id = $7
-RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by
+RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source
 `
 
 type UpdateMarkerParams struct {
@@ -223,7 +254,7 @@ type UpdateMarkerParams struct {
 //	    marker_type = COALESCE($5, marker_type),
 //	    duration = COALESCE($6, duration)
 //	WHERE id = $7
-//	RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by
+//	RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source
 func (q *Queries) UpdateMarker(ctx context.Context, arg *UpdateMarkerParams) (*Marker, error) {
 	row := q.db.QueryRow(ctx, updateMarker,
 		arg.Timestamp,
@@ -246,6 +277,7 @@ func (q *Queries) UpdateMarker(ctx context.Context, arg *UpdateMarkerParams) (*M
 		&i.Duration,
 		&i.CreatedAt,
 		&i.CreatedBy,
+		&i.Source,
 	)
 	return &i, err
 }
@@ -12,6 +12,7 @@ import (
 	"thirdcoast.systems/rewind/pkg/utils/crops"
 	"thirdcoast.systems/rewind/pkg/utils/crypto"
 	"thirdcoast.systems/rewind/pkg/utils/language"
+	"thirdcoast.systems/rewind/pkg/utils/markdown"
 	"thirdcoast.systems/rewind/pkg/utils/passwords"
 	"thirdcoast.systems/rewind/pkg/videoinfo"
 )
@@ -202,6 +203,64 @@ func AllLogStreamValues() []LogStream {
 	}
 }
 
+type MarkerSource string
+
+const (
+	MarkerSourceUser         MarkerSource = "user"
+	MarkerSourceSponsorblock MarkerSource = "sponsorblock"
+)
+
+func (e *MarkerSource) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = MarkerSource(s)
+	case string:
+		*e = MarkerSource(s)
+	default:
+		return fmt.Errorf("unsupported scan type for MarkerSource: %T", src)
+	}
+	return nil
+}
+
+type NullMarkerSource struct {
+	MarkerSource MarkerSource `json:"MarkerSource"`
+	Valid        bool         `json:"Valid"` // Valid is true if MarkerSource is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullMarkerSource) Scan(value interface{}) error {
+	if value == nil {
+		ns.MarkerSource, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.MarkerSource.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullMarkerSource) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.MarkerSource), nil
+}
+
+func (e MarkerSource) Valid() bool {
+	switch e {
+	case MarkerSourceUser,
+		MarkerSourceSponsorblock:
+		return true
+	}
+	return false
+}
+
+func AllMarkerSourceValues() []MarkerSource {
+	return []MarkerSource{
+		MarkerSourceUser,
+		MarkerSourceSponsorblock,
+	}
+}
+
 type MarkerType string
 
 const (
@@ -337,27 +396,29 @@ type Clip struct {
 }
 
 type ClipExport struct {
-	ID             pgtype.UUID        `db:"id" json:"ID"`
-	ClipID         pgtype.UUID        `db:"clip_id" json:"ClipID"`
-	CreatedBy      pgtype.UUID        `db:"created_by" json:"CreatedBy"`
-	Format         string             `db:"format" json:"Format"`
-	FilePath       string             `db:"file_path" json:"FilePath"`
-	SizeBytes      int64              `db:"size_bytes" json:"SizeBytes"`
-	Status         ExportStatus       `db:"status" json:"Status"`
-	LastError      *string            `db:"last_error" json:"LastError"`
-	Variant        string             `db:"variant" json:"Variant"`
-	ClipUpdatedAt  pgtype.Timestamptz `db:"clip_updated_at" json:"ClipUpdatedAt"`
-	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
-	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
-	LastAccessedAt pgtype.Timestamptz `db:"last_accessed_at" json:"LastAccessedAt"`
-	Attempts       int32              `db:"attempts" json:"Attempts"`
-	LockedAt       pgtype.Timestamptz `db:"locked_at" json:"LockedAt"`
-	LockedBy       *string            `db:"locked_by" json:"LockedBy"`
-	StartedAt      pgtype.Timestamptz `db:"started_at" json:"StartedAt"`
-	FinishedAt     pgtype.Timestamptz `db:"finished_at" json:"FinishedAt"`
-	ProgressPct    int32              `db:"progress_pct" json:"ProgressPct"`
-	Pid            *int32             `db:"pid" json:"Pid"`
-	Spec           []byte             `db:"spec" json:"Spec"`
+	ID              pgtype.UUID        `db:"id" json:"ID"`
+	ClipID          pgtype.UUID        `db:"clip_id" json:"ClipID"`
+	CreatedBy       pgtype.UUID        `db:"created_by" json:"CreatedBy"`
+	Format          string             `db:"format" json:"Format"`
+	FilePath        string             `db:"file_path" json:"FilePath"`
+	SizeBytes       int64              `db:"size_bytes" json:"SizeBytes"`
+	Status          ExportStatus       `db:"status" json:"Status"`
+	LastError       *string            `db:"last_error" json:"LastError"`
+	Variant         string             `db:"variant" json:"Variant"`
+	ClipUpdatedAt   pgtype.Timestamptz `db:"clip_updated_at" json:"ClipUpdatedAt"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
+	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+	LastAccessedAt  pgtype.Timestamptz `db:"last_accessed_at" json:"LastAccessedAt"`
+	Attempts        int32              `db:"attempts" json:"Attempts"`
+	LockedAt        pgtype.Timestamptz `db:"locked_at" json:"LockedAt"`
+	LockedBy        *string            `db:"locked_by" json:"LockedBy"`
+	StartedAt       pgtype.Timestamptz `db:"started_at" json:"StartedAt"`
+	FinishedAt      pgtype.Timestamptz `db:"finished_at" json:"FinishedAt"`
+	ProgressPct     int32              `db:"progress_pct" json:"ProgressPct"`
+	Pid             *int32             `db:"pid" json:"Pid"`
+	Spec            []byte             `db:"spec" json:"Spec"`
+	ProgressHistory []byte             `db:"progress_history" json:"ProgressHistory"`
+	LadderParentID  pgtype.UUID        `db:"ladder_parent_id" json:"LadderParentID"`
 }
 
 type ComposeJob struct {
@@ -434,6 +495,10 @@ type DownloadJob struct {
 	ParentJobID  pgtype.UUID        `db:"parent_job_id" json:"ParentJobID"`
 	BatchLabel   *string            `db:"batch_label" json:"BatchLabel"`
 	BatchTotal   *int32             `db:"batch_total" json:"BatchTotal"`
+	ErrorCode    *string            `db:"error_code" json:"ErrorCode"`
+	Priority     int32              `db:"priority" json:"Priority"`
+	MetadataOnly bool               `db:"metadata_only" json:"MetadataOnly"`
+	NextRetryAt  pgtype.Timestamptz `db:"next_retry_at" json:"NextRetryAt"`
 }
 
 type ExtensionToken struct {
@@ -447,24 +512,40 @@ type ExtensionToken struct {
 }
 
 type IngestJob struct {
-	ID            pgtype.UUID        `db:"id" json:"ID"`
-	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
-	UpdatedAt     pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
-	DownloadJobID pgtype.UUID        `db:"download_job_id" json:"DownloadJobID"`
-	Status        JobStatus          `db:"status" json:"Status"`
-	Attempts      int32              `db:"attempts" json:"Attempts"`
-	LastError     *string            `db:"last_error" json:"LastError"`
-	StartedAt     pgtype.Timestamptz `db:"started_at" json:"StartedAt"`
-	FinishedAt    pgtype.Timestamptz `db:"finished_at" json:"FinishedAt"`
-	AssetScope    *string            `db:"asset_scope" json:"AssetScope"`
+	ID              pgtype.UUID        `db:"id" json:"ID"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
+	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+	DownloadJobID   pgtype.UUID        `db:"download_job_id" json:"DownloadJobID"`
+	Status          JobStatus          `db:"status" json:"Status"`
+	Attempts        int32              `db:"attempts" json:"Attempts"`
+	LastError       *string            `db:"last_error" json:"LastError"`
+	StartedAt       pgtype.Timestamptz `db:"started_at" json:"StartedAt"`
+	FinishedAt      pgtype.Timestamptz `db:"finished_at" json:"FinishedAt"`
+	AssetScope      *string            `db:"asset_scope" json:"AssetScope"`
+	NextRetryAt     pgtype.Timestamptz `db:"next_retry_at" json:"NextRetryAt"`
+	ThumbnailAt     *float64           `db:"thumbnail_at" json:"ThumbnailAt"`
+	WhisperModel    *string            `db:"whisper_model" json:"WhisperModel"`
+	WhisperLanguage *string            `db:"whisper_language" json:"WhisperLanguage"`
 }
 
 type InstanceSetting struct {
-	ID                          int32              `db:"id" json:"ID"`
-	RegistrationEnabled         bool               `db:"registration_enabled" json:"RegistrationEnabled"`
-	ClipExportStorageLimitBytes int64              `db:"clip_export_storage_limit_bytes" json:"ClipExportStorageLimitBytes"`
-	AdminEmails                 []string           `db:"admin_emails" json:"AdminEmails"`
-	UpdatedAt                   pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+	ID                           int32              `db:"id" json:"ID"`
+	RegistrationEnabled          bool               `db:"registration_enabled" json:"RegistrationEnabled"`
+	ClipExportStorageLimitBytes  int64              `db:"clip_export_storage_limit_bytes" json:"ClipExportStorageLimitBytes"`
+	AdminEmails                  []string           `db:"admin_emails" json:"AdminEmails"`
+	UpdatedAt                    pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+	RegistrationRequiresApproval bool               `db:"registration_requires_approval" json:"RegistrationRequiresApproval"`
+	DownloadRetryMaxAttempts     int32              `db:"download_retry_max_attempts" json:"DownloadRetryMaxAttempts"`
+	DownloadRetryBackoff         string             `db:"download_retry_backoff" json:"DownloadRetryBackoff"`
+	DownloadRetryBaseSeconds     int32              `db:"download_retry_base_seconds" json:"DownloadRetryBaseSeconds"`
+	DownloadRetryCapSeconds      int32              `db:"download_retry_cap_seconds" json:"DownloadRetryCapSeconds"`
+	IngestRetryMaxAttempts       int32              `db:"ingest_retry_max_attempts" json:"IngestRetryMaxAttempts"`
+	IngestRetryBackoff           string             `db:"ingest_retry_backoff" json:"IngestRetryBackoff"`
+	IngestRetryBaseSeconds       int32              `db:"ingest_retry_base_seconds" json:"IngestRetryBaseSeconds"`
+	IngestRetryCapSeconds        int32              `db:"ingest_retry_cap_seconds" json:"IngestRetryCapSeconds"`
+	UserDownloadMaxHeight        int32              `db:"user_download_max_height" json:"UserDownloadMaxHeight"`
+	AdminDownloadMaxHeight       int32              `db:"admin_download_max_height" json:"AdminDownloadMaxHeight"`
+	PlaybackMaxHeight            int32              `db:"playback_max_height" json:"PlaybackMaxHeight"`
 }
 
 type Marker struct {
@@ -478,6 +559,7 @@ type Marker struct {
 	Duration    *float64           `db:"duration" json:"Duration"`
 	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
 	CreatedBy   pgtype.UUID        `db:"created_by" json:"CreatedBy"`
+	Source      MarkerSource       `db:"source" json:"Source"`
 }
 
 type PlaybackPosition struct {
@@ -485,6 +567,7 @@ type PlaybackPosition struct {
 	VideoID         pgtype.UUID        `db:"video_id" json:"VideoID"`
 	PositionSeconds float64            `db:"position_seconds" json:"PositionSeconds"`
 	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+	WatchedAt       pgtype.Timestamptz `db:"watched_at" json:"WatchedAt"`
 }
 
 type PlayerScenePreset struct {
@@ -567,6 +650,8 @@ type User struct {
 	UpdatedAt             pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
 	DeletedAt             pgtype.Timestamptz `db:"deleted_at" json:"DeletedAt"`
 	SessionsInvalidatedAt pgtype.Timestamptz `db:"sessions_invalidated_at" json:"SessionsInvalidatedAt"`
+	PendingApproval       bool               `db:"pending_approval" json:"PendingApproval"`
+	DownloadMaxHeight     *int32             `db:"download_max_height" json:"DownloadMaxHeight"`
 }
 
 type UserKeybinding struct {
@@ -575,6 +660,18 @@ type UserKeybinding struct {
 	Key    string      `db:"key" json:"Key"`
 }
 
+type UserPreference struct {
+	UserID                 pgtype.UUID        `db:"user_id" json:"UserID"`
+	ExportFilenameTemplate string             `db:"export_filename_template" json:"ExportFilenameTemplate"`
+	UpdatedAt              pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+	CaptionFontSize        string             `db:"caption_font_size" json:"CaptionFontSize"`
+	CaptionColor           string             `db:"caption_color" json:"CaptionColor"`
+	CaptionBackground      string             `db:"caption_background" json:"CaptionBackground"`
+	CaptionPosition        string             `db:"caption_position" json:"CaptionPosition"`
+	CookieDomainAllowlist  []string           `db:"cookie_domain_allowlist" json:"CookieDomainAllowlist"`
+	CookieDomainBlocklist  []string           `db:"cookie_domain_blocklist" json:"CookieDomainBlocklist"`
+}
+
 type Video struct {
 	ID                 pgtype.UUID          `db:"id" json:"ID"`
 	CreatedAt          pgtype.Timestamptz   `db:"created_at" json:"CreatedAt"`
@@ -604,6 +701,21 @@ type Video struct {
 	Search             string               `db:"search" json:"Search"`
 	ProbeData          *videoinfo.ProbeInfo `db:"probe_data" json:"ProbeData"`
 	CommentsCheckedAt  pgtype.Timestamptz   `db:"comments_checked_at" json:"CommentsCheckedAt"`
+	Phash              *int64               `db:"phash" json:"Phash"`
+	PhashFrames        []int64              `db:"phash_frames" json:"PhashFrames"`
+	MetadataOnly       bool                 `db:"metadata_only" json:"MetadataOnly"`
+	AutoTaggedAt       pgtype.Timestamptz   `db:"auto_tagged_at" json:"AutoTaggedAt"`
+	ThumbnailPhash     *int64               `db:"thumbnail_phash" json:"ThumbnailPhash"`
+}
+
+type VideoChapter struct {
+	VideoID      pgtype.UUID        `db:"video_id" json:"VideoID"`
+	Position     int32              `db:"position" json:"Position"`
+	StartSeconds float64            `db:"start_seconds" json:"StartSeconds"`
+	EndSeconds   float64            `db:"end_seconds" json:"EndSeconds"`
+	Title        string             `db:"title" json:"Title"`
+	Source       string             `db:"source" json:"Source"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
 }
 
 type VideoComment struct {
@@ -622,6 +734,42 @@ type VideoComment struct {
 	Text        *string            `db:"text" json:"Text"`
 	Raw         []byte             `db:"raw" json:"Raw"`
 	Search      string             `db:"search" json:"Search"`
+	FirstSeenAt pgtype.Timestamptz `db:"first_seen_at" json:"FirstSeenAt"`
+	LastSeenAt  pgtype.Timestamptz `db:"last_seen_at" json:"LastSeenAt"`
+	DeletedAt   pgtype.Timestamptz `db:"deleted_at" json:"DeletedAt"`
+}
+
+type VideoCommentEdit struct {
+	ID             pgtype.UUID        `db:"id" json:"ID"`
+	VideoCommentID pgtype.UUID        `db:"video_comment_id" json:"VideoCommentID"`
+	Text           *string            `db:"text" json:"Text"`
+	Raw            []byte             `db:"raw" json:"Raw"`
+	ReplacedAt     pgtype.Timestamptz `db:"replaced_at" json:"ReplacedAt"`
+}
+
+type VideoHlsKey struct {
+	VideoID   pgtype.UUID           `db:"video_id" json:"VideoID"`
+	Key       crypto.EncryptedBytes `db:"key" json:"Key"`
+	CreatedAt pgtype.Timestamptz    `db:"created_at" json:"CreatedAt"`
+}
+
+type VideoNote struct {
+	UserID    pgtype.UUID        `db:"user_id" json:"UserID"`
+	VideoID   pgtype.UUID        `db:"video_id" json:"VideoID"`
+	Body      markdown.Markdown  `db:"body" json:"Body"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
+	UpdatedAt pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+}
+
+type VideoPhashAttempt struct {
+	VideoID     pgtype.UUID        `db:"video_id" json:"VideoID"`
+	AttemptedAt pgtype.Timestamptz `db:"attempted_at" json:"AttemptedAt"`
+}
+
+type VideoPhashPair struct {
+	VideoID  pgtype.UUID `db:"video_id" json:"VideoID"`
+	OtherID  pgtype.UUID `db:"other_id" json:"OtherID"`
+	Distance int16       `db:"distance" json:"Distance"`
 }
 
 type VideoRevision struct {
@@ -643,6 +791,12 @@ type VideoTag struct {
 	TagID     pgtype.UUID        `db:"tag_id" json:"TagID"`
 	CreatedAt pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
 	CreatedBy pgtype.UUID        `db:"created_by" json:"CreatedBy"`
+	Auto      bool               `db:"auto" json:"Auto"`
+}
+
+type VideoThumbnailPhashAttempt struct {
+	VideoID     pgtype.UUID        `db:"video_id" json:"VideoID"`
+	AttemptedAt pgtype.Timestamptz `db:"attempted_at" json:"AttemptedAt"`
 }
 
 type VideoTranscript struct {
@@ -657,6 +811,16 @@ type VideoTranscript struct {
 	Search    string             `db:"search" json:"Search"`
 }
 
+type VideoTranscriptWord struct {
+	VideoID     pgtype.UUID  `db:"video_id" json:"VideoID"`
+	Lang        language.Tag `db:"lang" json:"Lang"`
+	CueIndex    int32        `db:"cue_index" json:"CueIndex"`
+	WordIndex   int32        `db:"word_index" json:"WordIndex"`
+	StartOffset float64      `db:"start_offset" json:"StartOffset"`
+	EndOffset   float64      `db:"end_offset" json:"EndOffset"`
+	Word        string       `db:"word" json:"Word"`
+}
+
 type YtdlpLog struct {
 	ID        int64              `db:"id" json:"ID"`
 	JobID     pgtype.UUID        `db:"job_id" json:"JobID"`
@@ -12,7 +12,7 @@ import (
 )
 
 const getPlaybackPosition = `-- name: GetPlaybackPosition :one
-SELECT position_seconds, updated_at
+SELECT position_seconds, updated_at, watched_at
 FROM playback_positions
 WHERE user_id = $1
   AND video_id = $2
@@ -26,21 +26,115 @@ type GetPlaybackPositionParams struct {
 type GetPlaybackPositionRow struct {
 	PositionSeconds float64            `db:"position_seconds" json:"PositionSeconds"`
 	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+	WatchedAt       pgtype.Timestamptz `db:"watched_at" json:"WatchedAt"`
 }
 
 // GetPlaybackPosition retrieves the last playback position for a user/video
 //
-//	SELECT position_seconds, updated_at
+//	SELECT position_seconds, updated_at, watched_at
 //	FROM playback_positions
 //	WHERE user_id = $1
 //	  AND video_id = $2
 func (q *Queries) GetPlaybackPosition(ctx context.Context, arg *GetPlaybackPositionParams) (*GetPlaybackPositionRow, error) {
 	row := q.db.QueryRow(ctx, getPlaybackPosition, arg.UserID, arg.VideoID)
 	var i GetPlaybackPositionRow
-	err := row.Scan(&i.PositionSeconds, &i.UpdatedAt)
+	err := row.Scan(&i.PositionSeconds, &i.UpdatedAt, &i.WatchedAt)
 	return &i, err
 }
 
+const getVideoPlaybackState = `-- name: GetVideoPlaybackState :one
+SELECT
+    v.duration_seconds,
+    pp.position_seconds,
+    pp.updated_at,
+    pp.watched_at
+FROM videos v
+LEFT JOIN playback_positions pp
+    ON pp.video_id = v.id
+   AND pp.user_id = $1
+WHERE v.id = $2
+`
+
+type GetVideoPlaybackStateParams struct {
+	UserID  pgtype.UUID `db:"user_id" json:"UserID"`
+	VideoID pgtype.UUID `db:"video_id" json:"VideoID"`
+}
+
+type GetVideoPlaybackStateRow struct {
+	DurationSeconds *int32             `db:"duration_seconds" json:"DurationSeconds"`
+	PositionSeconds *float64           `db:"position_seconds" json:"PositionSeconds"`
+	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"UpdatedAt"`
+	WatchedAt       pgtype.Timestamptz `db:"watched_at" json:"WatchedAt"`
+}
+
+// GetVideoPlaybackState returns a video's duration with the user's saved
+// position and watched state, if any. No row means the video does not exist.
+//
+//	SELECT
+//	    v.duration_seconds,
+//	    pp.position_seconds,
+//	    pp.updated_at,
+//	    pp.watched_at
+//	FROM videos v
+//	LEFT JOIN playback_positions pp
+//	    ON pp.video_id = v.id
+//	   AND pp.user_id = $1
+//	WHERE v.id = $2
+func (q *Queries) GetVideoPlaybackState(ctx context.Context, arg *GetVideoPlaybackStateParams) (*GetVideoPlaybackStateRow, error) {
+	row := q.db.QueryRow(ctx, getVideoPlaybackState, arg.UserID, arg.VideoID)
+	var i GetVideoPlaybackStateRow
+	err := row.Scan(
+		&i.DurationSeconds,
+		&i.PositionSeconds,
+		&i.UpdatedAt,
+		&i.WatchedAt,
+	)
+	return &i, err
+}
+
+const setPlaybackWatched = `-- name: SetPlaybackWatched :exec
+INSERT INTO playback_positions (user_id, video_id, position_seconds, updated_at, watched_at)
+VALUES (
+    $1,
+    $2,
+    0,
+    CURRENT_TIMESTAMP,
+    CASE WHEN $3::bool THEN CURRENT_TIMESTAMP END
+)
+ON CONFLICT (user_id, video_id)
+DO UPDATE SET
+    position_seconds = 0,
+    updated_at = CURRENT_TIMESTAMP,
+    watched_at = EXCLUDED.watched_at
+`
+
+type SetPlaybackWatchedParams struct {
+	UserID  pgtype.UUID `db:"user_id" json:"UserID"`
+	VideoID pgtype.UUID `db:"video_id" json:"VideoID"`
+	Watched bool        `db:"watched" json:"Watched"`
+}
+
+// SetPlaybackWatched marks a video watched or unwatched for a user. Either
+// way the resume position goes back to the start.
+//
+//	INSERT INTO playback_positions (user_id, video_id, position_seconds, updated_at, watched_at)
+//	VALUES (
+//	    $1,
+//	    $2,
+//	    0,
+//	    CURRENT_TIMESTAMP,
+//	    CASE WHEN $3::bool THEN CURRENT_TIMESTAMP END
+//	)
+//	ON CONFLICT (user_id, video_id)
+//	DO UPDATE SET
+//	    position_seconds = 0,
+//	    updated_at = CURRENT_TIMESTAMP,
+//	    watched_at = EXCLUDED.watched_at
+func (q *Queries) SetPlaybackWatched(ctx context.Context, arg *SetPlaybackWatchedParams) error {
+	_, err := q.db.Exec(ctx, setPlaybackWatched, arg.UserID, arg.VideoID, arg.Watched)
+	return err
+}
+
 const upsertPlaybackPosition = `-- name: UpsertPlaybackPosition :exec
 INSERT INTO playback_positions (user_id, video_id, position_seconds, updated_at)
 VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
@@ -8,14 +8,23 @@ import (
 	"context"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"thirdcoast.systems/rewind/pkg/utils/crypto"
 )
 
 type Querier interface {
-	// AddVideoTag links a tag to a video (idempotent).
+	// AddVideoAutoTag links a suggested tag to a video. A link the user already
+	// made is left as a manual tag.
+	//
+	//  INSERT INTO video_tags (video_id, tag_id, auto)
+	//  VALUES ($1, $2, true)
+	//  ON CONFLICT (video_id, tag_id) DO NOTHING
+	AddVideoAutoTag(ctx context.Context, arg *AddVideoAutoTagParams) error
+	// AddVideoTag links a tag to a video (idempotent). Adding a tag by hand that
+	// was suggested automatically keeps it through later re-tagging.
 	//
 	//  INSERT INTO video_tags (video_id, tag_id, created_by)
 	//  VALUES ($1, $2, $3)
-	//  ON CONFLICT (video_id, tag_id) DO NOTHING
+	//  ON CONFLICT (video_id, tag_id) DO UPDATE SET auto = false
 	AddVideoTag(ctx context.Context, arg *AddVideoTagParams) error
 	// AddVideoTagToMany links one tag to many videos at once (idempotent). Drives
 	// the library bulk-tag action.
@@ -23,13 +32,26 @@ type Querier interface {
 	//  INSERT INTO video_tags (video_id, tag_id, created_by)
 	//  SELECT v, $1, $2
 	//  FROM unnest($3::uuid[]) AS v
-	//  ON CONFLICT (video_id, tag_id) DO NOTHING
+	//  ON CONFLICT (video_id, tag_id) DO UPDATE SET auto = false
 	AddVideoTagToMany(ctx context.Context, arg *AddVideoTagToManyParams) error
 	// Releases a PostgreSQL advisory lock
 	// Returns true if the lock was released, false if it wasn't held
 	//
 	//  SELECT pg_advisory_unlock($1::bigint) AS unlocked
 	AdvisoryUnlock(ctx context.Context, lockID int64) (bool, error)
+	// ArchiveEditedVideoComments copies the stored version of every comment in a
+	// yt-dlp comment array whose text has changed into video_comment_edits. Run it
+	// before UpsertVideoCommentsFromJSON overwrites the text.
+	//
+	//  INSERT INTO video_comment_edits (video_comment_id, text, raw, replaced_at)
+	//  SELECT vc.id, vc.text, vc.raw, NOW()
+	//  FROM jsonb_array_elements($1::jsonb) AS c
+	//  JOIN video_comments vc
+	//    ON vc.video_id = $2::uuid
+	//   AND vc.source = $3::text
+	//   AND vc.comment_id = c->>'id'
+	//  WHERE vc.text IS DISTINCT FROM COALESCE(NULLIF(c->>'text', ''), NULLIF(c->>'content', ''), NULLIF(c->>'comment', ''))
+	ArchiveEditedVideoComments(ctx context.Context, arg *ArchiveEditedVideoCommentsParams) (int64, error)
 	// ArchiveJob marks a job as archived (soft delete).
 	//
 	//  UPDATE download_jobs
@@ -54,6 +76,31 @@ type Querier interface {
 	//      updated_at = NOW()
 	//  WHERE id = $1
 	CancelDownloadJob(ctx context.Context, id pgtype.UUID) error
+	// CancelQueuedDownloadJobsForUser cancels every queued (not yet claimed)
+	// download job submitted by a user, returning the number of jobs cancelled.
+	//
+	//  UPDATE download_jobs
+	//  SET status = 'failed',
+	//      last_error = 'Cancelled by user',
+	//      finished_at = NOW(),
+	//      process_pid = NULL,
+	//      updated_at = NOW()
+	//  WHERE archived_by = $1
+	//    AND status = 'queued'
+	CancelQueuedDownloadJobsForUser(ctx context.Context, archivedBy pgtype.UUID) (int64, error)
+	// Mark a ladder lead's pending rungs as processing by the same worker
+	//
+	//  UPDATE clip_exports
+	//  SET status = 'processing',
+	//      locked_at = NOW(),
+	//      locked_by = $1,
+	//      started_at = NOW(),
+	//      attempts = attempts + 1,
+	//      updated_at = NOW()
+	//  WHERE ladder_parent_id = $2
+	//    AND status IN ('queued', 'processing')
+	//  RETURNING id, spec
+	ClaimClipExportRungs(ctx context.Context, arg *ClaimClipExportRungsParams) ([]*ClaimClipExportRungsRow, error)
 	// ClaimVideosForCommentCatchup atomically claims up to batch_size videos that
 	// have no comments (and weren't checked in the last 30 days), marking
 	// comments_checked_at so other downloader replicas skip them. The downloader
@@ -121,14 +168,49 @@ type Querier interface {
 	//      last_error = NULL
 	//  WHERE id = $3
 	CompletePlaylistJob(ctx context.Context, arg *CompletePlaylistJobParams) error
+	// CountActiveClipExportsByUser returns how many of the user's exports are
+	// queued or processing, for the per-user export cap.
+	//
+	//  SELECT COUNT(*) FROM clip_exports
+	//  WHERE created_by = $1
+	//    AND status IN ('queued', 'processing')
+	CountActiveClipExportsByUser(ctx context.Context, createdBy pgtype.UUID) (int64, error)
 	//CountClipExports
 	//
 	//  SELECT COUNT(*) FROM clip_exports
 	CountClipExports(ctx context.Context) (int64, error)
+	// CountCookieBlockedDownloadJobs counts a user's failed, unarchived download
+	// jobs whose error_code is one of the given cookie-fixable codes.
+	//
+	//  SELECT COUNT(*)::bigint
+	//  FROM download_jobs
+	//  WHERE archived_by = $1
+	//    AND status = 'failed'
+	//    AND archived = FALSE
+	//    AND error_code = ANY($2::text[])
+	CountCookieBlockedDownloadJobs(ctx context.Context, arg *CountCookieBlockedDownloadJobsParams) (int64, error)
 	// CountEnabledAdmins counts enabled admin users
 	//
 	//  SELECT COUNT(*)::bigint FROM users WHERE deleted_at IS NULL AND enabled = TRUE AND role = 'admin'
 	CountEnabledAdmins(ctx context.Context) (int64, error)
+	// CountTranscribedVideos returns how many videos have a transcript, the
+	// document count for keyword weighting.
+	//
+	//  SELECT COUNT(DISTINCT video_id)::bigint FROM video_transcripts
+	CountTranscribedVideos(ctx context.Context) (int64, error)
+	// CountTranscriptTermVideos returns, for each term, how many videos have a
+	// transcript containing it. Matches the 'simple' search vector, so terms are
+	// compared lowercased and unstemmed.
+	//
+	//  SELECT
+	//      t.term::text AS term,
+	//      (
+	//          SELECT COUNT(DISTINCT vt.video_id)
+	//          FROM video_transcripts vt
+	//          WHERE vt.search @@ plainto_tsquery('simple'::regconfig, t.term)
+	//      )::bigint AS videos
+	//  FROM unnest($1::text[]) AS t(term)
+	CountTranscriptTermVideos(ctx context.Context, terms []string) ([]*CountTranscriptTermVideosRow, error)
 	// CountUserCookies counts the number of cookies for a user
 	//
 	//  SELECT COUNT(*) as count
@@ -145,6 +227,22 @@ type Querier interface {
 	//  FROM video_comments
 	//  WHERE video_id = $1
 	CountVideoComments(ctx context.Context, videoID pgtype.UUID) (int64, error)
+	// CountVideos returns the total number of archived videos.
+	//
+	//  SELECT COUNT(*) FROM videos
+	CountVideos(ctx context.Context) (int64, error)
+	// CountVideosByChannel returns how many archived videos belong to a channel.
+	//
+	//  SELECT COUNT(*) FROM videos WHERE channel_id = $1
+	CountVideosByChannel(ctx context.Context, channelID *string) (int64, error)
+	// CountVideosMissingPreview returns the number of videos whose preview failed
+	// or was skipped (assets_status.preview = false).
+	//
+	//  SELECT COUNT(*)
+	//  FROM videos
+	//  WHERE video_path IS NOT NULL AND btrim(video_path) <> ''
+	//  AND assets_status @> '{"preview": false}'::jsonb
+	CountVideosMissingPreview(ctx context.Context) (int64, error)
 	// CountVideosWithAssetErrors returns the number of videos with asset generation errors.
 	//
 	//  SELECT COUNT(*)
@@ -153,6 +251,10 @@ type Querier interface {
 	//  AND assets_status ? '_error_count'
 	//  AND (assets_status->>'_error_count')::int > 0
 	CountVideosWithAssetErrors(ctx context.Context) (int64, error)
+	// CountVideosWithPHash returns how many videos have a perceptual hash.
+	//
+	//  SELECT COUNT(*) FROM videos WHERE phash IS NOT NULL
+	CountVideosWithPHash(ctx context.Context) (int64, error)
 	// CountVideosWithVideoPath returns count of videos that have a video_path.
 	//
 	//  SELECT COUNT(*)
@@ -161,8 +263,11 @@ type Querier interface {
 	CountVideosWithVideoPath(ctx context.Context) (int64, error)
 	//CountYtdlpLogsForJob
 	//
-	//  SELECT COUNT(*) FROM ytdlp_logs WHERE job_id = $1
-	CountYtdlpLogsForJob(ctx context.Context, jobID pgtype.UUID) (int64, error)
+	//  SELECT COUNT(*) FROM ytdlp_logs
+	//  WHERE job_id = $1
+	//    AND ($2::text IS NULL OR stream::text = $2::text)
+	//    AND ($3::text IS NULL OR strpos(lower(message), lower($3::text)) > 0)
+	CountYtdlpLogsForJob(ctx context.Context, arg *CountYtdlpLogsForJobParams) (int64, error)
 	//CreateClip
 	//
 	//  INSERT INTO clips (
@@ -193,6 +298,13 @@ type Querier interface {
 	//  VALUES ($1, $2, $3, $4, $5, $6, '', 'queued', NOW(), NOW())
 	//  RETURNING id
 	CreateClipExport(ctx context.Context, arg *CreateClipExportParams) (pgtype.UUID, error)
+	// CreateClipExportRung adds a queued rung to a rendition ladder. Rungs are
+	// encoded by the lead's worker, so no NOTIFY is needed.
+	//
+	//  INSERT INTO clip_exports (clip_id, created_by, format, variant, spec, clip_updated_at, ladder_parent_id, file_path, status, created_at, updated_at)
+	//  VALUES ($1, $2, $3, $4, $5, $6, $7, '', 'queued', NOW(), NOW())
+	//  RETURNING id
+	CreateClipExportRung(ctx context.Context, arg *CreateClipExportRungParams) (pgtype.UUID, error)
 	//CreateExtensionToken
 	//
 	//  INSERT INTO extension_tokens (user_id, token, expires_at)
@@ -209,7 +321,8 @@ type Querier interface {
 	//      color,
 	//      marker_type,
 	//      duration,
-	//      created_by
+	//      created_by,
+	//      source
 	//  ) VALUES (
 	//      $1,
 	//      $2,
@@ -218,8 +331,9 @@ type Querier interface {
 	//      $5,
 	//      $6,
 	//      $7,
-	//      $8
-	//  ) RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by
+	//      $8,
+	//      $9
+	//  ) RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source
 	CreateMarker(ctx context.Context, arg *CreateMarkerParams) (*Marker, error)
 	//CreatePlayerSession
 	//
@@ -272,6 +386,13 @@ type Querier interface {
 	//  DELETE FROM markers
 	//  WHERE video_id = $1
 	DeleteMarkersByVideo(ctx context.Context, videoID pgtype.UUID) error
+	// DeleteMarkersByVideoSource deletes a video's markers from one source, e.g.
+	// its SponsorBlock markers before they are re-imported.
+	//
+	//  DELETE FROM markers
+	//  WHERE video_id = $1
+	//    AND source = $2
+	DeleteMarkersByVideoSource(ctx context.Context, arg *DeleteMarkersByVideoSourceParams) error
 	//DeletePlayerScenePreset
 	//
 	//  DELETE FROM player_scene_presets
@@ -307,34 +428,78 @@ type Querier interface {
 	//  DELETE FROM videos
 	//  WHERE id = $1
 	DeleteVideo(ctx context.Context, id pgtype.UUID) error
-	// DequeueDownloadJob claims one queued download job.
+	// DeleteVideoAutoTags removes a video's automatically added tag links.
+	//
+	//  DELETE FROM video_tags
+	//  WHERE video_id = $1 AND auto
+	DeleteVideoAutoTags(ctx context.Context, videoID pgtype.UUID) error
+	// DeleteVideoChapters removes a video's stored chapters before they are
+	// replaced.
+	//
+	//  DELETE FROM video_chapters
+	//  WHERE video_id = $1
+	DeleteVideoChapters(ctx context.Context, videoID pgtype.UUID) error
+	// DeleteVideoNote removes the user's note on a video.
+	//
+	//  DELETE FROM video_notes
+	//  WHERE user_id = $1
+	//    AND video_id = $2
+	DeleteVideoNote(ctx context.Context, arg *DeleteVideoNoteParams) error
+	// DeleteVideoPHashPairs removes a video's near-duplicate pairs, before
+	// InsertVideoPHashPairs stores them again for a new hash.
+	//
+	//  DELETE FROM video_phash_pairs
+	//  WHERE video_id = $1 OR other_id = $1
+	DeleteVideoPHashPairs(ctx context.Context, videoID pgtype.UUID) error
+	// DeleteVideoTranscriptWords removes the word timings of a video's transcript
+	// in a language before it is replaced.
+	//
+	//  DELETE FROM video_transcript_words
+	//  WHERE video_id = $1
+	//      AND lang = $2::language_tag
+	DeleteVideoTranscriptWords(ctx context.Context, arg *DeleteVideoTranscriptWordsParams) error
+	// DequeueDownloadJob claims one queued download job, highest priority first.
+	// Jobs waiting out a retry backoff (next_retry_at in the future) are skipped,
+	// as are jobs of users who already have max_per_user jobs processing (0 means
+	// no limit), so one user's playlist can't occupy every worker.
+	// Callers enforcing the cap hold LockDownloadDequeue in the same transaction.
 	//
 	//  WITH cte AS (
-	//      SELECT id
-	//      FROM download_jobs
-	//      WHERE status = 'queued'
-	//      ORDER BY created_at
+	//      SELECT j.id
+	//      FROM download_jobs j
+	//      WHERE j.status = 'queued'
+	//        AND (j.next_retry_at IS NULL OR j.next_retry_at <= NOW())
+	//        AND ($1::int <= 0 OR (
+	//            SELECT COUNT(*)
+	//            FROM download_jobs p
+	//            WHERE p.archived_by = j.archived_by
+	//              AND p.status = 'processing'
+	//        ) < $1::int)
+	//      ORDER BY j.priority DESC, j.created_at
 	//      LIMIT 1
 	//      FOR UPDATE SKIP LOCKED
 	//  )
 	//  UPDATE download_jobs
 	//  SET status = 'processing',
 	//      attempts = attempts + 1,
+	//      next_retry_at = NULL,
 	//      started_at = COALESCE(started_at, NOW()),
 	//      updated_at = NOW()
 	//  WHERE id IN (SELECT id FROM cte)
-	//  RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
-	DequeueDownloadJob(ctx context.Context) (*DownloadJob, error)
+	//  RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
+	DequeueDownloadJob(ctx context.Context, maxPerUser int32) (*DownloadJob, error)
 	// DequeueIngestJob claims one queued ingest job and returns needed info.
 	// Returns video_id for asset regeneration jobs (NULL for normal ingest).
-	// Skips jobs that have already been retried too many times.
+	// Skips jobs that have already been retried max_attempts times and jobs still
+	// waiting out a retry backoff.
 	//
 	//  WITH cte AS (
 	//      SELECT ij.id
 	//      FROM ingest_jobs ij
 	//      JOIN download_jobs dj ON dj.id = ij.download_job_id
 	//      WHERE ij.status = 'queued'
-	//        AND ij.attempts < 5
+	//        AND ij.attempts < $1::int
+	//        AND (ij.next_retry_at IS NULL OR ij.next_retry_at <= NOW())
 	//      ORDER BY
 	//        -- Real ingests (fresh downloads with a spool/info.json) take priority over
 	//        -- background asset-regeneration jobs, so a large regen batch never starves
@@ -349,6 +514,7 @@ type Querier interface {
 	//  UPDATE ingest_jobs AS ij
 	//  SET status = 'processing',
 	//      attempts = ij.attempts + 1,
+	//      next_retry_at = NULL,
 	//      started_at = COALESCE(ij.started_at, NOW()),
 	//      updated_at = NOW()
 	//  FROM download_jobs AS dj
@@ -364,8 +530,12 @@ type Querier interface {
 	//      dj.info_json_path AS info_json_path,
 	//      dj.video_id AS video_id,
 	//      ij.asset_scope AS asset_scope,
-	//      dj.extra_args AS extra_args
-	DequeueIngestJob(ctx context.Context) (*DequeueIngestJobRow, error)
+	//      ij.thumbnail_at AS thumbnail_at,
+	//      ij.whisper_model AS whisper_model,
+	//      ij.whisper_language AS whisper_language,
+	//      dj.extra_args AS extra_args,
+	//      dj.metadata_only AS metadata_only
+	DequeueIngestJob(ctx context.Context, maxAttempts int32) (*DequeueIngestJobRow, error)
 	// EmailRegistered checks if an email is already registered
 	//
 	//  SELECT EXISTS (
@@ -376,6 +546,8 @@ type Querier interface {
 	EmailRegistered(ctx context.Context, email string) (bool, error)
 	// EnqueueAssetRegenerationJob creates a download + ingest job pair for regenerating assets.
 	// asset_scope: NULL = all assets, or one of 'thumbnail', 'preview', 'seek', 'waveform'.
+	// thumbnail_at pins the thumbnail frame (seconds); negative clears the pin, NULL keeps it.
+	// whisper_model and whisper_language override the Whisper settings for this run; NULL uses the defaults.
 	//
 	//  WITH new_download_job AS (
 	//      INSERT INTO download_jobs (
@@ -393,20 +565,26 @@ type Querier interface {
 	//          v.id
 	//      FROM videos v
 	//      WHERE v.id = $1
-	//      RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+	//      RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 	//  ),
 	//  new_ingest_job AS (
 	//      INSERT INTO ingest_jobs (
 	//          download_job_id,
 	//          status,
-	//          asset_scope
+	//          asset_scope,
+	//          thumbnail_at,
+	//          whisper_model,
+	//          whisper_language
 	//      )
 	//      SELECT
 	//          new_download_job.id,
 	//          'queued',
-	//          $2::text
+	//          $2::text,
+	//          $3::double precision,
+	//          $4::text,
+	//          $5::text
 	//      FROM new_download_job
-	//      RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+	//      RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 	//  )
 	//  SELECT
 	//      new_ingest_job.id AS ingest_job_id,
@@ -414,31 +592,127 @@ type Querier interface {
 	//      new_download_job.video_id AS video_id
 	//  FROM new_ingest_job, new_download_job
 	EnqueueAssetRegenerationJob(ctx context.Context, arg *EnqueueAssetRegenerationJobParams) (*EnqueueAssetRegenerationJobRow, error)
+	// EnqueueCaptionBackfillBatch queues a "captions" asset regeneration job for
+	// up to batch_size caption-less videos with id > after_id, optionally limited
+	// to one channel. Videos that already have a captions job queued or running
+	// are skipped, as are videos whose last captions job failed or produced no
+	// transcript after retry_before. Returns the queued video IDs in order.
+	//
+	//  WITH batch AS (
+	//      SELECT v.id, v.src, v.archived_by
+	//      FROM videos v
+	//      WHERE v.id > $1::uuid
+	//        AND ($2::text IS NULL OR v.channel_id = $2)
+	//        AND NOT EXISTS (SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id)
+	//        AND NOT EXISTS (
+	//            SELECT 1
+	//            FROM ingest_jobs ij
+	//            JOIN download_jobs dj ON dj.id = ij.download_job_id
+	//            WHERE dj.video_id = v.id
+	//              AND ij.asset_scope = 'captions'
+	//              AND (
+	//                  ij.status IN ('queued', 'processing')
+	//                  OR COALESCE(ij.finished_at, ij.updated_at) >= $3
+	//              )
+	//        )
+	//      ORDER BY v.id
+	//      LIMIT $4
+	//  ),
+	//  new_download_jobs AS (
+	//      INSERT INTO download_jobs (
+	//          url,
+	//          archived_by,
+	//          refresh,
+	//          status,
+	//          video_id
+	//      )
+	//      SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+	//      FROM batch
+	//      RETURNING id, video_id
+	//  ),
+	//  new_ingest_jobs AS (
+	//      INSERT INTO ingest_jobs (
+	//          download_job_id,
+	//          status,
+	//          asset_scope
+	//      )
+	//      SELECT new_download_jobs.id, 'queued', 'captions'
+	//      FROM new_download_jobs
+	//      RETURNING download_job_id
+	//  )
+	//  SELECT new_download_jobs.video_id::uuid AS video_id
+	//  FROM new_ingest_jobs
+	//  JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+	//  ORDER BY new_download_jobs.video_id
+	EnqueueCaptionBackfillBatch(ctx context.Context, arg *EnqueueCaptionBackfillBatchParams) ([]pgtype.UUID, error)
+	// EnqueueChannelAssetRegenerationBatch creates download + ingest job pairs (the
+	// same shape as EnqueueAssetRegenerationJob) for the next batch of a channel's
+	// videos, keyset-ordered by id. Returns the video IDs queued; pass the last one
+	// as after_id for the next batch (zero UUID for the first).
+	//
+	//  WITH batch AS (
+	//      SELECT v.id, v.src, v.archived_by
+	//      FROM videos v
+	//      WHERE v.channel_id = $1
+	//        AND v.id > $2::uuid
+	//      ORDER BY v.id
+	//      LIMIT $3
+	//  ),
+	//  new_download_jobs AS (
+	//      INSERT INTO download_jobs (
+	//          url,
+	//          archived_by,
+	//          refresh,
+	//          status,
+	//          video_id
+	//      )
+	//      SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+	//      FROM batch
+	//      RETURNING id, video_id
+	//  ),
+	//  new_ingest_jobs AS (
+	//      INSERT INTO ingest_jobs (
+	//          download_job_id,
+	//          status,
+	//          asset_scope
+	//      )
+	//      SELECT new_download_jobs.id, 'queued', $4::text
+	//      FROM new_download_jobs
+	//      RETURNING download_job_id
+	//  )
+	//  SELECT new_download_jobs.video_id::uuid AS video_id
+	//  FROM new_ingest_jobs
+	//  JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+	//  ORDER BY new_download_jobs.video_id
+	EnqueueChannelAssetRegenerationBatch(ctx context.Context, arg *EnqueueChannelAssetRegenerationBatchParams) ([]pgtype.UUID, error)
 	// EnqueueChildDownloadJobs bulk-inserts one normal video download job per URL,
 	// all linked to a parent playlist job. Each insert fires the download_jobs
 	// NOTIFY trigger, so existing downloader workers pick them up unchanged.
 	//
-	//  INSERT INTO download_jobs (url, archived_by, status, kind, parent_job_id)
-	//  SELECT u, $1, 'queued', 'video', $2
-	//  FROM unnest($3::text[]) AS u
+	//  INSERT INTO download_jobs (url, archived_by, status, kind, parent_job_id, extra_args, metadata_only)
+	//  SELECT u, $1, 'queued', 'video', $2, $3::text[], $4
+	//  FROM unnest($5::text[]) AS u
 	EnqueueChildDownloadJobs(ctx context.Context, arg *EnqueueChildDownloadJobsParams) (int64, error)
-	// EnqueueDownloadJob inserts a new download job.
+	// EnqueueDownloadJob inserts a new download job. metadata_only jobs fetch
+	// just the info JSON and thumbnail, never the media file.
 	//
 	//  INSERT INTO download_jobs (
 	//      url,
 	//      archived_by,
 	//      status,
 	//      refresh,
-	//      extra_args
+	//      extra_args,
+	//      metadata_only
 	//  )
 	//  VALUES (
 	//      $1,
 	//      $2,
 	//      'queued',
 	//      $3,
-	//      $4
+	//      $4,
+	//      $5
 	//  )
-	//  RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+	//  RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 	EnqueueDownloadJob(ctx context.Context, arg *EnqueueDownloadJobParams) (*DownloadJob, error)
 	// EnqueueIngestJob inserts a new ingest job from a download job.
 	//
@@ -450,25 +724,92 @@ type Querier interface {
 	//      $1,
 	//      'queued'
 	//  )
-	//  RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+	//  RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 	EnqueueIngestJob(ctx context.Context, downloadJobID pgtype.UUID) (*IngestJob, error)
 	// EnqueuePlaylistJob inserts a parent "playlist" job. The downloader expands it
 	// into child video jobs (see EnqueueChildDownloadJobs) rather than downloading.
+	// extra_args are used for enumeration and copied to every child job, as is metadata_only.
 	//
 	//  INSERT INTO download_jobs (
 	//      url,
 	//      archived_by,
 	//      status,
-	//      kind
+	//      kind,
+	//      extra_args,
+	//      metadata_only
 	//  )
 	//  VALUES (
 	//      $1,
 	//      $2,
 	//      'queued',
-	//      'playlist'
+	//      'playlist',
+	//      $3::text[],
+	//      $4
 	//  )
-	//  RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+	//  RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 	EnqueuePlaylistJob(ctx context.Context, arg *EnqueuePlaylistJobParams) (*DownloadJob, error)
+	// EnqueuePreviewBackfillBatch creates preview-scoped regeneration job pairs
+	// for the next batch of videos missing a preview (assets_status.preview =
+	// false), keyset-ordered by id. Videos that already have a pending preview or
+	// full regeneration job are skipped. Jobs are spread out by setting
+	// next_retry_at, which ingest workers wait for: the nth job of the batch
+	// becomes claimable (first_slot + n - 1) * interval_seconds from now. Returns
+	// the video IDs queued; pass the last one as after_id for the next batch.
+	//
+	//  WITH batch AS (
+	//      SELECT
+	//          v.id,
+	//          v.src,
+	//          v.archived_by,
+	//          ROW_NUMBER() OVER (ORDER BY v.id) AS slot
+	//      FROM videos v
+	//      WHERE v.id > $1::uuid
+	//        AND v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+	//        AND v.assets_status @> '{"preview": false}'::jsonb
+	//        AND NOT EXISTS (
+	//            SELECT 1
+	//            FROM ingest_jobs ij
+	//            JOIN download_jobs dj ON dj.id = ij.download_job_id
+	//            WHERE dj.video_id = v.id
+	//              AND ij.status IN ('queued', 'processing')
+	//              AND (ij.asset_scope IS NULL OR ij.asset_scope = 'preview')
+	//        )
+	//      ORDER BY v.id
+	//      LIMIT $2
+	//  ),
+	//  new_download_jobs AS (
+	//      INSERT INTO download_jobs (
+	//          url,
+	//          archived_by,
+	//          refresh,
+	//          status,
+	//          video_id
+	//      )
+	//      SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+	//      FROM batch
+	//      RETURNING id, video_id
+	//  ),
+	//  new_ingest_jobs AS (
+	//      INSERT INTO ingest_jobs (
+	//          download_job_id,
+	//          status,
+	//          asset_scope,
+	//          next_retry_at
+	//      )
+	//      SELECT
+	//          new_download_jobs.id,
+	//          'queued',
+	//          'preview',
+	//          NOW() + ($3::int + batch.slot - 1) * $4::float8 * INTERVAL '1 second'
+	//      FROM new_download_jobs
+	//      JOIN batch ON batch.id = new_download_jobs.video_id
+	//      RETURNING download_job_id
+	//  )
+	//  SELECT new_download_jobs.video_id::uuid AS video_id
+	//  FROM new_ingest_jobs
+	//  JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+	//  ORDER BY new_download_jobs.video_id
+	EnqueuePreviewBackfillBatch(ctx context.Context, arg *EnqueuePreviewBackfillBatchParams) ([]pgtype.UUID, error)
 	// EnqueueUploadIngestJob creates a download + ingest job pair for a local file upload.
 	// The download_job is pre-marked as succeeded (no yt-dlp download needed).
 	//
@@ -491,7 +832,7 @@ type Querier interface {
 	//          $4,
 	//          NOW()
 	//      )
-	//      RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+	//      RETURNING id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 	//  ),
 	//  new_ingest_job AS (
 	//      INSERT INTO ingest_jobs (
@@ -500,32 +841,60 @@ type Querier interface {
 	//      )
 	//      SELECT new_download_job.id, 'queued'
 	//      FROM new_download_job
-	//      RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+	//      RETURNING id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 	//  )
 	//  SELECT
 	//      new_ingest_job.id AS ingest_job_id,
 	//      new_download_job.id AS download_job_id
 	//  FROM new_ingest_job, new_download_job
 	EnqueueUploadIngestJob(ctx context.Context, arg *EnqueueUploadIngestJobParams) (*EnqueueUploadIngestJobRow, error)
-	// FailExcessiveRetryIngestJobs permanently fails jobs that have been retried too many times.
+	// EnsureVideoHLSKey stores key for a video unless it already has one, and
+	// returns the stored key either way.
+	//
+	//  INSERT INTO video_hls_keys (video_id, key)
+	//  VALUES ($1, $2)
+	//  ON CONFLICT (video_id)
+	//  DO UPDATE SET key = video_hls_keys.key
+	//  RETURNING key
+	EnsureVideoHLSKey(ctx context.Context, arg *EnsureVideoHLSKeyParams) (crypto.EncryptedBytes, error)
+	// FailExcessiveRetryDownloadJobs permanently fails orphaned "processing"
+	// download jobs that have used up their max_attempts.
+	//
+	//  UPDATE download_jobs
+	//  SET status = 'failed',
+	//      last_error = 'exceeded maximum retry attempts',
+	//      next_retry_at = NULL,
+	//      finished_at = NOW(),
+	//      process_pid = NULL,
+	//      updated_at = NOW()
+	//  WHERE status = 'processing'
+	//    AND updated_at < NOW() - INTERVAL '5 minutes'
+	//    AND attempts >= $1::int
+	FailExcessiveRetryDownloadJobs(ctx context.Context, maxAttempts int32) (int64, error)
+	// FailExcessiveRetryIngestJobs permanently fails jobs that have used up their
+	// max_attempts, whether queued or orphaned in "processing".
 	// This prevents zombie jobs from wasting workers indefinitely.
 	//
 	//  UPDATE ingest_jobs
 	//  SET status = 'failed',
 	//      last_error = 'exceeded maximum retry attempts',
+	//      next_retry_at = NULL,
 	//      finished_at = NOW(),
 	//      updated_at = NOW()
-	//  WHERE status = 'queued'
-	//    AND attempts >= 5
-	FailExcessiveRetryIngestJobs(ctx context.Context) (int64, error)
+	//  WHERE (status = 'queued' OR (status = 'processing' AND updated_at < NOW() - INTERVAL '5 minutes'))
+	//    AND attempts >= $1::int
+	FailExcessiveRetryIngestJobs(ctx context.Context, maxAttempts int32) (int64, error)
 	// FilterExistingVideoIDs returns, from the given candidate ids, the subset that
 	// already exist as videos. Used to skip re-downloading already-archived videos
 	// when expanding a playlist/channel (video ids are deterministic UUIDv5s).
+	// Metadata-only videos count as archived only for a metadata-only expansion,
+	// so a full playlist archive downloads their files.
 	//
 	//  SELECT id
 	//  FROM videos
 	//  WHERE id = ANY($1::uuid[])
-	FilterExistingVideoIDs(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error)
+	//    AND ($2::bool OR NOT metadata_only)
+	FilterExistingVideoIDs(ctx context.Context, arg *FilterExistingVideoIDsParams) ([]pgtype.UUID, error)
 	// ============================================================================
 	// ENCODER WORKER QUERIES
 	// ============================================================================
@@ -543,6 +912,7 @@ type Querier interface {
 	//  WHERE id = (
 	//      SELECT id FROM clip_exports
 	//      WHERE status = 'queued'
+	//        AND ladder_parent_id IS NULL
 	//        AND (locked_at IS NULL OR locked_at < NOW() - INTERVAL '10 minutes')
 	//      ORDER BY created_at ASC
 	//      LIMIT 1
@@ -577,6 +947,16 @@ type Querier interface {
 	//    AND created_by = $2
 	//    AND format = $3
 	//    AND variant = $4
+	//    AND COALESCE((spec->>'loop')::int, 0) = $5::int
+	//    AND COALESCE((spec->>'gop')::int, 0) = $6::int
+	//    AND COALESCE((spec->>'target_bitrate')::int, 0) = $7::int
+	//    AND COALESCE((spec->>'fps')::int, 0) = $8::int
+	//    AND COALESCE((spec->>'max_width')::int, 0) = $9::int
+	//    AND COALESCE(spec->>'quality', '') = $10::text
+	//    AND COALESCE(spec->'filters', '[]'::jsonb) = $11::jsonb
+	//    AND COALESCE(spec->'pip', 'null'::jsonb) = $12::jsonb
+	//    AND COALESCE((spec->>'accurate')::boolean, false) = $13::boolean
+	//    AND COALESCE((spec->>'height')::int, 0) = 0
 	//    AND status IN ('queued', 'processing')
 	//    AND updated_at > NOW() - INTERVAL '5 minutes'
 	//  ORDER BY created_at DESC
@@ -599,7 +979,10 @@ type Querier interface {
 	//  ORDER BY created_at DESC
 	//  LIMIT 500
 	FindReadyExportsWithMissingFiles(ctx context.Context) ([]*FindReadyExportsWithMissingFilesRow, error)
-	//FindReusableClipExport
+	// FindReusableClipExport returns the user's newest ready export of the clip
+	// with an identical spec (format, variant, loop, GOP, quality, target bitrate,
+	// GIF/WebP frame rate and width, filters, PiP inset and seek mode).
+	// Ladder renditions are scaled, so they never stand in for a full-size export.
 	//
 	//  SELECT id, file_path
 	//  FROM clip_exports
@@ -607,11 +990,45 @@ type Querier interface {
 	//    AND clip_exports.created_by = $2
 	//    AND clip_exports.format = $3
 	//    AND clip_exports.variant = $4
+	//    AND COALESCE((clip_exports.spec->>'loop')::int, 0) = $5::int
+	//    AND COALESCE((clip_exports.spec->>'gop')::int, 0) = $6::int
+	//    AND COALESCE((clip_exports.spec->>'target_bitrate')::int, 0) = $7::int
+	//    AND COALESCE((clip_exports.spec->>'fps')::int, 0) = $8::int
+	//    AND COALESCE((clip_exports.spec->>'max_width')::int, 0) = $9::int
+	//    AND COALESCE(clip_exports.spec->>'quality', '') = $10::text
+	//    AND COALESCE(clip_exports.spec->'filters', '[]'::jsonb) = $11::jsonb
+	//    AND COALESCE(clip_exports.spec->'pip', 'null'::jsonb) = $12::jsonb
+	//    AND COALESCE((clip_exports.spec->>'accurate')::boolean, false) = $13::boolean
+	//    AND COALESCE((clip_exports.spec->>'height')::int, 0) = 0
 	//    AND clip_exports.status = 'ready'
 	//    AND clip_exports.clip_updated_at >= (SELECT clips.updated_at FROM clips WHERE clips.id = $1)
 	//  ORDER BY clip_exports.created_at DESC
 	//  LIMIT 1
 	FindReusableClipExport(ctx context.Context, arg *FindReusableClipExportParams) (*FindReusableClipExportRow, error)
+	// FindSimilarVideosByPHash returns other videos within max_distance bits of
+	// video_id, closest first. Whole-video hashes are compared when both videos
+	// have one, thumbnail hashes otherwise; the same duration guard as
+	// ListNearDuplicateVideos applies.
+	//
+	//  SELECT b.id, b.created_at, b.updated_at, b.src, b.archived_by, b.title, b.info, b.comments, b.video_path, b.thumbnail_path, b.description, b.tags, b.uploader, b.uploader_id, b.channel_id, b.upload_date, b.duration_seconds, b.view_count, b.like_count, b.thumb_gradient_start, b.thumb_gradient_end, b.thumb_gradient_angle, b.file_hash, b.file_size, b.assets_status, b.search, b.probe_data, b.comments_checked_at, b.phash, b.phash_frames, b.metadata_only, b.auto_tagged_at, b.thumbnail_phash
+	//  FROM videos a
+	//  JOIN videos b ON b.id <> a.id
+	//  CROSS JOIN LATERAL (
+	//      SELECT CASE
+	//          WHEN a.phash IS NOT NULL AND b.phash IS NOT NULL
+	//              THEN bit_count((a.phash # b.phash)::bit(64))
+	//          ELSE bit_count((a.thumbnail_phash # b.thumbnail_phash)::bit(64))
+	//      END AS distance
+	//  ) d
+	//  WHERE a.id = $1
+	//    AND d.distance <= $2::int
+	//    AND (
+	//      a.duration_seconds IS NULL OR b.duration_seconds IS NULL
+	//      OR abs(a.duration_seconds - b.duration_seconds) <= GREATEST(2, a.duration_seconds * 0.02)
+	//    )
+	//  ORDER BY d.distance ASC, b.created_at DESC
+	//  LIMIT $3
+	FindSimilarVideosByPHash(ctx context.Context, arg *FindSimilarVideosByPHashParams) ([]*Video, error)
 	// Mark export as failed with error message
 	//
 	//  UPDATE clip_exports
@@ -639,6 +1056,19 @@ type Querier interface {
 	//      updated_at = NOW()
 	//  WHERE id = $3
 	FinishClipExportReady(ctx context.Context, arg *FinishClipExportReadyParams) error
+	// Fail a ladder lead's in-flight rungs along with the lead
+	//
+	//  UPDATE clip_exports
+	//  SET status = 'error',
+	//      last_error = $1,
+	//      finished_at = NOW(),
+	//      locked_at = NULL,
+	//      locked_by = NULL,
+	//      pid = NULL,
+	//      updated_at = NOW()
+	//  WHERE ladder_parent_id = $2
+	//    AND status = 'processing'
+	FinishClipExportRungsError(ctx context.Context, arg *FinishClipExportRungsErrorParams) error
 	//FinishStitchJobError
 	//
 	//  UPDATE stitch_jobs
@@ -686,6 +1116,51 @@ type Querier interface {
 	//  ORDER BY created_at DESC
 	//  LIMIT 1
 	GetActiveSessionByProducer(ctx context.Context, producerID pgtype.UUID) (*PlayerSession, error)
+	// GetCaptionBackfillProgress counts "captions" regeneration jobs created at or
+	// after since, grouped by status.
+	//
+	//  SELECT
+	//      COUNT(*) AS total_count,
+	//      COUNT(*) FILTER (WHERE ij.status = 'queued') AS queued_count,
+	//      COUNT(*) FILTER (WHERE ij.status = 'processing') AS processing_count,
+	//      COUNT(*) FILTER (WHERE ij.status = 'succeeded') AS succeeded_count,
+	//      COUNT(*) FILTER (WHERE ij.status = 'failed') AS failed_count
+	//  FROM ingest_jobs ij
+	//  JOIN download_jobs dj ON dj.id = ij.download_job_id
+	//  WHERE ij.asset_scope = 'captions'
+	//    AND dj.refresh = true
+	//    AND dj.spool_dir IS NULL
+	//    AND ij.created_at >= $1
+	GetCaptionBackfillProgress(ctx context.Context, since pgtype.Timestamptz) (*GetCaptionBackfillProgressRow, error)
+	// GetCaptionCoverage counts videos with and without any transcript,
+	// optionally limited to one channel.
+	//
+	//  SELECT
+	//      COUNT(*) AS total_count,
+	//      COUNT(*) FILTER (WHERE EXISTS (
+	//          SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id
+	//      )) AS captioned_count
+	//  FROM videos v
+	//  WHERE $1::text IS NULL OR v.channel_id = $1
+	GetCaptionCoverage(ctx context.Context, channelID *string) (*GetCaptionCoverageRow, error)
+	// GetChannelAssetRegenerationProgress counts asset regeneration ingest jobs for
+	// a channel's videos created at or after since, grouped by status.
+	// Regeneration jobs are refresh download jobs that never had a spool dir.
+	//
+	//  SELECT
+	//      COUNT(*) AS total_count,
+	//      COUNT(*) FILTER (WHERE ij.status = 'queued') AS queued_count,
+	//      COUNT(*) FILTER (WHERE ij.status = 'processing') AS processing_count,
+	//      COUNT(*) FILTER (WHERE ij.status = 'succeeded') AS succeeded_count,
+	//      COUNT(*) FILTER (WHERE ij.status = 'failed') AS failed_count
+	//  FROM ingest_jobs ij
+	//  JOIN download_jobs dj ON dj.id = ij.download_job_id
+	//  JOIN videos v ON v.id = dj.video_id
+	//  WHERE v.channel_id = $1
+	//    AND dj.refresh = true
+	//    AND dj.spool_dir IS NULL
+	//    AND ij.created_at >= $2
+	GetChannelAssetRegenerationProgress(ctx context.Context, arg *GetChannelAssetRegenerationProgressParams) (*GetChannelAssetRegenerationProgressRow, error)
 	//GetClip
 	//
 	//  SELECT id, video_id, start_ts, end_ts, duration, created_at, updated_at, created_by, title, description, color, tags, crops, filter_stack, shot_list FROM clips
@@ -701,11 +1176,19 @@ type Querier interface {
 	//
 	//  SELECT ce.file_path, ce.format, ce.status, ce.clip_id, ce.variant,
 	//         COALESCE(c.title, '') AS clip_title,
-	//         c.crops
+	//         c.crops,
+	//         v.title AS video_title
 	//  FROM clip_exports ce
 	//  JOIN clips c ON c.id = ce.clip_id
+	//  JOIN videos v ON v.id = c.video_id
 	//  WHERE ce.id = $1
 	GetClipExportForDownload(ctx context.Context, id pgtype.UUID) (*GetClipExportForDownloadRow, error)
+	//GetClipExportProgressHistory
+	//
+	//  SELECT id, status, started_at, finished_at, progress_history
+	//  FROM clip_exports
+	//  WHERE id = $1
+	GetClipExportProgressHistory(ctx context.Context, id pgtype.UUID) (*GetClipExportProgressHistoryRow, error)
 	// Get export statistics for admin dashboard
 	//
 	//  SELECT
@@ -726,6 +1209,21 @@ type Querier interface {
 	//
 	//  SELECT COALESCE(clip_export_storage_limit_bytes, 0) FROM instance_settings WHERE id = 1
 	GetClipExportStorageLimit(ctx context.Context) (int64, error)
+	// Fleet-wide encoder throughput for the admin exports dashboard: exports
+	// finished in the last hour, mean encode time over the last 24 hours, the
+	// current backlog and how many encoder hosts are busy.
+	//
+	//  SELECT
+	//      COUNT(*) FILTER (WHERE status = 'ready' AND finished_at > NOW() - INTERVAL '1 hour')::bigint AS completed_last_hour,
+	//      COUNT(*) FILTER (WHERE status = 'error' AND finished_at > NOW() - INTERVAL '1 hour')::bigint AS failed_last_hour,
+	//      COALESCE(AVG(EXTRACT(EPOCH FROM finished_at - started_at)) FILTER (
+	//          WHERE status = 'ready' AND started_at IS NOT NULL AND finished_at > NOW() - INTERVAL '24 hours'
+	//      ), 0)::float8 AS avg_encode_seconds,
+	//      COUNT(*) FILTER (WHERE status = 'queued')::bigint AS queued_count,
+	//      COUNT(*) FILTER (WHERE status = 'processing')::bigint AS processing_count,
+	//      COUNT(DISTINCT locked_by) FILTER (WHERE status = 'processing')::bigint AS active_workers
+	//  FROM clip_exports
+	GetClipExportThroughput(ctx context.Context) (*GetClipExportThroughputRow, error)
 	// Get clip data needed for encoding
 	//
 	//  SELECT c.id, c.video_id, c.start_ts, c.end_ts, c.duration, c.crops, c.filter_stack,
@@ -757,7 +1255,7 @@ type Querier interface {
 	GetDashboardOverview(ctx context.Context) (*GetDashboardOverviewRow, error)
 	// GetDownloadJobByID returns a download job by ID
 	//
-	//  SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+	//  SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 	//  FROM download_jobs
 	//  WHERE id = $1
 	GetDownloadJobByID(ctx context.Context, id pgtype.UUID) (*DownloadJob, error)
@@ -767,6 +1265,18 @@ type Querier interface {
 	//  FROM download_jobs
 	//  WHERE id = $1
 	GetDownloadJobPID(ctx context.Context, id pgtype.UUID) (*int64, error)
+	// GetDownloadJobQueuePosition returns the 1-based position of a queued job in
+	// dequeue order (priority DESC, created_at).
+	//
+	//  SELECT (COUNT(q.id) + 1)::bigint AS position
+	//  FROM download_jobs t
+	//  LEFT JOIN download_jobs q
+	//      ON q.status = 'queued'
+	//     AND q.id <> t.id
+	//     AND (q.priority > t.priority OR (q.priority = t.priority AND q.created_at < t.created_at))
+	//  WHERE t.id = $1
+	//  GROUP BY t.id
+	GetDownloadJobQueuePosition(ctx context.Context, id pgtype.UUID) (int64, error)
 	//GetExtensionTokenByToken
 	//
 	//  SELECT id, user_id, token, created_at, last_used_at, expires_at, revoked FROM extension_tokens
@@ -783,7 +1293,7 @@ type Querier interface {
 	GetHomeStats(ctx context.Context) (*GetHomeStatsRow, error)
 	// GetInstanceSettings fetches the single instance settings row
 	//
-	//  SELECT id, registration_enabled, clip_export_storage_limit_bytes, admin_emails, updated_at FROM instance_settings WHERE id = 1
+	//  SELECT id, registration_enabled, clip_export_storage_limit_bytes, admin_emails, updated_at, registration_requires_approval, download_retry_max_attempts, download_retry_backoff, download_retry_base_seconds, download_retry_cap_seconds, ingest_retry_max_attempts, ingest_retry_backoff, ingest_retry_base_seconds, ingest_retry_cap_seconds, user_download_max_height, admin_download_max_height, playback_max_height FROM instance_settings WHERE id = 1
 	GetInstanceSettings(ctx context.Context) (*InstanceSetting, error)
 	// GetJobStatusCounts returns download and ingest job counts grouped by status.
 	//
@@ -804,12 +1314,12 @@ type Querier interface {
 	GetJobStatusCounts(ctx context.Context) ([]*GetJobStatusCountsRow, error)
 	//GetMarker
 	//
-	//  SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by FROM markers
+	//  SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source FROM markers
 	//  WHERE id = $1
 	GetMarker(ctx context.Context, id pgtype.UUID) (*Marker, error)
 	// GetPlaybackPosition retrieves the last playback position for a user/video
 	//
-	//  SELECT position_seconds, updated_at
+	//  SELECT position_seconds, updated_at, watched_at
 	//  FROM playback_positions
 	//  WHERE user_id = $1
 	//    AND video_id = $2
@@ -889,6 +1399,18 @@ type Querier interface {
 	//
 	//  SELECT COALESCE(SUM(size_bytes), 0)::bigint FROM clip_exports WHERE status = 'ready'
 	GetTotalClipExportSize(ctx context.Context) (int64, error)
+	//GetUserCaptionStyle
+	//
+	//  SELECT caption_font_size, caption_color, caption_background, caption_position
+	//  FROM user_preferences
+	//  WHERE user_id = $1
+	GetUserCaptionStyle(ctx context.Context, userID pgtype.UUID) (*GetUserCaptionStyleRow, error)
+	//GetUserCookieDomainFilter
+	//
+	//  SELECT cookie_domain_allowlist, cookie_domain_blocklist
+	//  FROM user_preferences
+	//  WHERE user_id = $1
+	GetUserCookieDomainFilter(ctx context.Context, userID pgtype.UUID) (*GetUserCookieDomainFilterRow, error)
 	// GetUserCookies returns all cookies for a user in Netscape format
 	//
 	//  SELECT domain, flag, path, secure, expiration, name, value
@@ -896,6 +1418,23 @@ type Querier interface {
 	//  WHERE user_id = $1
 	//  ORDER BY domain, name, path
 	GetUserCookies(ctx context.Context, userID pgtype.UUID) ([]*GetUserCookiesRow, error)
+	// GetUserDownloadCap returns a user's role and download cap override, with the
+	// role caps from instance settings (0 when there is no settings row).
+	//
+	//  SELECT u.role,
+	//         u.download_max_height,
+	//         COALESCE(s.user_download_max_height, 0)::int AS user_max_height,
+	//         COALESCE(s.admin_download_max_height, 0)::int AS admin_max_height
+	//  FROM users u
+	//  LEFT JOIN instance_settings s ON s.id = 1
+	//  WHERE u.id = $1
+	GetUserDownloadCap(ctx context.Context, id pgtype.UUID) (*GetUserDownloadCapRow, error)
+	//GetUserExportFilenameTemplate
+	//
+	//  SELECT export_filename_template
+	//  FROM user_preferences
+	//  WHERE user_id = $1
+	GetUserExportFilenameTemplate(ctx context.Context, userID pgtype.UUID) (string, error)
 	//GetUserKeybindings
 	//
 	//  SELECT action, key
@@ -904,10 +1443,38 @@ type Querier interface {
 	GetUserKeybindings(ctx context.Context, userID pgtype.UUID) ([]*GetUserKeybindingsRow, error)
 	// GetVideoByID returns a video by ID
 	//
-	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 	//  FROM videos
 	//  WHERE id = $1
 	GetVideoByID(ctx context.Context, id pgtype.UUID) (*Video, error)
+	// GetVideoHLSKey returns the key of a video's encrypted HLS rendition. No row
+	// means none was issued.
+	//
+	//  SELECT key
+	//  FROM video_hls_keys
+	//  WHERE video_id = $1
+	GetVideoHLSKey(ctx context.Context, videoID pgtype.UUID) (crypto.EncryptedBytes, error)
+	// GetVideoNote returns the user's note on a video. No row means no note.
+	//
+	//  SELECT body, updated_at
+	//  FROM video_notes
+	//  WHERE user_id = $1
+	//    AND video_id = $2
+	GetVideoNote(ctx context.Context, arg *GetVideoNoteParams) (*GetVideoNoteRow, error)
+	// GetVideoPlaybackState returns a video's duration with the user's saved
+	// position and watched state, if any. No row means the video does not exist.
+	//
+	//  SELECT
+	//      v.duration_seconds,
+	//      pp.position_seconds,
+	//      pp.updated_at,
+	//      pp.watched_at
+	//  FROM videos v
+	//  LEFT JOIN playback_positions pp
+	//      ON pp.video_id = v.id
+	//     AND pp.user_id = $1
+	//  WHERE v.id = $2
+	GetVideoPlaybackState(ctx context.Context, arg *GetVideoPlaybackStateParams) (*GetVideoPlaybackStateRow, error)
 	// GetVideoWithDownloadJob gets a video with its download job info for playback
 	//
 	//  SELECT
@@ -948,13 +1515,16 @@ type Querier interface {
 	//  WHERE job_id = $1
 	//  ORDER BY created_at ASC, id ASC
 	GetYtdlpLogsForJob(ctx context.Context, jobID pgtype.UUID) ([]*YtdlpLog, error)
-	//GetYtdlpLogsForJobPaginated
+	// Newest-first page of a job's log lines, optionally limited to one stream
+	// and/or lines containing a case-insensitive substring.
 	//
 	//  SELECT id, job_id, stream, message, created_at
 	//  FROM ytdlp_logs
 	//  WHERE job_id = $1
+	//    AND ($2::text IS NULL OR stream::text = $2::text)
+	//    AND ($3::text IS NULL OR strpos(lower(message), lower($3::text)) > 0)
 	//  ORDER BY created_at DESC, id DESC
-	//  LIMIT $3 OFFSET $2
+	//  LIMIT $5 OFFSET $4
 	GetYtdlpLogsForJobPaginated(ctx context.Context, arg *GetYtdlpLogsForJobPaginatedParams) ([]*YtdlpLog, error)
 	//GetYtdlpLogsForJobSince
 	//
@@ -1078,8 +1648,34 @@ type Querier interface {
 	//      file_size = EXCLUDED.file_size,
 	//      probe_data = COALESCE(EXCLUDED.probe_data, videos.probe_data),
 	//      search = EXCLUDED.search
-	//  RETURNING id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+	//  RETURNING id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 	InsertVideo(ctx context.Context, arg *InsertVideoParams) (*Video, error)
+	// InsertVideoChapters stores a video's chapters; the arrays are parallel,
+	// one element per chapter.
+	//
+	//  INSERT INTO video_chapters (video_id, position, start_seconds, end_seconds, title, source)
+	//  SELECT $1, c.position, c.start_seconds, c.end_seconds, c.title, $2
+	//  FROM unnest(
+	//      $3::int[],
+	//      $4::double precision[],
+	//      $5::double precision[],
+	//      $6::text[]
+	//  ) AS c(position, start_seconds, end_seconds, title)
+	InsertVideoChapters(ctx context.Context, arg *InsertVideoChaptersParams) error
+	// InsertVideoPHashPairs stores the pairs of a video with every other hashed
+	// video within max_distance bits, for ListNearDuplicateVideos. Comparing one
+	// hash with the rest is a single scan, done as each hash is stored.
+	//
+	//  INSERT INTO video_phash_pairs (video_id, other_id, distance)
+	//  SELECT LEAST(a.id, b.id), GREATEST(a.id, b.id), bit_count((a.phash # b.phash)::bit(64))
+	//  FROM videos a
+	//  JOIN videos b ON b.id <> a.id
+	//  WHERE a.id = $1
+	//    AND a.phash IS NOT NULL
+	//    AND b.phash IS NOT NULL
+	//    AND bit_count((a.phash # b.phash)::bit(64)) <= $2::int
+	//  ON CONFLICT (video_id, other_id) DO UPDATE SET distance = EXCLUDED.distance
+	InsertVideoPHashPairs(ctx context.Context, arg *InsertVideoPHashPairsParams) error
 	// InsertVideoRevision stores a refresh diff.
 	//
 	//  INSERT INTO video_revisions (
@@ -1105,6 +1701,19 @@ type Querier interface {
 	//      $9
 	//  )
 	InsertVideoRevision(ctx context.Context, arg *InsertVideoRevisionParams) error
+	// InsertVideoTranscriptWords stores word timings; the arrays are parallel,
+	// one element per word.
+	//
+	//  INSERT INTO video_transcript_words (video_id, lang, cue_index, word_index, start_offset, end_offset, word)
+	//  SELECT $1, $2::language_tag, w.cue_index, w.word_index, w.start_offset, w.end_offset, w.word
+	//  FROM unnest(
+	//      $3::int[],
+	//      $4::int[],
+	//      $5::double precision[],
+	//      $6::double precision[],
+	//      $7::text[]
+	//  ) AS w(cue_index, word_index, start_offset, end_offset, word)
+	InsertVideoTranscriptWords(ctx context.Context, arg *InsertVideoTranscriptWordsParams) error
 	//InsertYtdlpLog
 	//
 	//  INSERT INTO ytdlp_logs (job_id, stream, message)
@@ -1156,13 +1765,81 @@ type Querier interface {
 	ListAllTagsWithCounts(ctx context.Context) ([]*ListAllTagsWithCountsRow, error)
 	// ListAllUsers lists all users in the database
 	//
-	//  SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE deleted_at IS NULL
+	//  SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE deleted_at IS NULL
 	ListAllUsers(ctx context.Context) ([]*User, error)
+	// ListCaptionAuditVideos lists videos with their transcript languages.
+	// presence is 'missing' (no transcripts), 'present' (at least one) or NULL
+	// (all); lang keeps only videos with a transcript in that language.
+	//
+	//  SELECT
+	//      v.id,
+	//      v.title,
+	//      v.uploader,
+	//      v.channel_id,
+	//      v.created_at,
+	//      COALESCE(array_agg(t.lang::text ORDER BY t.lang) FILTER (WHERE t.lang IS NOT NULL), '{}')::text[] AS langs
+	//  FROM videos v
+	//  LEFT JOIN video_transcripts t ON t.video_id = v.id
+	//  WHERE $1::text IS NULL OR v.channel_id = $1
+	//  GROUP BY v.id
+	//  HAVING (
+	//          $2::text IS NULL
+	//          OR ($2 = 'missing' AND COUNT(t.id) = 0)
+	//          OR ($2 = 'present' AND COUNT(t.id) > 0)
+	//      )
+	//      AND ($3::text IS NULL OR COALESCE(bool_or(t.lang::text = $3), false))
+	//  ORDER BY v.created_at DESC
+	//  LIMIT $4
+	ListCaptionAuditVideos(ctx context.Context, arg *ListCaptionAuditVideosParams) ([]*ListCaptionAuditVideosRow, error)
+	// ListCaptionChannels returns channels with their video and caption-less
+	// counts, most caption-less first, for the caption audit filter.
+	//
+	//  SELECT
+	//      v.channel_id::text AS channel_id,
+	//      MAX(v.uploader)::text AS uploader,
+	//      COUNT(*) AS total_count,
+	//      COUNT(*) FILTER (WHERE NOT EXISTS (
+	//          SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id
+	//      )) AS missing_count
+	//  FROM videos v
+	//  WHERE v.channel_id IS NOT NULL AND v.channel_id <> ''
+	//  GROUP BY v.channel_id
+	//  ORDER BY missing_count DESC, total_count DESC
+	//  LIMIT 200
+	ListCaptionChannels(ctx context.Context) ([]*ListCaptionChannelsRow, error)
+	// ListCaptionLanguageCounts returns how many videos have a transcript in each
+	// language, optionally limited to one channel.
+	//
+	//  SELECT t.lang::text AS lang, COUNT(DISTINCT t.video_id) AS video_count
+	//  FROM video_transcripts t
+	//  JOIN videos v ON v.id = t.video_id
+	//  WHERE $1::text IS NULL OR v.channel_id = $1
+	//  GROUP BY t.lang
+	//  ORDER BY video_count DESC, lang
+	ListCaptionLanguageCounts(ctx context.Context, channelID *string) ([]*ListCaptionLanguageCountsRow, error)
+	// ListChannelPlaylistVideos returns a channel's downloaded videos in upload
+	// order, oldest first, for continuous playback.
+	//
+	//  SELECT id
+	//  FROM videos
+	//  WHERE channel_id = $1
+	//    AND NOT metadata_only
+	//    AND video_path IS NOT NULL
+	//  ORDER BY upload_date ASC NULLS LAST, created_at, id
+	//  LIMIT $2
+	ListChannelPlaylistVideos(ctx context.Context, arg *ListChannelPlaylistVideosParams) ([]pgtype.UUID, error)
 	// Get file paths for exports by status (for cleanup before delete)
 	//
 	//  SELECT id, file_path FROM clip_exports
 	//  WHERE status = $1 AND file_path != ''
 	ListClipExportFilesByStatus(ctx context.Context, status ExportStatus) ([]*ListClipExportFilesByStatusRow, error)
+	// ListClipExportRungs returns the rungs of a rendition ladder, tallest first.
+	//
+	//  SELECT id, status, COALESCE((spec->>'height')::int, 0)::int AS height
+	//  FROM clip_exports
+	//  WHERE ladder_parent_id = $1
+	//  ORDER BY height DESC
+	ListClipExportRungs(ctx context.Context, ladderParentID pgtype.UUID) ([]*ListClipExportRungsRow, error)
 	// List exports with clip/video info for admin management
 	//
 	//  SELECT
@@ -1211,7 +1888,7 @@ type Querier interface {
 	ListDistinctUploaders(ctx context.Context) ([]string, error)
 	// ListDownloadJobsByUser returns all download jobs for a user
 	//
-	//  SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+	//  SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 	//  FROM download_jobs
 	//  WHERE archived_by = $1
 	//    AND archived = FALSE
@@ -1221,25 +1898,69 @@ type Querier interface {
 	// ListDownloadJobsByVideoID returns all download jobs for a video.
 	// Matches by video_id FK or by URL matching the video's src column.
 	//
-	//  SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+	//  SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 	//  FROM download_jobs
 	//  WHERE video_id = $1
 	//     OR url = $2
 	//  ORDER BY created_at DESC
 	ListDownloadJobsByVideoID(ctx context.Context, arg *ListDownloadJobsByVideoIDParams) ([]*DownloadJob, error)
+	// Exports currently being encoded, oldest first, for the throughput dashboard
+	//
+	//  SELECT
+	//      ce.id,
+	//      ce.format,
+	//      ce.progress_pct,
+	//      ce.started_at,
+	//      ce.locked_by,
+	//      c.title AS clip_label,
+	//      v.title AS video_title
+	//  FROM clip_exports ce
+	//  JOIN clips c ON c.id = ce.clip_id
+	//  JOIN videos v ON v.id = c.video_id
+	//  WHERE ce.status = 'processing'
+	//  ORDER BY ce.started_at ASC NULLS LAST
+	//  LIMIT $1
+	ListInFlightClipExports(ctx context.Context, lim int32) ([]*ListInFlightClipExportsRow, error)
 	// ListIngestJobsByDownloadJobIDs returns ingest jobs for a set of download job IDs.
 	//
-	//  SELECT id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+	//  SELECT id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 	//  FROM ingest_jobs
 	//  WHERE download_job_id = ANY($1::uuid[])
 	//  ORDER BY created_at DESC
 	ListIngestJobsByDownloadJobIDs(ctx context.Context, downloadJobIds []pgtype.UUID) ([]*IngestJob, error)
 	//ListMarkersByVideo
 	//
-	//  SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by FROM markers
+	//  SELECT id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source FROM markers
 	//  WHERE video_id = $1
 	//  ORDER BY timestamp ASC
 	ListMarkersByVideo(ctx context.Context, videoID pgtype.UUID) ([]*Marker, error)
+	// ListNearDuplicateVideos returns pairs of videos whose combined perceptual
+	// hashes are within max_distance bits, skipping pairs whose durations differ
+	// by more than 2s or 2% (a re-encode keeps its length). It reads the pairs
+	// InsertVideoPHashPairs stored, so max_distance above the distance they were
+	// stored with finds nothing more.
+	//
+	//  SELECT
+	//      a.id AS video_id,
+	//      a.title AS video_title,
+	//      a.file_hash AS video_file_hash,
+	//      a.phash_frames::bigint[] AS video_frames,
+	//      b.id AS other_id,
+	//      b.title AS other_title,
+	//      b.file_hash AS other_file_hash,
+	//      b.phash_frames::bigint[] AS other_frames,
+	//      p.distance::int AS distance
+	//  FROM video_phash_pairs p
+	//  JOIN videos a ON a.id = p.video_id
+	//  JOIN videos b ON b.id = p.other_id
+	//  WHERE p.distance <= $1::int
+	//    AND (
+	//      a.duration_seconds IS NULL OR b.duration_seconds IS NULL
+	//      OR abs(a.duration_seconds - b.duration_seconds) <= GREATEST(2, a.duration_seconds * 0.02)
+	//    )
+	//  ORDER BY p.distance ASC, p.video_id, p.other_id
+	//  LIMIT $2
+	ListNearDuplicateVideos(ctx context.Context, arg *ListNearDuplicateVideosParams) ([]*ListNearDuplicateVideosRow, error)
 	//ListOldestClipExportsForCleanup
 	//
 	//  SELECT id, file_path, size_bytes FROM clip_exports
@@ -1252,6 +1973,26 @@ type Querier interface {
 	//  WHERE producer_id = $1
 	//  ORDER BY updated_at DESC
 	ListPlayerScenePresetsByProducer(ctx context.Context, producerID pgtype.UUID) ([]*PlayerScenePreset, error)
+	// ListPlaylistJobVideos returns the downloaded videos of a playlist download
+	// job's children, in the order the playlist was expanded.
+	//
+	//  SELECT v.id
+	//  FROM download_jobs j
+	//  JOIN videos v ON v.id = j.video_id
+	//  WHERE j.parent_job_id = $1
+	//    AND NOT v.metadata_only
+	//    AND v.video_path IS NOT NULL
+	//  ORDER BY j.created_at, j.id
+	//  LIMIT $2
+	ListPlaylistJobVideos(ctx context.Context, arg *ListPlaylistJobVideosParams) ([]pgtype.UUID, error)
+	// ListProcessingDownloadJobsForUser returns a user's running download jobs
+	// with their process IDs, so they can be signalled before being cancelled.
+	//
+	//  SELECT id, process_pid
+	//  FROM download_jobs
+	//  WHERE archived_by = $1
+	//    AND status = 'processing'
+	ListProcessingDownloadJobsForUser(ctx context.Context, archivedBy pgtype.UUID) ([]*ListProcessingDownloadJobsForUserRow, error)
 	// ListRecentClips returns recently created clips with their source video title
 	//
 	//  SELECT
@@ -1271,7 +2012,7 @@ type Querier interface {
 	ListRecentClips(ctx context.Context) ([]*ListRecentClipsRow, error)
 	// ListRecentDownloadJobs returns recent download jobs for all users
 	//
-	//  SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+	//  SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 	//  FROM download_jobs
 	//  WHERE archived = FALSE
 	//  ORDER BY created_at DESC
@@ -1279,19 +2020,45 @@ type Querier interface {
 	ListRecentDownloadJobs(ctx context.Context) ([]*DownloadJob, error)
 	// ListRecentVideos returns recent videos (by archive date)
 	//
-	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 	//  FROM videos
 	//  ORDER BY created_at DESC
 	//  LIMIT 15
 	ListRecentVideos(ctx context.Context) ([]*Video, error)
 	// ListRecentlyPublishedVideos returns videos sorted by original publish date
 	//
-	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 	//  FROM videos
 	//  WHERE upload_date IS NOT NULL
 	//  ORDER BY upload_date DESC
 	//  LIMIT 15
 	ListRecentlyPublishedVideos(ctx context.Context) ([]*Video, error)
+	// ListRelatedVideos returns other videos from the same channel (when
+	// channel_id is given) or otherwise the same uploader, newest first.
+	//
+	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
+	//  FROM videos
+	//  WHERE id <> $1
+	//    AND (
+	//      ($2::text IS NOT NULL AND channel_id = $2)
+	//      OR ($2::text IS NULL AND uploader = $3)
+	//    )
+	//  ORDER BY upload_date DESC NULLS LAST, created_at DESC
+	//  LIMIT $4
+	ListRelatedVideos(ctx context.Context, arg *ListRelatedVideosParams) ([]*Video, error)
+	// ListScrapedTagCounts returns each distinct scraped (yt-dlp) tag with how
+	// many videos carry it, most-used first. Tags are matched exactly as stored,
+	// the same way the library tag filter matches them.
+	//
+	//  SELECT t.tag::text AS tag, COUNT(DISTINCT v.id)::bigint AS video_count
+	//  FROM videos v
+	//  CROSS JOIN LATERAL unnest(v.tags) AS t(tag)
+	//  WHERE btrim(t.tag) <> ''
+	//  GROUP BY t.tag
+	//  HAVING COUNT(DISTINCT v.id) >= $1::bigint
+	//  ORDER BY video_count DESC, tag ASC
+	//  LIMIT $2
+	ListScrapedTagCounts(ctx context.Context, arg *ListScrapedTagCountsParams) ([]*ListScrapedTagCountsRow, error)
 	//ListSessionsByProducer
 	//
 	//  SELECT id, session_code, producer_id, current_video_id, state, created_at, expires_at, last_activity FROM player_sessions
@@ -1317,14 +2084,55 @@ type Querier interface {
 	//  WHERE created_by = $1
 	//  ORDER BY updated_at DESC
 	ListStitchProjects(ctx context.Context, userID pgtype.UUID) ([]*ListStitchProjectsRow, error)
-	// ListTagsForVideo returns a video's tags, alphabetically.
+	// ListTagsForVideo returns a video's tags, alphabetically. auto is set for
+	// tags suggested from the transcript.
 	//
-	//  SELECT t.id, t.name, t.slug, t.color
+	//  SELECT t.id, t.name, t.slug, t.color, vt.auto
 	//  FROM tags t
 	//  JOIN video_tags vt ON vt.tag_id = t.id
 	//  WHERE vt.video_id = $1
 	//  ORDER BY t.name ASC
 	ListTagsForVideo(ctx context.Context, videoID pgtype.UUID) ([]*ListTagsForVideoRow, error)
+	// ListTranscriptsForExport pages through transcripts joined with their video
+	// metadata, keyset-ordered by (video_id, lang) so bulk exports can stream
+	// without OFFSET scans. Pass the last row's (video_id, lang) as the cursor;
+	// use the zero UUID and '' for the first page.
+	//
+	//  SELECT
+	//      vt.video_id,
+	//      vt.lang::text AS lang,
+	//      vt.format,
+	//      vt.text,
+	//      vt.raw,
+	//      v.title,
+	//      v.uploader,
+	//      v.channel_id,
+	//      v.upload_date,
+	//      v.created_at AS archived_at
+	//  FROM video_transcripts vt
+	//  JOIN videos v ON v.id = vt.video_id
+	//  WHERE (vt.video_id, vt.lang::text) > ($1::uuid, $2::text)
+	//      AND ($3::text IS NULL OR v.channel_id = $3)
+	//      AND (
+	//          $4::date IS NULL
+	//          OR ($5::text = 'published' AND v.upload_date >= $4)
+	//          OR ($5::text IS DISTINCT FROM 'published' AND v.created_at::date >= $4)
+	//      )
+	//      AND (
+	//          $6::date IS NULL
+	//          OR ($5::text = 'published' AND v.upload_date <= $6)
+	//          OR ($5::text IS DISTINCT FROM 'published' AND v.created_at::date <= $6)
+	//      )
+	//  ORDER BY vt.video_id, vt.lang::text
+	//  LIMIT $7
+	ListTranscriptsForExport(ctx context.Context, arg *ListTranscriptsForExportParams) ([]*ListTranscriptsForExportRow, error)
+	// ListVideoChapters returns a video's stored chapters in playback order.
+	//
+	//  SELECT video_id, position, start_seconds, end_seconds, title, source, created_at
+	//  FROM video_chapters
+	//  WHERE video_id = $1
+	//  ORDER BY position
+	ListVideoChapters(ctx context.Context, videoID pgtype.UUID) ([]*VideoChapter, error)
 	// ListVideoCommentReplies returns replies (children) for a given parent comment.
 	// Carries the same display extras as ListVideoComments so replies render with
 	// the same CommentRow component.
@@ -1336,7 +2144,9 @@ type Querier interface {
 	//         COALESCE((c.raw->>'is_pinned')::boolean, false)::boolean         AS is_pinned,
 	//         COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
 	//         COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
-	//         COALESCE(c.raw->>'_time_text', '')::text                     AS time_text
+	//         COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+	//         (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+	//         EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited
 	//  FROM video_comments c
 	//  WHERE c.video_id = $1
 	//    AND c.parent_id = $2::text
@@ -1359,6 +2169,8 @@ type Querier interface {
 	//         COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
 	//         COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
 	//         COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+	//         (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+	//         EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited,
 	//         COALESCE((SELECT count(*) FROM video_comments rc
 	//                   WHERE rc.video_id = c.video_id AND rc.parent_id = c.comment_id), 0)::bigint AS reply_count
 	//  FROM video_comments c
@@ -1368,6 +2180,23 @@ type Querier interface {
 	//  LIMIT $3::int
 	//  OFFSET $2::int
 	ListVideoComments(ctx context.Context, arg *ListVideoCommentsParams) ([]*ListVideoCommentsRow, error)
+	// ListVideoTranscriptTexts returns the plain text of each of a video's
+	// transcripts.
+	//
+	//  SELECT text
+	//  FROM video_transcripts
+	//  WHERE video_id = $1
+	//  ORDER BY lang
+	ListVideoTranscriptTexts(ctx context.Context, videoID pgtype.UUID) ([]string, error)
+	// ListVideoTranscriptWords returns the word timings of a video's transcript
+	// in a language, in cue and word order.
+	//
+	//  SELECT video_id, lang, cue_index, word_index, start_offset, end_offset, word
+	//  FROM video_transcript_words
+	//  WHERE video_id = $1
+	//      AND lang = $2::language_tag
+	//  ORDER BY cue_index, word_index
+	ListVideoTranscriptWords(ctx context.Context, arg *ListVideoTranscriptWordsParams) ([]*VideoTranscriptWord, error)
 	// ListVideosForAssetCatchup returns videos that are missing one or more generated assets.
 	// Videos with recent errors are backed off exponentially based on _error_count.
 	//
@@ -1381,6 +2210,7 @@ type Querier interface {
 	//      OR NOT (assets_status ?& array['thumbnail','preview','waveform','file_hash','seek','faststart'])
 	//      OR assets_status @> '{"thumbnail": false}'::jsonb
 	//      OR assets_status @> '{"preview": false}'::jsonb
+	//      OR assets_status @> '{"preview_sprite": false}'::jsonb
 	//      OR assets_status @> '{"waveform": false}'::jsonb
 	//      OR assets_status @> '{"file_hash": false}'::jsonb
 	//      OR assets_status @> '{"seek": false}'::jsonb
@@ -1401,15 +2231,71 @@ type Querier interface {
 	//  ORDER BY updated_at ASC
 	//  LIMIT $1
 	ListVideosForAssetCatchup(ctx context.Context, limit int32) ([]*ListVideosForAssetCatchupRow, error)
+	// ListVideosForManifest pages through every video in id order for the
+	// archive manifest. Pass the last id of the previous page as after_id
+	// (the nil UUID for the first page).
+	//
+	//  SELECT
+	//      id,
+	//      created_at,
+	//      updated_at,
+	//      src,
+	//      title,
+	//      description,
+	//      tags,
+	//      uploader,
+	//      uploader_id,
+	//      channel_id,
+	//      upload_date,
+	//      duration_seconds,
+	//      video_path,
+	//      thumbnail_path,
+	//      file_hash,
+	//      file_size,
+	//      metadata_only,
+	//      assets_status,
+	//      info,
+	//      probe_data
+	//  FROM videos
+	//  WHERE id > $1
+	//  ORDER BY id
+	//  LIMIT $2
+	ListVideosForManifest(ctx context.Context, arg *ListVideosForManifestParams) ([]*ListVideosForManifestRow, error)
 	// ListVideosMissingVideoPath returns videos whose video_path is unset, for
 	// disk-discovery recovery of ingests that never completed (file on disk, no path).
+	// Metadata-only videos have no file by design and are skipped.
 	//
 	//  SELECT id::text AS id
 	//  FROM videos
-	//  WHERE video_path IS NULL OR btrim(video_path) = ''
+	//  WHERE (video_path IS NULL OR btrim(video_path) = '')
+	//    AND NOT metadata_only
 	//  ORDER BY updated_at ASC
 	//  LIMIT $1
 	ListVideosMissingVideoPath(ctx context.Context, limit int32) ([]string, error)
+	// ListVideosNeedingAutoTags returns transcribed videos that have not been
+	// auto-tagged yet, for backfill.
+	//
+	//  SELECT v.id
+	//  FROM videos v
+	//  WHERE v.auto_tagged_at IS NULL
+	//    AND EXISTS (SELECT 1 FROM video_transcripts vt WHERE vt.video_id = v.id)
+	//  ORDER BY v.created_at DESC
+	//  LIMIT $1
+	ListVideosNeedingAutoTags(ctx context.Context, maxCount int32) ([]pgtype.UUID, error)
+	// ListVideosNeedingPHash returns videos with a video_path but no phash, for
+	// backfill. Audio-only videos are skipped, as are videos whose last failed
+	// attempt is newer than retry_before.
+	//
+	//  SELECT v.id, v.video_path, v.duration_seconds
+	//  FROM videos v
+	//  LEFT JOIN video_phash_attempts a ON a.video_id = v.id
+	//  WHERE v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+	//    AND v.phash IS NULL
+	//    AND NOT COALESCE(v.assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb
+	//    AND (a.attempted_at IS NULL OR a.attempted_at < $1)
+	//  ORDER BY v.created_at DESC
+	//  LIMIT $2
+	ListVideosNeedingPHash(ctx context.Context, arg *ListVideosNeedingPHashParams) ([]*ListVideosNeedingPHashRow, error)
 	// ListVideosNeedingProbe returns videos with a video_path but no probe_data, for backfill.
 	//
 	//  SELECT id, video_path
@@ -1419,11 +2305,28 @@ type Querier interface {
 	//  ORDER BY created_at DESC
 	//  LIMIT $1
 	ListVideosNeedingProbe(ctx context.Context, maxCount int32) ([]*ListVideosNeedingProbeRow, error)
+	// ListVideosNeedingThumbnailPHash returns videos with a thumbnail but no
+	// thumbnail_phash, for backfill. Audio-only and metadata-only videos are
+	// skipped, since their thumbnail is not a frame of the video, as are videos
+	// whose last failed attempt is newer than retry_before.
+	//
+	//  SELECT v.id, v.thumbnail_path
+	//  FROM videos v
+	//  LEFT JOIN video_thumbnail_phash_attempts a ON a.video_id = v.id
+	//  WHERE v.thumbnail_phash IS NULL
+	//    AND v.thumbnail_path IS NOT NULL AND btrim(v.thumbnail_path) <> ''
+	//    AND v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+	//    AND NOT v.metadata_only
+	//    AND NOT COALESCE(v.assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb
+	//    AND (a.attempted_at IS NULL OR a.attempted_at < $1)
+	//  ORDER BY v.created_at DESC
+	//  LIMIT $2
+	ListVideosNeedingThumbnailPHash(ctx context.Context, arg *ListVideosNeedingThumbnailPHashParams) ([]*ListVideosNeedingThumbnailPHashRow, error)
 	// ListVideosPaginated returns videos with filters, sorting, and pagination.
 	// Returns total_count via window function for pagination UI.
 	//
 	//  SELECT
-	//      v.id, v.created_at, v.updated_at, v.src, v.archived_by, v.title, v.info, v.comments, v.video_path, v.thumbnail_path, v.description, v.tags, v.uploader, v.uploader_id, v.channel_id, v.upload_date, v.duration_seconds, v.view_count, v.like_count, v.thumb_gradient_start, v.thumb_gradient_end, v.thumb_gradient_angle, v.file_hash, v.file_size, v.assets_status, v.search, v.probe_data, v.comments_checked_at,
+	//      v.id, v.created_at, v.updated_at, v.src, v.archived_by, v.title, v.info, v.comments, v.video_path, v.thumbnail_path, v.description, v.tags, v.uploader, v.uploader_id, v.channel_id, v.upload_date, v.duration_seconds, v.view_count, v.like_count, v.thumb_gradient_start, v.thumb_gradient_end, v.thumb_gradient_angle, v.file_hash, v.file_size, v.assets_status, v.search, v.probe_data, v.comments_checked_at, v.phash, v.phash_frames, v.metadata_only, v.auto_tagged_at, v.thumbnail_phash,
 	//      COUNT(*) OVER() AS total_count,
 	//      COALESCE((SELECT COUNT(*) FROM clips c WHERE c.video_id = v.id), 0) AS clip_count,
 	//      COALESCE((SELECT COUNT(*) FROM markers m WHERE m.video_id = v.id), 0) AS marker_count,
@@ -1511,14 +2414,28 @@ type Querier interface {
 	//
 	//  LISTEN ingest_jobs
 	ListenIngestJobs(ctx context.Context) error
+	// LockClipExportsByUser serializes the user's export enqueues until the
+	// calling transaction ends, so concurrent requests cannot all count the user
+	// below the export cap before any of them inserts.
+	//
+	//  SELECT pg_advisory_xact_lock(hashtext('clip-exports'), hashtext($1::uuid::text))
+	LockClipExportsByUser(ctx context.Context, createdBy pgtype.UUID) error
+	// LockDownloadDequeue serializes the DequeueDownloadJob calls that enforce
+	// max_per_user until the calling transaction ends. Without it two workers can
+	// both count a user below the cap and each claim one of their jobs.
+	//
+	//  SELECT pg_advisory_xact_lock(hashtext('download-dequeue'))
+	LockDownloadDequeue(ctx context.Context) error
 	// MarkDownloadJobFailed stores error and marks job failed.
+	// error_code is a classified failure reason (see ytdlp.ClassifyError), or NULL.
 	//
 	//  UPDATE download_jobs
 	//  SET status = 'failed',
 	//      finished_at = NOW(),
 	//      updated_at = NOW(),
-	//      last_error = $1
-	//  WHERE id = $2
+	//      last_error = $1,
+	//      error_code = $2::text
+	//  WHERE id = $3
 	MarkDownloadJobFailed(ctx context.Context, arg *MarkDownloadJobFailedParams) error
 	// MarkDownloadJobSucceeded stores paths and marks job done.
 	//
@@ -1528,7 +2445,8 @@ type Querier interface {
 	//      updated_at = NOW(),
 	//      spool_dir = $1,
 	//      info_json_path = $2,
-	//      last_error = NULL
+	//      last_error = NULL,
+	//      error_code = NULL
 	//  WHERE id = $3
 	MarkDownloadJobSucceeded(ctx context.Context, arg *MarkDownloadJobSucceededParams) error
 	// MarkIngestJobFailed marks ingest failed.
@@ -1549,24 +2467,94 @@ type Querier interface {
 	//      last_error = NULL
 	//  WHERE id = $1
 	MarkIngestJobSucceeded(ctx context.Context, id pgtype.UUID) error
+	// MarkMissingVideoCommentsDeleted flags stored comments for a video/source that
+	// are absent from the latest fetch (seen_ids). Already-flagged rows keep their
+	// original deleted_at.
+	//
+	//  UPDATE video_comments
+	//  SET deleted_at = NOW()
+	//  WHERE video_id = $1
+	//    AND source = $2
+	//    AND deleted_at IS NULL
+	//    AND NOT (comment_id = ANY($3::text[]))
+	MarkMissingVideoCommentsDeleted(ctx context.Context, arg *MarkMissingVideoCommentsDeletedParams) (int64, error)
+	// MarkVideoAutoTagged records that a video's transcript keywords were tagged.
+	//
+	//  UPDATE videos SET auto_tagged_at = NOW() WHERE id = $1
+	MarkVideoAutoTagged(ctx context.Context, id pgtype.UUID) error
+	// MarkVideoPHashAttempted records a failed perceptual hash attempt, holding
+	// the video back from the backfill until its retry delay has passed.
+	//
+	//  INSERT INTO video_phash_attempts (video_id, attempted_at)
+	//  VALUES ($1, NOW())
+	//  ON CONFLICT (video_id) DO UPDATE SET attempted_at = EXCLUDED.attempted_at
+	MarkVideoPHashAttempted(ctx context.Context, videoID pgtype.UUID) error
+	// MarkVideoThumbnailPHashAttempted records a failed thumbnail hash attempt,
+	// holding the video back from the backfill until its retry delay has passed.
+	//
+	//  INSERT INTO video_thumbnail_phash_attempts (video_id, attempted_at)
+	//  VALUES ($1, NOW())
+	//  ON CONFLICT (video_id) DO UPDATE SET attempted_at = EXCLUDED.attempted_at
+	MarkVideoThumbnailPHashAttempted(ctx context.Context, videoID pgtype.UUID) error
+	// PrioritizeDownloadJob moves a queued job to the front of the queue by
+	// raising its priority above every other queued job, whoever queued it, so
+	// callers must restrict it to admins. Returns no rows when the job is not
+	// queued.
+	//
+	//  UPDATE download_jobs
+	//  SET priority = (
+	//          SELECT COALESCE(MAX(q.priority), 0) + 1
+	//          FROM download_jobs q
+	//          WHERE q.status = 'queued' AND q.id <> $1
+	//      ),
+	//      updated_at = NOW()
+	//  WHERE id = $1
+	//    AND status = 'queued'
+	//  RETURNING priority
+	PrioritizeDownloadJob(ctx context.Context, id pgtype.UUID) (int32, error)
 	// RecoverStuckDownloadJobs resets orphaned "processing" jobs back to "queued" on service startup.
 	// Jobs stuck in "processing" for more than the timeout are assumed to have been orphaned by a crash or restart.
+	// Backoff and max_attempts work as in RecoverStuckIngestJobs.
 	//
 	//  UPDATE download_jobs
 	//  SET status = 'queued',
+	//      next_retry_at = NOW() + LEAST(
+	//          $1::int,
+	//          CASE WHEN $2::bool
+	//               THEN $3::int * power(2, LEAST(GREATEST(attempts - 1, 0), 30))
+	//               ELSE $3::int * GREATEST(attempts, 1)
+	//          END
+	//      ) * INTERVAL '1 second',
+	//      process_pid = NULL,
 	//      updated_at = NOW()
 	//  WHERE status = 'processing'
 	//    AND updated_at < NOW() - INTERVAL '5 minutes'
-	RecoverStuckDownloadJobs(ctx context.Context) error
+	//    AND attempts < $4::int
+	RecoverStuckDownloadJobs(ctx context.Context, arg *RecoverStuckDownloadJobsParams) error
 	// RecoverStuckIngestJobs resets orphaned "processing" jobs back to "queued" on service startup.
 	// Jobs stuck in "processing" for more than the timeout are assumed to have been orphaned by a crash.
+	// Only jobs with attempts left under max_attempts are re-queued; each waits out
+	// its backoff (see db.RetryPolicy.Delay) before it can be dequeued again.
+	// Jobs running a medium or large Whisper model get a longer timeout, since a
+	// busy host can delay their heartbeat.
 	//
 	//  UPDATE ingest_jobs
 	//  SET status = 'queued',
+	//      next_retry_at = NOW() + LEAST(
+	//          $1::int,
+	//          CASE WHEN $2::bool
+	//               THEN $3::int * power(2, LEAST(GREATEST(attempts - 1, 0), 30))
+	//               ELSE $3::int * GREATEST(attempts, 1)
+	//          END
+	//      ) * INTERVAL '1 second',
 	//      updated_at = NOW()
 	//  WHERE status = 'processing'
-	//    AND updated_at < NOW() - INTERVAL '5 minutes'
-	RecoverStuckIngestJobs(ctx context.Context) error
+	//    AND updated_at < NOW() - CASE
+	//        WHEN whisper_model LIKE 'medium%' OR whisper_model LIKE 'large%' THEN INTERVAL '20 minutes'
+	//        ELSE INTERVAL '5 minutes'
+	//    END
+	//    AND attempts < $4::int
+	RecoverStuckIngestJobs(ctx context.Context, arg *RecoverStuckIngestJobsParams) error
 	// RemoveVideoTag unlinks a tag from a video.
 	//
 	//  DELETE FROM video_tags
@@ -1582,6 +2570,10 @@ type Querier interface {
 	//      progress_pct = 0,
 	//      attempts = 0,
 	//      last_error = NULL,
+	//      -- Rungs stay with their lead only if it is being requeued too.
+	//      ladder_parent_id = CASE
+	//          WHEN ladder_parent_id IN (SELECT id FROM clip_exports WHERE status = 'error') THEN ladder_parent_id
+	//      END,
 	//      updated_at = NOW()
 	//  WHERE status = 'error'
 	RequeueAllErrorExports(ctx context.Context) error
@@ -1597,9 +2589,25 @@ type Querier interface {
 	//      started_at = NULL,
 	//      finished_at = NULL,
 	//      last_error = 'Requeued: output file was missing',
+	//      -- A requeued ladder rung is encoded on its own.
+	//      ladder_parent_id = NULL,
 	//      updated_at = NOW()
 	//  WHERE id = $1
 	RequeueClipExport(ctx context.Context, id pgtype.UUID) error
+	// ResetPreviewBackfillSchedule deletes the preview backfill jobs still waiting
+	// for their scheduled slot (queued, never attempted, next_retry_at in the
+	// future) together with their placeholder download jobs, so a new backfill run
+	// replaces the previous schedule instead of adding its rate on top of it.
+	// Returns the number of jobs removed.
+	//
+	//  DELETE FROM download_jobs dj
+	//  USING ingest_jobs ij
+	//  WHERE ij.download_job_id = dj.id
+	//    AND ij.status = 'queued'
+	//    AND ij.attempts = 0
+	//    AND ij.asset_scope = 'preview'
+	//    AND ij.next_retry_at > NOW()
+	ResetPreviewBackfillSchedule(ctx context.Context) (int64, error)
 	// Reset stuck exports that have been in processing state too long without updates
 	//
 	//  UPDATE clip_exports
@@ -1627,11 +2635,15 @@ type Querier interface {
 	//  DELETE FROM user_keybindings
 	//  WHERE user_id = $1
 	ResetUserKeybindings(ctx context.Context, userID pgtype.UUID) error
-	// RetryDownloadJob resets a job to queued status for retry.
+	// RetryDownloadJob resets a job to queued status for a manual retry. The
+	// attempt count starts over and the job can be dequeued immediately.
 	//
 	//  UPDATE download_jobs
 	//  SET status = 'queued',
+	//      attempts = 0,
+	//      next_retry_at = NULL,
 	//      last_error = NULL,
+	//      error_code = NULL,
 	//      started_at = NULL,
 	//      finished_at = NULL,
 	//      process_pid = NULL,
@@ -1650,6 +2662,20 @@ type Querier interface {
 	//  SET revoked = TRUE
 	//  WHERE token = $1
 	RevokeExtensionToken(ctx context.Context, token string) error
+	// ScheduleDownloadJobRetry re-queues a failed download after delay_seconds,
+	// keeping the error so the job list shows why it is waiting. Only a job still
+	// "processing" is re-queued, so a job cancelled mid-download stays cancelled.
+	//
+	//  UPDATE download_jobs
+	//  SET status = 'queued',
+	//      next_retry_at = NOW() + $1::int * INTERVAL '1 second',
+	//      last_error = $2,
+	//      error_code = NULL,
+	//      process_pid = NULL,
+	//      updated_at = NOW()
+	//  WHERE id = $3
+	//    AND status = 'processing'
+	ScheduleDownloadJobRetry(ctx context.Context, arg *ScheduleDownloadJobRetryParams) (int64, error)
 	// Cross-video clip search for the stitch clip browser.
 	//
 	//  SELECT c.id, c.video_id, c.start_ts, c.end_ts, c.duration,
@@ -1748,6 +2774,8 @@ type Querier interface {
 	//         COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
 	//         COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
 	//         COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+	//         (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+	//         EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited,
 	//         COALESCE((SELECT count(*) FROM video_comments rc
 	//                   WHERE rc.video_id = c.video_id AND rc.parent_id = c.comment_id), 0)::bigint AS reply_count
 	//  FROM video_comments c
@@ -1793,7 +2821,7 @@ type Querier interface {
 	//    FROM hits
 	//    GROUP BY video_id
 	//  )
-	//  SELECT v.id, v.created_at, v.updated_at, v.src, v.archived_by, v.title, v.info, v.comments, v.video_path, v.thumbnail_path, v.description, v.tags, v.uploader, v.uploader_id, v.channel_id, v.upload_date, v.duration_seconds, v.view_count, v.like_count, v.thumb_gradient_start, v.thumb_gradient_end, v.thumb_gradient_angle, v.file_hash, v.file_size, v.assets_status, v.search, v.probe_data, v.comments_checked_at
+	//  SELECT v.id, v.created_at, v.updated_at, v.src, v.archived_by, v.title, v.info, v.comments, v.video_path, v.thumbnail_path, v.description, v.tags, v.uploader, v.uploader_id, v.channel_id, v.upload_date, v.duration_seconds, v.view_count, v.like_count, v.thumb_gradient_start, v.thumb_gradient_end, v.thumb_gradient_angle, v.file_hash, v.file_size, v.assets_status, v.search, v.probe_data, v.comments_checked_at, v.phash, v.phash_frames, v.metadata_only, v.auto_tagged_at, v.thumbnail_phash
 	//  FROM ranked r
 	//  JOIN videos v ON v.id = r.video_id
 	//  ORDER BY r.rank DESC, v.created_at DESC
@@ -1802,26 +2830,58 @@ type Querier interface {
 	SearchVideos(ctx context.Context, arg *SearchVideosParams) ([]*Video, error)
 	// SelectUserByEmail selects a user by email from the database
 	//
-	//  SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE email = $1 AND deleted_at IS NULL
+	//  SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE email = $1 AND deleted_at IS NULL
 	SelectUserByEmail(ctx context.Context, email string) (*User, error)
 	// SelectUserByID selects a user by ID from the database
 	//
-	//  SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE id = $1 AND deleted_at IS NULL
+	//  SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE id = $1 AND deleted_at IS NULL
 	SelectUserByID(ctx context.Context, id pgtype.UUID) (*User, error)
 	// SelectUserByUserName selects a user by user name from the database
 	//
-	//  SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE user_name = $1 AND deleted_at IS NULL
+	//  SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE user_name = $1 AND deleted_at IS NULL
 	SelectUserByUserName(ctx context.Context, userName string) (*User, error)
 	// SelectVideoBySrc returns a video by src.
 	//
-	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+	//  SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 	//  FROM videos
 	//  WHERE src = $1
 	SelectVideoBySrc(ctx context.Context, src string) (*Video, error)
+	// Store the encoder's downsampled progress timeline for a finished export
+	//
+	//  UPDATE clip_exports
+	//  SET progress_history = $1
+	//  WHERE id = $2
+	SetClipExportProgressHistory(ctx context.Context, arg *SetClipExportProgressHistoryParams) error
+	// SetPlaybackWatched marks a video watched or unwatched for a user. Either
+	// way the resume position goes back to the start.
+	//
+	//  INSERT INTO playback_positions (user_id, video_id, position_seconds, updated_at, watched_at)
+	//  VALUES (
+	//      $1,
+	//      $2,
+	//      0,
+	//      CURRENT_TIMESTAMP,
+	//      CASE WHEN $3::bool THEN CURRENT_TIMESTAMP END
+	//  )
+	//  ON CONFLICT (user_id, video_id)
+	//  DO UPDATE SET
+	//      position_seconds = 0,
+	//      updated_at = CURRENT_TIMESTAMP,
+	//      watched_at = EXCLUDED.watched_at
+	SetPlaybackWatched(ctx context.Context, arg *SetPlaybackWatchedParams) error
+	// SetUserDownloadMaxHeight sets or clears (NULL = use the role cap) a user's download resolution cap
+	//
+	//  UPDATE users
+	//  SET download_max_height = $1,
+	//      updated_at = NOW()
+	//  WHERE id = $2 AND deleted_at IS NULL
+	SetUserDownloadMaxHeight(ctx context.Context, arg *SetUserDownloadMaxHeightParams) error
 	// SetUserEnabled updates a user's enabled flag
+	// Enabling a user also clears any pending registration approval.
 	//
 	//  UPDATE users
 	//  SET enabled = $1,
+	//      pending_approval = pending_approval AND NOT $1,
 	//      updated_at = NOW()
 	//  WHERE id = $2 AND deleted_at IS NULL
 	SetUserEnabled(ctx context.Context, arg *SetUserEnabledParams) error
@@ -1832,6 +2892,14 @@ type Querier interface {
 	//      updated_at = NOW()
 	//  WHERE id = $2 AND deleted_at IS NULL
 	SetUserRole(ctx context.Context, arg *SetUserRoleParams) error
+	// SetVideoMetadataOnly flags a video as archived without its media file, or
+	// clears the flag once the full file has been ingested.
+	//
+	//  UPDATE videos
+	//  SET metadata_only = $1,
+	//      updated_at = NOW()
+	//  WHERE id = $2
+	SetVideoMetadataOnly(ctx context.Context, arg *SetVideoMetadataOnlyParams) error
 	// Attempts to acquire a PostgreSQL advisory lock (non-blocking)
 	// Returns true if the lock was acquired, false if it's already held
 	//
@@ -1882,6 +2950,14 @@ type Querier interface {
 	//  SET file_path = $1, updated_at = NOW()
 	//  WHERE id = $2
 	UpdateClipExportFilePath(ctx context.Context, arg *UpdateClipExportFilePathParams) error
+	// Update progress on a ladder lead and its in-flight rungs together
+	//
+	//  UPDATE clip_exports
+	//  SET progress_pct = $1,
+	//      updated_at = NOW()
+	//  WHERE (id = $2 OR ladder_parent_id = $2)
+	//    AND status = 'processing'
+	UpdateClipExportLadderProgress(ctx context.Context, arg *UpdateClipExportLadderProgressParams) error
 	//UpdateClipExportLastAccessed
 	//
 	//  UPDATE clip_exports
@@ -1940,7 +3016,7 @@ type Querier interface {
 	//      marker_type = COALESCE($5, marker_type),
 	//      duration = COALESCE($6, duration)
 	//  WHERE id = $7
-	//  RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by
+	//  RETURNING id, video_id, timestamp, title, description, color, marker_type, duration, created_at, created_by, source
 	UpdateMarker(ctx context.Context, arg *UpdateMarkerParams) (*Marker, error)
 	//UpdatePlayerSessionActivity
 	//
@@ -2015,6 +3091,14 @@ type Querier interface {
 	//      updated_at = NOW()
 	//  WHERE id = $3
 	UpdateVideoFileHashAndSize(ctx context.Context, arg *UpdateVideoFileHashAndSizeParams) error
+	// UpdateVideoPHash stores the perceptual hash and per-frame hashes for a video.
+	//
+	//  UPDATE videos
+	//  SET phash = $1,
+	//      phash_frames = $2::bigint[],
+	//      updated_at = NOW()
+	//  WHERE id = $3
+	UpdateVideoPHash(ctx context.Context, arg *UpdateVideoPHashParams) error
 	// UpdateVideoPath updates the video_path for a video.
 	//
 	//  UPDATE videos
@@ -2029,6 +3113,13 @@ type Querier interface {
 	//      updated_at = NOW()
 	//  WHERE id = $2
 	UpdateVideoProbeData(ctx context.Context, arg *UpdateVideoProbeDataParams) error
+	// UpdateVideoThumbnailPHash stores the perceptual hash of a video's thumbnail.
+	//
+	//  UPDATE videos
+	//  SET thumbnail_phash = $1,
+	//      updated_at = NOW()
+	//  WHERE id = $2
+	UpdateVideoThumbnailPHash(ctx context.Context, arg *UpdateVideoThumbnailPHashParams) error
 	// UpdateVideoThumbnailPath updates the thumbnail_path for a video.
 	//
 	//  UPDATE videos
@@ -2036,6 +3127,16 @@ type Querier interface {
 	//      updated_at = NOW()
 	//  WHERE id = $2
 	UpdateVideoThumbnailPath(ctx context.Context, arg *UpdateVideoThumbnailPathParams) error
+	// UpdateVideoTranscriptRaw replaces the stored VTT for a video+lang after its
+	// cue times are corrected. The plain text (and search index) carries no
+	// timestamps, so it is left as is.
+	//
+	//  UPDATE video_transcripts
+	//  SET raw = $1,
+	//      updated_at = NOW()
+	//  WHERE video_id = $2
+	//      AND lang = $3::language_tag
+	UpdateVideoTranscriptRaw(ctx context.Context, arg *UpdateVideoTranscriptRawParams) (int64, error)
 	// UpsertAdminEmails sets admin emails (creates row if missing)
 	//
 	//  INSERT INTO instance_settings (id, registration_enabled, admin_emails, updated_at)
@@ -2044,6 +3145,14 @@ type Querier interface {
 	//  SET admin_emails = EXCLUDED.admin_emails,
 	//      updated_at = NOW()
 	UpsertAdminEmails(ctx context.Context, adminEmails []string) error
+	// UpsertAutoTag returns the tag with slug, creating it when missing. Unlike
+	// UpsertTag it never renames an existing tag, so a user's casing survives.
+	//
+	//  INSERT INTO tags (name, slug)
+	//  VALUES ($1, $2)
+	//  ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+	//  RETURNING id
+	UpsertAutoTag(ctx context.Context, arg *UpsertAutoTagParams) (pgtype.UUID, error)
 	// UpsertClipExportStorageLimit sets clip export storage limit (creates row if missing)
 	//
 	//  INSERT INTO instance_settings (id, registration_enabled, admin_emails, clip_export_storage_limit_bytes, updated_at)
@@ -2052,6 +3161,48 @@ type Querier interface {
 	//  SET clip_export_storage_limit_bytes = EXCLUDED.clip_export_storage_limit_bytes,
 	//      updated_at = NOW()
 	UpsertClipExportStorageLimit(ctx context.Context, limitBytes int64) error
+	// UpsertDownloadHeightCaps sets the per-role download resolution caps (creates row if missing)
+	//
+	//  INSERT INTO instance_settings (id, registration_enabled, admin_emails, user_download_max_height, admin_download_max_height, updated_at)
+	//  VALUES (1, TRUE, ARRAY[]::text[], $1, $2, NOW())
+	//  ON CONFLICT (id) DO UPDATE
+	//  SET user_download_max_height = EXCLUDED.user_download_max_height,
+	//      admin_download_max_height = EXCLUDED.admin_download_max_height,
+	//      updated_at = NOW()
+	UpsertDownloadHeightCaps(ctx context.Context, arg *UpsertDownloadHeightCapsParams) error
+	// UpsertJobRetryPolicy sets the download and ingest retry policies (creates row if missing)
+	//
+	//  INSERT INTO instance_settings (
+	//      id, registration_enabled, admin_emails,
+	//      download_retry_max_attempts, download_retry_backoff, download_retry_base_seconds, download_retry_cap_seconds,
+	//      ingest_retry_max_attempts, ingest_retry_backoff, ingest_retry_base_seconds, ingest_retry_cap_seconds,
+	//      updated_at
+	//  )
+	//  VALUES (
+	//      1, TRUE, ARRAY[]::text[],
+	//      $1, $2, $3, $4,
+	//      $5, $6, $7, $8,
+	//      NOW()
+	//  )
+	//  ON CONFLICT (id) DO UPDATE
+	//  SET download_retry_max_attempts = EXCLUDED.download_retry_max_attempts,
+	//      download_retry_backoff = EXCLUDED.download_retry_backoff,
+	//      download_retry_base_seconds = EXCLUDED.download_retry_base_seconds,
+	//      download_retry_cap_seconds = EXCLUDED.download_retry_cap_seconds,
+	//      ingest_retry_max_attempts = EXCLUDED.ingest_retry_max_attempts,
+	//      ingest_retry_backoff = EXCLUDED.ingest_retry_backoff,
+	//      ingest_retry_base_seconds = EXCLUDED.ingest_retry_base_seconds,
+	//      ingest_retry_cap_seconds = EXCLUDED.ingest_retry_cap_seconds,
+	//      updated_at = NOW()
+	UpsertJobRetryPolicy(ctx context.Context, arg *UpsertJobRetryPolicyParams) error
+	// UpsertPlaybackMaxHeight sets the player quality menu resolution cap (creates row if missing)
+	//
+	//  INSERT INTO instance_settings (id, registration_enabled, admin_emails, playback_max_height, updated_at)
+	//  VALUES (1, TRUE, ARRAY[]::text[], $1, NOW())
+	//  ON CONFLICT (id) DO UPDATE
+	//  SET playback_max_height = EXCLUDED.playback_max_height,
+	//      updated_at = NOW()
+	UpsertPlaybackMaxHeight(ctx context.Context, playbackMaxHeight int32) error
 	// UpsertPlaybackPosition saves or updates the playback position for a user/video
 	//
 	//  INSERT INTO playback_positions (user_id, video_id, position_seconds, updated_at)
@@ -2078,6 +3229,14 @@ type Querier interface {
 	//      admin_emails = EXCLUDED.admin_emails,
 	//      updated_at = NOW()
 	UpsertRegistrationEnabled(ctx context.Context, arg *UpsertRegistrationEnabledParams) error
+	// UpsertRegistrationRequiresApproval sets whether new registrations need admin approval (creates row if missing)
+	//
+	//  INSERT INTO instance_settings (id, registration_enabled, admin_emails, registration_requires_approval, updated_at)
+	//  VALUES (1, TRUE, ARRAY[]::text[], $1, NOW())
+	//  ON CONFLICT (id) DO UPDATE
+	//  SET registration_requires_approval = EXCLUDED.registration_requires_approval,
+	//      updated_at = NOW()
+	UpsertRegistrationRequiresApproval(ctx context.Context, requiresApproval bool) error
 	// UpsertTag inserts a tag (keyed by slug) or returns the existing one. The name
 	// is refreshed so the latest casing wins; color is left as-is on conflict.
 	//
@@ -2086,6 +3245,34 @@ type Querier interface {
 	//  ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name
 	//  RETURNING id, name, slug, color, created_at, created_by
 	UpsertTag(ctx context.Context, arg *UpsertTagParams) (*Tag, error)
+	//UpsertUserCaptionStyle
+	//
+	//  INSERT INTO user_preferences (user_id, caption_font_size, caption_color, caption_background, caption_position, updated_at)
+	//  VALUES ($1, $2, $3, $4, $5, NOW())
+	//  ON CONFLICT (user_id)
+	//  DO UPDATE SET caption_font_size = EXCLUDED.caption_font_size,
+	//                caption_color = EXCLUDED.caption_color,
+	//                caption_background = EXCLUDED.caption_background,
+	//                caption_position = EXCLUDED.caption_position,
+	//                updated_at = NOW()
+	UpsertUserCaptionStyle(ctx context.Context, arg *UpsertUserCaptionStyleParams) error
+	//UpsertUserCookieDomainFilter
+	//
+	//  INSERT INTO user_preferences (user_id, cookie_domain_allowlist, cookie_domain_blocklist, updated_at)
+	//  VALUES ($1, $2::text[], $3::text[], NOW())
+	//  ON CONFLICT (user_id)
+	//  DO UPDATE SET cookie_domain_allowlist = EXCLUDED.cookie_domain_allowlist,
+	//                cookie_domain_blocklist = EXCLUDED.cookie_domain_blocklist,
+	//                updated_at = NOW()
+	UpsertUserCookieDomainFilter(ctx context.Context, arg *UpsertUserCookieDomainFilterParams) error
+	//UpsertUserExportFilenameTemplate
+	//
+	//  INSERT INTO user_preferences (user_id, export_filename_template, updated_at)
+	//  VALUES ($1, $2, NOW())
+	//  ON CONFLICT (user_id)
+	//  DO UPDATE SET export_filename_template = EXCLUDED.export_filename_template,
+	//                updated_at = NOW()
+	UpsertUserExportFilenameTemplate(ctx context.Context, arg *UpsertUserExportFilenameTemplateParams) error
 	//UpsertUserKeybinding
 	//
 	//  INSERT INTO user_keybindings (user_id, action, key)
@@ -2141,6 +3328,8 @@ type Querier interface {
 	//  WHERE COALESCE(NULLIF(c->>'id', ''), '') <> ''
 	//  ON CONFLICT (video_id, source, comment_id)
 	//  DO UPDATE SET
+	//      last_seen_at = NOW(),
+	//      deleted_at = NULL,
 	//      parent_id = EXCLUDED.parent_id,
 	//      author = EXCLUDED.author,
 	//      author_id = EXCLUDED.author_id,
@@ -2152,6 +3341,16 @@ type Querier interface {
 	//      raw = EXCLUDED.raw,
 	//      updated_at = NOW()
 	UpsertVideoCommentsFromJSON(ctx context.Context, arg *UpsertVideoCommentsFromJSONParams) error
+	// UpsertVideoNote saves the user's note on a video.
+	//
+	//  INSERT INTO video_notes (user_id, video_id, body)
+	//  VALUES ($1, $2, $3)
+	//  ON CONFLICT (user_id, video_id)
+	//  DO UPDATE SET
+	//      body = EXCLUDED.body,
+	//      updated_at = CURRENT_TIMESTAMP
+	//  RETURNING body, updated_at
+	UpsertVideoNote(ctx context.Context, arg *UpsertVideoNoteParams) (*UpsertVideoNoteRow, error)
 	// UpsertVideoTranscript stores (or updates) a transcript for a video+lang.
 	//
 	//  INSERT INTO video_transcripts (
@@ -2198,6 +3397,8 @@ type Querier interface {
 	//      password,
 	//      user_name,
 	//      role,
+	//      enabled,
+	//      pending_approval,
 	//      created_at,
 	//      updated_at,
 	//      deleted_at
@@ -2208,11 +3409,13 @@ type Querier interface {
 	//      $3,
 	//      $4,
 	//      $5,
+	//      NOT $6::boolean,
+	//      $6::boolean,
 	//      NOW(),
 	//      NOW(),
 	//      NULL
 	//  )
-	//  RETURNING id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at
+	//  RETURNING id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height
 	insertUser(ctx context.Context, arg *insertUserParams) (*User, error)
 }
 
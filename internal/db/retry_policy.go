@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Backoff modes for RetryPolicy.
+const (
+	BackoffLinear      = "linear"
+	BackoffExponential = "exponential"
+)
+
+// RetryPolicy controls how many attempts a job gets and how long it waits
+// before each re-queue. It is configured per job type in instance_settings.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt, so 1 means never retry.
+	MaxAttempts int32
+	// Backoff is BackoffLinear or BackoffExponential.
+	Backoff     string
+	BaseSeconds int32
+	// CapSeconds bounds the delay however many attempts have been made.
+	CapSeconds int32
+}
+
+// Default retry policies, matching the instance_settings column defaults.
+var (
+	DefaultDownloadRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: BackoffExponential, BaseSeconds: 60, CapSeconds: 1800}
+	DefaultIngestRetryPolicy   = RetryPolicy{MaxAttempts: 5, Backoff: BackoffExponential, BaseSeconds: 30, CapSeconds: 600}
+)
+
+// Exponential reports whether the delay doubles with each attempt.
+func (p RetryPolicy) Exponential() bool {
+	return p.Backoff == BackoffExponential
+}
+
+// CanRetry reports whether a job that has made attempts attempts may run again.
+func (p RetryPolicy) CanRetry(attempts int32) bool {
+	return attempts < p.MaxAttempts
+}
+
+// DelaySeconds is how long a job waits after its attempts-th attempt fails:
+// BaseSeconds*attempts when linear, BaseSeconds*2^(attempts-1) when
+// exponential, never more than CapSeconds. The recovery queries in
+// job_queries.sql compute the same delay in SQL.
+func (p RetryPolicy) DelaySeconds(attempts int32) int32 {
+	attempts = max(attempts, 1)
+	var d int64
+	if p.Exponential() {
+		d = int64(p.BaseSeconds) << min(attempts-1, 30)
+	} else {
+		d = int64(p.BaseSeconds) * int64(attempts)
+	}
+	return int32(min(d, int64(p.CapSeconds)))
+}
+
+// Delay is DelaySeconds as a time.Duration.
+func (p RetryPolicy) Delay(attempts int32) time.Duration {
+	return time.Duration(p.DelaySeconds(attempts)) * time.Second
+}
+
+// sanitized returns def for a zero policy (a settings row that predates the
+// retry columns) and clamps out-of-range fields otherwise.
+func (p RetryPolicy) sanitized(def RetryPolicy) RetryPolicy {
+	if p.MaxAttempts < 1 {
+		return def
+	}
+	if p.Backoff != BackoffLinear && p.Backoff != BackoffExponential {
+		p.Backoff = def.Backoff
+	}
+	p.BaseSeconds = max(p.BaseSeconds, 0)
+	p.CapSeconds = max(p.CapSeconds, 0)
+	return p
+}
+
+// DownloadRetryPolicy returns the retry policy for download jobs.
+func (s *InstanceSetting) DownloadRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: s.DownloadRetryMaxAttempts,
+		Backoff:     s.DownloadRetryBackoff,
+		BaseSeconds: s.DownloadRetryBaseSeconds,
+		CapSeconds:  s.DownloadRetryCapSeconds,
+	}.sanitized(DefaultDownloadRetryPolicy)
+}
+
+// IngestRetryPolicy returns the retry policy for ingest jobs.
+func (s *InstanceSetting) IngestRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: s.IngestRetryMaxAttempts,
+		Backoff:     s.IngestRetryBackoff,
+		BaseSeconds: s.IngestRetryBaseSeconds,
+		CapSeconds:  s.IngestRetryCapSeconds,
+	}.sanitized(DefaultIngestRetryPolicy)
+}
+
+// LoadRetryPolicies reads the download and ingest retry policies from
+// instance settings, falling back to the defaults when there is no settings
+// row or the retry columns are not migrated yet.
+func LoadRetryPolicies(ctx context.Context, q *Queries) (download, ingest RetryPolicy, err error) {
+	settings, err := q.GetInstanceSettings(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || IsUndefinedColumnErr(err) {
+			return DefaultDownloadRetryPolicy, DefaultIngestRetryPolicy, nil
+		}
+		return DefaultDownloadRetryPolicy, DefaultIngestRetryPolicy, err
+	}
+	return settings.DownloadRetryPolicy(), settings.IngestRetryPolicy(), nil
+}
@@ -0,0 +1,51 @@
+package db
+
+import "testing"
+
+func TestRetryPolicyDelaySeconds(t *testing.T) {
+	exp := RetryPolicy{MaxAttempts: 5, Backoff: BackoffExponential, BaseSeconds: 30, CapSeconds: 600}
+	lin := RetryPolicy{MaxAttempts: 5, Backoff: BackoffLinear, BaseSeconds: 30, CapSeconds: 100}
+	cases := []struct {
+		name     string
+		p        RetryPolicy
+		attempts int32
+		want     int32
+	}{
+		{"exponential first", exp, 1, 30},
+		{"exponential doubles", exp, 3, 120},
+		{"exponential capped", exp, 6, 600},
+		{"exponential huge attempts", exp, 1000, 600},
+		{"zero attempts counts as one", exp, 0, 30},
+		{"linear", lin, 2, 60},
+		{"linear capped", lin, 4, 100},
+	}
+	for _, tc := range cases {
+		if got := tc.p.DelaySeconds(tc.attempts); got != tc.want {
+			t.Errorf("%s: DelaySeconds(%d) = %d, want %d", tc.name, tc.attempts, got, tc.want)
+		}
+	}
+}
+
+func TestRetryPolicyCanRetry(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3}
+	if !p.CanRetry(2) || p.CanRetry(3) {
+		t.Fatalf("CanRetry with MaxAttempts 3: got %v at 2, %v at 3", p.CanRetry(2), p.CanRetry(3))
+	}
+}
+
+func TestInstanceSettingRetryPolicyDefaults(t *testing.T) {
+	// A zero settings row (no retry columns yet) falls back to the defaults.
+	s := &InstanceSetting{RegistrationEnabled: true}
+	if got := s.DownloadRetryPolicy(); got != DefaultDownloadRetryPolicy {
+		t.Errorf("DownloadRetryPolicy() = %+v, want %+v", got, DefaultDownloadRetryPolicy)
+	}
+	if got := s.IngestRetryPolicy(); got != DefaultIngestRetryPolicy {
+		t.Errorf("IngestRetryPolicy() = %+v, want %+v", got, DefaultIngestRetryPolicy)
+	}
+
+	s = &InstanceSetting{IngestRetryMaxAttempts: 2, IngestRetryBackoff: "bogus", IngestRetryBaseSeconds: 0, IngestRetryCapSeconds: 0}
+	want := RetryPolicy{MaxAttempts: 2, Backoff: BackoffExponential}
+	if got := s.IngestRetryPolicy(); got != want {
+		t.Errorf("IngestRetryPolicy() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Per-user preferences that need to be available server-side (as opposed to
+-- UI-only preferences kept in localStorage).
+CREATE TABLE user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    -- Download filename template for clip exports, e.g. "{video} - {clip}".
+    -- Empty means use the built-in default.
+    export_filename_template TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Perceptual video hash for near-duplicate detection. phash is the per-bit
+-- majority of phash_frames (64-bit DCT hashes of evenly sampled frames),
+-- stored as BIGINT bit patterns.
+ALTER TABLE videos ADD COLUMN phash BIGINT;
+ALTER TABLE videos ADD COLUMN phash_frames BIGINT[];
+
+-- +goose Down
+ALTER TABLE videos DROP COLUMN IF EXISTS phash_frames;
+ALTER TABLE videos DROP COLUMN IF EXISTS phash;
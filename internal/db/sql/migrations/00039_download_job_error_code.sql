@@ -0,0 +1,7 @@
+-- +goose Up
+-- Machine-readable failure reason for download jobs (e.g. 'age_restricted',
+-- 'login_required'), set alongside last_error so the UI can suggest a fix.
+ALTER TABLE download_jobs ADD COLUMN error_code TEXT;
+
+-- +goose Down
+ALTER TABLE download_jobs DROP COLUMN IF EXISTS error_code;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Related-video lookups match on channel_id first.
+CREATE INDEX IF NOT EXISTS videos_channel_id_idx ON videos(channel_id) WHERE channel_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS videos_channel_id_idx;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Queued download jobs are claimed highest priority first, then oldest first.
+-- Users raise a job's priority to move it to the front of the queue.
+ALTER TABLE download_jobs ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS download_jobs_queued_priority_idx
+    ON download_jobs(priority DESC, created_at)
+    WHERE status = 'queued';
+
+-- +goose Down
+DROP INDEX IF EXISTS download_jobs_queued_priority_idx;
+ALTER TABLE download_jobs DROP COLUMN IF EXISTS priority;
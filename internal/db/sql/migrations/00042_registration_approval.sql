@@ -0,0 +1,9 @@
+-- +goose Up
+-- When registration_requires_approval is set, new sign-ups are created
+-- disabled and flagged pending_approval until an admin enables them.
+ALTER TABLE instance_settings ADD COLUMN registration_requires_approval BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN pending_approval BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS pending_approval;
+ALTER TABLE instance_settings DROP COLUMN IF EXISTS registration_requires_approval;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Downsampled encode timeline ([{t, pct, speed}, ...]) recorded by the encoder
+-- when EXPORT_PROGRESS_HISTORY is enabled. NULL when not recorded.
+ALTER TABLE clip_exports ADD COLUMN progress_history JSONB;
+
+-- +goose Down
+ALTER TABLE clip_exports DROP COLUMN IF EXISTS progress_history;
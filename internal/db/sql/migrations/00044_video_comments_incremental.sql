@@ -0,0 +1,26 @@
+-- +goose Up
+-- Incremental comment archiving. first_seen_at/last_seen_at record when a
+-- comment was first and most recently present in a fetch; deleted_at is set
+-- when a refresh no longer returns it (only with COMMENTS_MARK_DELETED) and is
+-- cleared if it reappears. Text a comment had before an edit is kept in
+-- video_comment_edits.
+ALTER TABLE video_comments ADD COLUMN first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE video_comments ADD COLUMN last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE video_comments ADD COLUMN deleted_at TIMESTAMPTZ;
+UPDATE video_comments SET first_seen_at = created_at, last_seen_at = updated_at;
+
+CREATE TABLE video_comment_edits (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    video_comment_id UUID NOT NULL REFERENCES video_comments(id) ON DELETE CASCADE,
+    text TEXT,
+    raw JSONB NOT NULL,
+    -- When the newer version replaced this one.
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX video_comment_edits_comment_idx ON video_comment_edits(video_comment_id, replaced_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS video_comment_edits;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE video_comments DROP COLUMN IF EXISTS first_seen_at;
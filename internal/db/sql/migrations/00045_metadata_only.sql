@@ -0,0 +1,10 @@
+-- +goose Up
+-- Metadata-only archiving: the downloader fetches just the info JSON and
+-- thumbnail, and the resulting video row has no media file. videos.metadata_only
+-- stays set until a full download of the same source is ingested.
+ALTER TABLE download_jobs ADD COLUMN metadata_only BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE videos ADD COLUMN metadata_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE videos DROP COLUMN IF EXISTS metadata_only;
+ALTER TABLE download_jobs DROP COLUMN IF EXISTS metadata_only;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Caption display preferences applied to the captions track in the player.
+-- Empty means keep the VTT/browser default. Values are validated by
+-- pkg/utils/captionstyle.
+ALTER TABLE user_preferences
+    ADD COLUMN caption_font_size TEXT NOT NULL DEFAULT '',
+    ADD COLUMN caption_color TEXT NOT NULL DEFAULT '',
+    ADD COLUMN caption_background TEXT NOT NULL DEFAULT '',
+    ADD COLUMN caption_position TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE user_preferences
+    DROP COLUMN IF EXISTS caption_position,
+    DROP COLUMN IF EXISTS caption_background,
+    DROP COLUMN IF EXISTS caption_color,
+    DROP COLUMN IF EXISTS caption_font_size;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Set when the user marks a video as watched; cleared when they mark it
+-- unwatched. Independent of position_seconds so rewatching keeps the flag.
+ALTER TABLE playback_positions ADD COLUMN watched_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE playback_positions DROP COLUMN IF EXISTS watched_at;
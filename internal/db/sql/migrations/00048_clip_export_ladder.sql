@@ -0,0 +1,14 @@
+-- +goose Up
+-- Rungs of a rendition ladder point at the ladder's lead export, which the
+-- encoder claims and encodes every pending rung with in one pass. Rungs are
+-- never claimed on their own while linked; detaching one (on requeue or when
+-- the lead is deleted) turns it into an ordinary export of its height.
+ALTER TABLE clip_exports
+    ADD COLUMN ladder_parent_id UUID REFERENCES clip_exports(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_clip_exports_ladder_parent ON clip_exports(ladder_parent_id)
+    WHERE ladder_parent_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_clip_exports_ladder_parent;
+ALTER TABLE clip_exports DROP COLUMN IF EXISTS ladder_parent_id;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Tags suggested from transcript keywords. auto marks links added by ingest so
+-- re-tagging can replace them without touching tags users added themselves;
+-- auto_tagged_at records which videos have been processed, for backfill.
+ALTER TABLE video_tags ADD COLUMN auto BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE videos ADD COLUMN auto_tagged_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE videos DROP COLUMN IF EXISTS auto_tagged_at;
+ALTER TABLE video_tags DROP COLUMN IF EXISTS auto;
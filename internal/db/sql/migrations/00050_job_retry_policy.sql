@@ -0,0 +1,29 @@
+-- +goose Up
+-- Per job type retry policy: how many attempts a job gets and how long it
+-- waits before each re-queue (backoff is 'linear' or 'exponential', capped).
+-- next_retry_at holds a re-queued job back until its backoff has elapsed.
+ALTER TABLE instance_settings
+    ADD COLUMN download_retry_max_attempts INT NOT NULL DEFAULT 3 CHECK (download_retry_max_attempts BETWEEN 1 AND 20),
+    ADD COLUMN download_retry_backoff TEXT NOT NULL DEFAULT 'exponential' CHECK (download_retry_backoff IN ('linear', 'exponential')),
+    ADD COLUMN download_retry_base_seconds INT NOT NULL DEFAULT 60 CHECK (download_retry_base_seconds >= 0),
+    ADD COLUMN download_retry_cap_seconds INT NOT NULL DEFAULT 1800 CHECK (download_retry_cap_seconds >= 0),
+    ADD COLUMN ingest_retry_max_attempts INT NOT NULL DEFAULT 5 CHECK (ingest_retry_max_attempts BETWEEN 1 AND 20),
+    ADD COLUMN ingest_retry_backoff TEXT NOT NULL DEFAULT 'exponential' CHECK (ingest_retry_backoff IN ('linear', 'exponential')),
+    ADD COLUMN ingest_retry_base_seconds INT NOT NULL DEFAULT 30 CHECK (ingest_retry_base_seconds >= 0),
+    ADD COLUMN ingest_retry_cap_seconds INT NOT NULL DEFAULT 600 CHECK (ingest_retry_cap_seconds >= 0);
+
+ALTER TABLE download_jobs ADD COLUMN next_retry_at TIMESTAMPTZ;
+ALTER TABLE ingest_jobs ADD COLUMN next_retry_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE ingest_jobs DROP COLUMN IF EXISTS next_retry_at;
+ALTER TABLE download_jobs DROP COLUMN IF EXISTS next_retry_at;
+ALTER TABLE instance_settings
+    DROP COLUMN IF EXISTS download_retry_max_attempts,
+    DROP COLUMN IF EXISTS download_retry_backoff,
+    DROP COLUMN IF EXISTS download_retry_base_seconds,
+    DROP COLUMN IF EXISTS download_retry_cap_seconds,
+    DROP COLUMN IF EXISTS ingest_retry_max_attempts,
+    DROP COLUMN IF EXISTS ingest_retry_backoff,
+    DROP COLUMN IF EXISTS ingest_retry_base_seconds,
+    DROP COLUMN IF EXISTS ingest_retry_cap_seconds;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Download resolution caps. The instance settings hold one cap per role;
+-- users.download_max_height overrides the role cap for one user when set.
+-- A cap is a maximum video height in pixels; 0 means best available.
+ALTER TABLE instance_settings
+    ADD COLUMN user_download_max_height INT NOT NULL DEFAULT 0 CHECK (user_download_max_height >= 0),
+    ADD COLUMN admin_download_max_height INT NOT NULL DEFAULT 0 CHECK (admin_download_max_height >= 0);
+ALTER TABLE users ADD COLUMN download_max_height INT CHECK (download_max_height >= 0);
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS download_max_height;
+ALTER TABLE instance_settings
+    DROP COLUMN IF EXISTS user_download_max_height,
+    DROP COLUMN IF EXISTS admin_download_max_height;
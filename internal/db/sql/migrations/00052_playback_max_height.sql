@@ -0,0 +1,8 @@
+-- +goose Up
+-- Tallest alternate-quality stream offered in the player's quality menu, in
+-- pixels; 0 offers every stream. Taller stream files stay on disk.
+ALTER TABLE instance_settings
+    ADD COLUMN playback_max_height INT NOT NULL DEFAULT 0 CHECK (playback_max_height >= 0);
+
+-- +goose Down
+ALTER TABLE instance_settings DROP COLUMN IF EXISTS playback_max_height;
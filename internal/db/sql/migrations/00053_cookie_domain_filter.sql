@@ -0,0 +1,14 @@
+-- +goose Up
+-- Per-user cookie domain filter applied when cookies are imported (settings
+-- upload, browser extension, cookies refreshed by the downloader). Entries are
+-- bare domains that also match their subdomains, normalized by
+-- pkg/utils/cookiedomains. A non-empty allowlist keeps only matching domains;
+-- the blocklist always wins.
+ALTER TABLE user_preferences
+    ADD COLUMN cookie_domain_allowlist TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN cookie_domain_blocklist TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE user_preferences
+    DROP COLUMN IF EXISTS cookie_domain_blocklist,
+    DROP COLUMN IF EXISTS cookie_domain_allowlist;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Free-form, private markdown notes a user keeps on a video. Unlike markers
+-- they are not tied to a timestamp, and unlike comments they are not archived
+-- from the source. One note per user and video; an empty note is deleted.
+CREATE TABLE video_notes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    body markdown_src NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, video_id)
+);
+
+CREATE INDEX idx_video_notes_video_id ON video_notes(video_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS video_notes;
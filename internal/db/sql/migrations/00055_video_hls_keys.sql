@@ -0,0 +1,12 @@
+-- +goose Up
+-- AES-128 keys of encrypted HLS renditions, one per video, stored encrypted
+-- with the instance key. Kept out of videos so the many SELECT * queries on
+-- videos never load it.
+CREATE TABLE video_hls_keys (
+    video_id UUID PRIMARY KEY REFERENCES videos(id) ON DELETE CASCADE,
+    key encrypted_bytes NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS video_hls_keys;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Where a marker came from. Markers imported from SponsorBlock at ingest are
+-- replaced on every re-import, so they must be told apart from markers users
+-- placed themselves.
+CREATE TYPE marker_source AS ENUM ('user', 'sponsorblock');
+ALTER TABLE markers ADD COLUMN source marker_source NOT NULL DEFAULT 'user';
+
+-- +goose Down
+ALTER TABLE markers DROP COLUMN IF EXISTS source;
+DROP TYPE IF EXISTS marker_source;
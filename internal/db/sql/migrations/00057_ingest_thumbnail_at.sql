@@ -0,0 +1,7 @@
+-- +goose Up
+-- Timestamp (seconds) a thumbnail regeneration job pins the video's thumbnail
+-- frame to. A negative value clears the pin; NULL leaves it unchanged.
+ALTER TABLE ingest_jobs ADD COLUMN thumbnail_at DOUBLE PRECISION;
+
+-- +goose Down
+ALTER TABLE ingest_jobs DROP COLUMN IF EXISTS thumbnail_at;
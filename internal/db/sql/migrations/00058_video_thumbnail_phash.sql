@@ -0,0 +1,8 @@
+-- +goose Up
+-- Perceptual hash of the video's thumbnail. Cheap enough to compute on every
+-- ingest, it backs duplicate suggestions for videos that have no whole-video
+-- phash (PHASH_ENABLED off).
+ALTER TABLE videos ADD COLUMN thumbnail_phash BIGINT;
+
+-- +goose Down
+ALTER TABLE videos DROP COLUMN IF EXISTS thumbnail_phash;
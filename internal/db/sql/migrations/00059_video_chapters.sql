@@ -0,0 +1,19 @@
+-- +goose Up
+-- Chapters of a video, sanitized at ingest (sorted, no overlaps, no
+-- zero-length entries). source records where they came from: the yt-dlp
+-- info.json ('info') or the media file's own chapter metadata ('container').
+-- position matches the chapter poster index under <video dir>/chapters/.
+CREATE TABLE video_chapters (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    start_seconds DOUBLE PRECISION NOT NULL,
+    end_seconds DOUBLE PRECISION NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (video_id, position),
+    CHECK (end_seconds > start_seconds)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS video_chapters;
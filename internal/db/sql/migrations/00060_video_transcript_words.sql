@@ -0,0 +1,18 @@
+-- +goose Up
+-- Word timings of a transcript, from Whisper's word-level timestamps.
+-- cue_index numbers the transcript's VTT cues in file order (cues without
+-- text are not counted); offsets are seconds from that cue's start, so they
+-- stay valid when the captions are shifted.
+CREATE TABLE video_transcript_words (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    lang language_tag NOT NULL,
+    cue_index INT NOT NULL,
+    word_index INT NOT NULL,
+    start_offset DOUBLE PRECISION NOT NULL,
+    end_offset DOUBLE PRECISION NOT NULL,
+    word TEXT NOT NULL,
+    PRIMARY KEY (video_id, lang, cue_index, word_index)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS video_transcript_words;
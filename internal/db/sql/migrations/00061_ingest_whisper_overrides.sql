@@ -0,0 +1,10 @@
+-- +goose Up
+-- Whisper settings a caption regeneration job overrides for its run. NULL
+-- uses WHISPER_MODEL / WHISPER_LANGUAGE; a language of 'auto' forces
+-- detection.
+ALTER TABLE ingest_jobs ADD COLUMN whisper_model TEXT;
+ALTER TABLE ingest_jobs ADD COLUMN whisper_language TEXT;
+
+-- +goose Down
+ALTER TABLE ingest_jobs DROP COLUMN IF EXISTS whisper_language;
+ALTER TABLE ingest_jobs DROP COLUMN IF EXISTS whisper_model;
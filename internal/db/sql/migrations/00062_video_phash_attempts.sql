@@ -0,0 +1,11 @@
+-- +goose Up
+-- Last failed perceptual hash attempt per video. The backfill skips a video
+-- until its retry delay has passed, so files that cannot be hashed don't hold
+-- the newest slots forever.
+CREATE TABLE video_phash_attempts (
+    video_id UUID PRIMARY KEY REFERENCES videos(id) ON DELETE CASCADE,
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS video_phash_attempts;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Thumbnail hashes of audio-only and metadata-only videos hash cover or
+-- platform art, not a frame of the video, and suggest false duplicates.
+UPDATE videos SET thumbnail_phash = NULL
+WHERE metadata_only
+   OR COALESCE(assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb;
+
+-- Last failed thumbnail hash attempt per video, so the backfill skips
+-- thumbnails that cannot be hashed until their retry delay has passed.
+CREATE TABLE video_thumbnail_phash_attempts (
+    video_id UUID PRIMARY KEY REFERENCES videos(id) ON DELETE CASCADE,
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS video_thumbnail_phash_attempts;
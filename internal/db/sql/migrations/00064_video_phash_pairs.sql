@@ -0,0 +1,26 @@
+-- +goose Up
+-- Pairs of videos whose combined perceptual hashes are within 20 bits
+-- (phash.MaxPairDistance), kept up to date as hashes are stored, so the
+-- near-duplicates page reads pairs instead of comparing every video with
+-- every other. video_id sorts before other_id.
+CREATE TABLE video_phash_pairs (
+    video_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    other_id UUID NOT NULL REFERENCES videos(id) ON DELETE CASCADE,
+    distance SMALLINT NOT NULL,
+    PRIMARY KEY (video_id, other_id),
+    CHECK (video_id < other_id)
+);
+CREATE INDEX video_phash_pairs_distance_idx ON video_phash_pairs (distance);
+CREATE INDEX video_phash_pairs_other_id_idx ON video_phash_pairs (other_id);
+
+-- Pairs among videos hashed before this migration.
+INSERT INTO video_phash_pairs (video_id, other_id, distance)
+SELECT a.id, b.id, bit_count((a.phash # b.phash)::bit(64))
+FROM videos a
+JOIN videos b ON a.id < b.id
+WHERE a.phash IS NOT NULL
+  AND b.phash IS NOT NULL
+  AND bit_count((a.phash # b.phash)::bit(64)) <= 20;
+
+-- +goose Down
+DROP TABLE IF EXISTS video_phash_pairs;
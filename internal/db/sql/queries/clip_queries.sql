@@ -111,6 +111,38 @@ JOIN videos v ON v.id = c.video_id
 ORDER BY ce.created_at DESC
 LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
 
+-- name: GetClipExportThroughput :one
+-- Fleet-wide encoder throughput for the admin exports dashboard: exports
+-- finished in the last hour, mean encode time over the last 24 hours, the
+-- current backlog and how many encoder hosts are busy.
+SELECT
+    COUNT(*) FILTER (WHERE status = 'ready' AND finished_at > NOW() - INTERVAL '1 hour')::bigint AS completed_last_hour,
+    COUNT(*) FILTER (WHERE status = 'error' AND finished_at > NOW() - INTERVAL '1 hour')::bigint AS failed_last_hour,
+    COALESCE(AVG(EXTRACT(EPOCH FROM finished_at - started_at)) FILTER (
+        WHERE status = 'ready' AND started_at IS NOT NULL AND finished_at > NOW() - INTERVAL '24 hours'
+    ), 0)::float8 AS avg_encode_seconds,
+    COUNT(*) FILTER (WHERE status = 'queued')::bigint AS queued_count,
+    COUNT(*) FILTER (WHERE status = 'processing')::bigint AS processing_count,
+    COUNT(DISTINCT locked_by) FILTER (WHERE status = 'processing')::bigint AS active_workers
+FROM clip_exports;
+
+-- name: ListInFlightClipExports :many
+-- Exports currently being encoded, oldest first, for the throughput dashboard
+SELECT
+    ce.id,
+    ce.format,
+    ce.progress_pct,
+    ce.started_at,
+    ce.locked_by,
+    c.title AS clip_label,
+    v.title AS video_title
+FROM clip_exports ce
+JOIN clips c ON c.id = ce.clip_id
+JOIN videos v ON v.id = c.video_id
+WHERE ce.status = 'processing'
+ORDER BY ce.started_at ASC NULLS LAST
+LIMIT sqlc.arg(lim);
+
 -- name: CountClipExports :one
 SELECT COUNT(*) FROM clip_exports;
 
@@ -137,6 +169,10 @@ SET status = 'queued',
     progress_pct = 0,
     attempts = 0,
     last_error = NULL,
+    -- Rungs stay with their lead only if it is being requeued too.
+    ladder_parent_id = CASE
+        WHEN ladder_parent_id IN (SELECT id FROM clip_exports WHERE status = 'error') THEN ladder_parent_id
+    END,
     updated_at = NOW()
 WHERE status = 'error';
 
@@ -145,9 +181,26 @@ SELECT id, file_path, size_bytes FROM clip_exports
 WHERE status = 'ready'
 ORDER BY last_accessed_at ASC NULLS FIRST;
 
+-- CountActiveClipExportsByUser returns how many of the user's exports are
+-- queued or processing, for the per-user export cap.
+-- name: CountActiveClipExportsByUser :one
+SELECT COUNT(*) FROM clip_exports
+WHERE created_by = sqlc.arg(created_by)
+  AND status IN ('queued', 'processing');
+
+-- LockClipExportsByUser serializes the user's export enqueues until the
+-- calling transaction ends, so concurrent requests cannot all count the user
+-- below the export cap before any of them inserts.
+-- name: LockClipExportsByUser :exec
+SELECT pg_advisory_xact_lock(hashtext('clip-exports'), hashtext(sqlc.arg(created_by)::uuid::text));
+
 -- name: DeleteClipExport :exec
 DELETE FROM clip_exports WHERE id = sqlc.arg(id);
 
+-- FindReusableClipExport returns the user's newest ready export of the clip
+-- with an identical spec (format, variant, loop, GOP, quality, target bitrate,
+-- GIF/WebP frame rate and width, filters, PiP inset and seek mode).
+-- Ladder renditions are scaled, so they never stand in for a full-size export.
 -- name: FindReusableClipExport :one
 SELECT id, file_path 
 FROM clip_exports
@@ -155,6 +208,16 @@ WHERE clip_exports.clip_id = sqlc.arg(clip_id)
   AND clip_exports.created_by = sqlc.arg(created_by)
   AND clip_exports.format = sqlc.arg(format)
   AND clip_exports.variant = sqlc.arg(variant)
+  AND COALESCE((clip_exports.spec->>'loop')::int, 0) = sqlc.arg(loop_count)::int
+  AND COALESCE((clip_exports.spec->>'gop')::int, 0) = sqlc.arg(gop)::int
+  AND COALESCE((clip_exports.spec->>'target_bitrate')::int, 0) = sqlc.arg(target_bitrate)::int
+  AND COALESCE((clip_exports.spec->>'fps')::int, 0) = sqlc.arg(fps)::int
+  AND COALESCE((clip_exports.spec->>'max_width')::int, 0) = sqlc.arg(max_width)::int
+  AND COALESCE(clip_exports.spec->>'quality', '') = sqlc.arg(quality)::text
+  AND COALESCE(clip_exports.spec->'filters', '[]'::jsonb) = sqlc.arg(filters)::jsonb
+  AND COALESCE(clip_exports.spec->'pip', 'null'::jsonb) = sqlc.arg(pip)::jsonb
+  AND COALESCE((clip_exports.spec->>'accurate')::boolean, false) = sqlc.arg(accurate)::boolean
+  AND COALESCE((clip_exports.spec->>'height')::int, 0) = 0
   AND clip_exports.status = 'ready'
   AND clip_exports.clip_updated_at >= (SELECT clips.updated_at FROM clips WHERE clips.id = sqlc.arg(clip_id))
 ORDER BY clip_exports.created_at DESC
@@ -170,6 +233,20 @@ INSERT INTO clip_exports (clip_id, created_by, format, variant, spec, clip_updat
 VALUES (sqlc.arg(clip_id), sqlc.arg(created_by), sqlc.arg(format), sqlc.arg(variant), sqlc.arg(spec), sqlc.arg(clip_updated_at), '', 'queued', NOW(), NOW())
 RETURNING id;
 
+-- CreateClipExportRung adds a queued rung to a rendition ladder. Rungs are
+-- encoded by the lead's worker, so no NOTIFY is needed.
+-- name: CreateClipExportRung :one
+INSERT INTO clip_exports (clip_id, created_by, format, variant, spec, clip_updated_at, ladder_parent_id, file_path, status, created_at, updated_at)
+VALUES (sqlc.arg(clip_id), sqlc.arg(created_by), sqlc.arg(format), sqlc.arg(variant), sqlc.arg(spec), sqlc.arg(clip_updated_at), sqlc.arg(ladder_parent_id), '', 'queued', NOW(), NOW())
+RETURNING id;
+
+-- ListClipExportRungs returns the rungs of a rendition ladder, tallest first.
+-- name: ListClipExportRungs :many
+SELECT id, status, COALESCE((spec->>'height')::int, 0)::int AS height
+FROM clip_exports
+WHERE ladder_parent_id = sqlc.arg(ladder_parent_id)
+ORDER BY height DESC;
+
 -- name: UpdateClipExportFilePath :exec
 UPDATE clip_exports 
 SET file_path = sqlc.arg(file_path), updated_at = NOW() 
@@ -183,9 +260,11 @@ WHERE id = sqlc.arg(id);
 -- name: GetClipExportForDownload :one
 SELECT ce.file_path, ce.format, ce.status, ce.clip_id, ce.variant,
        COALESCE(c.title, '') AS clip_title,
-       c.crops
+       c.crops,
+       v.title AS video_title
 FROM clip_exports ce
 JOIN clips c ON c.id = ce.clip_id
+JOIN videos v ON v.id = c.video_id
 WHERE ce.id = sqlc.arg(id);
 
 -- ============================================================================
@@ -205,6 +284,7 @@ SET status = 'processing',
 WHERE id = (
     SELECT id FROM clip_exports
     WHERE status = 'queued'
+      AND ladder_parent_id IS NULL
       AND (locked_at IS NULL OR locked_at < NOW() - INTERVAL '10 minutes')
     ORDER BY created_at ASC
     LIMIT 1
@@ -265,6 +345,40 @@ SET status = 'ready',
     updated_at = NOW()
 WHERE id = sqlc.arg(id);
 
+-- name: ClaimClipExportRungs :many
+-- Mark a ladder lead's pending rungs as processing by the same worker
+UPDATE clip_exports
+SET status = 'processing',
+    locked_at = NOW(),
+    locked_by = sqlc.arg(locked_by),
+    started_at = NOW(),
+    attempts = attempts + 1,
+    updated_at = NOW()
+WHERE ladder_parent_id = sqlc.arg(ladder_parent_id)
+  AND status IN ('queued', 'processing')
+RETURNING id, spec;
+
+-- name: UpdateClipExportLadderProgress :exec
+-- Update progress on a ladder lead and its in-flight rungs together
+UPDATE clip_exports
+SET progress_pct = sqlc.arg(progress_pct),
+    updated_at = NOW()
+WHERE (id = sqlc.arg(id) OR ladder_parent_id = sqlc.arg(id))
+  AND status = 'processing';
+
+-- name: FinishClipExportRungsError :exec
+-- Fail a ladder lead's in-flight rungs along with the lead
+UPDATE clip_exports
+SET status = 'error',
+    last_error = sqlc.arg(last_error),
+    finished_at = NOW(),
+    locked_at = NULL,
+    locked_by = NULL,
+    pid = NULL,
+    updated_at = NOW()
+WHERE ladder_parent_id = sqlc.arg(ladder_parent_id)
+  AND status = 'processing';
+
 -- name: FinishClipExportError :exec
 -- Mark export as failed with error message
 UPDATE clip_exports
@@ -277,6 +391,17 @@ SET status = 'error',
     updated_at = NOW()
 WHERE id = sqlc.arg(id);
 
+-- name: SetClipExportProgressHistory :exec
+-- Store the encoder's downsampled progress timeline for a finished export
+UPDATE clip_exports
+SET progress_history = sqlc.arg(progress_history)
+WHERE id = sqlc.arg(id);
+
+-- name: GetClipExportProgressHistory :one
+SELECT id, status, started_at, finished_at, progress_history
+FROM clip_exports
+WHERE id = sqlc.arg(id);
+
 -- name: GetClipExportStatus :one
 -- Get current export status for SSE streaming
 SELECT id, clip_id, status, progress_pct, file_path, last_error
@@ -291,6 +416,16 @@ WHERE clip_id = sqlc.arg(clip_id)
   AND created_by = sqlc.arg(created_by)
   AND format = sqlc.arg(format)
   AND variant = sqlc.arg(variant)
+  AND COALESCE((spec->>'loop')::int, 0) = sqlc.arg(loop_count)::int
+  AND COALESCE((spec->>'gop')::int, 0) = sqlc.arg(gop)::int
+  AND COALESCE((spec->>'target_bitrate')::int, 0) = sqlc.arg(target_bitrate)::int
+  AND COALESCE((spec->>'fps')::int, 0) = sqlc.arg(fps)::int
+  AND COALESCE((spec->>'max_width')::int, 0) = sqlc.arg(max_width)::int
+  AND COALESCE(spec->>'quality', '') = sqlc.arg(quality)::text
+  AND COALESCE(spec->'filters', '[]'::jsonb) = sqlc.arg(filters)::jsonb
+  AND COALESCE(spec->'pip', 'null'::jsonb) = sqlc.arg(pip)::jsonb
+  AND COALESCE((spec->>'accurate')::boolean, false) = sqlc.arg(accurate)::boolean
+  AND COALESCE((spec->>'height')::int, 0) = 0
   AND status IN ('queued', 'processing')
   AND updated_at > NOW() - INTERVAL '5 minutes'
 ORDER BY created_at DESC
@@ -319,6 +454,8 @@ SET status = 'queued',
     started_at = NULL,
     finished_at = NULL,
     last_error = 'Requeued: output file was missing',
+    -- A requeued ladder rung is encoded on its own.
+    ladder_parent_id = NULL,
     updated_at = NOW()
 WHERE id = sqlc.arg(id);
 
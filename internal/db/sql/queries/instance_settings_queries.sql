@@ -26,3 +26,53 @@ VALUES (1, TRUE, sqlc.arg(admin_emails), NOW())
 ON CONFLICT (id) DO UPDATE
 SET admin_emails = EXCLUDED.admin_emails,
     updated_at = NOW();
+
+-- UpsertRegistrationRequiresApproval sets whether new registrations need admin approval (creates row if missing)
+-- name: UpsertRegistrationRequiresApproval :exec
+INSERT INTO instance_settings (id, registration_enabled, admin_emails, registration_requires_approval, updated_at)
+VALUES (1, TRUE, ARRAY[]::text[], sqlc.arg(requires_approval), NOW())
+ON CONFLICT (id) DO UPDATE
+SET registration_requires_approval = EXCLUDED.registration_requires_approval,
+    updated_at = NOW();
+
+-- UpsertJobRetryPolicy sets the download and ingest retry policies (creates row if missing)
+-- name: UpsertJobRetryPolicy :exec
+INSERT INTO instance_settings (
+    id, registration_enabled, admin_emails,
+    download_retry_max_attempts, download_retry_backoff, download_retry_base_seconds, download_retry_cap_seconds,
+    ingest_retry_max_attempts, ingest_retry_backoff, ingest_retry_base_seconds, ingest_retry_cap_seconds,
+    updated_at
+)
+VALUES (
+    1, TRUE, ARRAY[]::text[],
+    sqlc.arg(download_max_attempts), sqlc.arg(download_backoff), sqlc.arg(download_base_seconds), sqlc.arg(download_cap_seconds),
+    sqlc.arg(ingest_max_attempts), sqlc.arg(ingest_backoff), sqlc.arg(ingest_base_seconds), sqlc.arg(ingest_cap_seconds),
+    NOW()
+)
+ON CONFLICT (id) DO UPDATE
+SET download_retry_max_attempts = EXCLUDED.download_retry_max_attempts,
+    download_retry_backoff = EXCLUDED.download_retry_backoff,
+    download_retry_base_seconds = EXCLUDED.download_retry_base_seconds,
+    download_retry_cap_seconds = EXCLUDED.download_retry_cap_seconds,
+    ingest_retry_max_attempts = EXCLUDED.ingest_retry_max_attempts,
+    ingest_retry_backoff = EXCLUDED.ingest_retry_backoff,
+    ingest_retry_base_seconds = EXCLUDED.ingest_retry_base_seconds,
+    ingest_retry_cap_seconds = EXCLUDED.ingest_retry_cap_seconds,
+    updated_at = NOW();
+
+-- UpsertDownloadHeightCaps sets the per-role download resolution caps (creates row if missing)
+-- name: UpsertDownloadHeightCaps :exec
+INSERT INTO instance_settings (id, registration_enabled, admin_emails, user_download_max_height, admin_download_max_height, updated_at)
+VALUES (1, TRUE, ARRAY[]::text[], sqlc.arg(user_max_height), sqlc.arg(admin_max_height), NOW())
+ON CONFLICT (id) DO UPDATE
+SET user_download_max_height = EXCLUDED.user_download_max_height,
+    admin_download_max_height = EXCLUDED.admin_download_max_height,
+    updated_at = NOW();
+
+-- UpsertPlaybackMaxHeight sets the player quality menu resolution cap (creates row if missing)
+-- name: UpsertPlaybackMaxHeight :exec
+INSERT INTO instance_settings (id, registration_enabled, admin_emails, playback_max_height, updated_at)
+VALUES (1, TRUE, ARRAY[]::text[], sqlc.arg(playback_max_height), NOW())
+ON CONFLICT (id) DO UPDATE
+SET playback_max_height = EXCLUDED.playback_max_height,
+    updated_at = NOW();
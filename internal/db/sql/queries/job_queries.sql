@@ -10,42 +10,63 @@ JOIN download_jobs dj ON dj.id = ij.download_job_id
 WHERE dj.video_id = sqlc.arg(video_id)
   AND ij.status IN ('queued', 'processing');
 
--- EnqueueDownloadJob inserts a new download job.
+-- EnqueueDownloadJob inserts a new download job. metadata_only jobs fetch
+-- just the info JSON and thumbnail, never the media file.
 -- name: EnqueueDownloadJob :one
 INSERT INTO download_jobs (
     url,
     archived_by,
     status,
     refresh,
-    extra_args
+    extra_args,
+    metadata_only
 )
 VALUES (
     sqlc.arg(url),
     sqlc.arg(archived_by),
     'queued',
     sqlc.arg(refresh),
-    sqlc.arg(extra_args)
+    sqlc.arg(extra_args),
+    sqlc.arg(metadata_only)
 )
 RETURNING *;
 
--- DequeueDownloadJob claims one queued download job.
+-- DequeueDownloadJob claims one queued download job, highest priority first.
+-- Jobs waiting out a retry backoff (next_retry_at in the future) are skipped,
+-- as are jobs of users who already have max_per_user jobs processing (0 means
+-- no limit), so one user's playlist can't occupy every worker.
+-- Callers enforcing the cap hold LockDownloadDequeue in the same transaction.
 -- name: DequeueDownloadJob :one
 WITH cte AS (
-    SELECT id
-    FROM download_jobs
-    WHERE status = 'queued'
-    ORDER BY created_at
+    SELECT j.id
+    FROM download_jobs j
+    WHERE j.status = 'queued'
+      AND (j.next_retry_at IS NULL OR j.next_retry_at <= NOW())
+      AND (sqlc.arg(max_per_user)::int <= 0 OR (
+          SELECT COUNT(*)
+          FROM download_jobs p
+          WHERE p.archived_by = j.archived_by
+            AND p.status = 'processing'
+      ) < sqlc.arg(max_per_user)::int)
+    ORDER BY j.priority DESC, j.created_at
     LIMIT 1
     FOR UPDATE SKIP LOCKED
 )
 UPDATE download_jobs
 SET status = 'processing',
     attempts = attempts + 1,
+    next_retry_at = NULL,
     started_at = COALESCE(started_at, NOW()),
     updated_at = NOW()
 WHERE id IN (SELECT id FROM cte)
 RETURNING *;
 
+-- LockDownloadDequeue serializes the DequeueDownloadJob calls that enforce
+-- max_per_user until the calling transaction ends. Without it two workers can
+-- both count a user below the cap and each claim one of their jobs.
+-- name: LockDownloadDequeue :exec
+SELECT pg_advisory_xact_lock(hashtext('download-dequeue'));
+
 -- MarkDownloadJobSucceeded stores paths and marks job done.
 -- name: MarkDownloadJobSucceeded :exec
 UPDATE download_jobs
@@ -54,18 +75,31 @@ SET status = 'succeeded',
     updated_at = NOW(),
     spool_dir = sqlc.arg(spool_dir),
     info_json_path = sqlc.arg(info_json_path),
-    last_error = NULL
+    last_error = NULL,
+    error_code = NULL
 WHERE id = sqlc.arg(id);
 
 -- MarkDownloadJobFailed stores error and marks job failed.
+-- error_code is a classified failure reason (see ytdlp.ClassifyError), or NULL.
 -- name: MarkDownloadJobFailed :exec
 UPDATE download_jobs
 SET status = 'failed',
     finished_at = NOW(),
     updated_at = NOW(),
-    last_error = sqlc.arg(last_error)
+    last_error = sqlc.arg(last_error),
+    error_code = sqlc.narg(error_code)::text
 WHERE id = sqlc.arg(id);
 
+-- CountCookieBlockedDownloadJobs counts a user's failed, unarchived download
+-- jobs whose error_code is one of the given cookie-fixable codes.
+-- name: CountCookieBlockedDownloadJobs :one
+SELECT COUNT(*)::bigint
+FROM download_jobs
+WHERE archived_by = sqlc.arg(archived_by)
+  AND status = 'failed'
+  AND archived = FALSE
+  AND error_code = ANY(sqlc.arg(error_codes)::text[]);
+
 -- EnqueueIngestJob inserts a new ingest job from a download job.
 -- name: EnqueueIngestJob :one
 INSERT INTO ingest_jobs (
@@ -80,34 +114,53 @@ RETURNING *;
 
 -- RecoverStuckIngestJobs resets orphaned "processing" jobs back to "queued" on service startup.
 -- Jobs stuck in "processing" for more than the timeout are assumed to have been orphaned by a crash.
+-- Only jobs with attempts left under max_attempts are re-queued; each waits out
+-- its backoff (see db.RetryPolicy.Delay) before it can be dequeued again.
+-- Jobs running a medium or large Whisper model get a longer timeout, since a
+-- busy host can delay their heartbeat.
 -- name: RecoverStuckIngestJobs :exec
 UPDATE ingest_jobs
 SET status = 'queued',
+    next_retry_at = NOW() + LEAST(
+        sqlc.arg(cap_seconds)::int,
+        CASE WHEN sqlc.arg(backoff_exponential)::bool
+             THEN sqlc.arg(base_seconds)::int * power(2, LEAST(GREATEST(attempts - 1, 0), 30))
+             ELSE sqlc.arg(base_seconds)::int * GREATEST(attempts, 1)
+        END
+    ) * INTERVAL '1 second',
     updated_at = NOW()
 WHERE status = 'processing'
-  AND updated_at < NOW() - INTERVAL '5 minutes';
+  AND updated_at < NOW() - CASE
+      WHEN whisper_model LIKE 'medium%' OR whisper_model LIKE 'large%' THEN INTERVAL '20 minutes'
+      ELSE INTERVAL '5 minutes'
+  END
+  AND attempts < sqlc.arg(max_attempts)::int;
 
--- FailExcessiveRetryIngestJobs permanently fails jobs that have been retried too many times.
+-- FailExcessiveRetryIngestJobs permanently fails jobs that have used up their
+-- max_attempts, whether queued or orphaned in "processing".
 -- This prevents zombie jobs from wasting workers indefinitely.
 -- name: FailExcessiveRetryIngestJobs :execrows
 UPDATE ingest_jobs
 SET status = 'failed',
     last_error = 'exceeded maximum retry attempts',
+    next_retry_at = NULL,
     finished_at = NOW(),
     updated_at = NOW()
-WHERE status = 'queued'
-  AND attempts >= 5;
+WHERE (status = 'queued' OR (status = 'processing' AND updated_at < NOW() - INTERVAL '5 minutes'))
+  AND attempts >= sqlc.arg(max_attempts)::int;
 
 -- DequeueIngestJob claims one queued ingest job and returns needed info.
 -- Returns video_id for asset regeneration jobs (NULL for normal ingest).
--- Skips jobs that have already been retried too many times.
+-- Skips jobs that have already been retried max_attempts times and jobs still
+-- waiting out a retry backoff.
 -- name: DequeueIngestJob :one
 WITH cte AS (
     SELECT ij.id
     FROM ingest_jobs ij
     JOIN download_jobs dj ON dj.id = ij.download_job_id
     WHERE ij.status = 'queued'
-      AND ij.attempts < 5
+      AND ij.attempts < sqlc.arg(max_attempts)::int
+      AND (ij.next_retry_at IS NULL OR ij.next_retry_at <= NOW())
     ORDER BY
       -- Real ingests (fresh downloads with a spool/info.json) take priority over
       -- background asset-regeneration jobs, so a large regen batch never starves
@@ -122,6 +175,7 @@ WITH cte AS (
 UPDATE ingest_jobs AS ij
 SET status = 'processing',
     attempts = ij.attempts + 1,
+    next_retry_at = NULL,
     started_at = COALESCE(ij.started_at, NOW()),
     updated_at = NOW()
 FROM download_jobs AS dj
@@ -137,7 +191,11 @@ RETURNING
     dj.info_json_path AS info_json_path,
     dj.video_id AS video_id,
     ij.asset_scope AS asset_scope,
-    dj.extra_args AS extra_args;
+    ij.thumbnail_at AS thumbnail_at,
+    ij.whisper_model AS whisper_model,
+    ij.whisper_language AS whisper_language,
+    dj.extra_args AS extra_args,
+    dj.metadata_only AS metadata_only;
 
 -- HeartbeatIngestJob touches updated_at to prevent the recovery goroutine from
 -- resetting a long-running job back to "queued" while it is still being processed.
@@ -172,25 +230,67 @@ SET video_id = sqlc.arg(video_id),
     updated_at = NOW()
 WHERE id = sqlc.arg(id);
 
--- RetryDownloadJob resets a job to queued status for retry.
+-- RetryDownloadJob resets a job to queued status for a manual retry. The
+-- attempt count starts over and the job can be dequeued immediately.
 -- name: RetryDownloadJob :exec
 UPDATE download_jobs
 SET status = 'queued',
+    attempts = 0,
+    next_retry_at = NULL,
     last_error = NULL,
+    error_code = NULL,
     started_at = NULL,
     finished_at = NULL,
     process_pid = NULL,
     updated_at = NOW()
 WHERE id = sqlc.arg(id);
 
+-- ScheduleDownloadJobRetry re-queues a failed download after delay_seconds,
+-- keeping the error so the job list shows why it is waiting. Only a job still
+-- "processing" is re-queued, so a job cancelled mid-download stays cancelled.
+-- name: ScheduleDownloadJobRetry :execrows
+UPDATE download_jobs
+SET status = 'queued',
+    next_retry_at = NOW() + sqlc.arg(delay_seconds)::int * INTERVAL '1 second',
+    last_error = sqlc.arg(last_error),
+    error_code = NULL,
+    process_pid = NULL,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'processing';
+
 -- RecoverStuckDownloadJobs resets orphaned "processing" jobs back to "queued" on service startup.
 -- Jobs stuck in "processing" for more than the timeout are assumed to have been orphaned by a crash or restart.
+-- Backoff and max_attempts work as in RecoverStuckIngestJobs.
 -- name: RecoverStuckDownloadJobs :exec
 UPDATE download_jobs
 SET status = 'queued',
+    next_retry_at = NOW() + LEAST(
+        sqlc.arg(cap_seconds)::int,
+        CASE WHEN sqlc.arg(backoff_exponential)::bool
+             THEN sqlc.arg(base_seconds)::int * power(2, LEAST(GREATEST(attempts - 1, 0), 30))
+             ELSE sqlc.arg(base_seconds)::int * GREATEST(attempts, 1)
+        END
+    ) * INTERVAL '1 second',
+    process_pid = NULL,
     updated_at = NOW()
 WHERE status = 'processing'
-  AND updated_at < NOW() - INTERVAL '5 minutes';
+  AND updated_at < NOW() - INTERVAL '5 minutes'
+  AND attempts < sqlc.arg(max_attempts)::int;
+
+-- FailExcessiveRetryDownloadJobs permanently fails orphaned "processing"
+-- download jobs that have used up their max_attempts.
+-- name: FailExcessiveRetryDownloadJobs :execrows
+UPDATE download_jobs
+SET status = 'failed',
+    last_error = 'exceeded maximum retry attempts',
+    next_retry_at = NULL,
+    finished_at = NOW(),
+    process_pid = NULL,
+    updated_at = NOW()
+WHERE status = 'processing'
+  AND updated_at < NOW() - INTERVAL '5 minutes'
+  AND attempts >= sqlc.arg(max_attempts)::int;
 
 -- UpdateDownloadJobPID stores the process ID of the running download.
 -- name: UpdateDownloadJobPID :exec
@@ -209,6 +309,54 @@ SET status = 'failed',
     updated_at = NOW()
 WHERE id = sqlc.arg(id);
 
+-- PrioritizeDownloadJob moves a queued job to the front of the queue by
+-- raising its priority above every other queued job, whoever queued it, so
+-- callers must restrict it to admins. Returns no rows when the job is not
+-- queued.
+-- name: PrioritizeDownloadJob :one
+UPDATE download_jobs
+SET priority = (
+        SELECT COALESCE(MAX(q.priority), 0) + 1
+        FROM download_jobs q
+        WHERE q.status = 'queued' AND q.id <> sqlc.arg(id)
+    ),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'queued'
+RETURNING priority;
+
+-- GetDownloadJobQueuePosition returns the 1-based position of a queued job in
+-- dequeue order (priority DESC, created_at).
+-- name: GetDownloadJobQueuePosition :one
+SELECT (COUNT(q.id) + 1)::bigint AS position
+FROM download_jobs t
+LEFT JOIN download_jobs q
+    ON q.status = 'queued'
+   AND q.id <> t.id
+   AND (q.priority > t.priority OR (q.priority = t.priority AND q.created_at < t.created_at))
+WHERE t.id = sqlc.arg(id)
+GROUP BY t.id;
+
+-- CancelQueuedDownloadJobsForUser cancels every queued (not yet claimed)
+-- download job submitted by a user, returning the number of jobs cancelled.
+-- name: CancelQueuedDownloadJobsForUser :execrows
+UPDATE download_jobs
+SET status = 'failed',
+    last_error = 'Cancelled by user',
+    finished_at = NOW(),
+    process_pid = NULL,
+    updated_at = NOW()
+WHERE archived_by = sqlc.arg(archived_by)
+  AND status = 'queued';
+
+-- ListProcessingDownloadJobsForUser returns a user's running download jobs
+-- with their process IDs, so they can be signalled before being cancelled.
+-- name: ListProcessingDownloadJobsForUser :many
+SELECT id, process_pid
+FROM download_jobs
+WHERE archived_by = sqlc.arg(archived_by)
+  AND status = 'processing';
+
 -- GetDownloadJobPID retrieves the process ID for a job.
 -- name: GetDownloadJobPID :one
 SELECT process_pid
@@ -276,6 +424,7 @@ FROM new_ingest_job, new_download_job;
 
 -- EnqueueAssetRegenerationJob creates a download + ingest job pair for regenerating assets.
 -- asset_scope: NULL = all assets, or one of 'thumbnail', 'preview', 'seek', 'waveform'.
+-- thumbnail_at pins the thumbnail frame (seconds); negative clears the pin, NULL keeps it.
 -- name: EnqueueAssetRegenerationJob :one
 WITH new_download_job AS (
     INSERT INTO download_jobs (
@@ -299,12 +448,18 @@ new_ingest_job AS (
     INSERT INTO ingest_jobs (
         download_job_id,
         status,
-        asset_scope
+        asset_scope,
+        thumbnail_at,
+        whisper_model,
+        whisper_language
     )
     SELECT
         new_download_job.id,
         'queued',
-        sqlc.narg(asset_scope)::text
+        sqlc.narg(asset_scope)::text,
+        sqlc.narg(thumbnail_at)::double precision,
+        sqlc.narg(whisper_model)::text,
+        sqlc.narg(whisper_language)::text
     FROM new_download_job
     RETURNING *
 )
@@ -316,18 +471,23 @@ FROM new_ingest_job, new_download_job;
 
 -- EnqueuePlaylistJob inserts a parent "playlist" job. The downloader expands it
 -- into child video jobs (see EnqueueChildDownloadJobs) rather than downloading.
+-- extra_args are used for enumeration and copied to every child job, as is metadata_only.
 -- name: EnqueuePlaylistJob :one
 INSERT INTO download_jobs (
     url,
     archived_by,
     status,
-    kind
+    kind,
+    extra_args,
+    metadata_only
 )
 VALUES (
     sqlc.arg(url),
     sqlc.arg(archived_by),
     'queued',
-    'playlist'
+    'playlist',
+    sqlc.arg(extra_args)::text[],
+    sqlc.arg(metadata_only)
 )
 RETURNING *;
 
@@ -335,8 +495,8 @@ RETURNING *;
 -- all linked to a parent playlist job. Each insert fires the download_jobs
 -- NOTIFY trigger, so existing downloader workers pick them up unchanged.
 -- name: EnqueueChildDownloadJobs :execrows
-INSERT INTO download_jobs (url, archived_by, status, kind, parent_job_id)
-SELECT u, sqlc.arg(archived_by), 'queued', 'video', sqlc.arg(parent_job_id)
+INSERT INTO download_jobs (url, archived_by, status, kind, parent_job_id, extra_args, metadata_only)
+SELECT u, sqlc.arg(archived_by), 'queued', 'video', sqlc.arg(parent_job_id), sqlc.arg(extra_args)::text[], sqlc.arg(metadata_only)
 FROM unnest(sqlc.arg(urls)::text[]) AS u;
 
 -- CompletePlaylistJob marks a playlist parent job done after fan-out and records
@@ -350,3 +510,210 @@ SET status = 'succeeded',
     batch_label = sqlc.arg(batch_label),
     last_error = NULL
 WHERE id = sqlc.arg(id);
+
+-- CountVideosByChannel returns how many archived videos belong to a channel.
+-- name: CountVideosByChannel :one
+SELECT COUNT(*) FROM videos WHERE channel_id = sqlc.arg(channel_id);
+
+-- EnqueueChannelAssetRegenerationBatch creates download + ingest job pairs (the
+-- same shape as EnqueueAssetRegenerationJob) for the next batch of a channel's
+-- videos, keyset-ordered by id. Returns the video IDs queued; pass the last one
+-- as after_id for the next batch (zero UUID for the first).
+-- name: EnqueueChannelAssetRegenerationBatch :many
+WITH batch AS (
+    SELECT v.id, v.src, v.archived_by
+    FROM videos v
+    WHERE v.channel_id = sqlc.arg(channel_id)
+      AND v.id > sqlc.arg(after_id)::uuid
+    ORDER BY v.id
+    LIMIT sqlc.arg(batch_size)
+),
+new_download_jobs AS (
+    INSERT INTO download_jobs (
+        url,
+        archived_by,
+        refresh,
+        status,
+        video_id
+    )
+    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+    FROM batch
+    RETURNING id, video_id
+),
+new_ingest_jobs AS (
+    INSERT INTO ingest_jobs (
+        download_job_id,
+        status,
+        asset_scope
+    )
+    SELECT new_download_jobs.id, 'queued', sqlc.narg(asset_scope)::text
+    FROM new_download_jobs
+    RETURNING download_job_id
+)
+SELECT new_download_jobs.video_id::uuid AS video_id
+FROM new_ingest_jobs
+JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+ORDER BY new_download_jobs.video_id;
+
+-- EnqueuePreviewBackfillBatch creates preview-scoped regeneration job pairs
+-- for the next batch of videos missing a preview (assets_status.preview =
+-- false), keyset-ordered by id. Videos that already have a pending preview or
+-- full regeneration job are skipped. Jobs are spread out by setting
+-- next_retry_at, which ingest workers wait for: the nth job of the batch
+-- becomes claimable (first_slot + n - 1) * interval_seconds from now. Returns
+-- the video IDs queued; pass the last one as after_id for the next batch.
+-- name: EnqueuePreviewBackfillBatch :many
+WITH batch AS (
+    SELECT
+        v.id,
+        v.src,
+        v.archived_by,
+        ROW_NUMBER() OVER (ORDER BY v.id) AS slot
+    FROM videos v
+    WHERE v.id > sqlc.arg(after_id)::uuid
+      AND v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+      AND v.assets_status @> '{"preview": false}'::jsonb
+      AND NOT EXISTS (
+          SELECT 1
+          FROM ingest_jobs ij
+          JOIN download_jobs dj ON dj.id = ij.download_job_id
+          WHERE dj.video_id = v.id
+            AND ij.status IN ('queued', 'processing')
+            AND (ij.asset_scope IS NULL OR ij.asset_scope = 'preview')
+      )
+    ORDER BY v.id
+    LIMIT sqlc.arg(batch_size)
+),
+new_download_jobs AS (
+    INSERT INTO download_jobs (
+        url,
+        archived_by,
+        refresh,
+        status,
+        video_id
+    )
+    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+    FROM batch
+    RETURNING id, video_id
+),
+new_ingest_jobs AS (
+    INSERT INTO ingest_jobs (
+        download_job_id,
+        status,
+        asset_scope,
+        next_retry_at
+    )
+    SELECT
+        new_download_jobs.id,
+        'queued',
+        'preview',
+        NOW() + (sqlc.arg(first_slot)::int + batch.slot - 1) * sqlc.arg(interval_seconds)::float8 * INTERVAL '1 second'
+    FROM new_download_jobs
+    JOIN batch ON batch.id = new_download_jobs.video_id
+    RETURNING download_job_id
+)
+SELECT new_download_jobs.video_id::uuid AS video_id
+FROM new_ingest_jobs
+JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+ORDER BY new_download_jobs.video_id;
+
+-- ResetPreviewBackfillSchedule deletes the preview backfill jobs still waiting
+-- for their scheduled slot (queued, never attempted, next_retry_at in the
+-- future) together with their placeholder download jobs, so a new backfill run
+-- replaces the previous schedule instead of adding its rate on top of it.
+-- Returns the number of jobs removed.
+-- name: ResetPreviewBackfillSchedule :execrows
+DELETE FROM download_jobs dj
+USING ingest_jobs ij
+WHERE ij.download_job_id = dj.id
+  AND ij.status = 'queued'
+  AND ij.attempts = 0
+  AND ij.asset_scope = 'preview'
+  AND ij.next_retry_at > NOW();
+
+-- GetChannelAssetRegenerationProgress counts asset regeneration ingest jobs for
+-- a channel's videos created at or after since, grouped by status.
+-- Regeneration jobs are refresh download jobs that never had a spool dir.
+-- name: GetChannelAssetRegenerationProgress :one
+SELECT
+    COUNT(*) AS total_count,
+    COUNT(*) FILTER (WHERE ij.status = 'queued') AS queued_count,
+    COUNT(*) FILTER (WHERE ij.status = 'processing') AS processing_count,
+    COUNT(*) FILTER (WHERE ij.status = 'succeeded') AS succeeded_count,
+    COUNT(*) FILTER (WHERE ij.status = 'failed') AS failed_count
+FROM ingest_jobs ij
+JOIN download_jobs dj ON dj.id = ij.download_job_id
+JOIN videos v ON v.id = dj.video_id
+WHERE v.channel_id = sqlc.arg(channel_id)
+  AND dj.refresh = true
+  AND dj.spool_dir IS NULL
+  AND ij.created_at >= sqlc.arg(since);
+
+-- EnqueueCaptionBackfillBatch queues a "captions" asset regeneration job for
+-- up to batch_size caption-less videos with id > after_id, optionally limited
+-- to one channel. Videos that already have a captions job queued or running
+-- are skipped, as are videos whose last captions job failed or produced no
+-- transcript after retry_before. Returns the queued video IDs in order.
+-- name: EnqueueCaptionBackfillBatch :many
+WITH batch AS (
+    SELECT v.id, v.src, v.archived_by
+    FROM videos v
+    WHERE v.id > sqlc.arg(after_id)::uuid
+      AND (sqlc.narg('channel_id')::text IS NULL OR v.channel_id = sqlc.narg('channel_id'))
+      AND NOT EXISTS (SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id)
+      AND NOT EXISTS (
+          SELECT 1
+          FROM ingest_jobs ij
+          JOIN download_jobs dj ON dj.id = ij.download_job_id
+          WHERE dj.video_id = v.id
+            AND ij.asset_scope = 'captions'
+            AND (
+                ij.status IN ('queued', 'processing')
+                OR COALESCE(ij.finished_at, ij.updated_at) >= sqlc.arg(retry_before)
+            )
+      )
+    ORDER BY v.id
+    LIMIT sqlc.arg(batch_size)
+),
+new_download_jobs AS (
+    INSERT INTO download_jobs (
+        url,
+        archived_by,
+        refresh,
+        status,
+        video_id
+    )
+    SELECT batch.src, batch.archived_by, true, 'succeeded', batch.id
+    FROM batch
+    RETURNING id, video_id
+),
+new_ingest_jobs AS (
+    INSERT INTO ingest_jobs (
+        download_job_id,
+        status,
+        asset_scope
+    )
+    SELECT new_download_jobs.id, 'queued', 'captions'
+    FROM new_download_jobs
+    RETURNING download_job_id
+)
+SELECT new_download_jobs.video_id::uuid AS video_id
+FROM new_ingest_jobs
+JOIN new_download_jobs ON new_download_jobs.id = new_ingest_jobs.download_job_id
+ORDER BY new_download_jobs.video_id;
+
+-- GetCaptionBackfillProgress counts "captions" regeneration jobs created at or
+-- after since, grouped by status.
+-- name: GetCaptionBackfillProgress :one
+SELECT
+    COUNT(*) AS total_count,
+    COUNT(*) FILTER (WHERE ij.status = 'queued') AS queued_count,
+    COUNT(*) FILTER (WHERE ij.status = 'processing') AS processing_count,
+    COUNT(*) FILTER (WHERE ij.status = 'succeeded') AS succeeded_count,
+    COUNT(*) FILTER (WHERE ij.status = 'failed') AS failed_count
+FROM ingest_jobs ij
+JOIN download_jobs dj ON dj.id = ij.download_job_id
+WHERE ij.asset_scope = 'captions'
+  AND dj.refresh = true
+  AND dj.spool_dir IS NULL
+  AND ij.created_at >= sqlc.arg(since);
@@ -16,7 +16,8 @@ INSERT INTO markers (
     color,
     marker_type,
     duration,
-    created_by
+    created_by,
+    source
 ) VALUES (
     sqlc.arg(video_id),
     sqlc.arg(timestamp),
@@ -25,7 +26,8 @@ INSERT INTO markers (
     sqlc.arg(color),
     sqlc.arg(marker_type),
     sqlc.arg(duration),
-    sqlc.arg(created_by)
+    sqlc.arg(created_by),
+    sqlc.arg(source)
 ) RETURNING *;
 
 -- name: UpdateMarker :one
@@ -47,3 +49,10 @@ WHERE id = sqlc.arg(id);
 -- name: DeleteMarkersByVideo :exec
 DELETE FROM markers
 WHERE video_id = sqlc.arg(video_id);
+
+-- DeleteMarkersByVideoSource deletes a video's markers from one source, e.g.
+-- its SponsorBlock markers before they are re-imported.
+-- name: DeleteMarkersByVideoSource :exec
+DELETE FROM markers
+WHERE video_id = sqlc.arg(video_id)
+  AND source = sqlc.arg(source);
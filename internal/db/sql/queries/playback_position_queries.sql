@@ -1,6 +1,6 @@
 -- GetPlaybackPosition retrieves the last playback position for a user/video
 -- name: GetPlaybackPosition :one
-SELECT position_seconds, updated_at
+SELECT position_seconds, updated_at, watched_at
 FROM playback_positions
 WHERE user_id = sqlc.arg(user_id)
   AND video_id = sqlc.arg(video_id);
@@ -14,3 +14,34 @@ DO UPDATE SET
     position_seconds = EXCLUDED.position_seconds,
     updated_at = CURRENT_TIMESTAMP;
 
+
+-- GetVideoPlaybackState returns a video's duration with the user's saved
+-- position and watched state, if any. No row means the video does not exist.
+-- name: GetVideoPlaybackState :one
+SELECT
+    v.duration_seconds,
+    pp.position_seconds,
+    pp.updated_at,
+    pp.watched_at
+FROM videos v
+LEFT JOIN playback_positions pp
+    ON pp.video_id = v.id
+   AND pp.user_id = sqlc.arg(user_id)
+WHERE v.id = sqlc.arg(video_id);
+
+-- SetPlaybackWatched marks a video watched or unwatched for a user. Either
+-- way the resume position goes back to the start.
+-- name: SetPlaybackWatched :exec
+INSERT INTO playback_positions (user_id, video_id, position_seconds, updated_at, watched_at)
+VALUES (
+    sqlc.arg(user_id),
+    sqlc.arg(video_id),
+    0,
+    CURRENT_TIMESTAMP,
+    CASE WHEN sqlc.arg(watched)::bool THEN CURRENT_TIMESTAMP END
+)
+ON CONFLICT (user_id, video_id)
+DO UPDATE SET
+    position_seconds = 0,
+    updated_at = CURRENT_TIMESTAMP,
+    watched_at = EXCLUDED.watched_at;
@@ -0,0 +1,22 @@
+-- ListChannelPlaylistVideos returns a channel's downloaded videos in upload
+-- order, oldest first, for continuous playback.
+-- name: ListChannelPlaylistVideos :many
+SELECT id
+FROM videos
+WHERE channel_id = sqlc.arg(channel_id)
+  AND NOT metadata_only
+  AND video_path IS NOT NULL
+ORDER BY upload_date ASC NULLS LAST, created_at, id
+LIMIT sqlc.arg(max_videos);
+
+-- ListPlaylistJobVideos returns the downloaded videos of a playlist download
+-- job's children, in the order the playlist was expanded.
+-- name: ListPlaylistJobVideos :many
+SELECT v.id
+FROM download_jobs j
+JOIN videos v ON v.id = j.video_id
+WHERE j.parent_job_id = sqlc.arg(parent_job_id)
+  AND NOT v.metadata_only
+  AND v.video_path IS NOT NULL
+ORDER BY j.created_at, j.id
+LIMIT sqlc.arg(max_videos);
@@ -6,11 +6,12 @@ VALUES (sqlc.arg(name), sqlc.arg(slug), sqlc.narg(color), sqlc.narg(created_by))
 ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name
 RETURNING *;
 
--- AddVideoTag links a tag to a video (idempotent).
+-- AddVideoTag links a tag to a video (idempotent). Adding a tag by hand that
+-- was suggested automatically keeps it through later re-tagging.
 -- name: AddVideoTag :exec
 INSERT INTO video_tags (video_id, tag_id, created_by)
 VALUES (sqlc.arg(video_id), sqlc.arg(tag_id), sqlc.narg(created_by))
-ON CONFLICT (video_id, tag_id) DO NOTHING;
+ON CONFLICT (video_id, tag_id) DO UPDATE SET auto = false;
 
 -- AddVideoTagToMany links one tag to many videos at once (idempotent). Drives
 -- the library bulk-tag action.
@@ -18,16 +19,17 @@ ON CONFLICT (video_id, tag_id) DO NOTHING;
 INSERT INTO video_tags (video_id, tag_id, created_by)
 SELECT v, sqlc.arg(tag_id), sqlc.narg(created_by)
 FROM unnest(sqlc.arg(video_ids)::uuid[]) AS v
-ON CONFLICT (video_id, tag_id) DO NOTHING;
+ON CONFLICT (video_id, tag_id) DO UPDATE SET auto = false;
 
 -- RemoveVideoTag unlinks a tag from a video.
 -- name: RemoveVideoTag :exec
 DELETE FROM video_tags
 WHERE video_id = sqlc.arg(video_id) AND tag_id = sqlc.arg(tag_id);
 
--- ListTagsForVideo returns a video's tags, alphabetically.
+-- ListTagsForVideo returns a video's tags, alphabetically. auto is set for
+-- tags suggested from the transcript.
 -- name: ListTagsForVideo :many
-SELECT t.id, t.name, t.slug, t.color
+SELECT t.id, t.name, t.slug, t.color, vt.auto
 FROM tags t
 JOIN video_tags vt ON vt.tag_id = t.id
 WHERE vt.video_id = sqlc.arg(video_id)
@@ -41,3 +43,23 @@ FROM tags t
 LEFT JOIN video_tags vt ON vt.tag_id = t.id
 GROUP BY t.id
 ORDER BY video_count DESC, t.name ASC;
+
+-- UpsertAutoTag returns the tag with slug, creating it when missing. Unlike
+-- UpsertTag it never renames an existing tag, so a user's casing survives.
+-- name: UpsertAutoTag :one
+INSERT INTO tags (name, slug)
+VALUES (sqlc.arg(name), sqlc.arg(slug))
+ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+RETURNING id;
+
+-- DeleteVideoAutoTags removes a video's automatically added tag links.
+-- name: DeleteVideoAutoTags :exec
+DELETE FROM video_tags
+WHERE video_id = sqlc.arg(video_id) AND auto;
+
+-- AddVideoAutoTag links a suggested tag to a video. A link the user already
+-- made is left as a manual tag.
+-- name: AddVideoAutoTag :exec
+INSERT INTO video_tags (video_id, tag_id, auto)
+VALUES (sqlc.arg(video_id), sqlc.arg(tag_id), true)
+ON CONFLICT (video_id, tag_id) DO NOTHING;
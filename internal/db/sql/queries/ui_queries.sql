@@ -95,6 +95,19 @@ ORDER BY
 LIMIT sqlc.arg(page_limit)
 OFFSET sqlc.arg(page_offset);
 
+-- ListRelatedVideos returns other videos from the same channel (when
+-- channel_id is given) or otherwise the same uploader, newest first.
+-- name: ListRelatedVideos :many
+SELECT *
+FROM videos
+WHERE id <> sqlc.arg(video_id)
+  AND (
+    (sqlc.narg('channel_id')::text IS NOT NULL AND channel_id = sqlc.narg('channel_id'))
+    OR (sqlc.narg('channel_id')::text IS NULL AND uploader = sqlc.narg('uploader'))
+  )
+ORDER BY upload_date DESC NULLS LAST, created_at DESC
+LIMIT sqlc.arg(page_limit);
+
 -- ListDistinctUploaders returns unique uploader names for filter dropdown
 -- name: ListDistinctUploaders :many
 SELECT DISTINCT uploader
@@ -111,6 +124,19 @@ WHERE tags IS NOT NULL AND array_length(tags, 1) > 0
 ORDER BY tag ASC
 LIMIT 200;
 
+-- ListScrapedTagCounts returns each distinct scraped (yt-dlp) tag with how
+-- many videos carry it, most-used first. Tags are matched exactly as stored,
+-- the same way the library tag filter matches them.
+-- name: ListScrapedTagCounts :many
+SELECT t.tag::text AS tag, COUNT(DISTINCT v.id)::bigint AS video_count
+FROM videos v
+CROSS JOIN LATERAL unnest(v.tags) AS t(tag)
+WHERE btrim(t.tag) <> ''
+GROUP BY t.tag
+HAVING COUNT(DISTINCT v.id) >= sqlc.arg(min_count)::bigint
+ORDER BY video_count DESC, tag ASC
+LIMIT sqlc.arg(page_limit);
+
 -- ListRecentVideos returns recent videos (by archive date)
 -- name: ListRecentVideos :many
 SELECT *
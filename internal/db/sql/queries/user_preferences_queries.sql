@@ -0,0 +1,39 @@
+-- name: GetUserExportFilenameTemplate :one
+SELECT export_filename_template
+FROM user_preferences
+WHERE user_id = $1;
+
+-- name: UpsertUserExportFilenameTemplate :exec
+INSERT INTO user_preferences (user_id, export_filename_template, updated_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(export_filename_template), NOW())
+ON CONFLICT (user_id)
+DO UPDATE SET export_filename_template = EXCLUDED.export_filename_template,
+              updated_at = NOW();
+
+-- name: GetUserCaptionStyle :one
+SELECT caption_font_size, caption_color, caption_background, caption_position
+FROM user_preferences
+WHERE user_id = $1;
+
+-- name: UpsertUserCaptionStyle :exec
+INSERT INTO user_preferences (user_id, caption_font_size, caption_color, caption_background, caption_position, updated_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(caption_font_size), sqlc.arg(caption_color), sqlc.arg(caption_background), sqlc.arg(caption_position), NOW())
+ON CONFLICT (user_id)
+DO UPDATE SET caption_font_size = EXCLUDED.caption_font_size,
+              caption_color = EXCLUDED.caption_color,
+              caption_background = EXCLUDED.caption_background,
+              caption_position = EXCLUDED.caption_position,
+              updated_at = NOW();
+
+-- name: GetUserCookieDomainFilter :one
+SELECT cookie_domain_allowlist, cookie_domain_blocklist
+FROM user_preferences
+WHERE user_id = $1;
+
+-- name: UpsertUserCookieDomainFilter :exec
+INSERT INTO user_preferences (user_id, cookie_domain_allowlist, cookie_domain_blocklist, updated_at)
+VALUES (sqlc.arg(user_id), sqlc.arg(cookie_domain_allowlist)::text[], sqlc.arg(cookie_domain_blocklist)::text[], NOW())
+ON CONFLICT (user_id)
+DO UPDATE SET cookie_domain_allowlist = EXCLUDED.cookie_domain_allowlist,
+              cookie_domain_blocklist = EXCLUDED.cookie_domain_blocklist,
+              updated_at = NOW();
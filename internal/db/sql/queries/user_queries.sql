@@ -8,6 +8,8 @@ INSERT INTO users (
     password,
     user_name,
     role,
+    enabled,
+    pending_approval,
     created_at,
     updated_at,
     deleted_at
@@ -18,6 +20,8 @@ VALUES (
     sqlc.arg(password),
     sqlc.arg(user_name),
     sqlc.arg(role),
+    NOT sqlc.arg(pending_approval)::boolean,
+    sqlc.arg(pending_approval)::boolean,
     NOW(),
     NOW(),
     NULL
@@ -53,9 +57,11 @@ SELECT COUNT(*)::bigint FROM users WHERE deleted_at IS NULL;
 SELECT COUNT(*)::bigint FROM users WHERE deleted_at IS NULL AND enabled = TRUE AND role = 'admin';
 
 -- SetUserEnabled updates a user's enabled flag
+-- Enabling a user also clears any pending registration approval.
 -- name: SetUserEnabled :exec
 UPDATE users
 SET enabled = sqlc.arg(enabled),
+    pending_approval = pending_approval AND NOT sqlc.arg(enabled),
     updated_at = NOW()
 WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
 
@@ -66,6 +72,24 @@ SET role = sqlc.arg(role),
     updated_at = NOW()
 WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
 
+-- SetUserDownloadMaxHeight sets or clears (NULL = use the role cap) a user's download resolution cap
+-- name: SetUserDownloadMaxHeight :exec
+UPDATE users
+SET download_max_height = sqlc.narg(download_max_height),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL;
+
+-- GetUserDownloadCap returns a user's role and download cap override, with the
+-- role caps from instance settings (0 when there is no settings row).
+-- name: GetUserDownloadCap :one
+SELECT u.role,
+       u.download_max_height,
+       COALESCE(s.user_download_max_height, 0)::int AS user_max_height,
+       COALESCE(s.admin_download_max_height, 0)::int AS admin_max_height
+FROM users u
+LEFT JOIN instance_settings s ON s.id = 1
+WHERE u.id = sqlc.arg(id);
+
 -- UsernameTaken checks if a username is already taken 
 -- name: UsernameTaken :one
 SELECT EXISTS (
@@ -0,0 +1,24 @@
+-- ListVideoChapters returns a video's stored chapters in playback order.
+-- name: ListVideoChapters :many
+SELECT *
+FROM video_chapters
+WHERE video_id = sqlc.arg(video_id)
+ORDER BY position;
+
+-- DeleteVideoChapters removes a video's stored chapters before they are
+-- replaced.
+-- name: DeleteVideoChapters :exec
+DELETE FROM video_chapters
+WHERE video_id = sqlc.arg(video_id);
+
+-- InsertVideoChapters stores a video's chapters; the arrays are parallel,
+-- one element per chapter.
+-- name: InsertVideoChapters :exec
+INSERT INTO video_chapters (video_id, position, start_seconds, end_seconds, title, source)
+SELECT sqlc.arg(video_id), c.position, c.start_seconds, c.end_seconds, c.title, sqlc.arg(source)
+FROM unnest(
+    sqlc.arg(positions)::int[],
+    sqlc.arg(starts)::double precision[],
+    sqlc.arg(ends)::double precision[],
+    sqlc.arg(titles)::text[]
+) AS c(position, start_seconds, end_seconds, title);
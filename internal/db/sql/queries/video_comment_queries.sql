@@ -46,6 +46,8 @@ FROM jsonb_array_elements(sqlc.arg(comments_json)::jsonb) AS c
 WHERE COALESCE(NULLIF(c->>'id', ''), '') <> ''
 ON CONFLICT (video_id, source, comment_id)
 DO UPDATE SET
+    last_seen_at = NOW(),
+    deleted_at = NULL,
     parent_id = EXCLUDED.parent_id,
     author = EXCLUDED.author,
     author_id = EXCLUDED.author_id,
@@ -57,6 +59,30 @@ DO UPDATE SET
     raw = EXCLUDED.raw,
     updated_at = NOW();
 
+-- ArchiveEditedVideoComments copies the stored version of every comment in a
+-- yt-dlp comment array whose text has changed into video_comment_edits. Run it
+-- before UpsertVideoCommentsFromJSON overwrites the text.
+-- name: ArchiveEditedVideoComments :execrows
+INSERT INTO video_comment_edits (video_comment_id, text, raw, replaced_at)
+SELECT vc.id, vc.text, vc.raw, NOW()
+FROM jsonb_array_elements(sqlc.arg(comments_json)::jsonb) AS c
+JOIN video_comments vc
+  ON vc.video_id = sqlc.arg(video_id)::uuid
+ AND vc.source = sqlc.arg(source)::text
+ AND vc.comment_id = c->>'id'
+WHERE vc.text IS DISTINCT FROM COALESCE(NULLIF(c->>'text', ''), NULLIF(c->>'content', ''), NULLIF(c->>'comment', ''));
+
+-- MarkMissingVideoCommentsDeleted flags stored comments for a video/source that
+-- are absent from the latest fetch (seen_ids). Already-flagged rows keep their
+-- original deleted_at.
+-- name: MarkMissingVideoCommentsDeleted :execrows
+UPDATE video_comments
+SET deleted_at = NOW()
+WHERE video_id = sqlc.arg(video_id)
+  AND source = sqlc.arg(source)
+  AND deleted_at IS NULL
+  AND NOT (comment_id = ANY(sqlc.arg(seen_ids)::text[]));
+
 -- CountVideoComments returns total comments ingested for a video.
 -- name: CountVideoComments :one
 SELECT COUNT(*)
@@ -79,6 +105,8 @@ SELECT c.id, c.video_id, c.source, c.comment_id, c.parent_id, c.author, c.author
        COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
        COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
        COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited,
        COALESCE((SELECT count(*) FROM video_comments rc
                  WHERE rc.video_id = c.video_id AND rc.parent_id = c.comment_id), 0)::bigint AS reply_count
 FROM video_comments c
@@ -99,7 +127,9 @@ SELECT c.id, c.video_id, c.source, c.comment_id, c.parent_id, c.author, c.author
        COALESCE((c.raw->>'is_pinned')::boolean, false)::boolean         AS is_pinned,
        COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
        COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
-       COALESCE(c.raw->>'_time_text', '')::text                     AS time_text
+       COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited
 FROM video_comments c
 WHERE c.video_id = sqlc.arg(video_id)
   AND c.parent_id = sqlc.arg(parent_comment_id)::text
@@ -121,6 +151,8 @@ SELECT c.id, c.video_id, c.source, c.comment_id, c.parent_id, c.author, c.author
        COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
        COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
        COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited,
        COALESCE((SELECT count(*) FROM video_comments rc
                  WHERE rc.video_id = c.video_id AND rc.parent_id = c.comment_id), 0)::bigint AS reply_count
 FROM video_comments c
@@ -0,0 +1,15 @@
+-- GetVideoHLSKey returns the key of a video's encrypted HLS rendition. No row
+-- means none was issued.
+-- name: GetVideoHLSKey :one
+SELECT key
+FROM video_hls_keys
+WHERE video_id = sqlc.arg(video_id);
+
+-- EnsureVideoHLSKey stores key for a video unless it already has one, and
+-- returns the stored key either way.
+-- name: EnsureVideoHLSKey :one
+INSERT INTO video_hls_keys (video_id, key)
+VALUES (sqlc.arg(video_id), sqlc.arg(key))
+ON CONFLICT (video_id)
+DO UPDATE SET key = video_hls_keys.key
+RETURNING key;
@@ -0,0 +1,22 @@
+-- GetVideoNote returns the user's note on a video. No row means no note.
+-- name: GetVideoNote :one
+SELECT body, updated_at
+FROM video_notes
+WHERE user_id = sqlc.arg(user_id)
+  AND video_id = sqlc.arg(video_id);
+
+-- UpsertVideoNote saves the user's note on a video.
+-- name: UpsertVideoNote :one
+INSERT INTO video_notes (user_id, video_id, body)
+VALUES (sqlc.arg(user_id), sqlc.arg(video_id), sqlc.arg(body))
+ON CONFLICT (user_id, video_id)
+DO UPDATE SET
+    body = EXCLUDED.body,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING body, updated_at;
+
+-- DeleteVideoNote removes the user's note on a video.
+-- name: DeleteVideoNote :exec
+DELETE FROM video_notes
+WHERE user_id = sqlc.arg(user_id)
+  AND video_id = sqlc.arg(video_id);
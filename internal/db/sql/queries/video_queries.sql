@@ -95,10 +95,13 @@ WHERE src = $1;
 -- FilterExistingVideoIDs returns, from the given candidate ids, the subset that
 -- already exist as videos. Used to skip re-downloading already-archived videos
 -- when expanding a playlist/channel (video ids are deterministic UUIDv5s).
+-- Metadata-only videos count as archived only for a metadata-only expansion,
+-- so a full playlist archive downloads their files.
 -- name: FilterExistingVideoIDs :many
 SELECT id
 FROM videos
-WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+  AND (sqlc.arg(metadata_only)::bool OR NOT metadata_only);
 
 -- ClaimVideosForCommentCatchup atomically claims up to batch_size videos that
 -- have no comments (and weren't checked in the last 30 days), marking
@@ -151,6 +154,7 @@ AND (
     OR NOT (assets_status ?& array['thumbnail','preview','waveform','file_hash','seek','faststart'])
     OR assets_status @> '{"thumbnail": false}'::jsonb
     OR assets_status @> '{"preview": false}'::jsonb
+    OR assets_status @> '{"preview_sprite": false}'::jsonb
     OR assets_status @> '{"waveform": false}'::jsonb
     OR assets_status @> '{"file_hash": false}'::jsonb
     OR assets_status @> '{"seek": false}'::jsonb
@@ -173,10 +177,12 @@ LIMIT $1;
 
 -- ListVideosMissingVideoPath returns videos whose video_path is unset, for
 -- disk-discovery recovery of ingests that never completed (file on disk, no path).
+-- Metadata-only videos have no file by design and are skipped.
 -- name: ListVideosMissingVideoPath :many
 SELECT id::text AS id
 FROM videos
-WHERE video_path IS NULL OR btrim(video_path) = ''
+WHERE (video_path IS NULL OR btrim(video_path) = '')
+  AND NOT metadata_only
 ORDER BY updated_at ASC
 LIMIT $1;
 
@@ -198,6 +204,14 @@ WHERE video_path IS NOT NULL AND btrim(video_path) <> ''
 AND assets_status ? '_error_count'
 AND (assets_status->>'_error_count')::int > 0;
 
+-- CountVideosMissingPreview returns the number of videos whose preview failed
+-- or was skipped (assets_status.preview = false).
+-- name: CountVideosMissingPreview :one
+SELECT COUNT(*)
+FROM videos
+WHERE video_path IS NOT NULL AND btrim(video_path) <> ''
+AND assets_status @> '{"preview": false}'::jsonb;
+
 -- ClearVideoAssetErrors resets error tracking for a single video so catchup retries it.
 -- name: ClearVideoAssetErrors :exec
 UPDATE videos
@@ -227,6 +241,14 @@ SET thumbnail_path = sqlc.arg(thumbnail_path),
     updated_at = NOW()
 WHERE id = sqlc.arg(id);
 
+-- SetVideoMetadataOnly flags a video as archived without its media file, or
+-- clears the flag once the full file has been ingested.
+-- name: SetVideoMetadataOnly :exec
+UPDATE videos
+SET metadata_only = sqlc.arg(metadata_only),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
 -- UpdateVideoFileHashAndSize updates file_hash + file_size for a video.
 -- name: UpdateVideoFileHashAndSize :exec
 UPDATE videos
@@ -251,9 +273,253 @@ WHERE video_path IS NOT NULL AND btrim(video_path) <> ''
 ORDER BY created_at DESC
 LIMIT sqlc.arg(max_count);
 
+-- UpdateVideoPHash stores the perceptual hash and per-frame hashes for a video.
+-- name: UpdateVideoPHash :exec
+UPDATE videos
+SET phash = sqlc.arg(phash),
+    phash_frames = sqlc.arg(phash_frames)::bigint[],
+    updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- UpdateVideoThumbnailPHash stores the perceptual hash of a video's thumbnail.
+-- name: UpdateVideoThumbnailPHash :exec
+UPDATE videos
+SET thumbnail_phash = sqlc.narg(thumbnail_phash),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- ListVideosNeedingPHash returns videos with a video_path but no phash, for
+-- backfill. Audio-only videos are skipped, as are videos whose last failed
+-- attempt is newer than retry_before.
+-- name: ListVideosNeedingPHash :many
+SELECT v.id, v.video_path, v.duration_seconds
+FROM videos v
+LEFT JOIN video_phash_attempts a ON a.video_id = v.id
+WHERE v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+  AND v.phash IS NULL
+  AND NOT COALESCE(v.assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb
+  AND (a.attempted_at IS NULL OR a.attempted_at < sqlc.arg(retry_before))
+ORDER BY v.created_at DESC
+LIMIT sqlc.arg(max_count);
+
+-- ListVideosNeedingAutoTags returns transcribed videos that have not been
+-- auto-tagged yet, for backfill.
+-- name: ListVideosNeedingAutoTags :many
+SELECT v.id
+FROM videos v
+WHERE v.auto_tagged_at IS NULL
+  AND EXISTS (SELECT 1 FROM video_transcripts vt WHERE vt.video_id = v.id)
+ORDER BY v.created_at DESC
+LIMIT sqlc.arg(max_count);
+
+-- MarkVideoAutoTagged records that a video's transcript keywords were tagged.
+-- name: MarkVideoAutoTagged :exec
+UPDATE videos SET auto_tagged_at = NOW() WHERE id = sqlc.arg(id);
+
+-- MarkVideoPHashAttempted records a failed perceptual hash attempt, holding
+-- the video back from the backfill until its retry delay has passed.
+-- name: MarkVideoPHashAttempted :exec
+INSERT INTO video_phash_attempts (video_id, attempted_at)
+VALUES (sqlc.arg(video_id), NOW())
+ON CONFLICT (video_id) DO UPDATE SET attempted_at = EXCLUDED.attempted_at;
+
+-- ListVideosNeedingThumbnailPHash returns videos with a thumbnail but no
+-- thumbnail_phash, for backfill. Audio-only and metadata-only videos are
+-- skipped, since their thumbnail is not a frame of the video, as are videos
+-- whose last failed attempt is newer than retry_before.
+-- name: ListVideosNeedingThumbnailPHash :many
+SELECT v.id, v.thumbnail_path
+FROM videos v
+LEFT JOIN video_thumbnail_phash_attempts a ON a.video_id = v.id
+WHERE v.thumbnail_phash IS NULL
+  AND v.thumbnail_path IS NOT NULL AND btrim(v.thumbnail_path) <> ''
+  AND v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+  AND NOT v.metadata_only
+  AND NOT COALESCE(v.assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb
+  AND (a.attempted_at IS NULL OR a.attempted_at < sqlc.arg(retry_before))
+ORDER BY v.created_at DESC
+LIMIT sqlc.arg(max_count);
+
+-- MarkVideoThumbnailPHashAttempted records a failed thumbnail hash attempt,
+-- holding the video back from the backfill until its retry delay has passed.
+-- name: MarkVideoThumbnailPHashAttempted :exec
+INSERT INTO video_thumbnail_phash_attempts (video_id, attempted_at)
+VALUES (sqlc.arg(video_id), NOW())
+ON CONFLICT (video_id) DO UPDATE SET attempted_at = EXCLUDED.attempted_at;
+
+-- CountVideosWithPHash returns how many videos have a perceptual hash.
+-- name: CountVideosWithPHash :one
+SELECT COUNT(*) FROM videos WHERE phash IS NOT NULL;
+
+-- ListNearDuplicateVideos returns pairs of videos whose combined perceptual
+-- hashes are within max_distance bits, skipping pairs whose durations differ
+-- by more than 2s or 2% (a re-encode keeps its length). It reads the pairs
+-- InsertVideoPHashPairs stored, so max_distance above the distance they were
+-- stored with finds nothing more.
+-- name: ListNearDuplicateVideos :many
+SELECT
+    a.id AS video_id,
+    a.title AS video_title,
+    a.file_hash AS video_file_hash,
+    a.phash_frames::bigint[] AS video_frames,
+    b.id AS other_id,
+    b.title AS other_title,
+    b.file_hash AS other_file_hash,
+    b.phash_frames::bigint[] AS other_frames,
+    p.distance::int AS distance
+FROM video_phash_pairs p
+JOIN videos a ON a.id = p.video_id
+JOIN videos b ON b.id = p.other_id
+WHERE p.distance <= sqlc.arg(max_distance)::int
+  AND (
+    a.duration_seconds IS NULL OR b.duration_seconds IS NULL
+    OR abs(a.duration_seconds - b.duration_seconds) <= GREATEST(2, a.duration_seconds * 0.02)
+  )
+ORDER BY p.distance ASC, p.video_id, p.other_id
+LIMIT sqlc.arg(max_count);
+
+-- DeleteVideoPHashPairs removes a video's near-duplicate pairs, before
+-- InsertVideoPHashPairs stores them again for a new hash.
+-- name: DeleteVideoPHashPairs :exec
+DELETE FROM video_phash_pairs
+WHERE video_id = sqlc.arg(video_id) OR other_id = sqlc.arg(video_id);
+
+-- InsertVideoPHashPairs stores the pairs of a video with every other hashed
+-- video within max_distance bits, for ListNearDuplicateVideos. Comparing one
+-- hash with the rest is a single scan, done as each hash is stored.
+-- name: InsertVideoPHashPairs :exec
+INSERT INTO video_phash_pairs (video_id, other_id, distance)
+SELECT LEAST(a.id, b.id), GREATEST(a.id, b.id), bit_count((a.phash # b.phash)::bit(64))
+FROM videos a
+JOIN videos b ON b.id <> a.id
+WHERE a.id = sqlc.arg(video_id)
+  AND a.phash IS NOT NULL
+  AND b.phash IS NOT NULL
+  AND bit_count((a.phash # b.phash)::bit(64)) <= sqlc.arg(max_distance)::int
+ON CONFLICT (video_id, other_id) DO UPDATE SET distance = EXCLUDED.distance;
+
+-- FindSimilarVideosByPHash returns other videos within max_distance bits of
+-- video_id, closest first. Whole-video hashes are compared when both videos
+-- have one, thumbnail hashes otherwise; the same duration guard as
+-- ListNearDuplicateVideos applies.
+-- name: FindSimilarVideosByPHash :many
+SELECT b.*
+FROM videos a
+JOIN videos b ON b.id <> a.id
+CROSS JOIN LATERAL (
+    SELECT CASE
+        WHEN a.phash IS NOT NULL AND b.phash IS NOT NULL
+            THEN bit_count((a.phash # b.phash)::bit(64))
+        ELSE bit_count((a.thumbnail_phash # b.thumbnail_phash)::bit(64))
+    END AS distance
+) d
+WHERE a.id = sqlc.arg(video_id)
+  AND d.distance <= sqlc.arg(max_distance)::int
+  AND (
+    a.duration_seconds IS NULL OR b.duration_seconds IS NULL
+    OR abs(a.duration_seconds - b.duration_seconds) <= GREATEST(2, a.duration_seconds * 0.02)
+  )
+ORDER BY d.distance ASC, b.created_at DESC
+LIMIT sqlc.arg(max_count);
+
 -- UpdateVideoAssetsStatus merges asset status flags into videos.assets_status.
 -- name: UpdateVideoAssetsStatus :exec
 UPDATE videos
 SET assets_status = COALESCE(assets_status, '{}'::jsonb) || sqlc.arg(assets_status)::asset_status_map,
     updated_at = NOW()
 WHERE id = sqlc.arg(id);
+
+-- GetCaptionCoverage counts videos with and without any transcript,
+-- optionally limited to one channel.
+-- name: GetCaptionCoverage :one
+SELECT
+    COUNT(*) AS total_count,
+    COUNT(*) FILTER (WHERE EXISTS (
+        SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id
+    )) AS captioned_count
+FROM videos v
+WHERE sqlc.narg('channel_id')::text IS NULL OR v.channel_id = sqlc.narg('channel_id');
+
+-- ListCaptionLanguageCounts returns how many videos have a transcript in each
+-- language, optionally limited to one channel.
+-- name: ListCaptionLanguageCounts :many
+SELECT t.lang::text AS lang, COUNT(DISTINCT t.video_id) AS video_count
+FROM video_transcripts t
+JOIN videos v ON v.id = t.video_id
+WHERE sqlc.narg('channel_id')::text IS NULL OR v.channel_id = sqlc.narg('channel_id')
+GROUP BY t.lang
+ORDER BY video_count DESC, lang;
+
+-- ListCaptionChannels returns channels with their video and caption-less
+-- counts, most caption-less first, for the caption audit filter.
+-- name: ListCaptionChannels :many
+SELECT
+    v.channel_id::text AS channel_id,
+    MAX(v.uploader)::text AS uploader,
+    COUNT(*) AS total_count,
+    COUNT(*) FILTER (WHERE NOT EXISTS (
+        SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id
+    )) AS missing_count
+FROM videos v
+WHERE v.channel_id IS NOT NULL AND v.channel_id <> ''
+GROUP BY v.channel_id
+ORDER BY missing_count DESC, total_count DESC
+LIMIT 200;
+
+-- ListCaptionAuditVideos lists videos with their transcript languages.
+-- presence is 'missing' (no transcripts), 'present' (at least one) or NULL
+-- (all); lang keeps only videos with a transcript in that language.
+-- name: ListCaptionAuditVideos :many
+SELECT
+    v.id,
+    v.title,
+    v.uploader,
+    v.channel_id,
+    v.created_at,
+    COALESCE(array_agg(t.lang::text ORDER BY t.lang) FILTER (WHERE t.lang IS NOT NULL), '{}')::text[] AS langs
+FROM videos v
+LEFT JOIN video_transcripts t ON t.video_id = v.id
+WHERE sqlc.narg('channel_id')::text IS NULL OR v.channel_id = sqlc.narg('channel_id')
+GROUP BY v.id
+HAVING (
+        sqlc.narg('presence')::text IS NULL
+        OR (sqlc.narg('presence') = 'missing' AND COUNT(t.id) = 0)
+        OR (sqlc.narg('presence') = 'present' AND COUNT(t.id) > 0)
+    )
+    AND (sqlc.narg('lang')::text IS NULL OR COALESCE(bool_or(t.lang::text = sqlc.narg('lang')), false))
+ORDER BY v.created_at DESC
+LIMIT sqlc.arg(page_limit);
+
+-- ListVideosForManifest pages through every video in id order for the
+-- archive manifest. Pass the last id of the previous page as after_id
+-- (the nil UUID for the first page).
+-- name: ListVideosForManifest :many
+SELECT
+    id,
+    created_at,
+    updated_at,
+    src,
+    title,
+    description,
+    tags,
+    uploader,
+    uploader_id,
+    channel_id,
+    upload_date,
+    duration_seconds,
+    video_path,
+    thumbnail_path,
+    file_hash,
+    file_size,
+    metadata_only,
+    assets_status,
+    info,
+    probe_data
+FROM videos
+WHERE id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
+-- CountVideos returns the total number of archived videos.
+-- name: CountVideos :one
+SELECT COUNT(*) FROM videos;
@@ -25,3 +25,101 @@ DO UPDATE SET
     search = EXCLUDED.search,
     raw = EXCLUDED.raw,
     updated_at = NOW();
+
+-- ListTranscriptsForExport pages through transcripts joined with their video
+-- metadata, keyset-ordered by (video_id, lang) so bulk exports can stream
+-- without OFFSET scans. Pass the last row's (video_id, lang) as the cursor;
+-- use the zero UUID and '' for the first page.
+-- name: ListTranscriptsForExport :many
+SELECT
+    vt.video_id,
+    vt.lang::text AS lang,
+    vt.format,
+    vt.text,
+    vt.raw,
+    v.title,
+    v.uploader,
+    v.channel_id,
+    v.upload_date,
+    v.created_at AS archived_at
+FROM video_transcripts vt
+JOIN videos v ON v.id = vt.video_id
+WHERE (vt.video_id, vt.lang::text) > (sqlc.arg(after_video_id)::uuid, sqlc.arg(after_lang)::text)
+    AND (sqlc.narg('channel_id')::text IS NULL OR v.channel_id = sqlc.narg('channel_id'))
+    AND (
+        sqlc.narg('date_from')::date IS NULL
+        OR (sqlc.narg('date_type')::text = 'published' AND v.upload_date >= sqlc.narg('date_from'))
+        OR (sqlc.narg('date_type')::text IS DISTINCT FROM 'published' AND v.created_at::date >= sqlc.narg('date_from'))
+    )
+    AND (
+        sqlc.narg('date_to')::date IS NULL
+        OR (sqlc.narg('date_type')::text = 'published' AND v.upload_date <= sqlc.narg('date_to'))
+        OR (sqlc.narg('date_type')::text IS DISTINCT FROM 'published' AND v.created_at::date <= sqlc.narg('date_to'))
+    )
+ORDER BY vt.video_id, vt.lang::text
+LIMIT sqlc.arg(page_limit);
+
+-- UpdateVideoTranscriptRaw replaces the stored VTT for a video+lang after its
+-- cue times are corrected. The plain text (and search index) carries no
+-- timestamps, so it is left as is.
+-- name: UpdateVideoTranscriptRaw :execrows
+UPDATE video_transcripts
+SET raw = sqlc.arg(raw),
+    updated_at = NOW()
+WHERE video_id = sqlc.arg(video_id)
+    AND lang = sqlc.arg(lang)::language_tag;
+
+-- ListVideoTranscriptTexts returns the plain text of each of a video's
+-- transcripts.
+-- name: ListVideoTranscriptTexts :many
+SELECT text
+FROM video_transcripts
+WHERE video_id = sqlc.arg(video_id)
+ORDER BY lang;
+
+-- CountTranscribedVideos returns how many videos have a transcript, the
+-- document count for keyword weighting.
+-- name: CountTranscribedVideos :one
+SELECT COUNT(DISTINCT video_id)::bigint FROM video_transcripts;
+
+-- CountTranscriptTermVideos returns, for each term, how many videos have a
+-- transcript containing it. Matches the 'simple' search vector, so terms are
+-- compared lowercased and unstemmed.
+-- name: CountTranscriptTermVideos :many
+SELECT
+    t.term::text AS term,
+    (
+        SELECT COUNT(DISTINCT vt.video_id)
+        FROM video_transcripts vt
+        WHERE vt.search @@ plainto_tsquery('simple'::regconfig, t.term)
+    )::bigint AS videos
+FROM unnest(sqlc.arg(terms)::text[]) AS t(term);
+
+-- ListVideoTranscriptWords returns the word timings of a video's transcript
+-- in a language, in cue and word order.
+-- name: ListVideoTranscriptWords :many
+SELECT *
+FROM video_transcript_words
+WHERE video_id = sqlc.arg(video_id)
+    AND lang = sqlc.arg(lang)::language_tag
+ORDER BY cue_index, word_index;
+
+-- DeleteVideoTranscriptWords removes the word timings of a video's transcript
+-- in a language before it is replaced.
+-- name: DeleteVideoTranscriptWords :exec
+DELETE FROM video_transcript_words
+WHERE video_id = sqlc.arg(video_id)
+    AND lang = sqlc.arg(lang)::language_tag;
+
+-- InsertVideoTranscriptWords stores word timings; the arrays are parallel,
+-- one element per word.
+-- name: InsertVideoTranscriptWords :exec
+INSERT INTO video_transcript_words (video_id, lang, cue_index, word_index, start_offset, end_offset, word)
+SELECT sqlc.arg(video_id), sqlc.arg(lang)::language_tag, w.cue_index, w.word_index, w.start_offset, w.end_offset, w.word
+FROM unnest(
+    sqlc.arg(cue_indexes)::int[],
+    sqlc.arg(word_indexes)::int[],
+    sqlc.arg(start_offsets)::double precision[],
+    sqlc.arg(end_offsets)::double precision[],
+    sqlc.arg(words)::text[]
+) AS w(cue_index, word_index, start_offset, end_offset, word);
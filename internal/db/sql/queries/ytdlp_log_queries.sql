@@ -15,11 +15,18 @@ WHERE job_id = sqlc.arg(job_id) AND created_at > sqlc.arg(since)
 ORDER BY created_at ASC, id ASC;
 
 -- name: GetYtdlpLogsForJobPaginated :many
+-- Newest-first page of a job's log lines, optionally limited to one stream
+-- and/or lines containing a case-insensitive substring.
 SELECT id, job_id, stream, message, created_at
 FROM ytdlp_logs
 WHERE job_id = sqlc.arg(job_id)
+  AND (sqlc.narg(stream)::text IS NULL OR stream::text = sqlc.narg(stream)::text)
+  AND (sqlc.narg(search)::text IS NULL OR strpos(lower(message), lower(sqlc.narg(search)::text)) > 0)
 ORDER BY created_at DESC, id DESC
 LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
 
 -- name: CountYtdlpLogsForJob :one
-SELECT COUNT(*) FROM ytdlp_logs WHERE job_id = sqlc.arg(job_id);
+SELECT COUNT(*) FROM ytdlp_logs
+WHERE job_id = sqlc.arg(job_id)
+  AND (sqlc.narg(stream)::text IS NULL OR stream::text = sqlc.narg(stream)::text)
+  AND (sqlc.narg(search)::text IS NULL OR strpos(lower(message), lower(sqlc.narg(search)::text)) > 0);
@@ -11,10 +11,32 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addVideoAutoTag = `-- name: AddVideoAutoTag :exec
+INSERT INTO video_tags (video_id, tag_id, auto)
+VALUES ($1, $2, true)
+ON CONFLICT (video_id, tag_id) DO NOTHING
+`
+
+type AddVideoAutoTagParams struct {
+	VideoID pgtype.UUID `db:"video_id" json:"VideoID"`
+	TagID   pgtype.UUID `db:"tag_id" json:"TagID"`
+}
+
+// AddVideoAutoTag links a suggested tag to a video. A link the user already
+// made is left as a manual tag.
+//
+//	INSERT INTO video_tags (video_id, tag_id, auto)
+//	VALUES ($1, $2, true)
+//	ON CONFLICT (video_id, tag_id) DO NOTHING
+func (q *Queries) AddVideoAutoTag(ctx context.Context, arg *AddVideoAutoTagParams) error {
+	_, err := q.db.Exec(ctx, addVideoAutoTag, arg.VideoID, arg.TagID)
+	return err
+}
+
 const addVideoTag = `-- name: AddVideoTag :exec
 INSERT INTO video_tags (video_id, tag_id, created_by)
 VALUES ($1, $2, $3)
-ON CONFLICT (video_id, tag_id) DO NOTHING
+ON CONFLICT (video_id, tag_id) DO UPDATE SET auto = false
 `
 
 type AddVideoTagParams struct {
@@ -23,11 +45,12 @@ type AddVideoTagParams struct {
 	CreatedBy pgtype.UUID `db:"created_by" json:"CreatedBy"`
 }
 
-// AddVideoTag links a tag to a video (idempotent).
+// AddVideoTag links a tag to a video (idempotent). Adding a tag by hand that
+// was suggested automatically keeps it through later re-tagging.
 //
 //	INSERT INTO video_tags (video_id, tag_id, created_by)
 //	VALUES ($1, $2, $3)
-//	ON CONFLICT (video_id, tag_id) DO NOTHING
+//	ON CONFLICT (video_id, tag_id) DO UPDATE SET auto = false
 func (q *Queries) AddVideoTag(ctx context.Context, arg *AddVideoTagParams) error {
 	_, err := q.db.Exec(ctx, addVideoTag, arg.VideoID, arg.TagID, arg.CreatedBy)
 	return err
@@ -37,7 +60,7 @@ const addVideoTagToMany = `-- name: AddVideoTagToMany :exec
 INSERT INTO video_tags (video_id, tag_id, created_by)
 SELECT v, $1, $2
 FROM unnest($3::uuid[]) AS v
-ON CONFLICT (video_id, tag_id) DO NOTHING
+ON CONFLICT (video_id, tag_id) DO UPDATE SET auto = false
 `
 
 type AddVideoTagToManyParams struct {
@@ -52,12 +75,26 @@ type AddVideoTagToManyParams struct {
 //	INSERT INTO video_tags (video_id, tag_id, created_by)
 //	SELECT v, $1, $2
 //	FROM unnest($3::uuid[]) AS v
-//	ON CONFLICT (video_id, tag_id) DO NOTHING
+//	ON CONFLICT (video_id, tag_id) DO UPDATE SET auto = false
 func (q *Queries) AddVideoTagToMany(ctx context.Context, arg *AddVideoTagToManyParams) error {
 	_, err := q.db.Exec(ctx, addVideoTagToMany, arg.TagID, arg.CreatedBy, arg.VideoIds)
 	return err
 }
 
+const deleteVideoAutoTags = `-- name: DeleteVideoAutoTags :exec
+DELETE FROM video_tags
+WHERE video_id = $1 AND auto
+`
+
+// DeleteVideoAutoTags removes a video's automatically added tag links.
+//
+//	DELETE FROM video_tags
+//	WHERE video_id = $1 AND auto
+func (q *Queries) DeleteVideoAutoTags(ctx context.Context, videoID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteVideoAutoTags, videoID)
+	return err
+}
+
 const listAllTagsWithCounts = `-- name: ListAllTagsWithCounts :many
 SELECT t.id, t.name, t.slug, t.color, COUNT(vt.video_id)::bigint AS video_count
 FROM tags t
@@ -109,7 +146,7 @@ func (q *Queries) ListAllTagsWithCounts(ctx context.Context) ([]*ListAllTagsWith
 }
 
 const listTagsForVideo = `-- name: ListTagsForVideo :many
-SELECT t.id, t.name, t.slug, t.color
+SELECT t.id, t.name, t.slug, t.color, vt.auto
 FROM tags t
 JOIN video_tags vt ON vt.tag_id = t.id
 WHERE vt.video_id = $1
@@ -121,11 +158,13 @@ type ListTagsForVideoRow struct {
 	Name  string      `db:"name" json:"Name"`
 	Slug  string      `db:"slug" json:"Slug"`
 	Color *string     `db:"color" json:"Color"`
+	Auto  bool        `db:"auto" json:"Auto"`
 }
 
-// ListTagsForVideo returns a video's tags, alphabetically.
+// ListTagsForVideo returns a video's tags, alphabetically. auto is set for
+// tags suggested from the transcript.
 //
-//	SELECT t.id, t.name, t.slug, t.color
+//	SELECT t.id, t.name, t.slug, t.color, vt.auto
 //	FROM tags t
 //	JOIN video_tags vt ON vt.tag_id = t.id
 //	WHERE vt.video_id = $1
@@ -144,6 +183,7 @@ func (q *Queries) ListTagsForVideo(ctx context.Context, videoID pgtype.UUID) ([]
 			&i.Name,
 			&i.Slug,
 			&i.Color,
+			&i.Auto,
 		); err != nil {
 			return nil, err
 		}
@@ -174,6 +214,32 @@ func (q *Queries) RemoveVideoTag(ctx context.Context, arg *RemoveVideoTagParams)
 	return err
 }
 
+const upsertAutoTag = `-- name: UpsertAutoTag :one
+INSERT INTO tags (name, slug)
+VALUES ($1, $2)
+ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+RETURNING id
+`
+
+type UpsertAutoTagParams struct {
+	Name string `db:"name" json:"Name"`
+	Slug string `db:"slug" json:"Slug"`
+}
+
+// UpsertAutoTag returns the tag with slug, creating it when missing. Unlike
+// UpsertTag it never renames an existing tag, so a user's casing survives.
+//
+//	INSERT INTO tags (name, slug)
+//	VALUES ($1, $2)
+//	ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+//	RETURNING id
+func (q *Queries) UpsertAutoTag(ctx context.Context, arg *UpsertAutoTagParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, upsertAutoTag, arg.Name, arg.Slug)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const upsertTag = `-- name: UpsertTag :one
 INSERT INTO tags (name, slug, color, created_by)
 VALUES ($1, $2, $3, $4)
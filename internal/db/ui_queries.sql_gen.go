@@ -13,14 +13,14 @@ import (
 )
 
 const getDownloadJobByID = `-- name: GetDownloadJobByID :one
-SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 FROM download_jobs
 WHERE id = $1
 `
 
 // GetDownloadJobByID returns a download job by ID
 //
-//	SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+//	SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 //	FROM download_jobs
 //	WHERE id = $1
 func (q *Queries) GetDownloadJobByID(ctx context.Context, id pgtype.UUID) (*DownloadJob, error) {
@@ -48,6 +48,10 @@ func (q *Queries) GetDownloadJobByID(ctx context.Context, id pgtype.UUID) (*Down
 		&i.ParentJobID,
 		&i.BatchLabel,
 		&i.BatchTotal,
+		&i.ErrorCode,
+		&i.Priority,
+		&i.MetadataOnly,
+		&i.NextRetryAt,
 	)
 	return &i, err
 }
@@ -91,14 +95,14 @@ func (q *Queries) GetHomeStats(ctx context.Context) (*GetHomeStatsRow, error) {
 }
 
 const getVideoByID = `-- name: GetVideoByID :one
-SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 FROM videos
 WHERE id = $1
 `
 
 // GetVideoByID returns a video by ID
 //
-//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 //	FROM videos
 //	WHERE id = $1
 func (q *Queries) GetVideoByID(ctx context.Context, id pgtype.UUID) (*Video, error) {
@@ -133,6 +137,11 @@ func (q *Queries) GetVideoByID(ctx context.Context, id pgtype.UUID) (*Video, err
 		&i.Search,
 		&i.ProbeData,
 		&i.CommentsCheckedAt,
+		&i.Phash,
+		&i.PhashFrames,
+		&i.MetadataOnly,
+		&i.AutoTaggedAt,
+		&i.ThumbnailPhash,
 	)
 	return &i, err
 }
@@ -208,7 +217,7 @@ func (q *Queries) ListDistinctUploaders(ctx context.Context) ([]string, error) {
 }
 
 const listDownloadJobsByUser = `-- name: ListDownloadJobsByUser :many
-SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 FROM download_jobs
 WHERE archived_by = $1
   AND archived = FALSE
@@ -223,7 +232,7 @@ type ListDownloadJobsByUserParams struct {
 
 // ListDownloadJobsByUser returns all download jobs for a user
 //
-//	SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+//	SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 //	FROM download_jobs
 //	WHERE archived_by = $1
 //	  AND archived = FALSE
@@ -260,6 +269,10 @@ func (q *Queries) ListDownloadJobsByUser(ctx context.Context, arg *ListDownloadJ
 			&i.ParentJobID,
 			&i.BatchLabel,
 			&i.BatchTotal,
+			&i.ErrorCode,
+			&i.Priority,
+			&i.MetadataOnly,
+			&i.NextRetryAt,
 		); err != nil {
 			return nil, err
 		}
@@ -272,7 +285,7 @@ func (q *Queries) ListDownloadJobsByUser(ctx context.Context, arg *ListDownloadJ
 }
 
 const listDownloadJobsByVideoID = `-- name: ListDownloadJobsByVideoID :many
-SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 FROM download_jobs
 WHERE video_id = $1
    OR url = $2
@@ -287,7 +300,7 @@ type ListDownloadJobsByVideoIDParams struct {
 // ListDownloadJobsByVideoID returns all download jobs for a video.
 // Matches by video_id FK or by URL matching the video's src column.
 //
-//	SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+//	SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 //	FROM download_jobs
 //	WHERE video_id = $1
 //	   OR url = $2
@@ -323,6 +336,10 @@ func (q *Queries) ListDownloadJobsByVideoID(ctx context.Context, arg *ListDownlo
 			&i.ParentJobID,
 			&i.BatchLabel,
 			&i.BatchTotal,
+			&i.ErrorCode,
+			&i.Priority,
+			&i.MetadataOnly,
+			&i.NextRetryAt,
 		); err != nil {
 			return nil, err
 		}
@@ -335,7 +352,7 @@ func (q *Queries) ListDownloadJobsByVideoID(ctx context.Context, arg *ListDownlo
 }
 
 const listIngestJobsByDownloadJobIDs = `-- name: ListIngestJobsByDownloadJobIDs :many
-SELECT id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+SELECT id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 FROM ingest_jobs
 WHERE download_job_id = ANY($1::uuid[])
 ORDER BY created_at DESC
@@ -343,7 +360,7 @@ ORDER BY created_at DESC
 
 // ListIngestJobsByDownloadJobIDs returns ingest jobs for a set of download job IDs.
 //
-//	SELECT id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope
+//	SELECT id, created_at, updated_at, download_job_id, status, attempts, last_error, started_at, finished_at, asset_scope, next_retry_at, thumbnail_at, whisper_model, whisper_language
 //	FROM ingest_jobs
 //	WHERE download_job_id = ANY($1::uuid[])
 //	ORDER BY created_at DESC
@@ -367,6 +384,10 @@ func (q *Queries) ListIngestJobsByDownloadJobIDs(ctx context.Context, downloadJo
 			&i.StartedAt,
 			&i.FinishedAt,
 			&i.AssetScope,
+			&i.NextRetryAt,
+			&i.ThumbnailAt,
+			&i.WhisperModel,
+			&i.WhisperLanguage,
 		); err != nil {
 			return nil, err
 		}
@@ -454,7 +475,7 @@ func (q *Queries) ListRecentClips(ctx context.Context) ([]*ListRecentClipsRow, e
 }
 
 const listRecentDownloadJobs = `-- name: ListRecentDownloadJobs :many
-SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 FROM download_jobs
 WHERE archived = FALSE
 ORDER BY created_at DESC
@@ -463,7 +484,7 @@ LIMIT 100
 
 // ListRecentDownloadJobs returns recent download jobs for all users
 //
-//	SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total
+//	SELECT id, created_at, updated_at, url, archived_by, status, attempts, last_error, started_at, finished_at, spool_dir, info_json_path, video_id, refresh, process_pid, archived, extra_args, kind, parent_job_id, batch_label, batch_total, error_code, priority, metadata_only, next_retry_at
 //	FROM download_jobs
 //	WHERE archived = FALSE
 //	ORDER BY created_at DESC
@@ -499,6 +520,10 @@ func (q *Queries) ListRecentDownloadJobs(ctx context.Context) ([]*DownloadJob, e
 			&i.ParentJobID,
 			&i.BatchLabel,
 			&i.BatchTotal,
+			&i.ErrorCode,
+			&i.Priority,
+			&i.MetadataOnly,
+			&i.NextRetryAt,
 		); err != nil {
 			return nil, err
 		}
@@ -511,7 +536,7 @@ func (q *Queries) ListRecentDownloadJobs(ctx context.Context) ([]*DownloadJob, e
 }
 
 const listRecentVideos = `-- name: ListRecentVideos :many
-SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 FROM videos
 ORDER BY created_at DESC
 LIMIT 15
@@ -519,7 +544,7 @@ LIMIT 15
 
 // ListRecentVideos returns recent videos (by archive date)
 //
-//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 //	FROM videos
 //	ORDER BY created_at DESC
 //	LIMIT 15
@@ -561,6 +586,11 @@ func (q *Queries) ListRecentVideos(ctx context.Context) ([]*Video, error) {
 			&i.Search,
 			&i.ProbeData,
 			&i.CommentsCheckedAt,
+			&i.Phash,
+			&i.PhashFrames,
+			&i.MetadataOnly,
+			&i.AutoTaggedAt,
+			&i.ThumbnailPhash,
 		); err != nil {
 			return nil, err
 		}
@@ -573,7 +603,7 @@ func (q *Queries) ListRecentVideos(ctx context.Context) ([]*Video, error) {
 }
 
 const listRecentlyPublishedVideos = `-- name: ListRecentlyPublishedVideos :many
-SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 FROM videos
 WHERE upload_date IS NOT NULL
 ORDER BY upload_date DESC
@@ -582,7 +612,7 @@ LIMIT 15
 
 // ListRecentlyPublishedVideos returns videos sorted by original publish date
 //
-//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 //	FROM videos
 //	WHERE upload_date IS NOT NULL
 //	ORDER BY upload_date DESC
@@ -625,6 +655,11 @@ func (q *Queries) ListRecentlyPublishedVideos(ctx context.Context) ([]*Video, er
 			&i.Search,
 			&i.ProbeData,
 			&i.CommentsCheckedAt,
+			&i.Phash,
+			&i.PhashFrames,
+			&i.MetadataOnly,
+			&i.AutoTaggedAt,
+			&i.ThumbnailPhash,
 		); err != nil {
 			return nil, err
 		}
@@ -636,9 +671,152 @@ func (q *Queries) ListRecentlyPublishedVideos(ctx context.Context) ([]*Video, er
 	return items, nil
 }
 
+const listRelatedVideos = `-- name: ListRelatedVideos :many
+SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
+FROM videos
+WHERE id <> $1
+  AND (
+    ($2::text IS NOT NULL AND channel_id = $2)
+    OR ($2::text IS NULL AND uploader = $3)
+  )
+ORDER BY upload_date DESC NULLS LAST, created_at DESC
+LIMIT $4
+`
+
+type ListRelatedVideosParams struct {
+	VideoID   pgtype.UUID `db:"video_id" json:"VideoID"`
+	ChannelID *string     `db:"channel_id" json:"ChannelID"`
+	Uploader  *string     `db:"uploader" json:"Uploader"`
+	PageLimit int32       `db:"page_limit" json:"PageLimit"`
+}
+
+// ListRelatedVideos returns other videos from the same channel (when
+// channel_id is given) or otherwise the same uploader, newest first.
+//
+//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
+//	FROM videos
+//	WHERE id <> $1
+//	  AND (
+//	    ($2::text IS NOT NULL AND channel_id = $2)
+//	    OR ($2::text IS NULL AND uploader = $3)
+//	  )
+//	ORDER BY upload_date DESC NULLS LAST, created_at DESC
+//	LIMIT $4
+func (q *Queries) ListRelatedVideos(ctx context.Context, arg *ListRelatedVideosParams) ([]*Video, error) {
+	rows, err := q.db.Query(ctx, listRelatedVideos,
+		arg.VideoID,
+		arg.ChannelID,
+		arg.Uploader,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Src,
+			&i.ArchivedBy,
+			&i.Title,
+			&i.Info,
+			&i.Comments,
+			&i.VideoPath,
+			&i.ThumbnailPath,
+			&i.Description,
+			&i.Tags,
+			&i.Uploader,
+			&i.UploaderID,
+			&i.ChannelID,
+			&i.UploadDate,
+			&i.DurationSeconds,
+			&i.ViewCount,
+			&i.LikeCount,
+			&i.ThumbGradientStart,
+			&i.ThumbGradientEnd,
+			&i.ThumbGradientAngle,
+			&i.FileHash,
+			&i.FileSize,
+			&i.AssetsStatus,
+			&i.Search,
+			&i.ProbeData,
+			&i.CommentsCheckedAt,
+			&i.Phash,
+			&i.PhashFrames,
+			&i.MetadataOnly,
+			&i.AutoTaggedAt,
+			&i.ThumbnailPhash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScrapedTagCounts = `-- name: ListScrapedTagCounts :many
+SELECT t.tag::text AS tag, COUNT(DISTINCT v.id)::bigint AS video_count
+FROM videos v
+CROSS JOIN LATERAL unnest(v.tags) AS t(tag)
+WHERE btrim(t.tag) <> ''
+GROUP BY t.tag
+HAVING COUNT(DISTINCT v.id) >= $1::bigint
+ORDER BY video_count DESC, tag ASC
+LIMIT $2
+`
+
+type ListScrapedTagCountsParams struct {
+	MinCount  int64 `db:"min_count" json:"MinCount"`
+	PageLimit int32 `db:"page_limit" json:"PageLimit"`
+}
+
+type ListScrapedTagCountsRow struct {
+	Tag        string `db:"tag" json:"Tag"`
+	VideoCount int64  `db:"video_count" json:"VideoCount"`
+}
+
+// ListScrapedTagCounts returns each distinct scraped (yt-dlp) tag with how
+// many videos carry it, most-used first. Tags are matched exactly as stored,
+// the same way the library tag filter matches them.
+//
+//	SELECT t.tag::text AS tag, COUNT(DISTINCT v.id)::bigint AS video_count
+//	FROM videos v
+//	CROSS JOIN LATERAL unnest(v.tags) AS t(tag)
+//	WHERE btrim(t.tag) <> ''
+//	GROUP BY t.tag
+//	HAVING COUNT(DISTINCT v.id) >= $1::bigint
+//	ORDER BY video_count DESC, tag ASC
+//	LIMIT $2
+func (q *Queries) ListScrapedTagCounts(ctx context.Context, arg *ListScrapedTagCountsParams) ([]*ListScrapedTagCountsRow, error) {
+	rows, err := q.db.Query(ctx, listScrapedTagCounts, arg.MinCount, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListScrapedTagCountsRow
+	for rows.Next() {
+		var i ListScrapedTagCountsRow
+		if err := rows.Scan(&i.Tag, &i.VideoCount); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVideosPaginated = `-- name: ListVideosPaginated :many
 SELECT 
-    v.id, v.created_at, v.updated_at, v.src, v.archived_by, v.title, v.info, v.comments, v.video_path, v.thumbnail_path, v.description, v.tags, v.uploader, v.uploader_id, v.channel_id, v.upload_date, v.duration_seconds, v.view_count, v.like_count, v.thumb_gradient_start, v.thumb_gradient_end, v.thumb_gradient_angle, v.file_hash, v.file_size, v.assets_status, v.search, v.probe_data, v.comments_checked_at,
+    v.id, v.created_at, v.updated_at, v.src, v.archived_by, v.title, v.info, v.comments, v.video_path, v.thumbnail_path, v.description, v.tags, v.uploader, v.uploader_id, v.channel_id, v.upload_date, v.duration_seconds, v.view_count, v.like_count, v.thumb_gradient_start, v.thumb_gradient_end, v.thumb_gradient_angle, v.file_hash, v.file_size, v.assets_status, v.search, v.probe_data, v.comments_checked_at, v.phash, v.phash_frames, v.metadata_only, v.auto_tagged_at, v.thumbnail_phash,
     COUNT(*) OVER() AS total_count,
     COALESCE((SELECT COUNT(*) FROM clips c WHERE c.video_id = v.id), 0) AS clip_count,
     COALESCE((SELECT COUNT(*) FROM markers m WHERE m.video_id = v.id), 0) AS marker_count,
@@ -755,6 +933,11 @@ type ListVideosPaginatedRow struct {
 	Search             string               `db:"search" json:"Search"`
 	ProbeData          *videoinfo.ProbeInfo `db:"probe_data" json:"ProbeData"`
 	CommentsCheckedAt  pgtype.Timestamptz   `db:"comments_checked_at" json:"CommentsCheckedAt"`
+	Phash              *int64               `db:"phash" json:"Phash"`
+	PhashFrames        []int64              `db:"phash_frames" json:"PhashFrames"`
+	MetadataOnly       bool                 `db:"metadata_only" json:"MetadataOnly"`
+	AutoTaggedAt       pgtype.Timestamptz   `db:"auto_tagged_at" json:"AutoTaggedAt"`
+	ThumbnailPhash     *int64               `db:"thumbnail_phash" json:"ThumbnailPhash"`
 	TotalCount         int64                `db:"total_count" json:"TotalCount"`
 	ClipCount          interface{}          `db:"clip_count" json:"ClipCount"`
 	MarkerCount        interface{}          `db:"marker_count" json:"MarkerCount"`
@@ -767,7 +950,7 @@ type ListVideosPaginatedRow struct {
 // Returns total_count via window function for pagination UI.
 //
 //	SELECT
-//	    v.id, v.created_at, v.updated_at, v.src, v.archived_by, v.title, v.info, v.comments, v.video_path, v.thumbnail_path, v.description, v.tags, v.uploader, v.uploader_id, v.channel_id, v.upload_date, v.duration_seconds, v.view_count, v.like_count, v.thumb_gradient_start, v.thumb_gradient_end, v.thumb_gradient_angle, v.file_hash, v.file_size, v.assets_status, v.search, v.probe_data, v.comments_checked_at,
+//	    v.id, v.created_at, v.updated_at, v.src, v.archived_by, v.title, v.info, v.comments, v.video_path, v.thumbnail_path, v.description, v.tags, v.uploader, v.uploader_id, v.channel_id, v.upload_date, v.duration_seconds, v.view_count, v.like_count, v.thumb_gradient_start, v.thumb_gradient_end, v.thumb_gradient_angle, v.file_hash, v.file_size, v.assets_status, v.search, v.probe_data, v.comments_checked_at, v.phash, v.phash_frames, v.metadata_only, v.auto_tagged_at, v.thumbnail_phash,
 //	    COUNT(*) OVER() AS total_count,
 //	    COALESCE((SELECT COUNT(*) FROM clips c WHERE c.video_id = v.id), 0) AS clip_count,
 //	    COALESCE((SELECT COUNT(*) FROM markers m WHERE m.video_id = v.id), 0) AS marker_count,
@@ -889,6 +1072,11 @@ func (q *Queries) ListVideosPaginated(ctx context.Context, arg *ListVideosPagina
 			&i.Search,
 			&i.ProbeData,
 			&i.CommentsCheckedAt,
+			&i.Phash,
+			&i.PhashFrames,
+			&i.MetadataOnly,
+			&i.AutoTaggedAt,
+			&i.ThumbnailPhash,
 			&i.TotalCount,
 			&i.ClipCount,
 			&i.MarkerCount,
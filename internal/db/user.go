@@ -14,6 +14,8 @@ type NewUserParams struct {
 	Email    string
 	Password string // plaintext password
 	Role     string
+	// PendingApproval creates the user disabled and awaiting admin approval.
+	PendingApproval bool
 }
 
 // NewUser creates a new user with a hashed password
@@ -40,10 +42,11 @@ func (q *Queries) NewUser(ctx context.Context, params NewUserParams) (*User, err
 
 	// Insert the user with the hashed password
 	return q.insertUser(ctx, &insertUserParams{
-		ID:       pgUUID,
-		Email:    params.Email,
-		Password: hashedPassword,
-		UserName: params.Username,
-		Role:     role,
+		ID:              pgUUID,
+		Email:           params.Email,
+		Password:        hashedPassword,
+		UserName:        params.Username,
+		Role:            role,
+		PendingApproval: params.PendingApproval,
 	})
 }
@@ -98,6 +98,45 @@ func (q *Queries) GetSessionInvalidation(ctx context.Context, id pgtype.UUID) (*
 	return &i, err
 }
 
+const getUserDownloadCap = `-- name: GetUserDownloadCap :one
+SELECT u.role,
+       u.download_max_height,
+       COALESCE(s.user_download_max_height, 0)::int AS user_max_height,
+       COALESCE(s.admin_download_max_height, 0)::int AS admin_max_height
+FROM users u
+LEFT JOIN instance_settings s ON s.id = 1
+WHERE u.id = $1
+`
+
+type GetUserDownloadCapRow struct {
+	Role              UserRole `db:"role" json:"Role"`
+	DownloadMaxHeight *int32   `db:"download_max_height" json:"DownloadMaxHeight"`
+	UserMaxHeight     int32    `db:"user_max_height" json:"UserMaxHeight"`
+	AdminMaxHeight    int32    `db:"admin_max_height" json:"AdminMaxHeight"`
+}
+
+// GetUserDownloadCap returns a user's role and download cap override, with the
+// role caps from instance settings (0 when there is no settings row).
+//
+//	SELECT u.role,
+//	       u.download_max_height,
+//	       COALESCE(s.user_download_max_height, 0)::int AS user_max_height,
+//	       COALESCE(s.admin_download_max_height, 0)::int AS admin_max_height
+//	FROM users u
+//	LEFT JOIN instance_settings s ON s.id = 1
+//	WHERE u.id = $1
+func (q *Queries) GetUserDownloadCap(ctx context.Context, id pgtype.UUID) (*GetUserDownloadCapRow, error) {
+	row := q.db.QueryRow(ctx, getUserDownloadCap, id)
+	var i GetUserDownloadCapRow
+	err := row.Scan(
+		&i.Role,
+		&i.DownloadMaxHeight,
+		&i.UserMaxHeight,
+		&i.AdminMaxHeight,
+	)
+	return &i, err
+}
+
 const invalidateUserSessions = `-- name: InvalidateUserSessions :exec
 UPDATE users
 SET sessions_invalidated_at = NOW(),
@@ -118,12 +157,12 @@ func (q *Queries) InvalidateUserSessions(ctx context.Context, id pgtype.UUID) er
 }
 
 const listAllUsers = `-- name: ListAllUsers :many
-SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE deleted_at IS NULL
+SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE deleted_at IS NULL
 `
 
 // ListAllUsers lists all users in the database
 //
-//	SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE deleted_at IS NULL
+//	SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE deleted_at IS NULL
 func (q *Queries) ListAllUsers(ctx context.Context) ([]*User, error) {
 	rows, err := q.db.Query(ctx, listAllUsers)
 	if err != nil {
@@ -146,6 +185,8 @@ func (q *Queries) ListAllUsers(ctx context.Context) ([]*User, error) {
 			&i.UpdatedAt,
 			&i.DeletedAt,
 			&i.SessionsInvalidatedAt,
+			&i.PendingApproval,
+			&i.DownloadMaxHeight,
 		); err != nil {
 			return nil, err
 		}
@@ -158,12 +199,12 @@ func (q *Queries) ListAllUsers(ctx context.Context) ([]*User, error) {
 }
 
 const selectUserByEmail = `-- name: SelectUserByEmail :one
-SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE email = $1 AND deleted_at IS NULL
+SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE email = $1 AND deleted_at IS NULL
 `
 
 // SelectUserByEmail selects a user by email from the database
 //
-//	SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE email = $1 AND deleted_at IS NULL
+//	SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE email = $1 AND deleted_at IS NULL
 func (q *Queries) SelectUserByEmail(ctx context.Context, email string) (*User, error) {
 	row := q.db.QueryRow(ctx, selectUserByEmail, email)
 	var i User
@@ -180,17 +221,19 @@ func (q *Queries) SelectUserByEmail(ctx context.Context, email string) (*User, e
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SessionsInvalidatedAt,
+		&i.PendingApproval,
+		&i.DownloadMaxHeight,
 	)
 	return &i, err
 }
 
 const selectUserByID = `-- name: SelectUserByID :one
-SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE id = $1 AND deleted_at IS NULL
+SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE id = $1 AND deleted_at IS NULL
 `
 
 // SelectUserByID selects a user by ID from the database
 //
-//	SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE id = $1 AND deleted_at IS NULL
+//	SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE id = $1 AND deleted_at IS NULL
 func (q *Queries) SelectUserByID(ctx context.Context, id pgtype.UUID) (*User, error) {
 	row := q.db.QueryRow(ctx, selectUserByID, id)
 	var i User
@@ -207,17 +250,19 @@ func (q *Queries) SelectUserByID(ctx context.Context, id pgtype.UUID) (*User, er
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SessionsInvalidatedAt,
+		&i.PendingApproval,
+		&i.DownloadMaxHeight,
 	)
 	return &i, err
 }
 
 const selectUserByUserName = `-- name: SelectUserByUserName :one
-SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE user_name = $1 AND deleted_at IS NULL
+SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE user_name = $1 AND deleted_at IS NULL
 `
 
 // SelectUserByUserName selects a user by user name from the database
 //
-//	SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at FROM users WHERE user_name = $1 AND deleted_at IS NULL
+//	SELECT id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height FROM users WHERE user_name = $1 AND deleted_at IS NULL
 func (q *Queries) SelectUserByUserName(ctx context.Context, userName string) (*User, error) {
 	row := q.db.QueryRow(ctx, selectUserByUserName, userName)
 	var i User
@@ -234,13 +279,39 @@ func (q *Queries) SelectUserByUserName(ctx context.Context, userName string) (*U
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SessionsInvalidatedAt,
+		&i.PendingApproval,
+		&i.DownloadMaxHeight,
 	)
 	return &i, err
 }
 
+const setUserDownloadMaxHeight = `-- name: SetUserDownloadMaxHeight :exec
+UPDATE users
+SET download_max_height = $1,
+    updated_at = NOW()
+WHERE id = $2 AND deleted_at IS NULL
+`
+
+type SetUserDownloadMaxHeightParams struct {
+	DownloadMaxHeight *int32      `db:"download_max_height" json:"DownloadMaxHeight"`
+	ID                pgtype.UUID `db:"id" json:"ID"`
+}
+
+// SetUserDownloadMaxHeight sets or clears (NULL = use the role cap) a user's download resolution cap
+//
+//	UPDATE users
+//	SET download_max_height = $1,
+//	    updated_at = NOW()
+//	WHERE id = $2 AND deleted_at IS NULL
+func (q *Queries) SetUserDownloadMaxHeight(ctx context.Context, arg *SetUserDownloadMaxHeightParams) error {
+	_, err := q.db.Exec(ctx, setUserDownloadMaxHeight, arg.DownloadMaxHeight, arg.ID)
+	return err
+}
+
 const setUserEnabled = `-- name: SetUserEnabled :exec
 UPDATE users
 SET enabled = $1,
+    pending_approval = pending_approval AND NOT $1,
     updated_at = NOW()
 WHERE id = $2 AND deleted_at IS NULL
 `
@@ -251,9 +322,11 @@ type SetUserEnabledParams struct {
 }
 
 // SetUserEnabled updates a user's enabled flag
+// Enabling a user also clears any pending registration approval.
 //
 //	UPDATE users
 //	SET enabled = $1,
+//	    pending_approval = pending_approval AND NOT $1,
 //	    updated_at = NOW()
 //	WHERE id = $2 AND deleted_at IS NULL
 func (q *Queries) SetUserEnabled(ctx context.Context, arg *SetUserEnabledParams) error {
@@ -351,6 +424,8 @@ INSERT INTO users (
     password,
     user_name,
     role,
+    enabled,
+    pending_approval,
     created_at,
     updated_at,
     deleted_at
@@ -361,19 +436,22 @@ VALUES (
     $3,
     $4,
     $5,
+    NOT $6::boolean,
+    $6::boolean,
     NOW(),
     NOW(),
     NULL
 )
-RETURNING id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at
+RETURNING id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height
 `
 
 type insertUserParams struct {
-	ID       pgtype.UUID        `db:"id" json:"ID"`
-	Email    string             `db:"email" json:"Email"`
-	Password passwords.Password `db:"password" json:"Password"`
-	UserName string             `db:"user_name" json:"UserName"`
-	Role     UserRole           `db:"role" json:"Role"`
+	ID              pgtype.UUID        `db:"id" json:"ID"`
+	Email           string             `db:"email" json:"Email"`
+	Password        passwords.Password `db:"password" json:"Password"`
+	UserName        string             `db:"user_name" json:"UserName"`
+	Role            UserRole           `db:"role" json:"Role"`
+	PendingApproval bool               `db:"pending_approval" json:"PendingApproval"`
 }
 
 // insertUser inserts a user into the database
@@ -386,6 +464,8 @@ type insertUserParams struct {
 //	    password,
 //	    user_name,
 //	    role,
+//	    enabled,
+//	    pending_approval,
 //	    created_at,
 //	    updated_at,
 //	    deleted_at
@@ -396,11 +476,13 @@ type insertUserParams struct {
 //	    $3,
 //	    $4,
 //	    $5,
+//	    NOT $6::boolean,
+//	    $6::boolean,
 //	    NOW(),
 //	    NOW(),
 //	    NULL
 //	)
-//	RETURNING id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at
+//	RETURNING id, user_name, password, email, email_verified, verify_hash, enabled, role, created_at, updated_at, deleted_at, sessions_invalidated_at, pending_approval, download_max_height
 func (q *Queries) insertUser(ctx context.Context, arg *insertUserParams) (*User, error) {
 	row := q.db.QueryRow(ctx, insertUser,
 		arg.ID,
@@ -408,6 +490,7 @@ func (q *Queries) insertUser(ctx context.Context, arg *insertUserParams) (*User,
 		arg.Password,
 		arg.UserName,
 		arg.Role,
+		arg.PendingApproval,
 	)
 	var i User
 	err := row.Scan(
@@ -423,6 +506,8 @@ func (q *Queries) insertUser(ctx context.Context, arg *insertUserParams) (*User,
 		&i.UpdatedAt,
 		&i.DeletedAt,
 		&i.SessionsInvalidatedAt,
+		&i.PendingApproval,
+		&i.DownloadMaxHeight,
 	)
 	return &i, err
 }
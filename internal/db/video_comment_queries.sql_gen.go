@@ -11,6 +11,43 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const archiveEditedVideoComments = `-- name: ArchiveEditedVideoComments :execrows
+INSERT INTO video_comment_edits (video_comment_id, text, raw, replaced_at)
+SELECT vc.id, vc.text, vc.raw, NOW()
+FROM jsonb_array_elements($1::jsonb) AS c
+JOIN video_comments vc
+  ON vc.video_id = $2::uuid
+ AND vc.source = $3::text
+ AND vc.comment_id = c->>'id'
+WHERE vc.text IS DISTINCT FROM COALESCE(NULLIF(c->>'text', ''), NULLIF(c->>'content', ''), NULLIF(c->>'comment', ''))
+`
+
+type ArchiveEditedVideoCommentsParams struct {
+	CommentsJson []byte      `db:"comments_json" json:"CommentsJson"`
+	VideoID      pgtype.UUID `db:"video_id" json:"VideoID"`
+	Source       string      `db:"source" json:"Source"`
+}
+
+// ArchiveEditedVideoComments copies the stored version of every comment in a
+// yt-dlp comment array whose text has changed into video_comment_edits. Run it
+// before UpsertVideoCommentsFromJSON overwrites the text.
+//
+//	INSERT INTO video_comment_edits (video_comment_id, text, raw, replaced_at)
+//	SELECT vc.id, vc.text, vc.raw, NOW()
+//	FROM jsonb_array_elements($1::jsonb) AS c
+//	JOIN video_comments vc
+//	  ON vc.video_id = $2::uuid
+//	 AND vc.source = $3::text
+//	 AND vc.comment_id = c->>'id'
+//	WHERE vc.text IS DISTINCT FROM COALESCE(NULLIF(c->>'text', ''), NULLIF(c->>'content', ''), NULLIF(c->>'comment', ''))
+func (q *Queries) ArchiveEditedVideoComments(ctx context.Context, arg *ArchiveEditedVideoCommentsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, archiveEditedVideoComments, arg.CommentsJson, arg.VideoID, arg.Source)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const countVideoComments = `-- name: CountVideoComments :one
 SELECT COUNT(*)
 FROM video_comments
@@ -37,7 +74,9 @@ SELECT c.id, c.video_id, c.source, c.comment_id, c.parent_id, c.author, c.author
        COALESCE((c.raw->>'is_pinned')::boolean, false)::boolean         AS is_pinned,
        COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
        COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
-       COALESCE(c.raw->>'_time_text', '')::text                     AS time_text
+       COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited
 FROM video_comments c
 WHERE c.video_id = $1
   AND c.parent_id = $2::text
@@ -69,6 +108,8 @@ type ListVideoCommentRepliesRow struct {
 	AuthorIsUploader bool               `db:"author_is_uploader" json:"AuthorIsUploader"`
 	AuthorIsVerified bool               `db:"author_is_verified" json:"AuthorIsVerified"`
 	TimeText         string             `db:"time_text" json:"TimeText"`
+	IsDeleted        bool               `db:"is_deleted" json:"IsDeleted"`
+	IsEdited         bool               `db:"is_edited" json:"IsEdited"`
 }
 
 // ListVideoCommentReplies returns replies (children) for a given parent comment.
@@ -82,7 +123,9 @@ type ListVideoCommentRepliesRow struct {
 //	       COALESCE((c.raw->>'is_pinned')::boolean, false)::boolean         AS is_pinned,
 //	       COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
 //	       COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
-//	       COALESCE(c.raw->>'_time_text', '')::text                     AS time_text
+//	       COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+//	       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+//	       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited
 //	FROM video_comments c
 //	WHERE c.video_id = $1
 //	  AND c.parent_id = $2::text
@@ -116,6 +159,8 @@ func (q *Queries) ListVideoCommentReplies(ctx context.Context, arg *ListVideoCom
 			&i.AuthorIsUploader,
 			&i.AuthorIsVerified,
 			&i.TimeText,
+			&i.IsDeleted,
+			&i.IsEdited,
 		); err != nil {
 			return nil, err
 		}
@@ -136,6 +181,8 @@ SELECT c.id, c.video_id, c.source, c.comment_id, c.parent_id, c.author, c.author
        COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
        COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
        COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited,
        COALESCE((SELECT count(*) FROM video_comments rc
                  WHERE rc.video_id = c.video_id AND rc.parent_id = c.comment_id), 0)::bigint AS reply_count
 FROM video_comments c
@@ -171,6 +218,8 @@ type ListVideoCommentsRow struct {
 	AuthorIsUploader bool               `db:"author_is_uploader" json:"AuthorIsUploader"`
 	AuthorIsVerified bool               `db:"author_is_verified" json:"AuthorIsVerified"`
 	TimeText         string             `db:"time_text" json:"TimeText"`
+	IsDeleted        bool               `db:"is_deleted" json:"IsDeleted"`
+	IsEdited         bool               `db:"is_edited" json:"IsEdited"`
 	ReplyCount       int64              `db:"reply_count" json:"ReplyCount"`
 }
 
@@ -190,6 +239,8 @@ type ListVideoCommentsRow struct {
 //	       COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
 //	       COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
 //	       COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+//	       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+//	       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited,
 //	       COALESCE((SELECT count(*) FROM video_comments rc
 //	                 WHERE rc.video_id = c.video_id AND rc.parent_id = c.comment_id), 0)::bigint AS reply_count
 //	FROM video_comments c
@@ -226,6 +277,8 @@ func (q *Queries) ListVideoComments(ctx context.Context, arg *ListVideoCommentsP
 			&i.AuthorIsUploader,
 			&i.AuthorIsVerified,
 			&i.TimeText,
+			&i.IsDeleted,
+			&i.IsEdited,
 			&i.ReplyCount,
 		); err != nil {
 			return nil, err
@@ -238,6 +291,39 @@ func (q *Queries) ListVideoComments(ctx context.Context, arg *ListVideoCommentsP
 	return items, nil
 }
 
+const markMissingVideoCommentsDeleted = `-- name: MarkMissingVideoCommentsDeleted :execrows
+UPDATE video_comments
+SET deleted_at = NOW()
+WHERE video_id = $1
+  AND source = $2
+  AND deleted_at IS NULL
+  AND NOT (comment_id = ANY($3::text[]))
+`
+
+type MarkMissingVideoCommentsDeletedParams struct {
+	VideoID pgtype.UUID `db:"video_id" json:"VideoID"`
+	Source  string      `db:"source" json:"Source"`
+	SeenIds []string    `db:"seen_ids" json:"SeenIds"`
+}
+
+// MarkMissingVideoCommentsDeleted flags stored comments for a video/source that
+// are absent from the latest fetch (seen_ids). Already-flagged rows keep their
+// original deleted_at.
+//
+//	UPDATE video_comments
+//	SET deleted_at = NOW()
+//	WHERE video_id = $1
+//	  AND source = $2
+//	  AND deleted_at IS NULL
+//	  AND NOT (comment_id = ANY($3::text[]))
+func (q *Queries) MarkMissingVideoCommentsDeleted(ctx context.Context, arg *MarkMissingVideoCommentsDeletedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markMissingVideoCommentsDeleted, arg.VideoID, arg.Source, arg.SeenIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const searchVideoComments = `-- name: SearchVideoComments :many
 SELECT c.id, c.video_id, c.source, c.comment_id, c.parent_id, c.author, c.author_id, c.author_url,
        c.published_at, c.like_count, c.text, c.created_at,
@@ -249,6 +335,8 @@ SELECT c.id, c.video_id, c.source, c.comment_id, c.parent_id, c.author, c.author
        COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
        COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
        COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited,
        COALESCE((SELECT count(*) FROM video_comments rc
                  WHERE rc.video_id = c.video_id AND rc.parent_id = c.comment_id), 0)::bigint AS reply_count
 FROM video_comments c
@@ -286,6 +374,8 @@ type SearchVideoCommentsRow struct {
 	AuthorIsUploader bool               `db:"author_is_uploader" json:"AuthorIsUploader"`
 	AuthorIsVerified bool               `db:"author_is_verified" json:"AuthorIsVerified"`
 	TimeText         string             `db:"time_text" json:"TimeText"`
+	IsDeleted        bool               `db:"is_deleted" json:"IsDeleted"`
+	IsEdited         bool               `db:"is_edited" json:"IsEdited"`
 	ReplyCount       int64              `db:"reply_count" json:"ReplyCount"`
 }
 
@@ -304,6 +394,8 @@ type SearchVideoCommentsRow struct {
 //	       COALESCE((c.raw->>'author_is_uploader')::boolean, false)::boolean AS author_is_uploader,
 //	       COALESCE((c.raw->>'author_is_verified')::boolean, false)::boolean AS author_is_verified,
 //	       COALESCE(c.raw->>'_time_text', '')::text                     AS time_text,
+//	       (c.deleted_at IS NOT NULL)::boolean                          AS is_deleted,
+//	       EXISTS (SELECT 1 FROM video_comment_edits e WHERE e.video_comment_id = c.id)::boolean AS is_edited,
 //	       COALESCE((SELECT count(*) FROM video_comments rc
 //	                 WHERE rc.video_id = c.video_id AND rc.parent_id = c.comment_id), 0)::bigint AS reply_count
 //	FROM video_comments c
@@ -346,6 +438,8 @@ func (q *Queries) SearchVideoComments(ctx context.Context, arg *SearchVideoComme
 			&i.AuthorIsUploader,
 			&i.AuthorIsVerified,
 			&i.TimeText,
+			&i.IsDeleted,
+			&i.IsEdited,
 			&i.ReplyCount,
 		); err != nil {
 			return nil, err
@@ -402,6 +496,8 @@ FROM jsonb_array_elements($3::jsonb) AS c
 WHERE COALESCE(NULLIF(c->>'id', ''), '') <> ''
 ON CONFLICT (video_id, source, comment_id)
 DO UPDATE SET
+    last_seen_at = NOW(),
+    deleted_at = NULL,
     parent_id = EXCLUDED.parent_id,
     author = EXCLUDED.author,
     author_id = EXCLUDED.author_id,
@@ -468,6 +564,8 @@ type UpsertVideoCommentsFromJSONParams struct {
 //	WHERE COALESCE(NULLIF(c->>'id', ''), '') <> ''
 //	ON CONFLICT (video_id, source, comment_id)
 //	DO UPDATE SET
+//	    last_seen_at = NOW(),
+//	    deleted_at = NULL,
 //	    parent_id = EXCLUDED.parent_id,
 //	    author = EXCLUDED.author,
 //	    author_id = EXCLUDED.author_id,
@@ -129,6 +129,41 @@ func (q *Queries) ClearVideoFromJobs(ctx context.Context, videoID pgtype.UUID) e
 	return err
 }
 
+const countVideos = `-- name: CountVideos :one
+SELECT COUNT(*) FROM videos
+`
+
+// CountVideos returns the total number of archived videos.
+//
+//	SELECT COUNT(*) FROM videos
+func (q *Queries) CountVideos(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countVideos)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countVideosMissingPreview = `-- name: CountVideosMissingPreview :one
+SELECT COUNT(*)
+FROM videos
+WHERE video_path IS NOT NULL AND btrim(video_path) <> ''
+AND assets_status @> '{"preview": false}'::jsonb
+`
+
+// CountVideosMissingPreview returns the number of videos whose preview failed
+// or was skipped (assets_status.preview = false).
+//
+//	SELECT COUNT(*)
+//	FROM videos
+//	WHERE video_path IS NOT NULL AND btrim(video_path) <> ''
+//	AND assets_status @> '{"preview": false}'::jsonb
+func (q *Queries) CountVideosMissingPreview(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countVideosMissingPreview)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countVideosWithAssetErrors = `-- name: CountVideosWithAssetErrors :one
 SELECT COUNT(*)
 FROM videos
@@ -151,6 +186,20 @@ func (q *Queries) CountVideosWithAssetErrors(ctx context.Context) (int64, error)
 	return count, err
 }
 
+const countVideosWithPHash = `-- name: CountVideosWithPHash :one
+SELECT COUNT(*) FROM videos WHERE phash IS NOT NULL
+`
+
+// CountVideosWithPHash returns how many videos have a perceptual hash.
+//
+//	SELECT COUNT(*) FROM videos WHERE phash IS NOT NULL
+func (q *Queries) CountVideosWithPHash(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countVideosWithPHash)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countVideosWithVideoPath = `-- name: CountVideosWithVideoPath :one
 SELECT COUNT(*)
 FROM videos
@@ -183,21 +232,45 @@ func (q *Queries) DeleteVideo(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const deleteVideoPHashPairs = `-- name: DeleteVideoPHashPairs :exec
+DELETE FROM video_phash_pairs
+WHERE video_id = $1 OR other_id = $1
+`
+
+// DeleteVideoPHashPairs removes a video's near-duplicate pairs, before
+// InsertVideoPHashPairs stores them again for a new hash.
+//
+//	DELETE FROM video_phash_pairs
+//	WHERE video_id = $1 OR other_id = $1
+func (q *Queries) DeleteVideoPHashPairs(ctx context.Context, videoID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteVideoPHashPairs, videoID)
+	return err
+}
+
 const filterExistingVideoIDs = `-- name: FilterExistingVideoIDs :many
 SELECT id
 FROM videos
 WHERE id = ANY($1::uuid[])
+  AND ($2::bool OR NOT metadata_only)
 `
 
+type FilterExistingVideoIDsParams struct {
+	Ids          []pgtype.UUID `db:"ids" json:"Ids"`
+	MetadataOnly bool          `db:"metadata_only" json:"MetadataOnly"`
+}
+
 // FilterExistingVideoIDs returns, from the given candidate ids, the subset that
 // already exist as videos. Used to skip re-downloading already-archived videos
 // when expanding a playlist/channel (video ids are deterministic UUIDv5s).
+// Metadata-only videos count as archived only for a metadata-only expansion,
+// so a full playlist archive downloads their files.
 //
 //	SELECT id
 //	FROM videos
 //	WHERE id = ANY($1::uuid[])
-func (q *Queries) FilterExistingVideoIDs(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error) {
-	rows, err := q.db.Query(ctx, filterExistingVideoIDs, ids)
+//	  AND ($2::bool OR NOT metadata_only)
+func (q *Queries) FilterExistingVideoIDs(ctx context.Context, arg *FilterExistingVideoIDsParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, filterExistingVideoIDs, arg.Ids, arg.MetadataOnly)
 	if err != nil {
 		return nil, err
 	}
@@ -216,6 +289,142 @@ func (q *Queries) FilterExistingVideoIDs(ctx context.Context, ids []pgtype.UUID)
 	return items, nil
 }
 
+const findSimilarVideosByPHash = `-- name: FindSimilarVideosByPHash :many
+SELECT b.id, b.created_at, b.updated_at, b.src, b.archived_by, b.title, b.info, b.comments, b.video_path, b.thumbnail_path, b.description, b.tags, b.uploader, b.uploader_id, b.channel_id, b.upload_date, b.duration_seconds, b.view_count, b.like_count, b.thumb_gradient_start, b.thumb_gradient_end, b.thumb_gradient_angle, b.file_hash, b.file_size, b.assets_status, b.search, b.probe_data, b.comments_checked_at, b.phash, b.phash_frames, b.metadata_only, b.auto_tagged_at, b.thumbnail_phash
+FROM videos a
+JOIN videos b ON b.id <> a.id
+CROSS JOIN LATERAL (
+    SELECT CASE
+        WHEN a.phash IS NOT NULL AND b.phash IS NOT NULL
+            THEN bit_count((a.phash # b.phash)::bit(64))
+        ELSE bit_count((a.thumbnail_phash # b.thumbnail_phash)::bit(64))
+    END AS distance
+) d
+WHERE a.id = $1
+  AND d.distance <= $2::int
+  AND (
+    a.duration_seconds IS NULL OR b.duration_seconds IS NULL
+    OR abs(a.duration_seconds - b.duration_seconds) <= GREATEST(2, a.duration_seconds * 0.02)
+  )
+ORDER BY d.distance ASC, b.created_at DESC
+LIMIT $3
+`
+
+type FindSimilarVideosByPHashParams struct {
+	VideoID     pgtype.UUID `db:"video_id" json:"VideoID"`
+	MaxDistance int32       `db:"max_distance" json:"MaxDistance"`
+	MaxCount    int32       `db:"max_count" json:"MaxCount"`
+}
+
+// FindSimilarVideosByPHash returns other videos within max_distance bits of
+// video_id, closest first. Whole-video hashes are compared when both videos
+// have one, thumbnail hashes otherwise; the same duration guard as
+// ListNearDuplicateVideos applies.
+//
+//	SELECT b.id, b.created_at, b.updated_at, b.src, b.archived_by, b.title, b.info, b.comments, b.video_path, b.thumbnail_path, b.description, b.tags, b.uploader, b.uploader_id, b.channel_id, b.upload_date, b.duration_seconds, b.view_count, b.like_count, b.thumb_gradient_start, b.thumb_gradient_end, b.thumb_gradient_angle, b.file_hash, b.file_size, b.assets_status, b.search, b.probe_data, b.comments_checked_at, b.phash, b.phash_frames, b.metadata_only, b.auto_tagged_at, b.thumbnail_phash
+//	FROM videos a
+//	JOIN videos b ON b.id <> a.id
+//	CROSS JOIN LATERAL (
+//	    SELECT CASE
+//	        WHEN a.phash IS NOT NULL AND b.phash IS NOT NULL
+//	            THEN bit_count((a.phash # b.phash)::bit(64))
+//	        ELSE bit_count((a.thumbnail_phash # b.thumbnail_phash)::bit(64))
+//	    END AS distance
+//	) d
+//	WHERE a.id = $1
+//	  AND d.distance <= $2::int
+//	  AND (
+//	    a.duration_seconds IS NULL OR b.duration_seconds IS NULL
+//	    OR abs(a.duration_seconds - b.duration_seconds) <= GREATEST(2, a.duration_seconds * 0.02)
+//	  )
+//	ORDER BY d.distance ASC, b.created_at DESC
+//	LIMIT $3
+func (q *Queries) FindSimilarVideosByPHash(ctx context.Context, arg *FindSimilarVideosByPHashParams) ([]*Video, error) {
+	rows, err := q.db.Query(ctx, findSimilarVideosByPHash, arg.VideoID, arg.MaxDistance, arg.MaxCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Src,
+			&i.ArchivedBy,
+			&i.Title,
+			&i.Info,
+			&i.Comments,
+			&i.VideoPath,
+			&i.ThumbnailPath,
+			&i.Description,
+			&i.Tags,
+			&i.Uploader,
+			&i.UploaderID,
+			&i.ChannelID,
+			&i.UploadDate,
+			&i.DurationSeconds,
+			&i.ViewCount,
+			&i.LikeCount,
+			&i.ThumbGradientStart,
+			&i.ThumbGradientEnd,
+			&i.ThumbGradientAngle,
+			&i.FileHash,
+			&i.FileSize,
+			&i.AssetsStatus,
+			&i.Search,
+			&i.ProbeData,
+			&i.CommentsCheckedAt,
+			&i.Phash,
+			&i.PhashFrames,
+			&i.MetadataOnly,
+			&i.AutoTaggedAt,
+			&i.ThumbnailPhash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCaptionCoverage = `-- name: GetCaptionCoverage :one
+SELECT
+    COUNT(*) AS total_count,
+    COUNT(*) FILTER (WHERE EXISTS (
+        SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id
+    )) AS captioned_count
+FROM videos v
+WHERE $1::text IS NULL OR v.channel_id = $1
+`
+
+type GetCaptionCoverageRow struct {
+	TotalCount     int64 `db:"total_count" json:"TotalCount"`
+	CaptionedCount int64 `db:"captioned_count" json:"CaptionedCount"`
+}
+
+// GetCaptionCoverage counts videos with and without any transcript,
+// optionally limited to one channel.
+//
+//	SELECT
+//	    COUNT(*) AS total_count,
+//	    COUNT(*) FILTER (WHERE EXISTS (
+//	        SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id
+//	    )) AS captioned_count
+//	FROM videos v
+//	WHERE $1::text IS NULL OR v.channel_id = $1
+func (q *Queries) GetCaptionCoverage(ctx context.Context, channelID *string) (*GetCaptionCoverageRow, error) {
+	row := q.db.QueryRow(ctx, getCaptionCoverage, channelID)
+	var i GetCaptionCoverageRow
+	err := row.Scan(&i.TotalCount, &i.CaptionedCount)
+	return &i, err
+}
+
 const insertVideo = `-- name: InsertVideo :one
 INSERT INTO videos (
     id,
@@ -301,7 +510,7 @@ DO UPDATE SET
     file_size = EXCLUDED.file_size,
     probe_data = COALESCE(EXCLUDED.probe_data, videos.probe_data),
     search = EXCLUDED.search
-RETURNING id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+RETURNING id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 `
 
 type InsertVideoParams struct {
@@ -416,7 +625,7 @@ type InsertVideoParams struct {
 //	    file_size = EXCLUDED.file_size,
 //	    probe_data = COALESCE(EXCLUDED.probe_data, videos.probe_data),
 //	    search = EXCLUDED.search
-//	RETURNING id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+//	RETURNING id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 func (q *Queries) InsertVideo(ctx context.Context, arg *InsertVideoParams) (*Video, error) {
 	row := q.db.QueryRow(ctx, insertVideo,
 		arg.ID,
@@ -473,10 +682,344 @@ func (q *Queries) InsertVideo(ctx context.Context, arg *InsertVideoParams) (*Vid
 		&i.Search,
 		&i.ProbeData,
 		&i.CommentsCheckedAt,
+		&i.Phash,
+		&i.PhashFrames,
+		&i.MetadataOnly,
+		&i.AutoTaggedAt,
+		&i.ThumbnailPhash,
 	)
 	return &i, err
 }
 
+const insertVideoPHashPairs = `-- name: InsertVideoPHashPairs :exec
+INSERT INTO video_phash_pairs (video_id, other_id, distance)
+SELECT LEAST(a.id, b.id), GREATEST(a.id, b.id), bit_count((a.phash # b.phash)::bit(64))
+FROM videos a
+JOIN videos b ON b.id <> a.id
+WHERE a.id = $1
+  AND a.phash IS NOT NULL
+  AND b.phash IS NOT NULL
+  AND bit_count((a.phash # b.phash)::bit(64)) <= $2::int
+ON CONFLICT (video_id, other_id) DO UPDATE SET distance = EXCLUDED.distance
+`
+
+type InsertVideoPHashPairsParams struct {
+	VideoID     pgtype.UUID `db:"video_id" json:"VideoID"`
+	MaxDistance int32       `db:"max_distance" json:"MaxDistance"`
+}
+
+// InsertVideoPHashPairs stores the pairs of a video with every other hashed
+// video within max_distance bits, for ListNearDuplicateVideos. Comparing one
+// hash with the rest is a single scan, done as each hash is stored.
+//
+//	INSERT INTO video_phash_pairs (video_id, other_id, distance)
+//	SELECT LEAST(a.id, b.id), GREATEST(a.id, b.id), bit_count((a.phash # b.phash)::bit(64))
+//	FROM videos a
+//	JOIN videos b ON b.id <> a.id
+//	WHERE a.id = $1
+//	  AND a.phash IS NOT NULL
+//	  AND b.phash IS NOT NULL
+//	  AND bit_count((a.phash # b.phash)::bit(64)) <= $2::int
+//	ON CONFLICT (video_id, other_id) DO UPDATE SET distance = EXCLUDED.distance
+func (q *Queries) InsertVideoPHashPairs(ctx context.Context, arg *InsertVideoPHashPairsParams) error {
+	_, err := q.db.Exec(ctx, insertVideoPHashPairs, arg.VideoID, arg.MaxDistance)
+	return err
+}
+
+const listCaptionAuditVideos = `-- name: ListCaptionAuditVideos :many
+SELECT
+    v.id,
+    v.title,
+    v.uploader,
+    v.channel_id,
+    v.created_at,
+    COALESCE(array_agg(t.lang::text ORDER BY t.lang) FILTER (WHERE t.lang IS NOT NULL), '{}')::text[] AS langs
+FROM videos v
+LEFT JOIN video_transcripts t ON t.video_id = v.id
+WHERE $1::text IS NULL OR v.channel_id = $1
+GROUP BY v.id
+HAVING (
+        $2::text IS NULL
+        OR ($2 = 'missing' AND COUNT(t.id) = 0)
+        OR ($2 = 'present' AND COUNT(t.id) > 0)
+    )
+    AND ($3::text IS NULL OR COALESCE(bool_or(t.lang::text = $3), false))
+ORDER BY v.created_at DESC
+LIMIT $4
+`
+
+type ListCaptionAuditVideosParams struct {
+	ChannelID *string `db:"channel_id" json:"ChannelID"`
+	Presence  *string `db:"presence" json:"Presence"`
+	Lang      *string `db:"lang" json:"Lang"`
+	PageLimit int32   `db:"page_limit" json:"PageLimit"`
+}
+
+type ListCaptionAuditVideosRow struct {
+	ID        pgtype.UUID        `db:"id" json:"ID"`
+	Title     string             `db:"title" json:"Title"`
+	Uploader  string             `db:"uploader" json:"Uploader"`
+	ChannelID *string            `db:"channel_id" json:"ChannelID"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"CreatedAt"`
+	Langs     []string           `db:"langs" json:"Langs"`
+}
+
+// ListCaptionAuditVideos lists videos with their transcript languages.
+// presence is 'missing' (no transcripts), 'present' (at least one) or NULL
+// (all); lang keeps only videos with a transcript in that language.
+//
+//	SELECT
+//	    v.id,
+//	    v.title,
+//	    v.uploader,
+//	    v.channel_id,
+//	    v.created_at,
+//	    COALESCE(array_agg(t.lang::text ORDER BY t.lang) FILTER (WHERE t.lang IS NOT NULL), '{}')::text[] AS langs
+//	FROM videos v
+//	LEFT JOIN video_transcripts t ON t.video_id = v.id
+//	WHERE $1::text IS NULL OR v.channel_id = $1
+//	GROUP BY v.id
+//	HAVING (
+//	        $2::text IS NULL
+//	        OR ($2 = 'missing' AND COUNT(t.id) = 0)
+//	        OR ($2 = 'present' AND COUNT(t.id) > 0)
+//	    )
+//	    AND ($3::text IS NULL OR COALESCE(bool_or(t.lang::text = $3), false))
+//	ORDER BY v.created_at DESC
+//	LIMIT $4
+func (q *Queries) ListCaptionAuditVideos(ctx context.Context, arg *ListCaptionAuditVideosParams) ([]*ListCaptionAuditVideosRow, error) {
+	rows, err := q.db.Query(ctx, listCaptionAuditVideos,
+		arg.ChannelID,
+		arg.Presence,
+		arg.Lang,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListCaptionAuditVideosRow
+	for rows.Next() {
+		var i ListCaptionAuditVideosRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Uploader,
+			&i.ChannelID,
+			&i.CreatedAt,
+			&i.Langs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCaptionChannels = `-- name: ListCaptionChannels :many
+SELECT
+    v.channel_id::text AS channel_id,
+    MAX(v.uploader)::text AS uploader,
+    COUNT(*) AS total_count,
+    COUNT(*) FILTER (WHERE NOT EXISTS (
+        SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id
+    )) AS missing_count
+FROM videos v
+WHERE v.channel_id IS NOT NULL AND v.channel_id <> ''
+GROUP BY v.channel_id
+ORDER BY missing_count DESC, total_count DESC
+LIMIT 200
+`
+
+type ListCaptionChannelsRow struct {
+	ChannelID    string `db:"channel_id" json:"ChannelID"`
+	Uploader     string `db:"uploader" json:"Uploader"`
+	TotalCount   int64  `db:"total_count" json:"TotalCount"`
+	MissingCount int64  `db:"missing_count" json:"MissingCount"`
+}
+
+// ListCaptionChannels returns channels with their video and caption-less
+// counts, most caption-less first, for the caption audit filter.
+//
+//	SELECT
+//	    v.channel_id::text AS channel_id,
+//	    MAX(v.uploader)::text AS uploader,
+//	    COUNT(*) AS total_count,
+//	    COUNT(*) FILTER (WHERE NOT EXISTS (
+//	        SELECT 1 FROM video_transcripts t WHERE t.video_id = v.id
+//	    )) AS missing_count
+//	FROM videos v
+//	WHERE v.channel_id IS NOT NULL AND v.channel_id <> ''
+//	GROUP BY v.channel_id
+//	ORDER BY missing_count DESC, total_count DESC
+//	LIMIT 200
+func (q *Queries) ListCaptionChannels(ctx context.Context) ([]*ListCaptionChannelsRow, error) {
+	rows, err := q.db.Query(ctx, listCaptionChannels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListCaptionChannelsRow
+	for rows.Next() {
+		var i ListCaptionChannelsRow
+		if err := rows.Scan(
+			&i.ChannelID,
+			&i.Uploader,
+			&i.TotalCount,
+			&i.MissingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCaptionLanguageCounts = `-- name: ListCaptionLanguageCounts :many
+SELECT t.lang::text AS lang, COUNT(DISTINCT t.video_id) AS video_count
+FROM video_transcripts t
+JOIN videos v ON v.id = t.video_id
+WHERE $1::text IS NULL OR v.channel_id = $1
+GROUP BY t.lang
+ORDER BY video_count DESC, lang
+`
+
+type ListCaptionLanguageCountsRow struct {
+	Lang       string `db:"lang" json:"Lang"`
+	VideoCount int64  `db:"video_count" json:"VideoCount"`
+}
+
+// ListCaptionLanguageCounts returns how many videos have a transcript in each
+// language, optionally limited to one channel.
+//
+//	SELECT t.lang::text AS lang, COUNT(DISTINCT t.video_id) AS video_count
+//	FROM video_transcripts t
+//	JOIN videos v ON v.id = t.video_id
+//	WHERE $1::text IS NULL OR v.channel_id = $1
+//	GROUP BY t.lang
+//	ORDER BY video_count DESC, lang
+func (q *Queries) ListCaptionLanguageCounts(ctx context.Context, channelID *string) ([]*ListCaptionLanguageCountsRow, error) {
+	rows, err := q.db.Query(ctx, listCaptionLanguageCounts, channelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListCaptionLanguageCountsRow
+	for rows.Next() {
+		var i ListCaptionLanguageCountsRow
+		if err := rows.Scan(&i.Lang, &i.VideoCount); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNearDuplicateVideos = `-- name: ListNearDuplicateVideos :many
+SELECT
+    a.id AS video_id,
+    a.title AS video_title,
+    a.file_hash AS video_file_hash,
+    a.phash_frames::bigint[] AS video_frames,
+    b.id AS other_id,
+    b.title AS other_title,
+    b.file_hash AS other_file_hash,
+    b.phash_frames::bigint[] AS other_frames,
+    p.distance::int AS distance
+FROM video_phash_pairs p
+JOIN videos a ON a.id = p.video_id
+JOIN videos b ON b.id = p.other_id
+WHERE p.distance <= $1::int
+  AND (
+    a.duration_seconds IS NULL OR b.duration_seconds IS NULL
+    OR abs(a.duration_seconds - b.duration_seconds) <= GREATEST(2, a.duration_seconds * 0.02)
+  )
+ORDER BY p.distance ASC, p.video_id, p.other_id
+LIMIT $2
+`
+
+type ListNearDuplicateVideosParams struct {
+	MaxDistance int32 `db:"max_distance" json:"MaxDistance"`
+	MaxCount    int32 `db:"max_count" json:"MaxCount"`
+}
+
+type ListNearDuplicateVideosRow struct {
+	VideoID       pgtype.UUID `db:"video_id" json:"VideoID"`
+	VideoTitle    string      `db:"video_title" json:"VideoTitle"`
+	VideoFileHash *string     `db:"video_file_hash" json:"VideoFileHash"`
+	VideoFrames   []int64     `db:"video_frames" json:"VideoFrames"`
+	OtherID       pgtype.UUID `db:"other_id" json:"OtherID"`
+	OtherTitle    string      `db:"other_title" json:"OtherTitle"`
+	OtherFileHash *string     `db:"other_file_hash" json:"OtherFileHash"`
+	OtherFrames   []int64     `db:"other_frames" json:"OtherFrames"`
+	Distance      int32       `db:"distance" json:"Distance"`
+}
+
+// ListNearDuplicateVideos returns pairs of videos whose combined perceptual
+// hashes are within max_distance bits, skipping pairs whose durations differ
+// by more than 2s or 2% (a re-encode keeps its length). It reads the pairs
+// InsertVideoPHashPairs stored, so max_distance above the distance they were
+// stored with finds nothing more.
+//
+//	SELECT
+//	    a.id AS video_id,
+//	    a.title AS video_title,
+//	    a.file_hash AS video_file_hash,
+//	    a.phash_frames::bigint[] AS video_frames,
+//	    b.id AS other_id,
+//	    b.title AS other_title,
+//	    b.file_hash AS other_file_hash,
+//	    b.phash_frames::bigint[] AS other_frames,
+//	    p.distance::int AS distance
+//	FROM video_phash_pairs p
+//	JOIN videos a ON a.id = p.video_id
+//	JOIN videos b ON b.id = p.other_id
+//	WHERE p.distance <= $1::int
+//	  AND (
+//	    a.duration_seconds IS NULL OR b.duration_seconds IS NULL
+//	    OR abs(a.duration_seconds - b.duration_seconds) <= GREATEST(2, a.duration_seconds * 0.02)
+//	  )
+//	ORDER BY p.distance ASC, p.video_id, p.other_id
+//	LIMIT $2
+func (q *Queries) ListNearDuplicateVideos(ctx context.Context, arg *ListNearDuplicateVideosParams) ([]*ListNearDuplicateVideosRow, error) {
+	rows, err := q.db.Query(ctx, listNearDuplicateVideos, arg.MaxDistance, arg.MaxCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListNearDuplicateVideosRow
+	for rows.Next() {
+		var i ListNearDuplicateVideosRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.VideoTitle,
+			&i.VideoFileHash,
+			&i.VideoFrames,
+			&i.OtherID,
+			&i.OtherTitle,
+			&i.OtherFileHash,
+			&i.OtherFrames,
+			&i.Distance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVideosForAssetCatchup = `-- name: ListVideosForAssetCatchup :many
 SELECT id::text, video_path, thumbnail_path, file_hash, duration_seconds, assets_status
 FROM videos
@@ -488,6 +1031,7 @@ AND (
     OR NOT (assets_status ?& array['thumbnail','preview','waveform','file_hash','seek','faststart'])
     OR assets_status @> '{"thumbnail": false}'::jsonb
     OR assets_status @> '{"preview": false}'::jsonb
+    OR assets_status @> '{"preview_sprite": false}'::jsonb
     OR assets_status @> '{"waveform": false}'::jsonb
     OR assets_status @> '{"file_hash": false}'::jsonb
     OR assets_status @> '{"seek": false}'::jsonb
@@ -531,6 +1075,7 @@ type ListVideosForAssetCatchupRow struct {
 //	    OR NOT (assets_status ?& array['thumbnail','preview','waveform','file_hash','seek','faststart'])
 //	    OR assets_status @> '{"thumbnail": false}'::jsonb
 //	    OR assets_status @> '{"preview": false}'::jsonb
+//	    OR assets_status @> '{"preview_sprite": false}'::jsonb
 //	    OR assets_status @> '{"waveform": false}'::jsonb
 //	    OR assets_status @> '{"file_hash": false}'::jsonb
 //	    OR assets_status @> '{"seek": false}'::jsonb
@@ -577,20 +1122,149 @@ func (q *Queries) ListVideosForAssetCatchup(ctx context.Context, limit int32) ([
 	return items, nil
 }
 
+const listVideosForManifest = `-- name: ListVideosForManifest :many
+SELECT
+    id,
+    created_at,
+    updated_at,
+    src,
+    title,
+    description,
+    tags,
+    uploader,
+    uploader_id,
+    channel_id,
+    upload_date,
+    duration_seconds,
+    video_path,
+    thumbnail_path,
+    file_hash,
+    file_size,
+    metadata_only,
+    assets_status,
+    info,
+    probe_data
+FROM videos
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListVideosForManifestParams struct {
+	AfterID   pgtype.UUID `db:"after_id" json:"AfterID"`
+	PageLimit int32       `db:"page_limit" json:"PageLimit"`
+}
+
+type ListVideosForManifestRow struct {
+	ID              pgtype.UUID          `db:"id" json:"ID"`
+	CreatedAt       pgtype.Timestamptz   `db:"created_at" json:"CreatedAt"`
+	UpdatedAt       pgtype.Timestamptz   `db:"updated_at" json:"UpdatedAt"`
+	Src             string               `db:"src" json:"Src"`
+	Title           string               `db:"title" json:"Title"`
+	Description     string               `db:"description" json:"Description"`
+	Tags            []string             `db:"tags" json:"Tags"`
+	Uploader        string               `db:"uploader" json:"Uploader"`
+	UploaderID      *string              `db:"uploader_id" json:"UploaderID"`
+	ChannelID       *string              `db:"channel_id" json:"ChannelID"`
+	UploadDate      pgtype.Date          `db:"upload_date" json:"UploadDate"`
+	DurationSeconds *int32               `db:"duration_seconds" json:"DurationSeconds"`
+	VideoPath       *string              `db:"video_path" json:"VideoPath"`
+	ThumbnailPath   *string              `db:"thumbnail_path" json:"ThumbnailPath"`
+	FileHash        *string              `db:"file_hash" json:"FileHash"`
+	FileSize        *int64               `db:"file_size" json:"FileSize"`
+	MetadataOnly    bool                 `db:"metadata_only" json:"MetadataOnly"`
+	AssetsStatus    AssetMap             `db:"assets_status" json:"AssetsStatus"`
+	Info            videoinfo.VideoInfo  `db:"info" json:"Info"`
+	ProbeData       *videoinfo.ProbeInfo `db:"probe_data" json:"ProbeData"`
+}
+
+// ListVideosForManifest pages through every video in id order for the
+// archive manifest. Pass the last id of the previous page as after_id
+// (the nil UUID for the first page).
+//
+//	SELECT
+//	    id,
+//	    created_at,
+//	    updated_at,
+//	    src,
+//	    title,
+//	    description,
+//	    tags,
+//	    uploader,
+//	    uploader_id,
+//	    channel_id,
+//	    upload_date,
+//	    duration_seconds,
+//	    video_path,
+//	    thumbnail_path,
+//	    file_hash,
+//	    file_size,
+//	    metadata_only,
+//	    assets_status,
+//	    info,
+//	    probe_data
+//	FROM videos
+//	WHERE id > $1
+//	ORDER BY id
+//	LIMIT $2
+func (q *Queries) ListVideosForManifest(ctx context.Context, arg *ListVideosForManifestParams) ([]*ListVideosForManifestRow, error) {
+	rows, err := q.db.Query(ctx, listVideosForManifest, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListVideosForManifestRow
+	for rows.Next() {
+		var i ListVideosForManifestRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Src,
+			&i.Title,
+			&i.Description,
+			&i.Tags,
+			&i.Uploader,
+			&i.UploaderID,
+			&i.ChannelID,
+			&i.UploadDate,
+			&i.DurationSeconds,
+			&i.VideoPath,
+			&i.ThumbnailPath,
+			&i.FileHash,
+			&i.FileSize,
+			&i.MetadataOnly,
+			&i.AssetsStatus,
+			&i.Info,
+			&i.ProbeData,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVideosMissingVideoPath = `-- name: ListVideosMissingVideoPath :many
 SELECT id::text AS id
 FROM videos
-WHERE video_path IS NULL OR btrim(video_path) = ''
+WHERE (video_path IS NULL OR btrim(video_path) = '')
+  AND NOT metadata_only
 ORDER BY updated_at ASC
 LIMIT $1
 `
 
 // ListVideosMissingVideoPath returns videos whose video_path is unset, for
 // disk-discovery recovery of ingests that never completed (file on disk, no path).
+// Metadata-only videos have no file by design and are skipped.
 //
 //	SELECT id::text AS id
 //	FROM videos
-//	WHERE video_path IS NULL OR btrim(video_path) = ''
+//	WHERE (video_path IS NULL OR btrim(video_path) = '')
+//	  AND NOT metadata_only
 //	ORDER BY updated_at ASC
 //	LIMIT $1
 func (q *Queries) ListVideosMissingVideoPath(ctx context.Context, limit int32) ([]string, error) {
@@ -613,6 +1287,100 @@ func (q *Queries) ListVideosMissingVideoPath(ctx context.Context, limit int32) (
 	return items, nil
 }
 
+const listVideosNeedingAutoTags = `-- name: ListVideosNeedingAutoTags :many
+SELECT v.id
+FROM videos v
+WHERE v.auto_tagged_at IS NULL
+  AND EXISTS (SELECT 1 FROM video_transcripts vt WHERE vt.video_id = v.id)
+ORDER BY v.created_at DESC
+LIMIT $1
+`
+
+// ListVideosNeedingAutoTags returns transcribed videos that have not been
+// auto-tagged yet, for backfill.
+//
+//	SELECT v.id
+//	FROM videos v
+//	WHERE v.auto_tagged_at IS NULL
+//	  AND EXISTS (SELECT 1 FROM video_transcripts vt WHERE vt.video_id = v.id)
+//	ORDER BY v.created_at DESC
+//	LIMIT $1
+func (q *Queries) ListVideosNeedingAutoTags(ctx context.Context, maxCount int32) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listVideosNeedingAutoTags, maxCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideosNeedingPHash = `-- name: ListVideosNeedingPHash :many
+SELECT v.id, v.video_path, v.duration_seconds
+FROM videos v
+LEFT JOIN video_phash_attempts a ON a.video_id = v.id
+WHERE v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+  AND v.phash IS NULL
+  AND NOT COALESCE(v.assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb
+  AND (a.attempted_at IS NULL OR a.attempted_at < $1)
+ORDER BY v.created_at DESC
+LIMIT $2
+`
+
+type ListVideosNeedingPHashParams struct {
+	RetryBefore pgtype.Timestamptz `db:"retry_before" json:"RetryBefore"`
+	MaxCount    int32              `db:"max_count" json:"MaxCount"`
+}
+
+type ListVideosNeedingPHashRow struct {
+	ID              pgtype.UUID `db:"id" json:"ID"`
+	VideoPath       *string     `db:"video_path" json:"VideoPath"`
+	DurationSeconds *int32      `db:"duration_seconds" json:"DurationSeconds"`
+}
+
+// ListVideosNeedingPHash returns videos with a video_path but no phash, for
+// backfill. Audio-only videos are skipped, as are videos whose last failed
+// attempt is newer than retry_before.
+//
+//	SELECT v.id, v.video_path, v.duration_seconds
+//	FROM videos v
+//	LEFT JOIN video_phash_attempts a ON a.video_id = v.id
+//	WHERE v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+//	  AND v.phash IS NULL
+//	  AND NOT COALESCE(v.assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb
+//	  AND (a.attempted_at IS NULL OR a.attempted_at < $1)
+//	ORDER BY v.created_at DESC
+//	LIMIT $2
+func (q *Queries) ListVideosNeedingPHash(ctx context.Context, arg *ListVideosNeedingPHashParams) ([]*ListVideosNeedingPHashRow, error) {
+	rows, err := q.db.Query(ctx, listVideosNeedingPHash, arg.RetryBefore, arg.MaxCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListVideosNeedingPHashRow
+	for rows.Next() {
+		var i ListVideosNeedingPHashRow
+		if err := rows.Scan(&i.ID, &i.VideoPath, &i.DurationSeconds); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVideosNeedingProbe = `-- name: ListVideosNeedingProbe :many
 SELECT id, video_path
 FROM videos
@@ -655,6 +1423,66 @@ func (q *Queries) ListVideosNeedingProbe(ctx context.Context, maxCount int32) ([
 	return items, nil
 }
 
+const listVideosNeedingThumbnailPHash = `-- name: ListVideosNeedingThumbnailPHash :many
+SELECT v.id, v.thumbnail_path
+FROM videos v
+LEFT JOIN video_thumbnail_phash_attempts a ON a.video_id = v.id
+WHERE v.thumbnail_phash IS NULL
+  AND v.thumbnail_path IS NOT NULL AND btrim(v.thumbnail_path) <> ''
+  AND v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+  AND NOT v.metadata_only
+  AND NOT COALESCE(v.assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb
+  AND (a.attempted_at IS NULL OR a.attempted_at < $1)
+ORDER BY v.created_at DESC
+LIMIT $2
+`
+
+type ListVideosNeedingThumbnailPHashParams struct {
+	RetryBefore pgtype.Timestamptz `db:"retry_before" json:"RetryBefore"`
+	MaxCount    int32              `db:"max_count" json:"MaxCount"`
+}
+
+type ListVideosNeedingThumbnailPHashRow struct {
+	ID            pgtype.UUID `db:"id" json:"ID"`
+	ThumbnailPath *string     `db:"thumbnail_path" json:"ThumbnailPath"`
+}
+
+// ListVideosNeedingThumbnailPHash returns videos with a thumbnail but no
+// thumbnail_phash, for backfill. Audio-only and metadata-only videos are
+// skipped, since their thumbnail is not a frame of the video, as are videos
+// whose last failed attempt is newer than retry_before.
+//
+//	SELECT v.id, v.thumbnail_path
+//	FROM videos v
+//	LEFT JOIN video_thumbnail_phash_attempts a ON a.video_id = v.id
+//	WHERE v.thumbnail_phash IS NULL
+//	  AND v.thumbnail_path IS NOT NULL AND btrim(v.thumbnail_path) <> ''
+//	  AND v.video_path IS NOT NULL AND btrim(v.video_path) <> ''
+//	  AND NOT v.metadata_only
+//	  AND NOT COALESCE(v.assets_status, '{}'::jsonb) @> '{"seek": "n/a"}'::jsonb
+//	  AND (a.attempted_at IS NULL OR a.attempted_at < $1)
+//	ORDER BY v.created_at DESC
+//	LIMIT $2
+func (q *Queries) ListVideosNeedingThumbnailPHash(ctx context.Context, arg *ListVideosNeedingThumbnailPHashParams) ([]*ListVideosNeedingThumbnailPHashRow, error) {
+	rows, err := q.db.Query(ctx, listVideosNeedingThumbnailPHash, arg.RetryBefore, arg.MaxCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListVideosNeedingThumbnailPHashRow
+	for rows.Next() {
+		var i ListVideosNeedingThumbnailPHashRow
+		if err := rows.Scan(&i.ID, &i.ThumbnailPath); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVideosWithAssetErrors = `-- name: ListVideosWithAssetErrors :many
 SELECT id::text, title, video_path, assets_status, updated_at
 FROM videos
@@ -708,15 +1536,61 @@ func (q *Queries) ListVideosWithAssetErrors(ctx context.Context, limit int32) ([
 	return items, nil
 }
 
+const markVideoAutoTagged = `-- name: MarkVideoAutoTagged :exec
+UPDATE videos SET auto_tagged_at = NOW() WHERE id = $1
+`
+
+// MarkVideoAutoTagged records that a video's transcript keywords were tagged.
+//
+//	UPDATE videos SET auto_tagged_at = NOW() WHERE id = $1
+func (q *Queries) MarkVideoAutoTagged(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markVideoAutoTagged, id)
+	return err
+}
+
+const markVideoPHashAttempted = `-- name: MarkVideoPHashAttempted :exec
+INSERT INTO video_phash_attempts (video_id, attempted_at)
+VALUES ($1, NOW())
+ON CONFLICT (video_id) DO UPDATE SET attempted_at = EXCLUDED.attempted_at
+`
+
+// MarkVideoPHashAttempted records a failed perceptual hash attempt, holding
+// the video back from the backfill until its retry delay has passed.
+//
+//	INSERT INTO video_phash_attempts (video_id, attempted_at)
+//	VALUES ($1, NOW())
+//	ON CONFLICT (video_id) DO UPDATE SET attempted_at = EXCLUDED.attempted_at
+func (q *Queries) MarkVideoPHashAttempted(ctx context.Context, videoID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markVideoPHashAttempted, videoID)
+	return err
+}
+
+const markVideoThumbnailPHashAttempted = `-- name: MarkVideoThumbnailPHashAttempted :exec
+INSERT INTO video_thumbnail_phash_attempts (video_id, attempted_at)
+VALUES ($1, NOW())
+ON CONFLICT (video_id) DO UPDATE SET attempted_at = EXCLUDED.attempted_at
+`
+
+// MarkVideoThumbnailPHashAttempted records a failed thumbnail hash attempt,
+// holding the video back from the backfill until its retry delay has passed.
+//
+//	INSERT INTO video_thumbnail_phash_attempts (video_id, attempted_at)
+//	VALUES ($1, NOW())
+//	ON CONFLICT (video_id) DO UPDATE SET attempted_at = EXCLUDED.attempted_at
+func (q *Queries) MarkVideoThumbnailPHashAttempted(ctx context.Context, videoID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markVideoThumbnailPHashAttempted, videoID)
+	return err
+}
+
 const selectVideoBySrc = `-- name: SelectVideoBySrc :one
-SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 FROM videos
 WHERE src = $1
 `
 
 // SelectVideoBySrc returns a video by src.
 //
-//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at
+//	SELECT id, created_at, updated_at, src, archived_by, title, info, comments, video_path, thumbnail_path, description, tags, uploader, uploader_id, channel_id, upload_date, duration_seconds, view_count, like_count, thumb_gradient_start, thumb_gradient_end, thumb_gradient_angle, file_hash, file_size, assets_status, search, probe_data, comments_checked_at, phash, phash_frames, metadata_only, auto_tagged_at, thumbnail_phash
 //	FROM videos
 //	WHERE src = $1
 func (q *Queries) SelectVideoBySrc(ctx context.Context, src string) (*Video, error) {
@@ -751,10 +1625,39 @@ func (q *Queries) SelectVideoBySrc(ctx context.Context, src string) (*Video, err
 		&i.Search,
 		&i.ProbeData,
 		&i.CommentsCheckedAt,
+		&i.Phash,
+		&i.PhashFrames,
+		&i.MetadataOnly,
+		&i.AutoTaggedAt,
+		&i.ThumbnailPhash,
 	)
 	return &i, err
 }
 
+const setVideoMetadataOnly = `-- name: SetVideoMetadataOnly :exec
+UPDATE videos
+SET metadata_only = $1,
+    updated_at = NOW()
+WHERE id = $2
+`
+
+type SetVideoMetadataOnlyParams struct {
+	MetadataOnly bool        `db:"metadata_only" json:"MetadataOnly"`
+	ID           pgtype.UUID `db:"id" json:"ID"`
+}
+
+// SetVideoMetadataOnly flags a video as archived without its media file, or
+// clears the flag once the full file has been ingested.
+//
+//	UPDATE videos
+//	SET metadata_only = $1,
+//	    updated_at = NOW()
+//	WHERE id = $2
+func (q *Queries) SetVideoMetadataOnly(ctx context.Context, arg *SetVideoMetadataOnlyParams) error {
+	_, err := q.db.Exec(ctx, setVideoMetadataOnly, arg.MetadataOnly, arg.ID)
+	return err
+}
+
 const updateVideoAssetsStatus = `-- name: UpdateVideoAssetsStatus :exec
 UPDATE videos
 SET assets_status = COALESCE(assets_status, '{}'::jsonb) || $1::asset_status_map,
@@ -804,6 +1707,32 @@ func (q *Queries) UpdateVideoFileHashAndSize(ctx context.Context, arg *UpdateVid
 	return err
 }
 
+const updateVideoPHash = `-- name: UpdateVideoPHash :exec
+UPDATE videos
+SET phash = $1,
+    phash_frames = $2::bigint[],
+    updated_at = NOW()
+WHERE id = $3
+`
+
+type UpdateVideoPHashParams struct {
+	Phash       *int64      `db:"phash" json:"Phash"`
+	PhashFrames []int64     `db:"phash_frames" json:"PhashFrames"`
+	ID          pgtype.UUID `db:"id" json:"ID"`
+}
+
+// UpdateVideoPHash stores the perceptual hash and per-frame hashes for a video.
+//
+//	UPDATE videos
+//	SET phash = $1,
+//	    phash_frames = $2::bigint[],
+//	    updated_at = NOW()
+//	WHERE id = $3
+func (q *Queries) UpdateVideoPHash(ctx context.Context, arg *UpdateVideoPHashParams) error {
+	_, err := q.db.Exec(ctx, updateVideoPHash, arg.Phash, arg.PhashFrames, arg.ID)
+	return err
+}
+
 const updateVideoPath = `-- name: UpdateVideoPath :exec
 UPDATE videos
 SET video_path = $1,
@@ -850,6 +1779,29 @@ func (q *Queries) UpdateVideoProbeData(ctx context.Context, arg *UpdateVideoProb
 	return err
 }
 
+const updateVideoThumbnailPHash = `-- name: UpdateVideoThumbnailPHash :exec
+UPDATE videos
+SET thumbnail_phash = $1,
+    updated_at = NOW()
+WHERE id = $2
+`
+
+type UpdateVideoThumbnailPHashParams struct {
+	ThumbnailPhash *int64      `db:"thumbnail_phash" json:"ThumbnailPhash"`
+	ID             pgtype.UUID `db:"id" json:"ID"`
+}
+
+// UpdateVideoThumbnailPHash stores the perceptual hash of a video's thumbnail.
+//
+//	UPDATE videos
+//	SET thumbnail_phash = $1,
+//	    updated_at = NOW()
+//	WHERE id = $2
+func (q *Queries) UpdateVideoThumbnailPHash(ctx context.Context, arg *UpdateVideoThumbnailPHashParams) error {
+	_, err := q.db.Exec(ctx, updateVideoThumbnailPHash, arg.ThumbnailPhash, arg.ID)
+	return err
+}
+
 const updateVideoThumbnailPath = `-- name: UpdateVideoThumbnailPath :exec
 UPDATE videos
 SET thumbnail_path = $1,
@@ -12,6 +12,376 @@ import (
 	"thirdcoast.systems/rewind/pkg/utils/language"
 )
 
+const countTranscribedVideos = `-- name: CountTranscribedVideos :one
+SELECT COUNT(DISTINCT video_id)::bigint FROM video_transcripts
+`
+
+// CountTranscribedVideos returns how many videos have a transcript, the
+// document count for keyword weighting.
+//
+//	SELECT COUNT(DISTINCT video_id)::bigint FROM video_transcripts
+func (q *Queries) CountTranscribedVideos(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countTranscribedVideos)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const countTranscriptTermVideos = `-- name: CountTranscriptTermVideos :many
+SELECT
+    t.term::text AS term,
+    (
+        SELECT COUNT(DISTINCT vt.video_id)
+        FROM video_transcripts vt
+        WHERE vt.search @@ plainto_tsquery('simple'::regconfig, t.term)
+    )::bigint AS videos
+FROM unnest($1::text[]) AS t(term)
+`
+
+type CountTranscriptTermVideosRow struct {
+	Term   string `db:"term" json:"Term"`
+	Videos int64  `db:"videos" json:"Videos"`
+}
+
+// CountTranscriptTermVideos returns, for each term, how many videos have a
+// transcript containing it. Matches the 'simple' search vector, so terms are
+// compared lowercased and unstemmed.
+//
+//	SELECT
+//	    t.term::text AS term,
+//	    (
+//	        SELECT COUNT(DISTINCT vt.video_id)
+//	        FROM video_transcripts vt
+//	        WHERE vt.search @@ plainto_tsquery('simple'::regconfig, t.term)
+//	    )::bigint AS videos
+//	FROM unnest($1::text[]) AS t(term)
+func (q *Queries) CountTranscriptTermVideos(ctx context.Context, terms []string) ([]*CountTranscriptTermVideosRow, error) {
+	rows, err := q.db.Query(ctx, countTranscriptTermVideos, terms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*CountTranscriptTermVideosRow
+	for rows.Next() {
+		var i CountTranscriptTermVideosRow
+		if err := rows.Scan(&i.Term, &i.Videos); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteVideoTranscriptWords = `-- name: DeleteVideoTranscriptWords :exec
+DELETE FROM video_transcript_words
+WHERE video_id = $1
+    AND lang = $2::language_tag
+`
+
+type DeleteVideoTranscriptWordsParams struct {
+	VideoID pgtype.UUID  `db:"video_id" json:"VideoID"`
+	Lang    language.Tag `db:"lang" json:"Lang"`
+}
+
+// DeleteVideoTranscriptWords removes the word timings of a video's transcript
+// in a language before it is replaced.
+//
+//	DELETE FROM video_transcript_words
+//	WHERE video_id = $1
+//	    AND lang = $2::language_tag
+func (q *Queries) DeleteVideoTranscriptWords(ctx context.Context, arg *DeleteVideoTranscriptWordsParams) error {
+	_, err := q.db.Exec(ctx, deleteVideoTranscriptWords, arg.VideoID, arg.Lang)
+	return err
+}
+
+const insertVideoTranscriptWords = `-- name: InsertVideoTranscriptWords :exec
+INSERT INTO video_transcript_words (video_id, lang, cue_index, word_index, start_offset, end_offset, word)
+SELECT $1, $2::language_tag, w.cue_index, w.word_index, w.start_offset, w.end_offset, w.word
+FROM unnest(
+    $3::int[],
+    $4::int[],
+    $5::double precision[],
+    $6::double precision[],
+    $7::text[]
+) AS w(cue_index, word_index, start_offset, end_offset, word)
+`
+
+type InsertVideoTranscriptWordsParams struct {
+	VideoID      pgtype.UUID  `db:"video_id" json:"VideoID"`
+	Lang         language.Tag `db:"lang" json:"Lang"`
+	CueIndexes   []int32      `db:"cue_indexes" json:"CueIndexes"`
+	WordIndexes  []int32      `db:"word_indexes" json:"WordIndexes"`
+	StartOffsets []float64    `db:"start_offsets" json:"StartOffsets"`
+	EndOffsets   []float64    `db:"end_offsets" json:"EndOffsets"`
+	Words        []string     `db:"words" json:"Words"`
+}
+
+// InsertVideoTranscriptWords stores word timings; the arrays are parallel,
+// one element per word.
+//
+//	INSERT INTO video_transcript_words (video_id, lang, cue_index, word_index, start_offset, end_offset, word)
+//	SELECT $1, $2::language_tag, w.cue_index, w.word_index, w.start_offset, w.end_offset, w.word
+//	FROM unnest(
+//	    $3::int[],
+//	    $4::int[],
+//	    $5::double precision[],
+//	    $6::double precision[],
+//	    $7::text[]
+//	) AS w(cue_index, word_index, start_offset, end_offset, word)
+func (q *Queries) InsertVideoTranscriptWords(ctx context.Context, arg *InsertVideoTranscriptWordsParams) error {
+	_, err := q.db.Exec(ctx, insertVideoTranscriptWords,
+		arg.VideoID,
+		arg.Lang,
+		arg.CueIndexes,
+		arg.WordIndexes,
+		arg.StartOffsets,
+		arg.EndOffsets,
+		arg.Words,
+	)
+	return err
+}
+
+const listTranscriptsForExport = `-- name: ListTranscriptsForExport :many
+SELECT
+    vt.video_id,
+    vt.lang::text AS lang,
+    vt.format,
+    vt.text,
+    vt.raw,
+    v.title,
+    v.uploader,
+    v.channel_id,
+    v.upload_date,
+    v.created_at AS archived_at
+FROM video_transcripts vt
+JOIN videos v ON v.id = vt.video_id
+WHERE (vt.video_id, vt.lang::text) > ($1::uuid, $2::text)
+    AND ($3::text IS NULL OR v.channel_id = $3)
+    AND (
+        $4::date IS NULL
+        OR ($5::text = 'published' AND v.upload_date >= $4)
+        OR ($5::text IS DISTINCT FROM 'published' AND v.created_at::date >= $4)
+    )
+    AND (
+        $6::date IS NULL
+        OR ($5::text = 'published' AND v.upload_date <= $6)
+        OR ($5::text IS DISTINCT FROM 'published' AND v.created_at::date <= $6)
+    )
+ORDER BY vt.video_id, vt.lang::text
+LIMIT $7
+`
+
+type ListTranscriptsForExportParams struct {
+	AfterVideoID pgtype.UUID `db:"after_video_id" json:"AfterVideoID"`
+	AfterLang    string      `db:"after_lang" json:"AfterLang"`
+	ChannelID    *string     `db:"channel_id" json:"ChannelID"`
+	DateFrom     pgtype.Date `db:"date_from" json:"DateFrom"`
+	DateType     *string     `db:"date_type" json:"DateType"`
+	DateTo       pgtype.Date `db:"date_to" json:"DateTo"`
+	PageLimit    int32       `db:"page_limit" json:"PageLimit"`
+}
+
+type ListTranscriptsForExportRow struct {
+	VideoID    pgtype.UUID        `db:"video_id" json:"VideoID"`
+	Lang       string             `db:"lang" json:"Lang"`
+	Format     string             `db:"format" json:"Format"`
+	Text       string             `db:"text" json:"Text"`
+	Raw        string             `db:"raw" json:"Raw"`
+	Title      string             `db:"title" json:"Title"`
+	Uploader   string             `db:"uploader" json:"Uploader"`
+	ChannelID  *string            `db:"channel_id" json:"ChannelID"`
+	UploadDate pgtype.Date        `db:"upload_date" json:"UploadDate"`
+	ArchivedAt pgtype.Timestamptz `db:"archived_at" json:"ArchivedAt"`
+}
+
+// ListTranscriptsForExport pages through transcripts joined with their video
+// metadata, keyset-ordered by (video_id, lang) so bulk exports can stream
+// without OFFSET scans. Pass the last row's (video_id, lang) as the cursor;
+// use the zero UUID and ” for the first page.
+//
+//	SELECT
+//	    vt.video_id,
+//	    vt.lang::text AS lang,
+//	    vt.format,
+//	    vt.text,
+//	    vt.raw,
+//	    v.title,
+//	    v.uploader,
+//	    v.channel_id,
+//	    v.upload_date,
+//	    v.created_at AS archived_at
+//	FROM video_transcripts vt
+//	JOIN videos v ON v.id = vt.video_id
+//	WHERE (vt.video_id, vt.lang::text) > ($1::uuid, $2::text)
+//	    AND ($3::text IS NULL OR v.channel_id = $3)
+//	    AND (
+//	        $4::date IS NULL
+//	        OR ($5::text = 'published' AND v.upload_date >= $4)
+//	        OR ($5::text IS DISTINCT FROM 'published' AND v.created_at::date >= $4)
+//	    )
+//	    AND (
+//	        $6::date IS NULL
+//	        OR ($5::text = 'published' AND v.upload_date <= $6)
+//	        OR ($5::text IS DISTINCT FROM 'published' AND v.created_at::date <= $6)
+//	    )
+//	ORDER BY vt.video_id, vt.lang::text
+//	LIMIT $7
+func (q *Queries) ListTranscriptsForExport(ctx context.Context, arg *ListTranscriptsForExportParams) ([]*ListTranscriptsForExportRow, error) {
+	rows, err := q.db.Query(ctx, listTranscriptsForExport,
+		arg.AfterVideoID,
+		arg.AfterLang,
+		arg.ChannelID,
+		arg.DateFrom,
+		arg.DateType,
+		arg.DateTo,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListTranscriptsForExportRow
+	for rows.Next() {
+		var i ListTranscriptsForExportRow
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Lang,
+			&i.Format,
+			&i.Text,
+			&i.Raw,
+			&i.Title,
+			&i.Uploader,
+			&i.ChannelID,
+			&i.UploadDate,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideoTranscriptTexts = `-- name: ListVideoTranscriptTexts :many
+SELECT text
+FROM video_transcripts
+WHERE video_id = $1
+ORDER BY lang
+`
+
+// ListVideoTranscriptTexts returns the plain text of each of a video's
+// transcripts.
+//
+//	SELECT text
+//	FROM video_transcripts
+//	WHERE video_id = $1
+//	ORDER BY lang
+func (q *Queries) ListVideoTranscriptTexts(ctx context.Context, videoID pgtype.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, listVideoTranscriptTexts, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, err
+		}
+		items = append(items, text)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listVideoTranscriptWords = `-- name: ListVideoTranscriptWords :many
+SELECT video_id, lang, cue_index, word_index, start_offset, end_offset, word
+FROM video_transcript_words
+WHERE video_id = $1
+    AND lang = $2::language_tag
+ORDER BY cue_index, word_index
+`
+
+type ListVideoTranscriptWordsParams struct {
+	VideoID pgtype.UUID  `db:"video_id" json:"VideoID"`
+	Lang    language.Tag `db:"lang" json:"Lang"`
+}
+
+// ListVideoTranscriptWords returns the word timings of a video's transcript
+// in a language, in cue and word order.
+//
+//	SELECT video_id, lang, cue_index, word_index, start_offset, end_offset, word
+//	FROM video_transcript_words
+//	WHERE video_id = $1
+//	    AND lang = $2::language_tag
+//	ORDER BY cue_index, word_index
+func (q *Queries) ListVideoTranscriptWords(ctx context.Context, arg *ListVideoTranscriptWordsParams) ([]*VideoTranscriptWord, error) {
+	rows, err := q.db.Query(ctx, listVideoTranscriptWords, arg.VideoID, arg.Lang)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*VideoTranscriptWord
+	for rows.Next() {
+		var i VideoTranscriptWord
+		if err := rows.Scan(
+			&i.VideoID,
+			&i.Lang,
+			&i.CueIndex,
+			&i.WordIndex,
+			&i.StartOffset,
+			&i.EndOffset,
+			&i.Word,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateVideoTranscriptRaw = `-- name: UpdateVideoTranscriptRaw :execrows
+UPDATE video_transcripts
+SET raw = $1,
+    updated_at = NOW()
+WHERE video_id = $2
+    AND lang = $3::language_tag
+`
+
+type UpdateVideoTranscriptRawParams struct {
+	Raw     string       `db:"raw" json:"Raw"`
+	VideoID pgtype.UUID  `db:"video_id" json:"VideoID"`
+	Lang    language.Tag `db:"lang" json:"Lang"`
+}
+
+// UpdateVideoTranscriptRaw replaces the stored VTT for a video+lang after its
+// cue times are corrected. The plain text (and search index) carries no
+// timestamps, so it is left as is.
+//
+//	UPDATE video_transcripts
+//	SET raw = $1,
+//	    updated_at = NOW()
+//	WHERE video_id = $2
+//	    AND lang = $3::language_tag
+func (q *Queries) UpdateVideoTranscriptRaw(ctx context.Context, arg *UpdateVideoTranscriptRawParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateVideoTranscriptRaw, arg.Raw, arg.VideoID, arg.Lang)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const upsertVideoTranscript = `-- name: UpsertVideoTranscript :exec
 INSERT INTO video_transcripts (
     video_id,
@@ -12,14 +12,26 @@ import (
 )
 
 const countYtdlpLogsForJob = `-- name: CountYtdlpLogsForJob :one
-SELECT COUNT(*) FROM ytdlp_logs WHERE job_id = $1
+SELECT COUNT(*) FROM ytdlp_logs
+WHERE job_id = $1
+  AND ($2::text IS NULL OR stream::text = $2::text)
+  AND ($3::text IS NULL OR strpos(lower(message), lower($3::text)) > 0)
 `
 
+type CountYtdlpLogsForJobParams struct {
+	JobID  pgtype.UUID `db:"job_id" json:"JobID"`
+	Stream *string     `db:"stream" json:"Stream"`
+	Search *string     `db:"search" json:"Search"`
+}
+
 // CountYtdlpLogsForJob
 //
-//	SELECT COUNT(*) FROM ytdlp_logs WHERE job_id = $1
-func (q *Queries) CountYtdlpLogsForJob(ctx context.Context, jobID pgtype.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, countYtdlpLogsForJob, jobID)
+//	SELECT COUNT(*) FROM ytdlp_logs
+//	WHERE job_id = $1
+//	  AND ($2::text IS NULL OR stream::text = $2::text)
+//	  AND ($3::text IS NULL OR strpos(lower(message), lower($3::text)) > 0)
+func (q *Queries) CountYtdlpLogsForJob(ctx context.Context, arg *CountYtdlpLogsForJobParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countYtdlpLogsForJob, arg.JobID, arg.Stream, arg.Search)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -68,25 +80,38 @@ const getYtdlpLogsForJobPaginated = `-- name: GetYtdlpLogsForJobPaginated :many
 SELECT id, job_id, stream, message, created_at
 FROM ytdlp_logs
 WHERE job_id = $1
+  AND ($2::text IS NULL OR stream::text = $2::text)
+  AND ($3::text IS NULL OR strpos(lower(message), lower($3::text)) > 0)
 ORDER BY created_at DESC, id DESC
-LIMIT $3 OFFSET $2
+LIMIT $5 OFFSET $4
 `
 
 type GetYtdlpLogsForJobPaginatedParams struct {
 	JobID      pgtype.UUID `db:"job_id" json:"JobID"`
+	Stream     *string     `db:"stream" json:"Stream"`
+	Search     *string     `db:"search" json:"Search"`
 	PageOffset int32       `db:"page_offset" json:"PageOffset"`
 	PageLimit  int32       `db:"page_limit" json:"PageLimit"`
 }
 
-// GetYtdlpLogsForJobPaginated
+// Newest-first page of a job's log lines, optionally limited to one stream
+// and/or lines containing a case-insensitive substring.
 //
 //	SELECT id, job_id, stream, message, created_at
 //	FROM ytdlp_logs
 //	WHERE job_id = $1
+//	  AND ($2::text IS NULL OR stream::text = $2::text)
+//	  AND ($3::text IS NULL OR strpos(lower(message), lower($3::text)) > 0)
 //	ORDER BY created_at DESC, id DESC
-//	LIMIT $3 OFFSET $2
+//	LIMIT $5 OFFSET $4
 func (q *Queries) GetYtdlpLogsForJobPaginated(ctx context.Context, arg *GetYtdlpLogsForJobPaginatedParams) ([]*YtdlpLog, error) {
-	rows, err := q.db.Query(ctx, getYtdlpLogsForJobPaginated, arg.JobID, arg.PageOffset, arg.PageLimit)
+	rows, err := q.db.Query(ctx, getYtdlpLogsForJobPaginated,
+		arg.JobID,
+		arg.Stream,
+		arg.Search,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,199 @@
+// Package diskmonitor periodically checks free space on the storage volumes
+// Rewind writes to and reports which ones have dropped below a configured
+// threshold, so the web UI can warn admins and new downloads can be paused
+// before a full disk corrupts in-flight work.
+package diskmonitor
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/utils/diskusage"
+)
+
+const bytesPerGB = 1 << 30
+
+// Config controls which volumes are checked and what counts as "low".
+type Config struct {
+	Paths []string
+	// MinFreePercent flags a volume when free space falls below this
+	// percentage of its capacity. 0 disables the percentage check.
+	MinFreePercent float64
+	// MinFreeBytes flags a volume when free space falls below this many
+	// bytes. 0 disables the absolute check.
+	MinFreeBytes uint64
+	Interval     time.Duration
+	// PauseDownloads makes DownloadsPaused report true while any volume is low.
+	PauseDownloads bool
+}
+
+// ConfigFromEnv reads the DISK_* environment variables, applying defaults.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Paths:          []string{"/downloads", "/exports", "/spool"},
+		MinFreePercent: envFloat("DISK_LOW_FREE_PERCENT", 5),
+		MinFreeBytes:   uint64(envFloat("DISK_LOW_FREE_GB", 0) * bytesPerGB),
+		Interval:       time.Duration(envFloat("DISK_CHECK_INTERVAL_SECONDS", 60)) * time.Second,
+		PauseDownloads: envBool("DISK_LOW_PAUSE_DOWNLOADS"),
+	}
+	if raw := strings.TrimSpace(os.Getenv("DISK_MONITOR_PATHS")); raw != "" {
+		cfg.Paths = nil
+		for _, p := range strings.Split(raw, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				cfg.Paths = append(cfg.Paths, p)
+			}
+		}
+	}
+	if cfg.Interval < time.Second {
+		cfg.Interval = 60 * time.Second
+	}
+	return cfg
+}
+
+func envFloat(name string, def float64) float64 {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return def
+	}
+	return f
+}
+
+func envBool(name string) bool {
+	v := strings.TrimSpace(os.Getenv(name))
+	return v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "yes")
+}
+
+// VolumeStatus is the most recent check result for one configured path.
+type VolumeStatus struct {
+	Path        string  `json:"path"`
+	TotalBytes  uint64  `json:"total_bytes"`
+	FreeBytes   uint64  `json:"free_bytes"`
+	FreePercent float64 `json:"free_percent"`
+	Low         bool    `json:"low"`
+	// Error is set when the path could not be checked (e.g. not mounted).
+	Error string `json:"error,omitempty"`
+}
+
+// Status is a snapshot of every monitored volume.
+type Status struct {
+	CheckedAt       time.Time      `json:"checked_at"`
+	Volumes         []VolumeStatus `json:"volumes"`
+	DownloadsPaused bool           `json:"downloads_paused"`
+}
+
+// Low returns the volumes currently below threshold.
+func (s Status) Low() []VolumeStatus {
+	var low []VolumeStatus
+	for _, v := range s.Volumes {
+		if v.Low {
+			low = append(low, v)
+		}
+	}
+	return low
+}
+
+// Monitor runs the periodic checks. The zero value is not usable; use New.
+type Monitor struct {
+	cfg   Config
+	usage func(string) (diskusage.Usage, error)
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// New returns a Monitor for cfg. Call Run to start checking.
+func New(cfg Config) *Monitor {
+	return &Monitor{cfg: cfg, usage: diskusage.Get}
+}
+
+// Run checks immediately and then every cfg.Interval until ctx is done.
+func (m *Monitor) Run(ctx context.Context) {
+	m.Check()
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check()
+		}
+	}
+}
+
+// Check refreshes the status of every volume, logging a warning when a
+// volume first drops below threshold and an info line when it recovers.
+func (m *Monitor) Check() Status {
+	m.mu.RLock()
+	prevLow := make(map[string]bool, len(m.status.Volumes))
+	for _, v := range m.status.Volumes {
+		prevLow[v.Path] = v.Low
+	}
+	m.mu.RUnlock()
+
+	next := Status{CheckedAt: time.Now(), Volumes: make([]VolumeStatus, 0, len(m.cfg.Paths))}
+	anyLow := false
+	for _, path := range m.cfg.Paths {
+		vs := VolumeStatus{Path: path}
+		u, err := m.usage(path)
+		if err != nil {
+			vs.Error = err.Error()
+			next.Volumes = append(next.Volumes, vs)
+			continue
+		}
+		vs.TotalBytes = u.TotalBytes
+		vs.FreeBytes = u.FreeBytes
+		vs.FreePercent = u.FreePercent()
+		vs.Low = m.isLow(u)
+		if vs.Low {
+			anyLow = true
+			if !prevLow[path] {
+				slog.Warn("disk space low", "path", path, "free_bytes", u.FreeBytes, "total_bytes", u.TotalBytes, "free_percent", vs.FreePercent, "downloads_paused", m.cfg.PauseDownloads)
+			}
+		} else if prevLow[path] {
+			slog.Info("disk space recovered", "path", path, "free_bytes", u.FreeBytes, "free_percent", vs.FreePercent)
+		}
+		next.Volumes = append(next.Volumes, vs)
+	}
+	next.DownloadsPaused = m.cfg.PauseDownloads && anyLow
+
+	m.mu.Lock()
+	m.status = next
+	m.mu.Unlock()
+	return next
+}
+
+func (m *Monitor) isLow(u diskusage.Usage) bool {
+	if u.TotalBytes == 0 {
+		return false
+	}
+	if m.cfg.MinFreePercent > 0 && u.FreePercent() < m.cfg.MinFreePercent {
+		return true
+	}
+	return m.cfg.MinFreeBytes > 0 && u.FreeBytes < m.cfg.MinFreeBytes
+}
+
+// Status returns the result of the most recent check.
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := m.status
+	out.Volumes = append([]VolumeStatus(nil), m.status.Volumes...)
+	return out
+}
+
+// DownloadsPaused reports whether new downloads should be refused.
+func (m *Monitor) DownloadsPaused() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status.DownloadsPaused
+}
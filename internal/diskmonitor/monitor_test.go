@@ -0,0 +1,95 @@
+package diskmonitor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/utils/diskusage"
+)
+
+func fakeUsage(free map[string]uint64) func(string) (diskusage.Usage, error) {
+	return func(path string) (diskusage.Usage, error) {
+		f, ok := free[path]
+		if !ok {
+			return diskusage.Usage{}, errors.New("not mounted")
+		}
+		return diskusage.Usage{Path: path, TotalBytes: 100 * bytesPerGB, FreeBytes: f}, nil
+	}
+}
+
+func TestCheck_Thresholds(t *testing.T) {
+	free := map[string]uint64{
+		"/downloads": 3 * bytesPerGB,  // 3% free
+		"/exports":   50 * bytesPerGB, // 50% free
+	}
+	m := New(Config{
+		Paths:          []string{"/downloads", "/exports", "/spool"},
+		MinFreePercent: 5,
+		Interval:       time.Minute,
+		PauseDownloads: true,
+	})
+	m.usage = fakeUsage(free)
+
+	st := m.Check()
+	if len(st.Volumes) != 3 {
+		t.Fatalf("got %d volumes, want 3", len(st.Volumes))
+	}
+	if !st.Volumes[0].Low || st.Volumes[1].Low {
+		t.Fatalf("unexpected low flags: %+v", st.Volumes)
+	}
+	if st.Volumes[2].Error == "" || st.Volumes[2].Low {
+		t.Fatalf("missing path should report an error and not be low: %+v", st.Volumes[2])
+	}
+	if !m.DownloadsPaused() {
+		t.Fatalf("expected downloads paused while /downloads is low")
+	}
+	if got := st.Low(); len(got) != 1 || got[0].Path != "/downloads" {
+		t.Fatalf("Low() = %+v", got)
+	}
+
+	free["/downloads"] = 40 * bytesPerGB
+	m.Check()
+	if m.DownloadsPaused() {
+		t.Fatalf("expected downloads resumed after space recovered")
+	}
+}
+
+func TestCheck_AbsoluteThreshold(t *testing.T) {
+	m := New(Config{
+		Paths:        []string{"/downloads"},
+		MinFreeBytes: 20 * bytesPerGB,
+		Interval:     time.Minute,
+	})
+	m.usage = fakeUsage(map[string]uint64{"/downloads": 10 * bytesPerGB})
+
+	st := m.Check()
+	if !st.Volumes[0].Low {
+		t.Fatalf("expected low with 10GB free under a 20GB threshold")
+	}
+	if st.DownloadsPaused {
+		t.Fatalf("downloads must not pause unless PauseDownloads is set")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("DISK_MONITOR_PATHS", " /a, ,/b ")
+	t.Setenv("DISK_LOW_FREE_PERCENT", "10")
+	t.Setenv("DISK_LOW_FREE_GB", "2")
+	t.Setenv("DISK_CHECK_INTERVAL_SECONDS", "0")
+	t.Setenv("DISK_LOW_PAUSE_DOWNLOADS", "true")
+
+	cfg := ConfigFromEnv()
+	if len(cfg.Paths) != 2 || cfg.Paths[0] != "/a" || cfg.Paths[1] != "/b" {
+		t.Fatalf("Paths = %v", cfg.Paths)
+	}
+	if cfg.MinFreePercent != 10 || cfg.MinFreeBytes != 2*bytesPerGB {
+		t.Fatalf("thresholds = %v%%, %d bytes", cfg.MinFreePercent, cfg.MinFreeBytes)
+	}
+	if cfg.Interval != 60*time.Second {
+		t.Fatalf("Interval = %v, want fallback 60s", cfg.Interval)
+	}
+	if !cfg.PauseDownloads {
+		t.Fatalf("PauseDownloads should be true")
+	}
+}
@@ -183,6 +183,30 @@ func SegmentToMarker(videoID pgtype.UUID, seg SkipSegment) *db.Marker {
 		MarkerType:  markerType,
 		CreatedAt:   pgtype.Timestamptz{}, // Virtual marker
 		CreatedBy:   pgtype.UUID{},        // Virtual marker
+		Source:      db.MarkerSourceSponsorblock,
+	}
+}
+
+// ImportCategories are the segment categories ingest imports as markers by
+// default.
+var ImportCategories = []string{"sponsor", "intro", "outro", "selfpromo"}
+
+// SegmentMarkerParams converts a sponsorblock segment to the parameters for
+// storing it as a marker owned by createdBy, tagged with the SponsorBlock
+// source so it can be told apart from (and replaced without touching) markers
+// users placed.
+func SegmentMarkerParams(videoID, createdBy pgtype.UUID, seg SkipSegment) *db.CreateMarkerParams {
+	m := SegmentToMarker(videoID, seg)
+	return &db.CreateMarkerParams{
+		VideoID:     videoID,
+		Timestamp:   m.Timestamp,
+		Title:       m.Title,
+		Description: m.Description,
+		Color:       m.Color,
+		MarkerType:  m.MarkerType,
+		Duration:    m.Duration,
+		CreatedBy:   createdBy,
+		Source:      db.MarkerSourceSponsorblock,
 	}
 }
 
@@ -3,13 +3,17 @@ package videoid
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
+	"thirdcoast.systems/rewind/pkg/utils/netaddr"
 )
 
 // Well-known host aliases. Key: input host. Value: canonical domain.
@@ -207,6 +211,49 @@ func NormalizeSourceURL(raw string) (string, string, error) {
 	return u.String(), canon, nil
 }
 
+// SourceVideoID best-effort derives the extractor video ID (the value yt-dlp
+// reports as info.id) from a normalized source URL, without any network calls.
+//
+// It only handles known sources whose IDs are stable path/query components.
+// Returns "" when the ID cannot be derived before download.
+func SourceVideoID(normalizedURL string, canonicalDomain string) string {
+	u, err := url.Parse(strings.TrimSpace(normalizedURL))
+	if err != nil {
+		return ""
+	}
+	segs := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch canonicalDomain {
+	case "youtube.com":
+		id, _ := ExtractYouTubeVideoID(u.String())
+		return strings.TrimSpace(id)
+	case "x.com":
+		// /{user}/status/{id}
+		if len(segs) >= 3 && segs[1] == "status" {
+			return segs[2]
+		}
+	case "twitch.tv":
+		// /videos/{id}; yt-dlp prefixes VOD IDs with "v".
+		if len(segs) >= 2 && segs[0] == "videos" && segs[1] != "" {
+			return "v" + segs[1]
+		}
+	case "kick.com":
+		// /video/{uuid}
+		if len(segs) >= 2 && segs[0] == "video" {
+			return segs[1]
+		}
+	case "instagram.com":
+		// /p/{shortcode}, /reel/{shortcode}, /reels/{shortcode}
+		if len(segs) >= 2 {
+			switch segs[0] {
+			case "p", "reel", "reels", "tv":
+				return segs[1]
+			}
+		}
+	}
+	return ""
+}
+
 // isDeadEndURL reports whether a redirect landed on a known generic/error page
 // (e.g. Instagram bouncing non-browser clients to facebook.com/unsupportedbrowser).
 // Such URLs must never be treated as a video's canonical source — they are the
@@ -246,24 +293,75 @@ func trimTrailingSlash(p string) string {
 	return strings.TrimRight(p, "/")
 }
 
-func followRedirects(ctx context.Context, u *url.URL) (*url.URL, bool) {
-	client := &http.Client{
-		Timeout: 6 * time.Second,
+const (
+	// expandMaxRedirects caps the redirect chain followed during URL expansion.
+	expandMaxRedirects = 5
+	// expandTimeout bounds the whole expansion request, including redirects.
+	expandTimeout = 6 * time.Second
+)
+
+// errBlockedAddress is returned when expansion would reach a local/private address.
+var errBlockedAddress = errors.New("videoid: refusing to expand to local or private address")
+
+// expandDialAllowed reports whether expansion may connect to address (ip:port).
+// It runs after DNS resolution so hostnames pointing at internal IPs are caught.
+// Tests override this to reach httptest servers on loopback.
+var expandDialAllowed = func(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	return !netaddr.IsLocalOrPrivateIP(ip)
+}
+
+// newExpandClient returns the HTTP client used for URL expansion. It refuses
+// connections to local/private addresses (SSRF guard), limits redirects to
+// http(s) targets, and enforces per-phase timeouts.
+func newExpandClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 3 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if !expandDialAllowed(address) {
+				return errBlockedAddress
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   3 * time.Second,
+		ResponseHeaderTimeout: 4 * time.Second,
+		MaxIdleConns:          1,
+		DisableKeepAlives:     true,
+	}
+	return &http.Client{
+		Timeout:   expandTimeout,
+		Transport: transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			if len(via) >= 8 {
+			if len(via) >= expandMaxRedirects {
 				return http.ErrUseLastResponse
 			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("videoid: refusing redirect to %q scheme", req.URL.Scheme)
+			}
 			return nil
 		},
 	}
+}
 
+func followRedirects(ctx context.Context, u *url.URL) (*url.URL, bool) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, false
 	}
 	req.Header.Set("User-Agent", os.Getenv("USER_AGENT"))
 
-	resp, err := client.Do(req)
+	resp, err := newExpandClient().Do(req)
 	if err != nil {
 		return nil, false
 	}
@@ -2,6 +2,8 @@ package videoid
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -98,3 +100,139 @@ func TestIsDeadEndURL_UnsupportedBrowser(t *testing.T) {
 	u, _ := url.Parse("https://www.facebook.com/unsupportedbrowser")
 	require.True(t, isDeadEndURL(u))
 }
+
+func TestSourceVideoID_KnownSources(t *testing.T) {
+	require.Equal(t, "ggLajT7aMMk", SourceVideoID("https://youtube.com/watch?v=ggLajT7aMMk", "youtube.com"))
+	require.Equal(t, "2009472976463495257", SourceVideoID("https://x.com/Breaking911/status/2009472976463495257", "x.com"))
+	require.Equal(t, "v123456789", SourceVideoID("https://twitch.tv/videos/123456789", "twitch.tv"))
+	require.Equal(t, "DYc_k5PpRGA", SourceVideoID("https://instagram.com/p/DYc_k5PpRGA", "instagram.com"))
+}
+
+func TestSourceVideoID_UnknownReturnsEmpty(t *testing.T) {
+	require.Equal(t, "", SourceVideoID("https://twitch.tv/somechannel", "twitch.tv"))
+	require.Equal(t, "", SourceVideoID("https://example.com/video/123", "example.com"))
+}
+
+// allowOnlyTestServer lets expansion reach srv (on loopback) while keeping the
+// default SSRF guard for every other address.
+func allowOnlyTestServer(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	orig := expandDialAllowed
+	allowed := srv.Listener.Addr().String()
+	expandDialAllowed = func(address string) bool {
+		return address == allowed || orig(address)
+	}
+	t.Cleanup(func() { expandDialAllowed = orig })
+}
+
+func TestExpandAndCanonicalizeURL_RefusesRedirectToPrivateAddress(t *testing.T) {
+	targets := []string{
+		"http://127.0.0.1:1/admin",
+		"http://localhost:1/admin",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/internal",
+		"http://[::1]:1/admin",
+	}
+	for _, target := range targets {
+		t.Run(target, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, target, http.StatusFound)
+			}))
+			defer srv.Close()
+			allowOnlyTestServer(t, srv)
+
+			u, _ := url.Parse(srv.URL + "/short")
+			_, ok := followRedirects(context.Background(), u)
+			require.False(t, ok)
+
+			res, err := ExpandAndCanonicalizeURL(context.Background(), srv.URL+"/short")
+			require.NoError(t, err)
+			require.Equal(t, srv.URL+"/short", res.ExpandedURL)
+		})
+	}
+}
+
+// TestExpandAndCanonicalizeURL_DialGuardBlocksLiveTarget redirects to a
+// second server that is listening, so only the dial guard stands between
+// expansion and the request landing there.
+func TestExpandAndCanonicalizeURL_DialGuardBlocksLiveTarget(t *testing.T) {
+	var internalHits int
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		internalHits++
+	}))
+	defer internal.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL+"/admin", http.StatusFound)
+	}))
+	defer srv.Close()
+	allowOnlyTestServer(t, srv)
+
+	guarded := expandDialAllowed
+	var refused []string
+	expandDialAllowed = func(address string) bool {
+		ok := guarded(address)
+		if !ok {
+			refused = append(refused, address)
+		}
+		return ok
+	}
+
+	u, _ := url.Parse(srv.URL + "/short")
+	_, ok := followRedirects(context.Background(), u)
+	require.False(t, ok)
+	require.Zero(t, internalHits)
+	require.Equal(t, []string{internal.Listener.Addr().String()}, refused)
+}
+
+func TestExpandDialAllowed(t *testing.T) {
+	for _, address := range []string{
+		"127.0.0.1:80", "0.0.0.0:80", "[::]:80", "[::1]:80", "10.0.0.5:443",
+		"100.64.1.1:80", "169.254.169.254:80", "[fec0::1]:80", "224.0.0.1:80", "not-an-ip:80",
+	} {
+		require.False(t, expandDialAllowed(address), address)
+	}
+	require.True(t, expandDialAllowed("93.184.216.34:443"))
+}
+
+func TestExpandAndCanonicalizeURL_RefusesDirectPrivateAddress(t *testing.T) {
+	res, err := ExpandAndCanonicalizeURL(context.Background(), "http://169.254.169.254/latest/meta-data/")
+	require.NoError(t, err)
+	require.Equal(t, "http://169.254.169.254/latest/meta-data/", res.ExpandedURL)
+
+	u, _ := url.Parse("http://127.0.0.1:1/")
+	_, ok := followRedirects(context.Background(), u)
+	require.False(t, ok)
+}
+
+func TestExpandAndCanonicalizeURL_LimitsRedirects(t *testing.T) {
+	var hits int
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		http.Redirect(w, r, srv.URL+"/loop", http.StatusFound)
+	}))
+	defer srv.Close()
+	allowOnlyTestServer(t, srv)
+
+	u, _ := url.Parse(srv.URL + "/start")
+	_, _ = followRedirects(context.Background(), u)
+	require.LessOrEqual(t, hits, expandMaxRedirects+1)
+}
+
+func TestExpandAndCanonicalizeURL_FollowsPublicRedirect(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/short" {
+			http.Redirect(w, r, srv.URL+"/final", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	allowOnlyTestServer(t, srv)
+
+	u, _ := url.Parse(srv.URL + "/short")
+	final, ok := followRedirects(context.Background(), u)
+	require.True(t, ok)
+	require.Equal(t, "/final", final.Path)
+}
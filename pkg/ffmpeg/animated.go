@@ -0,0 +1,83 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// Animated image exports (GIF and WebP) are sized for sharing: a low frame
+// rate, a capped width and a length limit keep files from growing into the
+// gigabytes a full-rate GIF of a long clip would take.
+const (
+	DefaultImageFPS      = 15
+	MaxImageFPS          = 30
+	DefaultImageMaxWidth = 480
+	MinImageMaxWidth     = 64
+	MaxImageMaxWidth     = 1280
+	// MaxImageExportSeconds caps the clip length of a GIF or WebP export.
+	MaxImageExportSeconds = 30
+)
+
+// IsAnimatedImageFormat reports whether format is exported as an animated
+// image rather than a video: no audio, no keyframe control, and looping
+// built into the file.
+func IsAnimatedImageFormat(format string) bool {
+	return format == "gif" || format == "webp"
+}
+
+// ImageFPS returns the spec's animated image frame rate, DefaultImageFPS
+// when unset, clamped to MaxImageFPS.
+func (s ExportSpec) ImageFPS() int {
+	if s.FPS < 1 {
+		return DefaultImageFPS
+	}
+	return min(s.FPS, MaxImageFPS)
+}
+
+// ImageMaxWidth returns the spec's animated image width limit,
+// DefaultImageMaxWidth when unset, clamped to [MinImageMaxWidth,
+// MaxImageMaxWidth].
+func (s ExportSpec) ImageMaxWidth() int {
+	if s.MaxWidth < 1 {
+		return DefaultImageMaxWidth
+	}
+	return max(MinImageMaxWidth, min(s.MaxWidth, MaxImageMaxWidth))
+}
+
+// AnimatedImageFilter returns the filter chain that resamples video for an
+// animated image: fps frames per second, scaled down (never up) to at most
+// maxWidth pixels wide. For GIF it adds the two-stage palette pass: one
+// branch builds a 256-colour palette from the whole clip, the other is mapped
+// onto it. It runs after every other video filter.
+func AnimatedImageFilter(format string, fps, maxWidth int) string {
+	f := "fps=" + strconv.Itoa(fps) + ",scale=min(" + strconv.Itoa(maxWidth) + "\\,iw):-1:flags=lanczos"
+	if format == "gif" {
+		f += ",split[pal_in][pal_src];[pal_in]palettegen=stats_mode=diff[pal];[pal_src][pal]paletteuse=dither=bayer:bayer_scale=5:diff_mode=rectangle"
+	}
+	return f
+}
+
+// CheckAnimatedImage reports an error unless path starts with a GIF or WebP
+// signature. ffprobe reports no reliable duration for animated images, so
+// exports check the container instead.
+func CheckAnimatedImage(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	head := make([]byte, 12)
+	if _, err := io.ReadFull(f, head); err != nil {
+		return fmt.Errorf("animated image too short: %w", err)
+	}
+	switch {
+	case bytes.HasPrefix(head, []byte("GIF87a")), bytes.HasPrefix(head, []byte("GIF89a")):
+		return nil
+	case bytes.HasPrefix(head, []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WEBP")):
+		return nil
+	}
+	return fmt.Errorf("not a gif or webp file")
+}
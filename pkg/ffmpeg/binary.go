@@ -0,0 +1,236 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Binary paths used for every ffmpeg and ffprobe run. They default to a PATH
+// lookup; services override them at startup with SetBinaryPaths or
+// ConfigureFromEnv, before any work starts.
+var (
+	ffmpegPath  = "ffmpeg"
+	ffprobePath = "ffprobe"
+)
+
+// MinVersion is the oldest ffmpeg release the filter compiler supports;
+// colortemperature first shipped in 5.1.
+var MinVersion = Version{Major: 5, Minor: 1}
+
+// SetBinaryPaths sets the ffmpeg and ffprobe executables. An empty argument
+// leaves that path unchanged.
+func SetBinaryPaths(ffmpeg, ffprobe string) {
+	if ffmpeg = strings.TrimSpace(ffmpeg); ffmpeg != "" {
+		ffmpegPath = ffmpeg
+	}
+	if ffprobe = strings.TrimSpace(ffprobe); ffprobe != "" {
+		ffprobePath = ffprobe
+	}
+}
+
+// FFmpegPath returns the configured ffmpeg executable.
+func FFmpegPath() string { return ffmpegPath }
+
+// FFprobePath returns the configured ffprobe executable.
+func FFprobePath() string { return ffprobePath }
+
+// ConfigureFromEnv applies FFMPEG_PATH and FFPROBE_PATH, and raises MinVersion
+// to FFMPEG_MIN_VERSION (e.g. "6.1") when that is set.
+func ConfigureFromEnv() error {
+	SetBinaryPaths(os.Getenv("FFMPEG_PATH"), os.Getenv("FFPROBE_PATH"))
+	if raw := strings.TrimSpace(os.Getenv("FFMPEG_MIN_VERSION")); raw != "" {
+		v, err := parseVersionNumber(raw)
+		if err != nil {
+			return fmt.Errorf("FFMPEG_MIN_VERSION: %w", err)
+		}
+		if !MinVersion.AtLeast(v) {
+			MinVersion = v
+		}
+	}
+	return nil
+}
+
+// Version is a release version parsed from `ffmpeg -version`. Builds from git
+// (e.g. "N-113000-g1234abcd") carry no release number and are reported with
+// Dev set; they are assumed to be newer than any release.
+type Version struct {
+	Major, Minor, Patch int
+	Dev                 bool
+	// Raw is the version token as printed, e.g. "6.1.1-3ubuntu5".
+	Raw string
+}
+
+// String returns the version as printed by ffmpeg, or major.minor.patch.
+func (v Version) String() string {
+	if v.Raw != "" {
+		return v.Raw
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is min or newer. Dev builds always are.
+func (v Version) AtLeast(min Version) bool {
+	if v.Dev {
+		return true
+	}
+	if v.Major != min.Major {
+		return v.Major > min.Major
+	}
+	if v.Minor != min.Minor {
+		return v.Minor > min.Minor
+	}
+	return v.Patch >= min.Patch
+}
+
+var (
+	versionLineRe   = regexp.MustCompile(`^ff(?:mpeg|probe) version (\S+)`)
+	versionNumberRe = regexp.MustCompile(`^n?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+)
+
+// ParseVersion extracts the version from the first line of `ffmpeg -version`
+// or `ffprobe -version` output, e.g. "ffmpeg version 6.1.1-3ubuntu5 Copyright
+// ...". Release tags ("n7.0.2"), distro suffixes and git builds are handled.
+func ParseVersion(output string) (Version, error) {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	m := versionLineRe.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Version{}, fmt.Errorf("unrecognized version output %q", line)
+	}
+	raw := m[1]
+	// Git snapshots print "N-<commits>-g<hash>" and date-stamped builds
+	// "2024-03-14-git-..."; neither maps to a release.
+	if strings.HasPrefix(raw, "N-") || strings.Contains(raw, "-git-") {
+		return Version{Dev: true, Raw: raw}, nil
+	}
+	v, err := parseVersionNumber(raw)
+	if err != nil {
+		return Version{}, err
+	}
+	v.Raw = raw
+	return v, nil
+}
+
+// parseVersionNumber parses a leading "[n]major[.minor[.patch]]".
+func parseVersionNumber(s string) (Version, error) {
+	m := versionNumberRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("unrecognized version %q", s)
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		v.Minor, _ = strconv.Atoi(m[2])
+	}
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+// BinaryInfo describes the ffmpeg and ffprobe executables found by
+// CheckBinaries.
+type BinaryInfo struct {
+	FFmpegPath     string
+	FFprobePath    string
+	FFmpegVersion  Version
+	FFprobeVersion Version
+}
+
+// CheckBinaries resolves the configured ffmpeg and ffprobe, reads their
+// versions and checks that ffmpeg has each of filters compiled in. It fails
+// when either binary is missing, ffmpeg is older than MinVersion, or a filter
+// is unavailable. Services call it at startup so a bad install fails fast
+// instead of on the first job.
+func CheckBinaries(ctx context.Context, filters ...string) (*BinaryInfo, error) {
+	info := &BinaryInfo{}
+	var err error
+	if info.FFmpegPath, info.FFmpegVersion, err = binaryVersion(ctx, ffmpegPath); err != nil {
+		return nil, err
+	}
+	if info.FFprobePath, info.FFprobeVersion, err = binaryVersion(ctx, ffprobePath); err != nil {
+		return nil, err
+	}
+	if !info.FFmpegVersion.AtLeast(MinVersion) {
+		return info, fmt.Errorf("ffmpeg %s at %s is older than the required %d.%d", info.FFmpegVersion, info.FFmpegPath, MinVersion.Major, MinVersion.Minor)
+	}
+	if len(filters) == 0 {
+		return info, nil
+	}
+
+	available, err := listFilters(ctx, info.FFmpegPath)
+	if err != nil {
+		return info, err
+	}
+	var missing []string
+	for _, f := range filters {
+		if !available[f] {
+			missing = append(missing, f)
+		}
+	}
+	if len(missing) > 0 {
+		return info, fmt.Errorf("ffmpeg %s at %s is missing filters: %s", info.FFmpegVersion, info.FFmpegPath, strings.Join(missing, ", "))
+	}
+	return info, nil
+}
+
+// HasFilter reports whether the configured ffmpeg has filter compiled in,
+// for optional features that are skipped without it rather than failing
+// the service like CheckBinaries.
+func HasFilter(ctx context.Context, filter string) (bool, error) {
+	available, err := listFilters(ctx, ffmpegPath)
+	if err != nil {
+		return false, err
+	}
+	return available[filter], nil
+}
+
+// listFilters runs ffmpeg -filters and returns the filter names it lists.
+func listFilters(ctx context.Context, path string) (map[string]bool, error) {
+	out, err := exec.CommandContext(ctx, path, "-hide_banner", "-filters").Output()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg -filters: %w", err)
+	}
+	return parseFilterList(out), nil
+}
+
+// binaryVersion resolves name on PATH and runs it with -version.
+func binaryVersion(ctx context.Context, name string) (string, Version, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", Version{}, fmt.Errorf("%s not found: %w", name, err)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "-version")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return path, Version{}, fmt.Errorf("%s -version: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	v, err := ParseVersion(string(out))
+	if err != nil {
+		return path, Version{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return path, v, nil
+}
+
+// parseFilterList returns the filter names in `ffmpeg -filters` output, whose
+// entries look like " T.. colortemperature V->V  Adjust color temperature".
+func parseFilterList(out []byte) map[string]bool {
+	names := make(map[string]bool)
+	for line := range strings.SplitSeq(string(out), "\n") {
+		fields := strings.Fields(line)
+		// Entries have flags, name, and an "in->out" pad spec; the legend
+		// above the list does not.
+		if len(fields) < 3 || !strings.Contains(fields[2], "->") {
+			continue
+		}
+		names[fields[1]] = true
+	}
+	return names
+}
@@ -0,0 +1,74 @@
+package ffmpeg
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   Version
+	}{
+		{"ffmpeg version 6.1.1-3ubuntu5 Copyright (c) 2000-2023 the FFmpeg developers\nbuilt with gcc 13",
+			Version{Major: 6, Minor: 1, Patch: 1, Raw: "6.1.1-3ubuntu5"}},
+		{"ffmpeg version 5.1.6-0+deb12u1 Copyright (c) 2000-2024", Version{Major: 5, Minor: 1, Patch: 6, Raw: "5.1.6-0+deb12u1"}},
+		{"ffprobe version n7.0.2 Copyright", Version{Major: 7, Patch: 2, Raw: "n7.0.2"}},
+		{"ffmpeg version 7.1 Copyright", Version{Major: 7, Minor: 1, Raw: "7.1"}},
+		{"ffmpeg version N-113000-g1234abcd Copyright", Version{Dev: true, Raw: "N-113000-g1234abcd"}},
+		{"ffmpeg version 2024-03-14-git-1234abcd-full_build-www.gyan.dev", Version{Dev: true, Raw: "2024-03-14-git-1234abcd-full_build-www.gyan.dev"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.output)
+		if err != nil {
+			t.Errorf("ParseVersion(%q): %v", tt.output, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %+v, want %+v", tt.output, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "sh: ffmpeg: not found", "ffmpeg version unknown"} {
+		if _, err := ParseVersion(bad); err == nil {
+			t.Errorf("ParseVersion(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	min := Version{Major: 5, Minor: 1}
+	for _, tt := range []struct {
+		v    Version
+		want bool
+	}{
+		{Version{Major: 5, Minor: 1}, true},
+		{Version{Major: 5, Minor: 0, Patch: 9}, false},
+		{Version{Major: 4, Minor: 4, Patch: 2}, false},
+		{Version{Major: 6}, true},
+		{Version{Dev: true}, true},
+	} {
+		if got := tt.v.AtLeast(min); got != tt.want {
+			t.Errorf("%v.AtLeast(%v) = %v, want %v", tt.v, min, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterList(t *testing.T) {
+	out := []byte(`Filters:
+  T.. = Timeline support
+  .S. = Slice threading
+  ..C = Command support
+  A = Audio input/output
+  | = Source or sink filter
+ ... abench            A->A       Benchmark part of a filtergraph.
+ TSC colortemperature  V->V       Adjust color temperature of video.
+ T.C drawtext          V->V       Draw text on top of video frames using libfreetype library.
+`)
+	got := parseFilterList(out)
+	for _, name := range []string{"abench", "colortemperature", "drawtext"} {
+		if !got[name] {
+			t.Errorf("missing filter %q", name)
+		}
+	}
+	if len(got) != 3 {
+		t.Errorf("got %d filters, want 3: %v", len(got), got)
+	}
+}
@@ -89,42 +89,158 @@ func PresetExportOpus() []Option {
 	}
 }
 
-// PresetExportGIF returns options for high-quality GIF output.
-// Generates a palette per frame for optimal dithering.
-func PresetExportGIF() []Option {
+// PresetExportGIF returns options for a palette-optimized looping GIF at fps
+// frames per second and at most maxWidth pixels wide (see
+// AnimatedImageFilter).
+func PresetExportGIF(fps, maxWidth int) []Option {
 	return []Option{
-		// GIF palette generation is handled via filter_complex in the encoder.
-		// This just sets basic framerate limit.
 		OptionFunc(func(cmd *Command) {
-			cmd.postInput = append(cmd.postInput, "-r", "15")
+			cmd.animate = AnimatedImageFilter("gif", fps, maxWidth)
+			cmd.postInput = append(cmd.postInput, "-an", "-loop", "0")
 		}),
 	}
 }
 
+// PresetExportWebP returns options for a lossy looping animated WebP at fps
+// frames per second and at most maxWidth pixels wide. "max" quality raises
+// the encoder quality from 75 to 90.
+func PresetExportWebP(quality string, fps, maxWidth int) []Option {
+	q := "75"
+	if quality == "max" {
+		q = "90"
+	}
+	return []Option{
+		VideoCodec("libwebp"),
+		OptionFunc(func(cmd *Command) {
+			cmd.animate = AnimatedImageFilter("webp", fps, maxWidth)
+			cmd.postInput = append(cmd.postInput, "-lossless", "0", "-q:v", q, "-compression_level", "4", "-an", "-loop", "0")
+		}),
+	}
+}
+
+// IsAudioFormat reports whether format is an audio-only export format: MP3,
+// M4A (AAC) or FLAC.
+func IsAudioFormat(format string) bool {
+	return format == "mp3" || format == "m4a" || format == "flac"
+}
+
+// PresetExportAudioOnly returns the options for an audio-only export in
+// format, one of the IsAudioFormat formats. Lossy formats use a higher
+// bitrate for "max" quality; FLAC is lossless either way.
+func PresetExportAudioOnly(format, quality string) []Option {
+	maxQ := quality == "max"
+	switch format {
+	case "mp3":
+		bitrate := "192k"
+		if maxQ {
+			bitrate = "320k"
+		}
+		return []Option{AudioCodec("libmp3lame"), AudioBitrate(bitrate), AudioChannels(2)}
+	case "flac":
+		return []Option{AudioCodec("flac"), AudioChannels(2)}
+	default: // "m4a"
+		bitrate := "192k"
+		if maxQ {
+			bitrate = "256k"
+		}
+		return []Option{AudioCodec("aac"), AudioBitrate(bitrate), AudioChannels(2)}
+	}
+}
+
+// PresetExportTwoPass returns the video options for a two-pass export at a
+// target bitrate in kbps, in place of the format's constant-quality preset.
+// Pair it with FirstPass and SecondPass. "max" quality uses the slower x264
+// preset; VP9 ignores it.
+func PresetExportTwoPass(format, quality string, targetKbps int) []Option {
+	bitrate := itoa(targetKbps) + "k"
+	if format == "webm" {
+		return []Option{
+			VideoCodec("libvpx-vp9"),
+			ExtraArgs("-b:v", bitrate, "-row-mt", "1"),
+			PixelFormat("yuv420p"),
+		}
+	}
+	preset := "medium"
+	if quality == "max" {
+		preset = "slow"
+	}
+	return []Option{
+		VideoCodec("libx264"),
+		Preset(preset),
+		ExtraArgs("-b:v", bitrate),
+		PixelFormat("yuv420p"),
+	}
+}
+
 // ExportPresetForFormat returns (video codec options, audio options, file extension)
-// for the given format string. Returns (h264, aac, ".mp4") as default.
-func ExportPresetForFormat(format, quality string) (video []Option, audio []Option, ext string) {
+// for the given format string and the spec's Quality, GOP, TargetBitrate, FPS
+// and MaxWidth. Returns (h264, aac, ".mp4") as default.
+// A positive GOP forces a keyframe every GOP frames; it is ignored for GIF
+// and WebP, which take their frame rate and size from the spec instead.
+// hw moves the MP4 video encode to a GPU encoder (see PresetExportHardware);
+// WebM, GIF and WebP always encode in software. The audio formats (see
+// IsAudioFormat) return NoVideo as their video options.
+//
+// A positive TargetBitrate selects a two-pass software encode at that bitrate
+// in kbps for MP4 and WebM (see PresetExportTwoPass), overriding hw; twoPass
+// reports whether the caller must run the encode as FirstPass then SecondPass.
+func ExportPresetForFormat(format string, spec ExportSpec, hw HardwareEncoder) (video []Option, audio []Option, ext string, twoPass bool) {
+	quality, gop, targetKbps := spec.Quality, spec.GOP, spec.TargetBitrate
+	twoPass = targetKbps > 0 && !IsAnimatedImageFormat(format) && !IsAudioFormat(format)
 	// Determine CRF override for "max" quality
 	switch format {
 	case "webm":
-		video = PresetExportWebM()
 		audio = PresetExportOpus()
 		ext = ".webm"
-		if quality == "max" {
-			// Override CRF to 18 for max quality VP9
-			video = append(video, CRF(18))
+		if twoPass {
+			video = PresetExportTwoPass(format, quality, targetKbps)
+		} else {
+			video = PresetExportWebM()
+			if quality == "max" {
+				// Override CRF to 18 for max quality VP9
+				video = append(video, CRF(18))
+			}
+		}
+		if gop > 0 {
+			video = append(video, KeyframeInterval(gop))
 		}
 	case "gif":
-		video = PresetExportGIF()
+		video = PresetExportGIF(spec.ImageFPS(), spec.ImageMaxWidth())
 		audio = nil // No audio in GIF
 		ext = ".gif"
+	case "webp":
+		video = PresetExportWebP(quality, spec.ImageFPS(), spec.ImageMaxWidth())
+		ext = ".webp"
+	case "mp3", "m4a", "flac":
+		video = []Option{NoVideo}
+		audio = PresetExportAudioOnly(format, quality)
+		ext = "." + format
 	default: // "mp4"
-		video = PresetExportHQ()
 		audio = PresetExportAAC()
 		ext = ".mp4"
-		if quality == "max" {
-			// Override CRF to 17 for max quality h264
-			video = append(video, CRF(17), Preset("slow"))
+		if hw != HardwareNone && !twoPass {
+			video = PresetExportHardware(hw, quality)
+			if gop > 0 {
+				video = append(video, KeyframeInterval(gop))
+				if hw == HardwareNVENC {
+					// NVENC's counterpart of -sc_threshold 0.
+					video = append(video, ExtraArgs("-no-scenecut", "1"))
+				}
+			}
+			return
+		}
+		if twoPass {
+			video = PresetExportTwoPass(format, quality, targetKbps)
+		} else {
+			video = PresetExportHQ()
+			if quality == "max" {
+				// Override CRF to 17 for max quality h264
+				video = append(video, CRF(17), Preset("slow"))
+			}
+		}
+		if gop > 0 {
+			// x264 also places keyframes on scene cuts unless told not to.
+			video = append(video, KeyframeInterval(gop), SceneCutThreshold(0))
 		}
 	}
 	return
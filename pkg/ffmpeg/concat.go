@@ -0,0 +1,75 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// WriteConcatList writes a concat demuxer list at path naming files in
+// playback order. Relative names are resolved by ffmpeg against the list's
+// directory, so files should be absolute.
+func WriteConcatList(path string, files []string) error {
+	var b strings.Builder
+	for _, f := range files {
+		// Inside single quotes a quote is written as '\''
+		fmt.Fprintf(&b, "file '%s'\n", strings.ReplaceAll(f, "'", `'\''`))
+	}
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// ConcatInput reads the command's input as a concat demuxer list (see
+// WriteConcatList), so its files play back to back as one input.
+func ConcatInput() Option {
+	return OptionFunc(func(cmd *Command) {
+		cmd.preInput = append(cmd.preInput, "-f", "concat", "-safe", "0")
+	})
+}
+
+// ConcatFiles writes output as the files of the concat list at list played
+// back to back. Streams are copied, so every file must share the same codecs
+// and parameters; see ConcatCompatible.
+func ConcatFiles(ctx context.Context, list, output string) error {
+	args := []string{
+		"-hide_banner", "-y",
+		"-f", "concat", "-safe", "0",
+		"-i", list,
+		"-map", "0",
+		"-c", "copy",
+	}
+	if strings.EqualFold(filepath.Ext(output), ".mp4") {
+		args = append(args, "-movflags", "+faststart")
+	}
+	args = append(args, output)
+	return run(ctx, args, nil)
+}
+
+// ConcatCompatible reports whether files probed as parts can be joined with
+// a stream copy: the same video size, frame rate and codec, and the same
+// audio layout. Parts that differ have to be re-encoded (see ConformFilter).
+func ConcatCompatible(parts []*ProbeResult) bool {
+	first := parts[0]
+	for _, p := range parts[1:] {
+		if p.Width != first.Width || p.Height != first.Height || p.FPS != first.FPS ||
+			p.VideoCodec != first.VideoCodec || p.PixelFormat != first.PixelFormat ||
+			p.AudioStreams != first.AudioStreams || p.AudioCodec != first.AudioCodec ||
+			p.AudioChannels != first.AudioChannels || p.AudioSampleRate != first.AudioSampleRate {
+			return false
+		}
+	}
+	return true
+}
+
+// ConformFilter scales and pads every frame into a width x height picture
+// at fps (kept as is when 0), so parts of different sizes or rates can be
+// encoded as one stream.
+func ConformFilter(width, height int, fps float64) string {
+	f := fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=decrease,pad=%d:%d:(ow-iw)/2:(oh-ih)/2,setsar=1", width, height, width, height)
+	if fps > 0 {
+		f += ",fps=" + strconv.FormatFloat(fps, 'f', -1, 64)
+	}
+	return f
+}
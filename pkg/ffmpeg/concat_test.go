@@ -0,0 +1,45 @@
+package ffmpeg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteConcatList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.txt")
+	if err := WriteConcatList(path, []string{"/exports/a.part0.mp4", "/exports/it's.part1.mp4"}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "file '/exports/a.part0.mp4'\nfile '/exports/it'\\''s.part1.mp4'\n"
+	if string(b) != want {
+		t.Fatalf("list = %q, want %q", b, want)
+	}
+}
+
+func TestConcatInput(t *testing.T) {
+	args := NewCommand("list.txt", "out.mp4", ConcatInput(), Filter(ConformFilter(1280, 720, 29.97))).Build()
+	want := []string{"-hide_banner", "-y", "-f", "concat", "-safe", "0", "-i", "list.txt",
+		"-vf", "scale=1280:720:force_original_aspect_ratio=decrease,pad=1280:720:(ow-iw)/2:(oh-ih)/2,setsar=1,fps=29.97",
+		"-movflags", "+faststart", "out.mp4"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %q", args)
+	}
+}
+
+func TestConcatCompatible(t *testing.T) {
+	a := &ProbeResult{Width: 1920, Height: 1080, FPS: 30, VideoCodec: "h264", AudioStreams: 1, AudioCodec: "aac"}
+	b := *a
+	if !ConcatCompatible([]*ProbeResult{a, &b}) {
+		t.Fatal("identical parts are not compatible")
+	}
+	b.Width, b.Height = 1080, 1080
+	if ConcatCompatible([]*ProbeResult{a, &b}) {
+		t.Fatal("parts of different sizes are compatible")
+	}
+}
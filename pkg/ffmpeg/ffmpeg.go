@@ -13,11 +13,19 @@ import (
 type Command struct {
 	input        string
 	output       string
-	preInput     []string // args before -i (like -ss for input seeking)
-	postInput    []string // args after -i
-	filters      []string // collected -vf filters
-	audioFilters []string // collected -af filters
-	rawArgs      []string // when set, Build() returns this verbatim (for multi-input commands)
+	preInput     []string       // args before -i (like -ss for input seeking)
+	postInput    []string       // args after -i
+	filters      []string       // collected -vf filters
+	audioFilters []string       // collected -af filters
+	rawArgs      []string       // when set, Build() returns this verbatim (for multi-input commands)
+	pip          *pipInput      // second input overlaid as an inset (see PictureInPicture)
+	overlays     []imageOverlay // image inputs composited over the video (see ImageOverlay)
+	ladder       []Rendition    // scaled outputs from one decode (see RenditionLadder)
+	hwUpload     string         // filter moving frames to the GPU, run after all others (see PresetExportHardware)
+	animate      string         // frame rate, size and palette chain of an animated image, run after all others (see AnimatedImageFilter)
+	noVideo      bool           // drop the video stream and every video filter (see NoVideo)
+	pass         int            // two-pass encode pass, 0 for single pass (see FirstPass)
+	passLog      string         // -passlogfile prefix shared by both passes
 }
 
 // VideoFilterStrings returns the compiled video filter strings.
@@ -64,13 +72,51 @@ func (c *Command) Build() []string {
 
 	// Input
 	args = append(args, "-i", c.input)
+	if c.pip != nil {
+		args = append(args, c.pip.inputArgs()...)
+	}
+	args = append(args, c.overlayInputArgs()...)
+
+	// A ladder writes several outputs, each with its own output args
+	if len(c.ladder) > 0 {
+		return append(args, c.ladderOutputArgs()...)
+	}
 
 	// Post-input args
 	args = append(args, c.postInput...)
 
-	// Combine video filters
-	if len(c.filters) > 0 {
-		args = append(args, "-vf", strings.Join(c.filters, ","))
+	// Combine video filters; a PiP inset or an image overlay needs a filter
+	// graph over all inputs
+	if c.noVideo {
+		args = append(args, "-vn")
+	} else if c.pip != nil || len(c.overlays) > 0 {
+		var graph string
+		if c.pip != nil {
+			graph = c.pipFilterGraph()
+		} else {
+			graph = c.mainVideoGraph("vout")
+		}
+		out := "[vout]"
+		if c.animate != "" {
+			graph, out = graph+";"+out+c.animate+"[vanim]", "[vanim]"
+		}
+		if c.hwUpload != "" {
+			graph, out = graph+";"+out+c.hwUpload+"[vhw]", "[vhw]"
+		}
+		args = append(args, "-filter_complex", graph, "-map", out, "-map", "0:a?")
+		if c.pip != nil {
+			args = append(args, "-shortest")
+		}
+	} else if vf := c.outputVideoFilters(); len(vf) > 0 {
+		args = append(args, "-vf", strings.Join(vf, ","))
+	}
+
+	// Two-pass encoding; the first pass only gathers video statistics
+	if c.pass > 0 {
+		args = append(args, "-pass", itoa(c.pass), "-passlogfile", c.passLog)
+	}
+	if c.pass == 1 {
+		return append(args, "-an", "-f", "null", c.output)
 	}
 
 	// Combine audio filters
@@ -90,6 +136,21 @@ func (c *Command) Build() []string {
 	return args
 }
 
+// outputVideoFilters returns the -vf chain: the collected filters followed by
+// the animated image chain and the GPU upload, if any.
+func (c *Command) outputVideoFilters() []string {
+	if c.animate == "" && c.hwUpload == "" {
+		return c.filters
+	}
+	vf := append([]string(nil), c.filters...)
+	for _, f := range []string{c.animate, c.hwUpload} {
+		if f != "" {
+			vf = append(vf, f)
+		}
+	}
+	return vf
+}
+
 // Run executes the ffmpeg command.
 func (c *Command) Run(ctx context.Context) error {
 	return run(ctx, c.Build(), nil)
@@ -167,6 +228,35 @@ func SeekTo(start, end time.Duration) Option {
 	})
 }
 
+// accurateSeekPreroll is how far before the start AccurateSeekTo begins
+// decoding; enough to reach the preceding keyframe in typical sources.
+const accurateSeekPreroll = 5 * time.Second
+
+// AccurateSeekTo is SeekTo for outputs that must start exactly on the frame
+// at start. The input is seeked to a point before start, and trim filters
+// placed ahead of every other filter drop the frames and samples up to start
+// and reset timestamps to zero, so time-based filters (fades, start/end
+// ranges) see the same clock as with SeekTo. withAudio must be false for a
+// source with no audio stream, since ffmpeg fails on an audio filter chain
+// with nothing to feed it.
+func AccurateSeekTo(start, end time.Duration, withAudio bool) Option {
+	return OptionFunc(func(cmd *Command) {
+		preroll := min(start, accurateSeekPreroll)
+		cmd.preInput = append(cmd.preInput, "-ss", formatDuration(start-preroll))
+		if duration := end - start; duration > 0 {
+			cmd.postInput = append(cmd.postInput, "-t", formatDuration(duration))
+		}
+		trim := formatDuration(preroll)
+		cmd.filters = append([]string{"trim=start=" + trim, "setpts=PTS-STARTPTS"}, cmd.filters...)
+		for i := range cmd.overlays {
+			cmd.overlays[i].at += 2
+		}
+		if withAudio {
+			cmd.audioFilters = append([]string{"atrim=start=" + trim, "asetpts=PTS-STARTPTS"}, cmd.audioFilters...)
+		}
+	})
+}
+
 // --- Video Codec Options ---
 
 // VideoCodec sets the video codec (-c:v).
@@ -190,6 +280,39 @@ func Preset(name string) Option {
 	})
 }
 
+// KeyframeInterval fixes the GOP size to frames by setting both the maximum
+// (-g) and minimum (-keyint_min) keyframe distance.
+func KeyframeInterval(frames int) Option {
+	return OptionFunc(func(cmd *Command) {
+		cmd.postInput = append(cmd.postInput, "-g", itoa(frames), "-keyint_min", itoa(frames))
+	})
+}
+
+// SceneCutThreshold sets the x264 scene-cut threshold (-sc_threshold). Zero
+// disables extra keyframes on scene changes.
+func SceneCutThreshold(value int) Option {
+	return OptionFunc(func(cmd *Command) {
+		cmd.postInput = append(cmd.postInput, "-sc_threshold", itoa(value))
+	})
+}
+
+// FirstPass makes the command the analysis pass of a two-pass encode. Its
+// statistics go to files prefixed logPrefix; audio and the output file are
+// skipped, so pass os.DevNull as the output.
+func FirstPass(logPrefix string) Option {
+	return OptionFunc(func(cmd *Command) {
+		cmd.pass, cmd.passLog = 1, logPrefix
+	})
+}
+
+// SecondPass makes the command the final pass of a two-pass encode, reading
+// the statistics FirstPass wrote under logPrefix.
+func SecondPass(logPrefix string) Option {
+	return OptionFunc(func(cmd *Command) {
+		cmd.pass, cmd.passLog = 2, logPrefix
+	})
+}
+
 // PixelFormat sets the pixel format (-pix_fmt).
 func PixelFormat(fmt string) Option {
 	return OptionFunc(func(cmd *Command) {
@@ -249,6 +372,13 @@ var NoAudio Option = OptionFunc(func(cmd *Command) {
 	cmd.postInput = append(cmd.postInput, "-an")
 })
 
+// NoVideo disables video in output (-vn). Video filters are dropped with it,
+// so a compiled filter stack can be reused for an audio-only output and only
+// its audio filters take effect.
+var NoVideo Option = OptionFunc(func(cmd *Command) {
+	cmd.noVideo = true
+})
+
 // MapAll maps all streams from input (-map 0).
 var MapAll Option = OptionFunc(func(cmd *Command) {
 	cmd.postInput = append(cmd.postInput, "-map", "0")
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -73,6 +74,42 @@ func TestCommandBuild(t *testing.T) {
 				"output.mp4",
 			},
 		},
+		{
+			name:   "accurate seek trims after a preroll",
+			input:  "input.mp4",
+			output: "output.mp4",
+			opts: []Option{
+				Filter("hflip"),
+				AccurateSeekTo(10*time.Second, 25*time.Second, true),
+			},
+			wantArgs: []string{
+				"-hide_banner", "-y",
+				"-ss", "5.000",
+				"-i", "input.mp4",
+				"-t", "15.000",
+				"-vf", "trim=start=5.000,setpts=PTS-STARTPTS,hflip",
+				"-af", "atrim=start=5.000,asetpts=PTS-STARTPTS",
+				"-movflags", "+faststart",
+				"output.mp4",
+			},
+		},
+		{
+			name:   "accurate seek near the start of a silent source",
+			input:  "input.mp4",
+			output: "output.mp4",
+			opts: []Option{
+				AccurateSeekTo(2*time.Second, 4*time.Second, false),
+			},
+			wantArgs: []string{
+				"-hide_banner", "-y",
+				"-ss", "0.000",
+				"-i", "input.mp4",
+				"-t", "2.000",
+				"-vf", "trim=start=2.000,setpts=PTS-STARTPTS",
+				"-movflags", "+faststart",
+				"output.mp4",
+			},
+		},
 		{
 			name:   "h264 encoding",
 			input:  "input.mp4",
@@ -203,6 +240,101 @@ func TestCommandBuild(t *testing.T) {
 				"output.mp4",
 			},
 		},
+		{
+			name:   "picture in picture",
+			input:  "main.mp4",
+			output: "output.mp4",
+			opts: []Option{
+				SeekTo(10*time.Second, 20*time.Second),
+				Filter("eq=brightness=0.1"),
+				PictureInPicture("signer.mp4", 4*time.Second, 10*time.Second, PiPSpec{VideoID: "x", Position: "top_left", Scale: 0.3}),
+			},
+			wantArgs: []string{
+				"-hide_banner", "-y",
+				"-ss", "10.000",
+				"-i", "main.mp4",
+				"-ss", "4.000", "-t", "10.000", "-i", "signer.mp4",
+				"-t", "10.000",
+				"-filter_complex", "[0:v]eq=brightness=0.1[base];" +
+					"[1:v][base]scale2ref=w=trunc(main_w*0.3/2)*2:h=trunc(ow/dar/2)*2[pip][ref];" +
+					"[ref][pip]overlay=x=W*0.02:y=W*0.02:shortest=1[vout]",
+				"-map", "[vout]", "-map", "0:a?", "-shortest",
+				"-movflags", "+faststart",
+				"output.mp4",
+			},
+		},
+		{
+			name:   "image overlay",
+			input:  "main.mp4",
+			output: "output.mp4",
+			opts: []Option{
+				Filter("eq=brightness=0.1"),
+				ImageOverlay("logo.png", 0.2, 0.5, "W-w-10", "H-h-10"),
+				Filter("hue=s=0"),
+			},
+			wantArgs: []string{
+				"-hide_banner", "-y",
+				"-i", "main.mp4",
+				"-loop", "1", "-i", "logo.png",
+				"-filter_complex", "[0:v]eq=brightness=0.1[ovb0];" +
+					"[1:v]format=rgba,colorchannelmixer=aa=0.5[ovi0];" +
+					"[ovi0][ovb0]scale2ref=w=trunc(main_w*0.2/2)*2:h=trunc(ow/dar/2)*2[ovs0][ovr0];" +
+					"[ovr0][ovs0]overlay=x=W-w-10:y=H-h-10:shortest=1[ovo0];" +
+					"[ovo0]hue=s=0[vout]",
+				"-map", "[vout]", "-map", "0:a?",
+				"-movflags", "+faststart",
+				"output.mp4",
+			},
+		},
+		{
+			name:   "image overlay under picture in picture",
+			input:  "main.mp4",
+			output: "output.mp4",
+			opts: []Option{
+				ImageOverlay("logo.png", 0.1, 1, "10", "10"),
+				PictureInPicture("signer.mp4", 0, 0, PiPSpec{VideoID: "x"}),
+			},
+			wantArgs: []string{
+				"-hide_banner", "-y",
+				"-i", "main.mp4",
+				"-ss", "0.000", "-i", "signer.mp4",
+				"-loop", "1", "-i", "logo.png",
+				"-filter_complex", "[0:v]null[ovb0];" +
+					"[2:v]format=rgba[ovi0];" +
+					"[ovi0][ovb0]scale2ref=w=trunc(main_w*0.1/2)*2:h=trunc(ow/dar/2)*2[ovs0][ovr0];" +
+					"[ovr0][ovs0]overlay=x=10:y=10:shortest=1[ovo0];" +
+					"[ovo0]null[base];" +
+					"[1:v][base]scale2ref=w=trunc(main_w*0.25/2)*2:h=trunc(ow/dar/2)*2[pip][ref];" +
+					"[ref][pip]overlay=x=W-w-W*0.02:y=H-h-W*0.02:shortest=1[vout]",
+				"-map", "[vout]", "-map", "0:a?", "-shortest",
+				"-movflags", "+faststart",
+				"output.mp4",
+			},
+		},
+		{
+			name:   "rendition ladder",
+			input:  "input.mp4",
+			output: "unused.mp4",
+			opts: []Option{
+				SeekTo(10*time.Second, 20*time.Second),
+				CRF(21),
+				Filter("eq=brightness=0.1"),
+				AudioFilter("volume=2"),
+				RenditionLadder([]Rendition{{Height: 1080, Output: "r1080.mp4"}, {Height: 480, Output: "r480.webm"}}),
+			},
+			wantArgs: []string{
+				"-hide_banner", "-y",
+				"-ss", "10.000",
+				"-i", "input.mp4",
+				"-filter_complex", "[0:v]eq=brightness=0.1[vout];[vout]split=2[s0][s1];" +
+					"[s0]scale=-2:trunc(min(1080\\,ih)/2)*2[r0];" +
+					"[s1]scale=-2:trunc(min(480\\,ih)/2)*2[r1]",
+				"-map", "[r0]", "-map", "0:a?", "-t", "10.000", "-crf", "21", "-af", "volume=2",
+				"-movflags", "+faststart", "r1080.mp4",
+				"-map", "[r1]", "-map", "0:a?", "-t", "10.000", "-crf", "21", "-af", "volume=2",
+				"r480.webm",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -214,6 +346,161 @@ func TestCommandBuild(t *testing.T) {
 	}
 }
 
+func TestPiPSpecValidate(t *testing.T) {
+	neg := -1.0
+	assert.NoError(t, PiPSpec{VideoID: "v"}.Validate())
+	assert.NoError(t, PiPSpec{VideoID: "v", Position: "bottom_left", Scale: MaxPiPScale}.Validate())
+	assert.Error(t, PiPSpec{}.Validate())
+	assert.Error(t, PiPSpec{VideoID: "v", Position: "middle"}.Validate())
+	assert.Error(t, PiPSpec{VideoID: "v", Scale: 0.9}.Validate())
+	assert.Error(t, PiPSpec{VideoID: "v", Start: &neg}.Validate())
+}
+
+func TestNormalizeLadder(t *testing.T) {
+	got, err := NormalizeLadder([]int{480, 1080, 720})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1080, 720, 480}, got)
+	for _, bad := range [][]int{{720}, {1080, 720, 480, 360, 240}, {720, 720}, {721, 480}, {100, 480}, {4320, 1080}} {
+		_, err := NormalizeLadder(bad)
+		assert.Error(t, err, "%v", bad)
+	}
+}
+
+func TestExportPresetForFormat_GOP(t *testing.T) {
+	build := func(format string, gop int) string {
+		video, _, _, _ := ExportPresetForFormat(format, ExportSpec{Quality: "high", GOP: gop}, HardwareNone)
+		return strings.Join(NewCommand("in.mp4", "out"+format, video...).Build(), " ")
+	}
+
+	assert.Contains(t, build("mp4", 60), "-g 60 -keyint_min 60 -sc_threshold 0")
+	assert.Contains(t, build("webm", 48), "-g 48 -keyint_min 48")
+	assert.NotContains(t, build("webm", 48), "-sc_threshold")
+	for _, format := range []string{"mp4", "webm", "gif"} {
+		assert.NotContains(t, build(format, 0), "-keyint_min", format)
+	}
+	assert.NotContains(t, build("gif", 30), "-keyint_min")
+}
+
+func TestNormalizeOptions_Angle(t *testing.T) {
+	build := func(codec, videoMap string) string {
+		opts := normalizeOptions(&ProbeResult{VideoCodec: codec, AudioCodec: "aac"}, videoMap, AudioKeepPlayable)
+		return strings.Join(NewCommand("in.mkv", "out.mp4", opts...).Build(), " ")
+	}
+
+	assert.Contains(t, build("h264", "0:v:1"), "-map 0:v:1 -map 0:a?")
+	assert.Contains(t, build("h264", "0:v:1"), "-c:v copy")
+	assert.Contains(t, build("mpeg2video", "0:v:2"), "-c:v libx264")
+}
+
+// ac3ProbeJSON is trimmed ffprobe output for an MKV with H.264 video, a Dolby
+// Digital (AC3) 5.1 track and an Opus commentary track.
+const ac3ProbeJSON = `{
+  "streams": [
+    {"index": 0, "codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080, "r_frame_rate": "24000/1001", "pix_fmt": "yuv420p"},
+    {"index": 1, "codec_type": "audio", "codec_name": "ac3", "sample_rate": "48000", "channels": 6, "channel_layout": "5.1(side)"},
+    {"index": 2, "codec_type": "audio", "codec_name": "opus", "sample_rate": "48000", "channels": 2, "channel_layout": "stereo"}
+  ],
+  "format": {"filename": "in.mkv", "format_name": "matroska,webm", "duration": "5400.000000", "size": "4294967296", "bit_rate": "6362915"}
+}`
+
+func TestNormalizeOptions_AudioPolicy(t *testing.T) {
+	probe, err := parseProbeOutput([]byte(ac3ProbeJSON))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ac3", "opus"}, probe.AudioCodecs)
+
+	build := func(p *ProbeResult, audio AudioPolicy) string {
+		return strings.Join(NewCommand("in.mkv", "out.mp4", normalizeOptions(p, "0:v:0", audio)...).Build(), " ")
+	}
+
+	// AC3 is transcoded under either policy; video is still copied.
+	for _, audio := range []AudioPolicy{AudioKeepPlayable, AudioForceAAC} {
+		cmd := build(probe, audio)
+		assert.Contains(t, cmd, "-c:v copy")
+		assert.Contains(t, cmd, "-c:a aac")
+	}
+
+	// Opus is browser-playable, so only AudioForceAAC transcodes it.
+	opus := &ProbeResult{FormatName: "mov,mp4,m4a,3gp,3g2,mj2", VideoCodec: "h264", AudioCodec: "opus", AudioCodecs: []string{"opus"}}
+	assert.Contains(t, build(opus, AudioKeepPlayable), "-c:a copy")
+	assert.Contains(t, build(opus, AudioForceAAC), "-c:a aac")
+	assert.True(t, IsStreamableMP4(opus, AudioKeepPlayable))
+	assert.False(t, IsStreamableMP4(opus, AudioForceAAC))
+
+	// AAC and silent files are left alone either way.
+	aac := &ProbeResult{FormatName: "mov,mp4,m4a,3gp,3g2,mj2", VideoCodec: "h264", AudioCodec: "aac", AudioCodecs: []string{"aac"}}
+	assert.True(t, IsStreamableMP4(aac, AudioForceAAC))
+	assert.False(t, AudioForceAAC.NeedsAudioTranscode(&ProbeResult{VideoCodec: "h264"}))
+}
+
+// coverFirstProbeJSON is a file whose cover art comes before its real video
+// stream, plus an audio file with nothing but cover art.
+const (
+	coverFirstProbeJSON = `{
+  "format": {"format_name": "matroska,webm", "duration": "12.0"},
+  "streams": [
+    {"index": 0, "codec_type": "video", "codec_name": "mjpeg", "width": 600, "height": 600, "disposition": {"attached_pic": 1}},
+    {"index": 1, "codec_type": "video", "codec_name": "h264", "width": 1920, "height": 1080, "r_frame_rate": "30/1", "pix_fmt": "yuv420p", "disposition": {"attached_pic": 0}},
+    {"index": 2, "codec_type": "audio", "codec_name": "aac", "channels": 2, "sample_rate": "48000"}
+  ]
+}`
+	coverOnlyProbeJSON = `{
+  "format": {"format_name": "mp3", "duration": "200.0"},
+  "streams": [
+    {"index": 0, "codec_type": "audio", "codec_name": "mp3", "channels": 2, "sample_rate": "44100"},
+    {"index": 1, "codec_type": "video", "codec_name": "png", "width": 500, "height": 500, "disposition": {"attached_pic": 1}}
+  ]
+}`
+)
+
+func TestParseProbeOutput_AttachedPics(t *testing.T) {
+	probe, err := parseProbeOutput([]byte(coverFirstProbeJSON))
+	require.NoError(t, err)
+	assert.Equal(t, 1, probe.VideoStreams)
+	assert.Equal(t, []int{0}, probe.AttachedPics)
+	assert.Equal(t, "h264", probe.VideoCodec)
+	assert.Equal(t, 1920, probe.Width)
+	require.Len(t, probe.Angles, 1)
+	assert.Equal(t, 1, probe.Angles[0].Index)
+	assert.Equal(t, "0:v:1", primaryVideoMap(probe))
+
+	audio, err := parseProbeOutput([]byte(coverOnlyProbeJSON))
+	require.NoError(t, err)
+	assert.Equal(t, 0, audio.VideoStreams)
+	assert.Equal(t, []int{0}, audio.AttachedPics)
+	assert.Empty(t, audio.VideoCodec)
+	assert.False(t, NeedsVideoTranscode(audio))
+}
+
+func TestParseProbeOutput_Chapters(t *testing.T) {
+	probe, err := parseProbeOutput([]byte(`{
+  "format": {"format_name": "matroska,webm", "duration": "300.0"},
+  "streams": [],
+  "chapters": [
+    {"id": 0, "start_time": "0.000000", "end_time": "95.500000", "tags": {"title": "Intro"}},
+    {"id": 1, "start_time": "95.500000", "end_time": "300.000000"},
+    {"id": 2, "start_time": "bogus", "end_time": "1.0"}
+  ]
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, []ProbeChapter{
+		{StartTime: 0, EndTime: 95.5, Title: "Intro"},
+		{StartTime: 95.5, EndTime: 300},
+	}, probe.Chapters)
+}
+
+func TestSelectAudioTrackArgs(t *testing.T) {
+	keep := strings.Join(selectAudioTrackArgs("in.mp4", "out.mp4", 1, false), " ")
+	assert.Contains(t, keep, "-map 0:v? -map 0:a -c copy")
+	assert.Contains(t, keep, "-disposition:a 0 -disposition:a:1 default")
+	assert.Contains(t, keep, "-movflags +faststart")
+
+	only := strings.Join(selectAudioTrackArgs("in.webm", "out.webm", 2, true), " ")
+	assert.Contains(t, only, "-map 0:v? -map 0:a:2 -c copy -disposition:a:0 default")
+	assert.NotContains(t, only, "-map 0:a ")
+	assert.NotContains(t, only, "faststart")
+	assert.True(t, strings.HasSuffix(only, " out.webm"))
+}
+
 func TestCropFilter(t *testing.T) {
 	tests := []struct {
 		crop CropFilter
@@ -290,11 +577,17 @@ progress=continue`
 	assert.Equal(t, int64(5000000), p.OutTimeUS)
 	assert.Equal(t, int64(5000), p.OutTimeMS())
 	assert.Equal(t, "2.5x", p.Speed)
+	assert.Equal(t, 2.5, p.SpeedMultiplier())
 	assert.Equal(t, "continue", p.Progress)
 
 	_ = input // silence unused warning
 }
 
+func TestProgressSpeedMultiplier_NA(t *testing.T) {
+	assert.Equal(t, 0.0, Progress{Speed: "N/A"}.SpeedMultiplier())
+	assert.Equal(t, 0.0, Progress{}.SpeedMultiplier())
+}
+
 func TestFormatDuration(t *testing.T) {
 	tests := []struct {
 		d    time.Duration
@@ -634,3 +927,155 @@ func TestIntegration_Probe(t *testing.T) {
 
 	t.Logf("Probe result: %+v", result)
 }
+
+func TestNormalizeOptions_KeepsTextSubtitles(t *testing.T) {
+	build := func(p *ProbeResult) string {
+		return strings.Join(NewCommand("in.mkv", "out.mp4", normalizeOptions(p, "0:v:0", AudioKeepPlayable)...).Build(), " ")
+	}
+
+	subs := &ProbeResult{VideoCodec: "h264", AudioCodec: "aac", SubtitleCodecs: []string{"hdmv_pgs_subtitle", "webvtt", "subrip"}}
+	cmd := build(subs)
+	assert.Contains(t, cmd, "-map 0:s:1 -map 0:s:2")
+	assert.NotContains(t, cmd, "0:s:0")
+	assert.Contains(t, cmd, "-c:s mov_text")
+	assert.NotContains(t, cmd, "-sn")
+
+	bitmapOnly := &ProbeResult{VideoCodec: "h264", AudioCodec: "aac", SubtitleCodecs: []string{"dvd_subtitle"}}
+	assert.Contains(t, build(bitmapOnly), "-sn")
+}
+
+func TestExportPresetForFormat_Hardware(t *testing.T) {
+	build := func(format, quality string, gop int, hw HardwareEncoder, extra ...Option) string {
+		video, _, _, _ := ExportPresetForFormat(format, ExportSpec{Quality: quality, GOP: gop}, hw)
+		return strings.Join(NewCommand("in.mp4", "out."+format, append(extra, video...)...).Build(), " ")
+	}
+
+	nvenc := build("mp4", "high", 60, HardwareNVENC)
+	assert.Contains(t, nvenc, "-c:v h264_nvenc -preset p5 -tune hq -rc vbr -cq 21 -b:v 0")
+	assert.Contains(t, nvenc, "-g 60 -keyint_min 60 -no-scenecut 1")
+	assert.NotContains(t, nvenc, "-crf")
+	assert.Contains(t, build("mp4", "max", 0, HardwareNVENC), "-preset p7 -tune hq -rc vbr -cq 17")
+
+	qsv := build("mp4", "high", 0, HardwareQSV)
+	assert.Contains(t, qsv, "-c:v h264_qsv -preset medium -global_quality 21 -pix_fmt nv12")
+
+	// VAAPI uploads after the CPU filters, in every filter layout.
+	vaapi := build("mp4", "high", 0, HardwareVAAPI, Filter("hflip"))
+	assert.Contains(t, vaapi, "-vaapi_device /dev/dri/renderD128 -i in.mp4")
+	assert.Contains(t, vaapi, "-vf hflip,format=nv12,hwupload")
+	assert.Contains(t, vaapi, "-c:v h264_vaapi -rc_mode CQP -qp 21")
+	assert.Contains(t, build("mp4", "high", 0, HardwareVAAPI), "-vf format=nv12,hwupload")
+	pip := build("mp4", "high", 0, HardwareVAAPI, PictureInPicture("pip.mp4", 0, 0, PiPSpec{VideoID: "v"}))
+	assert.Contains(t, pip, ";[vout]format=nv12,hwupload[vhw] -map [vhw]")
+	ladder := build("mp4", "high", 0, HardwareVAAPI, RenditionLadder([]Rendition{{Height: 720, Output: "a.mp4"}, {Height: 480, Output: "b.mp4"}}))
+	assert.Contains(t, ladder, "*2,format=nv12,hwupload[r0]")
+	assert.Contains(t, ladder, "*2,format=nv12,hwupload[r1]")
+
+	// WebM and GIF stay in software.
+	assert.Contains(t, build("webm", "high", 0, HardwareNVENC), "-c:v libvpx-vp9")
+	assert.NotContains(t, build("gif", "high", 0, HardwareVAAPI), "hwupload")
+}
+
+func TestExportPresetForFormat_TwoPass(t *testing.T) {
+	build := func(format, quality string, hw HardwareEncoder, pass Option) (string, bool) {
+		video, _, _, twoPass := ExportPresetForFormat(format, ExportSpec{Quality: quality, TargetBitrate: 8000}, hw)
+		opts := append(video, AudioFilter("volume=2"), pass)
+		return strings.Join(NewCommand("in.mp4", "out."+format, opts...).Build(), " "), twoPass
+	}
+
+	second, twoPass := build("mp4", "max", HardwareNVENC, SecondPass("/x/e.passlog"))
+	assert.True(t, twoPass)
+	assert.Contains(t, second, "-c:v libx264 -preset slow -b:v 8000k")
+	assert.NotContains(t, second, "-crf")
+	assert.Contains(t, second, "-pass 2 -passlogfile /x/e.passlog -af volume=2 -movflags +faststart out.mp4")
+
+	first, _ := build("webm", "high", HardwareNone, FirstPass("/x/e.passlog"))
+	assert.Contains(t, first, "-c:v libvpx-vp9 -b:v 8000k -row-mt 1")
+	assert.True(t, strings.HasSuffix(first, "-pass 1 -passlogfile /x/e.passlog -an -f null out.webm"), first)
+	assert.NotContains(t, first, "-af")
+
+	_, _, _, twoPass = ExportPresetForFormat("gif", ExportSpec{Quality: "max", TargetBitrate: 8000}, HardwareNone)
+	assert.False(t, twoPass)
+	_, _, _, twoPass = ExportPresetForFormat("mp4", ExportSpec{Quality: "max"}, HardwareNone)
+	assert.False(t, twoPass)
+}
+
+func TestExportPresetForFormat_AnimatedImage(t *testing.T) {
+	build := func(format string, spec ExportSpec) string {
+		video, audio, ext, _ := ExportPresetForFormat(format, spec, HardwareNone)
+		assert.Nil(t, audio, format)
+		opts := append(video, Filter("hflip"))
+		return strings.Join(NewCommand("in.mp4", "out"+ext, opts...).Build(), " ")
+	}
+
+	// The palette pass runs after the export's own filters.
+	gif := build("gif", ExportSpec{})
+	assert.Contains(t, gif, "-vf hflip,fps=15,scale=min(480\\,iw):-1:flags=lanczos,split[pal_in][pal_src];[pal_in]palettegen")
+	assert.Contains(t, gif, "[pal_src][pal]paletteuse")
+	assert.Contains(t, gif, "-an -loop 0")
+	assert.True(t, strings.HasSuffix(gif, "out.gif"), gif)
+
+	webp := build("webp", ExportSpec{Quality: "max", FPS: 24, MaxWidth: 640, GOP: 60})
+	assert.Contains(t, webp, "-c:v libwebp -lossless 0 -q:v 90")
+	assert.Contains(t, webp, "-vf hflip,fps=24,scale=min(640\\,iw):-1:flags=lanczos")
+	assert.NotContains(t, webp, "palettegen")
+	assert.NotContains(t, webp, "-keyint_min")
+	assert.True(t, strings.HasSuffix(webp, "out.webp"), webp)
+}
+
+func TestExportPresetForFormat_Audio(t *testing.T) {
+	build := func(format, quality string) (string, string) {
+		video, audio, ext, twoPass := ExportPresetForFormat(format, ExportSpec{Quality: quality, GOP: 60, TargetBitrate: 8000}, HardwareNone)
+		assert.False(t, twoPass, format)
+		opts := append(append(video, audio...), Filter("hflip"), AudioFilter("volume=2"))
+		return strings.Join(NewCommand("in.mp4", "out"+ext, opts...).Build(), " "), ext
+	}
+
+	mp3, ext := build("mp3", "max")
+	assert.Equal(t, ".mp3", ext)
+	assert.Contains(t, mp3, "-c:a libmp3lame -b:a 320k")
+	assert.Contains(t, mp3, "-vn -af volume=2 out.mp3")
+	for _, notWant := range []string{"-vf", "hflip", "-c:v", "-keyint_min"} {
+		assert.NotContains(t, mp3, notWant)
+	}
+
+	m4a, _ := build("m4a", "high")
+	assert.Contains(t, m4a, "-c:a aac -b:a 192k")
+	assert.Contains(t, m4a, "-movflags +faststart out.m4a")
+
+	flac, _ := build("flac", "high")
+	assert.Contains(t, flac, "-c:a flac")
+	assert.NotContains(t, flac, "-b:a")
+}
+
+func TestExportSpec_ImageSize(t *testing.T) {
+	assert.Equal(t, DefaultImageFPS, ExportSpec{}.ImageFPS())
+	assert.Equal(t, MaxImageFPS, ExportSpec{FPS: 120}.ImageFPS())
+	assert.Equal(t, DefaultImageMaxWidth, ExportSpec{}.ImageMaxWidth())
+	assert.Equal(t, MinImageMaxWidth, ExportSpec{MaxWidth: 8}.ImageMaxWidth())
+	assert.Equal(t, MaxImageMaxWidth, ExportSpec{MaxWidth: 4000}.ImageMaxWidth())
+}
+
+func TestCheckAnimatedImage(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+		return p
+	}
+	assert.NoError(t, CheckAnimatedImage(write("a.gif", "GIF89a\x01\x00\x01\x00\x00\x00")))
+	assert.NoError(t, CheckAnimatedImage(write("a.webp", "RIFF\x10\x00\x00\x00WEBPVP8X")))
+	assert.Error(t, CheckAnimatedImage(write("a.mp4", "\x00\x00\x00\x18ftypmp42")))
+	assert.Error(t, CheckAnimatedImage(write("short.gif", "GIF")))
+	assert.Error(t, CheckAnimatedImage(filepath.Join(dir, "missing.gif")))
+}
+
+func TestParseHardwareEncoder(t *testing.T) {
+	for in, want := range map[string]HardwareEncoder{"": HardwareNone, "off": HardwareNone, " NVENC ": HardwareNVENC, "vaapi": HardwareVAAPI, "qsv": HardwareQSV} {
+		got, err := ParseHardwareEncoder(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseHardwareEncoder("cuda")
+	assert.Error(t, err)
+}
@@ -3,18 +3,88 @@ package ffmpeg
 import (
 	"fmt"
 	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"thirdcoast.systems/rewind/pkg/utils/crops"
 )
 
 // ExportSpec describes the full encoding recipe for a clip export.
 type ExportSpec struct {
-	// Format is the output container format: "mp4", "webm", "mkv"
+	// Format is the output container format: "mp4", "webm", or an animated
+	// image format, "gif" or "webp" (see IsAnimatedImageFormat)
 	Format string `json:"format,omitempty"`
 	// Quality selects the encoding quality tier: "high" (CRF 21), "max" (CRF 18)
 	Quality string `json:"quality,omitempty"`
 	// Filters is an ordered list of filters to apply (video + audio).
 	Filters []FilterSpec `json:"filters,omitempty"`
+	// Loop is how many times the cut clip is played back to back in the
+	// output. 0 and 1 both mean a single play; the maximum is MaxExportLoop.
+	Loop int `json:"loop,omitempty"`
+	// GOP is the keyframe interval in frames. When set, keyframes are forced
+	// at exactly this interval; 0 leaves the encoder's default GOP structure.
+	GOP int `json:"gop,omitempty"`
+	// Accurate starts the output exactly on the clip's first frame by
+	// decoding from before it and trimming (see AccurateSeekTo). The default
+	// fast seek is quicker but can start on a nearby keyframe.
+	Accurate bool `json:"accurate,omitempty"`
+	// TargetBitrate, in kbps, encodes MP4 and WebM in two passes at this
+	// average bitrate instead of at constant quality; 0 for one pass. It
+	// always encodes in software. See PresetExportTwoPass.
+	TargetBitrate int `json:"target_bitrate,omitempty"`
+	// FPS and MaxWidth set the frame rate and the width limit of GIF and
+	// WebP output; 0 selects the defaults. See ImageFPS and ImageMaxWidth.
+	FPS      int `json:"fps,omitempty"`
+	MaxWidth int `json:"max_width,omitempty"`
+	// PiP insets a second video in a corner of the output; nil for none.
+	PiP *PiPSpec `json:"pip,omitempty"`
+	// Ladder lists the target heights of a rendition ladder, tallest first.
+	// Only the ladder's lead export carries it; the encoder writes every
+	// pending rung from one decode. See NormalizeLadder.
+	Ladder []int `json:"ladder,omitempty"`
+	// Height scales the output to this height (never upscaling); 0 keeps
+	// the source size. Set on each rung of a ladder.
+	Height int `json:"height,omitempty"`
+	// Concat lists the clip IDs of a concat export in playback order. Each
+	// clip is cut and filtered with its own saved filter stack, then the
+	// parts are joined into one file. Only "concat:" variants carry it.
+	Concat []string `json:"concat,omitempty"`
+}
+
+// MaxExportLoop caps ExportSpec.Loop so a short clip cannot be blown up into
+// an arbitrarily long export.
+const MaxExportLoop = 10
+
+// MaxExportGOP caps ExportSpec.GOP (10 seconds at 60 fps).
+const MaxExportGOP = 600
+
+// MinExportBitrate and MaxExportBitrate bound ExportSpec.TargetBitrate, in
+// kbps.
+const (
+	MinExportBitrate = 100
+	MaxExportBitrate = 100000
+)
+
+// MinSpeedFactor and MaxSpeedFactor bound the speed filter's factor.
+const (
+	MinSpeedFactor = 0.1
+	MaxSpeedFactor = 4.0
+)
+
+// LoopCount returns the number of plays the spec asks for, clamped to
+// [1, MaxExportLoop].
+func (s ExportSpec) LoopCount() int {
+	if s.Loop < 1 {
+		return 1
+	}
+	if s.Loop > MaxExportLoop {
+		return MaxExportLoop
+	}
+	return s.Loop
 }
 
 // FilterSpec describes a single filter in the export pipeline.
@@ -26,15 +96,32 @@ type FilterSpec struct {
 }
 
 // CompileFilters converts a slice of FilterSpec into ffmpeg Options.
-// clipCrops is needed to resolve crop IDs to coordinates.
+// clipCrops is needed to resolve crop IDs to coordinates. The source is
+// assumed to have audio; see CompileFiltersForSource.
 func CompileFilters(specs []FilterSpec, clipCrops crops.CropArray) ([]Option, error) {
-	var opts []Option
+	opts, _, err := CompileFiltersForSource(specs, clipCrops, true)
+	return opts, err
+}
+
+// CompileFiltersForSource is CompileFilters for a source that may have no
+// audio stream. When hasAudio is false, audio filters are left out, since
+// ffmpeg fails on an audio filter chain with no audio to feed it; filters
+// that touch both (speed, reverse, …) keep their video part. skipped lists
+// "filter[i] (type)" for each filter dropped entirely, for callers to log.
+func CompileFiltersForSource(specs []FilterSpec, clipCrops crops.CropArray, hasAudio bool) (opts []Option, skipped []string, err error) {
 	hasCrop := false
 
 	for i, spec := range specs {
 		filterOpts, err := compileFilter(spec, clipCrops)
 		if err != nil {
-			return nil, fmt.Errorf("filter[%d] (%s): %w", i, spec.Type, err)
+			return nil, nil, fmt.Errorf("filter[%d] (%s): %w", i, spec.Type, err)
+		}
+		if !hasAudio {
+			filterOpts = videoFiltersOnly(filterOpts)
+			if len(filterOpts) == 0 {
+				skipped = append(skipped, fmt.Sprintf("filter[%d] (%s)", i, spec.Type))
+				continue
+			}
 		}
 		opts = append(opts, filterOpts...)
 
@@ -48,11 +135,443 @@ func CompileFilters(specs []FilterSpec, clipCrops crops.CropArray) ([]Option, er
 		opts = append(opts, EvenDimensions())
 	}
 
-	return opts, nil
+	return opts, skipped, nil
+}
+
+// videoFiltersOnly returns opts with any audio filters removed.
+func videoFiltersOnly(opts []Option) []Option {
+	scratch := &Command{}
+	for _, opt := range opts {
+		opt.Apply(scratch)
+	}
+	if len(scratch.audioFilters) == 0 {
+		return opts
+	}
+	out := make([]Option, 0, len(scratch.filters))
+	for _, f := range scratch.filters {
+		out = append(out, Filter(f))
+	}
+	return out
+}
+
+// StripInjectedParams returns a copy of specs without any "_"-prefixed
+// params. Those keys are reserved for what encoders inject (file paths, clip
+// timing, measurements), so a filter stack from a request or a saved clip
+// must never carry them: apply it to every stack from user input.
+func StripInjectedParams(specs []FilterSpec) []FilterSpec {
+	out := make([]FilterSpec, len(specs))
+	for i, spec := range specs {
+		out[i] = spec
+		for k := range spec.Params {
+			if !strings.HasPrefix(k, "_") {
+				continue
+			}
+			params := make(map[string]any, len(spec.Params))
+			for k, v := range spec.Params {
+				if !strings.HasPrefix(k, "_") {
+					params[k] = v
+				}
+			}
+			out[i].Params = params
+			break
+		}
+	}
+	return out
+}
+
+// dropParams returns a copy of specs in which no filter carries keys. The
+// helpers that inject params start with it, so an injected key only ever
+// holds the value they set, never one left in the stack.
+func dropParams(specs []FilterSpec, keys ...string) []FilterSpec {
+	out := make([]FilterSpec, len(specs))
+	for i, spec := range specs {
+		out[i] = spec
+		for _, key := range keys {
+			if _, ok := spec.Params[key]; !ok {
+				continue
+			}
+			params := make(map[string]any, len(spec.Params))
+			for k, v := range spec.Params {
+				if !slices.Contains(keys, k) {
+					params[k] = v
+				}
+			}
+			out[i].Params = params
+			break
+		}
+	}
+	return out
+}
+
+// clipDurationParam is the params key WithClipDuration injects for filters
+// whose output depends on how long the clip is.
+const clipDurationParam = "_clip_duration"
+
+// durationAwareFilters are the filter types WithClipDuration annotates.
+var durationAwareFilters = map[string]bool{
+	"ken_burns":      true,
+	"fade_out":       true,
+	"audio_fade_out": true,
+}
+
+// WithClipDuration returns a copy of specs in which duration-aware filters
+// (ken_burns and the fade-outs) carry the clip duration in seconds. Encoders
+// call it before compiling, since the filter stack itself is
+// duration-agnostic. Filters run in stack order, so a filter after a speed
+// filter gets the duration at that speed.
+func WithClipDuration(specs []FilterSpec, seconds float64) []FilterSpec {
+	specs = dropParams(specs, clipDurationParam)
+	if seconds <= 0 {
+		return specs
+	}
+	out := make([]FilterSpec, len(specs))
+	for i, spec := range specs {
+		out[i] = spec
+		if spec.Type == "speed" {
+			if factor := speedFactor(spec.Params); factor >= MinSpeedFactor && factor <= MaxSpeedFactor {
+				seconds /= factor
+			}
+			continue
+		}
+		if !durationAwareFilters[spec.Type] {
+			continue
+		}
+		params := make(map[string]any, len(spec.Params)+1)
+		for k, v := range spec.Params {
+			params[k] = v
+		}
+		params[clipDurationParam] = seconds
+		out[i].Params = params
+	}
+	return out
+}
+
+// sourceFPSParam is the params key WithSourceFPS injects for filters that
+// read an extra input, which must match the video's frame rate.
+const sourceFPSParam = "_source_fps"
+
+// WithSourceFPS returns a copy of specs in which chromakey filters carry
+// the source video's frame rate, so their background does not resample the
+// output to ffmpeg's default 25 fps. Like WithClipDuration it is applied by
+// encoders before compiling.
+func WithSourceFPS(specs []FilterSpec, fps float64) []FilterSpec {
+	specs = dropParams(specs, sourceFPSParam)
+	if fps <= 0 {
+		return specs
+	}
+	out := make([]FilterSpec, len(specs))
+	for i, spec := range specs {
+		out[i] = spec
+		if spec.Type != "chromakey" {
+			continue
+		}
+		params := make(map[string]any, len(spec.Params)+1)
+		for k, v := range spec.Params {
+			params[k] = v
+		}
+		params[sourceFPSParam] = fps
+		out[i].Params = params
+	}
+	return out
+}
+
+// speedFactor returns a speed filter's factor, rounded once so setpts and the
+// atempo chain use the same factor and audio stays in sync with video.
+func speedFactor(params map[string]any) float64 {
+	return math.Round(paramFloat(params, "factor", 1.0)*1e4) / 1e4
+}
+
+// clipStartParam is the params key WithClipStart injects for filters that
+// show where in the source video a frame comes from.
+const clipStartParam = "_clip_start"
+
+// WithClipStart returns a copy of specs in which source-relative filters
+// (timecode and subtitles) carry the clip's start offset in the source video,
+// in seconds. Like WithClipDuration it is applied by encoders before
+// compiling.
+func WithClipStart(specs []FilterSpec, seconds float64) []FilterSpec {
+	specs = dropParams(specs, clipStartParam)
+	if seconds <= 0 {
+		return specs
+	}
+	out := make([]FilterSpec, len(specs))
+	for i, spec := range specs {
+		out[i] = spec
+		if spec.Type != "timecode" && spec.Type != "subtitles" {
+			continue
+		}
+		params := make(map[string]any, len(spec.Params)+1)
+		for k, v := range spec.Params {
+			params[k] = v
+		}
+		params[clipStartParam] = seconds
+		out[i].Params = params
+	}
+	return out
+}
+
+// overlayImagePathParam is the params key ResolveOverlayImages injects with
+// the file of an image_overlay filter's image.
+const overlayImagePathParam = "_image_path"
+
+// ResolveOverlayImages returns a copy of specs in which image_overlay filters,
+// and chromakey filters with an image background, carry the path of their
+// image, looked up by "image_id" in dir (see OverlayImageDir). Encoders call
+// it before compiling, with the directory of the user who requested the
+// export. Errors are user-facing.
+func ResolveOverlayImages(specs []FilterSpec, dir string) ([]FilterSpec, error) {
+	specs = dropParams(specs, overlayImagePathParam)
+	out := make([]FilterSpec, len(specs))
+	for i, spec := range specs {
+		out[i] = spec
+		background, _ := spec.Params["background"].(string)
+		if spec.Type != "image_overlay" && !(spec.Type == "chromakey" && background == "image") {
+			continue
+		}
+		imageID, _ := spec.Params["image_id"].(string)
+		if imageID == "" {
+			return nil, fmt.Errorf("filter[%d] (%s): image_id is required", i, spec.Type)
+		}
+		path, err := OverlayImagePath(dir, imageID)
+		if err != nil {
+			return nil, fmt.Errorf("filter[%d] (%s): %w", i, spec.Type, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("filter[%d] (%s): overlay image %s not found", i, spec.Type, imageID)
+		}
+		params := make(map[string]any, len(spec.Params)+1)
+		for k, v := range spec.Params {
+			params[k] = v
+		}
+		params[overlayImagePathParam] = path
+		out[i].Params = params
+	}
+	return out, nil
+}
+
+// lutPathParam and lutNameParam are the params keys ResolveLUTFiles injects
+// with the file and the name of a lut_file filter's LUT.
+const (
+	lutPathParam = "_lut_path"
+	lutNameParam = "_lut_name"
+)
+
+// ResolveLUTFiles returns a copy of specs in which lut_file filters carry the
+// absolute path and the name of their LUT, looked up by "lut_id" in dir (see
+// LUTFileDir). Like ResolveOverlayImages it is applied by encoders before
+// compiling. Errors are user-facing.
+func ResolveLUTFiles(specs []FilterSpec, dir string) ([]FilterSpec, error) {
+	specs = dropParams(specs, lutPathParam, lutNameParam)
+	out := make([]FilterSpec, len(specs))
+	for i, spec := range specs {
+		out[i] = spec
+		if spec.Type != "lut_file" {
+			continue
+		}
+		lutID, _ := spec.Params["lut_id"].(string)
+		if lutID == "" {
+			return nil, fmt.Errorf("filter[%d] (lut_file): lut_id is required", i)
+		}
+		path, err := LUTFilePath(dir, lutID)
+		if err != nil {
+			return nil, fmt.Errorf("filter[%d] (lut_file): %w", i, err)
+		}
+		if path, err = filepath.Abs(path); err != nil {
+			return nil, fmt.Errorf("filter[%d] (lut_file): %w", i, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("filter[%d] (lut_file): LUT %s not found", i, lutID)
+		}
+		info, err := ReadLUTInfo(path)
+		if err != nil {
+			return nil, fmt.Errorf("filter[%d] (lut_file): LUT %s has no stored info", i, lutID)
+		}
+		params := make(map[string]any, len(spec.Params)+2)
+		for k, v := range spec.Params {
+			params[k] = v
+		}
+		params[lutPathParam] = path
+		params[lutNameParam] = info.Name
+		out[i].Params = params
+	}
+	return out, nil
+}
+
+// subtitlesPathParam is the params key ResolveSubtitleFiles injects with the
+// caption file a subtitles filter burns in.
+const subtitlesPathParam = "_subtitles_path"
+
+// subtitleLangRe matches the caption languages a subtitles filter may ask
+// for: a lowercase BCP 47 tag as used in caption file names.
+var subtitleLangRe = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// ResolveSubtitleFiles returns a copy of specs in which subtitles filters
+// carry the caption file to burn in. find looks it up by the filter's "lang"
+// param, "" asking for the video's default captions. Like ResolveLUTFiles it
+// is applied by encoders before compiling. Errors are user-facing.
+func ResolveSubtitleFiles(specs []FilterSpec, find func(lang string) (string, bool)) ([]FilterSpec, error) {
+	specs = dropParams(specs, subtitlesPathParam)
+	out := make([]FilterSpec, len(specs))
+	for i, spec := range specs {
+		out[i] = spec
+		if spec.Type != "subtitles" {
+			continue
+		}
+		lang, _ := spec.Params["lang"].(string)
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang != "" && !subtitleLangRe.MatchString(lang) {
+			return nil, fmt.Errorf("filter[%d] (subtitles): invalid lang %q", i, lang)
+		}
+		path, ok := find(lang)
+		if !ok {
+			if lang == "" {
+				return nil, fmt.Errorf("filter[%d] (subtitles): the video has no captions to burn in", i)
+			}
+			return nil, fmt.Errorf("filter[%d] (subtitles): the video has no %s captions to burn in", i, lang)
+		}
+		params := make(map[string]any, len(spec.Params)+1)
+		for k, v := range spec.Params {
+			params[k] = v
+		}
+		params[subtitlesPathParam] = path
+		out[i].Params = params
+	}
+	return out, nil
+}
+
+// LUTFileNames returns the names of the LUTs of specs resolved by
+// ResolveLUTFiles, in filter order, for export metadata.
+func LUTFileNames(specs []FilterSpec) []string {
+	var names []string
+	for _, spec := range specs {
+		if name, _ := spec.Params[lutNameParam].(string); spec.Type == "lut_file" && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// timelineFilterTypes are the filter types whose compiled ffmpeg filters all
+// support timeline editing (the "enable" option), so they can be limited to
+// part of the clip with the generic "start"/"end" params.
+var timelineFilterTypes = map[string]bool{
+	"brightness": true, "contrast": true, "saturation": true, "gamma": true,
+	"curves": true, "grayscale": true, "sepia": true, "sharpen": true,
+	"denoise": true, "vignette": true, "color_balance": true, "color_temp": true,
+	"lift_gamma_gain": true, "exposure": true, "lut": true, "lut_file": true, "blur": true,
+	"text": true, "timecode": true,
+	"volume": true, "equalizer": true, "bass": true, "treble": true,
+	"highpass": true, "lowpass": true,
+}
+
+// SupportsTimeline reports whether a filter type accepts "start"/"end" params.
+func SupportsTimeline(filterType string) bool {
+	return timelineFilterTypes[filterType]
 }
 
 // compileFilter converts a single FilterSpec into one or more ffmpeg Options.
+// When the spec carries a "start" and/or "end" (seconds from the clip start),
+// every compiled filter is limited to that range via ffmpeg's enable option.
 func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error) {
+	enable, err := timelineEnableExpr(spec.Params)
+	if err != nil {
+		return nil, err
+	}
+	if enable != "" && !SupportsTimeline(spec.Type) {
+		return nil, fmt.Errorf("start/end is not supported for this filter")
+	}
+	opts, err := compileFilterType(spec, clipCrops)
+	if err != nil || enable == "" || len(opts) == 0 {
+		return opts, err
+	}
+
+	// Render the options to filter strings, then re-emit each with the
+	// enable expression appended.
+	scratch := &Command{}
+	for _, opt := range opts {
+		opt.Apply(scratch)
+	}
+	out := make([]Option, 0, len(scratch.filters)+len(scratch.audioFilters))
+	for _, f := range scratch.filters {
+		out = append(out, Filter(withEnable(f, enable)))
+	}
+	for _, f := range scratch.audioFilters {
+		out = append(out, AudioFilter(withEnable(f, enable)))
+	}
+	return out, nil
+}
+
+// CompileStillFilter compiles one filter for a single still frame and returns
+// its video filter chain, or "" when the params make it a no-op. Filters that
+// act over time or on audio, and crop by ID (which needs a clip's crops), are
+// rejected; start/end params are ignored since a still has no timeline.
+// Errors are user-facing.
+func CompileStillFilter(spec FilterSpec) (string, error) {
+	switch spec.Type {
+	case "crop":
+		return "", fmt.Errorf("crop needs a clip; use crop_manual to preview a crop")
+	case "speed", "fade_in", "fade_out", "reverse", "ken_burns", "timecode", "subtitles", "stabilize":
+		return "", fmt.Errorf("%s changes over time and cannot be previewed on a still frame", spec.Type)
+	case "image_overlay", "lut_file":
+		return "", fmt.Errorf("%s needs an uploaded file and cannot be previewed on a still frame", spec.Type)
+	case "chromakey":
+		return "", fmt.Errorf("chromakey needs a background input and cannot be previewed on a still frame")
+	case "volume", "normalize", "equalizer", "bass", "treble", "compressor",
+		"noise_gate", "highpass", "lowpass", "audio_fade_in", "audio_fade_out", "mute":
+		return "", fmt.Errorf("only video filters can be previewed")
+	}
+	params := make(map[string]any, len(spec.Params))
+	for k, v := range spec.Params {
+		if k != "start" && k != "end" {
+			params[k] = v
+		}
+	}
+	opts, err := compileFilterType(FilterSpec{Type: spec.Type, Params: params}, nil)
+	if err != nil {
+		return "", err
+	}
+	scratch := &Command{}
+	for _, opt := range opts {
+		opt.Apply(scratch)
+	}
+	if len(scratch.audioFilters) > 0 || len(scratch.preInput) > 0 || len(scratch.postInput) > 0 {
+		return "", fmt.Errorf("only video filters can be previewed")
+	}
+	return strings.Join(scratch.filters, ","), nil
+}
+
+// timelineEnableExpr builds the enable expression for the optional "start"
+// and "end" params. An unset or zero end means "until the end of the clip";
+// an empty result means the filter applies to the whole clip.
+func timelineEnableExpr(params map[string]any) (string, error) {
+	start := paramFloat(params, "start", 0)
+	end := paramFloat(params, "end", 0)
+	if start < 0 || end < 0 {
+		return "", fmt.Errorf("start and end must not be negative")
+	}
+	switch {
+	case end > 0 && end <= start:
+		return "", fmt.Errorf("end must be after start")
+	case end > 0:
+		return fmt.Sprintf("between(t,%.3f,%.3f)", start, end), nil
+	case start > 0:
+		return fmt.Sprintf("gte(t,%.3f)", start), nil
+	default:
+		return "", nil
+	}
+}
+
+// withEnable appends an enable option to a single filter string.
+func withEnable(filter, enable string) string {
+	sep := ":"
+	if !strings.Contains(filter, "=") {
+		sep = "="
+	}
+	return filter + sep + "enable='" + enable + "'"
+}
+
+// compileFilterType converts a FilterSpec into Options for its type alone.
+func compileFilterType(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error) {
 	switch spec.Type {
 
 	// === Video - Spatial ===
@@ -124,18 +643,20 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 		}
 		return []Option{Filter(fmt.Sprintf("pad=%d:%d:(ow-iw)/2:(oh-ih)/2:%s", w, h, color))}, nil
 
+	case "chromakey":
+		return compileChromaKey(spec.Params)
+
 	// === Video - Temporal ===
 
 	case "speed":
-		factor := paramFloat(spec.Params, "factor", 1.0)
+		factor := speedFactor(spec.Params)
 		if factor == 1.0 {
 			return nil, nil
 		}
-		if factor <= 0 || factor > 4.0 {
-			return nil, fmt.Errorf("speed factor must be between 0.25 and 4.0")
+		if factor < MinSpeedFactor || factor > MaxSpeedFactor {
+			return nil, fmt.Errorf("speed factor must be between %g and %g", MinSpeedFactor, MaxSpeedFactor)
 		}
-		opts := []Option{Filter(fmt.Sprintf("setpts=PTS/%.4f", factor))}
-		// Audio atempo only supports 0.5-2.0 range; chain for larger ranges
+		opts := []Option{Filter("setpts=PTS/" + strconv.FormatFloat(factor, 'f', -1, 64))}
 		opts = append(opts, atempoChain(factor)...)
 		return opts, nil
 
@@ -152,10 +673,7 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 	case "fade_out":
 		dur := paramFloat(spec.Params, "duration", 0.5)
 		color := paramColor(spec.Params, "color", "black")
-		// Note: start time for fade_out must be calculated by the encoder
-		// from clip duration. offset param (seconds before clip end) is stored
-		// in the spec but applied by the encoder when it knows total duration.
-		filter := fmt.Sprintf("fade=t=out:d=%.3f", dur)
+		filter := fmt.Sprintf("fade=t=out:%sd=%.3f", fadeOutStart(spec.Params, dur), dur)
 		if color != "black" && color != "#000000" {
 			filter += fmt.Sprintf(":c=%s", color)
 		}
@@ -164,6 +682,12 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 	case "reverse":
 		return []Option{Filter("reverse"), AudioFilter("areverse")}, nil
 
+	case "ken_burns":
+		return compileKenBurns(spec.Params)
+
+	case "stabilize":
+		return compileStabilize(spec.Params)
+
 	// === Video - Color & Effects ===
 
 	case "brightness":
@@ -226,6 +750,16 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 		angle := paramFloat(spec.Params, "angle", 0.628) // PI/5 default
 		return []Option{Filter(fmt.Sprintf("vignette=a=%.4f", angle))}, nil
 
+	case "blur":
+		radius := paramInt(spec.Params, "radius", 10)
+		if radius <= 0 {
+			return nil, nil
+		}
+		if radius > 50 {
+			return nil, fmt.Errorf("radius must be between 1 and 50")
+		}
+		return []Option{Filter(fmt.Sprintf("boxblur=luma_radius=%d:luma_power=2", radius))}, nil
+
 	case "color_balance":
 		parts := ""
 		for _, key := range []string{"rs", "gs", "bs", "rm", "gm", "bm", "rh", "gh", "bh"} {
@@ -298,6 +832,16 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 		}
 		return compileLUTPreset(preset)
 
+	case "lut_file":
+		path, _ := spec.Params[lutPathParam].(string)
+		if path == "" {
+			if lutID, _ := spec.Params["lut_id"].(string); lutID == "" {
+				return nil, fmt.Errorf("lut_id is required")
+			}
+			return nil, fmt.Errorf("LUT file is not available")
+		}
+		return []Option{Filter("lut3d=file=" + escapeFilterPath(path))}, nil
+
 	// === Video - Overlay & Text ===
 
 	case "text":
@@ -311,6 +855,15 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 		x, y := textPosition(position)
 		return []Option{Filter(fmt.Sprintf("drawtext=text='%s':fontsize=%d:fontcolor=%s:x=%s:y=%s", text, fontSize, color, x, y))}, nil
 
+	case "timecode":
+		return compileTimecode(spec.Params)
+
+	case "image_overlay":
+		return compileImageOverlay(spec.Params)
+
+	case "subtitles":
+		return compileSubtitles(spec.Params)
+
 	// === Audio ===
 
 	case "volume":
@@ -333,8 +886,7 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 	case "audio_fade_out":
 		dur := paramFloat(spec.Params, "duration", 0.5)
 		curve, _ := spec.Params["curve"].(string)
-		// Start time calculated by encoder from clip duration
-		filter := fmt.Sprintf("afade=t=out:d=%.3f", dur)
+		filter := fmt.Sprintf("afade=t=out:%sd=%.3f", fadeOutStart(spec.Params, dur), dur)
 		if curve != "" && curve != "tri" {
 			filter += fmt.Sprintf(":curve=%s", curve)
 		}
@@ -348,7 +900,7 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 		case "peak":
 			return []Option{AudioFilter("dynaudnorm=p=1")}, nil
 		default: // "loudnorm" default
-			return []Option{AudioFilter("loudnorm")}, nil
+			return compileLoudnorm(spec.Params)
 		}
 
 	case "equalizer":
@@ -407,8 +959,228 @@ func compileFilter(spec FilterSpec, clipCrops crops.CropArray) ([]Option, error)
 	}
 }
 
-// atempoChain builds a chain of atempo filters for speed changes.
-// atempo only supports 0.5-2.0 range, so we chain multiple for larger values.
+// compileTimecode burns the running time into each frame with drawtext. The
+// clip is cut with an input seek, so frame timestamps start at 0; source
+// time ("source", the default) adds the start offset injected by
+// WithClipStart, while "clip" counts from the start of the clip. Formats:
+//
+//   - hms (default): HH:MM:SS.mmm
+//   - seconds: decimal seconds
+//   - frames: frame number at fps
+//   - timecode: HH:MM:SS:FF at fps, non-drop frame
+//
+// fps (default 30) should match the source frame rate for frames and
+// timecode, which count frames rather than reading timestamps.
+func compileTimecode(params map[string]any) ([]Option, error) {
+	offset := 0.0
+	switch source, _ := params["source"].(string); source {
+	case "", "source":
+		offset = paramFloat(params, clipStartParam, 0)
+	case "clip":
+	default:
+		return nil, fmt.Errorf("unknown source: %s", source)
+	}
+	fps := paramFloat(params, "fps", 30)
+	if fps < 1 || fps > 240 {
+		return nil, fmt.Errorf("fps must be between 1 and 240")
+	}
+
+	var text string
+	switch format, _ := params["format"].(string); format {
+	case "", "hms":
+		text = fmt.Sprintf(`text='%%{pts\:hms\:%.3f}'`, offset)
+	case "seconds":
+		text = fmt.Sprintf(`text='%%{pts\:flt\:%.3f}'`, offset)
+	case "frames":
+		text = fmt.Sprintf(`text='%%{eif\:n+%d\:d}'`, int64(math.Round(offset*fps)))
+	case "timecode":
+		// drawtext counts the timecode up one frame at a time from the
+		// start value.
+		frames := int64(math.Round(offset * fps))
+		nominal := int64(math.Round(fps))
+		secs := frames / nominal
+		start := fmt.Sprintf(`%02d\:%02d\:%02d\:%02d`, secs/3600%24, secs/60%60, secs%60, frames%nominal)
+		text = fmt.Sprintf("timecode='%s':rate=%s", start, strconv.FormatFloat(fps, 'f', -1, 64))
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+
+	fontSize := paramInt(params, "font_size", 24)
+	color := paramColor(params, "color", "white")
+	position, _ := params["position"].(string)
+	if position == "" {
+		position = "top-left"
+	}
+	x, y := textPosition(position)
+	return []Option{Filter(fmt.Sprintf("drawtext=%s:fontsize=%d:fontcolor=%s:box=1:boxcolor=black@0.5:boxborderw=6:x=%s:y=%s", text, fontSize, color, x, y))}, nil
+}
+
+// filterColorRe matches the colors accepted from filter params: a name or
+// #RRGGBB, which keeps params from injecting filter syntax.
+var filterColorRe = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|[a-zA-Z]+)$`)
+
+// compileChromaKey makes pixels close to the key "color" transparent and
+// composites the result over a "background": a solid "background_color"
+// (the default) or an uploaded image resolved by ResolveOverlayImages.
+// Encoded output has no alpha channel, so the key is never left
+// transparent. "similarity" (0.01-1) widens the range of keyed colors;
+// "blend" (0-1) softens the edge between keyed and kept pixels.
+func compileChromaKey(params map[string]any) ([]Option, error) {
+	key := paramColor(params, "color", "#00ff00")
+	if !filterColorRe.MatchString(key) {
+		return nil, fmt.Errorf("invalid key color")
+	}
+	similarity := paramFloat(params, "similarity", 0.1)
+	if similarity < 0.01 || similarity > 1 {
+		return nil, fmt.Errorf("similarity must be between 0.01 and 1")
+	}
+	blend := paramFloat(params, "blend", 0)
+	if blend < 0 || blend > 1 {
+		return nil, fmt.Errorf("blend must be between 0 and 1")
+	}
+	keyFilter := Filter(fmt.Sprintf("chromakey=%s:%.4f:%.4f", key, similarity, blend))
+	fps := paramFloat(params, sourceFPSParam, 0)
+
+	background, _ := params["background"].(string)
+	switch background {
+	case "", "color":
+		color := paramColor(params, "background_color", "black")
+		if !filterColorRe.MatchString(color) {
+			return nil, fmt.Errorf("invalid background color")
+		}
+		return []Option{keyFilter, ColorBackground(color, fps)}, nil
+	case "image":
+		path, _ := params[overlayImagePathParam].(string)
+		if path == "" {
+			if imageID, _ := params["image_id"].(string); imageID == "" {
+				return nil, fmt.Errorf("image_id is required for an image background")
+			}
+			return nil, fmt.Errorf("background image is not available")
+		}
+		return []Option{keyFilter, ImageBackground(path, fps)}, nil
+	default:
+		return nil, fmt.Errorf("background must be color or image")
+	}
+}
+
+// compileSubtitles burns in the caption file set by ResolveSubtitleFiles.
+// The file's cues are timed against the source video, while the filter
+// chain's clock starts at zero on the clip's first frame, so timestamps are
+// moved to source time for the subtitles filter and back again.
+func compileSubtitles(params map[string]any) ([]Option, error) {
+	path, _ := params[subtitlesPathParam].(string)
+	if path == "" {
+		return nil, fmt.Errorf("captions are not available")
+	}
+	filter := "subtitles=filename=" + escapeFilterPath(path)
+	start := paramFloat(params, clipStartParam, 0)
+	if start <= 0 {
+		return []Option{Filter(filter)}, nil
+	}
+	shift := fmt.Sprintf("%.3f/TB", start)
+	return []Option{Filter("setpts=PTS+" + shift), Filter(filter), Filter("setpts=PTS-" + shift)}, nil
+}
+
+// compileImageOverlay composites the filter's image, resolved beforehand by
+// ResolveOverlayImages, at a named position. "scale" is the image width as a
+// percentage of the video's width and "opacity" runs from 0 to 1.
+func compileImageOverlay(params map[string]any) ([]Option, error) {
+	path, _ := params[overlayImagePathParam].(string)
+	if path == "" {
+		if imageID, _ := params["image_id"].(string); imageID == "" {
+			return nil, fmt.Errorf("image_id is required")
+		}
+		return nil, fmt.Errorf("overlay image is not available")
+	}
+	scale := paramFloat(params, "scale", DefaultOverlayScale)
+	if scale < MinOverlayScale || scale > MaxOverlayScale {
+		return nil, fmt.Errorf("scale must be between %d and %d", MinOverlayScale, MaxOverlayScale)
+	}
+	opacity := paramFloat(params, "opacity", 1)
+	if opacity <= 0 || opacity > 1 {
+		return nil, fmt.Errorf("opacity must be greater than 0 and at most 1")
+	}
+	position, _ := params["position"].(string)
+	if position == "" {
+		position = "bottom-right"
+	}
+	x, y := anchorPosition(position, "W", "H", "w", "h")
+	return []Option{ImageOverlay(path, scale/100, opacity, x, y)}, nil
+}
+
+// kenBurnsSizes are the output resolutions offered for ken_burns. zoompan
+// cannot derive its output size from the input, so one must be chosen.
+var kenBurnsSizes = map[string]bool{
+	"1920x1080": true,
+	"1280x720":  true,
+	"1080x1920": true,
+	"1080x1080": true,
+	"3840x2160": true,
+}
+
+// compileKenBurns builds a slow zoom/pan using zoompan. The frame rate is
+// normalised first so zoompan (d=1, one output frame per input frame) keeps
+// the clip's timing, and progress is derived from the output frame number.
+// The animation runs over "duration" seconds, or the whole clip when 0.
+func compileKenBurns(params map[string]any) ([]Option, error) {
+	zoom := paramFloat(params, "zoom", 1.2)
+	if zoom < 1.0 || zoom > 3.0 {
+		return nil, fmt.Errorf("zoom must be between 1.0 and 3.0")
+	}
+	dur := paramFloat(params, "duration", 0)
+	if dur <= 0 {
+		dur = paramFloat(params, clipDurationParam, 0)
+	}
+	if dur <= 0 {
+		return nil, fmt.Errorf("clip duration is required for ken_burns")
+	}
+	fps := paramInt(params, "fps", 30)
+	if fps < 1 || fps > 120 {
+		return nil, fmt.Errorf("fps must be between 1 and 120")
+	}
+	size, _ := params["size"].(string)
+	if size == "" {
+		size = "1920x1080"
+	}
+	if !kenBurnsSizes[size] {
+		return nil, fmt.Errorf("unsupported size: %s", size)
+	}
+
+	// p runs 0→1 over the animation, then holds.
+	p := fmt.Sprintf("min(on/%.3f,1)", dur*float64(fps))
+	center := "iw/2-(iw/zoom/2)"
+	middle := "ih/2-(ih/zoom/2)"
+	z := fmt.Sprintf("%.4f", zoom)
+	x, y := center, middle
+
+	direction, _ := params["direction"].(string)
+	switch direction {
+	case "", "in":
+		z = fmt.Sprintf("1+%.4f*%s", zoom-1, p)
+	case "out":
+		z = fmt.Sprintf("%.4f-%.4f*%s", zoom, zoom-1, p)
+	case "left":
+		x = "(iw-iw/zoom)*(1-" + p + ")"
+	case "right":
+		x = "(iw-iw/zoom)*" + p
+	case "up":
+		y = "(ih-ih/zoom)*(1-" + p + ")"
+	case "down":
+		y = "(ih-ih/zoom)*" + p
+	default:
+		return nil, fmt.Errorf("unknown direction: %s", direction)
+	}
+
+	return []Option{
+		Filter(fmt.Sprintf("fps=%d", fps)),
+		Filter(fmt.Sprintf("zoompan=z='%s':x='%s':y='%s':d=1:s=%s:fps=%d", z, x, y, size, fps)),
+	}, nil
+}
+
+// atempoChain builds a chain of atempo filters changing the audio tempo by
+// factor. atempo only supports 0.5-2.0, so larger changes are split into
+// stages of 2.0 (or 0.5) plus one stage for the rest; the stages multiply
+// back to factor.
 func atempoChain(factor float64) []Option {
 	if factor <= 0 {
 		return nil
@@ -421,10 +1193,10 @@ func atempoChain(factor float64) []Option {
 	}
 	for remaining < 0.5 {
 		opts = append(opts, AudioFilter("atempo=0.5"))
-		remaining /= 0.5
+		remaining *= 2.0 // the 0.5 stage halved the tempo; the rest doubles
 	}
 	if remaining != 1.0 {
-		opts = append(opts, AudioFilter(fmt.Sprintf("atempo=%.4f", remaining)))
+		opts = append(opts, AudioFilter("atempo="+strconv.FormatFloat(remaining, 'f', -1, 64)))
 	}
 	return opts
 }
@@ -494,23 +1266,32 @@ func compileLUTPreset(preset string) ([]Option, error) {
 
 // textPosition maps a named position to ffmpeg drawtext x/y expressions.
 func textPosition(position string) (string, string) {
+	return anchorPosition(position, "w", "h", "text_w", "text_h")
+}
+
+// anchorPosition maps a named position to x/y expressions placing an item
+// of size iw×ih inside a frame of size w×h, 10 pixels from the edges. The
+// sizes are the filter's own expression variables.
+func anchorPosition(position, w, h, iw, ih string) (string, string) {
+	centerX, centerY := "("+w+"-"+iw+")/2", "("+h+"-"+ih+")/2"
+	right, bottom := w+"-"+iw+"-10", h+"-"+ih+"-10"
 	switch position {
 	case "top-left":
 		return "10", "10"
 	case "top-center":
-		return "(w-text_w)/2", "10"
+		return centerX, "10"
 	case "top-right":
-		return "w-text_w-10", "10"
+		return right, "10"
 	case "center":
-		return "(w-text_w)/2", "(h-text_h)/2"
+		return centerX, centerY
 	case "bottom-left":
-		return "10", "h-text_h-10"
+		return "10", bottom
 	case "bottom-center":
-		return "(w-text_w)/2", "h-text_h-10"
+		return centerX, bottom
 	case "bottom-right":
-		return "w-text_w-10", "h-text_h-10"
+		return right, bottom
 	default:
-		return "(w-text_w)/2", "h-text_h-10" // Default: bottom-center
+		return centerX, bottom // Default: bottom-center
 	}
 }
 
@@ -529,6 +1310,22 @@ func paramColor(params map[string]any, key string, def string) string {
 	return s
 }
 
+// fadeOutStart returns the "st=" option placing a fade-out of dur seconds so
+// that it ends offset seconds before the end of the clip, clamped to the
+// clip's start. Without an injected clip duration (see WithClipDuration) it
+// returns "" and the fade starts with the clip.
+func fadeOutStart(params map[string]any, dur float64) string {
+	clipDur := paramFloat(params, clipDurationParam, 0)
+	if clipDur <= 0 {
+		return ""
+	}
+	st := clipDur - dur - paramFloat(params, "offset", 0)
+	if st < 0 {
+		st = 0
+	}
+	return fmt.Sprintf("st=%.3f:", st)
+}
+
 // paramFloat extracts a float64 from a params map with a default value.
 func paramFloat(params map[string]any, key string, def float64) float64 {
 	v, ok := params[key]
@@ -553,6 +1350,20 @@ func paramFloat(params map[string]any, key string, def float64) float64 {
 	return def
 }
 
+// paramBool reads a boolean param, sent as a JSON bool or, from the filter
+// stack's selects, as "1" or "true".
+func paramBool(params map[string]any, key string) bool {
+	switch v := params[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "1" || strings.EqualFold(v, "true")
+	case float64:
+		return v == 1
+	}
+	return false
+}
+
 // paramInt extracts an int from a params map with a default value.
 func paramInt(params map[string]any, key string, def int) int {
 	v, ok := params[key]
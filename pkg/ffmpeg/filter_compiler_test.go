@@ -0,0 +1,539 @@
+package ffmpeg
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCompileKenBurns_UsesInjectedDuration(t *testing.T) {
+	specs := []FilterSpec{{Type: "ken_burns", Params: map[string]any{"zoom": 1.5, "direction": "in"}}}
+
+	if _, err := CompileFilters(specs, nil); err == nil {
+		t.Fatalf("expected error without a clip duration")
+	}
+
+	video, audio, err := CompileFilterStrings(WithClipDuration(specs, 10), nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	if len(audio) != 0 {
+		t.Fatalf("ken_burns must not add audio filters, got %q", audio)
+	}
+	if len(video) != 2 || video[0] != "fps=30" {
+		t.Fatalf("video filters = %q", video)
+	}
+	want := "zoompan=z='1+0.5000*min(on/300.000,1)':x='iw/2-(iw/zoom/2)':y='ih/2-(ih/zoom/2)':d=1:s=1920x1080:fps=30"
+	if video[1] != want {
+		t.Fatalf("zoompan = %q\nwant      %q", video[1], want)
+	}
+
+	// The original spec must not be mutated.
+	if _, ok := specs[0].Params[clipDurationParam]; ok {
+		t.Fatalf("WithClipDuration mutated the input specs")
+	}
+}
+
+func TestCompileKenBurns_ExplicitDurationAndPan(t *testing.T) {
+	specs := []FilterSpec{{Type: "ken_burns", Params: map[string]any{
+		"zoom": "1.2", "direction": "right", "duration": "4", "size": "1080x1920",
+	}}}
+	video, _, err := CompileFilterStrings(WithClipDuration(specs, 60), nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	if !strings.Contains(video[1], "x='(iw-iw/zoom)*min(on/120.000,1)'") || !strings.Contains(video[1], "s=1080x1920") {
+		t.Fatalf("unexpected zoompan: %q", video[1])
+	}
+}
+
+func TestCompileKenBurns_Rejects(t *testing.T) {
+	for _, params := range []map[string]any{
+		{"zoom": 0.5},
+		{"direction": "sideways"},
+		{"size": "123x45"},
+	} {
+		specs := WithClipDuration([]FilterSpec{{Type: "ken_burns", Params: params}}, 5)
+		if _, err := CompileFilters(specs, nil); err == nil {
+			t.Errorf("expected error for params %v", params)
+		}
+	}
+}
+
+func TestCompileFadeOut_StartFromClipDuration(t *testing.T) {
+	specs := []FilterSpec{
+		{Type: "fade_out", Params: map[string]any{"duration": 1.5}},
+		{Type: "audio_fade_out", Params: map[string]any{"duration": "2", "offset": "1"}},
+	}
+	video, audio, err := CompileFilterStrings(WithClipDuration(specs, 10), nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	if want := []string{"fade=t=out:st=8.500:d=1.500"}; !reflect.DeepEqual(video, want) {
+		t.Errorf("video filters = %q, want %q", video, want)
+	}
+	if want := []string{"afade=t=out:st=7.000:d=2.000"}; !reflect.DeepEqual(audio, want) {
+		t.Errorf("audio filters = %q, want %q", audio, want)
+	}
+
+	// A fade longer than the clip starts at the beginning.
+	long := []FilterSpec{{Type: "fade_out", Params: map[string]any{"duration": 5, "offset": 8}}}
+	video, _, err = CompileFilterStrings(WithClipDuration(long, 10), nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	if want := []string{"fade=t=out:st=0.000:d=5.000"}; !reflect.DeepEqual(video, want) {
+		t.Errorf("clamped video filters = %q, want %q", video, want)
+	}
+}
+
+func TestCompileFadeOut_AfterSpeed(t *testing.T) {
+	// At 2x a 10s clip plays for 5s, so a fade after the speed filter ends
+	// at 5s. A fade before it still sees the source timeline.
+	specs := []FilterSpec{
+		{Type: "fade_out", Params: map[string]any{"duration": 1}},
+		{Type: "speed", Params: map[string]any{"factor": 2}},
+		{Type: "audio_fade_out", Params: map[string]any{"duration": 1}},
+	}
+	video, audio, err := CompileFilterStrings(WithClipDuration(specs, 10), nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	if want := []string{"fade=t=out:st=9.000:d=1.000", "setpts=PTS/2"}; !reflect.DeepEqual(video, want) {
+		t.Errorf("video filters = %q, want %q", video, want)
+	}
+	if want := []string{"atempo=2", "afade=t=out:st=4.000:d=1.000"}; !reflect.DeepEqual(audio, want) {
+		t.Errorf("audio filters = %q, want %q", audio, want)
+	}
+}
+
+func TestCompileSpeed_AtempoChain(t *testing.T) {
+	for _, factor := range []float64{0.1, 0.25, 0.5, 1.5, 3.0, 4.0} {
+		specs := []FilterSpec{{Type: "speed", Params: map[string]any{"factor": factor}}}
+		video, audio, err := CompileFilterStrings(specs, nil)
+		if err != nil {
+			t.Fatalf("factor %g: %v", factor, err)
+		}
+		if want := "setpts=PTS/" + strconv.FormatFloat(factor, 'f', -1, 64); len(video) != 1 || video[0] != want {
+			t.Errorf("factor %g: video filters = %q, want [%q]", factor, video, want)
+		}
+		product := 1.0
+		for _, f := range audio {
+			v, err := strconv.ParseFloat(strings.TrimPrefix(f, "atempo="), 64)
+			if err != nil || v < 0.5 || v > 2.0 {
+				t.Fatalf("factor %g: bad atempo stage %q", factor, f)
+			}
+			product *= v
+		}
+		if math.Abs(product-factor) > 1e-9 {
+			t.Errorf("factor %g: atempo stages %q multiply to %g", factor, audio, product)
+		}
+	}
+
+	for _, factor := range []float64{0.05, 4.5, -1} {
+		specs := []FilterSpec{{Type: "speed", Params: map[string]any{"factor": factor}}}
+		if _, err := CompileFilters(specs, nil); err == nil {
+			t.Errorf("factor %g accepted, want error", factor)
+		}
+	}
+}
+
+func TestExportSpecLoopCount(t *testing.T) {
+	cases := map[int]int{-1: 1, 0: 1, 1: 1, 3: 3, MaxExportLoop: MaxExportLoop, MaxExportLoop + 5: MaxExportLoop}
+	for in, want := range cases {
+		if got := (ExportSpec{Loop: in}).LoopCount(); got != want {
+			t.Errorf("Loop=%d: LoopCount() = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestCompileFilters_TimelineRange(t *testing.T) {
+	specs := []FilterSpec{
+		{Type: "blur", Params: map[string]any{"radius": 8, "start": "2", "end": "4.5"}},
+		{Type: "grayscale", Params: map[string]any{"start": 3}},
+		{Type: "volume", Params: map[string]any{"gain": 0.5, "end": 1}},
+	}
+	video, audio, err := CompileFilterStrings(specs, nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	wantVideo := []string{
+		"boxblur=luma_radius=8:luma_power=2:enable='between(t,2.000,4.500)'",
+		"hue=s=0:enable='gte(t,3.000)'",
+	}
+	if strings.Join(video, ",") != strings.Join(wantVideo, ",") {
+		t.Fatalf("video = %q\nwant    %q", video, wantVideo)
+	}
+	if len(audio) != 1 || audio[0] != "volume=0.5000:enable='between(t,0.000,1.000)'" {
+		t.Fatalf("audio = %q", audio)
+	}
+}
+
+func TestCompileFilters_TimelineWholeClipUnchanged(t *testing.T) {
+	video, _, err := CompileFilterStrings([]FilterSpec{{Type: "sepia", Params: map[string]any{"start": "0", "end": "0"}}}, nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	if len(video) != 1 || strings.Contains(video[0], "enable") {
+		t.Fatalf("video = %q, want no enable option", video)
+	}
+}
+
+func TestCompileFilters_TimelineRejects(t *testing.T) {
+	for _, spec := range []FilterSpec{
+		{Type: "scale", Params: map[string]any{"width": 640, "start": 1}},
+		{Type: "crop_manual", Params: map[string]any{"width": 0.5, "end": 2}},
+		{Type: "brightness", Params: map[string]any{"value": 0.2, "start": 5, "end": 3}},
+		{Type: "brightness", Params: map[string]any{"value": 0.2, "start": -1}},
+	} {
+		if _, err := CompileFilters([]FilterSpec{spec}, nil); err == nil {
+			t.Errorf("expected error for %s %v", spec.Type, spec.Params)
+		}
+	}
+}
+
+func TestCompileStillFilter(t *testing.T) {
+	got, err := CompileStillFilter(FilterSpec{Type: "brightness", Params: map[string]any{"value": 0.2, "start": 1.0, "end": 2.0}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "eq=brightness=0.2000" {
+		t.Errorf("brightness = %q, want eq=brightness=0.2000 without enable", got)
+	}
+
+	if got, err := CompileStillFilter(FilterSpec{Type: "brightness", Params: map[string]any{"value": 0.0}}); err != nil || got != "" {
+		t.Errorf("no-op brightness = %q, %v; want empty chain", got, err)
+	}
+
+	for _, spec := range []FilterSpec{
+		{Type: "volume", Params: map[string]any{"gain": 1.0}},
+		{Type: "mute"},
+		{Type: "speed", Params: map[string]any{"factor": 2.0}},
+		{Type: "crop", Params: map[string]any{"crop_id": "x"}},
+		{Type: "nope"},
+	} {
+		if _, err := CompileStillFilter(spec); err == nil {
+			t.Errorf("CompileStillFilter(%s) accepted, want error", spec.Type)
+		}
+	}
+}
+
+func TestCompileTimecode(t *testing.T) {
+	specs := []FilterSpec{{Type: "timecode", Params: map[string]any{"position": "bottom-right", "font_size": 32}}}
+	video, _, err := CompileFilterStrings(WithClipStart(specs, 90.5), nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	if len(video) != 1 || !strings.HasPrefix(video[0], `drawtext=text='%{pts\:hms\:90.500}':fontsize=32:`) {
+		t.Fatalf("video = %q", video)
+	}
+	if _, ok := specs[0].Params[clipStartParam]; ok {
+		t.Fatalf("WithClipStart mutated the input specs")
+	}
+
+	cases := []struct {
+		params map[string]any
+		want   string
+	}{
+		{map[string]any{"source": "clip"}, `text='%{pts\:hms\:0.000}'`},
+		{map[string]any{"format": "frames", "fps": 25}, `text='%{eif\:n+2263\:d}'`},
+		{map[string]any{"format": "timecode", "fps": 25}, `timecode='00\:01\:30\:13':rate=25`},
+	}
+	for _, tc := range cases {
+		video, _, err := CompileFilterStrings(WithClipStart([]FilterSpec{{Type: "timecode", Params: tc.params}}, 90.5), nil)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.params, err)
+		}
+		if !strings.Contains(video[0], tc.want) {
+			t.Errorf("%v: got %q, want it to contain %q", tc.params, video[0], tc.want)
+		}
+	}
+
+	for _, params := range []map[string]any{
+		{"format": "bogus"},
+		{"source": "bogus"},
+		{"fps": 0},
+	} {
+		if _, err := CompileFilters([]FilterSpec{{Type: "timecode", Params: params}}, nil); err == nil {
+			t.Errorf("expected error for params %v", params)
+		}
+	}
+}
+
+func TestCompileSubtitles(t *testing.T) {
+	files := map[string]string{"": "/v/id.captions.en.vtt", "de": "/v/id.captions.de.vtt"}
+	find := func(lang string) (string, bool) {
+		p, ok := files[lang]
+		return p, ok
+	}
+
+	specs := []FilterSpec{{Type: "subtitles", Params: map[string]any{"lang": "DE"}}}
+	if _, err := CompileFilters(specs, nil); err == nil {
+		t.Fatalf("expected error without a resolved caption file")
+	}
+	resolved, err := ResolveSubtitleFiles(specs, find)
+	if err != nil {
+		t.Fatalf("ResolveSubtitleFiles: %v", err)
+	}
+	if _, ok := specs[0].Params[subtitlesPathParam]; ok {
+		t.Fatalf("ResolveSubtitleFiles mutated the input specs")
+	}
+	video, _, err := CompileFilterStrings(WithClipStart(resolved, 90.5), nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	want := []string{"setpts=PTS+90.500/TB", `subtitles=filename='/v/id.captions.de.vtt'`, "setpts=PTS-90.500/TB"}
+	if !reflect.DeepEqual(video, want) {
+		t.Fatalf("video = %q, want %q", video, want)
+	}
+
+	// A clip starting at the beginning of the video needs no shift.
+	resolved, err = ResolveSubtitleFiles([]FilterSpec{{Type: "subtitles"}}, find)
+	if err != nil {
+		t.Fatalf("ResolveSubtitleFiles: %v", err)
+	}
+	if video, _, err = CompileFilterStrings(resolved, nil); err != nil || len(video) != 1 {
+		t.Fatalf("video = %q, err = %v", video, err)
+	}
+
+	for _, lang := range []string{"fr", "../en", "en.vtt"} {
+		if _, err := ResolveSubtitleFiles([]FilterSpec{{Type: "subtitles", Params: map[string]any{"lang": lang}}}, find); err == nil {
+			t.Errorf("expected resolve error for lang %q", lang)
+		}
+	}
+	if _, err := CompileStillFilter(resolved[0]); err == nil {
+		t.Errorf("expected CompileStillFilter to reject subtitles")
+	}
+}
+
+func TestCompileImageOverlay(t *testing.T) {
+	dir := t.TempDir()
+	const imageID = "5f0c7f3e-6a43-4b5e-9d0a-2f9f7b1c2d3e"
+	path := filepath.Join(dir, imageID+".png")
+	if err := os.WriteFile(path, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	specs := []FilterSpec{{Type: "image_overlay", Params: map[string]any{
+		"image_id": imageID, "position": "top-right", "scale": 25, "opacity": 0.8,
+	}}}
+	if _, err := CompileFilters(specs, nil); err == nil {
+		t.Fatalf("expected error without a resolved image")
+	}
+	resolved, err := ResolveOverlayImages(specs, dir)
+	if err != nil {
+		t.Fatalf("ResolveOverlayImages: %v", err)
+	}
+	if _, ok := specs[0].Params[overlayImagePathParam]; ok {
+		t.Fatalf("ResolveOverlayImages mutated the input specs")
+	}
+	opts, err := CompileFilters(resolved, nil)
+	if err != nil {
+		t.Fatalf("CompileFilters: %v", err)
+	}
+	scratch := &Command{}
+	for _, opt := range opts {
+		opt.Apply(scratch)
+	}
+	want := imageOverlay{inputArgs: []string{"-loop", "1", "-i", path}, scale: 0.25, opacity: 0.8, x: "W-w-10", y: "10"}
+	if len(scratch.overlays) != 1 || !reflect.DeepEqual(scratch.overlays[0], want) {
+		t.Fatalf("overlays = %+v, want %+v", scratch.overlays, want)
+	}
+
+	// Stitching has no input for the image.
+	if _, _, err := CompileFilterStrings(resolved, nil); err == nil {
+		t.Errorf("expected CompileFilterStrings to reject image_overlay")
+	}
+	if _, err := CompileStillFilter(resolved[0]); err == nil {
+		t.Errorf("expected CompileStillFilter to reject image_overlay")
+	}
+
+	for _, params := range []map[string]any{
+		{},
+		{"image_id": "not-a-uuid"},
+		{"image_id": "0b7c2b8e-1d1f-4c59-8f43-64c3f2b0a111"},
+	} {
+		if _, err := ResolveOverlayImages([]FilterSpec{{Type: "image_overlay", Params: params}}, dir); err == nil {
+			t.Errorf("expected resolve error for params %v", params)
+		}
+	}
+	for _, params := range []map[string]any{
+		{"image_id": imageID, "scale": 0},
+		{"image_id": imageID, "scale": 101},
+		{"image_id": imageID, "opacity": 0},
+		{"image_id": imageID, "opacity": 1.5},
+	} {
+		specs, err := ResolveOverlayImages([]FilterSpec{{Type: "image_overlay", Params: params}}, dir)
+		if err != nil {
+			t.Fatalf("ResolveOverlayImages(%v): %v", params, err)
+		}
+		if _, err := CompileFilters(specs, nil); err == nil {
+			t.Errorf("expected error for params %v", params)
+		}
+	}
+}
+
+func TestCompileChromaKey(t *testing.T) {
+	specs := []FilterSpec{{Type: "chromakey", Params: map[string]any{
+		"color": "#00ff00", "similarity": 0.2, "blend": 0.05, "background_color": "#102030",
+	}}}
+	opts, err := CompileFilters(specs, nil)
+	if err != nil {
+		t.Fatalf("CompileFilters: %v", err)
+	}
+	cmd := NewCommand("in.mp4", "out.mp4", opts...)
+	args := strings.Join(cmd.Build(), " ")
+	for _, want := range []string{
+		"-f lavfi -i color=c=#102030",
+		"[0:v]chromakey=#00ff00:0.2000:0.0500[ovb0];",
+		"[1:v][ovb0]scale2ref=w=main_w:h=main_h[ovs0][ovr0];[ovs0][ovr0]overlay=shortest=1[ovo0];",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args missing %q:\n%s", want, args)
+		}
+	}
+
+	// An image background is resolved like an image overlay.
+	dir := t.TempDir()
+	const imageID = "5f0c7f3e-6a43-4b5e-9d0a-2f9f7b1c2d3e"
+	path := filepath.Join(dir, imageID+".png")
+	if err := os.WriteFile(path, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	specs = []FilterSpec{{Type: "chromakey", Params: map[string]any{"background": "image", "image_id": imageID}}}
+	if _, err := CompileFilters(specs, nil); err == nil {
+		t.Fatalf("expected error without a resolved background image")
+	}
+	resolved, err := ResolveOverlayImages(specs, dir)
+	if err != nil {
+		t.Fatalf("ResolveOverlayImages: %v", err)
+	}
+	opts, err = CompileFilters(resolved, nil)
+	if err != nil {
+		t.Fatalf("CompileFilters: %v", err)
+	}
+	if args := strings.Join(NewCommand("in.mp4", "out.mp4", opts...).Build(), " "); !strings.Contains(args, "-loop 1 -i "+path) {
+		t.Errorf("args missing background image input:\n%s", args)
+	}
+
+	// Backgrounds run at the source's frame rate rather than ffmpeg's 25.
+	opts, err = CompileFilters(WithSourceFPS(resolved, 29.97), nil)
+	if err != nil {
+		t.Fatalf("CompileFilters: %v", err)
+	}
+	if args := strings.Join(NewCommand("in.mp4", "out.mp4", opts...).Build(), " "); !strings.Contains(args, "-loop 1 -framerate 29.97 -i "+path) {
+		t.Errorf("args missing background frame rate:\n%s", args)
+	}
+	colorSpecs := WithSourceFPS([]FilterSpec{{Type: "chromakey", Params: map[string]any{"background_color": "black"}}}, 60)
+	if opts, err = CompileFilters(colorSpecs, nil); err != nil {
+		t.Fatalf("CompileFilters: %v", err)
+	}
+	if args := strings.Join(NewCommand("in.mp4", "out.mp4", opts...).Build(), " "); !strings.Contains(args, "-i color=c=black:r=60") {
+		t.Errorf("args missing background frame rate:\n%s", args)
+	}
+
+	for _, params := range []map[string]any{
+		{"similarity": 0},
+		{"similarity": 1.5},
+		{"blend": -0.1},
+		{"color": "green;drawbox"},
+		{"background_color": "0x000000:x"},
+		{"background": "video"},
+	} {
+		if _, err := CompileFilters([]FilterSpec{{Type: "chromakey", Params: params}}, nil); err == nil {
+			t.Errorf("expected error for params %v", params)
+		}
+	}
+}
+
+func TestCompileLUTFile(t *testing.T) {
+	dir := t.TempDir()
+	const lutID = "7d5b3c1a-2e4f-4a6b-8c9d-0e1f2a3b4c5d"
+	path, err := LUTFilePath(dir, lutID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(identityCube()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteLUTInfo(path, LUTInfo{Name: "Teal", Size: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	specs := []FilterSpec{
+		{Type: "grayscale"},
+		{Type: "lut_file", Params: map[string]any{"lut_id": lutID, "start": 1}},
+	}
+	if _, err := CompileFilters(specs, nil); err == nil {
+		t.Fatalf("expected error without a resolved LUT")
+	}
+	resolved, err := ResolveLUTFiles(specs, dir)
+	if err != nil {
+		t.Fatalf("ResolveLUTFiles: %v", err)
+	}
+	if _, ok := specs[1].Params[lutPathParam]; ok {
+		t.Fatalf("ResolveLUTFiles mutated the input specs")
+	}
+	video, _, err := CompileFilterStrings(resolved, nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	want := "lut3d=file=" + escapeFilterPath(path) + ":enable='gte(t,1.000)'"
+	if len(video) != 2 || video[1] != want {
+		t.Fatalf("video = %q, want second filter %q", video, want)
+	}
+	if names := LUTFileNames(resolved); len(names) != 1 || names[0] != "Teal" {
+		t.Fatalf("LUTFileNames = %q", names)
+	}
+
+	for _, params := range []map[string]any{
+		{},
+		{"lut_id": "nope"},
+		{"lut_id": "0b7c2b8e-1d1f-4c59-8f43-64c3f2b0a111"},
+	} {
+		if _, err := ResolveLUTFiles([]FilterSpec{{Type: "lut_file", Params: params}}, dir); err == nil {
+			t.Errorf("expected resolve error for params %v", params)
+		}
+	}
+}
+
+func TestCompileFiltersForSource_Silent(t *testing.T) {
+	specs := []FilterSpec{
+		{Type: "volume", Params: map[string]any{"gain": 2.0}},
+		{Type: "grayscale"},
+		{Type: "reverse"},
+		{Type: "normalize"},
+	}
+
+	opts, skipped, err := CompileFiltersForSource(specs, nil, false)
+	if err != nil {
+		t.Fatalf("CompileFiltersForSource: %v", err)
+	}
+	scratch := &Command{}
+	for _, opt := range opts {
+		opt.Apply(scratch)
+	}
+	if len(scratch.audioFilters) != 0 {
+		t.Errorf("audio filters = %q, want none for a silent source", scratch.audioFilters)
+	}
+	if got := strings.Join(scratch.filters, ","); got != "hue=s=0,reverse" {
+		t.Errorf("video filters = %q, want hue=s=0,reverse", got)
+	}
+	wantSkipped := []string{"filter[0] (volume)", "filter[3] (normalize)"}
+	if strings.Join(skipped, ";") != strings.Join(wantSkipped, ";") {
+		t.Errorf("skipped = %q, want %q", skipped, wantSkipped)
+	}
+
+	// With audio the same stack keeps every filter.
+	_, audio, err := CompileFilterStrings(specs, nil)
+	if err != nil {
+		t.Fatalf("CompileFilterStrings: %v", err)
+	}
+	if len(audio) != 3 {
+		t.Errorf("audio filters with audio = %q, want 3", audio)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -76,6 +78,63 @@ func ExtractThumbnail(ctx context.Context, input, output string, opts *Thumbnail
 	)
 }
 
+// ExtractCoverArt writes the first video stream of an audio file (normally
+// embedded cover art), or a still image, as a JPEG scaled to at most maxWidth
+// (default 640).
+func ExtractCoverArt(ctx context.Context, input, output string, maxWidth int) RunResult {
+	return ExtractAttachedPic(ctx, input, output, 0, maxWidth)
+}
+
+// ExtractAttachedPic writes video stream "0:v:<stream>" of input, normally an
+// attached picture listed in ProbeResult.AttachedPics, as a JPEG scaled to at
+// most maxWidth (default 640).
+func ExtractAttachedPic(ctx context.Context, input, output string, stream, maxWidth int) RunResult {
+	if maxWidth == 0 {
+		maxWidth = 640
+	}
+
+	return RunCapture(ctx, input, output,
+		MapStream(fmt.Sprintf("0:v:%d", stream)),
+		ScaleWidth(maxWidth),
+		Frames(1),
+		Quality(4),
+	)
+}
+
+// ExtractFrame extracts the frame at offset as a full-resolution JPEG.
+// Quality is the JPEG qscale (1-31, lower is better; default 2).
+func ExtractFrame(ctx context.Context, input, output string, offset time.Duration, quality int) RunResult {
+	if offset < 0 {
+		offset = 0
+	}
+	if quality == 0 {
+		quality = 2
+	}
+
+	return RunCapture(ctx, input, output,
+		Seek(offset),
+		Frames(1),
+		Quality(quality),
+	)
+}
+
+// ExtractFilteredFrame extracts the frame at offset with a video filter chain
+// applied, for filter previews. The output is scaled down to maxWidth after
+// filtering when wider (0 = no cap); the format follows output's extension.
+func ExtractFilteredFrame(ctx context.Context, input, output string, offset time.Duration, filter string, maxWidth int) RunResult {
+	if offset < 0 {
+		offset = 0
+	}
+	opts := []Option{Seek(offset), Frames(1), Quality(2)}
+	if filter != "" {
+		opts = append(opts, Filter(filter))
+	}
+	if maxWidth > 0 {
+		opts = append(opts, Filter(fmt.Sprintf("scale='min(%d,iw)':-2", maxWidth)))
+	}
+	return RunCapture(ctx, input, output, opts...)
+}
+
 // ExtractClip extracts a time range from a video.
 func ExtractClip(ctx context.Context, input, output string, start, end time.Duration, extraOpts ...Option) error {
 	opts := []Option{
@@ -137,6 +196,27 @@ func ApplyFaststart(ctx context.Context, path string) error {
 	return os.Rename(tmp, path)
 }
 
+// LoopFile writes output as count back-to-back copies of input. Streams are
+// copied rather than re-encoded, so audio and video repeat in lockstep and
+// the input should already be cut and encoded in the output format.
+func LoopFile(ctx context.Context, input, output string, count int) error {
+	if count < 1 {
+		count = 1
+	}
+	args := []string{
+		"-hide_banner", "-y",
+		"-stream_loop", strconv.Itoa(count - 1),
+		"-i", input,
+		"-map", "0",
+		"-c", "copy",
+	}
+	if strings.EqualFold(filepath.Ext(output), ".mp4") {
+		args = append(args, "-movflags", "+faststart")
+	}
+	args = append(args, output)
+	return run(ctx, args, nil)
+}
+
 // MuxVideoAudio combines the video stream(s) from videoInput with the first
 // audio stream from audioSource into a single MP4.  All streams are copied
 // (no re-encoding).  The output gets +faststart automatically because Run
@@ -155,6 +235,41 @@ func MuxVideoAudio(ctx context.Context, videoInput, audioSource, output string)
 	return run(ctx, args, nil)
 }
 
+// SelectAudioTrack remuxes input to output (stream copy) with the audio
+// track at index (0-based among audio streams) marked as the default. With
+// only set, the other audio tracks are dropped. Subtitle and data streams are
+// not carried over.
+func SelectAudioTrack(ctx context.Context, input, output string, index int, only bool) error {
+	return run(ctx, selectAudioTrackArgs(input, output, index, only), nil)
+}
+
+func selectAudioTrackArgs(input, output string, index int, only bool) []string {
+	track := strconv.Itoa(index)
+	args := []string{
+		"-hide_banner", "-y",
+		"-i", input,
+		"-map", "0:v?",
+	}
+	if only {
+		args = append(args,
+			"-map", "0:a:"+track,
+			"-c", "copy",
+			"-disposition:a:0", "default",
+		)
+	} else {
+		args = append(args,
+			"-map", "0:a",
+			"-c", "copy",
+			"-disposition:a", "0", // clear every track's default flag first
+			"-disposition:a:"+track, "default",
+		)
+	}
+	if strings.EqualFold(filepath.Ext(output), ".mp4") {
+		args = append(args, "-movflags", "+faststart")
+	}
+	return append(args, output)
+}
+
 // NeedsVideoTranscode returns true if the video codec is not natively playable
 // in modern browsers and should be re-encoded to H.264.
 func NeedsVideoTranscode(probe *ProbeResult) bool {
@@ -186,13 +301,44 @@ func StreamableAudioCodec(codec string) bool {
 	}
 }
 
-// NeedsAudioTranscode returns true if the (first) audio codec is not natively
-// playable in modern browsers and should be re-encoded to AAC.
-func NeedsAudioTranscode(probe *ProbeResult) bool {
+// AudioPolicy selects which audio tracks normalization re-encodes to AAC.
+type AudioPolicy int
+
+const (
+	// AudioKeepPlayable copies browser-playable audio and transcodes only
+	// codecs browsers can't play. This is the default.
+	AudioKeepPlayable AudioPolicy = iota
+	// AudioForceAAC transcodes every audio track that is not already AAC, so
+	// all normalized files share one audio codec.
+	AudioForceAAC
+)
+
+// NeedsAudioTranscode reports whether any audio track in probe must be
+// re-encoded to AAC under the policy.
+func (p AudioPolicy) NeedsAudioTranscode(probe *ProbeResult) bool {
 	if probe == nil {
 		return false
 	}
-	return !StreamableAudioCodec(probe.AudioCodec)
+	codecs := probe.AudioCodecs
+	if len(codecs) == 0 {
+		codecs = []string{probe.AudioCodec}
+	}
+	for _, codec := range codecs {
+		codec = strings.ToLower(strings.TrimSpace(codec))
+		if p == AudioForceAAC && codec != "aac" && codec != "" {
+			return true
+		}
+		if !StreamableAudioCodec(codec) {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsAudioTranscode returns true if any audio codec is not natively
+// playable in modern browsers and should be re-encoded to AAC.
+func NeedsAudioTranscode(probe *ProbeResult) bool {
+	return AudioKeepPlayable.NeedsAudioTranscode(probe)
 }
 
 // NeedsTranscode returns true if the video and/or audio codecs are not natively
@@ -228,23 +374,40 @@ func TranscodeToMP4WithProgress(ctx context.Context, input, output string, progr
 }
 
 // normalizeOptions builds the ffmpeg options that turn an arbitrary input into a
-// single browser-playable MP4: keep the first video stream and all audio
-// streams, drop subtitles/data, copy streams that browsers can already play, and
-// only re-encode what they can't.
+// single browser-playable MP4: keep one video stream (videoMap, normally
+// primaryVideoMap), all audio streams and text subtitles, drop data, copy
+// streams that browsers can already play, and only re-encode what they can't.
 //
 //   - Video: copied when the codec is browser-playable (H.264/HEVC/VP8/VP9/AV1);
 //     re-encoded to H.264 only for legacy codecs browsers can't play.
 //   - Audio: copied when streamable (AAC/MP3/Opus/…); transcoded to AAC otherwise
-//     (Dolby Digital/eac3, ac3, DTS, TrueHD, PCM, …). Channel layout is preserved.
-//   - Subtitles/data: dropped (captions are written as sidecar .vtt at ingest).
+//     (Dolby Digital/eac3, ac3, DTS, TrueHD, PCM, …). With AudioForceAAC, any
+//     non-AAC audio is transcoded. Channel layout is preserved.
+//   - Subtitles: text tracks (such as those yt-dlp embeds) are kept as
+//     mov_text; image-based ones (PGS, DVB) can't be and are dropped. Captions
+//     are written as sidecar .vtt at ingest either way.
+//   - Data: dropped.
 //   - HEVC kept as-is is tagged hvc1 so Safari will decode it.
 //
 // The .mp4 output gets +faststart automatically via the Command builder.
-func normalizeOptions(probe *ProbeResult) []Option {
+func normalizeOptions(probe *ProbeResult, videoMap string, audio AudioPolicy) []Option {
 	opts := []Option{
-		MapStream("0:v:0"),
+		MapStream(videoMap),
 		MapStream("0:a?"),
-		ExtraArgs("-sn", "-dn", "-map_metadata", "0"),
+	}
+	keptSubs := false
+	if probe != nil {
+		for i, codec := range probe.SubtitleCodecs {
+			if textSubtitleCodec(codec) {
+				opts = append(opts, MapStream(fmt.Sprintf("0:s:%d", i)))
+				keptSubs = true
+			}
+		}
+	}
+	if keptSubs {
+		opts = append(opts, ExtraArgs("-c:s", "mov_text", "-dn", "-map_metadata", "0"))
+	} else {
+		opts = append(opts, ExtraArgs("-sn", "-dn", "-map_metadata", "0"))
 	}
 
 	if NeedsVideoTranscode(probe) {
@@ -259,7 +422,7 @@ func normalizeOptions(probe *ProbeResult) []Option {
 		}
 	}
 
-	if NeedsAudioTranscode(probe) {
+	if audio.NeedsAudioTranscode(probe) {
 		opts = append(opts, AudioCodec("aac"), AudioBitrate("192k"))
 	} else {
 		opts = append(opts, CopyAudio)
@@ -268,39 +431,70 @@ func normalizeOptions(probe *ProbeResult) []Option {
 	return opts
 }
 
+// textSubtitleCodec reports whether a subtitle codec is text-based, so it can
+// be converted to the MP4 mov_text format.
+func textSubtitleCodec(codec string) bool {
+	switch strings.ToLower(strings.TrimSpace(codec)) {
+	case "mov_text", "subrip", "srt", "ass", "ssa", "webvtt", "text":
+		return true
+	default:
+		return false
+	}
+}
+
+// primaryVideoMap returns the stream specifier of the first real video stream,
+// skipping attached pictures that come before it.
+func primaryVideoMap(probe *ProbeResult) string {
+	if probe == nil || len(probe.Angles) == 0 {
+		return "0:v:0"
+	}
+	return fmt.Sprintf("0:v:%d", probe.Angles[0].Index)
+}
+
 // NormalizeToStreamableMP4 rewrites input into a single browser-playable MP4 at
 // output, copying anything browsers already support and only re-encoding what
 // they don't (legacy video → H.264, non-streamable audio → AAC). See
 // normalizeOptions for the exact stream handling.
-func NormalizeToStreamableMP4(ctx context.Context, input, output string) error {
+func NormalizeToStreamableMP4(ctx context.Context, input, output string, audio AudioPolicy) error {
 	probe, err := Probe(ctx, input)
 	if err != nil {
 		return fmt.Errorf("normalize: probe %s: %w", input, err)
 	}
-	return Run(ctx, input, output, normalizeOptions(probe)...)
+	return Run(ctx, input, output, normalizeOptions(probe, primaryVideoMap(probe), audio)...)
 }
 
 // NormalizeToStreamableMP4WithProgress is like NormalizeToStreamableMP4 but
 // reports progress.
-func NormalizeToStreamableMP4WithProgress(ctx context.Context, input, output string, progress chan<- Progress) error {
+func NormalizeToStreamableMP4WithProgress(ctx context.Context, input, output string, audio AudioPolicy, progress chan<- Progress) error {
 	probe, err := Probe(ctx, input)
 	if err != nil {
 		return fmt.Errorf("normalize: probe %s: %w", input, err)
 	}
-	return RunWithProgress(ctx, input, output, progress, normalizeOptions(probe)...)
+	return RunWithProgress(ctx, input, output, progress, normalizeOptions(probe, primaryVideoMap(probe), audio)...)
+}
+
+// ExtractAngleToStreamableMP4 writes one video angle of a multi-angle input,
+// with all of the input's audio, as a browser-playable MP4 at output. The
+// copy-or-re-encode decision follows NormalizeToStreamableMP4 but is made on
+// the angle's own codec.
+func ExtractAngleToStreamableMP4(ctx context.Context, input, output string, probe *ProbeResult, angle VideoAngle, audio AudioPolicy) error {
+	p := *probe
+	p.VideoCodec = angle.Codec
+	return Run(ctx, input, output, normalizeOptions(&p, "0:v:"+strconv.Itoa(angle.Index), audio)...)
 }
 
 // IsStreamableMP4 reports whether a probed file is already a browser-playable
 // MP4 that needs no normalization — an MP4 container whose video and audio
-// codecs are both natively playable. Such files only need a faststart remux.
-func IsStreamableMP4(probe *ProbeResult) bool {
+// codecs are both natively playable (and whose audio the policy would not
+// transcode). Such files only need a faststart remux.
+func IsStreamableMP4(probe *ProbeResult, audio AudioPolicy) bool {
 	if probe == nil {
 		return false
 	}
 	if !strings.Contains(strings.ToLower(probe.FormatName), "mp4") {
 		return false
 	}
-	return !NeedsVideoTranscode(probe) && !NeedsAudioTranscode(probe)
+	return !NeedsVideoTranscode(probe) && !audio.NeedsAudioTranscode(probe)
 }
 
 // WaveformOptions configures waveform peak generation.
@@ -344,7 +538,7 @@ func GenerateWaveformPeaks(ctx context.Context, input, output string, opts *Wave
 		"pipe:1", // Output to stdout
 	}
 
-	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("ffmpeg: failed to create stdout pipe: %w", err)
@@ -429,3 +623,86 @@ func GenerateWaveformPeaks(ctx context.Context, input, output string, opts *Wave
 		Logs:      stderrBuf.String(),
 	}, nil
 }
+
+// SampleGrayFrames decodes count frames spread evenly across a video of the
+// given duration, each downscaled to size×size 8-bit grayscale (row-major).
+// Used for perceptual hashing; fewer frames may be returned for very short
+// or damaged inputs.
+func SampleGrayFrames(ctx context.Context, input string, durationSeconds float64, count, size int) ([][]byte, error) {
+	if count <= 0 || size <= 0 {
+		return nil, fmt.Errorf("ffmpeg: invalid frame sample count=%d size=%d", count, size)
+	}
+	if durationSeconds <= 0 {
+		return nil, fmt.Errorf("ffmpeg: duration required for frame sampling")
+	}
+
+	// Sample at the midpoint of each of count equal segments, skipping the
+	// very first frame (often black) and the tail.
+	rate := float64(count) / durationSeconds
+	args := []string{
+		"-hide_banner", "-nostdin",
+		"-ss", fmt.Sprintf("%.3f", durationSeconds/float64(count)/2),
+		"-i", input,
+		"-an", "-sn", "-dn",
+		"-vf", fmt.Sprintf("fps=%.6f,scale=%d:%d:flags=area,format=gray", rate, size, size),
+		"-frames:v", fmt.Sprintf("%d", count),
+		"-f", "rawvideo",
+		"pipe:1",
+	}
+
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &Error{Args: args, Stderr: stderr.String(), Err: err}
+	}
+
+	frameLen := size * size
+	raw := stdout.Bytes()
+	frames := make([][]byte, 0, len(raw)/frameLen)
+	for off := 0; off+frameLen <= len(raw); off += frameLen {
+		frames = append(frames, raw[off:off+frameLen])
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("ffmpeg: no frames decoded from %s", input)
+	}
+	return frames, nil
+}
+
+// SampleGrayFrameAt decodes the single frame at offset, downscaled to
+// size×size 8-bit grayscale (row-major). The seek happens before the input so
+// only the frames around offset are decoded.
+func SampleGrayFrameAt(ctx context.Context, input string, offset time.Duration, size int) ([]byte, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("ffmpeg: invalid frame sample size=%d", size)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	args := []string{
+		"-hide_banner", "-nostdin",
+		"-ss", formatDuration(offset),
+		"-i", input,
+		"-an", "-sn", "-dn",
+		"-vf", fmt.Sprintf("scale=%d:%d:flags=area,format=gray", size, size),
+		"-frames:v", "1",
+		"-f", "rawvideo",
+		"pipe:1",
+	}
+
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &Error{Args: args, Stderr: stderr.String(), Err: err}
+	}
+
+	raw := stdout.Bytes()
+	if len(raw) < size*size {
+		return nil, fmt.Errorf("ffmpeg: no frame decoded from %s at %s", input, offset)
+	}
+	return raw[:size*size], nil
+}
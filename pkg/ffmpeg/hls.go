@@ -0,0 +1,61 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+)
+
+// File names SegmentHLS writes inside its output directory.
+const (
+	HLSIndex       = "index.m3u8"
+	HLSInitSegment = "init.mp4"
+)
+
+// SegmentHLS remuxes input (stream copy, no re-encode) into a VOD HLS
+// rendition in dir: HLSIndex, the fMP4 init segment HLSInitSegment and
+// seg_NNNNN.m4s media segments of about segmentSeconds each, cut at
+// keyframes. fMP4 carries every codec ingest keeps (H.264, HEVC, VP9, AV1),
+// which MPEG-TS would not. Only the first video and audio streams are kept;
+// cover art is skipped.
+//
+// A non-empty keyInfo is the path of an ffmpeg key info file (see
+// WriteHLSKeyInfo); the segments are then AES-128 encrypted and the playlist
+// carries an EXT-X-KEY tag.
+func SegmentHLS(ctx context.Context, input, dir string, segmentSeconds int, keyInfo string) RunResult {
+	return runCapture(ctx, segmentHLSArgs(input, dir, segmentSeconds, keyInfo))
+}
+
+// WriteHLSKeyInfo writes the key info file SegmentHLS reads to path: the URI
+// players fetch the key from, the key file ffmpeg reads, and the IV as hex.
+// The IV is written to the playlist, so segments stay decryptable when the
+// playlist is spliced into another.
+func WriteHLSKeyInfo(path, keyURI, keyFile string, iv []byte) error {
+	return os.WriteFile(path, []byte(keyURI+"\n"+keyFile+"\n"+hex.EncodeToString(iv)+"\n"), 0o600)
+}
+
+func segmentHLSArgs(input, dir string, segmentSeconds int, keyInfo string) []string {
+	if segmentSeconds <= 0 {
+		segmentSeconds = 6
+	}
+	args := []string{
+		"-hide_banner", "-nostdin", "-y",
+		"-i", input,
+		// V (capital) skips attached pictures such as embedded cover art.
+		"-map", "0:V:0?",
+		"-map", "0:a:0?",
+		"-c", "copy",
+		"-f", "hls",
+		"-hls_time", itoa(segmentSeconds),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_type", "fmp4",
+		// Relative to the playlist, so the playlist references it by name.
+		"-hls_fmp4_init_filename", HLSInitSegment,
+		"-hls_segment_filename", filepath.Join(dir, "seg_%05d.m4s"),
+	}
+	if keyInfo != "" {
+		args = append(args, "-hls_key_info_file", keyInfo)
+	}
+	return append(args, filepath.Join(dir, HLSIndex))
+}
@@ -0,0 +1,43 @@
+package ffmpeg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSegmentHLSArgs(t *testing.T) {
+	args := strings.Join(segmentHLSArgs("in.mp4", "/out", 0, ""), " ")
+	for _, want := range []string{
+		"-i in.mp4 -map 0:V:0? -map 0:a:0? -c copy -f hls -hls_time 6 ",
+		"-hls_segment_type fmp4 -hls_fmp4_init_filename init.mp4",
+		"-hls_segment_filename /out/seg_%05d.m4s /out/index.m3u8",
+	} {
+		if !strings.Contains(args, want) {
+			t.Errorf("args missing %q:\n%s", want, args)
+		}
+	}
+	if strings.Contains(args, "-hls_key_info_file") {
+		t.Errorf("clear rendition encrypted:\n%s", args)
+	}
+
+	enc := strings.Join(segmentHLSArgs("in.mp4", "/out", 6, "/tmp/key.info"), " ")
+	if !strings.HasSuffix(enc, "-hls_key_info_file /tmp/key.info /out/index.m3u8") {
+		t.Errorf("encrypted args = %s", enc)
+	}
+}
+
+func TestWriteHLSKeyInfo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.info")
+	if err := WriteHLSKeyInfo(path, "/api/videos/v/hls/key", "/tmp/k.bin", []byte{0, 1, 0xab}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/api/videos/v/hls/key\n/tmp/k.bin\n0001ab\n"; string(got) != want {
+		t.Errorf("key info = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// HardwareEncoder selects a GPU H.264 encoder for MP4 exports. Decoding and
+// filters stay on the CPU either way, so every filter the compiler emits
+// keeps working; only the final encode moves to the GPU.
+type HardwareEncoder string
+
+const (
+	HardwareNone  HardwareEncoder = ""      // libx264 (software)
+	HardwareNVENC HardwareEncoder = "nvenc" // NVIDIA h264_nvenc
+	HardwareVAAPI HardwareEncoder = "vaapi" // Intel/AMD h264_vaapi
+	HardwareQSV   HardwareEncoder = "qsv"   // Intel Quick Sync h264_qsv
+)
+
+// VAAPIDevice is the DRM render node VAAPI encodes run on.
+var VAAPIDevice = "/dev/dri/renderD128"
+
+// vaapiUpload converts CPU frames to the surface format h264_vaapi takes and
+// uploads them. It runs after every other video filter.
+const vaapiUpload = "format=nv12,hwupload"
+
+// ParseHardwareEncoder parses an ENCODER_HWACCEL value. Empty, "none", "off"
+// and "software" select HardwareNone.
+func ParseHardwareEncoder(s string) (HardwareEncoder, error) {
+	switch v := strings.ToLower(strings.TrimSpace(s)); v {
+	case "", "none", "off", "software":
+		return HardwareNone, nil
+	case string(HardwareNVENC), string(HardwareVAAPI), string(HardwareQSV):
+		return HardwareEncoder(v), nil
+	default:
+		return HardwareNone, fmt.Errorf("unknown hardware encoder %q (want nvenc, vaapi, qsv or none)", s)
+	}
+}
+
+// HardwareEncoderFromEnv reads ENCODER_HWACCEL, and ENCODER_VAAPI_DEVICE for
+// VAAPI.
+func HardwareEncoderFromEnv() (HardwareEncoder, error) {
+	if dev := strings.TrimSpace(os.Getenv("ENCODER_VAAPI_DEVICE")); dev != "" {
+		VAAPIDevice = dev
+	}
+	return ParseHardwareEncoder(os.Getenv("ENCODER_HWACCEL"))
+}
+
+// Codec returns the ffmpeg encoder name, or "libx264" for HardwareNone.
+func (h HardwareEncoder) Codec() string {
+	switch h {
+	case HardwareNVENC:
+		return "h264_nvenc"
+	case HardwareVAAPI:
+		return "h264_vaapi"
+	case HardwareQSV:
+		return "h264_qsv"
+	default:
+		return "libx264"
+	}
+}
+
+// PresetExportHardware returns options for an H.264 export on hw, the GPU
+// counterpart of PresetExportHQ. Each encoder gets its constant-quality mode
+// in place of -crf, at about the same quality: 21, or 17 for "max".
+func PresetExportHardware(hw HardwareEncoder, quality string) []Option {
+	q, maxQ := "21", quality == "max"
+	if maxQ {
+		q = "17"
+	}
+	switch hw {
+	case HardwareNVENC:
+		preset := "p5"
+		if maxQ {
+			preset = "p7"
+		}
+		return []Option{
+			VideoCodec(hw.Codec()),
+			Preset(preset),
+			ExtraArgs("-tune", "hq", "-rc", "vbr", "-cq", q, "-b:v", "0"),
+			PixelFormat("yuv420p"),
+		}
+	case HardwareQSV:
+		preset := "medium"
+		if maxQ {
+			preset = "veryslow"
+		}
+		return []Option{
+			VideoCodec(hw.Codec()),
+			Preset(preset),
+			ExtraArgs("-global_quality", q),
+			PixelFormat("nv12"),
+		}
+	case HardwareVAAPI:
+		return []Option{
+			OptionFunc(func(cmd *Command) {
+				cmd.preInput = append(cmd.preInput, "-vaapi_device", VAAPIDevice)
+				cmd.hwUpload = vaapiUpload
+			}),
+			VideoCodec(hw.Codec()),
+			ExtraArgs("-rc_mode", "CQP", "-qp", q),
+		}
+	default:
+		return PresetExportHQ()
+	}
+}
+
+// ProbeHardwareEncoder encodes half a second of test pattern with hw's
+// export preset, returning the error when the encoder, driver or device is
+// not usable. Services call it at startup and fall back to software on error.
+func ProbeHardwareEncoder(ctx context.Context, hw HardwareEncoder) error {
+	if hw == HardwareNone {
+		return nil
+	}
+	opts := append([]Option{
+		OptionFunc(func(cmd *Command) {
+			cmd.preInput = append(cmd.preInput, "-f", "lavfi")
+		}),
+		ExtraArgs("-f", "null"),
+	}, PresetExportHardware(hw, "high")...)
+	res := RunCapture(ctx, "testsrc2=size=256x144:rate=30:duration=0.5", "-", opts...)
+	if res.Err != nil {
+		return fmt.Errorf("%s probe encode: %w", hw.Codec(), res.Err)
+	}
+	return nil
+}
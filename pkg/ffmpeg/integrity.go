@@ -0,0 +1,81 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrCorrupt is returned (wrapped) by VerifyIntegrity when ffmpeg reports a
+// decode error, which usually means a truncated or damaged download.
+var ErrCorrupt = errors.New("media is corrupt or truncated")
+
+// IntegrityMode selects how much of a file VerifyIntegrity decodes.
+type IntegrityMode string
+
+const (
+	// IntegrityTail decodes only the last TailSeconds, which catches the
+	// common truncated-download case at a fraction of the cost.
+	IntegrityTail IntegrityMode = "tail"
+	// IntegrityFull decodes every stream end to end.
+	IntegrityFull IntegrityMode = "full"
+)
+
+// IntegrityOptions configures VerifyIntegrity.
+type IntegrityOptions struct {
+	Mode        IntegrityMode // Default: IntegrityTail
+	TailSeconds float64       // Seconds decoded in tail mode (default: 10)
+}
+
+// VerifyIntegrity decodes input to a null muxer with -xerror, so the first
+// decode error aborts the run. A decode failure is reported as ErrCorrupt;
+// other failures (ffmpeg missing, context cancelled) are returned as-is.
+func VerifyIntegrity(ctx context.Context, input string, opts *IntegrityOptions) error {
+	args := integrityArgs(input, opts)
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return &Error{Args: args, Stderr: stderr.String(), Err: err}
+	}
+	detail := strings.TrimSpace(stderr.String())
+	if detail == "" {
+		detail = err.Error()
+	}
+	return fmt.Errorf("%w: %s", ErrCorrupt, firstLines(detail, 3))
+}
+
+func integrityArgs(input string, opts *IntegrityOptions) []string {
+	if opts == nil {
+		opts = &IntegrityOptions{}
+	}
+	args := []string{"-hide_banner", "-nostdin", "-v", "error", "-xerror"}
+	if opts.Mode != IntegrityFull {
+		tail := opts.TailSeconds
+		if tail <= 0 {
+			tail = 10
+		}
+		args = append(args, "-sseof", fmt.Sprintf("-%.3f", tail))
+	}
+	return append(args, "-i", input, "-map", "0:v?", "-map", "0:a?", "-f", "null", "-")
+}
+
+func firstLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return strings.Join(lines, "; ")
+}
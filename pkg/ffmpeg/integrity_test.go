@@ -0,0 +1,26 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIntegrityArgs(t *testing.T) {
+	tail := strings.Join(integrityArgs("in.mp4", nil), " ")
+	if !strings.Contains(tail, "-xerror") || !strings.Contains(tail, "-sseof -10.000 -i in.mp4") {
+		t.Fatalf("default tail args = %q", tail)
+	}
+
+	short := strings.Join(integrityArgs("in.mp4", &IntegrityOptions{TailSeconds: 2.5}), " ")
+	if !strings.Contains(short, "-sseof -2.500") {
+		t.Fatalf("tail seconds not applied: %q", short)
+	}
+
+	full := strings.Join(integrityArgs("in.mp4", &IntegrityOptions{Mode: IntegrityFull}), " ")
+	if strings.Contains(full, "-sseof") {
+		t.Fatalf("full mode must not seek: %q", full)
+	}
+	if !strings.HasSuffix(full, "-f null -") {
+		t.Fatalf("expected null muxer output: %q", full)
+	}
+}
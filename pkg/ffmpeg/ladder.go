@@ -0,0 +1,119 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rendition ladder limits.
+const (
+	MaxLadderRungs     = 4
+	MinRenditionHeight = 144
+	MaxRenditionHeight = 2160
+)
+
+// Rendition is one output of a rendition ladder: the video scaled to Height
+// (never upscaled) and written to Output.
+type Rendition struct {
+	Height int
+	Output string
+}
+
+// NormalizeLadder validates a list of target heights and returns it sorted
+// tallest first. Errors are user-facing.
+func NormalizeLadder(heights []int) ([]int, error) {
+	if len(heights) < 2 || len(heights) > MaxLadderRungs {
+		return nil, fmt.Errorf("ladder must list between 2 and %d heights", MaxLadderRungs)
+	}
+	out := append([]int(nil), heights...)
+	sort.Sort(sort.Reverse(sort.IntSlice(out)))
+	for i, h := range out {
+		if h < MinRenditionHeight || h > MaxRenditionHeight || h%2 != 0 {
+			return nil, fmt.Errorf("ladder heights must be even and between %d and %d", MinRenditionHeight, MaxRenditionHeight)
+		}
+		if i > 0 && out[i-1] == h {
+			return nil, fmt.Errorf("ladder heights must be distinct")
+		}
+	}
+	return out, nil
+}
+
+// RenditionScaleFilter scales to height, keeping the aspect ratio with an
+// even width. Sources shorter than height keep their own (even) height.
+func RenditionScaleFilter(height int) string {
+	return "scale=-2:trunc(min(" + strconv.Itoa(height) + "\\,ih)/2)*2"
+}
+
+// RenditionLadder writes one output per rendition from a single decode: the
+// filtered video is split and each branch scaled to its rung's height. Codec
+// and other output options apply to every rendition; the command's own
+// output path is not used.
+func RenditionLadder(rungs []Rendition) Option {
+	return OptionFunc(func(cmd *Command) {
+		cmd.ladder = rungs
+	})
+}
+
+// videoFilterChain is the main video's filter chain, or "null" when there
+// are no filters, for use inside a filter graph.
+func (c *Command) videoFilterChain() string {
+	return filterChain(c.filters)
+}
+
+// filterChain joins filters into a chain, or "null" when there are none.
+func filterChain(filters []string) string {
+	if len(filters) == 0 {
+		return "null"
+	}
+	return strings.Join(filters, ",")
+}
+
+// ladderFilterGraph builds the -filter_complex graph for a ladder command.
+// Rung i is labelled [r<i>].
+func (c *Command) ladderFilterGraph() string {
+	var g strings.Builder
+	if c.pip != nil {
+		g.WriteString(c.pipFilterGraph())
+	} else {
+		g.WriteString(c.mainVideoGraph("vout"))
+	}
+	g.WriteString(";[vout]split=" + strconv.Itoa(len(c.ladder)))
+	for i := range c.ladder {
+		g.WriteString("[s" + strconv.Itoa(i) + "]")
+	}
+	for i, r := range c.ladder {
+		n := strconv.Itoa(i)
+		scale := RenditionScaleFilter(r.Height)
+		if c.hwUpload != "" {
+			scale += "," + c.hwUpload
+		}
+		g.WriteString(";[s" + n + "]" + scale + "[r" + n + "]")
+	}
+	return g.String()
+}
+
+// ladderOutputArgs returns the filter graph and per-rendition output args.
+// Output options in ffmpeg apply to the next output file only, so they are
+// repeated for each rendition.
+func (c *Command) ladderOutputArgs() []string {
+	args := []string{"-filter_complex", c.ladderFilterGraph()}
+	for i, r := range c.ladder {
+		args = append(args, "-map", "[r"+strconv.Itoa(i)+"]", "-map", "0:a?")
+		args = append(args, c.postInput...)
+		if c.pip != nil {
+			args = append(args, "-shortest")
+		}
+		if len(c.audioFilters) > 0 {
+			args = append(args, "-af", strings.Join(c.audioFilters, ","))
+		}
+		ext := strings.ToLower(filepath.Ext(r.Output))
+		if ext == ".mp4" || ext == ".m4a" || ext == ".mov" {
+			args = append(args, "-movflags", "+faststart")
+		}
+		args = append(args, r.Output)
+	}
+	return args
+}
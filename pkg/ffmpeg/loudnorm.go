@@ -0,0 +1,168 @@
+package ffmpeg
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Loudness targets of the normalize filter's loudnorm mode, in LUFS (I), dBTP
+// (TP) and LU (LRA). The defaults are loudnorm's own; the bounds are the
+// ranges it accepts.
+const (
+	DefaultLoudnormI   = -24.0
+	DefaultLoudnormTP  = -2.0
+	DefaultLoudnormLRA = 7.0
+	MinLoudnormI       = -70.0
+	MaxLoudnormI       = -5.0
+	MinLoudnormTP      = -9.0
+	MaxLoudnormTP      = 0.0
+	MinLoudnormLRA     = 1.0
+	MaxLoudnormLRA     = 20.0
+)
+
+// LoudnormStats are the input measurements loudnorm prints at the end of a
+// measurement pass, fed back into the second pass of a two-pass normalize.
+type LoudnormStats struct {
+	InputI       float64
+	InputTP      float64
+	InputLRA     float64
+	InputThresh  float64
+	TargetOffset float64
+}
+
+// loudnormMeasureParam and loudnormStatsParam are the params keys
+// WithLoudnormMeasurement and WithLoudnormStats inject into the normalize
+// filter being measured.
+const (
+	loudnormMeasureParam = "_loudnorm_measure"
+	loudnormStatsParam   = "_loudnorm_stats"
+)
+
+// twoPassLoudnorm returns the index of the first normalize filter in specs
+// that asks for a measured two-pass loudnorm, or -1.
+func twoPassLoudnorm(specs []FilterSpec) int {
+	for i, spec := range specs {
+		mode, _ := spec.Params["mode"].(string)
+		if spec.Type == "normalize" && (mode == "" || mode == "loudnorm") && paramBool(spec.Params, "two_pass") {
+			return i
+		}
+	}
+	return -1
+}
+
+// NeedsLoudnormMeasurement reports whether specs hold a normalize filter
+// asking for two-pass loudnorm. Only the first such filter is measured; any
+// later one normalizes in a single pass.
+func NeedsLoudnormMeasurement(specs []FilterSpec) bool {
+	return twoPassLoudnorm(specs) >= 0
+}
+
+// WithLoudnormMeasurement returns a copy of specs for the measurement pass:
+// the two-pass normalize filter prints its input statistics as JSON (read
+// with ParseLoudnormStats). Encoders run the clip's audio through it to a
+// null output before the real encode.
+func WithLoudnormMeasurement(specs []FilterSpec) []FilterSpec {
+	return withLoudnormParam(specs, loudnormMeasureParam, true)
+}
+
+// WithLoudnormStats returns a copy of specs in which the two-pass normalize
+// filter uses the measured stats, so loudnorm can normalize linearly to the
+// target instead of adjusting the gain as it goes.
+func WithLoudnormStats(specs []FilterSpec, stats LoudnormStats) []FilterSpec {
+	return withLoudnormParam(specs, loudnormStatsParam, stats)
+}
+
+func withLoudnormParam(specs []FilterSpec, key string, value any) []FilterSpec {
+	specs = dropParams(specs, loudnormMeasureParam, loudnormStatsParam)
+	at := twoPassLoudnorm(specs)
+	if at < 0 {
+		return specs
+	}
+	out := specs
+	params := make(map[string]any, len(out[at].Params)+1)
+	for k, v := range out[at].Params {
+		params[k] = v
+	}
+	params[key] = value
+	out[at].Params = params
+	return out
+}
+
+// ParseLoudnormStats reads the statistics of a measurement pass from
+// ffmpeg's stderr. Audio too quiet to measure is reported as an error, since
+// its stats cannot drive a linear second pass.
+func ParseLoudnormStats(stderr string) (LoudnormStats, error) {
+	end := strings.LastIndex(stderr, "}")
+	start := -1
+	if end >= 0 {
+		start = strings.LastIndex(stderr[:end], "{")
+	}
+	if start < 0 {
+		return LoudnormStats{}, fmt.Errorf("loudnorm printed no statistics")
+	}
+	var raw struct {
+		InputI       string `json:"input_i"`
+		InputTP      string `json:"input_tp"`
+		InputLRA     string `json:"input_lra"`
+		InputThresh  string `json:"input_thresh"`
+		TargetOffset string `json:"target_offset"`
+	}
+	if err := json.Unmarshal([]byte(stderr[start:end+1]), &raw); err != nil {
+		return LoudnormStats{}, fmt.Errorf("parse loudnorm statistics: %w", err)
+	}
+	var stats LoudnormStats
+	for _, f := range []struct {
+		name string
+		raw  string
+		dst  *float64
+	}{
+		{"input_i", raw.InputI, &stats.InputI},
+		{"input_tp", raw.InputTP, &stats.InputTP},
+		{"input_lra", raw.InputLRA, &stats.InputLRA},
+		{"input_thresh", raw.InputThresh, &stats.InputThresh},
+		{"target_offset", raw.TargetOffset, &stats.TargetOffset},
+	} {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f.raw), 64)
+		if err != nil {
+			return LoudnormStats{}, fmt.Errorf("loudnorm %s %q is not a number", f.name, f.raw)
+		}
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return LoudnormStats{}, fmt.Errorf("loudnorm could not measure the audio (%s is %s)", f.name, f.raw)
+		}
+		*f.dst = v
+	}
+	return stats, nil
+}
+
+// compileLoudnorm builds the normalize filter's loudnorm mode: EBU R128
+// normalization to the "i", "tp" and "lra" targets. In a measurement pass
+// it prints its statistics; given measured stats it normalizes linearly.
+func compileLoudnorm(params map[string]any) ([]Option, error) {
+	i := paramFloat(params, "i", DefaultLoudnormI)
+	tp := paramFloat(params, "tp", DefaultLoudnormTP)
+	lra := paramFloat(params, "lra", DefaultLoudnormLRA)
+	if i < MinLoudnormI || i > MaxLoudnormI {
+		return nil, fmt.Errorf("i must be between %g and %g LUFS", MinLoudnormI, MaxLoudnormI)
+	}
+	if tp < MinLoudnormTP || tp > MaxLoudnormTP {
+		return nil, fmt.Errorf("tp must be between %g and %g dBTP", MinLoudnormTP, MaxLoudnormTP)
+	}
+	if lra < MinLoudnormLRA || lra > MaxLoudnormLRA {
+		return nil, fmt.Errorf("lra must be between %g and %g LU", MinLoudnormLRA, MaxLoudnormLRA)
+	}
+	filter := fmt.Sprintf("loudnorm=I=%s:TP=%s:LRA=%s", formatFloat(i), formatFloat(tp), formatFloat(lra))
+	if measure, _ := params[loudnormMeasureParam].(bool); measure {
+		filter += ":print_format=json"
+	} else if stats, ok := params[loudnormStatsParam].(LoudnormStats); ok {
+		filter += fmt.Sprintf(":measured_I=%.2f:measured_TP=%.2f:measured_LRA=%.2f:measured_thresh=%.2f:offset=%.2f:linear=true",
+			stats.InputI, stats.InputTP, stats.InputLRA, stats.InputThresh, stats.TargetOffset)
+	}
+	return []Option{AudioFilter(filter)}, nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,110 @@
+package ffmpeg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const loudnormStderr = `size=N/A time=00:00:10.00 bitrate=N/A speed= 180x
+[Parsed_loudnorm_1 @ 0x55d0c3a4e2c0] 
+{
+	"input_i" : "-27.61",
+	"input_tp" : "-4.47",
+	"input_lra" : "18.06",
+	"input_thresh" : "-39.20",
+	"output_i" : "-16.58",
+	"output_tp" : "-1.50",
+	"output_lra" : "14.78",
+	"output_thresh" : "-27.71",
+	"normalization_type" : "dynamic",
+	"target_offset" : "0.58"
+}
+`
+
+func TestCompileLoudnorm(t *testing.T) {
+	cases := []struct {
+		params map[string]any
+		want   string
+	}{
+		{nil, "loudnorm=I=-24:TP=-2:LRA=7"},
+		{map[string]any{"mode": "loudnorm", "i": "-16", "tp": "-1.5", "lra": "11"}, "loudnorm=I=-16:TP=-1.5:LRA=11"},
+		{map[string]any{"i": -23.0, "tp": -1.0}, "loudnorm=I=-23:TP=-1:LRA=7"},
+	}
+	for _, tc := range cases {
+		_, audio, err := CompileFilterStrings([]FilterSpec{{Type: "normalize", Params: tc.params}}, nil)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.params, err)
+		}
+		if !reflect.DeepEqual(audio, []string{tc.want}) {
+			t.Errorf("%v: audio = %q, want %q", tc.params, audio, tc.want)
+		}
+	}
+
+	for _, params := range []map[string]any{
+		{"i": -4.0},
+		{"i": -71.0},
+		{"tp": 0.5},
+		{"lra": 0.5},
+		{"lra": 21.0},
+	} {
+		if _, err := CompileFilters([]FilterSpec{{Type: "normalize", Params: params}}, nil); err == nil {
+			t.Errorf("expected error for params %v", params)
+		}
+	}
+}
+
+func TestLoudnormTwoPass(t *testing.T) {
+	specs := []FilterSpec{
+		{Type: "volume", Params: map[string]any{"gain": 2.0}},
+		{Type: "normalize", Params: map[string]any{"i": "-16", "tp": "-1.5", "lra": "11", "two_pass": "1"}},
+		{Type: "normalize", Params: map[string]any{"two_pass": true}},
+	}
+	if !NeedsLoudnormMeasurement(specs) {
+		t.Fatal("NeedsLoudnormMeasurement = false")
+	}
+	if NeedsLoudnormMeasurement([]FilterSpec{{Type: "normalize", Params: map[string]any{"mode": "rms", "two_pass": "1"}}}) {
+		t.Fatal("dynaudnorm modes have no measurement pass")
+	}
+
+	_, audio, err := CompileFilterStrings(WithLoudnormMeasurement(specs), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audio[1] != "loudnorm=I=-16:TP=-1.5:LRA=11:print_format=json" || audio[2] != "loudnorm=I=-24:TP=-2:LRA=7" {
+		t.Fatalf("measurement pass audio = %q", audio)
+	}
+
+	stats, err := ParseLoudnormStats(loudnormStderr)
+	if err != nil {
+		t.Fatalf("ParseLoudnormStats: %v", err)
+	}
+	want := LoudnormStats{InputI: -27.61, InputTP: -4.47, InputLRA: 18.06, InputThresh: -39.2, TargetOffset: 0.58}
+	if stats != want {
+		t.Fatalf("stats = %+v, want %+v", stats, want)
+	}
+
+	_, audio, err = CompileFilterStrings(WithLoudnormStats(specs, stats), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFilter := "loudnorm=I=-16:TP=-1.5:LRA=11:measured_I=-27.61:measured_TP=-4.47:measured_LRA=18.06:measured_thresh=-39.20:offset=0.58:linear=true"
+	if audio[1] != wantFilter || audio[2] != "loudnorm=I=-24:TP=-2:LRA=7" {
+		t.Fatalf("second pass audio = %q", audio)
+	}
+	if _, ok := specs[1].Params[loudnormStatsParam]; ok {
+		t.Fatal("WithLoudnormStats mutated the input specs")
+	}
+}
+
+func TestParseLoudnormStats_Errors(t *testing.T) {
+	for name, stderr := range map[string]string{
+		"missing": "size=N/A time=00:00:10.00\n",
+		"silent":  strings.Replace(loudnormStderr, `"input_i" : "-27.61"`, `"input_i" : "-inf"`, 1),
+		"garbled": strings.Replace(loudnormStderr, `"input_tp" : "-4.47"`, `"input_tp" : "loud"`, 1),
+	} {
+		if _, err := ParseLoudnormStats(stderr); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package ffmpeg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Uploaded .cube LUT limits. 65 points per axis is the largest size common
+// grading tools export.
+const (
+	MinCubeLUTSize = 2
+	MaxCubeLUTSize = 65
+)
+
+// LUTInfo describes an uploaded .cube file. It is stored next to the file.
+type LUTInfo struct {
+	// Name is the LUT's TITLE, or the uploaded file name without extension.
+	Name string `json:"name"`
+	// Size is the number of points along each axis of the 3D table.
+	Size int `json:"size"`
+}
+
+// ParseCubeLUT checks that data is a 3D LUT in the .cube format ffmpeg's
+// lut3d filter reads and returns its title and size. 1D LUTs are rejected.
+// Errors are user-facing.
+func ParseCubeLUT(data []byte) (title string, size int, err error) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	entries, lineNo := 0, 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "TITLE":
+			title = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "TITLE")), `"`)
+			continue
+		case "LUT_1D_SIZE":
+			return "", 0, fmt.Errorf("1D LUTs are not supported; upload a 3D LUT")
+		case "LUT_3D_SIZE":
+			if size != 0 || len(fields) != 2 {
+				return "", 0, fmt.Errorf("line %d: invalid LUT_3D_SIZE", lineNo)
+			}
+			size, err = strconv.Atoi(fields[1])
+			if err != nil || size < MinCubeLUTSize || size > MaxCubeLUTSize {
+				return "", 0, fmt.Errorf("LUT_3D_SIZE must be between %d and %d", MinCubeLUTSize, MaxCubeLUTSize)
+			}
+			continue
+		case "DOMAIN_MIN", "DOMAIN_MAX":
+			if len(fields) != 4 || !allFloats(fields[1:]) {
+				return "", 0, fmt.Errorf("line %d: invalid %s", lineNo, fields[0])
+			}
+			continue
+		}
+		if len(fields) != 3 || !allFloats(fields) {
+			return "", 0, fmt.Errorf("line %d: expected three numbers", lineNo)
+		}
+		if size == 0 {
+			return "", 0, fmt.Errorf("line %d: table data before LUT_3D_SIZE", lineNo)
+		}
+		entries++
+	}
+	if err := sc.Err(); err != nil {
+		return "", 0, fmt.Errorf("unreadable LUT: %w", err)
+	}
+	if size == 0 {
+		return "", 0, fmt.Errorf("missing LUT_3D_SIZE")
+	}
+	if entries != size*size*size {
+		return "", 0, fmt.Errorf("LUT_3D_SIZE %d needs %d table entries, found %d", size, size*size*size, entries)
+	}
+	return title, size, nil
+}
+
+func allFloats(fields []string) bool {
+	for _, f := range fields {
+		if _, err := strconv.ParseFloat(f, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// LUTFileDir is the directory holding a user's uploaded LUTs under the
+// exports directory, shared by the web service and the encoder.
+func LUTFileDir(exportsDir, userID string) string {
+	return filepath.Join(exportsDir, "luts", userID)
+}
+
+// LUTFilePath returns the .cube file of LUT lutID in dir. Its LUTInfo is
+// stored alongside (see LUTInfoPath). Errors are user-facing.
+func LUTFilePath(dir, lutID string) (string, error) {
+	id, err := uuid.Parse(strings.TrimSpace(lutID))
+	if err != nil {
+		return "", fmt.Errorf("invalid lut_id")
+	}
+	return filepath.Join(dir, id.String()+".cube"), nil
+}
+
+// LUTInfoPath is the file holding the LUTInfo of the .cube file at path.
+func LUTInfoPath(path string) string {
+	return strings.TrimSuffix(path, ".cube") + ".json"
+}
+
+// ReadLUTInfo reads the LUTInfo stored next to the .cube file at path.
+func ReadLUTInfo(path string) (LUTInfo, error) {
+	var info LUTInfo
+	data, err := os.ReadFile(LUTInfoPath(path))
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
+
+// WriteLUTInfo stores info next to the .cube file at path.
+func WriteLUTInfo(path string, info LUTInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(LUTInfoPath(path), data, 0o644)
+}
+
+// escapeFilterPath quotes a file path for use as a filter option value.
+func escapeFilterPath(path string) string {
+	path = strings.ReplaceAll(path, `\`, `\\`)
+	path = strings.ReplaceAll(path, `'`, `\'`)
+	path = strings.ReplaceAll(path, `:`, `\:`)
+	return "'" + path + "'"
+}
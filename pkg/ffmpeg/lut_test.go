@@ -0,0 +1,57 @@
+package ffmpeg
+
+import (
+	"strings"
+	"testing"
+)
+
+// identityCube returns a 2-point identity .cube file with extra lines
+// inserted before the table.
+func identityCube(header ...string) string {
+	lines := append([]string{"# test LUT"}, header...)
+	lines = append(lines, "LUT_3D_SIZE 2")
+	for b := 0; b < 2; b++ {
+		for g := 0; g < 2; g++ {
+			for r := 0; r < 2; r++ {
+				lines = append(lines, strings.Join([]string{itoa(r), itoa(g), itoa(b)}, " ")+".0")
+			}
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestParseCubeLUT(t *testing.T) {
+	title, size, err := ParseCubeLUT([]byte(identityCube(`TITLE "Warm Film"`, "DOMAIN_MIN 0 0 0", "DOMAIN_MAX 1 1 1")))
+	if err != nil {
+		t.Fatalf("ParseCubeLUT: %v", err)
+	}
+	if title != "Warm Film" || size != 2 {
+		t.Fatalf("got title %q size %d", title, size)
+	}
+
+	if title, _, err := ParseCubeLUT([]byte(identityCube())); err != nil || title != "" {
+		t.Fatalf("untitled LUT: title %q, err %v", title, err)
+	}
+
+	for name, data := range map[string]string{
+		"empty":        "",
+		"1d":           "LUT_1D_SIZE 4\n0 0 0\n",
+		"too large":    "LUT_3D_SIZE 256\n",
+		"short table":  "LUT_3D_SIZE 2\n0 0 0\n1 1 1\n",
+		"no size":      "0 0 0\n",
+		"not numbers":  strings.Replace(identityCube(), "1 1 1.0", "1 x 1.0", 1),
+		"bad domain":   identityCube("DOMAIN_MIN 0 0"),
+		"two sizes":    identityCube("LUT_3D_SIZE 2"),
+		"binary noise": "\x89PNG\r\n\x1a\n",
+	} {
+		if _, _, err := ParseCubeLUT([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestEscapeFilterPath(t *testing.T) {
+	if got, want := escapeFilterPath(`/data/it's:here`), `'/data/it\'s\:here'`; got != want {
+		t.Fatalf("escapeFilterPath = %q, want %q", got, want)
+	}
+}
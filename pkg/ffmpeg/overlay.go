@@ -0,0 +1,155 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Image overlay limits. Scale is the overlay width as a percentage of the
+// video's width; opacity runs from 0 (invisible) to 1 (opaque).
+const (
+	DefaultOverlayScale = 20
+	MinOverlayScale     = 1
+	MaxOverlayScale     = 100
+)
+
+// imageOverlay is an extra input composited with the main video: an image
+// drawn over it, or a background drawn under it (see ImageBackground).
+type imageOverlay struct {
+	inputArgs  []string // args reading the input, ending with -i
+	at         int      // number of filters in the chain when the overlay was added
+	background bool     // stretched to fill the frame and drawn under the video
+	scale      float64  // overlay width as a fraction of the video's width
+	opacity    float64
+	x, y       string // overlay x/y expressions
+}
+
+// ImageOverlay composites a still image (e.g. a PNG logo with transparency)
+// over the video at x/y, which are overlay expressions in W/H (the video's
+// size) and w/h (the image's). The image is scaled to scale times the
+// video's width, keeping its aspect ratio, and drawn with the given opacity.
+//
+// The image is read as an extra looped input, so the command switches from
+// -vf to a -filter_complex graph. The overlay applies at its place in the
+// filter chain: filters added before it also change the picture under it,
+// filters added after it change both.
+func ImageOverlay(input string, scale, opacity float64, x, y string) Option {
+	return OptionFunc(func(cmd *Command) {
+		cmd.overlays = append(cmd.overlays, imageOverlay{
+			inputArgs: []string{"-loop", "1", "-i", input},
+			at:        len(cmd.filters),
+			scale:     scale,
+			opacity:   opacity,
+			x:         x,
+			y:         y,
+		})
+	})
+}
+
+// ImageBackground draws the video over a still image stretched to fill the
+// frame, so areas made transparent by a filter added before it (e.g.
+// chromakey) show the image. Like ImageOverlay it reads an extra input and
+// applies at its place in the filter chain.
+//
+// The background is the overlay's main input, so its frame rate becomes the
+// output's: fps should be the source video's. With 0 the input keeps
+// ffmpeg's default of 25.
+func ImageBackground(input string, fps float64) Option {
+	args := []string{"-loop", "1"}
+	if fps > 0 {
+		args = append(args, "-framerate", strconv.FormatFloat(fps, 'f', -1, 64))
+	}
+	return OptionFunc(func(cmd *Command) {
+		cmd.overlays = append(cmd.overlays, imageOverlay{
+			inputArgs:  append(args, "-i", input),
+			at:         len(cmd.filters),
+			background: true,
+		})
+	})
+}
+
+// ColorBackground is ImageBackground for a solid color, in any syntax
+// ffmpeg accepts (e.g. "black" or "#00ff00").
+func ColorBackground(color string, fps float64) Option {
+	source := "color=c=" + color
+	if fps > 0 {
+		source += ":r=" + strconv.FormatFloat(fps, 'f', -1, 64)
+	}
+	return OptionFunc(func(cmd *Command) {
+		cmd.overlays = append(cmd.overlays, imageOverlay{
+			inputArgs:  []string{"-f", "lavfi", "-i", source},
+			at:         len(cmd.filters),
+			background: true,
+		})
+	})
+}
+
+// overlayInputArgs returns the -i args of the overlays. They follow the main
+// input and the PiP input, if any. Audio-only output reads none.
+func (c *Command) overlayInputArgs() []string {
+	if c.noVideo {
+		return nil
+	}
+	var args []string
+	for _, o := range c.overlays {
+		args = append(args, o.inputArgs...)
+	}
+	return args
+}
+
+// mainVideoGraph is the main video's filter chain as filter graph text from
+// [0:v] to [out], with each overlay and background spliced in where it was
+// added.
+func (c *Command) mainVideoGraph(out string) string {
+	if len(c.overlays) == 0 {
+		return "[0:v]" + c.videoFilterChain() + "[" + out + "]"
+	}
+	first := 1
+	if c.pip != nil {
+		first = 2
+	}
+	var g strings.Builder
+	in, from := "[0:v]", 0
+	for i, o := range c.overlays {
+		n := strconv.Itoa(i)
+		g.WriteString(in + filterChain(c.filters[from:o.at]) + "[ovb" + n + "];")
+		input := "[" + strconv.Itoa(first+i) + ":v]"
+		if o.background {
+			g.WriteString(input + "[ovb" + n + "]scale2ref=w=main_w:h=main_h[ovs" + n + "][ovr" + n + "];")
+			g.WriteString("[ovs" + n + "][ovr" + n + "]overlay=shortest=1[ovo" + n + "];")
+			in, from = "[ovo"+n+"]", o.at
+			continue
+		}
+		g.WriteString(input + "format=rgba")
+		if o.opacity < 1 {
+			g.WriteString(",colorchannelmixer=aa=" + strconv.FormatFloat(o.opacity, 'f', -1, 64))
+		}
+		g.WriteString("[ovi" + n + "];")
+		g.WriteString("[ovi" + n + "][ovb" + n + "]scale2ref=w=trunc(main_w*" +
+			strconv.FormatFloat(o.scale, 'f', -1, 64) + "/2)*2:h=trunc(ow/dar/2)*2[ovs" + n + "][ovr" + n + "];")
+		g.WriteString("[ovr" + n + "][ovs" + n + "]overlay=x=" + o.x + ":y=" + o.y + ":shortest=1[ovo" + n + "];")
+		in, from = "[ovo"+n+"]", o.at
+	}
+	g.WriteString(in + filterChain(c.filters[from:]) + "[" + out + "]")
+	return g.String()
+}
+
+// OverlayImageDir is the directory holding a user's overlay images under the
+// exports directory, shared by the web service and the encoder.
+func OverlayImageDir(exportsDir, userID string) string {
+	return filepath.Join(exportsDir, "overlays", userID)
+}
+
+// OverlayImagePath returns the file of overlay image imageID in dir. Errors
+// are user-facing.
+func OverlayImagePath(dir, imageID string) (string, error) {
+	id, err := uuid.Parse(strings.TrimSpace(imageID))
+	if err != nil {
+		return "", fmt.Errorf("invalid image_id")
+	}
+	return filepath.Join(dir, id.String()+".png"), nil
+}
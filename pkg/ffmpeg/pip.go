@@ -0,0 +1,113 @@
+package ffmpeg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PiP inset size limits, as a fraction of the main video's width.
+const (
+	DefaultPiPScale = 0.25
+	MinPiPScale     = 0.1
+	MaxPiPScale     = 0.5
+)
+
+// pipMargin is the gap between the inset and the frame edge, as a fraction
+// of the main video's width.
+const pipMargin = "0.02"
+
+// PiPSpec insets a second archived video as a corner picture-in-picture,
+// e.g. a sign-language interpreter over the main video.
+type PiPSpec struct {
+	// VideoID is the archived video shown in the inset.
+	VideoID string `json:"video_id"`
+	// Position is the corner: "top_left", "top_right", "bottom_left" or
+	// "bottom_right". Empty means bottom right.
+	Position string `json:"position,omitempty"`
+	// Scale is the inset width as a fraction of the main video's width,
+	// between MinPiPScale and MaxPiPScale. 0 means DefaultPiPScale.
+	Scale float64 `json:"scale,omitempty"`
+	// Start is where playback of the inset video begins, in seconds. Nil
+	// means the clip's own start time, for recordings that share a timeline.
+	Start *float64 `json:"start,omitempty"`
+}
+
+// pipOverlayXY maps each position to overlay x/y expressions. W/H are the
+// main video's dimensions, w/h the inset's.
+var pipOverlayXY = map[string][2]string{
+	"top_left":     {"W*" + pipMargin, "W*" + pipMargin},
+	"top_right":    {"W-w-W*" + pipMargin, "W*" + pipMargin},
+	"bottom_left":  {"W*" + pipMargin, "H-h-W*" + pipMargin},
+	"bottom_right": {"W-w-W*" + pipMargin, "H-h-W*" + pipMargin},
+}
+
+// Validate checks the spec. Errors are user-facing.
+func (p PiPSpec) Validate() error {
+	if strings.TrimSpace(p.VideoID) == "" {
+		return fmt.Errorf("pip.video_id is required")
+	}
+	if _, ok := pipOverlayXY[p.position()]; !ok {
+		return fmt.Errorf("pip.position must be top_left, top_right, bottom_left or bottom_right")
+	}
+	if p.Scale != 0 && (p.Scale < MinPiPScale || p.Scale > MaxPiPScale) {
+		return fmt.Errorf("pip.scale must be between %g and %g", MinPiPScale, MaxPiPScale)
+	}
+	if p.Start != nil && *p.Start < 0 {
+		return fmt.Errorf("pip.start must be >= 0")
+	}
+	return nil
+}
+
+func (p PiPSpec) position() string {
+	if p.Position == "" {
+		return "bottom_right"
+	}
+	return p.Position
+}
+
+func (p PiPSpec) scale() float64 {
+	if p.Scale == 0 {
+		return DefaultPiPScale
+	}
+	return p.Scale
+}
+
+// pipInput is the second input of a picture-in-picture command.
+type pipInput struct {
+	input    string
+	start    time.Duration
+	duration time.Duration
+	spec     PiPSpec
+}
+
+// inputArgs returns the seek, length and -i arguments of the inset input.
+func (p *pipInput) inputArgs() []string {
+	args := []string{"-ss", formatDuration(p.start)}
+	if p.duration > 0 {
+		args = append(args, "-t", formatDuration(p.duration))
+	}
+	return append(args, "-i", p.input)
+}
+
+// PictureInPicture overlays input, read from start for duration (the clip's
+// length; 0 reads to the end), as an inset over the main video. The command switches from -vf to a -filter_complex graph: the
+// main video's filters run first, then the inset is scaled relative to the
+// result and overlaid. Output ends with the shorter of the two videos, audio
+// included; audio comes from the main input only.
+func PictureInPicture(input string, start, duration time.Duration, spec PiPSpec) Option {
+	return OptionFunc(func(cmd *Command) {
+		cmd.pip = &pipInput{input: input, start: start, duration: duration, spec: spec}
+	})
+}
+
+// pipFilterGraph builds the -filter_complex graph for a PiP command. The
+// result is labelled [vout].
+func (c *Command) pipFilterGraph() string {
+	xy := pipOverlayXY[c.pip.spec.position()]
+	scale := strconv.FormatFloat(c.pip.spec.scale(), 'f', -1, 64)
+	return c.mainVideoGraph("base") + ";" +
+		"[1:v][base]scale2ref=w=trunc(main_w*" + scale + "/2)*2:h=trunc(ow/dar/2)*2[pip][ref];" +
+		"[ref][pip]overlay=x=" + xy[0] + ":y=" + xy[1] + ":shortest=1[vout]"
+}
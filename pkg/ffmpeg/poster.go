@@ -0,0 +1,112 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Poster dimensions, the size Open Graph and Twitter large-image cards
+// display without cropping.
+const (
+	PosterWidth  = 1200
+	PosterHeight = 630
+)
+
+const (
+	posterFontSize   = 54
+	posterLineHeight = 68
+	posterMargin     = 56
+	// posterLineChars and posterMaxLines bound the title block: about as
+	// many characters as fit across the poster at posterFontSize, and no
+	// more lines than fit in the shaded band.
+	posterLineChars = 34
+	posterMaxLines  = 3
+)
+
+// WrapTitle breaks title into at most maxLines lines of at most maxChars
+// characters, splitting at spaces where possible. Words longer than a line are
+// cut. A title that does not fit ends with "..." on the last line.
+func WrapTitle(title string, maxChars, maxLines int) []string {
+	if maxChars < 4 || maxLines < 1 {
+		return nil
+	}
+	// Cut words longer than a line into line-sized pieces.
+	var words []string
+	for _, w := range strings.Fields(title) {
+		r := []rune(w)
+		for len(r) > maxChars {
+			words = append(words, string(r[:maxChars]))
+			r = r[maxChars:]
+		}
+		words = append(words, string(r))
+	}
+
+	var lines []string
+	cur := ""
+	for _, w := range words {
+		if cur == "" {
+			cur = w
+		} else if utf8.RuneCountInString(cur)+1+utf8.RuneCountInString(w) <= maxChars {
+			cur += " " + w
+		} else {
+			lines = append(lines, cur)
+			cur = w
+		}
+		if len(lines) == maxLines {
+			// Out of lines with words left over.
+			last := lines[maxLines-1]
+			for utf8.RuneCountInString(last)+3 > maxChars {
+				if i := strings.LastIndex(last, " "); i > 0 {
+					last = last[:i]
+				} else {
+					last = string([]rune(last)[:maxChars-3])
+				}
+			}
+			lines[maxLines-1] = last + "..."
+			return lines
+		}
+	}
+	if cur != "" {
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+// RenderPoster writes a PosterWidth x PosterHeight JPEG for link previews:
+// input (normally the video thumbnail) scaled to fill the frame, a dark band
+// along the bottom carrying the wrapped title, and brand in the top-left
+// corner. Long titles are wrapped and truncated with WrapTitle.
+func RenderPoster(ctx context.Context, input, output, title, brand string) RunResult {
+	return RunCapture(ctx, input, output,
+		Filter(posterFilter(title, brand)),
+		Frames(1),
+		Quality(3),
+	)
+}
+
+// posterFilter builds the RenderPoster filter chain.
+func posterFilter(title, brand string) string {
+	lines := WrapTitle(title, posterLineChars, posterMaxLines)
+	bandHeight := posterMargin*2 + posterLineHeight*max(len(lines), 1)
+
+	parts := []string{
+		fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase", PosterWidth, PosterHeight),
+		fmt.Sprintf("crop=%d:%d", PosterWidth, PosterHeight),
+		fmt.Sprintf("drawbox=x=0:y=ih-%d:w=iw:h=%d:color=black@0.7:t=fill", bandHeight, bandHeight),
+	}
+	for i, line := range lines {
+		// A straight quote cannot be escaped inside drawtext's quoted text.
+		line = strings.ReplaceAll(line, "'", "’")
+		// Lines are laid out top to bottom inside the band.
+		y := PosterHeight - bandHeight + posterMargin + i*posterLineHeight
+		parts = append(parts, fmt.Sprintf("drawtext=text='%s':fontsize=%d:fontcolor=white:x=%d:y=%d",
+			escapeDrawtext(line), posterFontSize, posterMargin, y))
+	}
+	if brand != "" {
+		parts = append(parts, fmt.Sprintf("drawtext=text='%s':fontsize=28:fontcolor=white:box=1:boxcolor=black@0.7:boxborderw=12:x=%d:y=%d",
+			escapeDrawtext(strings.ToUpper(brand)), posterMargin/2+12, posterMargin/2+12))
+	}
+	return strings.Join(parts, ",")
+}
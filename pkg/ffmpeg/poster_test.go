@@ -0,0 +1,39 @@
+package ffmpeg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  []string
+	}{
+		{"fits on one line", "Short one", []string{"Short one"}},
+		{"wraps at spaces", "one two three four", []string{"one two", "three four"}},
+		{"cuts long words", "abcdefghijklmnop", []string{"abcdefghij", "klmnop"}},
+		{"truncates", "one two three four five six seven", []string{"one two", "three four", "five..."}},
+		{"empty", "   ", nil},
+	}
+	for _, tt := range tests {
+		if got := WrapTitle(tt.title, 10, 3); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: WrapTitle(%q) = %q, want %q", tt.name, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestPosterFilter(t *testing.T) {
+	f := posterFilter("It's a test: 100%", "Rewind")
+	for _, want := range []string{
+		"scale=1200:630:force_original_aspect_ratio=increase,crop=1200:630",
+		`drawtext=text='It’s a test\: 100%%'`,
+		"drawtext=text='REWIND'",
+	} {
+		if !strings.Contains(f, want) {
+			t.Errorf("filter missing %q:\n%s", want, f)
+		}
+	}
+}
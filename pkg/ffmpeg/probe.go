@@ -22,6 +22,12 @@ type ProbeResult struct {
 	AudioCodec      string // Audio codec name (aac, opus, etc.)
 	AudioChannels   int    // Number of audio channels
 	AudioSampleRate int    // Audio sample rate in Hz
+	// AudioCodecs lists the codec of every audio stream, in stream order.
+	AudioCodecs []string
+
+	// SubtitleCodecs lists the codec of every subtitle stream, in stream
+	// order, so index i is "0:s:<i>".
+	SubtitleCodecs []string
 
 	// File properties
 	Duration   float64 // Duration in seconds
@@ -29,14 +35,42 @@ type ProbeResult struct {
 	Size       int64   // File size in bytes
 	FormatName string  // Container format (mp4, webm, mkv, etc.)
 
-	// Stream counts
+	// Stream counts. Attached pictures (embedded cover art) are not counted
+	// as video streams; see AttachedPics.
 	VideoStreams int
 	AudioStreams int
 
+	// AttachedPics lists the video stream index ("0:v:<N>") of every attached
+	// picture, such as the cover art of an audio file.
+	AttachedPics []int
+
+	// Angles lists every real video stream (not embedded cover art). Files
+	// with more than one carry alternate camera angles.
+	Angles []VideoAngle
+
+	// Chapters lists the container's chapters in file order, as muxed by
+	// yt-dlp (--embed-chapters) or the original encoder.
+	Chapters []ProbeChapter
+
 	// Raw JSON from ffprobe (complete output)
 	RawJSON map[string]any
 }
 
+// VideoAngle is one video stream of a possibly multi-angle file.
+type VideoAngle struct {
+	Index  int // Position among the file's video streams, as in "0:v:<Index>"
+	Codec  string
+	Width  int
+	Height int
+}
+
+// ProbeChapter is one container-level chapter. Times are seconds.
+type ProbeChapter struct {
+	StartTime float64
+	EndTime   float64
+	Title     string
+}
+
 // ffprobeOutput matches ffprobe JSON output structure.
 type ffprobeOutput struct {
 	Format struct {
@@ -61,7 +95,14 @@ type ffprobeOutput struct {
 		SampleRate    string `json:"sample_rate"`
 		Channels      int    `json:"channels"`
 		ChannelLayout string `json:"channel_layout"`
+
+		Disposition map[string]int `json:"disposition"`
 	} `json:"streams"`
+	Chapters []struct {
+		StartTime string            `json:"start_time"`
+		EndTime   string            `json:"end_time"`
+		Tags      map[string]string `json:"tags"`
+	} `json:"chapters"`
 }
 
 // Probe runs ffprobe on a file and returns metadata.
@@ -72,10 +113,11 @@ func Probe(ctx context.Context, path string) (*ProbeResult, error) {
 		"-print_format", "json",
 		"-show_format",
 		"-show_streams",
+		"-show_chapters",
 		path,
 	}
 
-	cmd := exec.CommandContext(ctx, "ffprobe", args...)
+	cmd := exec.CommandContext(ctx, ffprobePath, args...)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -84,9 +126,11 @@ func Probe(ctx context.Context, path string) (*ProbeResult, error) {
 	if err := cmd.Run(); err != nil {
 		return nil, fmt.Errorf("ffprobe: %w: %s", err, stderr.String())
 	}
+	return parseProbeOutput(stdout.Bytes())
+}
 
-	rawJSON := stdout.Bytes()
-
+// parseProbeOutput builds a ProbeResult from ffprobe's JSON output.
+func parseProbeOutput(rawJSON []byte) (*ProbeResult, error) {
 	var output ffprobeOutput
 	if err := json.Unmarshal(rawJSON, &output); err != nil {
 		return nil, fmt.Errorf("ffprobe: failed to parse output: %w", err)
@@ -114,10 +158,24 @@ func Probe(ctx context.Context, path string) (*ProbeResult, error) {
 	}
 	result.FormatName = output.Format.FormatName
 
-	// Parse streams
+	// Parse streams. videoIndex counts every video stream, attached pictures
+	// included, since that is how ffmpeg numbers "0:v:<N>".
+	videoIndex := 0
 	for _, stream := range output.Streams {
 		switch stream.CodecType {
 		case "video":
+			index := videoIndex
+			videoIndex++
+			if stream.Disposition["attached_pic"] == 1 {
+				result.AttachedPics = append(result.AttachedPics, index)
+				continue
+			}
+			result.Angles = append(result.Angles, VideoAngle{
+				Index:  index,
+				Codec:  stream.CodecName,
+				Width:  stream.Width,
+				Height: stream.Height,
+			})
 			result.VideoStreams++
 			// Only take first video stream metadata
 			if result.VideoCodec == "" {
@@ -128,8 +186,12 @@ func Probe(ctx context.Context, path string) (*ProbeResult, error) {
 				result.FPS = parseFrameRate(stream.RFrameRate)
 			}
 
+		case "subtitle":
+			result.SubtitleCodecs = append(result.SubtitleCodecs, stream.CodecName)
+
 		case "audio":
 			result.AudioStreams++
+			result.AudioCodecs = append(result.AudioCodecs, stream.CodecName)
 			// Only take first audio stream metadata
 			if result.AudioCodec == "" {
 				result.AudioCodec = stream.CodecName
@@ -141,6 +203,19 @@ func Probe(ctx context.Context, path string) (*ProbeResult, error) {
 		}
 	}
 
+	for _, ch := range output.Chapters {
+		start, err := strconv.ParseFloat(ch.StartTime, 64)
+		if err != nil {
+			continue
+		}
+		end, _ := strconv.ParseFloat(ch.EndTime, 64)
+		result.Chapters = append(result.Chapters, ProbeChapter{
+			StartTime: start,
+			EndTime:   end,
+			Title:     ch.Tags["title"],
+		})
+	}
+
 	return result, nil
 }
 
@@ -27,6 +27,16 @@ func (p Progress) OutTimeSeconds() float64 {
 	return float64(p.OutTimeUS) / 1_000_000
 }
 
+// SpeedMultiplier returns Speed as a number (e.g. 2.5 for "2.5x"), or 0 when
+// ffmpeg has not reported a speed yet ("N/A").
+func (p Progress) SpeedMultiplier() float64 {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(p.Speed), "x"), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
 // ParseProgressLine parses a single line from ffmpeg -progress output.
 // Returns the key, value, and whether parsing succeeded.
 func ParseProgressLine(line string) (key, value string, ok bool) {
@@ -60,7 +60,7 @@ func (p *Process) Stderr() string {
 // Start starts an ffmpeg process and returns a Process handle for lifecycle management.
 // The caller is responsible for calling Wait() or Kill() to clean up.
 func Start(ctx context.Context, args []string, progress chan<- Progress) (*Process, error) {
-	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...)
 
 	p := &Process{
 		cmd:      cmd,
@@ -0,0 +1,92 @@
+package ffmpeg
+
+import "fmt"
+
+// Stabilize filter limits. Smoothing is the number of frames on each side
+// that vidstabtransform averages the camera path over; higher values give a
+// steadier picture but follow intended pans more slowly.
+const (
+	DefaultStabilizeSmoothing = 10
+	MinStabilizeSmoothing     = 1
+	MaxStabilizeSmoothing     = 100
+)
+
+// stabilizeDetectParam and stabilizeTransformsParam are the params keys
+// WithStabilizeDetection and WithStabilizeTransforms inject with the
+// transforms file of the stabilize filter being run.
+const (
+	stabilizeDetectParam     = "_stabilize_detect"
+	stabilizeTransformsParam = "_stabilize_transforms"
+)
+
+// firstStabilize returns the index of the first stabilize filter in specs,
+// or -1.
+func firstStabilize(specs []FilterSpec) int {
+	for i, spec := range specs {
+		if spec.Type == "stabilize" {
+			return i
+		}
+	}
+	return -1
+}
+
+// NeedsStabilizeDetection reports whether specs hold a stabilize filter.
+// vidstab works in two passes: a detection pass over the clip writes the
+// camera motion to a transforms file, which the encode then reads. Only the
+// first stabilize filter is run; a stabilize filter with no transforms
+// compiles to nothing, so later ones, and all of them where the ffmpeg build
+// lacks libvidstab, are skipped.
+func NeedsStabilizeDetection(specs []FilterSpec) bool {
+	return firstStabilize(specs) >= 0
+}
+
+// WithStabilizeDetection returns a copy of specs for the detection pass: the
+// stabilize filter runs vidstabdetect, writing its transforms to path.
+func WithStabilizeDetection(specs []FilterSpec, path string) []FilterSpec {
+	return withStabilizeParam(specs, stabilizeDetectParam, path)
+}
+
+// WithStabilizeTransforms returns a copy of specs in which the stabilize
+// filter applies the transforms a detection pass wrote to path.
+func WithStabilizeTransforms(specs []FilterSpec, path string) []FilterSpec {
+	return withStabilizeParam(specs, stabilizeTransformsParam, path)
+}
+
+// withStabilizeParam sets key on the first stabilize filter. Both pass keys
+// are dropped first, so the main pass never still runs a detection.
+func withStabilizeParam(specs []FilterSpec, key, path string) []FilterSpec {
+	specs = dropParams(specs, stabilizeDetectParam, stabilizeTransformsParam)
+	at := firstStabilize(specs)
+	if at < 0 {
+		return specs
+	}
+	out := specs
+	params := make(map[string]any, len(out[at].Params)+1)
+	for k, v := range out[at].Params {
+		params[k] = v
+	}
+	params[key] = path
+	out[at].Params = params
+	return out
+}
+
+// compileStabilize builds the stabilize filter for the pass it is in: motion
+// detection, or the transform with "smoothing" followed by a light sharpen
+// to offset the interpolation blur. Without either it compiles to nothing.
+func compileStabilize(params map[string]any) ([]Option, error) {
+	smoothing := paramInt(params, "smoothing", DefaultStabilizeSmoothing)
+	if smoothing < MinStabilizeSmoothing || smoothing > MaxStabilizeSmoothing {
+		return nil, fmt.Errorf("smoothing must be between %d and %d", MinStabilizeSmoothing, MaxStabilizeSmoothing)
+	}
+	if path, _ := params[stabilizeDetectParam].(string); path != "" {
+		return []Option{Filter("vidstabdetect=result=" + escapeFilterPath(path))}, nil
+	}
+	path, _ := params[stabilizeTransformsParam].(string)
+	if path == "" {
+		return nil, nil
+	}
+	return []Option{
+		Filter(fmt.Sprintf("vidstabtransform=input=%s:smoothing=%d:optzoom=1", escapeFilterPath(path), smoothing)),
+		Filter("unsharp=5:5:0.8:3:3:0.4"),
+	}, nil
+}
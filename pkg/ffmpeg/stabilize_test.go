@@ -0,0 +1,85 @@
+package ffmpeg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompileStabilize(t *testing.T) {
+	specs := []FilterSpec{
+		{Type: "hflip"},
+		{Type: "stabilize", Params: map[string]any{"smoothing": "20"}},
+		{Type: "stabilize"},
+	}
+	if !NeedsStabilizeDetection(specs) {
+		t.Fatal("NeedsStabilizeDetection = false")
+	}
+
+	// Without a transforms file (no libvidstab) the filter is skipped.
+	video, _, err := CompileFilterStrings(specs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(video, []string{"hflip"}) {
+		t.Fatalf("unresolved video = %q", video)
+	}
+
+	video, _, err = CompileFilterStrings(WithStabilizeDetection(specs, "/exports/clips/c/e.trf"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if video[1] != "vidstabdetect=result='/exports/clips/c/e.trf'" {
+		t.Fatalf("detection video = %q", video)
+	}
+
+	resolved := WithStabilizeTransforms(specs, "/exports/clips/c/e.trf")
+	video, _, err = CompileFilterStrings(resolved, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"vidstabtransform=input='/exports/clips/c/e.trf':smoothing=20:optzoom=1", "unsharp=5:5:0.8:3:3:0.4"}
+	if len(video) != 3 || !reflect.DeepEqual(video[1:], want) {
+		t.Fatalf("transform video = %q", video)
+	}
+	if _, ok := specs[1].Params[stabilizeTransformsParam]; ok {
+		t.Fatal("WithStabilizeTransforms mutated the input specs")
+	}
+
+	for _, smoothing := range []any{0, 101} {
+		if _, err := CompileFilters([]FilterSpec{{Type: "stabilize", Params: map[string]any{"smoothing": smoothing}}}, nil); err == nil {
+			t.Errorf("expected error for smoothing %v", smoothing)
+		}
+	}
+	if _, err := CompileStillFilter(resolved[1]); err == nil {
+		t.Error("expected CompileStillFilter to reject stabilize")
+	}
+}
+
+func TestCompileStabilize_IgnoresUserPassKeys(t *testing.T) {
+	specs := []FilterSpec{{Type: "stabilize", Params: map[string]any{
+		stabilizeDetectParam: "/etc/cron.d/x",
+		"smoothing":          "20",
+	}}}
+
+	video, _, err := CompileFilterStrings(StripInjectedParams(specs), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(video) != 0 {
+		t.Fatalf("stripped video = %q", video)
+	}
+	if _, ok := specs[0].Params[stabilizeDetectParam]; !ok {
+		t.Fatal("StripInjectedParams mutated the input specs")
+	}
+
+	video, _, err = CompileFilterStrings(WithStabilizeTransforms(specs, "/exports/clips/c/e.trf"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range video {
+		if strings.Contains(f, "/etc/cron.d/x") || strings.HasPrefix(f, "vidstabdetect") {
+			t.Fatalf("user detect path reached the filters: %q", video)
+		}
+	}
+}
@@ -68,6 +68,10 @@ func CompileFilterStrings(specs []FilterSpec, clipCrops crops.CropArray) (video,
 	for _, opt := range opts {
 		opt.Apply(scratch)
 	}
+	// An image overlay needs its own input, which a stitch graph lacks
+	if len(scratch.overlays) > 0 {
+		return nil, nil, fmt.Errorf("image_overlay is not supported when stitching clips")
+	}
 	return scratch.VideoFilterStrings(), scratch.AudioFilterStrings(), nil
 }
 
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
 )
 
 // FilterParamType describes the kind of input control for a filter parameter.
@@ -280,18 +282,18 @@ func FilterPresetDataAttr(presets map[string]map[string]string) string {
 func IconForFilterType(t string) string {
 	icons := map[string]string{
 		"crop": "crop", "scale": "up-right-and-down-left-from-center", "transpose": "rotate-right",
-		"rotate": "rotate", "hflip": "arrows-left-right", "vflip": "arrows-up-down", "pad": "border-all",
+		"rotate": "rotate", "hflip": "arrows-left-right", "vflip": "arrows-up-down", "pad": "border-all", "chromakey": "user-slash",
 		"brightness": "sun", "contrast": "circle-half-stroke", "saturation": "palette",
 		"gamma": "sliders", "color_balance": "swatchbook", "curves": "bezier-curve", "grayscale": "droplet-slash",
 		"sepia": "image", "sharpen": "diamond", "denoise": "wand-magic-sparkles",
-		"vignette": "bullseye", "color_temp": "temperature-half", "lift_gamma_gain": "sliders",
-		"lut": "film", "exposure": "sun",
+		"vignette": "bullseye", "blur": "droplet", "color_temp": "temperature-half", "lift_gamma_gain": "sliders",
+		"lut": "film", "lut_file": "file-import", "exposure": "sun",
 		"speed": "gauge-high", "fade_in": "right-long",
-		"fade_out": "left-long", "reverse": "backward",
+		"fade_out": "left-long", "reverse": "backward", "ken_burns": "magnifying-glass-plus", "stabilize": "hand",
 		"volume": "volume-high", "normalize": "chart-bar", "equalizer": "sliders", "bass": "speaker",
 		"treble": "music", "compressor": "compress", "noise_gate": "volume-off", "highpass": "filter", "lowpass": "filter",
 		"audio_fade_in": "volume-low", "audio_fade_out": "volume-xmark", "mute": "volume-xmark",
-		"text": "font",
+		"text": "font", "timecode": "clock", "image_overlay": "image", "subtitles": "closed-captioning",
 	}
 	if v, ok := icons[t]; ok {
 		return v
@@ -303,18 +305,19 @@ func IconForFilterType(t string) string {
 func LabelForFilterType(t string) string {
 	labels := map[string]string{
 		"crop": "Crop", "scale": "Scale", "transpose": "Rotate 90°",
-		"rotate": "Rotate", "hflip": "Flip H", "vflip": "Flip V", "pad": "Pad / Letterbox",
+		"rotate": "Rotate", "hflip": "Flip H", "vflip": "Flip V", "pad": "Pad / Letterbox", "chromakey": "Chroma Key",
 		"brightness": "Brightness", "contrast": "Contrast", "saturation": "Saturation",
 		"gamma": "Gamma", "color_balance": "Color Balance", "curves": "Curves", "grayscale": "Grayscale",
 		"sepia": "Sepia", "sharpen": "Sharpen", "denoise": "Denoise",
-		"vignette": "Vignette", "color_temp": "Color Temperature", "lift_gamma_gain": "Lift / Gamma / Gain",
-		"lut": "LUT Preset", "exposure": "Exposure",
+		"vignette": "Vignette", "blur": "Blur", "color_temp": "Color Temperature", "lift_gamma_gain": "Lift / Gamma / Gain",
+		"lut": "LUT Preset", "lut_file": "LUT File", "exposure": "Exposure",
 		"speed": "Speed", "fade_in": "Fade In",
-		"fade_out": "Fade Out", "reverse": "Reverse",
+		"fade_out": "Fade Out", "reverse": "Reverse", "ken_burns": "Ken Burns", "stabilize": "Stabilize",
 		"volume": "Volume", "normalize": "Normalize", "equalizer": "Equalizer", "bass": "Bass",
 		"treble": "Treble", "compressor": "Compressor", "noise_gate": "Noise Gate", "highpass": "High Pass",
 		"lowpass": "Low Pass", "audio_fade_in": "Audio Fade In",
 		"audio_fade_out": "Audio Fade Out", "mute": "Mute Audio", "text": "Text",
+		"timecode": "Timecode", "image_overlay": "Image Overlay", "subtitles": "Subtitles",
 	}
 	if v, ok := labels[t]; ok {
 		return v
@@ -326,27 +329,42 @@ func LabelForFilterType(t string) string {
 // Used for color-coded card borders.
 func CategoryForFilterType(t string) string {
 	switch t {
-	case "crop", "scale", "transpose", "rotate", "hflip", "vflip", "pad":
+	case "crop", "scale", "transpose", "rotate", "hflip", "vflip", "pad", "chromakey":
 		return "spatial"
 	case "brightness", "contrast", "saturation", "gamma", "color_balance",
 		"curves", "grayscale", "sepia", "sharpen", "denoise", "vignette",
-		"color_temp", "lift_gamma_gain", "lut", "exposure":
+		"blur", "color_temp", "lift_gamma_gain", "lut", "lut_file", "exposure":
 		return "color"
-	case "speed", "fade_in", "fade_out", "reverse":
+	case "speed", "fade_in", "fade_out", "reverse", "ken_burns", "stabilize":
 		return "temporal"
 	case "volume", "normalize", "equalizer", "bass", "treble", "compressor",
 		"noise_gate", "highpass", "lowpass", "audio_fade_in", "audio_fade_out", "mute":
 		return "audio"
-	case "text":
+	case "text", "timecode", "image_overlay", "subtitles":
 		return "overlay"
 	default:
 		return "color"
 	}
 }
 
+// timelineParams limit a filter to part of the clip. They are offered on every
+// filter type the export compiler can restrict with ffmpeg's enable option.
+var timelineParams = []FilterParam{
+	{Key: "start", Label: "From", Type: FilterParamNumber, Min: 0, Max: 86400, Step: 0.1, DefaultVal: "0", Placeholder: "secs into clip"},
+	{Key: "end", Label: "Until", Type: FilterParamNumber, Min: 0, Max: 86400, Step: 0.1, DefaultVal: "0", Placeholder: "0 = clip end"},
+}
+
 // ParamsForFilterType returns the parameter definitions for a given filter type.
 // cropOptions is only used for the "crop" filter and may be nil.
 func ParamsForFilterType(filterType string, cropOptions []FilterOption) []FilterParam {
+	params := paramsForFilterType(filterType, cropOptions)
+	if ffmpeg.SupportsTimeline(filterType) {
+		params = append(params, timelineParams...)
+	}
+	return params
+}
+
+func paramsForFilterType(filterType string, cropOptions []FilterOption) []FilterParam {
 	switch filterType {
 	case "brightness":
 		return []FilterParam{{Key: "value", Label: "Value", Type: FilterParamRange, Min: -1, Max: 1, Step: 0.01, DefaultVal: "0", Decimals: 2, TrackGradient: "linear-gradient(to right, #000, #888, #fff)", HintMin: "dark", HintMax: "bright"}}
@@ -376,12 +394,14 @@ func ParamsForFilterType(filterType string, cropOptions []FilterOption) []Filter
 		}}
 	case "sharpen":
 		return []FilterParam{{Key: "amount", Label: "Amount", Type: FilterParamRange, Min: 0, Max: 5, Step: 0.1, DefaultVal: "1.5", Decimals: 1, HintMin: "soft", HintMax: "sharp"}}
+	case "blur":
+		return []FilterParam{{Key: "radius", Label: "Radius", Type: FilterParamRange, Min: 1, Max: 50, Step: 1, DefaultVal: "10", HintMin: "soft", HintMax: "heavy"}}
 	case "vignette":
 		return []FilterParam{{Key: "angle", Label: "Amount", Type: FilterParamRange, Min: 0, Max: 1, Step: 0.01, DefaultVal: "0.5", Decimals: 2, HintMin: "none", HintMax: "heavy"}}
 	case "rotate":
 		return []FilterParam{{Key: "angle", Label: "Angle", Type: FilterParamDial, Min: -180, Max: 180, Step: 0.5, DefaultVal: "0", Decimals: 1}}
 	case "speed":
-		return []FilterParam{{Key: "factor", Label: "Factor", Type: FilterParamDial, Min: 0.25, Max: 4, Step: 0.05, DefaultVal: "1", Decimals: 2, HintMin: "slow", HintMax: "fast"}}
+		return []FilterParam{{Key: "factor", Label: "Factor", Type: FilterParamDial, Min: 0.1, Max: 4, Step: 0.05, DefaultVal: "1", Decimals: 2, HintMin: "slow", HintMax: "fast"}}
 	case "fade_in":
 		return []FilterParam{
 			{Key: "duration", Label: "Duration", Type: FilterParamRange, Min: 0.1, Max: 10, Step: 0.1, DefaultVal: "0.5", Decimals: 1},
@@ -482,6 +502,30 @@ func ParamsForFilterType(filterType string, cropOptions []FilterOption) []Filter
 				{Value: "cw_flip", Label: "CW+Flip", Icon: "arrows-spin"},
 			},
 		}}
+	case "ken_burns":
+		return []FilterParam{
+			{Key: "direction", Label: "Move", Type: FilterParamIconSelect, DefaultVal: "in",
+				Options: []FilterOption{
+					{Value: "in", Label: "Zoom In", Icon: "magnifying-glass-plus"},
+					{Value: "out", Label: "Zoom Out", Icon: "magnifying-glass-minus"},
+					{Value: "left", Label: "Pan Left", Icon: "arrow-left"},
+					{Value: "right", Label: "Pan Right", Icon: "arrow-right"},
+					{Value: "up", Label: "Pan Up", Icon: "arrow-up"},
+					{Value: "down", Label: "Pan Down", Icon: "arrow-down"},
+				},
+			},
+			{Key: "zoom", Label: "Zoom", Type: FilterParamRange, Min: 1, Max: 2, Step: 0.05, DefaultVal: "1.2", Decimals: 2, HintMin: "subtle", HintMax: "deep"},
+			{Key: "duration", Label: "Duration", Type: FilterParamNumber, Min: 0, Max: 600, Step: 0.5, DefaultVal: "0", Placeholder: "0 = whole clip"},
+			{Key: "size", Label: "Output", Type: FilterParamSelect, DefaultVal: "1920x1080",
+				Options: []FilterOption{
+					{Value: "1920x1080", Label: "1080p (16:9)"},
+					{Value: "1280x720", Label: "720p (16:9)"},
+					{Value: "3840x2160", Label: "4K (16:9)"},
+					{Value: "1080x1920", Label: "Vertical (9:16)"},
+					{Value: "1080x1080", Label: "Square (1:1)"},
+				},
+			},
+		}
 	case "denoise":
 		return []FilterParam{{
 			Key: "strength", Label: "Level", Type: FilterParamIconSelect, DefaultVal: "medium",
@@ -492,14 +536,42 @@ func ParamsForFilterType(filterType string, cropOptions []FilterOption) []Filter
 			},
 		}}
 	case "normalize":
-		return []FilterParam{{
-			Key: "mode", Label: "Mode", Type: FilterParamIconSelect, DefaultVal: "loudnorm",
-			Options: []FilterOption{
-				{Value: "peak", Label: "Peak", Icon: "mountain"},
-				{Value: "rms", Label: "RMS", Icon: "wave-square"},
-				{Value: "loudnorm", Label: "Loudnorm", Icon: "chart-bar"},
+		// The loudness targets apply to the loudnorm mode (EBU R128); the
+		// presets are common delivery specs and also select that mode.
+		return []FilterParam{
+			{
+				Key: "mode", Label: "Mode", Type: FilterParamIconSelect, DefaultVal: "loudnorm",
+				Options: []FilterOption{
+					{Value: "peak", Label: "Peak", Icon: "mountain"},
+					{Value: "rms", Label: "RMS", Icon: "wave-square"},
+					{Value: "loudnorm", Label: "Loudnorm", Icon: "chart-bar"},
+				},
 			},
-		}}
+			{
+				Key: "_preset", Label: "Target", Type: FilterParamPreset, DefaultVal: "web",
+				Presets: map[string]map[string]string{
+					"ebu_r128":  {"mode": "loudnorm", "i": "-23", "tp": "-1", "lra": "7"},
+					"atsc_a85":  {"mode": "loudnorm", "i": "-24", "tp": "-2", "lra": "7"},
+					"web":       {"mode": "loudnorm", "i": "-16", "tp": "-1.5", "lra": "11"},
+					"streaming": {"mode": "loudnorm", "i": "-14", "tp": "-1", "lra": "11"},
+				},
+				Options: []FilterOption{
+					{Value: "web", Label: "Web / Podcast (-16)"},
+					{Value: "streaming", Label: "Music Streaming (-14)"},
+					{Value: "ebu_r128", Label: "EBU R128 Broadcast (-23)"},
+					{Value: "atsc_a85", Label: "ATSC A/85 Broadcast (-24)"},
+				},
+			},
+			{Key: "i", Label: "LUFS", Type: FilterParamRange, Min: ffmpeg.MinLoudnormI, Max: ffmpeg.MaxLoudnormI, Step: 0.5, DefaultVal: "-16", Decimals: 1, HintMin: "quiet", HintMax: "loud"},
+			{Key: "tp", Label: "Peak dB", Type: FilterParamRange, Min: ffmpeg.MinLoudnormTP, Max: ffmpeg.MaxLoudnormTP, Step: 0.1, DefaultVal: "-1.5", Decimals: 1},
+			{Key: "lra", Label: "LRA", Type: FilterParamRange, Min: ffmpeg.MinLoudnormLRA, Max: ffmpeg.MaxLoudnormLRA, Step: 0.5, DefaultVal: "11", Decimals: 1, HintMin: "even", HintMax: "dynamic"},
+			{Key: "two_pass", Label: "Pass", Type: FilterParamSelect, DefaultVal: "0",
+				Options: []FilterOption{
+					{Value: "0", Label: "Single pass"},
+					{Value: "1", Label: "Measured (two pass)"},
+				},
+			},
+		}
 	case "compressor":
 		return []FilterParam{{
 			Key: "_preset", Label: "Style", Type: FilterParamPreset, DefaultVal: "medium",
@@ -535,6 +607,77 @@ func ParamsForFilterType(filterType string, cropOptions []FilterOption) []Filter
 			{Key: "font_size", Label: "Size", Type: FilterParamRange, Min: 8, Max: 200, Step: 1, DefaultVal: "24", Decimals: 0},
 			{Key: "color", Label: "Color", Type: FilterParamColor, DefaultVal: "#ffffff"},
 		}
+	case "timecode":
+		return []FilterParam{
+			{Key: "source", Label: "Time", Type: FilterParamSelect, DefaultVal: "source",
+				Options: []FilterOption{
+					{Value: "source", Label: "Source video"},
+					{Value: "clip", Label: "From clip start"},
+				},
+			},
+			{Key: "format", Label: "Format", Type: FilterParamSelect, DefaultVal: "hms",
+				Options: []FilterOption{
+					{Value: "hms", Label: "HH:MM:SS.mmm"},
+					{Value: "timecode", Label: "HH:MM:SS:FF"},
+					{Value: "seconds", Label: "Seconds"},
+					{Value: "frames", Label: "Frame number"},
+				},
+			},
+			{Key: "fps", Label: "FPS", Type: FilterParamNumber, Min: 1, Max: 240, Step: 0.001, DefaultVal: "30", Placeholder: "frames & timecode"},
+			{Key: "position", Label: "Pos", Type: FilterParamPositionGrid, DefaultVal: "top-left",
+				Options: []FilterOption{
+					{Value: "top-left", Label: "Top Left"},
+					{Value: "top-center", Label: "Top Center"},
+					{Value: "top-right", Label: "Top Right"},
+					{Value: "center", Label: "Center"},
+					{Value: "bottom-left", Label: "Bottom Left"},
+					{Value: "bottom-center", Label: "Bottom Center"},
+					{Value: "bottom-right", Label: "Bottom Right"},
+				},
+			},
+			{Key: "font_size", Label: "Size", Type: FilterParamRange, Min: 8, Max: 200, Step: 1, DefaultVal: "24", Decimals: 0},
+			{Key: "color", Label: "Color", Type: FilterParamColor, DefaultVal: "#ffffff"},
+		}
+	case "chromakey":
+		// similarity and blend use ffmpeg's 0-1 scale: similarity 0.01 keys
+		// only the exact color and 1 keys everything; blend 0 gives a hard
+		// edge and higher values a partly transparent fringe.
+		return []FilterParam{
+			{Key: "color", Label: "Key", Type: FilterParamColor, DefaultVal: "#00ff00"},
+			{Key: "similarity", Label: "Similar", Type: FilterParamRange, Min: 0.01, Max: 1, Step: 0.01, DefaultVal: "0.1", Decimals: 2, HintMin: "exact", HintMax: "loose"},
+			{Key: "blend", Label: "Blend", Type: FilterParamRange, Min: 0, Max: 1, Step: 0.01, DefaultVal: "0", Decimals: 2, HintMin: "hard", HintMax: "soft"},
+			{Key: "background", Label: "Behind", Type: FilterParamSelect, DefaultVal: "color",
+				Options: []FilterOption{
+					{Value: "color", Label: "Solid color"},
+					{Value: "image", Label: "Uploaded image"},
+				},
+			},
+			{Key: "background_color", Label: "Color", Type: FilterParamColor, DefaultVal: "#000000"},
+			{Key: "image_id", Label: "Image", Type: FilterParamText, DefaultVal: "", Placeholder: "Uploaded image ID"},
+		}
+	case "lut_file":
+		return []FilterParam{{Key: "lut_id", Label: "LUT", Type: FilterParamText, DefaultVal: "", Placeholder: "Uploaded LUT ID"}}
+	case "stabilize":
+		return []FilterParam{{Key: "smoothing", Label: "Smooth", Type: FilterParamRange, Min: ffmpeg.MinStabilizeSmoothing, Max: ffmpeg.MaxStabilizeSmoothing, Step: 1, DefaultVal: "10", Decimals: 0, HintMin: "follow", HintMax: "steady"}}
+	case "subtitles":
+		return []FilterParam{{Key: "lang", Label: "Lang", Type: FilterParamText, DefaultVal: "", Placeholder: "blank = default captions"}}
+	case "image_overlay":
+		return []FilterParam{
+			{Key: "image_id", Label: "Image", Type: FilterParamText, DefaultVal: "", Placeholder: "Uploaded image ID"},
+			{Key: "position", Label: "Pos", Type: FilterParamPositionGrid, DefaultVal: "bottom-right",
+				Options: []FilterOption{
+					{Value: "top-left", Label: "Top Left"},
+					{Value: "top-center", Label: "Top Center"},
+					{Value: "top-right", Label: "Top Right"},
+					{Value: "center", Label: "Center"},
+					{Value: "bottom-left", Label: "Bottom Left"},
+					{Value: "bottom-center", Label: "Bottom Center"},
+					{Value: "bottom-right", Label: "Bottom Right"},
+				},
+			},
+			{Key: "scale", Label: "Width %", Type: FilterParamRange, Min: 1, Max: 100, Step: 1, DefaultVal: "20", Decimals: 0},
+			{Key: "opacity", Label: "Opacity", Type: FilterParamRange, Min: 0.05, Max: 1, Step: 0.05, DefaultVal: "1", Decimals: 2},
+		}
 	case "crop":
 		opts := cropOptions
 		if opts == nil {
@@ -0,0 +1,236 @@
+// Package hls reads HLS media playlists and joins several into one continuous
+// playlist, separating them with discontinuity tags.
+package hls
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Segment is one media segment of a playlist.
+type Segment struct {
+	Duration float64
+	URI      string
+}
+
+// Key is the EXT-X-KEY that encrypts a playlist's segments.
+type Key struct {
+	Method string
+	URI    string
+	// IV is the explicit initialization vector as written, e.g. "0x0123...".
+	// Without one, players derive the IV from the media sequence number,
+	// which concatenation renumbers.
+	IV string
+}
+
+// MediaPlaylist is the subset of an HLS media playlist needed to splice it
+// into another: the init segment, if any, and the media segments in order.
+type MediaPlaylist struct {
+	TargetDuration int
+	// MapURI is the EXT-X-MAP init segment (fMP4 playlists); empty for TS.
+	MapURI string
+	// Key encrypts every segment; nil for clear playlists. KeyBeforeMap is
+	// set when the key was declared ahead of EXT-X-MAP, so it encrypts the
+	// init segment too.
+	Key          *Key
+	KeyBeforeMap bool
+	Segments     []Segment
+	// Ended is set when the playlist carries EXT-X-ENDLIST.
+	Ended bool
+}
+
+// Duration returns the sum of the segment durations in seconds.
+func (p *MediaPlaylist) Duration() float64 {
+	var d float64
+	for _, s := range p.Segments {
+		d += s.Duration
+	}
+	return d
+}
+
+// ParseMedia reads a media playlist. Tags other than those MediaPlaylist
+// records are ignored. A playlist with more than one EXT-X-MAP or EXT-X-KEY,
+// or one that turns out to be a master playlist, is rejected.
+func ParseMedia(r io.Reader) (*MediaPlaylist, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	p := &MediaPlaylist{}
+	sawHeader := false
+	pending := -1.0
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if !sawHeader {
+			if line != "#EXTM3U" {
+				return nil, fmt.Errorf("hls: missing #EXTM3U header")
+			}
+			sawHeader = true
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF"):
+			return nil, fmt.Errorf("hls: master playlist, want media playlist")
+		case strings.HasPrefix(line, "#EXT-X-TARGETDURATION:"):
+			n, err := strconv.Atoi(strings.TrimPrefix(line, "#EXT-X-TARGETDURATION:"))
+			if err != nil {
+				return nil, fmt.Errorf("hls: bad target duration %q", line)
+			}
+			p.TargetDuration = n
+		case strings.HasPrefix(line, "#EXT-X-MAP:"):
+			if p.MapURI != "" {
+				return nil, fmt.Errorf("hls: multiple EXT-X-MAP tags are not supported")
+			}
+			uri := attribute(strings.TrimPrefix(line, "#EXT-X-MAP:"), "URI")
+			if uri == "" {
+				return nil, fmt.Errorf("hls: EXT-X-MAP without URI")
+			}
+			p.MapURI = uri
+		case strings.HasPrefix(line, "#EXT-X-KEY:"):
+			list := strings.TrimPrefix(line, "#EXT-X-KEY:")
+			method := attribute(list, "METHOD")
+			if method == "NONE" && p.Key == nil {
+				continue
+			}
+			if p.Key != nil {
+				return nil, fmt.Errorf("hls: multiple EXT-X-KEY tags are not supported")
+			}
+			uri := attribute(list, "URI")
+			if method == "" || uri == "" {
+				return nil, fmt.Errorf("hls: EXT-X-KEY without METHOD or URI")
+			}
+			p.Key = &Key{Method: method, URI: uri, IV: attribute(list, "IV")}
+			p.KeyBeforeMap = p.MapURI == ""
+		case strings.HasPrefix(line, "#EXTINF:"):
+			raw, _, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			d, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("hls: bad segment duration %q", line)
+			}
+			pending = d
+		case line == "#EXT-X-ENDLIST":
+			p.Ended = true
+		case strings.HasPrefix(line, "#"):
+			// Other tags and comments.
+		default:
+			if pending < 0 {
+				return nil, fmt.Errorf("hls: segment %q without EXTINF", line)
+			}
+			p.Segments = append(p.Segments, Segment{Duration: pending, URI: line})
+			pending = -1
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("hls: read playlist: %w", err)
+	}
+	if !sawHeader {
+		return nil, fmt.Errorf("hls: empty playlist")
+	}
+	return p, nil
+}
+
+// attribute returns the value of name in an attribute list such as
+// `URI="init.mp4",BYTERANGE="720@0"`, unquoted.
+func attribute(list, name string) string {
+	for list != "" {
+		var key, val string
+		key, list, _ = strings.Cut(list, "=")
+		if strings.HasPrefix(list, `"`) {
+			end := strings.Index(list[1:], `"`)
+			if end < 0 {
+				return ""
+			}
+			val, list = list[1:end+1], list[end+2:]
+			list = strings.TrimPrefix(list, ",")
+		} else {
+			val, list, _ = strings.Cut(list, ",")
+		}
+		if strings.TrimSpace(key) == name {
+			return val
+		}
+	}
+	return ""
+}
+
+// Part is one playlist to splice into a concatenation. Relative URIs in the
+// playlist are resolved against Base, which should end in "/".
+type Part struct {
+	Playlist *MediaPlaylist
+	Base     string
+}
+
+// WriteConcat writes parts as one media playlist, with EXT-X-DISCONTINUITY
+// between parts so players reset timestamps and decoders at each boundary.
+// Each part's init segment and key are re-declared after its discontinuity,
+// and a clear part following an encrypted one resets the key to NONE. Keys
+// must carry an explicit IV, since the concatenation renumbers segments. With
+// ended set the result is a VOD playlist; otherwise it is an EVENT playlist
+// that players reload, so later calls may append parts.
+func WriteConcat(w io.Writer, parts []Part, ended bool) error {
+	target := 1
+	for _, part := range parts {
+		if k := part.Playlist.Key; k != nil && k.IV == "" {
+			return fmt.Errorf("hls: key %q has no IV", k.URI)
+		}
+		target = max(target, part.Playlist.TargetDuration)
+		for _, s := range part.Playlist.Segments {
+			// EXTINF durations rounded to the nearest integer must not
+			// exceed the target duration.
+			target = max(target, int(math.Round(s.Duration)))
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "#EXTM3U\n#EXT-X-VERSION:7\n#EXT-X-TARGETDURATION:%d\n#EXT-X-MEDIA-SEQUENCE:0\n", target)
+	if ended {
+		bw.WriteString("#EXT-X-PLAYLIST-TYPE:VOD\n")
+	} else {
+		bw.WriteString("#EXT-X-PLAYLIST-TYPE:EVENT\n")
+	}
+	keyed := false
+	for i, part := range parts {
+		if i > 0 {
+			bw.WriteString("#EXT-X-DISCONTINUITY\n")
+		}
+		key := part.Playlist.Key
+		if key == nil && keyed {
+			bw.WriteString("#EXT-X-KEY:METHOD=NONE\n")
+		}
+		keyed = key != nil
+		if key != nil && part.Playlist.KeyBeforeMap {
+			writeKey(bw, part.Base, key)
+		}
+		if part.Playlist.MapURI != "" {
+			fmt.Fprintf(bw, "#EXT-X-MAP:URI=%q\n", resolve(part.Base, part.Playlist.MapURI))
+		}
+		if key != nil && !part.Playlist.KeyBeforeMap {
+			writeKey(bw, part.Base, key)
+		}
+		for _, s := range part.Playlist.Segments {
+			fmt.Fprintf(bw, "#EXTINF:%.6f,\n%s\n", s.Duration, resolve(part.Base, s.URI))
+		}
+	}
+	if ended {
+		bw.WriteString("#EXT-X-ENDLIST\n")
+	}
+	return bw.Flush()
+}
+
+// writeKey writes an EXT-X-KEY tag for key, resolving its URI against base.
+func writeKey(bw *bufio.Writer, base string, key *Key) {
+	fmt.Fprintf(bw, "#EXT-X-KEY:METHOD=%s,URI=%q,IV=%s\n", key.Method, resolve(base, key.URI), key.IV)
+}
+
+// resolve prefixes base to a relative URI and leaves absolute ones alone.
+func resolve(base, uri string) string {
+	if strings.HasPrefix(uri, "/") || strings.Contains(uri, "://") {
+		return uri
+	}
+	return base + uri
+}
@@ -0,0 +1,142 @@
+package hls
+
+import (
+	"strings"
+	"testing"
+)
+
+const ffmpegPlaylist = `#EXTM3U
+#EXT-X-VERSION:7
+#EXT-X-TARGETDURATION:6
+#EXT-X-MEDIA-SEQUENCE:0
+#EXT-X-PLAYLIST-TYPE:VOD
+#EXT-X-MAP:URI="init.mp4"
+#EXTINF:6.006000,
+seg_00000.m4s
+#EXTINF:4.504500,
+seg_00001.m4s
+#EXT-X-ENDLIST
+`
+
+func TestParseMedia(t *testing.T) {
+	p, err := ParseMedia(strings.NewReader(ffmpegPlaylist))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.TargetDuration != 6 || p.MapURI != "init.mp4" || !p.Ended {
+		t.Fatalf("header = %+v", p)
+	}
+	if len(p.Segments) != 2 || p.Segments[1] != (Segment{Duration: 4.5045, URI: "seg_00001.m4s"}) {
+		t.Fatalf("segments = %+v", p.Segments)
+	}
+
+	for _, bad := range []string{
+		"",
+		"seg.ts\n",
+		"#EXTM3U\nseg.ts\n",
+		"#EXTM3U\n#EXT-X-STREAM-INF:BANDWIDTH=1\nlow.m3u8\n",
+		"#EXTM3U\n#EXT-X-MAP:URI=\"a.mp4\"\n#EXT-X-MAP:URI=\"b.mp4\"\n",
+		"#EXTM3U\n#EXT-X-KEY:METHOD=AES-128\n",
+		"#EXTM3U\n#EXT-X-KEY:METHOD=AES-128,URI=\"a\"\n#EXT-X-KEY:METHOD=AES-128,URI=\"b\"\n",
+	} {
+		if _, err := ParseMedia(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseMedia(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestParseMedia_Key(t *testing.T) {
+	p, err := ParseMedia(strings.NewReader(`#EXTM3U
+#EXT-X-TARGETDURATION:6
+#EXT-X-KEY:METHOD=AES-128,URI="/api/videos/v/hls/key",IV=0x00ff
+#EXT-X-MAP:URI="init.mp4"
+#EXTINF:6.0,
+seg_00000.m4s
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Key{Method: "AES-128", URI: "/api/videos/v/hls/key", IV: "0x00ff"}
+	if p.Key == nil || *p.Key != want || !p.KeyBeforeMap {
+		t.Fatalf("key = %+v, before map = %v", p.Key, p.KeyBeforeMap)
+	}
+
+	plain, err := ParseMedia(strings.NewReader("#EXTM3U\n#EXT-X-KEY:METHOD=NONE\n#EXTINF:1,\na.ts\n"))
+	if err != nil || plain.Key != nil {
+		t.Fatalf("METHOD=NONE: key = %+v, err = %v", plain.Key, err)
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	list := `URI="init,0.mp4",BYTERANGE="720@0",X=1`
+	if got := attribute(list, "URI"); got != "init,0.mp4" {
+		t.Errorf("URI = %q", got)
+	}
+	if got := attribute(list, "X"); got != "1" {
+		t.Errorf("X = %q", got)
+	}
+	if got := attribute(list, "NOPE"); got != "" {
+		t.Errorf("NOPE = %q", got)
+	}
+}
+
+func TestWriteConcat(t *testing.T) {
+	a, _ := ParseMedia(strings.NewReader(ffmpegPlaylist))
+	b := &MediaPlaylist{TargetDuration: 4, MapURI: "init.mp4", Segments: []Segment{{Duration: 7.4, URI: "/abs/seg.m4s"}}}
+
+	var sb strings.Builder
+	if err := WriteConcat(&sb, []Part{{a, "/v/a/"}, {b, "/v/b/"}}, true); err != nil {
+		t.Fatal(err)
+	}
+	want := `#EXTM3U
+#EXT-X-VERSION:7
+#EXT-X-TARGETDURATION:7
+#EXT-X-MEDIA-SEQUENCE:0
+#EXT-X-PLAYLIST-TYPE:VOD
+#EXT-X-MAP:URI="/v/a/init.mp4"
+#EXTINF:6.006000,
+/v/a/seg_00000.m4s
+#EXTINF:4.504500,
+/v/a/seg_00001.m4s
+#EXT-X-DISCONTINUITY
+#EXT-X-MAP:URI="/v/b/init.mp4"
+#EXTINF:7.400000,
+/abs/seg.m4s
+#EXT-X-ENDLIST
+`
+	if sb.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", sb.String(), want)
+	}
+
+	sb.Reset()
+	if err := WriteConcat(&sb, []Part{{a, "/v/a/"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), "#EXT-X-PLAYLIST-TYPE:EVENT") || strings.Contains(sb.String(), "ENDLIST") {
+		t.Fatalf("unfinished playlist:\n%s", sb.String())
+	}
+}
+
+func TestWriteConcat_Key(t *testing.T) {
+	key := &Key{Method: "AES-128", URI: "/api/videos/a/hls/key", IV: "0x01"}
+	a := &MediaPlaylist{MapURI: "init.mp4", Key: key, KeyBeforeMap: true, Segments: []Segment{{Duration: 6, URI: "seg_00000.m4s"}}}
+	b := &MediaPlaylist{MapURI: "init.mp4", Segments: []Segment{{Duration: 6, URI: "seg_00000.m4s"}}}
+
+	var sb strings.Builder
+	if err := WriteConcat(&sb, []Part{{a, "/v/a/"}, {b, "/v/b/"}}, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"#EXT-X-KEY:METHOD=AES-128,URI=\"/api/videos/a/hls/key\",IV=0x01\n#EXT-X-MAP:URI=\"/v/a/init.mp4\"\n",
+		"#EXT-X-DISCONTINUITY\n#EXT-X-KEY:METHOD=NONE\n#EXT-X-MAP:URI=\"/v/b/init.mp4\"\n",
+	} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, sb.String())
+		}
+	}
+
+	noIV := &MediaPlaylist{Key: &Key{Method: "AES-128", URI: "k"}}
+	if err := WriteConcat(&strings.Builder{}, []Part{{noIV, "/"}}, true); err == nil {
+		t.Error("key without IV accepted")
+	}
+}
@@ -0,0 +1,171 @@
+// Package phash computes DCT-based perceptual hashes for near-duplicate
+// detection. Unlike a file hash, two re-encodes of the same footage produce
+// hashes a small Hamming distance apart.
+package phash
+
+import (
+	"errors"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// Size is the edge length of the grayscale input each frame is downscaled to.
+const Size = 32
+
+// lowFreq is the edge length of the low-frequency DCT block kept in the hash.
+const lowFreq = 8
+
+// MaxPairDistance is the largest Hamming distance at which two videos' hashes
+// are stored as a near-duplicate pair; looser matches are not kept.
+const MaxPairDistance = 20
+
+// ErrBadFrame is returned when a frame buffer is not Size*Size bytes.
+var ErrBadFrame = errors.New("phash: frame must be 32x32 8-bit grayscale")
+
+// dctCos[u][x] = cos((2x+1)uπ / 2N), precomputed for the kept coefficients.
+var dctCos = func() [lowFreq][Size]float64 {
+	var t [lowFreq][Size]float64
+	for u := 0; u < lowFreq; u++ {
+		for x := 0; x < Size; x++ {
+			t[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * Size))
+		}
+	}
+	return t
+}()
+
+// Hash returns the 64-bit pHash of a 32x32 8-bit grayscale frame (row-major).
+// Each bit is set when the matching 8x8 low-frequency DCT coefficient is above
+// the median of those coefficients (DC excluded from the median).
+func Hash(frame []byte) (uint64, error) {
+	if len(frame) != Size*Size {
+		return 0, ErrBadFrame
+	}
+
+	// Separable DCT: rows first, then columns, only for the kept frequencies.
+	var rows [Size][lowFreq]float64
+	for y := 0; y < Size; y++ {
+		for u := 0; u < lowFreq; u++ {
+			var sum float64
+			for x := 0; x < Size; x++ {
+				sum += float64(frame[y*Size+x]) * dctCos[u][x]
+			}
+			rows[y][u] = sum
+		}
+	}
+	var coeffs [lowFreq * lowFreq]float64
+	for v := 0; v < lowFreq; v++ {
+		for u := 0; u < lowFreq; u++ {
+			var sum float64
+			for y := 0; y < Size; y++ {
+				sum += rows[y][u] * dctCos[v][y]
+			}
+			coeffs[v*lowFreq+u] = sum
+		}
+	}
+
+	sorted := make([]float64, 0, len(coeffs)-1)
+	sorted = append(sorted, coeffs[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var h uint64
+	for i, c := range coeffs {
+		if c > median {
+			h |= 1 << uint(i)
+		}
+	}
+	return h, nil
+}
+
+// ErrEmptyImage is returned by HashImage for an image with no pixels.
+var ErrEmptyImage = errors.New("phash: empty image")
+
+// HashImage returns the pHash of a still image, such as a thumbnail. The
+// image is box-filtered down to Size×Size luma before hashing, so re-encodes
+// and resizes of the same picture hash close together.
+func HashImage(img image.Image) (uint64, error) {
+	frame, err := grayFrame(img)
+	if err != nil {
+		return 0, err
+	}
+	return Hash(frame)
+}
+
+// grayFrame averages img into a Size×Size 8-bit grayscale frame (Rec. 601 luma).
+func grayFrame(img image.Image) ([]byte, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 {
+		return nil, ErrEmptyImage
+	}
+	var sum [Size * Size]float64
+	var count [Size * Size]int
+	for y := 0; y < h; y++ {
+		cy := y * Size / h
+		for x := 0; x < w; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			i := cy*Size + x*Size/w
+			sum[i] += (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+			count[i]++
+		}
+	}
+	frame := make([]byte, Size*Size)
+	for i := range frame {
+		if count[i] > 0 {
+			frame[i] = byte(math.Round(sum[i] / float64(count[i])))
+			continue
+		}
+		// Images narrower or shorter than Size leave gaps; reuse the
+		// nearest filled cell to the left or above.
+		switch {
+		case i%Size > 0:
+			frame[i] = frame[i-1]
+		case i >= Size:
+			frame[i] = frame[i-Size]
+		}
+	}
+	return frame, nil
+}
+
+// Distance returns the Hamming distance between two hashes (0..64).
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Combine folds per-frame hashes into one video hash by per-bit majority vote,
+// so a few differing frames (intros, watermarks) don't dominate.
+func Combine(frames []uint64) uint64 {
+	if len(frames) == 0 {
+		return 0
+	}
+	var h uint64
+	for bit := 0; bit < 64; bit++ {
+		ones := 0
+		for _, f := range frames {
+			if f&(1<<uint(bit)) != 0 {
+				ones++
+			}
+		}
+		if ones*2 > len(frames) {
+			h |= 1 << uint(bit)
+		}
+	}
+	return h
+}
+
+// SequenceDistance returns the mean Hamming distance between frame hashes
+// sampled at the same relative positions. Sequences of different lengths are
+// compared over their common prefix; -1 means nothing to compare.
+func SequenceDistance(a, b []uint64) float64 {
+	n := min(len(a), len(b))
+	if n == 0 {
+		return -1
+	}
+	total := 0
+	for i := 0; i < n; i++ {
+		total += Distance(a[i], b[i])
+	}
+	return float64(total) / float64(n)
+}
@@ -0,0 +1,112 @@
+package phash
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// scene returns a synthetic frame with smooth low-frequency structure,
+// optionally brightened and noised to mimic a re-encode.
+func scene(offset int, noise bool) []byte {
+	f := make([]byte, Size*Size)
+	for y := 0; y < Size; y++ {
+		for x := 0; x < Size; x++ {
+			v := 128 + 60*math.Sin(float64(x)/5) + 50*math.Cos(float64(y)/3.7) + 30*math.Sin(float64(x+y)/6)
+			v += float64(offset)
+			if noise && (x*7+y*13)%11 == 0 {
+				v += 4
+			}
+			f[y*Size+x] = byte(min(max(v, 0), 255))
+		}
+	}
+	return f
+}
+
+func checkerboard() []byte {
+	f := make([]byte, Size*Size)
+	for y := 0; y < Size; y++ {
+		for x := 0; x < Size; x++ {
+			if (x/4+y/4)%2 == 0 {
+				f[y*Size+x] = 230
+			} else {
+				f[y*Size+x] = 20
+			}
+		}
+	}
+	return f
+}
+
+func mustHash(t *testing.T, frame []byte) uint64 {
+	t.Helper()
+	h, err := Hash(frame)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	return h
+}
+
+func TestHash_RejectsWrongSize(t *testing.T) {
+	if _, err := Hash(make([]byte, 10)); err != ErrBadFrame {
+		t.Fatalf("expected ErrBadFrame, got %v", err)
+	}
+}
+
+func TestHash_SimilarFramesAreClose(t *testing.T) {
+	a := mustHash(t, scene(0, false))
+	b := mustHash(t, scene(8, true))
+	if d := Distance(a, b); d > 10 {
+		t.Fatalf("expected near-identical frames to be close, distance=%d", d)
+	}
+}
+
+func TestHash_DifferentFramesAreFar(t *testing.T) {
+	a := mustHash(t, scene(0, false))
+	b := mustHash(t, checkerboard())
+	if d := Distance(a, b); d < 16 {
+		t.Fatalf("expected different frames to be far apart, distance=%d", d)
+	}
+}
+
+func TestCombine_MajorityVote(t *testing.T) {
+	got := Combine([]uint64{0b1011, 0b0011, 0b1001})
+	if got != 0b1011 {
+		t.Fatalf("Combine = %b, want 1011", got)
+	}
+	if Combine(nil) != 0 {
+		t.Fatalf("Combine(nil) should be 0")
+	}
+}
+
+func TestSequenceDistance(t *testing.T) {
+	if d := SequenceDistance([]uint64{0, 0xFF}, []uint64{0, 0x0F, 1}); d != 2 {
+		t.Fatalf("SequenceDistance = %v, want 2", d)
+	}
+	if d := SequenceDistance(nil, []uint64{1}); d != -1 {
+		t.Fatalf("SequenceDistance with empty input = %v, want -1", d)
+	}
+}
+
+func TestHashImage_MatchesResizedFrame(t *testing.T) {
+	// A 4x upscale of the scene (as a thumbnail would be) hashes like the frame.
+	frame := scene(0, false)
+	img := image.NewRGBA(image.Rect(0, 0, Size*4, Size*4))
+	for y := 0; y < Size*4; y++ {
+		for x := 0; x < Size*4; x++ {
+			v := frame[(y/4)*Size+x/4]
+			img.Set(x, y, color.RGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	got, err := HashImage(img)
+	if err != nil {
+		t.Fatalf("HashImage: %v", err)
+	}
+	if d := Distance(got, mustHash(t, frame)); d > 2 {
+		t.Fatalf("upscaled image hashed %d bits from its frame", d)
+	}
+
+	if _, err := HashImage(image.NewRGBA(image.Rect(0, 0, 0, 0))); err != ErrEmptyImage {
+		t.Fatalf("expected ErrEmptyImage, got %v", err)
+	}
+}
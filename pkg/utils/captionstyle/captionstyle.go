@@ -0,0 +1,187 @@
+// Package captionstyle holds per-user caption display preferences and applies
+// them to WebVTT files for in-app playback.
+package captionstyle
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Style is a user's caption display preference. Empty fields keep whatever
+// the VTT and the browser would do by default.
+type Style struct {
+	FontSize   string
+	Color      string
+	Background string
+	Position   string
+}
+
+// Option is one selectable value for a Style field.
+type Option struct {
+	Value string
+	Label string
+}
+
+// FontSizes, Colors, Backgrounds and Positions list the accepted values for
+// each field, in display order. The empty value means "default".
+var (
+	FontSizes = []Option{
+		{"", "Default"},
+		{"small", "Small"},
+		{"large", "Large"},
+		{"xlarge", "Extra large"},
+	}
+	Colors = []Option{
+		{"", "Default"},
+		{"white", "White"},
+		{"yellow", "Yellow"},
+		{"cyan", "Cyan"},
+		{"green", "Green"},
+	}
+	Backgrounds = []Option{
+		{"", "Default"},
+		{"none", "None"},
+		{"translucent", "Translucent black"},
+		{"opaque", "Solid black"},
+	}
+	Positions = []Option{
+		{"", "Default"},
+		{"bottom", "Bottom"},
+		{"top", "Top"},
+	}
+)
+
+var (
+	fontSizeCSS = map[string]string{"small": "80%", "large": "130%", "xlarge": "160%"}
+	colorCSS    = map[string]string{"white": "#ffffff", "yellow": "#ffff00", "cyan": "#00ffff", "green": "#00ff00"}
+	bgCSS       = map[string]string{"none": "transparent", "translucent": "rgba(0, 0, 0, 0.6)", "opaque": "#000000"}
+	// Cue line settings for each position. Percentages keep cues clear of
+	// the player controls.
+	positionLine = map[string]string{"bottom": "line:90%", "top": "line:10%"}
+)
+
+// Normalize returns s with unknown values reset to the default, so stored
+// preferences from older releases never produce bad CSS.
+func Normalize(s Style) Style {
+	return Style{
+		FontSize:   valid(FontSizes, s.FontSize),
+		Color:      valid(Colors, s.Color),
+		Background: valid(Backgrounds, s.Background),
+		Position:   valid(Positions, s.Position),
+	}
+}
+
+// Valid reports whether every field of s is an accepted value.
+func Valid(s Style) bool {
+	return Normalize(s) == s
+}
+
+// IsDefault reports whether s leaves every field at its default.
+func (s Style) IsDefault() bool {
+	return s == Style{}
+}
+
+// CSS returns the ::cue rule for s, or "" when it sets no properties.
+func (s Style) CSS() string {
+	decls := s.declarations()
+	if len(decls) == 0 {
+		return ""
+	}
+	return "::cue {\n  " + strings.Join(decls, "\n  ") + "\n}"
+}
+
+// CueCSS returns the ::cue rule for s scoped to the video elements matching
+// selector, for a page stylesheet, or "" when s sets no properties. Firefox
+// ignores STYLE blocks inside a VTT file, so pages style their players with
+// this rule as well.
+func (s Style) CueCSS(selector string) string {
+	decls := s.declarations()
+	if len(decls) == 0 {
+		return ""
+	}
+	return selector + "::cue { " + strings.Join(decls, " ") + " }"
+}
+
+// declarations returns the CSS declarations for s, in a fixed order.
+func (s Style) declarations() []string {
+	s = Normalize(s)
+	var decls []string
+	if v, ok := fontSizeCSS[s.FontSize]; ok {
+		decls = append(decls, "font-size: "+v+";")
+	}
+	if v, ok := colorCSS[s.Color]; ok {
+		decls = append(decls, "color: "+v+";")
+	}
+	if v, ok := bgCSS[s.Background]; ok {
+		decls = append(decls, "background-color: "+v+";")
+	}
+	return decls
+}
+
+// lineSettingRe matches an existing "line:" cue setting.
+var lineSettingRe = regexp.MustCompile(`(^|\s)line:\S+`)
+
+// Apply returns vtt with s applied: a STYLE block holding the ::cue rule
+// (honoured by Chrome and Safari; see CueCSS for Firefox),
+// placed after any existing STYLE/NOTE/REGION blocks so it takes precedence,
+// and a line setting on every cue when a position is set. The input is
+// returned unchanged for the default style or if it does not look like
+// WebVTT.
+func Apply(vtt []byte, s Style) []byte {
+	s = Normalize(s)
+	if s.IsDefault() {
+		return vtt
+	}
+	text := strings.ReplaceAll(string(vtt), "\r\n", "\n")
+	if !strings.HasPrefix(strings.TrimPrefix(text, "\ufeff"), "WEBVTT") {
+		return vtt
+	}
+
+	blocks := strings.Split(text, "\n\n")
+	out := make([]string, 0, len(blocks)+1)
+	css := s.CSS()
+	inserted := css == ""
+	line := positionLine[s.Position]
+	for i, block := range blocks {
+		isCue := i > 0 && strings.Contains(block, "-->")
+		if isCue && !inserted {
+			out = append(out, "STYLE\n"+css)
+			inserted = true
+		}
+		if isCue && line != "" {
+			block = setCueLine(block, line)
+		}
+		out = append(out, block)
+	}
+	if !inserted {
+		// No cues at all; the style still belongs after the header.
+		out = append(out[:1], append([]string{"STYLE\n" + css}, out[1:]...)...)
+	}
+	return []byte(strings.Join(out, "\n\n"))
+}
+
+// setCueLine replaces or adds the line setting on a cue's timing line.
+func setCueLine(block, line string) string {
+	lines := strings.Split(block, "\n")
+	for i, l := range lines {
+		if !strings.Contains(l, "-->") {
+			continue
+		}
+		if lineSettingRe.MatchString(l) {
+			lines[i] = lineSettingRe.ReplaceAllString(l, "${1}"+line)
+		} else {
+			lines[i] = strings.TrimRight(l, " \t") + " " + line
+		}
+		break
+	}
+	return strings.Join(lines, "\n")
+}
+
+func valid(opts []Option, v string) string {
+	for _, o := range opts {
+		if o.Value == v {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package captionstyle
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleVTT = "WEBVTT\nKind: captions\nLanguage: en\n\n" +
+	"STYLE\n::cue { color: lime; }\n\n" +
+	"00:00:01.000 --> 00:00:02.000 align:start position:0%\nhello\n\n" +
+	"00:00:03.000 --> 00:00:04.000 line:0\nworld\n"
+
+func TestApply_DefaultIsUnchanged(t *testing.T) {
+	if got := string(Apply([]byte(sampleVTT), Style{})); got != sampleVTT {
+		t.Fatalf("Apply(default) changed the VTT:\n%s", got)
+	}
+}
+
+func TestApply_InsertsStyleBeforeFirstCue(t *testing.T) {
+	got := string(Apply([]byte(sampleVTT), Style{FontSize: "large", Color: "yellow", Background: "opaque"}))
+	want := "STYLE\n::cue {\n  font-size: 130%;\n  color: #ffff00;\n  background-color: #000000;\n}\n\n00:00:01.000"
+	if !strings.Contains(got, want) {
+		t.Fatalf("style block missing or misplaced:\n%s", got)
+	}
+	if strings.Index(got, "color: lime") > strings.Index(got, "color: #ffff00") {
+		t.Fatalf("user style must come after the source style:\n%s", got)
+	}
+	if strings.Contains(got, "line:") != strings.Contains(sampleVTT, "line:") {
+		t.Fatalf("cue settings changed without a position:\n%s", got)
+	}
+}
+
+func TestApply_Position(t *testing.T) {
+	got := string(Apply([]byte(sampleVTT), Style{Position: "top"}))
+	if strings.Contains(got, "STYLE\n::cue {\n") {
+		t.Fatalf("no CSS expected for a position-only style:\n%s", got)
+	}
+	if !strings.Contains(got, "00:00:01.000 --> 00:00:02.000 align:start position:0% line:10%\n") {
+		t.Fatalf("line setting not added:\n%s", got)
+	}
+	if !strings.Contains(got, "00:00:03.000 --> 00:00:04.000 line:10%\n") {
+		t.Fatalf("existing line setting not replaced:\n%s", got)
+	}
+}
+
+func TestApply_NotVTT(t *testing.T) {
+	in := "1\n00:00:01,000 --> 00:00:02,000\nhi\n"
+	if got := string(Apply([]byte(in), Style{Color: "white"})); got != in {
+		t.Fatalf("non-VTT input changed:\n%s", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got := Normalize(Style{FontSize: "huge", Color: "cyan", Background: "<script>", Position: "top"})
+	want := Style{Color: "cyan", Position: "top"}
+	if got != want {
+		t.Fatalf("Normalize = %+v, want %+v", got, want)
+	}
+	if Valid(Style{FontSize: "huge"}) {
+		t.Fatal("Valid accepted an unknown font size")
+	}
+}
+
+func TestCueCSS(t *testing.T) {
+	got := Style{Color: "yellow", Background: "none", Position: "top"}.CueCSS("#videoPlayer")
+	want := "#videoPlayer::cue { color: #ffff00; background-color: transparent; }"
+	if got != want {
+		t.Fatalf("CueCSS = %q, want %q", got, want)
+	}
+	if got := (Style{Position: "top"}).CueCSS("#videoPlayer"); got != "" {
+		t.Fatalf("CueCSS for a position-only style = %q, want empty", got)
+	}
+	if got := (Style{Color: "</style>"}).CueCSS("video"); got != "" {
+		t.Fatalf("CueCSS let an unknown value through: %q", got)
+	}
+}
@@ -1,6 +1,7 @@
-// Package commentfmt provides helpers for rendering video comment text:
-// linkifying inline timestamps (so they can seek the player) and safely
-// rendering ts_headline search highlights.
+// Package commentfmt provides helpers for rendering video comment and
+// description text: linkifying inline timestamps (so they can seek the
+// player), extracting description chapter lists, and safely rendering
+// ts_headline search highlights.
 package commentfmt
 
 import (
@@ -80,3 +80,47 @@ func TestSafeHighlight_NoInjection(t *testing.T) {
 		t.Fatalf("script not neutralised: %q", got)
 	}
 }
+
+func TestParseDescriptionTimestamps(t *testing.T) {
+	desc := "Thanks for watching!\n" +
+		"0:00 Intro\n" +
+		"1:23 - 4:56 | Main part\n" +
+		"1:02:03 Outro (bonus at 1:05:00)\n" +
+		"Aspect 16:9, bad 9:99\n" +
+		"5:00 - 4:00 backwards\n"
+	got := ParseDescriptionTimestamps(desc)
+
+	type want struct {
+		label string
+		secs  float64
+		end   float64
+		title string
+	}
+	wants := []want{
+		{"0:00", 0, 0, "Intro"},
+		{"1:23 - 4:56", 83, 296, "Main part"},
+		{"1:02:03", 3723, 0, "Outro (bonus at"},
+		{"1:05:00", 3900, 0, "Outro (bonus at"},
+		{"5:00", 300, 0, "backwards"},
+		{"4:00", 240, 0, "backwards"},
+	}
+	if len(got) != len(wants) {
+		t.Fatalf("got %d timestamps, want %d: %#v", len(got), len(wants), got)
+	}
+	for i, w := range wants {
+		g := got[i]
+		if g.Label != w.label || g.Seconds != w.secs || g.Title != w.title {
+			t.Errorf("[%d] got %q %v %q, want %q %v %q", i, g.Label, g.Seconds, g.Title, w.label, w.secs, w.title)
+		}
+		switch {
+		case w.end == 0 && g.EndSeconds != nil:
+			t.Errorf("[%d] unexpected end %v", i, *g.EndSeconds)
+		case w.end != 0 && (g.EndSeconds == nil || *g.EndSeconds != w.end):
+			t.Errorf("[%d] end = %v, want %v", i, g.EndSeconds, w.end)
+		}
+	}
+
+	if got := ParseDescriptionTimestamps("no times here"); got != nil {
+		t.Errorf("expected nil, got %#v", got)
+	}
+}
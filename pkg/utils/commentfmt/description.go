@@ -0,0 +1,89 @@
+package commentfmt
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DescriptionTimestamp is a timestamp found in a video description, usually
+// one line of a chapter list ("0:00 Intro", "1:23 - 4:56 Main part").
+// EndSeconds is set only for explicit ranges.
+type DescriptionTimestamp struct {
+	Label      string   `json:"label"`
+	Seconds    float64  `json:"seconds"`
+	EndSeconds *float64 `json:"end_seconds,omitempty"`
+	Title      string   `json:"title"`
+}
+
+// tsRangeRe matches two timestamps joined by a dash, tilde or "to", as in
+// "1:23 - 4:56" or "1:23–4:56".
+var tsRangeRe = regexp.MustCompile(`(` + tsRe.String() + `)\s*(?:-|–|—|~|to)\s*(` + tsRe.String() + `)`)
+
+// titleTrim is the punctuation left around a title once its timestamps are cut
+// out of the line.
+const titleTrim = " \t-–—~:|•·()[]"
+
+// ParseDescriptionTimestamps returns every valid timestamp in a description,
+// in order. Each line's text without its timestamps becomes the title. A
+// range yields one entry with EndSeconds set. Invalid times (e.g. "9:99") and
+// ranges that end before they start are ignored.
+func ParseDescriptionTimestamps(text string) []DescriptionTimestamp {
+	var out []DescriptionTimestamp
+	for _, line := range strings.Split(text, "\n") {
+		var found []DescriptionTimestamp
+		var spans [][]int
+
+		for _, m := range tsRangeRe.FindAllStringSubmatchIndex(line, -1) {
+			start, okStart := parseTimestamp(line[m[2]:m[3]])
+			end, okEnd := parseTimestamp(line[m[4]:m[5]])
+			if !okStart || !okEnd || end <= start {
+				continue
+			}
+			found = append(found, DescriptionTimestamp{Label: line[m[0]:m[1]], Seconds: start, EndSeconds: &end})
+			spans = append(spans, m[:2])
+		}
+		for _, loc := range tsRe.FindAllStringIndex(line, -1) {
+			if insideSpan(loc, spans) {
+				continue
+			}
+			secs, ok := parseTimestamp(line[loc[0]:loc[1]])
+			if !ok {
+				continue
+			}
+			found = append(found, DescriptionTimestamp{Label: line[loc[0]:loc[1]], Seconds: secs})
+			spans = append(spans, loc)
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		title := line
+		for _, ts := range found {
+			title = strings.Replace(title, ts.Label, " ", 1)
+		}
+		title = strings.Trim(strings.Join(strings.Fields(title), " "), titleTrim)
+		for i := range found {
+			found[i].Title = title
+		}
+		// Ranges were collected first; put everything back in line order.
+		order := make([]int, len(found))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return spans[order[a]][0] < spans[order[b]][0] })
+		for _, i := range order {
+			out = append(out, found[i])
+		}
+	}
+	return out
+}
+
+func insideSpan(loc []int, spans [][]int) bool {
+	for _, s := range spans {
+		if loc[0] >= s[0] && loc[1] <= s[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// Package cookiedomains holds the per-user cookie domain filter that decides
+// which imported cookies are stored at all.
+package cookiedomains
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxEntries bounds the length of each list.
+const maxEntries = 200
+
+// domainRe matches a normalized list entry: dot-separated labels of letters,
+// digits and hyphens.
+var domainRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// Filter is a user's cookie domain filter. Entries are bare domains and also
+// match their subdomains, so "google.com" covers ".accounts.google.com". When
+// Allow is non-empty only matching domains are kept; Block always wins.
+type Filter struct {
+	Allow []string
+	Block []string
+}
+
+// IsZero reports whether f keeps every cookie.
+func (f Filter) IsZero() bool {
+	return len(f.Allow) == 0 && len(f.Block) == 0
+}
+
+// Keep reports whether a cookie for domain (the first field of a Netscape
+// cookie line) should be stored.
+func (f Filter) Keep(domain string) bool {
+	d := normalize(domain)
+	for _, b := range f.Block {
+		if matches(d, b) {
+			return false
+		}
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, a := range f.Allow {
+		if matches(d, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseList parses a list of domains separated by commas, spaces or newlines,
+// as typed into the settings form. Entries are lowercased, leading "." and
+// "*." are dropped and duplicates removed. An empty input yields an empty,
+// non-nil list.
+func ParseList(raw string) ([]string, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	out := []string{}
+	seen := map[string]bool{}
+	for _, f := range fields {
+		d := normalize(f)
+		if d == "" {
+			continue
+		}
+		if !domainRe.MatchString(d) {
+			return nil, fmt.Errorf("%q is not a domain", f)
+		}
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		out = append(out, d)
+	}
+	if len(out) > maxEntries {
+		return nil, fmt.Errorf("at most %d domains per list", maxEntries)
+	}
+	return out, nil
+}
+
+// normalize lowercases a domain and strips wildcard and leading-dot prefixes.
+func normalize(domain string) string {
+	d := strings.ToLower(strings.TrimSpace(domain))
+	d = strings.TrimPrefix(d, "*.")
+	return strings.Trim(d, ".")
+}
+
+// matches reports whether domain is entry or one of its subdomains.
+func matches(domain, entry string) bool {
+	return domain == entry || strings.HasSuffix(domain, "."+entry)
+}
@@ -0,0 +1,48 @@
+package cookiedomains
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseList(t *testing.T) {
+	got, err := ParseList(" .YouTube.com, *.google.com\nbank.example  youtube.com ")
+	if err != nil {
+		t.Fatalf("ParseList: %v", err)
+	}
+	want := []string{"youtube.com", "google.com", "bank.example"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseList = %v, want %v", got, want)
+	}
+
+	if got, err := ParseList("  "); err != nil || got == nil || len(got) != 0 {
+		t.Fatalf("ParseList(blank) = %v, %v; want empty list", got, err)
+	}
+	for _, bad := range []string{"https://youtube.com", "you tube.com/x", "a_b.com", "-x.com"} {
+		if _, err := ParseList(bad); err == nil {
+			t.Errorf("ParseList(%q) should fail", bad)
+		}
+	}
+}
+
+func TestFilterKeep(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		domain string
+		want   bool
+	}{
+		{"empty filter keeps all", Filter{}, ".youtube.com", true},
+		{"blocked exact", Filter{Block: []string{"bank.example"}}, "bank.example", false},
+		{"blocked subdomain", Filter{Block: []string{"bank.example"}}, ".login.bank.example", false},
+		{"suffix is not a subdomain", Filter{Block: []string{"bank.example"}}, "mybank.example", true},
+		{"allowlisted", Filter{Allow: []string{"youtube.com"}}, ".YOUTUBE.com", true},
+		{"not allowlisted", Filter{Allow: []string{"youtube.com"}}, ".google.com", false},
+		{"block beats allow", Filter{Allow: []string{"google.com"}, Block: []string{"mail.google.com"}}, ".mail.google.com", false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Keep(tt.domain); got != tt.want {
+			t.Errorf("%s: Keep(%q) = %v, want %v", tt.name, tt.domain, got, tt.want)
+		}
+	}
+}
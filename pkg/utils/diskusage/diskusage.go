@@ -0,0 +1,46 @@
+//go:build linux || darwin
+
+// Package diskusage reports filesystem capacity for a path.
+package diskusage
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Usage is a point-in-time capacity snapshot of the filesystem holding Path.
+type Usage struct {
+	Path       string
+	TotalBytes uint64
+	FreeBytes  uint64 // Available to unprivileged users (excludes root reserve)
+}
+
+// UsedBytes returns TotalBytes minus FreeBytes.
+func (u Usage) UsedBytes() uint64 {
+	if u.FreeBytes > u.TotalBytes {
+		return 0
+	}
+	return u.TotalBytes - u.FreeBytes
+}
+
+// FreePercent returns free space as a percentage of total (0-100).
+func (u Usage) FreePercent() float64 {
+	if u.TotalBytes == 0 {
+		return 0
+	}
+	return float64(u.FreeBytes) / float64(u.TotalBytes) * 100
+}
+
+// Get returns the capacity of the filesystem containing path.
+func Get(path string) (Usage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Usage{}, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	bsize := uint64(st.Bsize)
+	return Usage{
+		Path:       path,
+		TotalBytes: uint64(st.Blocks) * bsize,
+		FreeBytes:  uint64(st.Bavail) * bsize,
+	}, nil
+}
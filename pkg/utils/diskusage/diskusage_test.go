@@ -0,0 +1,37 @@
+//go:build linux || darwin
+
+package diskusage
+
+import "testing"
+
+func TestGet_TempDir(t *testing.T) {
+	u, err := Get(t.TempDir())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if u.TotalBytes == 0 {
+		t.Fatalf("expected non-zero total bytes")
+	}
+	if u.FreeBytes > u.TotalBytes {
+		t.Fatalf("free %d exceeds total %d", u.FreeBytes, u.TotalBytes)
+	}
+	if p := u.FreePercent(); p < 0 || p > 100 {
+		t.Fatalf("FreePercent out of range: %v", p)
+	}
+}
+
+func TestGet_MissingPath(t *testing.T) {
+	if _, err := Get("/definitely/not/a/real/path"); err == nil {
+		t.Fatalf("expected error for missing path")
+	}
+}
+
+func TestUsage_Derived(t *testing.T) {
+	u := Usage{TotalBytes: 200, FreeBytes: 50}
+	if u.UsedBytes() != 150 {
+		t.Fatalf("UsedBytes = %d, want 150", u.UsedBytes())
+	}
+	if u.FreePercent() != 25 {
+		t.Fatalf("FreePercent = %v, want 25", u.FreePercent())
+	}
+}
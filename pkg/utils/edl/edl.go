@@ -0,0 +1,242 @@
+// Package edl describes a clip as a portable edit decision list so it can be
+// rebuilt in an external editor. The JSON form (Clip) is the full description;
+// CMX3600 and FCPXML carry only the source and in/out points, since neither
+// has a portable way to express Rewind filters or crops.
+package edl
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+
+	"thirdcoast.systems/rewind/pkg/filters"
+	"thirdcoast.systems/rewind/pkg/utils/crops"
+)
+
+// Version is the schema version of the JSON clip description. It is bumped
+// when a field changes meaning; new optional fields do not bump it.
+const Version = 1
+
+// DefaultFPS is used for timecodes when the source frame rate is unknown.
+const DefaultFPS = 30.0
+
+// recordStart is the record-side timecode the single event starts at, the
+// usual 01:00:00:00 programme start.
+const recordStart = 3600.0
+
+// Clip is the JSON clip description served by GET /api/clips/:id/edl.
+// Times are seconds from the start of the source video.
+type Clip struct {
+	Version     int                        `json:"version"`
+	ID          string                     `json:"id"`
+	Title       string                     `json:"title"`
+	Description string                     `json:"description,omitempty"`
+	Source      Source                     `json:"source"`
+	In          float64                    `json:"in"`
+	Out         float64                    `json:"out"`
+	Duration    float64                    `json:"duration"`
+	FPS         float64                    `json:"fps"`
+	Filters     []filters.FilterStackEntry `json:"filters"`
+	Crops       []crops.Crop               `json:"crops"`
+}
+
+// Source identifies the archived video a clip was cut from.
+type Source struct {
+	VideoID  string  `json:"video_id"`
+	Title    string  `json:"title"`
+	URL      string  `json:"url"`
+	Filename string  `json:"filename,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// Normalize fills defaults so every encoder sees the same clip: empty filter
+// and crop lists become [] rather than null, and an unknown frame rate falls
+// back to DefaultFPS.
+func (c *Clip) Normalize() {
+	c.Version = Version
+	if c.Filters == nil {
+		c.Filters = []filters.FilterStackEntry{}
+	}
+	if c.Crops == nil {
+		c.Crops = []crops.Crop{}
+	}
+	if c.FPS <= 0 {
+		c.FPS = DefaultFPS
+	}
+	if c.Out < c.In {
+		c.Out = c.In
+	}
+	c.Duration = c.Out - c.In
+}
+
+// Timecode formats seconds as a non-drop-frame HH:MM:SS:FF timecode. Frames
+// are counted at fps and labelled at the nearest whole rate, which is how
+// NDF timecode is written for 29.97 and 23.976 material.
+func Timecode(seconds, fps float64) string {
+	if fps <= 0 {
+		fps = DefaultFPS
+	}
+	if seconds < 0 {
+		seconds = 0
+	}
+	nominal := int(math.Round(fps))
+	frames := int(math.Round(seconds * fps))
+	ff := frames % nominal
+	totalSeconds := frames / nominal
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", totalSeconds/3600, totalSeconds/60%60, totalSeconds%60, ff)
+}
+
+// CMX3600 renders the clip as a one-event CMX3600 EDL.
+func CMX3600(c Clip) string {
+	c.Normalize()
+	reel := "AX"
+	var b strings.Builder
+	fmt.Fprintf(&b, "TITLE: %s\n", edlLine(c.Title))
+	b.WriteString("FCM: NON-DROP FRAME\n\n")
+	fmt.Fprintf(&b, "001  %-8s AA/V  C        %s %s %s %s\n",
+		reel,
+		Timecode(c.In, c.FPS), Timecode(c.Out, c.FPS),
+		Timecode(recordStart, c.FPS), Timecode(recordStart+c.Duration, c.FPS))
+	if c.Source.Filename != "" {
+		fmt.Fprintf(&b, "* FROM CLIP NAME: %s\n", edlLine(c.Source.Filename))
+	}
+	if c.Source.URL != "" {
+		fmt.Fprintf(&b, "* SOURCE URL: %s\n", edlLine(c.Source.URL))
+	}
+	return b.String()
+}
+
+// edlLine keeps a value on one EDL line.
+func edlLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// FCPXML renders the clip as an FCPXML 1.9 project with a single asset-clip.
+func FCPXML(c Clip) ([]byte, error) {
+	c.Normalize()
+	num, den := frameDuration(c.FPS)
+	t := func(seconds float64) string {
+		frames := int64(math.Round(seconds * c.FPS))
+		if frames == 0 {
+			return "0s"
+		}
+		return fmt.Sprintf("%d/%ds", frames*num, den)
+	}
+
+	name := c.Title
+	if name == "" {
+		name = c.ID
+	}
+	sourceDur := c.Source.Duration
+	if sourceDur < c.Out {
+		sourceDur = c.Out
+	}
+
+	doc := fcpxml{
+		Version: "1.9",
+		Resources: fcpxResources{
+			Format: fcpxFormat{ID: "r1", FrameDuration: fmt.Sprintf("%d/%ds", num, den)},
+			Asset: fcpxAsset{
+				ID: "r2", Name: c.Source.Title, Start: "0s", Duration: t(sourceDur),
+				HasVideo: "1", HasAudio: "1", Format: "r1",
+				MediaRep: fcpxMediaRep{Kind: "original-media", Src: c.Source.Filename},
+			},
+		},
+		Library: fcpxLibrary{Event: fcpxEvent{
+			Name: "Rewind",
+			Project: fcpxProject{
+				Name: name,
+				Sequence: fcpxSequence{
+					Format: "r1", Duration: t(c.Duration), TCStart: "0s",
+					Spine: fcpxSpine{Clip: fcpxAssetClip{
+						Ref: "r2", Name: name, Offset: "0s", Start: t(c.In), Duration: t(c.Duration),
+					}},
+				},
+			},
+		}},
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal fcpxml: %w", err)
+	}
+	return append([]byte(xml.Header+"<!DOCTYPE fcpxml>\n"), append(out, '\n')...), nil
+}
+
+// frameDuration returns the FCPXML frame duration for fps as a rational,
+// using the NTSC 1001 denominators for the fractional broadcast rates.
+func frameDuration(fps float64) (int64, int64) {
+	for _, nominal := range []float64{24, 30, 60} {
+		if math.Abs(fps-nominal*1000/1001) < 0.01 {
+			return 1001, int64(nominal) * 1000
+		}
+	}
+	return 1, int64(math.Round(fps))
+}
+
+type fcpxml struct {
+	XMLName   xml.Name      `xml:"fcpxml"`
+	Version   string        `xml:"version,attr"`
+	Resources fcpxResources `xml:"resources"`
+	Library   fcpxLibrary   `xml:"library"`
+}
+
+type fcpxResources struct {
+	Format fcpxFormat `xml:"format"`
+	Asset  fcpxAsset  `xml:"asset"`
+}
+
+type fcpxFormat struct {
+	ID            string `xml:"id,attr"`
+	FrameDuration string `xml:"frameDuration,attr"`
+}
+
+type fcpxAsset struct {
+	ID       string       `xml:"id,attr"`
+	Name     string       `xml:"name,attr"`
+	Start    string       `xml:"start,attr"`
+	Duration string       `xml:"duration,attr"`
+	HasVideo string       `xml:"hasVideo,attr"`
+	HasAudio string       `xml:"hasAudio,attr"`
+	Format   string       `xml:"format,attr"`
+	MediaRep fcpxMediaRep `xml:"media-rep"`
+}
+
+type fcpxMediaRep struct {
+	Kind string `xml:"kind,attr"`
+	Src  string `xml:"src,attr"`
+}
+
+type fcpxLibrary struct {
+	Event fcpxEvent `xml:"event"`
+}
+
+type fcpxEvent struct {
+	Name    string      `xml:"name,attr"`
+	Project fcpxProject `xml:"project"`
+}
+
+type fcpxProject struct {
+	Name     string       `xml:"name,attr"`
+	Sequence fcpxSequence `xml:"sequence"`
+}
+
+type fcpxSequence struct {
+	Format   string    `xml:"format,attr"`
+	Duration string    `xml:"duration,attr"`
+	TCStart  string    `xml:"tcStart,attr"`
+	Spine    fcpxSpine `xml:"spine"`
+}
+
+type fcpxSpine struct {
+	Clip fcpxAssetClip `xml:"asset-clip"`
+}
+
+type fcpxAssetClip struct {
+	Ref      string `xml:"ref,attr"`
+	Name     string `xml:"name,attr"`
+	Offset   string `xml:"offset,attr"`
+	Start    string `xml:"start,attr"`
+	Duration string `xml:"duration,attr"`
+}
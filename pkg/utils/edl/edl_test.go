@@ -0,0 +1,121 @@
+package edl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTimecode(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		fps     float64
+		want    string
+	}{
+		{0, 30, "00:00:00:00"},
+		{1.5, 30, "00:00:01:15"},
+		{3661.2, 25, "01:01:01:05"},
+		{10, 0, "00:00:10:00"},
+		{-4, 30, "00:00:00:00"},
+		// 29.97 frames round to 30, labelled at the nominal 30.
+		{1, 29.97, "00:00:01:00"},
+	}
+	for _, tt := range tests {
+		if got := Timecode(tt.seconds, tt.fps); got != tt.want {
+			t.Errorf("Timecode(%v, %v) = %q, want %q", tt.seconds, tt.fps, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize_EmptyStacks(t *testing.T) {
+	c := Clip{ID: "c1", In: 10, Out: 4}
+	c.Normalize()
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	if !strings.Contains(s, `"filters":[]`) || !strings.Contains(s, `"crops":[]`) {
+		t.Errorf("empty stacks should encode as [], got %s", s)
+	}
+	if c.FPS != DefaultFPS || c.Duration != 0 || c.Version != Version {
+		t.Errorf("Normalize defaults: fps=%v duration=%v version=%v", c.FPS, c.Duration, c.Version)
+	}
+}
+
+func TestCMX3600(t *testing.T) {
+	out := CMX3600(Clip{
+		Title:  "My\nClip",
+		In:     12,
+		Out:    14.5,
+		FPS:    30,
+		Source: Source{Filename: "abc.mp4"},
+	})
+	for _, want := range []string{
+		"TITLE: My Clip\n",
+		"FCM: NON-DROP FRAME\n",
+		"001  AX       AA/V  C        00:00:12:00 00:00:14:15 01:00:00:00 01:00:02:15\n",
+		"* FROM CLIP NAME: abc.mp4\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("CMX3600 missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFCPXML(t *testing.T) {
+	out, err := FCPXML(Clip{
+		ID:     "c1",
+		Title:  "A & B",
+		In:     2,
+		Out:    4,
+		FPS:    29.97,
+		Source: Source{Title: "src", Filename: "abc.mp4", Duration: 60},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(out)
+	for _, want := range []string{
+		`<fcpxml version="1.9">`,
+		`frameDuration="1001/30000s"`,
+		`name="A &amp; B"`,
+		`start="60060/30000s"`,
+		`duration="60060/30000s"`,
+		`src="abc.mp4"`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("FCPXML missing %q in:\n%s", want, s)
+		}
+	}
+}
+
+func TestParseEdits(t *testing.T) {
+	// A single clip from GET /api/clips/:id/edl is a one-clip document.
+	e, err := ParseEdits([]byte(`{"version":1,"id":"c1","title":"Intro","in":2,"out":5,"filters":[],"crops":[]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Clips) != 1 || e.Clips[0].Title != "Intro" || e.Clips[0].Out != 5 || len(e.Markers) != 0 {
+		t.Errorf("single clip parsed as %+v", e)
+	}
+
+	e, err = ParseEdits([]byte(`{"version":1,"clips":[{"in":1,"out":2},{"in":3,"out":4}],"markers":[{"timestamp":7,"title":"Goal"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Clips) != 2 || len(e.Markers) != 1 || e.Markers[0].Title != "Goal" {
+		t.Errorf("document parsed as %+v", e)
+	}
+
+	for _, bad := range []string{
+		`[]`,
+		`{"version":2,"clips":[]}`,
+		`{"clips":[{"version":9,"in":1,"out":2}]}`,
+		`{"markers":"nope"}`,
+	} {
+		if _, err := ParseEdits([]byte(bad)); err == nil {
+			t.Errorf("ParseEdits(%s) succeeded, want error", bad)
+		}
+	}
+}
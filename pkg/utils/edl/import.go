@@ -0,0 +1,59 @@
+package edl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Edits is the document accepted by POST /api/videos/:id/import-edits: clips
+// in the JSON form served by GET /api/clips/:id/edl, plus markers. IDs in it
+// only link crops to the filters that use them; imports always get new IDs.
+type Edits struct {
+	Version int      `json:"version"`
+	Clips   []Clip   `json:"clips"`
+	Markers []Marker `json:"markers"`
+}
+
+// Marker is a point or chapter marker. Times are seconds from the start of
+// the video.
+type Marker struct {
+	Timestamp   float64  `json:"timestamp"`
+	Duration    *float64 `json:"duration,omitempty"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Color       string   `json:"color,omitempty"`
+	MarkerType  string   `json:"marker_type,omitempty"`
+}
+
+// ParseEdits decodes an edits document. A single clip description, as served
+// by GET /api/clips/:id/edl, is read as a document holding just that clip.
+// Documents from a newer schema version are rejected.
+func ParseEdits(data []byte) (*Edits, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+
+	var e Edits
+	_, hasClips := keys["clips"]
+	_, hasMarkers := keys["markers"]
+	if !hasClips && !hasMarkers {
+		var c Clip
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("invalid clip: %w", err)
+		}
+		e = Edits{Version: c.Version, Clips: []Clip{c}}
+	} else if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("invalid edits: %w", err)
+	}
+
+	if e.Version > Version {
+		return nil, fmt.Errorf("unsupported version %d (newest supported is %d)", e.Version, Version)
+	}
+	for _, c := range e.Clips {
+		if c.Version > Version {
+			return nil, fmt.Errorf("clip %q: unsupported version %d (newest supported is %d)", c.Title, c.Version, Version)
+		}
+	}
+	return &e, nil
+}
@@ -4,6 +4,7 @@ package filename
 import (
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // invalidCharsRe matches characters not safe for filenames across all major OSes.
@@ -46,6 +47,9 @@ func Sanitize(name string, maxLen int) string {
 	// Truncate to maxLen, but don't cut in the middle of a UTF-8 sequence.
 	if len(s) > maxLen {
 		s = s[:maxLen]
+		for !utf8.ValidString(s) {
+			s = s[:len(s)-1]
+		}
 		// Clean up a trailing partial dash/dot from the truncation.
 		s = strings.TrimRight(s, "-.")
 	}
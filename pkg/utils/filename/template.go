@@ -0,0 +1,129 @@
+package filename
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultExportTemplate is the download filename template used when a user
+// has not configured one. It matches the historical "{title}[-{crop}]-{id}" shape.
+const DefaultExportTemplate = "{clip}-{variant}-{id}"
+
+// templateVars lists the placeholders understood by Render, in display order.
+var templateVars = []string{"video", "clip", "format", "variant", "id", "date"}
+
+// TemplateVars returns the placeholder names supported in filename templates.
+func TemplateVars() []string {
+	out := make([]string, len(templateVars))
+	copy(out, templateVars)
+	return out
+}
+
+// Render expands {placeholder} tokens in tmpl using vars, sanitizing each value
+// and the final result. Unknown placeholders expand to "". Empty segments left
+// behind by missing values are collapsed by Sanitize. The result never includes
+// an extension; callers append one.
+func Render(tmpl string, vars map[string]string, maxLen int) string {
+	tmpl = strings.TrimSpace(tmpl)
+	if tmpl == "" {
+		tmpl = DefaultExportTemplate
+	}
+
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(tmpl, '{')
+		if open < 0 {
+			b.WriteString(tmpl)
+			break
+		}
+		end := strings.IndexByte(tmpl[open:], '}')
+		if end < 0 {
+			b.WriteString(tmpl)
+			break
+		}
+		b.WriteString(tmpl[:open])
+		key := strings.ToLower(strings.TrimSpace(tmpl[open+1 : open+end]))
+		b.WriteString(Sanitize(vars[key], 0))
+		tmpl = tmpl[open+end+1:]
+	}
+
+	return Sanitize(b.String(), maxLen)
+}
+
+// ValidateTemplate reports whether tmpl only references known placeholders.
+// An empty template is valid (the default is used).
+func ValidateTemplate(tmpl string) bool {
+	rest := tmpl
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			return !strings.Contains(rest, "}")
+		}
+		if strings.Contains(rest[:open], "}") {
+			return false
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return false
+		}
+		key := strings.ToLower(strings.TrimSpace(rest[open+1 : open+end]))
+		known := false
+		for _, v := range templateVars {
+			if v == key {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return false
+		}
+		rest = rest[open+end+1:]
+	}
+}
+
+// ContentDisposition builds an attachment Content-Disposition header value for
+// name. Non-ASCII names get an ASCII fallback in filename= plus the full
+// UTF-8 name in filename* (RFC 5987 / RFC 6266).
+func ContentDisposition(name string) string {
+	fallback := asciiFallback(name)
+	if fallback == name {
+		return `attachment; filename="` + fallback + `"`
+	}
+	return `attachment; filename="` + fallback + `"; filename*=UTF-8''` + encodeExtValue(name)
+}
+
+// encodeExtValue percent-encodes every byte outside RFC 5987 attr-char.
+func encodeExtValue(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isAttrChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+	return b.String()
+}
+
+func isAttrChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
+
+// asciiFallback replaces non-ASCII, control, quote, and backslash characters
+// with underscores so the value is safe inside a quoted-string.
+func asciiFallback(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || r < 0x20 || r == 0x7f || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,50 @@
+package filename
+
+import "testing"
+
+func TestRender_DefaultTemplate(t *testing.T) {
+	got := Render("", map[string]string{"clip": "My Clip", "variant": "", "id": "abc"}, 0)
+	if got != "My-Clip-abc" {
+		t.Fatalf("Render = %q, want %q", got, "My-Clip-abc")
+	}
+}
+
+func TestRender_SanitizesValues(t *testing.T) {
+	got := Render("{video}_{clip}", map[string]string{"video": "a/b:c", "clip": "d?e"}, 0)
+	if got != "a-b-c_d-e" {
+		t.Fatalf("Render = %q, want %q", got, "a-b-c_d-e")
+	}
+}
+
+func TestRender_TruncatesOnRuneBoundary(t *testing.T) {
+	got := Render("{clip}", map[string]string{"clip": "ééééé"}, 5)
+	if got != "éé" {
+		t.Fatalf("Render = %q, want %q", got, "éé")
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	cases := map[string]bool{
+		"":                      true,
+		"{video} - {clip}":      true,
+		"{clip}-{variant}-{id}": true,
+		"{nope}":                false,
+		"{clip":                 false,
+		"clip}":                 false,
+	}
+	for tmpl, want := range cases {
+		if got := ValidateTemplate(tmpl); got != want {
+			t.Errorf("ValidateTemplate(%q) = %v, want %v", tmpl, got, want)
+		}
+	}
+}
+
+func TestContentDisposition(t *testing.T) {
+	if got := ContentDisposition("clip.mp4"); got != `attachment; filename="clip.mp4"` {
+		t.Fatalf("ascii: got %q", got)
+	}
+	want := `attachment; filename="caf_.mp4"; filename*=UTF-8''caf%C3%A9.mp4`
+	if got := ContentDisposition("café.mp4"); got != want {
+		t.Fatalf("utf-8: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+// Package keywords picks the words that best characterise a transcript
+// against the rest of the archive, using TF-IDF weighting: words said often
+// in one video but in few others score highest.
+package keywords
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MinWordLength is the shortest word considered a keyword.
+const MinWordLength = 4
+
+// MinCount is how many times a word must appear in a transcript to be a
+// candidate; single mentions are too noisy to tag on.
+const MinCount = 3
+
+// Term is a candidate keyword and how many times it appears.
+type Term struct {
+	Word  string
+	Count int
+}
+
+// Tokenize splits text into lowercased words, dropping stopwords, numbers and
+// words shorter than MinWordLength. Possessive 's is stripped.
+func Tokenize(text string) []string {
+	text = strings.ReplaceAll(strings.ToLower(text), "’", "'")
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSuffix(strings.Trim(f, "'"), "'s")
+		if len([]rune(f)) < MinWordLength || strings.ContainsRune(f, '\'') || !hasLetter(f) {
+			continue
+		}
+		if _, stop := stopwords[f]; stop {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// Candidates returns up to limit words of text that appear at least MinCount
+// times, most frequent first (ties alphabetical).
+func Candidates(text string, limit int) []Term {
+	counts := map[string]int{}
+	for _, w := range Tokenize(text) {
+		counts[w]++
+	}
+	terms := make([]Term, 0, len(counts))
+	for w, n := range counts {
+		if n >= MinCount {
+			terms = append(terms, Term{Word: w, Count: n})
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Count != terms[j].Count {
+			return terms[i].Count > terms[j].Count
+		}
+		return terms[i].Word < terms[j].Word
+	})
+	if limit > 0 && len(terms) > limit {
+		terms = terms[:limit]
+	}
+	return terms
+}
+
+// Rank orders candidates by TF-IDF and returns at most max words. docFreq maps
+// a word to how many of the archive's totalDocs transcripts contain it; words
+// missing from docFreq count as appearing only in this one. The smoothed IDF
+// never reaches zero, so with a one-video archive the ranking falls back to
+// plain frequency.
+func Rank(terms []Term, docFreq map[string]int64, totalDocs int64, max int) []string {
+	type scored struct {
+		word  string
+		score float64
+	}
+	if totalDocs < 1 {
+		totalDocs = 1
+	}
+	ranked := make([]scored, 0, len(terms))
+	for _, t := range terms {
+		df := docFreq[t.Word]
+		if df < 1 {
+			df = 1
+		}
+		idf := math.Log(float64(totalDocs+1)/float64(df+1)) + 1
+		ranked = append(ranked, scored{t.Word, float64(t.Count) * idf})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+	if max > 0 && len(ranked) > max {
+		ranked = ranked[:max]
+	}
+	out := make([]string, len(ranked))
+	for i, s := range ranked {
+		out[i] = s.word
+	}
+	return out
+}
+
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// stopwords are common English words and spoken filler that say nothing
+// about a video's subject, plus caption cue words like [Music].
+var stopwords = toSet(`
+about above actually after again against ago almost along already also although always among another anybody anyone anything anyway anyways
+around away back basically because been before behind being below between both bring came cannot come comes coming could couldn
+definitely didn does doesn doing done down during each either else enough even ever every everybody everyone everything exactly
+fine first five four from front further gets getting give given gives giving goes going gone gonna good got gotta great guys
+hadn happen happened hasn have haven having hello here hers herself himself hmm honestly hope however hundred idea into isn itself
+just keep kind know knew known last later least less like likely little look looked looking looks lots made make makes making many
+maybe mean means might mine more most much must myself need needs never next nice nobody none nothing okay once one only onto other others
+otherwise ours ourselves over pretty probably quite rather real really right said same saying says second seen seem seems shall
+should shouldn show side since some somebody someone something sometimes somewhat soon sort start started still stuff such sure take
+taken takes taking talk talking tell than thank thanks that thats their theirs them themselves then there these they thing things
+think thinking this those though thought three through time times today together told took totally toward towards true trying
+under until upon very want wanted wants wasn watch watching well went were weren what whatever when where whether which while
+whole whom whose will with within without won wonder word words would wouldn yeah year years yes yesterday your yours yourself yourselves
+music applause laughter inaudible foreign
+`)
+
+func toSet(words string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, w := range strings.Fields(words) {
+		set[w] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,41 @@
+package keywords
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("The Raspberry Pi's GPIO pins — they're [Music] 2024 really useful, don’t you think? Kubernetes!")
+	want := []string{"raspberry", "gpio", "pins", "useful", "kubernetes"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	text := "solder solder solder flux flux flux flux iron iron yeah yeah yeah yeah"
+	got := Candidates(text, 10)
+	want := []Term{{"flux", 4}, {"solder", 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Candidates = %v, want %v", got, want)
+	}
+	if got := Candidates(text, 1); len(got) != 1 || got[0].Word != "flux" {
+		t.Fatalf("Candidates limit 1 = %v, want [flux]", got)
+	}
+}
+
+func TestRank(t *testing.T) {
+	terms := []Term{{"video", 10}, {"solder", 6}, {"flux", 5}}
+
+	// In a one-video archive, frequency decides.
+	if got := Rank(terms, nil, 1, 2); !reflect.DeepEqual(got, []string{"video", "solder"}) {
+		t.Fatalf("single-document Rank = %v", got)
+	}
+
+	// A word in every transcript drops below rarer ones.
+	df := map[string]int64{"video": 100, "solder": 2, "flux": 3}
+	if got := Rank(terms, df, 100, 3); !reflect.DeepEqual(got, []string{"solder", "flux", "video"}) {
+		t.Fatalf("Rank = %v", got)
+	}
+}
@@ -0,0 +1,65 @@
+// Package netaddr provides IP address classification helpers.
+package netaddr
+
+import (
+	"net"
+	"strings"
+)
+
+// IsLocalOrPrivateIP reports whether ip is unspecified, loopback, RFC1918
+// private, carrier-grade NAT (100.64.0.0/10), "this network" (0.0.0.0/8),
+// link-local, IPv6 ULA (fc00::/7), IPv6 link-local (fe80::/10), or IPv6
+// site-local (fec0::/10).
+func IsLocalOrPrivateIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() || ip.IsUnspecified() {
+		return true
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		// RFC1918 + link-local, plus ranges that reach this host or the
+		// provider's network
+		switch {
+		case ip4[0] == 0:
+			return true
+		case ip4[0] == 10:
+			return true
+		case ip4[0] == 100 && ip4[1]&0xc0 == 64:
+			return true
+		case ip4[0] == 172 && ip4[1] >= 16 && ip4[1] <= 31:
+			return true
+		case ip4[0] == 192 && ip4[1] == 168:
+			return true
+		case ip4[0] == 169 && ip4[1] == 254:
+			return true
+		default:
+			return false
+		}
+	}
+	// IPv6: treat ULA (fc00::/7), link-local (fe80::/10) and the deprecated
+	// site-local (fec0::/10) as local.
+	if len(ip) == net.IPv6len {
+		if ip[0]&0xfe == 0xfc { // fc00::/7
+			return true
+		}
+		if ip[0] == 0xfe && (ip[1]&0xc0) == 0x80 { // fe80::/10
+			return true
+		}
+		if ip[0] == 0xfe && (ip[1]&0xc0) == 0xc0 { // fec0::/10
+			return true
+		}
+	}
+	return false
+}
+
+// IsLocalOrPrivateHost reports whether host (no port) is "localhost" or an IP
+// literal classified by IsLocalOrPrivateIP. Hostnames are not resolved.
+func IsLocalOrPrivateHost(host string) bool {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "localhost" {
+		return true
+	}
+	return IsLocalOrPrivateIP(net.ParseIP(host))
+}
@@ -0,0 +1,39 @@
+package netaddr
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsLocalOrPrivateIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"0.0.0.0", true},
+		{"0.1.2.3", true},
+		{"10.1.2.3", true},
+		{"100.64.0.1", true},
+		{"100.127.255.254", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"::", true},
+		{"::1", true},
+		{"::ffff:10.0.0.1", true},
+		{"fd00::1", true},
+		{"fe80::1", true},
+		{"fec0::1", true},
+		{"8.8.8.8", false},
+		{"100.63.255.255", false},
+		{"100.128.0.1", false},
+		{"172.32.0.1", false},
+		{"2606:4700::1111", false},
+	}
+	for _, tt := range tests {
+		if got := IsLocalOrPrivateIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsLocalOrPrivateIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
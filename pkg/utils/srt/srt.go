@@ -0,0 +1,164 @@
+// Package srt converts captions between WebVTT and SubRip (SRT), for tools
+// that only read SRT.
+package srt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"thirdcoast.systems/rewind/pkg/utils/vttshift"
+)
+
+var (
+	// timingRe matches a cue timing line: start, end, and any cue settings.
+	timingRe = regexp.MustCompile(`^(\S+)[ \t]+-->[ \t]+(\S+)`)
+	// tagRe matches a markup tag in cue text: voice and class spans, ruby,
+	// language spans and karaoke timestamps.
+	tagRe = regexp.MustCompile(`</?[^<>]*>`)
+	// keepTagRe matches the tags SRT players understand.
+	keepTagRe = regexp.MustCompile(`^</?[biu]>$`)
+)
+
+var entities = strings.NewReplacer(
+	"&lt;", "<", "&gt;", ">", "&nbsp;", " ", "&lrm;", "", "&rlm;", "", "&amp;", "&",
+)
+
+// cue is one caption: its timing and its text lines.
+type cue struct {
+	Start, End time.Duration
+	Lines      []string
+}
+
+// FromVTT converts WebVTT captions to SRT. Cue settings (position, line,
+// align), STYLE, REGION and NOTE blocks and markup other than <b>, <i> and
+// <u> are dropped; cues are renumbered from 1. It returns the SRT and the
+// number of cues written.
+func FromVTT(vtt []byte) ([]byte, int) {
+	cues := parseVTT(vtt)
+	return write(cues), len(cues)
+}
+
+// parseVTT reads the cues of WebVTT captions with their text cleaned for SRT
+// (see FromVTT). Cues with bad timings or no text are skipped.
+func parseVTT(vtt []byte) []cue {
+	var cues []cue
+	for _, block := range blocks(string(vtt)) {
+		timing := -1
+		for i, line := range block {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		// The header, NOTE, STYLE and REGION blocks have no timing line.
+		if timing < 0 || timing > 1 {
+			continue
+		}
+		start, end, ok := parseTiming(block[timing])
+		if !ok {
+			continue
+		}
+		var lines []string
+		for _, line := range block[timing+1:] {
+			if line = cleanText(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			cues = append(cues, cue{Start: start, End: end, Lines: lines})
+		}
+	}
+	return cues
+}
+
+// write renders cues as SRT, numbered from 1.
+func write(cues []cue) []byte {
+	var b strings.Builder
+	for i, c := range cues {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n", i+1, timestamp(c.Start), timestamp(c.End), strings.Join(c.Lines, "\n"))
+	}
+	return []byte(b.String())
+}
+
+// ToVTT converts SRT captions to WebVTT. Cue numbers are dropped.
+func ToVTT(srt []byte) []byte {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for _, block := range blocks(string(srt)) {
+		timing := 0
+		if _, err := strconv.Atoi(block[0]); err == nil && len(block) > 1 {
+			timing = 1
+		}
+		start, end, ok := parseTiming(strings.ReplaceAll(block[timing], ",", "."))
+		if !ok || len(block) == timing+1 {
+			continue
+		}
+		text := strings.Join(block[timing+1:], "\n")
+		// The text may hold "<" or "&" literally; VTT needs them escaped
+		// outside of the tags both formats share.
+		text = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+		for _, tag := range []string{"b", "i", "u"} {
+			text = strings.NewReplacer("&lt;"+tag+"&gt;", "<"+tag+">", "&lt;/"+tag+"&gt;", "</"+tag+">").Replace(text)
+		}
+		fmt.Fprintf(&b, "\n%s --> %s\n%s\n", vttshift.Format(start), vttshift.Format(end), text)
+	}
+	return []byte(b.String())
+}
+
+// blocks splits captions into blank-line separated blocks of trimmed lines.
+func blocks(text string) [][]string {
+	text = strings.TrimPrefix(strings.ReplaceAll(text, "\r\n", "\n"), "\ufeff")
+	var out [][]string
+	var cur []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			if len(cur) > 0 {
+				out = append(out, cur)
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+func parseTiming(line string) (start, end time.Duration, ok bool) {
+	m := timingRe.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return 0, 0, false
+	}
+	start, err1 := vttshift.Parse(m[1])
+	end, err2 := vttshift.Parse(m[2])
+	if err1 != nil || err2 != nil || end < start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// cleanText strips VTT markup from a line of cue text, keeping <b>, <i> and
+// <u>, and decodes the character references VTT requires.
+func cleanText(line string) string {
+	line = tagRe.ReplaceAllStringFunc(line, func(tag string) string {
+		if keepTagRe.MatchString(tag) {
+			return tag
+		}
+		return ""
+	})
+	return strings.TrimSpace(entities.Replace(line))
+}
+
+// timestamp formats d as an SRT timestamp, "hh:mm:ss,ttt".
+func timestamp(d time.Duration) string {
+	return strings.Replace(vttshift.Format(d), ".", ",", 1)
+}
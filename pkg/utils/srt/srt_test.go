@@ -0,0 +1,53 @@
+package srt
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleVTT = "WEBVTT\nKind: captions\nLanguage: en\n\n" +
+	"STYLE\n::cue { color: yellow }\n\n" +
+	"NOTE generated by whisper\n\n" +
+	"intro\n00:00:01.000 --> 00:00:02.500 align:start position:0%\n<v Host>hello <b>there</b></v>\n\n" +
+	"01:05.000 --> 01:07.250 line:90%\nwor<00:01:06.000><c.red>ld</c>\nfish &amp; chips &lt;3\n\n" +
+	"00:01:08.000 --> 00:01:09.000\n<c></c>\n"
+
+func TestFromVTT(t *testing.T) {
+	got, cues := FromVTT([]byte(sampleVTT))
+	if cues != 2 {
+		t.Fatalf("cues = %d, want 2", cues)
+	}
+	want := "1\n00:00:01,000 --> 00:00:02,500\nhello <b>there</b>\n\n" +
+		"2\n00:01:05,000 --> 00:01:07,250\nworld\nfish & chips <3\n"
+	if string(got) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	srt, _ := FromVTT([]byte(sampleVTT))
+	vtt := ToVTT(srt)
+	if !strings.HasPrefix(string(vtt), "WEBVTT\n") {
+		t.Fatalf("missing header:\n%s", vtt)
+	}
+	for _, want := range []string{
+		"00:00:01.000 --> 00:00:02.500\nhello <b>there</b>\n",
+		"00:01:05.000 --> 00:01:07.250\nworld\nfish &amp; chips &lt;3\n",
+	} {
+		if !strings.Contains(string(vtt), want) {
+			t.Fatalf("missing %q in:\n%s", want, vtt)
+		}
+	}
+	again, cues := FromVTT(vtt)
+	if cues != 2 || string(again) != string(srt) {
+		t.Fatalf("second pass differs:\n%s\nwant:\n%s", again, srt)
+	}
+}
+
+func TestToVTT_SkipsBadCues(t *testing.T) {
+	in := "1\r\nbad --> 00:00:02,000\r\nhi\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nok\r\n"
+	got := string(ToVTT([]byte(in)))
+	if strings.Contains(got, "hi") || !strings.Contains(got, "00:00:03.000 --> 00:00:04.000\nok\n") {
+		t.Fatalf("got:\n%s", got)
+	}
+}
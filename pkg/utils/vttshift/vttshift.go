@@ -0,0 +1,115 @@
+// Package vttshift moves every cue in a WebVTT file by a fixed offset, for
+// correcting captions that are out of sync with their video.
+package vttshift
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotVTT is returned when the input does not start with a WEBVTT header.
+var ErrNotVTT = errors.New("not a WebVTT file")
+
+var (
+	// timingRe matches a cue timing line: start, end, and any cue settings.
+	timingRe = regexp.MustCompile(`^(\S+)[ \t]+-->[ \t]+(\S+)(.*)$`)
+	// inlineRe matches karaoke-style timestamp tags inside cue text, as in
+	// YouTube's auto-generated captions.
+	inlineRe = regexp.MustCompile(`<((?:\d+:)?\d{2}:\d{2}\.\d{3})>`)
+)
+
+// Shift adds offset to every cue start, end and inline timestamp. Negative
+// offsets move cues earlier; times are clamped at zero. It returns the
+// rewritten file and the number of cues shifted.
+func Shift(vtt []byte, offset time.Duration) ([]byte, int, error) {
+	text := strings.ReplaceAll(string(vtt), "\r\n", "\n")
+	if !strings.HasPrefix(strings.TrimPrefix(text, "\ufeff"), "WEBVTT") {
+		return nil, 0, ErrNotVTT
+	}
+
+	lines := strings.Split(text, "\n")
+	cues := 0
+	inCue := false
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			inCue = false
+			continue
+		}
+		if m := timingRe.FindStringSubmatch(line); m != nil && strings.Contains(line, "-->") {
+			start, err := Parse(m[1])
+			if err != nil {
+				return nil, 0, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			end, err := Parse(m[2])
+			if err != nil {
+				return nil, 0, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			lines[i] = Format(clamp(start+offset)) + " --> " + Format(clamp(end+offset)) + m[3]
+			cues++
+			inCue = true
+			continue
+		}
+		if inCue {
+			lines[i] = inlineRe.ReplaceAllStringFunc(line, func(tag string) string {
+				t, err := Parse(tag[1 : len(tag)-1])
+				if err != nil {
+					return tag
+				}
+				return "<" + Format(clamp(t+offset)) + ">"
+			})
+		}
+	}
+	return []byte(strings.Join(lines, "\n")), cues, nil
+}
+
+// Parse reads a WebVTT timestamp, "hh:mm:ss.ttt" or "mm:ss.ttt".
+func Parse(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var h int64
+	if len(parts) == 3 {
+		v, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		h = v
+		parts = parts[1:]
+	}
+	m, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	sec, frac, ok := strings.Cut(parts[1], ".")
+	if !ok || len(sec) != 2 || len(frac) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	ss, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil || ss < 0 || ss > 59 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	ms, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil || ms < 0 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+		time.Duration(ss)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+// Format writes d as a WebVTT timestamp, "hh:mm:ss.ttt".
+func Format(d time.Duration) string {
+	ms := d.Round(time.Millisecond).Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+func clamp(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
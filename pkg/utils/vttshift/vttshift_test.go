@@ -0,0 +1,60 @@
+package vttshift
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleVTT = "WEBVTT\nKind: captions\n\n" +
+	"00:00:01.000 --> 00:00:02.500 align:start position:0%\nhello\n\n" +
+	"01:05.000 --> 01:07.250\nwor<00:01:06.000><c>ld</c>\n"
+
+func TestShift_Forward(t *testing.T) {
+	got, cues, err := Shift([]byte(sampleVTT), 1500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cues != 2 {
+		t.Fatalf("cues = %d, want 2", cues)
+	}
+	for _, want := range []string{
+		"00:00:02.500 --> 00:00:04.000 align:start position:0%\nhello\n",
+		"00:01:06.500 --> 00:01:08.750\nwor<00:01:07.500><c>ld</c>\n",
+		"WEBVTT\nKind: captions\n",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestShift_BackwardClampsAtZero(t *testing.T) {
+	got, _, err := Shift([]byte(sampleVTT), -2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "00:00:00.000 --> 00:00:00.500 align:start") {
+		t.Fatalf("first cue not clamped:\n%s", got)
+	}
+}
+
+func TestShift_RoundTrip(t *testing.T) {
+	fwd, _, _ := Shift([]byte(sampleVTT), 3*time.Second)
+	back, _, err := Shift(fwd, -3*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(back), "00:00:01.000 --> 00:00:02.500") {
+		t.Fatalf("round trip changed times:\n%s", back)
+	}
+}
+
+func TestShift_Errors(t *testing.T) {
+	if _, _, err := Shift([]byte("1\n00:00:01,000 --> 00:00:02,000\nhi\n"), time.Second); err != ErrNotVTT {
+		t.Fatalf("err = %v, want ErrNotVTT", err)
+	}
+	if _, _, err := Shift([]byte("WEBVTT\n\nbad --> 00:00:02.000\nhi\n"), time.Second); err == nil {
+		t.Fatal("expected an error for a malformed timestamp")
+	}
+}
@@ -0,0 +1,68 @@
+package videoinfo
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// SanitizeChapters returns chapters cleaned up for storage: sorted by start,
+// clamped to [0, durationSeconds] (durationSeconds <= 0 means unknown), with
+// missing (zero) end times filled from the next chapter's start (or the duration),
+// overlaps cut at the next chapter's start, and zero-length or unusable
+// entries dropped. Of several chapters starting at the same time only the
+// first is kept. Titles are whitespace-collapsed; empty titles stay empty.
+func SanitizeChapters(chapters []Chapter, durationSeconds float64) []Chapter {
+	valid := func(f float64) bool { return !math.IsNaN(f) && !math.IsInf(f, 0) }
+	if !valid(durationSeconds) || durationSeconds < 0 {
+		durationSeconds = 0
+	}
+
+	in := make([]Chapter, 0, len(chapters))
+	for _, ch := range chapters {
+		if !valid(ch.StartTime) {
+			continue
+		}
+		if !valid(ch.EndTime) {
+			ch.EndTime = 0
+		}
+		ch.StartTime = math.Max(ch.StartTime, 0)
+		// An end of 0 means the source gave none; any other end at or
+		// before the start is a zero-length chapter.
+		if ch.EndTime > 0 && ch.EndTime <= ch.StartTime {
+			continue
+		}
+		if durationSeconds > 0 && ch.StartTime >= durationSeconds {
+			continue
+		}
+		ch.Title = strings.Join(strings.Fields(ch.Title), " ")
+		in = append(in, ch)
+	}
+	sort.SliceStable(in, func(i, j int) bool { return in[i].StartTime < in[j].StartTime })
+
+	out := make([]Chapter, 0, len(in))
+	for i, ch := range in {
+		if i > 0 && ch.StartTime == in[i-1].StartTime {
+			continue
+		}
+		// The next chapter that starts later bounds this one.
+		next := durationSeconds
+		for _, n := range in[i+1:] {
+			if n.StartTime > ch.StartTime {
+				next = n.StartTime
+				break
+			}
+		}
+		if ch.EndTime <= 0 {
+			ch.EndTime = next
+		}
+		if next > 0 && ch.EndTime > next {
+			ch.EndTime = next
+		}
+		if ch.EndTime <= ch.StartTime {
+			continue
+		}
+		out = append(out, ch)
+	}
+	return out
+}
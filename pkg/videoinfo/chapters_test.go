@@ -0,0 +1,73 @@
+package videoinfo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSanitizeChapters(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       []Chapter
+		duration float64
+		want     []Chapter
+	}{
+		{
+			name:     "clean chapters pass through",
+			in:       []Chapter{{0, 60, "Intro"}, {60, 120, "Main"}},
+			duration: 120,
+			want:     []Chapter{{0, 60, "Intro"}, {60, 120, "Main"}},
+		},
+		{
+			name:     "missing ends run to the next start or the duration",
+			in:       []Chapter{{0, 0, "A"}, {30, 0, "B"}},
+			duration: 90,
+			want:     []Chapter{{0, 30, "A"}, {30, 90, "B"}},
+		},
+		{
+			name:     "overlaps are cut at the next start",
+			in:       []Chapter{{0, 50, "A"}, {40, 100, "B"}},
+			duration: 100,
+			want:     []Chapter{{0, 40, "A"}, {40, 100, "B"}},
+		},
+		{
+			name:     "out of order input is sorted",
+			in:       []Chapter{{60, 120, "B"}, {0, 60, "A"}},
+			duration: 120,
+			want:     []Chapter{{0, 60, "A"}, {60, 120, "B"}},
+		},
+		{
+			name:     "zero-length and duplicate starts are dropped",
+			in:       []Chapter{{0, 30, "A"}, {0, 10, "A again"}, {30, 30, "Empty"}, {30, 60, "B"}},
+			duration: 60,
+			want:     []Chapter{{0, 30, "A"}, {30, 60, "B"}},
+		},
+		{
+			name:     "clamped to the duration",
+			in:       []Chapter{{-5, 30, "A"}, {30, 500, "B"}, {600, 700, "Past the end"}},
+			duration: 100,
+			want:     []Chapter{{0, 30, "A"}, {30, 100, "B"}},
+		},
+		{
+			name:     "last chapter without end or duration is dropped",
+			in:       []Chapter{{0, 0, "A"}, {30, 0, "B"}},
+			duration: 0,
+			want:     []Chapter{{0, 30, "A"}},
+		},
+		{
+			name:     "non-finite times and titles",
+			in:       []Chapter{{math.NaN(), 10, "Bad"}, {0, math.Inf(1), "  Spaced \n title "}},
+			duration: 20,
+			want:     []Chapter{{0, 20, "Spaced title"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeChapters(tt.in, tt.duration)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("SanitizeChapters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
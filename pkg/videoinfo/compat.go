@@ -0,0 +1,246 @@
+package videoinfo
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// PLAYBACK COMPATIBILITY - Can a browser play the stored file directly?
+// ============================================================================
+
+// Browser families tracked by the codec compatibility matrix.
+const (
+	BrowserChrome  = "chrome"
+	BrowserFirefox = "firefox"
+	BrowserSafari  = "safari"
+	BrowserEdge    = "edge"
+	BrowserOther   = "other"
+)
+
+// CompatBrowsers lists the browser families in matrix order.
+var CompatBrowsers = []string{BrowserChrome, BrowserFirefox, BrowserSafari, BrowserEdge}
+
+// codecSupport flags which browser families decode a codec in a <video> element
+// without plugins. Values are deliberately conservative: a codec that only
+// works with specific hardware or OS add-ons is marked unsupported.
+type codecSupport struct {
+	chrome, firefox, safari, edge bool
+}
+
+func (s codecSupport) supports(browser string) bool {
+	switch browser {
+	case BrowserChrome:
+		return s.chrome
+	case BrowserFirefox:
+		return s.firefox
+	case BrowserSafari:
+		return s.safari
+	case BrowserEdge:
+		return s.edge
+	}
+	// Unknown browsers get the lowest common denominator.
+	return s.chrome && s.firefox && s.safari && s.edge
+}
+
+// videoCodecMatrix is keyed on ffprobe codec_name.
+var videoCodecMatrix = map[string]codecSupport{
+	"h264":       {chrome: true, firefox: true, safari: true, edge: true},
+	"hevc":       {chrome: true, firefox: false, safari: true, edge: true},
+	"vp8":        {chrome: true, firefox: true, safari: false, edge: true},
+	"vp9":        {chrome: true, firefox: true, safari: true, edge: true},
+	"av1":        {chrome: true, firefox: true, safari: false, edge: true},
+	"mpeg4":      {},
+	"mpeg2video": {},
+	"mpeg1video": {},
+	"h263":       {},
+	"theora":     {chrome: false, firefox: true, safari: false, edge: false},
+	"wmv3":       {},
+	"vc1":        {},
+	"prores":     {chrome: false, firefox: false, safari: true, edge: false},
+}
+
+// audioCodecMatrix is keyed on ffprobe codec_name.
+var audioCodecMatrix = map[string]codecSupport{
+	"aac":       {chrome: true, firefox: true, safari: true, edge: true},
+	"mp3":       {chrome: true, firefox: true, safari: true, edge: true},
+	"opus":      {chrome: true, firefox: true, safari: true, edge: true},
+	"vorbis":    {chrome: true, firefox: true, safari: false, edge: true},
+	"flac":      {chrome: true, firefox: true, safari: true, edge: true},
+	"alac":      {chrome: false, firefox: false, safari: true, edge: false},
+	"ac3":       {chrome: false, firefox: false, safari: true, edge: true},
+	"eac3":      {chrome: false, firefox: false, safari: true, edge: true},
+	"dts":       {},
+	"truehd":    {},
+	"mp2":       {},
+	"pcm_s16le": {},
+	"pcm_s24le": {},
+	"wmav2":     {},
+}
+
+// eightBitPixFmts decode everywhere the codec itself is supported.
+var eightBitPixFmts = map[string]bool{
+	"yuv420p":  true,
+	"yuvj420p": true,
+	"nv12":     true,
+}
+
+// tenBitPixFmts are only safe for codecs whose browser decoders handle Main10
+// (H.264 High 10 is not decoded by any mainstream browser).
+var tenBitPixFmts = map[string]bool{
+	"yuv420p10le": true,
+	"p010le":      true,
+}
+
+var tenBitCodecs = map[string]bool{
+	"hevc": true,
+	"vp9":  true,
+	"av1":  true,
+}
+
+// PlaybackCompat reports whether the stored file can be handed to the browser
+// as-is or should be replaced by an HLS/transcoded rendition.
+type PlaybackCompat struct {
+	// Known is false when no probe data exists; the flags then default to
+	// "assume playable" so legacy videos keep using the direct path.
+	Known      bool   `json:"known"`
+	Browser    string `json:"browser"`
+	VideoCodec string `json:"video_codec,omitempty"`
+	AudioCodec string `json:"audio_codec,omitempty"`
+	PixFmt     string `json:"pix_fmt,omitempty"`
+	VideoOK    bool   `json:"video_ok"`
+	AudioOK    bool   `json:"audio_ok"`
+	PixFmtOK   bool   `json:"pix_fmt_ok"`
+	// Direct is true when the requesting browser should play the file directly.
+	Direct bool `json:"direct"`
+	// Browsers maps each browser family to its direct-play safety.
+	Browsers map[string]bool `json:"browsers"`
+	// Reasons explains each failed check, for logs and the UI.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// BrowserFromUserAgent maps a User-Agent header to a browser family.
+// Order matters: Edge and Chrome-on-iOS both contain "Safari", and Edge
+// contains "Chrome".
+func BrowserFromUserAgent(ua string) string {
+	ua = strings.ToLower(ua)
+	switch {
+	case ua == "":
+		return BrowserOther
+	case strings.Contains(ua, "edg/") || strings.Contains(ua, "edge/") || strings.Contains(ua, "edga/") || strings.Contains(ua, "edgios/"):
+		return BrowserEdge
+	case strings.Contains(ua, "firefox/") || strings.Contains(ua, "fxios/"):
+		return BrowserFirefox
+	case strings.Contains(ua, "crios/"):
+		// Every iOS browser runs on WebKit's media stack.
+		return BrowserSafari
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "chromium/"):
+		return BrowserChrome
+	case strings.Contains(ua, "safari/") || strings.Contains(ua, "applewebkit/"):
+		return BrowserSafari
+	}
+	return BrowserOther
+}
+
+// PlaybackCompatibility assesses the first video and default (or first) audio
+// stream of probe against the codec matrix for every browser family, and
+// marks Direct for the family matching userAgent.
+func PlaybackCompatibility(probe *ProbeInfo, userAgent string) PlaybackCompat {
+	browser := BrowserFromUserAgent(userAgent)
+	out := PlaybackCompat{
+		Browser:  browser,
+		VideoOK:  true,
+		AudioOK:  true,
+		PixFmtOK: true,
+		Direct:   true,
+		Browsers: make(map[string]bool, len(CompatBrowsers)),
+	}
+	for _, b := range CompatBrowsers {
+		out.Browsers[b] = true
+	}
+	if probe == nil || len(probe.Streams) == 0 {
+		return out
+	}
+	out.Known = true
+
+	var video, audio *ProbeStream
+	if streams := probe.VideoStreams(); len(streams) > 0 {
+		video = &streams[0]
+	}
+	audioStreams := probe.AudioStreams()
+	for i := range audioStreams {
+		if audioStreams[i].IsDefault() {
+			audio = &audioStreams[i]
+			break
+		}
+	}
+	if audio == nil && len(audioStreams) > 0 {
+		audio = &audioStreams[0]
+	}
+
+	if video != nil {
+		out.VideoCodec = strings.ToLower(video.CodecName)
+		out.PixFmt = strings.ToLower(video.PixFmt)
+	}
+	if audio != nil {
+		out.AudioCodec = strings.ToLower(audio.CodecName)
+	}
+
+	for _, b := range CompatBrowsers {
+		out.Browsers[b] = streamsPlayable(out.VideoCodec, out.PixFmt, out.AudioCodec, b)
+	}
+
+	if video != nil {
+		if support, ok := videoCodecMatrix[out.VideoCodec]; !ok || !support.supports(browser) {
+			out.VideoOK = false
+			out.Reasons = append(out.Reasons, "video codec "+out.VideoCodec+" is not supported by "+browser)
+		}
+		if !pixFmtPlayable(out.VideoCodec, out.PixFmt) {
+			out.PixFmtOK = false
+			out.Reasons = append(out.Reasons, "pixel format "+out.PixFmt+" is not supported for "+out.VideoCodec)
+		}
+	}
+	if audio != nil {
+		if support, ok := audioCodecMatrix[out.AudioCodec]; !ok || !support.supports(browser) {
+			out.AudioOK = false
+			out.Reasons = append(out.Reasons, "audio codec "+out.AudioCodec+" is not supported by "+browser)
+		}
+	}
+
+	out.Direct = out.VideoOK && out.AudioOK && out.PixFmtOK
+	return out
+}
+
+// StreamPlayable applies the same codec and pixel format checks as
+// PlaybackCompatibility to an alternate stream file, for the browser matching
+// userAgent. Manifests written before audio codecs and pixel formats were
+// recorded leave those fields empty, and the empty checks pass.
+func StreamPlayable(s StreamFile, userAgent string) bool {
+	return streamsPlayable(strings.ToLower(s.Codec), strings.ToLower(s.PixFmt), strings.ToLower(s.AudioCodec), BrowserFromUserAgent(userAgent))
+}
+
+// streamsPlayable reports whether browser can decode the given codec pair.
+// Empty codecs (no stream of that type) always pass.
+func streamsPlayable(videoCodec, pixFmt, audioCodec, browser string) bool {
+	if videoCodec != "" {
+		support, ok := videoCodecMatrix[videoCodec]
+		if !ok || !support.supports(browser) || !pixFmtPlayable(videoCodec, pixFmt) {
+			return false
+		}
+	}
+	if audioCodec != "" {
+		support, ok := audioCodecMatrix[audioCodec]
+		if !ok || !support.supports(browser) {
+			return false
+		}
+	}
+	return true
+}
+
+// pixFmtPlayable reports whether pixFmt decodes in browsers for codec.
+// An empty pix_fmt (older probes) is given the benefit of the doubt.
+func pixFmtPlayable(codec, pixFmt string) bool {
+	if pixFmt == "" || eightBitPixFmts[pixFmt] {
+		return true
+	}
+	return tenBitPixFmts[pixFmt] && tenBitCodecs[codec]
+}
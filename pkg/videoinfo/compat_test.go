@@ -0,0 +1,120 @@
+package videoinfo
+
+import "testing"
+
+const (
+	uaChrome  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+	uaFirefox = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+	uaSafari  = "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15"
+	uaEdge    = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0"
+	uaCriOS   = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/126.0 Mobile/15E148 Safari/604.1"
+)
+
+func probeWith(videoCodec, pixFmt, audioCodec string) *ProbeInfo {
+	p := &ProbeInfo{}
+	if videoCodec != "" {
+		p.Streams = append(p.Streams, ProbeStream{CodecType: "video", CodecName: videoCodec, PixFmt: pixFmt})
+	}
+	if audioCodec != "" {
+		p.Streams = append(p.Streams, ProbeStream{CodecType: "audio", CodecName: audioCodec})
+	}
+	return p
+}
+
+func TestBrowserFromUserAgent(t *testing.T) {
+	cases := map[string]string{
+		uaChrome:  BrowserChrome,
+		uaFirefox: BrowserFirefox,
+		uaSafari:  BrowserSafari,
+		uaEdge:    BrowserEdge,
+		uaCriOS:   BrowserSafari,
+		"curl/8":  BrowserOther,
+		"":        BrowserOther,
+	}
+	for ua, want := range cases {
+		if got := BrowserFromUserAgent(ua); got != want {
+			t.Errorf("BrowserFromUserAgent(%q) = %q, want %q", ua, got, want)
+		}
+	}
+}
+
+func TestPlaybackCompatibility_NilProbe(t *testing.T) {
+	got := PlaybackCompatibility(nil, uaFirefox)
+	if got.Known || !got.Direct {
+		t.Fatalf("nil probe: got Known=%v Direct=%v, want unknown and direct", got.Known, got.Direct)
+	}
+}
+
+func TestPlaybackCompatibility_H264AAC(t *testing.T) {
+	got := PlaybackCompatibility(probeWith("h264", "yuv420p", "aac"), uaFirefox)
+	if !got.Direct {
+		t.Fatalf("h264/aac should play directly, reasons: %v", got.Reasons)
+	}
+	for _, b := range CompatBrowsers {
+		if !got.Browsers[b] {
+			t.Errorf("h264/aac should be safe on %s", b)
+		}
+	}
+}
+
+func TestPlaybackCompatibility_AC3Audio(t *testing.T) {
+	p := probeWith("h264", "yuv420p", "ac3")
+	if got := PlaybackCompatibility(p, uaChrome); got.Direct || got.AudioOK {
+		t.Errorf("ac3 on chrome: got Direct=%v AudioOK=%v, want false", got.Direct, got.AudioOK)
+	}
+	if got := PlaybackCompatibility(p, uaSafari); !got.Direct {
+		t.Errorf("ac3 on safari should play directly, reasons: %v", got.Reasons)
+	}
+}
+
+func TestPlaybackCompatibility_HEVC(t *testing.T) {
+	p := probeWith("hevc", "yuv420p10le", "aac")
+	if got := PlaybackCompatibility(p, uaFirefox); got.Direct || got.VideoOK {
+		t.Errorf("hevc on firefox: got Direct=%v VideoOK=%v, want false", got.Direct, got.VideoOK)
+	}
+	if got := PlaybackCompatibility(p, uaSafari); !got.Direct {
+		t.Errorf("hevc main10 on safari should play directly, reasons: %v", got.Reasons)
+	}
+}
+
+func TestPlaybackCompatibility_PixFmt(t *testing.T) {
+	if got := PlaybackCompatibility(probeWith("h264", "yuv420p10le", "aac"), uaChrome); got.Direct || got.PixFmtOK {
+		t.Errorf("h264 high10: got Direct=%v PixFmtOK=%v, want false", got.Direct, got.PixFmtOK)
+	}
+	if got := PlaybackCompatibility(probeWith("h264", "yuv444p", "aac"), uaChrome); got.PixFmtOK {
+		t.Error("yuv444p should not be considered browser-safe")
+	}
+}
+
+func TestPlaybackCompatibility_SkipsCoverArt(t *testing.T) {
+	p := &ProbeInfo{Streams: []ProbeStream{
+		{CodecType: "video", CodecName: "mjpeg", PixFmt: "yuvj444p", Disposition: map[string]int{"attached_pic": 1}},
+		{CodecType: "audio", CodecName: "mp3"},
+	}}
+	got := PlaybackCompatibility(p, uaFirefox)
+	if !got.Direct || got.VideoCodec != "" {
+		t.Errorf("audio with cover art: got Direct=%v VideoCodec=%q, want direct and no video", got.Direct, got.VideoCodec)
+	}
+}
+
+func TestStreamPlayable(t *testing.T) {
+	h264 := StreamFile{Codec: "h264", PixFmt: "yuv420p", AudioCodec: "aac"}
+	if !StreamPlayable(h264, uaFirefox) {
+		t.Errorf("h264/aac stream not playable on Firefox")
+	}
+	hevc := StreamFile{Codec: "hevc", AudioCodec: "aac"}
+	if StreamPlayable(hevc, uaFirefox) || !StreamPlayable(hevc, uaSafari) {
+		t.Errorf("hevc stream: want unplayable on Firefox, playable on Safari")
+	}
+	ac3 := StreamFile{Codec: "h264", AudioCodec: "ac3"}
+	if StreamPlayable(ac3, uaChrome) {
+		t.Errorf("ac3 audio stream playable on Chrome")
+	}
+	if StreamPlayable(StreamFile{Codec: "h264", PixFmt: "yuv420p10le"}, uaChrome) {
+		t.Errorf("10-bit h264 stream playable on Chrome")
+	}
+	// Older manifests only record the video codec.
+	if !StreamPlayable(StreamFile{Codec: "vp9"}, uaChrome) {
+		t.Errorf("vp9 stream without audio codec not playable on Chrome")
+	}
+}
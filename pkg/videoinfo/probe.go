@@ -112,11 +112,24 @@ func (p ProbeInfo) RawJSON() []byte {
 	return b
 }
 
-// VideoStreams returns all video-type streams.
+// VideoStreams returns all video-type streams except attached pictures,
+// which AttachedPicStreams returns.
 func (p *ProbeInfo) VideoStreams() []ProbeStream {
 	var out []ProbeStream
 	for _, s := range p.Streams {
-		if s.CodecType == "video" {
+		if s.CodecType == "video" && !s.IsAttachedPic() {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// AttachedPicStreams returns the attached picture streams, such as embedded
+// cover art. ffprobe reports them as one-frame video streams.
+func (p *ProbeInfo) AttachedPicStreams() []ProbeStream {
+	var out []ProbeStream
+	for _, s := range p.Streams {
+		if s.CodecType == "video" && s.IsAttachedPic() {
 			out = append(out, s)
 		}
 	}
@@ -134,6 +147,17 @@ func (p *ProbeInfo) AudioStreams() []ProbeStream {
 	return out
 }
 
+// IsAudioOnly reports whether the file has audio but no real video stream.
+// Embedded cover art (an attached picture) does not count as video.
+func (p *ProbeInfo) IsAudioOnly() bool {
+	return len(p.AudioStreams()) > 0 && len(p.VideoStreams()) == 0
+}
+
+// HasCoverArt reports whether the file embeds an attached picture.
+func (p *ProbeInfo) HasCoverArt() bool {
+	return len(p.AttachedPicStreams()) > 0
+}
+
 // SubtitleStreams returns all subtitle-type streams.
 func (p *ProbeInfo) SubtitleStreams() []ProbeStream {
 	var out []ProbeStream
@@ -161,6 +185,12 @@ func (s ProbeStream) StreamTitle() string {
 	return ""
 }
 
+// IsAttachedPic reports whether the stream is an attached picture (cover
+// art) rather than real video.
+func (s ProbeStream) IsAttachedPic() bool {
+	return s.Disposition["attached_pic"] == 1
+}
+
 // IsDefault returns whether this stream has the default disposition flag.
 func (s ProbeStream) IsDefault() bool {
 	return s.Disposition["default"] == 1
@@ -434,10 +464,11 @@ func BuildColumnsForType(streams []ProbeStream, codecType string) []StreamColumn
 	return cols
 }
 
-// BuildVideoSummaryRows returns InfoPair rows for the first video stream.
+// BuildVideoSummaryRows returns InfoPair rows for the first video stream,
+// skipping attached pictures.
 func BuildVideoSummaryRows(streams []ProbeStream) []InfoPair {
 	for _, s := range streams {
-		if s.CodecType == "video" {
+		if s.CodecType == "video" && !s.IsAttachedPic() {
 			return s.StreamPropertyRows()
 		}
 	}
@@ -447,7 +478,7 @@ func BuildVideoSummaryRows(streams []ProbeStream) []InfoPair {
 // VideoStreamHDRInfo extracts HDR metadata from side_data_list for the video stream.
 func VideoStreamHDRInfo(streams []ProbeStream) []InfoPair {
 	for _, s := range streams {
-		if s.CodecType != "video" {
+		if s.CodecType != "video" || s.IsAttachedPic() {
 			continue
 		}
 		var rows []InfoPair
@@ -0,0 +1,39 @@
+package videoinfo
+
+import "testing"
+
+func TestProbeInfo_IsAudioOnly(t *testing.T) {
+	cover := ProbeStream{CodecType: "video", CodecName: "mjpeg", Disposition: map[string]int{"attached_pic": 1}}
+	video := ProbeStream{CodecType: "video", CodecName: "h264"}
+	audio := ProbeStream{CodecType: "audio", CodecName: "opus"}
+
+	tests := []struct {
+		name      string
+		streams   []ProbeStream
+		audioOnly bool
+		cover     bool
+	}{
+		{"audio", []ProbeStream{audio}, true, false},
+		{"audio with cover art", []ProbeStream{cover, audio}, true, true},
+		{"video and audio", []ProbeStream{video, audio}, false, false},
+		{"video only", []ProbeStream{video}, false, false},
+		{"cover art only", []ProbeStream{cover}, false, true},
+	}
+	for _, tt := range tests {
+		p := &ProbeInfo{Streams: tt.streams}
+		if got := p.IsAudioOnly(); got != tt.audioOnly {
+			t.Errorf("%s: IsAudioOnly() = %v, want %v", tt.name, got, tt.audioOnly)
+		}
+		if got := p.HasCoverArt(); got != tt.cover {
+			t.Errorf("%s: HasCoverArt() = %v, want %v", tt.name, got, tt.cover)
+		}
+		for _, s := range p.VideoStreams() {
+			if s.IsAttachedPic() {
+				t.Errorf("%s: VideoStreams() includes cover art", tt.name)
+			}
+		}
+		if got := len(p.AttachedPicStreams()) > 0; got != tt.cover {
+			t.Errorf("%s: AttachedPicStreams() non-empty = %v, want %v", tt.name, got, tt.cover)
+		}
+	}
+}
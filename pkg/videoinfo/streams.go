@@ -0,0 +1,156 @@
+package videoinfo
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"thirdcoast.systems/rewind/pkg/ffmpeg"
+)
+
+// AngleFilePrefix names extracted camera angles in streams/: angle2.mp4 is the
+// file's second video stream, and so on.
+const AngleFilePrefix = "angle"
+
+// StreamsManifest mirrors streams/manifest.json, written by the ingest
+// service next to a video to list its alternate stream files.
+type StreamsManifest struct {
+	Streams []StreamFile `json:"streams"`
+}
+
+// StreamFile is one alternate stream file: a downloaded quality variant, or
+// an extra camera angle when Angle is set (2 for the file's second video
+// stream, and so on; angle 1 is the main video).
+type StreamFile struct {
+	Filename   string `json:"filename"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Codec      string `json:"codec"`
+	PixFmt     string `json:"pix_fmt,omitempty"`
+	AudioCodec string `json:"audio_codec,omitempty"`
+	Angle      int    `json:"angle,omitempty"`
+}
+
+// ReadStreamsManifest reads streams/manifest.json from videoDir. A missing
+// manifest returns an empty manifest and no error.
+func ReadStreamsManifest(videoDir string) (*StreamsManifest, error) {
+	data, err := os.ReadFile(filepath.Join(videoDir, "streams", "manifest.json"))
+	if os.IsNotExist(err) {
+		return &StreamsManifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m StreamsManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// streamFileExts are the extensions ScanStreams treats as stream files.
+var streamFileExts = map[string]bool{".mp4": true, ".mkv": true, ".webm": true, ".mov": true}
+
+// ScanStreams probes every video file in videoDir/streams and returns a
+// manifest describing them, along with the names of files that could not be
+// probed (which the manifest leaves out). A missing streams directory yields
+// an empty manifest.
+func ScanStreams(ctx context.Context, videoDir string) (*StreamsManifest, []string, error) {
+	streamsDir := filepath.Join(videoDir, "streams")
+	entries, err := os.ReadDir(streamsDir)
+	if os.IsNotExist(err) {
+		return &StreamsManifest{}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := &StreamsManifest{}
+	var failed []string
+	for _, e := range entries {
+		if e.IsDir() || !streamFileExts[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		probe, err := ffmpeg.Probe(ctx, filepath.Join(streamsDir, e.Name()))
+		if err != nil {
+			failed = append(failed, e.Name())
+			continue
+		}
+		m.Streams = append(m.Streams, StreamFile{
+			Filename:   e.Name(),
+			Width:      probe.Width,
+			Height:     probe.Height,
+			Codec:      probe.VideoCodec,
+			PixFmt:     probe.PixelFormat,
+			AudioCodec: probe.AudioCodec,
+			Angle:      AngleFromFilename(e.Name()),
+		})
+	}
+	return m, failed, nil
+}
+
+// Write saves the manifest as videoDir/streams/manifest.json. Nothing is
+// written when videoDir has no streams directory.
+func (m *StreamsManifest) Write(videoDir string) error {
+	streamsDir := filepath.Join(videoDir, "streams")
+	if _, err := os.Stat(streamsDir); os.IsNotExist(err) {
+		return nil
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(streamsDir, "manifest.json"), data, 0o644)
+}
+
+// AngleFromFilename returns N for a streams/angle<N> file, or 0 for anything
+// else.
+func AngleFromFilename(name string) int {
+	rest, ok := strings.CutPrefix(name, AngleFilePrefix)
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(rest, filepath.Ext(rest)))
+	if err != nil || n < 2 {
+		return 0
+	}
+	return n
+}
+
+// Angles returns the manifest's extra camera angles, in manifest order.
+func (m *StreamsManifest) Angles() []StreamFile {
+	var out []StreamFile
+	for _, s := range m.Streams {
+		if s.Angle > 0 {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Qualities returns the manifest's quality variants (streams with a known
+// height that are not angles), in manifest order, leaving out any taller than
+// maxHeight (0 = no cap). When every variant is over the cap the shortest is
+// kept, so capping never leaves a video with no variants at all.
+func (m *StreamsManifest) Qualities(maxHeight int) []StreamFile {
+	var out []StreamFile
+	var shortest *StreamFile
+	for i, s := range m.Streams {
+		if s.Angle > 0 || s.Height <= 0 {
+			continue
+		}
+		if shortest == nil || s.Height < shortest.Height {
+			shortest = &m.Streams[i]
+		}
+		if maxHeight <= 0 || s.Height <= maxHeight {
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 && shortest != nil {
+		out = append(out, *shortest)
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package videoinfo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadStreamsManifest(t *testing.T) {
+	dir := t.TempDir()
+	m, err := ReadStreamsManifest(dir)
+	if err != nil || len(m.Streams) != 0 {
+		t.Fatalf("missing manifest: got %+v, %v; want empty, nil", m, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "streams"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"streams":[
+		{"filename":"video.mp4","width":1920,"height":1080,"codec":"h264"},
+		{"filename":"angle2.mp4","width":1280,"height":720,"codec":"h264","angle":2}
+	]}`
+	if err := os.WriteFile(filepath.Join(dir, "streams", "manifest.json"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err = ReadStreamsManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	angles := m.Angles()
+	if len(m.Streams) != 2 || len(angles) != 1 || angles[0].Filename != "angle2.mp4" || angles[0].Angle != 2 {
+		t.Fatalf("streams = %+v, angles = %+v; want one angle2.mp4", m.Streams, angles)
+	}
+}
+
+func TestStreamsManifestQualities(t *testing.T) {
+	m := &StreamsManifest{Streams: []StreamFile{
+		{Filename: "2160.mp4", Height: 2160},
+		{Filename: "angle2.mp4", Height: 720, Angle: 2},
+		{Filename: "1080.mp4", Height: 1080},
+		{Filename: "unknown.mp4"},
+		{Filename: "1440.mp4", Height: 1440},
+	}}
+	names := func(streams []StreamFile) []string {
+		var out []string
+		for _, s := range streams {
+			out = append(out, s.Filename)
+		}
+		return out
+	}
+	tests := []struct {
+		maxHeight int
+		want      []string
+	}{
+		{0, []string{"2160.mp4", "1080.mp4", "1440.mp4"}},
+		{1440, []string{"1080.mp4", "1440.mp4"}},
+		{1080, []string{"1080.mp4"}},
+		// Everything is over the cap: the shortest variant survives.
+		{720, []string{"1080.mp4"}},
+	}
+	for _, tt := range tests {
+		if got := names(m.Qualities(tt.maxHeight)); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Qualities(%d) = %v, want %v", tt.maxHeight, got, tt.want)
+		}
+	}
+}
+
+func TestAngleFromFilename(t *testing.T) {
+	for name, want := range map[string]int{
+		"angle2.mp4":     2,
+		"angle12.mp4":    12,
+		"angle1.mp4":     0,
+		"angle.mp4":      0,
+		"video_720p.mp4": 0,
+		"angle2.tmp.mp4": 0,
+		"manifest.json":  0,
+	} {
+		if got := AngleFromFilename(name); got != want {
+			t.Errorf("AngleFromFilename(%q) = %d, want %d", name, got, want)
+		}
+	}
+}
+
+func TestStreamsManifestWrite(t *testing.T) {
+	dir := t.TempDir()
+	m := &StreamsManifest{Streams: []StreamFile{{Filename: "video_720p.mp4", Height: 720}}}
+
+	// No streams directory: nothing to describe, nothing written.
+	if err := m.Write(dir); err != nil {
+		t.Fatalf("Write without streams dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "streams")); !os.IsNotExist(err) {
+		t.Fatalf("Write created the streams dir")
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "streams"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Write(dir); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := ReadStreamsManifest(dir)
+	if err != nil || len(got.Streams) != 1 || got.Streams[0].Height != 720 {
+		t.Fatalf("round trip = %+v, %v", got, err)
+	}
+}
@@ -86,26 +86,61 @@ type VideoInfo struct {
 
 	// Available formats (for multi-track display)
 	Formats []FormatInfo `json:"formats"`
+
+	// Chapters as published by the source, in playback order.
+	Chapters []Chapter `json:"chapters"`
+}
+
+// Chapter is a single entry from yt-dlp's chapters list. Times are seconds.
+type Chapter struct {
+	StartTime float64 `json:"start_time"`
+	EndTime   float64 `json:"end_time"`
+	Title     string  `json:"title"`
 }
 
 // FormatInfo represents a single format entry from yt-dlp info.json.
 type FormatInfo struct {
-	FormatID     string  `json:"format_id"`
-	FormatNote   string  `json:"format_note"`
-	Ext          string  `json:"ext"`
-	ACodec       string  `json:"acodec"`
-	VCodec       string  `json:"vcodec"`
-	ABR          float64 `json:"abr"`
-	VBR          float64 `json:"vbr"`
-	ASR          float64 `json:"asr"`
-	FPS          float64 `json:"fps"`
-	Width        float64 `json:"width"`
-	Height       float64 `json:"height"`
-	AudioCh      float64 `json:"audio_channels"`
-	Language     string  `json:"language"`
-	DynamicRange string  `json:"dynamic_range"`
-	Resolution   string  `json:"resolution"`
-	Protocol     string  `json:"protocol"`
+	FormatID       string  `json:"format_id"`
+	FormatNote     string  `json:"format_note"`
+	Ext            string  `json:"ext"`
+	ACodec         string  `json:"acodec"`
+	VCodec         string  `json:"vcodec"`
+	TBR            float64 `json:"tbr"`
+	ABR            float64 `json:"abr"`
+	VBR            float64 `json:"vbr"`
+	ASR            float64 `json:"asr"`
+	FPS            float64 `json:"fps"`
+	Width          float64 `json:"width"`
+	Height         float64 `json:"height"`
+	AudioCh        float64 `json:"audio_channels"`
+	Language       string  `json:"language"`
+	DynamicRange   string  `json:"dynamic_range"`
+	Resolution     string  `json:"resolution"`
+	Protocol       string  `json:"protocol"`
+	Filesize       float64 `json:"filesize"`
+	FilesizeApprox float64 `json:"filesize_approx"`
+}
+
+// Kind classifies the format as "video+audio" (muxed), "video", "audio" or
+// "other" (e.g. storyboards) from its codec fields.
+func (f FormatInfo) Kind() string {
+	hasVideo := codecPresent(f.VCodec)
+	hasAudio := codecPresent(f.ACodec)
+	switch {
+	case hasVideo && hasAudio:
+		return "video+audio"
+	case hasVideo:
+		return "video"
+	case hasAudio:
+		return "audio"
+	default:
+		return "other"
+	}
+}
+
+func codecPresent(codec string) bool {
+	codec = strings.TrimSpace(codec)
+	return codec != "" && codec != "none"
 }
 
 // Scan implements sql.Scanner for JSONB columns.
@@ -0,0 +1,21 @@
+package videoinfo
+
+import "testing"
+
+func TestFormatInfo_Kind(t *testing.T) {
+	tests := []struct {
+		f    FormatInfo
+		want string
+	}{
+		{FormatInfo{VCodec: "avc1.64001F", ACodec: "mp4a.40.2"}, "video+audio"},
+		{FormatInfo{VCodec: "vp09.00.40.08", ACodec: "none"}, "video"},
+		{FormatInfo{VCodec: "none", ACodec: "opus"}, "audio"},
+		{FormatInfo{VCodec: "none", ACodec: "none", FormatNote: "storyboard"}, "other"},
+		{FormatInfo{}, "other"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Kind(); got != tt.want {
+			t.Errorf("Kind(%+v) = %q, want %q", tt.f, got, tt.want)
+		}
+	}
+}
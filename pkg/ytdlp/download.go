@@ -44,7 +44,7 @@ func (c *Client) Download(ctx context.Context, url string, destDir string, extra
 		"--no-colors",
 		"--no-video-multistreams",
 		"--audio-multistreams",
-		"--format", "bestvideo+mergeall/best",
+		"--format", DefaultFormatSelector,
 	}
 	args = append(args, extraArgs...)
 	args = append(args, url)
@@ -55,3 +55,20 @@ func (c *Client) Download(ctx context.Context, url string, destDir string, extra
 	}
 	return nil
 }
+
+// DefaultEmbedSubtitleLangs is the --sub-langs value EmbedSubtitleArgs uses
+// when none is given, matching the sidecar subtitles Download writes.
+const DefaultEmbedSubtitleLangs = "en"
+
+// EmbedSubtitleArgs are the yt-dlp arguments that mux subtitles into the
+// downloaded container as selectable tracks. langs is a --sub-langs value
+// such as "en,de" or "all,-live_chat"; empty means DefaultEmbedSubtitleLangs.
+// --sub-langs also picks which sidecar subtitle files are written, so the
+// sidecars and embedded tracks match.
+func EmbedSubtitleArgs(langs string) []string {
+	langs = strings.TrimSpace(langs)
+	if langs == "" {
+		langs = DefaultEmbedSubtitleLangs
+	}
+	return []string{"--embed-subs", "--sub-langs", langs}
+}
@@ -0,0 +1,81 @@
+package ytdlp
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error codes stored on failed download jobs so the UI can offer a fix
+// instead of a raw yt-dlp error.
+const (
+	ErrorCodeAgeRestricted = "age_restricted"
+	ErrorCodeLoginRequired = "login_required"
+	ErrorCodeMembersOnly   = "members_only"
+	ErrorCodePrivate       = "private"
+)
+
+// errorPatterns map lowercase yt-dlp stderr fragments to error codes. Order
+// matters: the age check also says "sign in", so it must match first.
+// YouTube's "This content isn't available" is left out: it is mostly rate
+// limiting, which cookies don't fix and a later retry does.
+var errorPatterns = []struct {
+	code     string
+	patterns []string
+}{
+	{ErrorCodeAgeRestricted, []string{
+		"sign in to confirm your age",
+		"age-restricted",
+		"age restricted",
+		"inappropriate for some users",
+	}},
+	{ErrorCodeMembersOnly, []string{
+		"members-only",
+		"available to this channel's members",
+		"join this channel to get access",
+	}},
+	{ErrorCodePrivate, []string{
+		"private video",
+		"this video is private",
+	}},
+	{ErrorCodeLoginRequired, []string{
+		"sign in to confirm you",
+		"login required",
+		"requires authentication",
+		"you need to log in",
+		"use --cookies",
+		"--cookies-from-browser or --cookies",
+	}},
+}
+
+// ClassifyError maps a yt-dlp failure to one of the ErrorCode* constants, or
+// "" when the failure is not recognised. Only stderr is inspected for
+// *ExecError; other errors are matched on their message.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+	text := err.Error()
+	var execErr *ExecError
+	if errors.As(err, &execErr) {
+		text = execErr.Stderr
+	}
+	text = strings.ToLower(text)
+	for _, group := range errorPatterns {
+		for _, p := range group.patterns {
+			if strings.Contains(text, p) {
+				return group.code
+			}
+		}
+	}
+	return ""
+}
+
+// RequiresCookies reports whether a job failing with code is likely to
+// succeed on retry once the user has uploaded their login cookies.
+func RequiresCookies(code string) bool {
+	switch code {
+	case ErrorCodeAgeRestricted, ErrorCodeLoginRequired, ErrorCodeMembersOnly, ErrorCodePrivate:
+		return true
+	}
+	return false
+}
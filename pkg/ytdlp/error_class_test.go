@@ -0,0 +1,43 @@
+package ytdlp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		stderr string
+		want   string
+	}{
+		{"ERROR: [youtube] abc: Sign in to confirm your age. This video may be inappropriate for some users.", ErrorCodeAgeRestricted},
+		{"ERROR: [youtube] abc: Sign in to confirm you’re not a bot. Use --cookies-from-browser or --cookies for the authentication.", ErrorCodeLoginRequired},
+		{"ERROR: [youtube] abc: Join this channel to get access to members-only content like this video", ErrorCodeMembersOnly},
+		{"ERROR: [youtube] abc: Private video. Sign in if you've been granted access to this video", ErrorCodePrivate},
+		{"ERROR: [instagram] xyz: Requested content is not available, rate-limit reached or login required.", ErrorCodeLoginRequired},
+		{"ERROR: [youtube] abc: This content isn't available, try again later.", ""},
+		{"ERROR: [generic] Unable to download webpage: HTTP Error 404: Not Found", ""},
+	}
+	for _, tc := range cases {
+		err := fmt.Errorf("download: %w", &ExecError{Cmd: "yt-dlp", ExitCode: 1, Stderr: tc.stderr})
+		if got := ClassifyError(err); got != tc.want {
+			t.Errorf("ClassifyError(%q) = %q, want %q", tc.stderr, got, tc.want)
+		}
+	}
+}
+
+func TestClassifyError_NonExec(t *testing.T) {
+	if got := ClassifyError(nil); got != "" {
+		t.Fatalf("ClassifyError(nil) = %q", got)
+	}
+	if got := ClassifyError(errors.New("This video is private")); got != ErrorCodePrivate {
+		t.Fatalf("ClassifyError(plain) = %q, want %q", got, ErrorCodePrivate)
+	}
+}
+
+func TestRequiresCookies(t *testing.T) {
+	if !RequiresCookies(ErrorCodeAgeRestricted) || RequiresCookies("") || RequiresCookies("other") {
+		t.Fatalf("RequiresCookies returned unexpected results")
+	}
+}
@@ -0,0 +1,256 @@
+package ytdlp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxFormatSelectorLen bounds user-influenced -f values.
+const maxFormatSelectorLen = 256
+
+// CodecPreset describes how to request a specific video/audio codec pairing
+// from yt-dlp. Codec strings in yt-dlp formats are RFC 6381 style
+// ("avc1.640028", "mp4a.40.2"), so presets match on prefixes.
+type CodecPreset struct {
+	Name         string
+	VCodecPrefix []string
+	ACodecPrefix []string
+	MergeOutput  string
+}
+
+// codecPresets are the codec preferences accepted by the download-format API.
+// Each preset only selects codecs its MergeOutput container carries natively
+// (see containerCodecs), so yt-dlp never muxes e.g. VP9/Opus into MP4.
+var codecPresets = map[string]CodecPreset{
+	"h264": {Name: "h264", VCodecPrefix: []string{"avc1"}, ACodecPrefix: []string{"mp4a"}, MergeOutput: "mp4"},
+	"hevc": {Name: "hevc", VCodecPrefix: []string{"hvc1", "hev1"}, ACodecPrefix: []string{"mp4a"}, MergeOutput: "mp4"},
+	"vp9":  {Name: "vp9", VCodecPrefix: []string{"vp09", "vp9"}, ACodecPrefix: []string{"opus"}, MergeOutput: "webm"},
+	"av1":  {Name: "av1", VCodecPrefix: []string{"av01"}, ACodecPrefix: []string{"mp4a"}, MergeOutput: "mp4"},
+}
+
+// containerCodecs lists the video and audio codec prefixes each merge
+// container accepts.
+var containerCodecs = map[string]struct{ video, audio []string }{
+	"mp4":  {video: []string{"avc1", "hvc1", "hev1", "av01"}, audio: []string{"mp4a"}},
+	"webm": {video: []string{"vp09", "vp9", "av01"}, audio: []string{"opus", "vorbis"}},
+}
+
+// fitsContainer reports whether every codec the preset can select is one its
+// MergeOutput container accepts.
+func (p CodecPreset) fitsContainer() bool {
+	c, ok := containerCodecs[p.MergeOutput]
+	if !ok {
+		return false
+	}
+	for _, v := range p.VCodecPrefix {
+		if !hasAnyPrefix(v, c.video) {
+			return false
+		}
+	}
+	for _, a := range p.ACodecPrefix {
+		if !hasAnyPrefix(a, c.audio) {
+			return false
+		}
+	}
+	return true
+}
+
+// codecAliases maps common spellings to preset names.
+var codecAliases = map[string]string{
+	"avc":  "h264",
+	"avc1": "h264",
+	"h265": "hevc",
+	"vp09": "vp9",
+	"av01": "av1",
+}
+
+// CodecPresetNames returns the accepted codec preference names, sorted.
+func CodecPresetNames() []string {
+	names := make([]string, 0, len(codecPresets))
+	for name := range codecPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupCodecPreset resolves a codec preference (case-insensitive, aliases allowed).
+func LookupCodecPreset(codec string) (CodecPreset, bool) {
+	codec = strings.ToLower(strings.TrimSpace(codec))
+	if alias, ok := codecAliases[codec]; ok {
+		codec = alias
+	}
+	p, ok := codecPresets[codec]
+	return p, ok
+}
+
+// FormatSelector builds a -f selector that only matches the preset's codecs,
+// e.g. "bv*[vcodec^=avc1]+ba[acodec^=mp4a]/b[vcodec^=avc1][acodec^=mp4a]".
+// There is deliberately no trailing "/best": a source without the codec must
+// fail rather than silently download something else.
+func (p CodecPreset) FormatSelector() string {
+	var alts []string
+	for _, v := range p.VCodecPrefix {
+		for _, a := range p.ACodecPrefix {
+			alts = append(alts, fmt.Sprintf("bv*[vcodec^=%s]+ba[acodec^=%s]", v, a))
+		}
+	}
+	for _, v := range p.VCodecPrefix {
+		for _, a := range p.ACodecPrefix {
+			alts = append(alts, fmt.Sprintf("b[vcodec^=%s][acodec^=%s]", v, a))
+		}
+	}
+	return strings.Join(alts, "/")
+}
+
+// DownloadArgs returns the yt-dlp arguments selecting this preset.
+func (p CodecPreset) DownloadArgs() []string {
+	args := []string{"-f", p.FormatSelector()}
+	if p.MergeOutput != "" {
+		args = append(args, "--merge-output-format", p.MergeOutput)
+	}
+	return args
+}
+
+// MatchesVCodec reports whether a yt-dlp vcodec string belongs to this preset.
+func (p CodecPreset) MatchesVCodec(vcodec string) bool {
+	return hasAnyPrefix(strings.ToLower(vcodec), p.VCodecPrefix)
+}
+
+// MatchesACodec reports whether a yt-dlp acodec string belongs to this preset.
+func (p CodecPreset) MatchesACodec(acodec string) bool {
+	return hasAnyPrefix(strings.ToLower(acodec), p.ACodecPrefix)
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFormatSelector rejects -f values that could be mistaken for flags,
+// contain shell/whitespace characters, or have unbalanced filter brackets.
+func ValidateFormatSelector(sel string) error {
+	if sel == "" {
+		return errors.New("format selector is empty")
+	}
+	if len(sel) > maxFormatSelectorLen {
+		return fmt.Errorf("format selector exceeds %d characters", maxFormatSelectorLen)
+	}
+	if strings.HasPrefix(sel, "-") {
+		return errors.New("format selector must not start with '-'")
+	}
+	depth := 0
+	for _, ch := range sel {
+		switch {
+		case ch >= '0' && ch <= '9', ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z':
+		case strings.ContainsRune("+/,._-*^$=<>!~?:()", ch):
+		case ch == '[':
+			depth++
+			if depth > 1 {
+				return errors.New("format selector has nested '['")
+			}
+		case ch == ']':
+			depth--
+			if depth < 0 {
+				return errors.New("format selector has unbalanced ']'")
+			}
+		default:
+			return fmt.Errorf("format selector contains invalid character %q", ch)
+		}
+	}
+	if depth != 0 {
+		return errors.New("format selector has unbalanced '['")
+	}
+	return nil
+}
+
+// IsFormatUnavailable reports whether err is yt-dlp refusing a -f selector
+// because the source offers no matching format.
+func IsFormatUnavailable(err error) bool {
+	var execErr *ExecError
+	if !errors.As(err, &execErr) {
+		return false
+	}
+	return strings.Contains(execErr.Stderr, "Requested format is not available")
+}
+
+// DefaultFormatSelector is the selector Download uses when no -f is given:
+// the best video merged with every audio track, for sources with several
+// languages. Download pairs it with --audio-multistreams.
+const DefaultFormatSelector = "bestvideo+mergeall/best"
+
+// CapFormatHeight restricts every format in sel to at most maxHeight pixels
+// tall by appending [height<=?N] to each one, e.g. "bv*+ba/b" becomes
+// "bv*[height<=?1080]+ba[height<=?1080]/b[height<=?1080]". The "?" lets
+// formats without a known height, such as audio-only streams, through.
+// "mergeall" is left alone: it stands for every audio track, which has no
+// height. maxHeight <= 0 returns sel unchanged.
+func CapFormatHeight(sel string, maxHeight int) string {
+	if maxHeight <= 0 || sel == "" {
+		return sel
+	}
+	filter := fmt.Sprintf("[height<=?%d]", maxHeight)
+	var b strings.Builder
+	var name strings.Builder
+	depth := 0
+	inFormat := false
+	endFormat := func() {
+		if inFormat && name.String() != "mergeall" {
+			b.WriteString(filter)
+		}
+		inFormat = false
+		name.Reset()
+	}
+	for _, ch := range sel {
+		switch {
+		case ch == '[':
+			depth++
+		case ch == ']':
+			depth--
+		case depth == 0 && strings.ContainsRune("+/,()", ch):
+			endFormat()
+			b.WriteRune(ch)
+			continue
+		case depth == 0:
+			name.WriteRune(ch)
+		}
+		inFormat = true
+		b.WriteRune(ch)
+	}
+	endFormat()
+	return b.String()
+}
+
+// CapDownloadArgs returns a copy of yt-dlp args with the -f selector capped
+// to maxHeight (see CapFormatHeight), adding a capped DefaultFormatSelector
+// when args have none. The result is validated. maxHeight <= 0 returns args
+// unchanged.
+func CapDownloadArgs(args []string, maxHeight int) ([]string, error) {
+	if maxHeight <= 0 {
+		return args, nil
+	}
+	out := make([]string, 0, len(args)+2)
+	found := false
+	for i := 0; i < len(args); i++ {
+		out = append(out, args[i])
+		if args[i] == "-f" && i+1 < len(args) {
+			i++
+			sel := CapFormatHeight(args[i], maxHeight)
+			if err := ValidateFormatSelector(sel); err != nil {
+				return nil, err
+			}
+			out = append(out, sel)
+			found = true
+		}
+	}
+	if !found {
+		out = append(out, "-f", CapFormatHeight(DefaultFormatSelector, maxHeight))
+	}
+	return out, nil
+}
@@ -0,0 +1,140 @@
+package ytdlp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLookupCodecPreset_Aliases(t *testing.T) {
+	for _, in := range []string{"h264", "H264", " avc1 ", "avc"} {
+		p, ok := LookupCodecPreset(in)
+		if !ok || p.Name != "h264" {
+			t.Fatalf("LookupCodecPreset(%q) = %q, %v; want h264", in, p.Name, ok)
+		}
+	}
+	if _, ok := LookupCodecPreset("mpeg2"); ok {
+		t.Fatalf("expected mpeg2 to be rejected")
+	}
+}
+
+func TestCodecPreset_FormatSelector_H264(t *testing.T) {
+	p, _ := LookupCodecPreset("h264")
+	want := "bv*[vcodec^=avc1]+ba[acodec^=mp4a]/b[vcodec^=avc1][acodec^=mp4a]"
+	if got := p.FormatSelector(); got != want {
+		t.Fatalf("FormatSelector() = %q, want %q", got, want)
+	}
+}
+
+func TestCodecPreset_SelectorsValidate(t *testing.T) {
+	for _, name := range CodecPresetNames() {
+		p, _ := LookupCodecPreset(name)
+		if err := ValidateFormatSelector(p.FormatSelector()); err != nil {
+			t.Errorf("%s selector failed validation: %v", name, err)
+		}
+	}
+}
+
+func TestCodecPreset_FitsContainer(t *testing.T) {
+	for _, name := range CodecPresetNames() {
+		p, _ := LookupCodecPreset(name)
+		if !p.fitsContainer() {
+			t.Errorf("%s selects codecs that %s does not carry", name, p.MergeOutput)
+		}
+	}
+	bad := CodecPreset{VCodecPrefix: []string{"vp09"}, ACodecPrefix: []string{"opus"}, MergeOutput: "mp4"}
+	if bad.fitsContainer() {
+		t.Fatalf("expected vp9/opus not to fit mp4")
+	}
+}
+
+func TestCodecPreset_Matches(t *testing.T) {
+	p, _ := LookupCodecPreset("hevc")
+	if !p.MatchesVCodec("hvc1.2.4.L153.B0") || !p.MatchesVCodec("HEV1.1.6") {
+		t.Fatalf("expected hevc codec strings to match")
+	}
+	if p.MatchesVCodec("avc1.640028") {
+		t.Fatalf("expected avc1 not to match hevc")
+	}
+	if !p.MatchesACodec("mp4a.40.2") || p.MatchesACodec("opus") {
+		t.Fatalf("unexpected hevc audio matching")
+	}
+}
+
+func TestValidateFormatSelector(t *testing.T) {
+	valid := []string{"137+140/best", "303,251/best", "bv*[height<=1080]+ba/b"}
+	for _, sel := range valid {
+		if err := ValidateFormatSelector(sel); err != nil {
+			t.Errorf("ValidateFormatSelector(%q) = %v, want nil", sel, err)
+		}
+	}
+	invalid := []string{"", "--exec rm", "bv [x]", "bv[[x]]", "bv[x", "bv]x[", "b;ls", "b`id`"}
+	for _, sel := range invalid {
+		if err := ValidateFormatSelector(sel); err == nil {
+			t.Errorf("ValidateFormatSelector(%q) = nil, want error", sel)
+		}
+	}
+}
+
+func TestIsFormatUnavailable(t *testing.T) {
+	err := &ExecError{Stderr: "ERROR: [youtube] abc: Requested format is not available. Use --list-formats"}
+	if !IsFormatUnavailable(err) {
+		t.Fatalf("expected format-unavailable error to be detected")
+	}
+	if IsFormatUnavailable(&ExecError{Stderr: "HTTP Error 403"}) || IsFormatUnavailable(errors.New("boom")) {
+		t.Fatalf("unexpected match")
+	}
+}
+
+func TestCapFormatHeight(t *testing.T) {
+	cases := []struct {
+		sel  string
+		h    int
+		want string
+	}{
+		{"bv*+ba/b", 1080, "bv*[height<=?1080]+ba[height<=?1080]/b[height<=?1080]"},
+		{"303+251/best", 720, "303[height<=?720]+251[height<=?720]/best[height<=?720]"},
+		{"bv*[vcodec^=avc1]+ba[acodec^=mp4a]", 480, "bv*[vcodec^=avc1][height<=?480]+ba[acodec^=mp4a][height<=?480]"},
+		{"(bv*+ba/b)", 360, "(bv*[height<=?360]+ba[height<=?360]/b[height<=?360])"},
+		{"bestvideo+mergeall/best", 1080, "bestvideo[height<=?1080]+mergeall/best[height<=?1080]"},
+		{"bv*+ba/b", 0, "bv*+ba/b"},
+	}
+	for _, tc := range cases {
+		if got := CapFormatHeight(tc.sel, tc.h); got != tc.want {
+			t.Errorf("CapFormatHeight(%q, %d) = %q, want %q", tc.sel, tc.h, got, tc.want)
+		}
+	}
+}
+
+func TestCapDownloadArgs(t *testing.T) {
+	got, err := CapDownloadArgs([]string{"--add-header", "X:1"}, 1080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"--add-header", "X:1", "-f", "bestvideo[height<=?1080]+mergeall/best[height<=?1080]"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("CapDownloadArgs without -f = %q, want %q", got, want)
+	}
+
+	p, _ := LookupCodecPreset("h264")
+	got, err = CapDownloadArgs(p.DownloadArgs(), 720)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != "-f" || got[1] != CapFormatHeight(p.FormatSelector(), 720) || got[2] != "--merge-output-format" {
+		t.Fatalf("CapDownloadArgs(h264) = %q", got)
+	}
+
+	if got, _ := CapDownloadArgs([]string{"-f", "best"}, 0); len(got) != 2 || got[1] != "best" {
+		t.Fatalf("CapDownloadArgs with no cap = %q, want unchanged", got)
+	}
+}
+
+func TestCapDownloadArgs_PresetsValidate(t *testing.T) {
+	for _, name := range CodecPresetNames() {
+		p, _ := LookupCodecPreset(name)
+		if _, err := CapDownloadArgs(p.DownloadArgs(), 2160); err != nil {
+			t.Errorf("%s capped selector failed validation: %v", name, err)
+		}
+	}
+}
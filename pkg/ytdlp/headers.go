@@ -0,0 +1,104 @@
+package ytdlp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Limits for user-supplied request headers.
+const (
+	maxRequestHeaders   = 20
+	maxHeaderNameLen    = 64
+	maxHeaderValueLen   = 1024
+	maxUserAgentLen     = 512
+	headerTokenSpecials = "!#$%&'*+-.^_`|~"
+)
+
+// RequestHeaderArgs converts per-job request headers and a user-agent into
+// yt-dlp arguments ("--add-header Name:Value", "--user-agent UA"). Names must
+// be RFC 7230 tokens and values printable ASCII, so neither can smuggle in
+// another yt-dlp flag or split into extra arguments. Headers are emitted in
+// name order so the stored args are deterministic.
+func RequestHeaderArgs(headers map[string]string, userAgent string) ([]string, error) {
+	if len(headers) > maxRequestHeaders {
+		return nil, fmt.Errorf("at most %d headers are allowed", maxRequestHeaders)
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, 0, 2*len(headers)+2)
+	seen := make(map[string]bool, len(headers))
+	for _, name := range names {
+		value := strings.TrimSpace(headers[name])
+		name = strings.TrimSpace(name)
+		if err := validateHeaderName(name); err != nil {
+			return nil, err
+		}
+		lower := strings.ToLower(name)
+		if lower == "user-agent" {
+			return nil, errors.New("set the user agent with user_agent, not a header")
+		}
+		if seen[lower] {
+			return nil, fmt.Errorf("duplicate header %q", name)
+		}
+		seen[lower] = true
+		if err := validateHeaderValue(value, maxHeaderValueLen); err != nil {
+			return nil, fmt.Errorf("header %q: %w", name, err)
+		}
+		args = append(args, "--add-header", name+":"+value)
+	}
+
+	if userAgent = strings.TrimSpace(userAgent); userAgent != "" {
+		if err := validateHeaderValue(userAgent, maxUserAgentLen); err != nil {
+			return nil, fmt.Errorf("user agent: %w", err)
+		}
+		// yt-dlp's option parser would read a leading '-' as a new flag.
+		if strings.HasPrefix(userAgent, "-") {
+			return nil, errors.New("user agent must not start with '-'")
+		}
+		args = append(args, "--user-agent", userAgent)
+	}
+	return args, nil
+}
+
+func validateHeaderName(name string) error {
+	if name == "" {
+		return errors.New("header name is empty")
+	}
+	if len(name) > maxHeaderNameLen {
+		return fmt.Errorf("header name exceeds %d characters", maxHeaderNameLen)
+	}
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("header name %q must not start with '-'", name)
+	}
+	for _, ch := range name {
+		switch {
+		case ch >= '0' && ch <= '9', ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z':
+		case strings.ContainsRune(headerTokenSpecials, ch):
+		default:
+			return fmt.Errorf("header name %q contains invalid character %q", name, ch)
+		}
+	}
+	return nil
+}
+
+func validateHeaderValue(value string, maxLen int) error {
+	if value == "" {
+		return errors.New("value is empty")
+	}
+	if len(value) > maxLen {
+		return fmt.Errorf("value exceeds %d characters", maxLen)
+	}
+	for _, ch := range value {
+		if ch < 0x20 || ch > 0x7e {
+			return fmt.Errorf("value contains invalid character %q", ch)
+		}
+	}
+	return nil
+}
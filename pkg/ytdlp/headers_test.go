@@ -0,0 +1,56 @@
+package ytdlp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRequestHeaderArgs_Valid(t *testing.T) {
+	args, err := RequestHeaderArgs(map[string]string{
+		"X-Token": " abc123 ",
+		"Referer": "https://example.com/page",
+	}, "Mozilla/5.0 (X11; Linux x86_64)")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"--add-header", "Referer:https://example.com/page",
+		"--add-header", "X-Token:abc123",
+		"--user-agent", "Mozilla/5.0 (X11; Linux x86_64)",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %q, want %q", args, want)
+	}
+}
+
+func TestRequestHeaderArgs_Empty(t *testing.T) {
+	args, err := RequestHeaderArgs(nil, "  ")
+	if err != nil || len(args) != 0 {
+		t.Fatalf("RequestHeaderArgs(nil, blank) = %q, %v; want no args", args, err)
+	}
+}
+
+func TestRequestHeaderArgs_Rejects(t *testing.T) {
+	cases := []struct {
+		name    string
+		headers map[string]string
+		ua      string
+	}{
+		{"flag as name", map[string]string{"--exec": "rm"}, ""},
+		{"space in name", map[string]string{"X Token": "v"}, ""},
+		{"colon in name", map[string]string{"X:Token": "v"}, ""},
+		{"newline in value", map[string]string{"X-Token": "a\r\nX-Other: b"}, ""},
+		{"empty value", map[string]string{"X-Token": ""}, ""},
+		{"user-agent header", map[string]string{"user-agent": "curl"}, ""},
+		{"duplicate", map[string]string{"X-A": "1", "x-a": "2"}, ""},
+		{"long value", map[string]string{"X-A": strings.Repeat("a", maxHeaderValueLen+1)}, ""},
+		{"flag as user agent", nil, "--exec rm"},
+		{"control in user agent", nil, "agent\x00"},
+	}
+	for _, tc := range cases {
+		if args, err := RequestHeaderArgs(tc.headers, tc.ua); err == nil {
+			t.Errorf("%s: expected error, got args %q", tc.name, args)
+		}
+	}
+}
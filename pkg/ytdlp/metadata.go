@@ -31,6 +31,20 @@ func (c *Client) DumpInfoJSON(ctx context.Context, url string, destPath string,
 	return os.WriteFile(destPath, info.Raw, 0o644)
 }
 
+// MaxComments is the total comment cap passed to yt-dlp's youtube extractor.
+// A fetch that returns this many comments was probably truncated.
+const MaxComments = 4000
+
+// CommentArgs are the yt-dlp arguments that embed comments in the info JSON.
+// max_comments is max-comments,max-parents,max-replies,max-replies-per-thread.
+// A bare total cap (the old 2500,all,all,all) gets fully consumed by top-level
+// comments on large videos, so reply threads were never fetched. Allow a
+// larger total and bound replies-per-thread so threads come in too.
+var CommentArgs = []string{
+	"--write-comments",
+	"--extractor-args", fmt.Sprintf("youtube:max_comments=%d,all,all,8", MaxComments),
+}
+
 // WriteComments asks yt-dlp to write comments json into destDir.
 // Not all extractors support comments; callers may treat failures as best-effort.
 func (c *Client) WriteComments(ctx context.Context, url string, destDir string, extraArgs ...string) error {
@@ -43,14 +57,34 @@ func (c *Client) WriteComments(ctx context.Context, url string, destDir string,
 
 	tmpl := filepath.Join(destDir, "%(extractor)s_%(id)s.%(ext)s")
 
+	args := []string{"--skip-download"}
+	args = append(args, CommentArgs...)
+	args = append(args, "-o", tmpl)
+	args = append(args, extraArgs...)
+	args = append(args, url)
+
+	stdout, stderr, err := c.exec(ctx, args...)
+	if err != nil {
+		return wrapExecError(c.PathOrDefault(), args, stdout, stderr, err)
+	}
+	return nil
+}
+
+// WriteThumbnail asks yt-dlp to download the thumbnail into destDir.
+// Not all extractors support thumbnails; callers may treat failures as best-effort.
+func (c *Client) WriteThumbnail(ctx context.Context, url string, destDir string, extraArgs ...string) error {
+	if strings.TrimSpace(url) == "" {
+		return fmt.Errorf("ytdlp: url is required")
+	}
+	if strings.TrimSpace(destDir) == "" {
+		return fmt.Errorf("ytdlp: destDir is required")
+	}
+
+	tmpl := filepath.Join(destDir, "%(extractor)s_%(id)s.%(ext)s")
+
 	args := []string{
 		"--skip-download",
-		"--write-comments",
-		// max_comments is max-comments,max-parents,max-replies,max-replies-per-thread.
-		// A bare total cap (the old 2500,all,all,all) gets fully consumed by
-		// top-level comments on large videos, so reply threads were never fetched.
-		// Allow a larger total and bound replies-per-thread so threads come in too.
-		"--extractor-args", "youtube:max_comments=4000,all,all,8",
+		"--write-thumbnail",
 		"-o", tmpl,
 	}
 	args = append(args, extraArgs...)
@@ -63,9 +97,9 @@ func (c *Client) WriteComments(ctx context.Context, url string, destDir string,
 	return nil
 }
 
-// WriteThumbnail asks yt-dlp to download the thumbnail into destDir.
-// Not all extractors support thumbnails; callers may treat failures as best-effort.
-func (c *Client) WriteThumbnail(ctx context.Context, url string, destDir string, extraArgs ...string) error {
+// WriteMetadata writes the .info.json and thumbnail into destDir without
+// downloading any media. It is the whole download for metadata-only archiving.
+func (c *Client) WriteMetadata(ctx context.Context, url string, destDir string, extraArgs ...string) error {
 	if strings.TrimSpace(url) == "" {
 		return fmt.Errorf("ytdlp: url is required")
 	}
@@ -77,6 +111,7 @@ func (c *Client) WriteThumbnail(ctx context.Context, url string, destDir string,
 
 	args := []string{
 		"--skip-download",
+		"--write-info-json",
 		"--write-thumbnail",
 		"-o", tmpl,
 	}
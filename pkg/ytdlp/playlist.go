@@ -13,10 +13,14 @@ type FlatEntry struct {
 	ID    string // yt-dlp entry id (e.g. the YouTube video id)
 	URL   string // entry URL (often a canonical watch URL; may be empty for some extractors)
 	Title string
+	// Collection marks an entry that is itself a playlist or channel tab
+	// rather than a video (e.g. the tabs listed for a channel's root page).
+	Collection bool
 }
 
 // ListPlaylistEntries enumerates a playlist/channel/user URL WITHOUT downloading,
-// using yt-dlp --flat-playlist. Returns one FlatEntry per contained video.
+// using yt-dlp --flat-playlist. Returns one FlatEntry per contained video;
+// entries that are themselves collections are returned with Collection set.
 //
 // It uses: --flat-playlist --dump-single-json --skip-download, which yields a
 // single JSON object with an "entries" array. When given a non-playlist URL,
@@ -47,6 +51,8 @@ func (c *Client) ListPlaylistEntries(ctx context.Context, url string, extraArgs
 			ID    string `json:"id"`
 			URL   string `json:"url"`
 			Title string `json:"title"`
+			Type  string `json:"_type"`
+			IEKey string `json:"ie_key"`
 		} `json:"entries"`
 	}
 	if err := json.Unmarshal(raw, &payload); err != nil {
@@ -72,9 +78,10 @@ func (c *Client) ListPlaylistEntries(ctx context.Context, url string, extraArgs
 			continue
 		}
 		entries = append(entries, FlatEntry{
-			ID:    e.ID,
-			URL:   e.URL,
-			Title: e.Title,
+			ID:         e.ID,
+			URL:        e.URL,
+			Title:      e.Title,
+			Collection: e.Type == "playlist" || e.IEKey == "YoutubeTab",
 		})
 	}
 
@@ -35,6 +35,32 @@ func TestListPlaylistEntries_ParsesEntries(t *testing.T) {
 	}
 }
 
+func TestListPlaylistEntries_MarksNestedCollections(t *testing.T) {
+	c := New()
+	c.execFn = func(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+		// A channel root lists its tabs; a playlists tab lists playlists.
+		return []byte(`{
+			"id": "UC123",
+			"entries": [
+				{"_type": "url", "ie_key": "YoutubeTab", "id": "UC123", "url": "https://www.youtube.com/@chan/videos", "title": "Videos"},
+				{"_type": "playlist", "id": "PL9", "url": "https://www.youtube.com/playlist?list=PL9", "title": "Nested"},
+				{"_type": "url", "ie_key": "Youtube", "id": "vid", "url": "https://www.youtube.com/watch?v=vid", "title": "Video"}
+			]
+		}`), nil, nil
+	}
+
+	entries, err := c.ListPlaylistEntries(context.Background(), "https://www.youtube.com/@chan")
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	if !entries[0].Collection || !entries[1].Collection || entries[2].Collection {
+		t.Fatalf("unexpected collection flags: %+v", entries)
+	}
+}
+
 func TestListPlaylistEntries_SingleVideo(t *testing.T) {
 	c := New()
 	c.execFn = func(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
@@ -52,7 +52,7 @@ class VideoPlayer {
     this.positionSaveThreshold = 2; // Save every 2 seconds of playback change
     
     // Quality switching
-    this.qualities = []; // [{label, src, height}]
+    this.qualities = []; // [{label, src, height, angle, playable}]
     this.qualitySelect = null;
     this._switchingQuality = false;
 
@@ -204,7 +204,7 @@ class VideoPlayer {
     try {
       const raw = this.container.dataset.qualities;
       if (!raw) return;
-      this.qualities = JSON.parse(raw); // [{label, src, height}]
+      this.qualities = JSON.parse(raw); // [{label, src, height, angle, playable}]
     } catch (_) {
       return;
     }
@@ -239,6 +239,31 @@ class VideoPlayer {
     this.qualitySelect.addEventListener('change', () => {
       this._switchQuality(this.qualitySelect.value);
     });
+
+    // The server flags originals this browser can't decode (e.g. AC3 audio,
+    // HEVC on Firefox); start on the best alternate stream that passes the
+    // same check instead. Angles are other cameras, not stand-ins.
+    if (!this.originalPlayable()) {
+      const fallback = this.qualities.find((q) => !q.angle && q.playable !== false);
+      if (fallback) {
+        this.qualitySelect.value = fallback.src;
+        this._switchQuality(fallback.src);
+      }
+    }
+  }
+
+  /**
+   * Whether the original file is safe for direct playback in this browser,
+   * per the data-playback-compat assessment. Defaults to true when absent.
+   */
+  originalPlayable() {
+    try {
+      const raw = this.container.dataset.playbackCompat;
+      if (!raw) return true;
+      return JSON.parse(raw).direct !== false;
+    } catch (_) {
+      return true;
+    }
   }
 
   /**
@@ -855,6 +880,7 @@ class MarkerManager {
   constructor(player) {
     this.player = player;
     this.markers = [];
+    this.chapters = [];
     this.skipSegments = [];
     this.lastSkipCheck = 0;
     this.renderedForDuration = null;
@@ -865,6 +891,7 @@ class MarkerManager {
 	  this.listEl = this.panel?.querySelector('[data-markers-list]') || null;
 
     this.load();
+    this.loadChapters();
   }
 
 	findPanel() {
@@ -872,6 +899,21 @@ class MarkerManager {
 		return document.querySelector(`[data-video-panel][data-video-id="${CSS.escape(this.player.videoID)}"]`);
 	}
 
+  async loadChapters() {
+    if (!this.player.videoID) return;
+    try {
+      const res = await fetch(`/api/videos/${encodeURIComponent(this.player.videoID)}/chapters`, {
+        headers: { 'Accept': 'application/json' }
+      });
+      if (!res.ok) return;
+      this.chapters = await res.json();
+      this.renderedForDuration = null;
+      this.renderIfNeeded();
+    } catch (e) {
+      console.warn('Failed to load chapters', e);
+    }
+  }
+
   async load() {
     if (!this.player.videoID) return;
     this.loading = true;
@@ -1003,7 +1045,7 @@ class MarkerManager {
   }
 
   clearTicks() {
-    this.player.progressBar.querySelectorAll('.marker-tick, .marker-range').forEach(el => el.remove());
+    this.player.progressBar.querySelectorAll('.marker-tick, .marker-range, .chapter-tick').forEach(el => el.remove());
   }
 
   render() {
@@ -1013,6 +1055,24 @@ class MarkerManager {
     const duration = this.player.video.duration;
     if (!duration || !isFinite(duration) || duration <= 0) return;
 
+    // Chapter boundaries; the first chapter's start needs no divider.
+    (this.chapters || []).forEach(ch => {
+      const start = typeof ch.start === 'number' ? ch.start : NaN;
+      if (!isFinite(start) || start <= 0 || start >= duration) return;
+
+      const tick = document.createElement('div');
+      tick.className = 'chapter-tick';
+      tick.style.left = `${(start / duration) * 100}%`;
+      if (ch.title) {
+        tick.title = ch.title;
+      }
+      tick.addEventListener('click', (e) => {
+        e.stopPropagation();
+        this.player.video.currentTime = start;
+      });
+      this.player.progressBar.appendChild(tick);
+    });
+
     (this.markers || []).forEach(m => {
       const ts = typeof m.timestamp === 'number' ? m.timestamp : NaN;
       if (!isFinite(ts) || ts < 0 || ts > duration) return;
@@ -1119,6 +1179,11 @@ class TranscriptManager {
   attach() {
     if (this.searchEl) {
       this.searchEl.addEventListener('input', () => this.applyFilter());
+      this.searchEl.addEventListener('keydown', (e) => {
+        if (e.key !== 'Enter') return;
+        e.preventDefault();
+        this.seekToFirstMatch();
+      });
     }
 
     // Listen for video time updates to auto-scroll
@@ -1208,6 +1273,26 @@ class TranscriptManager {
     });
     this.onTimeUpdate();
   }
+
+  /**
+   * Seek to the first cue matching the search. Cues with word timings seek
+   * to the word where the match starts; others seek to the cue start.
+   */
+  seekToFirstMatch() {
+    const q = (this.searchEl?.value || '').trim().toLowerCase();
+    if (!q || !this.player.video) return;
+    const cue = this.cueElements.find((el) => !el.classList.contains('hidden'));
+    if (!cue) return;
+
+    let time = parseFloat(cue.dataset.cueStart);
+    const words = Array.from(cue.querySelectorAll('[data-word-start]'));
+    if (words.length) {
+      const first = q.split(/\s+/)[0];
+      const hit = words.find((w) => w.textContent.toLowerCase().includes(first));
+      if (hit) time = parseFloat(hit.dataset.wordStart);
+    }
+    if (isFinite(time)) this.player.video.currentTime = time;
+  }
 }
 
 class ClipManager {